@@ -0,0 +1,78 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	cliflag "k8s.io/component-base/cli/flag"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/descheduler/cmd/descheduler/app/options"
+	"sigs.k8s.io/descheduler/pkg/descheduler"
+	"sigs.k8s.io/descheduler/pkg/features"
+)
+
+// NewLocalCommand creates a command running a policy once, in dry run mode,
+// against a cluster from outside of it and printing the decisions.
+func NewLocalCommand() *cobra.Command {
+	s, err := options.NewDeschedulerServer()
+	if err != nil {
+		klog.ErrorS(err, "unable to initialize server")
+	}
+	localOptions := descheduler.LocalOptions{}
+
+	localCmd := &cobra.Command{
+		Use:   "local",
+		Short: "Evaluate a policy against a cluster from outside of it",
+		Long: `Runs a single descheduling cycle of a policy against the cluster of a kubeconfig, in dry run mode, and prints the decision report. No pod is evicted and nothing is deployed in the cluster.
+
+Node and pod metrics are read from the metrics.k8s.io API through the API server. A Prometheus metrics provider is reached through a port-forward to --prometheus-service, or through --prometheus-url.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			descheduler.SetupPlugins()
+			if err := features.DefaultMutableFeatureGate.SetFromMap(s.FeatureGates); err != nil {
+				return err
+			}
+			s.DefaultFeatureGates = features.DefaultMutableFeatureGate
+
+			report, err := descheduler.RunLocal(cmd.Context(), s, localOptions)
+			if err != nil {
+				return err
+			}
+			out, err := yaml.Marshal(report)
+			if err != nil {
+				return fmt.Errorf("unable to encode the decision report: %v", err)
+			}
+			_, err = cmd.OutOrStdout().Write(out)
+			return err
+		},
+	}
+
+	flags := localCmd.Flags()
+	flags.StringVar(&s.ClientConnection.Kubeconfig, "kubeconfig", "", "File with kube configuration. Defaults to $KUBECONFIG, then to ~/.kube/config.")
+	flags.StringVar(&s.PolicyConfigFile, "policy-config-file", "", "File with descheduler policy configuration.")
+	flags.Int64Var(&s.Seed, "seed", s.Seed, "Seed of the randomized choices, see the --seed flag of the descheduler.")
+	flags.StringVar(&localOptions.PrometheusService, "prometheus-service", "", "Service of Prometheus, as namespace/name[:port], a local port is forwarded to. Overrides the url of the Prometheus metrics provider of the policy.")
+	flags.StringVar(&localOptions.PrometheusURL, "prometheus-url", "", "Url of Prometheus, e.g. of a Prometheus port-forwarded by hand. Overrides the url of the Prometheus metrics provider of the policy.")
+	flags.StringVar(&localOptions.PrometheusAuthToken, "prometheus-token", "", "Token authenticating the Prometheus queries. Defaults to the token of the secret referred by the policy, if any.")
+	flags.Var(cliflag.NewMapStringBool(&s.FeatureGates), "feature-gates", "A set of key=value pairs that describe feature gates for alpha/experimental features.")
+	_ = localCmd.MarkFlagRequired("policy-config-file")
+	return localCmd
+}
@@ -46,24 +46,43 @@ import (
 
 const (
 	DefaultDeschedulerPort = 10258
+	// DefaultDebugBindAddress is the address the debug server listens on
+	// when profiling is enabled.
+	DefaultDebugBindAddress = "127.0.0.1:10259"
 )
 
 // DeschedulerServer configuration
 type DeschedulerServer struct {
 	componentconfig.DeschedulerConfiguration
 
-	Client            clientset.Interface
-	EventClient       clientset.Interface
+	Client      clientset.Interface
+	EventClient clientset.Interface
+	// EvictionClient is used to evict pods. It has its own rate limiter
+	// so eviction bursts do not starve the informers and vice versa.
+	EvictionClient    clientset.Interface
 	MetricsClient     metricsclient.Interface
 	PrometheusClient  promapi.Client
 	SecureServing     *apiserveroptions.SecureServingOptionsWithLoopback
 	SecureServingInfo *apiserver.SecureServingInfo
 	DisableMetrics    bool
 	EnableHTTP2       bool
+	// EnableProfiling starts a debug server exposing pprof profiles and
+	// Go runtime metrics on DebugBindAddress.
+	EnableProfiling  bool
+	DebugBindAddress string
+	// EvictionClientQPS and EvictionClientBurst configure the rate limiter
+	// of EvictionClient. ClientConnection keeps configuring the client
+	// used by the informers and the remaining api calls. When left unset
+	// (zero) the eviction client uses the ClientConnection QPS and Burst.
+	EvictionClientQPS   float32
+	EvictionClientBurst int32
 	// FeatureGates enabled by the user
 	FeatureGates map[string]bool
 	// DefaultFeatureGates for internal accessing so unit tests can enable/disable specific features
 	DefaultFeatureGates featuregate.FeatureGate
+	// Seed makes the randomized choices, e.g. tie-breaking between pods,
+	// reproducible. Zero seeds them from the current time instead.
+	Seed int64
 }
 
 // NewDeschedulerServer creates a new DeschedulerServer with default parameters
@@ -79,6 +98,7 @@ func NewDeschedulerServer() (*DeschedulerServer, error) {
 	return &DeschedulerServer{
 		DeschedulerConfiguration: *cfg,
 		SecureServing:            secureServing,
+		DebugBindAddress:         DefaultDebugBindAddress,
 	}, nil
 }
 
@@ -111,6 +131,8 @@ func (rs *DeschedulerServer) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&rs.ClientConnection.Kubeconfig, "client-connection-kubeconfig", rs.ClientConnection.Kubeconfig, "File path to kube configuration for interacting with kubernetes apiserver.")
 	fs.Float32Var(&rs.ClientConnection.QPS, "client-connection-qps", rs.ClientConnection.QPS, "QPS to use for interacting with kubernetes apiserver.")
 	fs.Int32Var(&rs.ClientConnection.Burst, "client-connection-burst", rs.ClientConnection.Burst, "Burst to use for interacting with kubernetes apiserver.")
+	fs.Float32Var(&rs.EvictionClientQPS, "eviction-client-qps", rs.EvictionClientQPS, "QPS to use for evicting pods. Evictions are rate limited independently from the rest of the interactions with kubernetes apiserver. Defaults to --client-connection-qps when unset.")
+	fs.Int32Var(&rs.EvictionClientBurst, "eviction-client-burst", rs.EvictionClientBurst, "Burst to use for evicting pods. Evictions are rate limited independently from the rest of the interactions with kubernetes apiserver. Defaults to --client-connection-burst when unset.")
 	fs.StringVar(&rs.PolicyConfigFile, "policy-config-file", rs.PolicyConfigFile, "File with descheduler policy configuration.")
 	fs.BoolVar(&rs.DryRun, "dry-run", rs.DryRun, "Execute descheduler in dry run mode.")
 	fs.BoolVar(&rs.DisableMetrics, "disable-metrics", rs.DisableMetrics, "Disables metrics. The metrics are by default served through https://localhost:10258/metrics. Secure address, resp. port can be changed through --bind-address, resp. --secure-port flags.")
@@ -121,6 +143,9 @@ func (rs *DeschedulerServer) AddFlags(fs *pflag.FlagSet) {
 	fs.Float64Var(&rs.Tracing.SampleRate, "otel-sample-rate", 1.0, "Sample rate to collect the Traces")
 	fs.BoolVar(&rs.Tracing.FallbackToNoOpProviderOnError, "otel-fallback-no-op-on-error", false, "Fallback to NoOp Tracer in case of error")
 	fs.BoolVar(&rs.EnableHTTP2, "enable-http2", false, "If http/2 should be enabled for the metrics and health check")
+	fs.BoolVar(&rs.EnableProfiling, "enable-profiling", rs.EnableProfiling, "Enables a debug server exposing pprof profiles under /debug/pprof and Go runtime metrics under /debug/vars. The server listens on --debug-bind-address.")
+	fs.StringVar(&rs.DebugBindAddress, "debug-bind-address", rs.DebugBindAddress, "The address the debug server listens on when --enable-profiling is set. The server is not secured, keep it bound to a local address.")
+	fs.Int64Var(&rs.Seed, "seed", rs.Seed, "Seed of the randomized choices, e.g. the order nodes and pods are processed in when nothing else tells them apart. Runs sharing the same seed, policy and cluster state take the same decisions. Zero, the default, seeds them from the current time.")
 	fs.Var(cliflag.NewMapStringBool(&rs.FeatureGates), "feature-gates", "A set of key=value pairs that describe feature gates for alpha/experimental features. "+
 		"Options are:\n"+strings.Join(features.DefaultMutableFeatureGate.KnownFeatures(), "\n"))
 
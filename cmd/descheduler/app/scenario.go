@@ -0,0 +1,61 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/descheduler/pkg/descheduler"
+)
+
+// NewScenarioCommand creates a command running the policy of a scenario file
+// against the cluster state the file describes and printing the decisions.
+func NewScenarioCommand() *cobra.Command {
+	var seed int64
+	scenarioCmd := &cobra.Command{
+		Use:   "scenario FILE",
+		Short: "Run a descheduling scenario",
+		Long:  `Runs the policy of a scenario file against the nodes, pods and metrics described in the file, in dry run mode, and prints the decision report.`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			descheduler.SetupPlugins()
+
+			scenario, err := descheduler.LoadScenario(args[0])
+			if err != nil {
+				return err
+			}
+			if cmd.Flags().Changed("seed") {
+				scenario.Seed = seed
+			}
+			report, err := descheduler.RunScenario(cmd.Context(), scenario)
+			if err != nil {
+				return err
+			}
+			out, err := yaml.Marshal(report)
+			if err != nil {
+				return fmt.Errorf("unable to encode the decision report: %v", err)
+			}
+			_, err = cmd.OutOrStdout().Write(out)
+			return err
+		},
+	}
+	scenarioCmd.Flags().Int64Var(&seed, "seed", 0, "Seed of the randomized choices, overrides the seed of the scenario file.")
+	return scenarioCmd
+}
@@ -19,9 +19,12 @@ package app
 
 import (
 	"context"
+	"expvar"
 	"io"
+	"net/http"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -33,6 +36,7 @@ import (
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/apiserver/pkg/server/healthz"
 	"k8s.io/apiserver/pkg/server/mux"
+	"k8s.io/apiserver/pkg/server/routes"
 	"k8s.io/component-base/featuregate"
 	"k8s.io/component-base/logs"
 	logsapi "k8s.io/component-base/logs/api/v1"
@@ -97,6 +101,10 @@ func Run(rootCtx context.Context, rs *options.DeschedulerServer) error {
 
 	healthz.InstallHandler(pathRecorderMux, healthz.NamedCheck("Descheduler", healthz.PingHealthz.Check))
 
+	if rs.EnableProfiling {
+		go serveDebugEndpoints(ctx, rs.DebugBindAddress)
+	}
+
 	stoppedCh, _, err := rs.SecureServingInfo.Serve(pathRecorderMux, 0, ctx.Done())
 	if err != nil {
 		klog.Fatalf("failed to start secure server: %v", err)
@@ -123,3 +131,29 @@ func Run(rootCtx context.Context, rs *options.DeschedulerServer) error {
 
 	return nil
 }
+
+// serveDebugEndpoints runs a plain http server exposing pprof profiles and Go
+// runtime metrics (through expvar) until the context is done. It is meant to
+// diagnose cpu and memory usage in place, e.g. informer caches growing on
+// large clusters, and is expected to be bound to a local address.
+func serveDebugEndpoints(ctx context.Context, address string) {
+	debugMux := mux.NewPathRecorderMux("descheduler-debug")
+	routes.Profiling{}.Install(debugMux)
+	debugMux.Handle("/debug/vars", expvar.Handler())
+
+	server := &http.Server{
+		Addr:              address,
+		Handler:           debugMux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+
+	klog.InfoS("Starting debug server", "address", address)
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		klog.ErrorS(err, "failed to run debug server", "address", address)
+	}
+}
@@ -27,6 +27,8 @@ func main() {
 	out := os.Stdout
 	cmd := app.NewDeschedulerCommand(out)
 	cmd.AddCommand(app.NewVersionCommand())
+	cmd.AddCommand(app.NewScenarioCommand())
+	cmd.AddCommand(app.NewLocalCommand())
 
 	code := cli.Run(cmd)
 	os.Exit(code)
@@ -66,11 +66,103 @@ var (
 			Buckets:        []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 25, 50, 100},
 		}, []string{"strategy", "profile"})
 
+	PluginExecutionDuration = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Subsystem:      DeschedulerSubsystem,
+			Name:           "plugin_execution_duration_seconds",
+			Help:           "Time taken by a plugin to complete an extension point (Deschedule or Balance), by the plugin, by the profile, by the extension point",
+			StabilityLevel: metrics.ALPHA,
+			Buckets:        []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 25, 50, 100},
+		}, []string{"plugin", "profile", "extension_point"})
+
+	PluginAPICalls = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      DeschedulerSubsystem,
+			Name:           "plugin_api_calls_total",
+			Help:           "Number of requests sent by a plugin to the API server and to Prometheus, by the plugin, by the profile, by the call type (the lowercased HTTP method of the API server requests, evict for evictions, metrics for Prometheus queries)",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"plugin", "profile", "call"})
+
+	BalancePredictedUtilizationDelta = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      DeschedulerSubsystem,
+			Name:           "balance_predicted_utilization_delta_percentage",
+			Help:           "Average node utilization drop predicted after evicting pods from the nodes, in percentage of the node capacity, by the strategy, by the resource",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"strategy", "resource"})
+
+	BalanceAchievedUtilizationDelta = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      DeschedulerSubsystem,
+			Name:           "balance_achieved_utilization_delta_percentage",
+			Help:           "Average node utilization drop observed on the next descheduling cycle after evicting pods from the nodes, in percentage of the node capacity, by the strategy, by the resource",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"strategy", "resource"})
+
+	BalanceSkipped = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      DeschedulerSubsystem,
+			Name:           "balance_skipped_total",
+			Help:           "Number of balance invocations that skipped the node classification because no node could be above the target thresholds, by the strategy",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"strategy"})
+
+	BalanceNodesClassified = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      DeschedulerSubsystem,
+			Name:           "balance_nodes_classified",
+			Help:           "Number of nodes classified on the last balance invocation, by the strategy, by the classification (underutilized, overutilized, appropriately_utilized)",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"strategy", "classification"})
+
+	BalancePods = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      DeschedulerSubsystem,
+			Name:           "balance_pods_total",
+			Help:           "Number of pods considered for eviction by the balance strategies, by the strategy, by the result (considered, evicted, skipped), by the reason a pod was skipped",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"strategy", "result", "reason"})
+
+	BalanceTotalAvailableUsage = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      DeschedulerSubsystem,
+			Name:           "balance_total_available_usage",
+			Help:           "Resources the destination nodes could still accept once the last balance invocation was over, in the base unit of the resource (e.g. cores, bytes), by the strategy, by the resource",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"strategy", "resource"})
+
+	UsageClientSyncDuration = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Subsystem:      DeschedulerSubsystem,
+			Name:           "usage_client_sync_duration_seconds",
+			Help:           "Time taken to collect the usage of the nodes and of their pods, by the usage client type",
+			StabilityLevel: metrics.ALPHA,
+			Buckets:        []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+		}, []string{"client"})
+
+	CloudEventsDropped = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      DeschedulerSubsystem,
+			Name:           "cloudevents_dropped_total",
+			Help:           "Number of CloudEvents dropped because the queue of the events waiting to be sent was full, by the event type",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"type"})
+
 	metricsList = []metrics.Registerable{
 		PodsEvicted,
 		buildInfo,
 		DeschedulerLoopDuration,
 		DeschedulerStrategyDuration,
+		PluginExecutionDuration,
+		PluginAPICalls,
+		BalancePredictedUtilizationDelta,
+		BalanceAchievedUtilizationDelta,
+		BalanceSkipped,
+		BalanceNodesClassified,
+		BalancePods,
+		BalanceTotalAvailableUsage,
+		UsageClientSyncDuration,
+		CloudEventsDropped,
 	}
 )
 
@@ -102,6 +102,8 @@ func Convert_v1alpha2_PluginConfig_To_api_PluginConfig(in *PluginConfig, out *ap
 			return err
 		}
 	}
+	out.ExcludedOperatingSystems = in.ExcludedOperatingSystems
+	out.ExcludedTaints = in.ExcludedTaints
 	return nil
 }
 
@@ -57,6 +57,88 @@ type DeschedulerPolicy struct {
 	// specified type will be used.
 	// Defaults to a per object value if not specified. zero means delete immediately.
 	GracePeriodSeconds *int64 `json:"gracePeriodSeconds,omitempty"`
+
+	// ConnectionDraining configures a request sent to the pods before they are evicted
+	ConnectionDraining *ConnectionDraining `json:"connectionDraining,omitempty"`
+
+	// SessionDraining configures taking the pods out of their load balancers before they are evicted
+	SessionDraining *SessionDraining `json:"sessionDraining,omitempty"`
+
+	// Notifications configures a webhook eviction summaries and alerts are posted to
+	Notifications *Notifications `json:"notifications,omitempty"`
+
+	// CloudEvents configures a sink every eviction and cycle summary is sent to as a CloudEvent
+	CloudEvents *CloudEvents `json:"cloudEvents,omitempty"`
+}
+
+// ConnectionDraining configures a request sent to the pods before they are evicted so they can
+// drain their connections. Only pods exposing the configured port in one of their containers are drained.
+type ConnectionDraining struct {
+	// Port the drain request is sent to.
+	Port int32 `json:"port"`
+
+	// Path of the drain request, e.g. /drain_listeners?graceful for Envoy.
+	Path string `json:"path,omitempty"`
+
+	// Timeout to wait for the pod to stop being ready after the drain request.
+	// The pod is evicted once the timeout expires. Defaults to 30 seconds.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// Budget is the total time draining pods may take during a descheduling cycle.
+	// Once it is exhausted pods are evicted without being drained. Defaults to 2 minutes.
+	Budget *metav1.Duration `json:"budget,omitempty"`
+}
+
+// SessionDraining configures taking the pods out of the Services, and the load balancers, they
+// back before they are evicted, so long-lived sessions are not cut abruptly. Only pods declaring
+// the readiness gate are drained.
+type SessionDraining struct {
+	// ReadinessGate is the condition type of the readiness gate set to False before the
+	// pod is evicted.
+	ReadinessGate string `json:"readinessGate"`
+
+	// Annotation, if set, is added to the pod with the "true" value along with the readiness
+	// gate, for load balancer controllers watching annotations instead of the pod readiness.
+	Annotation string `json:"annotation,omitempty"`
+
+	// Period to wait once the pod is out of rotation before evicting it. Defaults to 30 seconds.
+	Period *metav1.Duration `json:"period,omitempty"`
+
+	// Budget is the total time waiting for pods out of rotation may take during a descheduling
+	// cycle. Once it is exhausted pods are evicted without being drained. Defaults to 2 minutes.
+	Budget *metav1.Duration `json:"budget,omitempty"`
+}
+
+// Notifications configures a webhook, e.g. a Slack incoming webhook, notified about
+// evictions. Messages are posted as a JSON object with a "text" field.
+type Notifications struct {
+	// WebhookURL messages are posted to.
+	WebhookURL string `json:"webhookURL"`
+
+	// SummaryThreshold is the number of pods a descheduling cycle has to evict for its
+	// summary to be posted. Defaults to 1, cycles evicting no pods are not reported.
+	SummaryThreshold *uint `json:"summaryThreshold,omitempty"`
+
+	// AlertThreshold is the number of pods evicted during a descheduling cycle above which
+	// an alert is posted right away, without waiting for the cycle to finish. No alerts are
+	// posted if not set.
+	AlertThreshold *uint `json:"alertThreshold,omitempty"`
+
+	// Timeout of the webhook requests. Defaults to 10 seconds.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// CloudEvents configures an HTTP sink, e.g. a Knative broker or a Kafka sink, the
+// descheduling decisions are sent to. Events use the structured content mode.
+type CloudEvents struct {
+	// SinkURL events are posted to.
+	SinkURL string `json:"sinkURL"`
+
+	// Source of the events. Defaults to "descheduler".
+	Source string `json:"source,omitempty"`
+
+	// Timeout of the sink requests. Defaults to 10 seconds.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
 }
 
 type DeschedulerProfile struct {
@@ -77,6 +159,17 @@ type Plugins struct {
 type PluginConfig struct {
 	Name string               `json:"name"`
 	Args runtime.RawExtension `json:"args"`
+
+	// ExcludedOperatingSystems lists the operating systems, as reported by the
+	// kubernetes.io/os node label, of the nodes the plugin does not process,
+	// e.g. windows in a cluster descheduled for its Linux pool only.
+	ExcludedOperatingSystems []string `json:"excludedOperatingSystems,omitempty"`
+
+	// ExcludedTaints lists the taints of the nodes the plugin does not
+	// process, neither as a source nor as a destination of the evicted
+	// pods. Every taint is either a key, e.g. infra, or a key=value pair,
+	// e.g. dedicated=gpu, and matches regardless of the effect.
+	ExcludedTaints []string `json:"excludedTaints,omitempty"`
 }
 
 type PluginSet struct {
@@ -93,6 +186,22 @@ const (
 
 	// KubernetesMetrics enables metrics from a Prometheus metrics server.
 	PrometheusMetrics MetricsSource = "Prometheus"
+
+	// CustomMetrics enables metrics from the custom and the external
+	// metrics APIs, e.g. served by Prometheus Adapter.
+	CustomMetrics MetricsSource = "CustomMetrics"
+
+	// VPARecommendations computes the utilization from the targets
+	// VerticalPodAutoscalers recommend for the pods.
+	VPARecommendations MetricsSource = "VPARecommendations"
+
+	// OpenTelemetryMetrics enables metrics from an OpenTelemetry collector
+	// exposing them through its prometheus exporter.
+	OpenTelemetryMetrics MetricsSource = "OpenTelemetry"
+
+	// StaticMetrics reads the utilization from a static document, e.g. to
+	// replay a recorded or synthetic cluster state.
+	StaticMetrics MetricsSource = "Static"
 )
 
 // MetricsCollector configures collection of metrics about actual resource utilization
@@ -117,6 +226,39 @@ type Prometheus struct {
 	// If not set the in cluster authentication token for the descheduler service
 	// account is read from the container's file system.
 	AuthToken *AuthToken `json:"authToken,omitempty"`
+	// tokenFile is a file holding the token used for authentication with
+	// the prometheus server instead, it is read on every query so rotated
+	// tokens are picked up.
+	TokenFile string `json:"tokenFile,omitempty"`
+	// basicAuth authenticates with the prometheus server through basic
+	// authentication instead of a token.
+	BasicAuth *BasicAuth `json:"basicAuth,omitempty"`
+	// tls configures the client certificate presented to the prometheus
+	// server and the certificates its certificate is verified against.
+	TLS *PrometheusTLS `json:"tls,omitempty"`
+	// headers are sent along with every query, e.g. X-Scope-OrgID for
+	// multi-tenant Thanos or Cortex deployments.
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// BasicAuth holds the credentials of a basic authentication
+type BasicAuth struct {
+	// username is the user to authenticate as.
+	Username string `json:"username,omitempty"`
+	// passwordFile is a file holding the password of the user, it is read
+	// on every query.
+	PasswordFile string `json:"passwordFile,omitempty"`
+}
+
+// PrometheusTLS holds the TLS configuration of the prometheus client
+type PrometheusTLS struct {
+	// caFile is a file holding the certificates the prometheus server
+	// certificate is verified against. Defaults to the in cluster CA.
+	CAFile string `json:"caFile,omitempty"`
+	// certFile and keyFile are files holding the client certificate and
+	// its key, for mutual TLS.
+	CertFile string `json:"certFile,omitempty"`
+	KeyFile  string `json:"keyFile,omitempty"`
 }
 
 type AuthToken struct {
@@ -24,6 +24,7 @@ package v1alpha2
 import (
 	unsafe "unsafe"
 
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	conversion "k8s.io/apimachinery/pkg/conversion"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	api "sigs.k8s.io/descheduler/pkg/api"
@@ -46,6 +47,36 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*BasicAuth)(nil), (*api.BasicAuth)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_BasicAuth_To_api_BasicAuth(a.(*BasicAuth), b.(*api.BasicAuth), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*api.BasicAuth)(nil), (*BasicAuth)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_api_BasicAuth_To_v1alpha2_BasicAuth(a.(*api.BasicAuth), b.(*BasicAuth), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*CloudEvents)(nil), (*api.CloudEvents)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_CloudEvents_To_api_CloudEvents(a.(*CloudEvents), b.(*api.CloudEvents), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*api.CloudEvents)(nil), (*CloudEvents)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_api_CloudEvents_To_v1alpha2_CloudEvents(a.(*api.CloudEvents), b.(*CloudEvents), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ConnectionDraining)(nil), (*api.ConnectionDraining)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_ConnectionDraining_To_api_ConnectionDraining(a.(*ConnectionDraining), b.(*api.ConnectionDraining), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*api.ConnectionDraining)(nil), (*ConnectionDraining)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_api_ConnectionDraining_To_v1alpha2_ConnectionDraining(a.(*api.ConnectionDraining), b.(*ConnectionDraining), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*DeschedulerProfile)(nil), (*api.DeschedulerProfile)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_DeschedulerProfile_To_api_DeschedulerProfile(a.(*DeschedulerProfile), b.(*api.DeschedulerProfile), scope)
 	}); err != nil {
@@ -76,6 +107,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*Notifications)(nil), (*api.Notifications)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_Notifications_To_api_Notifications(a.(*Notifications), b.(*api.Notifications), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*api.Notifications)(nil), (*Notifications)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_api_Notifications_To_v1alpha2_Notifications(a.(*api.Notifications), b.(*Notifications), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*api.PluginConfig)(nil), (*PluginConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_api_PluginConfig_To_v1alpha2_PluginConfig(a.(*api.PluginConfig), b.(*PluginConfig), scope)
 	}); err != nil {
@@ -111,6 +152,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*PrometheusTLS)(nil), (*api.PrometheusTLS)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_PrometheusTLS_To_api_PrometheusTLS(a.(*PrometheusTLS), b.(*api.PrometheusTLS), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*api.PrometheusTLS)(nil), (*PrometheusTLS)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_api_PrometheusTLS_To_v1alpha2_PrometheusTLS(a.(*api.PrometheusTLS), b.(*PrometheusTLS), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*SecretReference)(nil), (*api.SecretReference)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_SecretReference_To_api_SecretReference(a.(*SecretReference), b.(*api.SecretReference), scope)
 	}); err != nil {
@@ -121,6 +172,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*SessionDraining)(nil), (*api.SessionDraining)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_SessionDraining_To_api_SessionDraining(a.(*SessionDraining), b.(*api.SessionDraining), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*api.SessionDraining)(nil), (*SessionDraining)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_api_SessionDraining_To_v1alpha2_SessionDraining(a.(*api.SessionDraining), b.(*SessionDraining), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*api.DeschedulerPolicy)(nil), (*DeschedulerPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_api_DeschedulerPolicy_To_v1alpha2_DeschedulerPolicy(a.(*api.DeschedulerPolicy), b.(*DeschedulerPolicy), scope)
 	}); err != nil {
@@ -159,6 +220,78 @@ func Convert_api_AuthToken_To_v1alpha2_AuthToken(in *api.AuthToken, out *AuthTok
 	return autoConvert_api_AuthToken_To_v1alpha2_AuthToken(in, out, s)
 }
 
+func autoConvert_v1alpha2_BasicAuth_To_api_BasicAuth(in *BasicAuth, out *api.BasicAuth, s conversion.Scope) error {
+	out.Username = in.Username
+	out.PasswordFile = in.PasswordFile
+	return nil
+}
+
+// Convert_v1alpha2_BasicAuth_To_api_BasicAuth is an autogenerated conversion function.
+func Convert_v1alpha2_BasicAuth_To_api_BasicAuth(in *BasicAuth, out *api.BasicAuth, s conversion.Scope) error {
+	return autoConvert_v1alpha2_BasicAuth_To_api_BasicAuth(in, out, s)
+}
+
+func autoConvert_api_BasicAuth_To_v1alpha2_BasicAuth(in *api.BasicAuth, out *BasicAuth, s conversion.Scope) error {
+	out.Username = in.Username
+	out.PasswordFile = in.PasswordFile
+	return nil
+}
+
+// Convert_api_BasicAuth_To_v1alpha2_BasicAuth is an autogenerated conversion function.
+func Convert_api_BasicAuth_To_v1alpha2_BasicAuth(in *api.BasicAuth, out *BasicAuth, s conversion.Scope) error {
+	return autoConvert_api_BasicAuth_To_v1alpha2_BasicAuth(in, out, s)
+}
+
+func autoConvert_v1alpha2_CloudEvents_To_api_CloudEvents(in *CloudEvents, out *api.CloudEvents, s conversion.Scope) error {
+	out.SinkURL = in.SinkURL
+	out.Source = in.Source
+	out.Timeout = (*v1.Duration)(unsafe.Pointer(in.Timeout))
+	return nil
+}
+
+// Convert_v1alpha2_CloudEvents_To_api_CloudEvents is an autogenerated conversion function.
+func Convert_v1alpha2_CloudEvents_To_api_CloudEvents(in *CloudEvents, out *api.CloudEvents, s conversion.Scope) error {
+	return autoConvert_v1alpha2_CloudEvents_To_api_CloudEvents(in, out, s)
+}
+
+func autoConvert_api_CloudEvents_To_v1alpha2_CloudEvents(in *api.CloudEvents, out *CloudEvents, s conversion.Scope) error {
+	out.SinkURL = in.SinkURL
+	out.Source = in.Source
+	out.Timeout = (*v1.Duration)(unsafe.Pointer(in.Timeout))
+	return nil
+}
+
+// Convert_api_CloudEvents_To_v1alpha2_CloudEvents is an autogenerated conversion function.
+func Convert_api_CloudEvents_To_v1alpha2_CloudEvents(in *api.CloudEvents, out *CloudEvents, s conversion.Scope) error {
+	return autoConvert_api_CloudEvents_To_v1alpha2_CloudEvents(in, out, s)
+}
+
+func autoConvert_v1alpha2_ConnectionDraining_To_api_ConnectionDraining(in *ConnectionDraining, out *api.ConnectionDraining, s conversion.Scope) error {
+	out.Port = in.Port
+	out.Path = in.Path
+	out.Timeout = (*v1.Duration)(unsafe.Pointer(in.Timeout))
+	out.Budget = (*v1.Duration)(unsafe.Pointer(in.Budget))
+	return nil
+}
+
+// Convert_v1alpha2_ConnectionDraining_To_api_ConnectionDraining is an autogenerated conversion function.
+func Convert_v1alpha2_ConnectionDraining_To_api_ConnectionDraining(in *ConnectionDraining, out *api.ConnectionDraining, s conversion.Scope) error {
+	return autoConvert_v1alpha2_ConnectionDraining_To_api_ConnectionDraining(in, out, s)
+}
+
+func autoConvert_api_ConnectionDraining_To_v1alpha2_ConnectionDraining(in *api.ConnectionDraining, out *ConnectionDraining, s conversion.Scope) error {
+	out.Port = in.Port
+	out.Path = in.Path
+	out.Timeout = (*v1.Duration)(unsafe.Pointer(in.Timeout))
+	out.Budget = (*v1.Duration)(unsafe.Pointer(in.Budget))
+	return nil
+}
+
+// Convert_api_ConnectionDraining_To_v1alpha2_ConnectionDraining is an autogenerated conversion function.
+func Convert_api_ConnectionDraining_To_v1alpha2_ConnectionDraining(in *api.ConnectionDraining, out *ConnectionDraining, s conversion.Scope) error {
+	return autoConvert_api_ConnectionDraining_To_v1alpha2_ConnectionDraining(in, out, s)
+}
+
 func autoConvert_v1alpha2_DeschedulerPolicy_To_api_DeschedulerPolicy(in *DeschedulerPolicy, out *api.DeschedulerPolicy, s conversion.Scope) error {
 	if in.Profiles != nil {
 		in, out := &in.Profiles, &out.Profiles
@@ -179,6 +312,10 @@ func autoConvert_v1alpha2_DeschedulerPolicy_To_api_DeschedulerPolicy(in *Desched
 	out.MetricsCollector = (*api.MetricsCollector)(unsafe.Pointer(in.MetricsCollector))
 	out.MetricsProviders = *(*[]api.MetricsProvider)(unsafe.Pointer(&in.MetricsProviders))
 	out.GracePeriodSeconds = (*int64)(unsafe.Pointer(in.GracePeriodSeconds))
+	out.ConnectionDraining = (*api.ConnectionDraining)(unsafe.Pointer(in.ConnectionDraining))
+	out.SessionDraining = (*api.SessionDraining)(unsafe.Pointer(in.SessionDraining))
+	out.Notifications = (*api.Notifications)(unsafe.Pointer(in.Notifications))
+	out.CloudEvents = (*api.CloudEvents)(unsafe.Pointer(in.CloudEvents))
 	return nil
 }
 
@@ -202,6 +339,10 @@ func autoConvert_api_DeschedulerPolicy_To_v1alpha2_DeschedulerPolicy(in *api.Des
 	out.MetricsCollector = (*MetricsCollector)(unsafe.Pointer(in.MetricsCollector))
 	out.MetricsProviders = *(*[]MetricsProvider)(unsafe.Pointer(&in.MetricsProviders))
 	out.GracePeriodSeconds = (*int64)(unsafe.Pointer(in.GracePeriodSeconds))
+	out.ConnectionDraining = (*ConnectionDraining)(unsafe.Pointer(in.ConnectionDraining))
+	out.SessionDraining = (*SessionDraining)(unsafe.Pointer(in.SessionDraining))
+	out.Notifications = (*Notifications)(unsafe.Pointer(in.Notifications))
+	out.CloudEvents = (*CloudEvents)(unsafe.Pointer(in.CloudEvents))
 	return nil
 }
 
@@ -295,11 +436,39 @@ func Convert_api_MetricsProvider_To_v1alpha2_MetricsProvider(in *api.MetricsProv
 	return autoConvert_api_MetricsProvider_To_v1alpha2_MetricsProvider(in, out, s)
 }
 
+func autoConvert_v1alpha2_Notifications_To_api_Notifications(in *Notifications, out *api.Notifications, s conversion.Scope) error {
+	out.WebhookURL = in.WebhookURL
+	out.SummaryThreshold = (*uint)(unsafe.Pointer(in.SummaryThreshold))
+	out.AlertThreshold = (*uint)(unsafe.Pointer(in.AlertThreshold))
+	out.Timeout = (*v1.Duration)(unsafe.Pointer(in.Timeout))
+	return nil
+}
+
+// Convert_v1alpha2_Notifications_To_api_Notifications is an autogenerated conversion function.
+func Convert_v1alpha2_Notifications_To_api_Notifications(in *Notifications, out *api.Notifications, s conversion.Scope) error {
+	return autoConvert_v1alpha2_Notifications_To_api_Notifications(in, out, s)
+}
+
+func autoConvert_api_Notifications_To_v1alpha2_Notifications(in *api.Notifications, out *Notifications, s conversion.Scope) error {
+	out.WebhookURL = in.WebhookURL
+	out.SummaryThreshold = (*uint)(unsafe.Pointer(in.SummaryThreshold))
+	out.AlertThreshold = (*uint)(unsafe.Pointer(in.AlertThreshold))
+	out.Timeout = (*v1.Duration)(unsafe.Pointer(in.Timeout))
+	return nil
+}
+
+// Convert_api_Notifications_To_v1alpha2_Notifications is an autogenerated conversion function.
+func Convert_api_Notifications_To_v1alpha2_Notifications(in *api.Notifications, out *Notifications, s conversion.Scope) error {
+	return autoConvert_api_Notifications_To_v1alpha2_Notifications(in, out, s)
+}
+
 func autoConvert_v1alpha2_PluginConfig_To_api_PluginConfig(in *PluginConfig, out *api.PluginConfig, s conversion.Scope) error {
 	out.Name = in.Name
 	if err := runtime.Convert_runtime_RawExtension_To_runtime_Object(&in.Args, &out.Args, s); err != nil {
 		return err
 	}
+	out.ExcludedOperatingSystems = *(*[]string)(unsafe.Pointer(&in.ExcludedOperatingSystems))
+	out.ExcludedTaints = *(*[]string)(unsafe.Pointer(&in.ExcludedTaints))
 	return nil
 }
 
@@ -308,6 +477,8 @@ func autoConvert_api_PluginConfig_To_v1alpha2_PluginConfig(in *api.PluginConfig,
 	if err := runtime.Convert_runtime_Object_To_runtime_RawExtension(&in.Args, &out.Args, s); err != nil {
 		return err
 	}
+	out.ExcludedOperatingSystems = *(*[]string)(unsafe.Pointer(&in.ExcludedOperatingSystems))
+	out.ExcludedTaints = *(*[]string)(unsafe.Pointer(&in.ExcludedTaints))
 	return nil
 }
 
@@ -395,6 +566,10 @@ func Convert_api_Plugins_To_v1alpha2_Plugins(in *api.Plugins, out *Plugins, s co
 func autoConvert_v1alpha2_Prometheus_To_api_Prometheus(in *Prometheus, out *api.Prometheus, s conversion.Scope) error {
 	out.URL = in.URL
 	out.AuthToken = (*api.AuthToken)(unsafe.Pointer(in.AuthToken))
+	out.TokenFile = in.TokenFile
+	out.BasicAuth = (*api.BasicAuth)(unsafe.Pointer(in.BasicAuth))
+	out.TLS = (*api.PrometheusTLS)(unsafe.Pointer(in.TLS))
+	out.Headers = *(*map[string]string)(unsafe.Pointer(&in.Headers))
 	return nil
 }
 
@@ -406,6 +581,10 @@ func Convert_v1alpha2_Prometheus_To_api_Prometheus(in *Prometheus, out *api.Prom
 func autoConvert_api_Prometheus_To_v1alpha2_Prometheus(in *api.Prometheus, out *Prometheus, s conversion.Scope) error {
 	out.URL = in.URL
 	out.AuthToken = (*AuthToken)(unsafe.Pointer(in.AuthToken))
+	out.TokenFile = in.TokenFile
+	out.BasicAuth = (*BasicAuth)(unsafe.Pointer(in.BasicAuth))
+	out.TLS = (*PrometheusTLS)(unsafe.Pointer(in.TLS))
+	out.Headers = *(*map[string]string)(unsafe.Pointer(&in.Headers))
 	return nil
 }
 
@@ -414,6 +593,30 @@ func Convert_api_Prometheus_To_v1alpha2_Prometheus(in *api.Prometheus, out *Prom
 	return autoConvert_api_Prometheus_To_v1alpha2_Prometheus(in, out, s)
 }
 
+func autoConvert_v1alpha2_PrometheusTLS_To_api_PrometheusTLS(in *PrometheusTLS, out *api.PrometheusTLS, s conversion.Scope) error {
+	out.CAFile = in.CAFile
+	out.CertFile = in.CertFile
+	out.KeyFile = in.KeyFile
+	return nil
+}
+
+// Convert_v1alpha2_PrometheusTLS_To_api_PrometheusTLS is an autogenerated conversion function.
+func Convert_v1alpha2_PrometheusTLS_To_api_PrometheusTLS(in *PrometheusTLS, out *api.PrometheusTLS, s conversion.Scope) error {
+	return autoConvert_v1alpha2_PrometheusTLS_To_api_PrometheusTLS(in, out, s)
+}
+
+func autoConvert_api_PrometheusTLS_To_v1alpha2_PrometheusTLS(in *api.PrometheusTLS, out *PrometheusTLS, s conversion.Scope) error {
+	out.CAFile = in.CAFile
+	out.CertFile = in.CertFile
+	out.KeyFile = in.KeyFile
+	return nil
+}
+
+// Convert_api_PrometheusTLS_To_v1alpha2_PrometheusTLS is an autogenerated conversion function.
+func Convert_api_PrometheusTLS_To_v1alpha2_PrometheusTLS(in *api.PrometheusTLS, out *PrometheusTLS, s conversion.Scope) error {
+	return autoConvert_api_PrometheusTLS_To_v1alpha2_PrometheusTLS(in, out, s)
+}
+
 func autoConvert_v1alpha2_SecretReference_To_api_SecretReference(in *SecretReference, out *api.SecretReference, s conversion.Scope) error {
 	out.Namespace = in.Namespace
 	out.Name = in.Name
@@ -435,3 +638,29 @@ func autoConvert_api_SecretReference_To_v1alpha2_SecretReference(in *api.SecretR
 func Convert_api_SecretReference_To_v1alpha2_SecretReference(in *api.SecretReference, out *SecretReference, s conversion.Scope) error {
 	return autoConvert_api_SecretReference_To_v1alpha2_SecretReference(in, out, s)
 }
+
+func autoConvert_v1alpha2_SessionDraining_To_api_SessionDraining(in *SessionDraining, out *api.SessionDraining, s conversion.Scope) error {
+	out.ReadinessGate = in.ReadinessGate
+	out.Annotation = in.Annotation
+	out.Period = (*v1.Duration)(unsafe.Pointer(in.Period))
+	out.Budget = (*v1.Duration)(unsafe.Pointer(in.Budget))
+	return nil
+}
+
+// Convert_v1alpha2_SessionDraining_To_api_SessionDraining is an autogenerated conversion function.
+func Convert_v1alpha2_SessionDraining_To_api_SessionDraining(in *SessionDraining, out *api.SessionDraining, s conversion.Scope) error {
+	return autoConvert_v1alpha2_SessionDraining_To_api_SessionDraining(in, out, s)
+}
+
+func autoConvert_api_SessionDraining_To_v1alpha2_SessionDraining(in *api.SessionDraining, out *SessionDraining, s conversion.Scope) error {
+	out.ReadinessGate = in.ReadinessGate
+	out.Annotation = in.Annotation
+	out.Period = (*v1.Duration)(unsafe.Pointer(in.Period))
+	out.Budget = (*v1.Duration)(unsafe.Pointer(in.Budget))
+	return nil
+}
+
+// Convert_api_SessionDraining_To_v1alpha2_SessionDraining is an autogenerated conversion function.
+func Convert_api_SessionDraining_To_v1alpha2_SessionDraining(in *api.SessionDraining, out *SessionDraining, s conversion.Scope) error {
+	return autoConvert_api_SessionDraining_To_v1alpha2_SessionDraining(in, out, s)
+}
@@ -69,6 +69,10 @@ func createConfig(clientConnection componentbaseconfig.ClientConnectionConfigura
 		cfg = rest.AddUserAgent(cfg, userAgt)
 	}
 
+	cfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &pluginCallsRoundTripper{rt: rt}
+	})
+
 	return cfg, nil
 }
 
@@ -122,10 +126,85 @@ func loadCAFile(filepath string) (*x509.CertPool, error) {
 	return caCertPool, nil
 }
 
-func CreatePrometheusClient(prometheusURL, authToken string) (promapi.Client, *http.Transport, error) {
+// PrometheusClientOptions holds the optional settings of the Prometheus
+// clients. Files are read on every request so rotated credentials are
+// picked up without recreating the clients.
+type PrometheusClientOptions struct {
+	// TokenFile holds the bearer token, it is only used when no token is
+	// passed to the client constructors.
+	TokenFile string
+	// Username and PasswordFile authenticate the requests through basic
+	// authentication when no bearer token is used.
+	Username     string
+	PasswordFile string
+	// CAFile holds the certificates the server certificate is verified
+	// against.
+	CAFile string
+	// CertFile and KeyFile hold the client certificate and its key.
+	CertFile string
+	KeyFile  string
+	// Headers are sent along with every request.
+	Headers map[string]string
+}
+
+// tlsConfig returns the TLS configuration of the options. The server
+// certificate is verified against the provided roots unless a CA file is
+// set.
+func (o PrometheusClientOptions) tlsConfig(roots *x509.CertPool, serverName string) (*tls.Config, error) {
+	cfg := &tls.Config{RootCAs: roots, ServerName: serverName}
+	if o.CAFile != "" {
+		caCertPool, err := loadCAFile(o.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("Error loading prometheus CA file: %v", err)
+		}
+		cfg.RootCAs = caCertPool
+	}
+	if o.CertFile != "" || o.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("Error loading prometheus client certificate: %v", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+// roundTripper wraps rt so the requests carry the headers and the
+// credentials of the options. The provided bearer token takes precedence
+// over the token file and the basic authentication.
+func (o PrometheusClientOptions) roundTripper(authToken string, rt http.RoundTripper) http.RoundTripper {
+	if len(o.Headers) > 0 {
+		headers := &config.Headers{Headers: map[string]config.Header{}}
+		for name, value := range o.Headers {
+			headers.Headers[name] = config.Header{Values: []string{value}}
+		}
+		rt = config.NewHeadersRoundTripper(headers, rt)
+	}
+	switch {
+	case authToken != "":
+		return config.NewAuthorizationCredentialsRoundTripper("Bearer", config.NewInlineSecret(authToken), rt)
+	case o.TokenFile != "":
+		return config.NewAuthorizationCredentialsRoundTripper("Bearer", config.NewFileSecret(o.TokenFile), rt)
+	case o.Username != "":
+		return config.NewBasicAuthRoundTripper(config.NewInlineSecret(o.Username), config.NewFileSecret(o.PasswordFile), rt)
+	}
+	return rt
+}
+
+// tls returns true if the options customize the TLS configuration.
+func (o PrometheusClientOptions) tls() bool {
+	return o.CAFile != "" || o.CertFile != "" || o.KeyFile != ""
+}
+
+// empty returns true if none of the options is set.
+func (o PrometheusClientOptions) empty() bool {
+	return !o.tls() && o.TokenFile == "" && o.Username == "" && len(o.Headers) == 0
+}
+
+func CreatePrometheusClient(prometheusURL, authToken string, opts PrometheusClientOptions) (promapi.Client, *http.Transport, error) {
 	// Retrieve Pod CA cert
 	caCertPool, err := loadCAFile(K8sPodCAFilePath)
-	if err != nil {
+	if err != nil && opts.CAFile == "" {
 		return nil, nil, fmt.Errorf("Error loading CA file: %v", err)
 	}
 
@@ -134,6 +213,10 @@ func CreatePrometheusClient(prometheusURL, authToken string) (promapi.Client, *h
 	if err != nil {
 		return nil, nil, fmt.Errorf("Error parsing prometheus URL: %v", err)
 	}
+	tlsConfig, err := opts.tlsConfig(caCertPool, u.Host)
+	if err != nil {
+		return nil, nil, err
+	}
 	t := &http.Transport{
 		Proxy: http.ProxyFromEnvironment,
 		DialContext: (&net.Dialer{
@@ -143,20 +226,24 @@ func CreatePrometheusClient(prometheusURL, authToken string) (promapi.Client, *h
 		MaxIdleConns:        100,
 		IdleConnTimeout:     90 * time.Second,
 		TLSHandshakeTimeout: 10 * time.Second,
-		TLSClientConfig: &tls.Config{
-			RootCAs:    caCertPool,
-			ServerName: u.Host,
-		},
+		TLSClientConfig:     tlsConfig,
 	}
-	roundTripper := transport.NewBearerAuthRoundTripper(
-		authToken,
-		t,
-	)
 
 	if authToken != "" {
+		roundTripper := transport.NewBearerAuthRoundTripper(
+			authToken,
+			t,
+		)
+		client, err := promapi.NewClient(promapi.Config{
+			Address:      prometheusURL,
+			RoundTripper: opts.roundTripper(authToken, roundTripper),
+		})
+		return client, t, err
+	}
+	if !opts.empty() {
 		client, err := promapi.NewClient(promapi.Config{
 			Address:      prometheusURL,
-			RoundTripper: config.NewAuthorizationCredentialsRoundTripper("Bearer", config.NewInlineSecret(authToken), roundTripper),
+			RoundTripper: opts.roundTripper("", t),
 		})
 		return client, t, err
 	}
@@ -165,3 +252,30 @@ func CreatePrometheusClient(prometheusURL, authToken string) (promapi.Client, *h
 	})
 	return client, t, err
 }
+
+// CreateLocalPrometheusClient creates a Prometheus client for a descheduler
+// running out of the cluster, e.g. from a laptop. The in cluster CA is not
+// available there, the server certificate is verified against the system
+// roots instead unless a CA file is provided.
+func CreateLocalPrometheusClient(prometheusURL, authToken string, opts PrometheusClientOptions) (promapi.Client, error) {
+	roundTripper := promapi.DefaultRoundTripper
+	if opts.tls() {
+		tlsConfig, err := opts.tlsConfig(nil, "")
+		if err != nil {
+			return nil, err
+		}
+		roundTripper = &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			DialContext: (&net.Dialer{
+				Timeout:   30 * time.Second,
+				KeepAlive: 30 * time.Second,
+			}).DialContext,
+			TLSHandshakeTimeout: 10 * time.Second,
+			TLSClientConfig:     tlsConfig,
+		}
+	}
+	return promapi.NewClient(promapi.Config{
+		Address:      prometheusURL,
+		RoundTripper: opts.roundTripper(authToken, roundTripper),
+	})
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"sigs.k8s.io/descheduler/metrics"
+)
+
+// Types of calls accounted to the plugins besides the lowercased HTTP
+// method of the requests sent to the API server. Used as the "call" label
+// of the plugin API calls metric.
+const (
+	PluginCallEvict   = "evict"
+	PluginCallMetrics = "metrics"
+)
+
+type pluginContextKey struct{}
+
+type pluginContext struct {
+	profile string
+	plugin  string
+}
+
+// WithPlugin returns a copy of ctx whose requests are accounted to the plugin
+// of the profile in the plugin API calls metric.
+func WithPlugin(ctx context.Context, profile, plugin string) context.Context {
+	return context.WithValue(ctx, pluginContextKey{}, pluginContext{profile: profile, plugin: plugin})
+}
+
+// CountPluginCall accounts a call to the plugin ctx was created for through
+// WithPlugin. Calls issued outside of a plugin are not accounted.
+func CountPluginCall(ctx context.Context, call string) {
+	pc, ok := ctx.Value(pluginContextKey{}).(pluginContext)
+	if !ok {
+		return
+	}
+	metrics.PluginAPICalls.With(map[string]string{"plugin": pc.plugin, "profile": pc.profile, "call": call}).Inc()
+}
+
+// pluginCallsRoundTripper accounts every request sent to the API server to
+// the plugin the request context was created for.
+type pluginCallsRoundTripper struct {
+	rt http.RoundTripper
+}
+
+func (p *pluginCallsRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	call := strings.ToLower(req.Method)
+	if req.Method == http.MethodPost && strings.HasSuffix(req.URL.Path, "/eviction") {
+		call = PluginCallEvict
+	}
+	CountPluginCall(req.Context(), call)
+	return p.rt.RoundTrip(req)
+}
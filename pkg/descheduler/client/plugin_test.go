@@ -0,0 +1,87 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"k8s.io/component-base/metrics/testutil"
+
+	"sigs.k8s.io/descheduler/metrics"
+)
+
+func TestPluginCallsRoundTripper(t *testing.T) {
+	metrics.Register()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	httpClient := &http.Client{Transport: &pluginCallsRoundTripper{rt: http.DefaultTransport}}
+
+	ctx := context.Background()
+	pluginCtx := WithPlugin(ctx, "test-profile", "FakePlugin")
+	requests := []struct {
+		ctx    context.Context
+		method string
+		path   string
+	}{
+		{ctx: pluginCtx, method: http.MethodGet, path: "/api/v1/pods"},
+		{ctx: pluginCtx, method: http.MethodGet, path: "/api/v1/nodes/n1"},
+		{ctx: pluginCtx, method: http.MethodPost, path: "/api/v1/namespaces/ns/pods/p1/eviction"},
+		{ctx: pluginCtx, method: http.MethodPatch, path: "/api/v1/nodes/n1"},
+		// requests issued outside of a plugin are not accounted.
+		{ctx: ctx, method: http.MethodGet, path: "/api/v1/pods"},
+		{ctx: ctx, method: http.MethodPost, path: "/api/v1/namespaces/ns/pods/p1/eviction"},
+	}
+
+	expected := map[string]float64{"get": 2, PluginCallEvict: 1, "patch": 1, "post": 0}
+	before := map[string]float64{}
+	for call := range expected {
+		value, err := testutil.GetCounterMetricValue(metrics.PluginAPICalls.With(map[string]string{"plugin": "FakePlugin", "profile": "test-profile", "call": call}))
+		if err != nil {
+			t.Fatalf("unable to read counter: %v", err)
+		}
+		before[call] = value
+	}
+
+	for _, r := range requests {
+		req, err := http.NewRequestWithContext(r.ctx, r.method, server.URL+r.path, nil)
+		if err != nil {
+			t.Fatalf("unable to create request: %v", err)
+		}
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	for call, count := range expected {
+		value, err := testutil.GetCounterMetricValue(metrics.PluginAPICalls.With(map[string]string{"plugin": "FakePlugin", "profile": "test-profile", "call": call}))
+		if err != nil {
+			t.Fatalf("unable to read counter: %v", err)
+		}
+		if value-before[call] != count {
+			t.Errorf("expected %v %q calls to be accounted, got %v", count, call, value-before[call])
+		}
+	}
+}
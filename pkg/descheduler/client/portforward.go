@@ -0,0 +1,208 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+	componentbaseconfig "k8s.io/component-base/config"
+)
+
+// ServiceReference points to a port of a service, as written on the
+// command line: namespace/name:port. The port is either the number or the
+// name of a service port, it can be left out when the service has a single
+// port.
+type ServiceReference struct {
+	Namespace string
+	Name      string
+	Port      string
+}
+
+// ParseServiceReference parses a namespace/name[:port] service reference.
+func ParseServiceReference(ref string) (ServiceReference, error) {
+	namespace, rest, found := strings.Cut(ref, "/")
+	if !found || namespace == "" || rest == "" {
+		return ServiceReference{}, fmt.Errorf("invalid service reference %q, expected namespace/name[:port]", ref)
+	}
+	name, port, _ := strings.Cut(rest, ":")
+	if name == "" {
+		return ServiceReference{}, fmt.Errorf("invalid service reference %q, expected namespace/name[:port]", ref)
+	}
+	return ServiceReference{Namespace: namespace, Name: name, Port: port}, nil
+}
+
+func (ref ServiceReference) String() string {
+	if ref.Port == "" {
+		return ref.Namespace + "/" + ref.Name
+	}
+	return ref.Namespace + "/" + ref.Name + ":" + ref.Port
+}
+
+// PortForwardService forwards a local port, picked at random, to a ready
+// pod backing the service, as kubectl port-forward does. The forwarding
+// stops when the context is done. It returns the local port.
+func PortForwardService(ctx context.Context, clientConnection componentbaseconfig.ClientConnectionConfiguration, ref ServiceReference) (uint16, error) {
+	cfg, err := createConfig(clientConnection, "descheduler")
+	if err != nil {
+		return 0, fmt.Errorf("unable to create config: %v", err)
+	}
+	client, err := clientset.NewForConfig(cfg)
+	if err != nil {
+		return 0, err
+	}
+
+	pod, targetPort, err := resolveServiceBackend(ctx, client, ref)
+	if err != nil {
+		return 0, err
+	}
+
+	roundTripper, upgrader, err := spdy.RoundTripperFor(cfg)
+	if err != nil {
+		return 0, fmt.Errorf("unable to create a port-forward round tripper: %v", err)
+	}
+	portForwardURL := client.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("portforward").
+		URL()
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: roundTripper}, http.MethodPost, portForwardURL)
+
+	readyCh := make(chan struct{})
+	forwarder, err := portforward.NewOnAddresses(dialer, []string{"localhost"}, []string{fmt.Sprintf("0:%d", targetPort)}, ctx.Done(), readyCh, io.Discard, io.Discard)
+	if err != nil {
+		return 0, fmt.Errorf("unable to port-forward to %v: %v", ref, err)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- forwarder.ForwardPorts()
+	}()
+
+	select {
+	case <-readyCh:
+	case err := <-errCh:
+		return 0, fmt.Errorf("unable to port-forward to %v: %v", ref, err)
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+
+	ports, err := forwarder.GetPorts()
+	if err != nil {
+		return 0, fmt.Errorf("unable to port-forward to %v: %v", ref, err)
+	}
+	if len(ports) != 1 {
+		return 0, fmt.Errorf("unable to port-forward to %v: expected a single forwarded port, got %v", ref, len(ports))
+	}
+	return ports[0].Local, nil
+}
+
+// resolveServiceBackend returns a ready pod backing the service and the
+// container port the service port targets.
+func resolveServiceBackend(ctx context.Context, client clientset.Interface, ref ServiceReference) (*v1.Pod, int32, error) {
+	service, err := client.CoreV1().Services(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, 0, fmt.Errorf("unable to get service %v/%v: %v", ref.Namespace, ref.Name, err)
+	}
+	servicePort, err := lookupServicePort(service, ref.Port)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(service.Spec.Selector) == 0 {
+		return nil, 0, fmt.Errorf("service %v/%v has no selector", ref.Namespace, ref.Name)
+	}
+
+	pods, err := client.CoreV1().Pods(ref.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(service.Spec.Selector).String(),
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("unable to list the pods of service %v/%v: %v", ref.Namespace, ref.Name, err)
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.DeletionTimestamp != nil || !isPodReady(pod) {
+			continue
+		}
+		targetPort, err := lookupContainerPort(pod, servicePort)
+		if err != nil {
+			return nil, 0, err
+		}
+		return pod, targetPort, nil
+	}
+	return nil, 0, fmt.Errorf("service %v/%v has no ready pod", ref.Namespace, ref.Name)
+}
+
+// lookupServicePort returns the service port with the given number or
+// name, or the only port of the service when none is given.
+func lookupServicePort(service *v1.Service, port string) (v1.ServicePort, error) {
+	if port == "" {
+		if len(service.Spec.Ports) != 1 {
+			return v1.ServicePort{}, fmt.Errorf("service %v/%v has %v ports, one must be picked", service.Namespace, service.Name, len(service.Spec.Ports))
+		}
+		return service.Spec.Ports[0], nil
+	}
+	for _, servicePort := range service.Spec.Ports {
+		if servicePort.Name == port || strconv.Itoa(int(servicePort.Port)) == port {
+			return servicePort, nil
+		}
+	}
+	return v1.ServicePort{}, fmt.Errorf("service %v/%v has no %q port", service.Namespace, service.Name, port)
+}
+
+// lookupContainerPort resolves the target port of a service port on a pod.
+func lookupContainerPort(pod *v1.Pod, servicePort v1.ServicePort) (int32, error) {
+	switch {
+	case servicePort.TargetPort.Type == intstr.Int && servicePort.TargetPort.IntVal != 0:
+		return servicePort.TargetPort.IntVal, nil
+	case servicePort.TargetPort.Type == intstr.String && servicePort.TargetPort.StrVal != "":
+		for _, container := range pod.Spec.Containers {
+			for _, containerPort := range container.Ports {
+				if containerPort.Name == servicePort.TargetPort.StrVal {
+					return containerPort.ContainerPort, nil
+				}
+			}
+		}
+		return 0, fmt.Errorf("pod %v/%v has no %q port", pod.Namespace, pod.Name, servicePort.TargetPort.StrVal)
+	default:
+		// the target port defaults to the service port
+		return servicePort.Port, nil
+	}
+}
+
+func isPodReady(pod *v1.Pod) bool {
+	if pod.Status.Phase != v1.PodRunning {
+		return false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
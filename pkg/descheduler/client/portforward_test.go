@@ -0,0 +1,176 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestParseServiceReference(t *testing.T) {
+	tests := []struct {
+		ref      string
+		expected ServiceReference
+		err      bool
+	}{
+		{ref: "monitoring/prometheus", expected: ServiceReference{Namespace: "monitoring", Name: "prometheus"}},
+		{ref: "monitoring/prometheus:9090", expected: ServiceReference{Namespace: "monitoring", Name: "prometheus", Port: "9090"}},
+		{ref: "monitoring/prometheus:web", expected: ServiceReference{Namespace: "monitoring", Name: "prometheus", Port: "web"}},
+		{ref: "prometheus", err: true},
+		{ref: "/prometheus", err: true},
+		{ref: "monitoring/", err: true},
+		{ref: "monitoring/:9090", err: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.ref, func(t *testing.T) {
+			ref, err := ParseServiceReference(tc.ref)
+			if tc.err {
+				if err == nil {
+					t.Fatalf("expected an error, got %v instead", ref)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ref != tc.expected {
+				t.Errorf("expected %v, got %v instead", tc.expected, ref)
+			}
+			if ref.String() != tc.ref {
+				t.Errorf("expected the reference to print as %q, got %q instead", tc.ref, ref.String())
+			}
+		})
+	}
+}
+
+func TestResolveServiceBackend(t *testing.T) {
+	service := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring", Name: "prometheus"},
+		Spec: v1.ServiceSpec{
+			Selector: map[string]string{"app": "prometheus"},
+			Ports: []v1.ServicePort{
+				{Name: "web", Port: 9090, TargetPort: intstr.FromString("http")},
+				{Name: "reloader", Port: 8080, TargetPort: intstr.FromInt32(8081)},
+				{Name: "grpc", Port: 10901},
+			},
+		},
+	}
+	buildPod := func(name string, ready bool) *v1.Pod {
+		status := v1.ConditionFalse
+		if ready {
+			status = v1.ConditionTrue
+		}
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring", Name: name, Labels: map[string]string{"app": "prometheus"}},
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{{
+					Name:  "prometheus",
+					Ports: []v1.ContainerPort{{Name: "http", ContainerPort: 9091}},
+				}},
+			},
+			Status: v1.PodStatus{
+				Phase:      v1.PodRunning,
+				Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: status}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		port         string
+		pods         []*v1.Pod
+		expectedPod  string
+		expectedPort int32
+		err          bool
+	}{
+		{
+			name:         "named target port",
+			port:         "web",
+			pods:         []*v1.Pod{buildPod("p1", true)},
+			expectedPod:  "p1",
+			expectedPort: 9091,
+		},
+		{
+			name:         "numbered target port",
+			port:         "8080",
+			pods:         []*v1.Pod{buildPod("p1", true)},
+			expectedPod:  "p1",
+			expectedPort: 8081,
+		},
+		{
+			name:         "target port defaulting to the service port",
+			port:         "grpc",
+			pods:         []*v1.Pod{buildPod("p1", true)},
+			expectedPod:  "p1",
+			expectedPort: 10901,
+		},
+		{
+			name:         "pods not ready are skipped",
+			port:         "web",
+			pods:         []*v1.Pod{buildPod("p1", false), buildPod("p2", true)},
+			expectedPod:  "p2",
+			expectedPort: 9091,
+		},
+		{
+			name: "no ready pod",
+			port: "web",
+			pods: []*v1.Pod{buildPod("p1", false)},
+			err:  true,
+		},
+		{
+			name: "unknown port",
+			port: "9999",
+			pods: []*v1.Pod{buildPod("p1", true)},
+			err:  true,
+		},
+		{
+			name: "port required with several ports",
+			pods: []*v1.Pod{buildPod("p1", true)},
+			err:  true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(service)
+			for _, pod := range tc.pods {
+				if _, err := client.CoreV1().Pods(pod.Namespace).Create(context.Background(), pod, metav1.CreateOptions{}); err != nil {
+					t.Fatalf("unable to create pod: %v", err)
+				}
+			}
+
+			pod, port, err := resolveServiceBackend(context.Background(), client, ServiceReference{Namespace: "monitoring", Name: "prometheus", Port: tc.port})
+			if tc.err {
+				if err == nil {
+					t.Fatalf("expected an error, got %v:%v instead", pod.Name, port)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if pod.Name != tc.expectedPod || port != tc.expectedPort {
+				t.Errorf("expected %v:%v, got %v:%v instead", tc.expectedPod, tc.expectedPort, pod.Name, port)
+			}
+		})
+	}
+}
@@ -29,9 +29,12 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	v1 "k8s.io/api/core/v1"
 	policy "k8s.io/api/policy/v1"
 	policyv1 "k8s.io/api/policy/v1"
+	resourcev1beta1 "k8s.io/api/resource/v1beta1"
 	schedulingv1 "k8s.io/api/scheduling/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -63,6 +66,7 @@ import (
 	"sigs.k8s.io/descheduler/pkg/descheduler/metricscollector"
 	nodeutil "sigs.k8s.io/descheduler/pkg/descheduler/node"
 	podutil "sigs.k8s.io/descheduler/pkg/descheduler/pod"
+	"sigs.k8s.io/descheduler/pkg/features"
 	"sigs.k8s.io/descheduler/pkg/framework/pluginregistry"
 	frameworkprofile "sigs.k8s.io/descheduler/pkg/framework/profile"
 	frameworktypes "sigs.k8s.io/descheduler/pkg/framework/types"
@@ -99,6 +103,10 @@ type descheduler struct {
 	queue                             workqueue.RateLimitingInterface
 	currentPrometheusAuthToken        string
 	metricsProviders                  map[api.MetricsSource]*api.MetricsProvider
+	terminationNotices                chan struct{}
+	rand                              *frameworktypes.Rand
+	sharedCache                       *frameworktypes.SharedCache
+	deviceAccounting                  *nodeutil.DeviceAccounting
 }
 
 type informerResources struct {
@@ -153,6 +161,13 @@ func metricsProviderListToMap(providersList []api.MetricsProvider) map[api.Metri
 	return providersMap
 }
 
+// usesKubernetesMetrics tells whether the policy reads the actual usage
+// from the metrics.k8s.io API.
+func usesKubernetesMetrics(deschedulerPolicy *api.DeschedulerPolicy) bool {
+	return (deschedulerPolicy.MetricsCollector != nil && deschedulerPolicy.MetricsCollector.Enabled) ||
+		metricsProviderListToMap(deschedulerPolicy.MetricsProviders)[api.KubernetesMetrics] != nil
+}
+
 func newDescheduler(ctx context.Context, rs *options.DeschedulerServer, deschedulerPolicy *api.DeschedulerPolicy, evictionPolicyGroupVersion string, eventRecorder events.EventRecorder, sharedInformerFactory, namespacedSharedInformerFactory informers.SharedInformerFactory) (*descheduler, error) {
 	podInformer := sharedInformerFactory.Core().V1().Pods().Informer()
 
@@ -161,9 +176,11 @@ func newDescheduler(ctx context.Context, rs *options.DeschedulerServer, deschedu
 		v1.SchemeGroupVersion.WithResource("nodes"),
 		// Future work could be to let each plugin declare what type of resources it needs; that way dry runs would stay
 		// consistent with the real runs without having to keep the list here in sync.
-		v1.SchemeGroupVersion.WithResource("namespaces"),                 // Used by the defaultevictor plugin
-		schedulingv1.SchemeGroupVersion.WithResource("priorityclasses"),  // Used by the defaultevictor plugin
-		policyv1.SchemeGroupVersion.WithResource("poddisruptionbudgets"), // Used by the defaultevictor plugin
+		v1.SchemeGroupVersion.WithResource("namespaces"),                          // Used by the defaultevictor plugin
+		schedulingv1.SchemeGroupVersion.WithResource("priorityclasses"),           // Used by the defaultevictor plugin
+		policyv1.SchemeGroupVersion.WithResource("poddisruptionbudgets"),          // Used by the defaultevictor and nodeutilization plugins
+		autoscalingv2.SchemeGroupVersion.WithResource("horizontalpodautoscalers"), // Used by the defaultevictor plugin
+		appsv1.SchemeGroupVersion.WithResource("replicasets"),                     // Used by the defaultevictor plugin
 
 	) // Used by the defaultevictor plugin
 
@@ -172,9 +189,14 @@ func newDescheduler(ctx context.Context, rs *options.DeschedulerServer, deschedu
 		return nil, fmt.Errorf("build get pods assigned to node function error: %v", err)
 	}
 
+	evictionClient := rs.EvictionClient
+	if evictionClient == nil {
+		evictionClient = rs.Client
+	}
+
 	podEvictor, err := evictions.NewPodEvictor(
 		ctx,
-		rs.Client,
+		evictionClient,
 		eventRecorder,
 		podInformer,
 		rs.DefaultFeatureGates,
@@ -185,6 +207,10 @@ func newDescheduler(ctx context.Context, rs *options.DeschedulerServer, deschedu
 			WithMaxPodsToEvictTotal(deschedulerPolicy.MaxNoOfPodsToEvictTotal).
 			WithEvictionFailureEventNotification(deschedulerPolicy.EvictionFailureEventNotification).
 			WithGracePeriodSeconds(deschedulerPolicy.GracePeriodSeconds).
+			WithConnectionDraining(deschedulerPolicy.ConnectionDraining).
+			WithSessionDraining(deschedulerPolicy.SessionDraining).
+			WithNotifications(deschedulerPolicy.Notifications).
+			WithCloudEvents(deschedulerPolicy.CloudEvents).
 			WithDryRun(rs.DryRun).
 			WithMetricsEnabled(!rs.DisableMetrics),
 	)
@@ -192,6 +218,13 @@ func newDescheduler(ctx context.Context, rs *options.DeschedulerServer, deschedu
 		return nil, err
 	}
 
+	seed := rs.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	} else {
+		klog.InfoS("Running in seeded mode, randomized choices are reproducible", "seed", seed)
+	}
+
 	desch := &descheduler{
 		rs:                     rs,
 		ir:                     ir,
@@ -204,6 +237,32 @@ func newDescheduler(ctx context.Context, rs *options.DeschedulerServer, deschedu
 		prometheusClient:       rs.PrometheusClient,
 		queue:                  workqueue.NewRateLimitingQueueWithConfig(workqueue.DefaultControllerRateLimiter(), workqueue.RateLimitingQueueConfig{Name: "descheduler"}),
 		metricsProviders:       metricsProviderListToMap(deschedulerPolicy.MetricsProviders),
+		terminationNotices:     make(chan struct{}, 1),
+		rand:                   frameworktypes.NewRand(seed),
+		sharedCache:            frameworktypes.NewSharedCache(),
+	}
+
+	// nodes receiving a termination notice from their cloud provider are
+	// usually gone within a couple of minutes, a descheduling cycle is run
+	// right away instead of waiting for the next interval.
+	if _, err := sharedInformerFactory.Core().V1().Nodes().Informer().AddEventHandler(desch.terminationNoticeHandler()); err != nil {
+		return nil, err
+	}
+
+	// devices allocated through Dynamic Resource Allocation are only
+	// accounted when asked for, the informers would never sync on clusters
+	// not serving the resource.k8s.io API.
+	if rs.DefaultFeatureGates != nil && rs.DefaultFeatureGates.Enabled(features.DynamicResourceAllocation) {
+		if err := ir.Uses(
+			resourcev1beta1.SchemeGroupVersion.WithResource("resourceslices"),
+			resourcev1beta1.SchemeGroupVersion.WithResource("resourceclaims"),
+		); err != nil {
+			return nil, err
+		}
+		desch.deviceAccounting, err = nodeutil.NewDeviceAccounting(sharedInformerFactory)
+		if err != nil {
+			return nil, fmt.Errorf("build device accounting error: %v", err)
+		}
 	}
 
 	if rs.MetricsClient != nil {
@@ -234,13 +293,23 @@ func newDescheduler(ctx context.Context, rs *options.DeschedulerServer, deschedu
 	return desch, nil
 }
 
+// evictionClient returns the client pods are evicted with. It falls back
+// to the main client when no dedicated eviction client was configured.
+func (d *descheduler) evictionClient() clientset.Interface {
+	if d.rs.EvictionClient != nil {
+		return d.rs.EvictionClient
+	}
+	return d.rs.Client
+}
+
 func (d *descheduler) reconcileInClusterSAToken() error {
 	// Read the sa token and assume it has the sufficient permissions to authenticate
 	cfg, err := rest.InClusterConfig()
 	if err == nil {
 		if d.currentPrometheusAuthToken != cfg.BearerToken {
 			klog.V(2).Infof("Creating Prometheus client (with SA token)")
-			prometheusClient, transport, err := client.CreatePrometheusClient(d.metricsProviders[api.PrometheusMetrics].Prometheus.URL, cfg.BearerToken)
+			prometheusConfig := d.metricsProviders[api.PrometheusMetrics].Prometheus
+			prometheusClient, transport, err := client.CreatePrometheusClient(prometheusConfig.URL, cfg.BearerToken, prometheusClientOptions(prometheusConfig))
 			if err != nil {
 				return fmt.Errorf("unable to create a prometheus client: %v", err)
 			}
@@ -324,7 +393,7 @@ func (d *descheduler) sync() error {
 	}
 
 	klog.V(2).Infof("authentication secret token updated, recreating prometheus client")
-	prometheusClient, transport, err := client.CreatePrometheusClient(prometheusConfig.URL, authToken)
+	prometheusClient, transport, err := client.CreatePrometheusClient(prometheusConfig.URL, authToken, prometheusClientOptions(prometheusConfig))
 	if err != nil {
 		return fmt.Errorf("unable to create a prometheus client: %v", err)
 	}
@@ -337,6 +406,25 @@ func (d *descheduler) sync() error {
 	return nil
 }
 
+// prometheusClientOptions returns the options of the prometheus client out of
+// its configuration.
+func prometheusClientOptions(prometheusConfig *api.Prometheus) client.PrometheusClientOptions {
+	opts := client.PrometheusClientOptions{
+		TokenFile: prometheusConfig.TokenFile,
+		Headers:   prometheusConfig.Headers,
+	}
+	if basicAuth := prometheusConfig.BasicAuth; basicAuth != nil {
+		opts.Username = basicAuth.Username
+		opts.PasswordFile = basicAuth.PasswordFile
+	}
+	if tls := prometheusConfig.TLS; tls != nil {
+		opts.CAFile = tls.CAFile
+		opts.CertFile = tls.CertFile
+		opts.KeyFile = tls.KeyFile
+	}
+	return opts
+}
+
 func (d *descheduler) eventHandler() cache.ResourceEventHandler {
 	return cache.ResourceEventHandlerFuncs{
 		AddFunc:    func(obj interface{}) { d.queue.Add(workQueueKey) },
@@ -345,6 +433,58 @@ func (d *descheduler) eventHandler() cache.ResourceEventHandler {
 	}
 }
 
+// terminationNoticeHandler signals terminationNotices when a node is
+// tainted by a cloud provider termination handler.
+func (d *descheduler) terminationNoticeHandler() cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(old, new interface{}) {
+			oldNode, ok := old.(*v1.Node)
+			if !ok {
+				return
+			}
+			newNode, ok := new.(*v1.Node)
+			if !ok {
+				return
+			}
+			if nodeutil.IsNodeBeingTerminated(oldNode) || !nodeutil.IsNodeBeingTerminated(newNode) {
+				return
+			}
+			klog.V(1).InfoS("Node received a termination notice", "node", klog.KObj(newNode))
+			select {
+			case d.terminationNotices <- struct{}{}:
+			default:
+			}
+		},
+	}
+}
+
+// runUntil runs f every period until the context is done, as
+// wait.NonSlidingUntil does: the period includes the time f takes to run. f
+// is run right away, without waiting for the period to expire, when trigger
+// is signaled.
+func runUntil(ctx context.Context, f func(), period time.Duration, trigger <-chan struct{}) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		timer := time.NewTimer(period)
+		f()
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		case <-trigger:
+			timer.Stop()
+			klog.V(1).InfoS("Running descheduling cycle ahead of the interval after a node termination notice")
+		}
+	}
+}
+
 func (d *descheduler) runDeschedulerLoop(ctx context.Context, nodes []*v1.Node) error {
 	var span trace.Span
 	ctx, span = tracing.Tracer().Start(ctx, "runDeschedulerLoop")
@@ -359,7 +499,7 @@ func (d *descheduler) runDeschedulerLoop(ctx context.Context, nodes []*v1.Node)
 		return fmt.Errorf("the cluster size is 0 or 1")
 	}
 
-	var client clientset.Interface
+	var client, evictionClient clientset.Interface
 	// When the dry mode is enable, collect all the relevant objects (mostly pods) under a fake client.
 	// So when evicting pods while running multiple strategies in a row have the cummulative effect
 	// as is when evicting pods for real.
@@ -382,6 +522,12 @@ func (d *descheduler) runDeschedulerLoop(ctx context.Context, nodes []*v1.Node)
 		if err != nil {
 			return fmt.Errorf("build get pods assigned to node function error: %v", err)
 		}
+		if d.deviceAccounting != nil {
+			d.deviceAccounting, err = nodeutil.NewDeviceAccounting(fakeSharedInformerFactory)
+			if err != nil {
+				return fmt.Errorf("build device accounting error: %v", err)
+			}
+		}
 
 		fakeCtx, cncl := context.WithCancel(context.TODO())
 		defer cncl()
@@ -389,18 +535,38 @@ func (d *descheduler) runDeschedulerLoop(ctx context.Context, nodes []*v1.Node)
 		fakeSharedInformerFactory.WaitForCacheSync(fakeCtx.Done())
 
 		client = fakeClient
+		evictionClient = fakeClient
 		d.sharedInformerFactory = fakeSharedInformerFactory
 	} else {
 		client = d.rs.Client
+		evictionClient = d.evictionClient()
 	}
 
 	klog.V(3).Infof("Setting up the pod evictor")
-	d.podEvictor.SetClient(client)
+	d.podEvictor.SetClient(evictionClient)
 	d.podEvictor.ResetCounters()
 
-	d.runProfiles(ctx, client, nodes)
+	// listers return nodes and pods in no particular order. in seeded mode
+	// they are processed in an order that only depends on the seed so
+	// ties between them are broken the same way from one run to the next.
+	podsAssignedToNode := d.getPodsAssignedToNode
+	if d.rs.Seed != 0 {
+		nodes = seededNodeOrder(d.rand, nodes)
+		podsAssignedToNode = seededPodOrder(d.rand, podsAssignedToNode)
+	}
+
+	// the pods assigned to the nodes are indexed once for the whole cycle
+	// and shared by all profiles and plugins. pods evicted during the cycle
+	// are hidden so the evictions have a cumulative effect.
+	getPodsAssignedToNode, err := podutil.BuildPodsByNodeIndex(nodes, podsAssignedToNode, d.podEvictor.WasEvicted)
+	if err != nil {
+		return fmt.Errorf("build pods by node index error: %v", err)
+	}
+
+	d.runProfiles(ctx, client, nodes, getPodsAssignedToNode)
 
 	klog.V(1).InfoS("Number of evictions/requests", "totalEvicted", d.podEvictor.TotalEvicted(), "evictionRequests", d.podEvictor.TotalEvictionRequests())
+	d.podEvictor.NotifyCycleSummary(ctx)
 
 	return nil
 }
@@ -408,7 +574,7 @@ func (d *descheduler) runDeschedulerLoop(ctx context.Context, nodes []*v1.Node)
 // runProfiles runs all the deschedule plugins of all profiles and
 // later runs through all balance plugins of all profiles. (All Balance plugins should come after all Deschedule plugins)
 // see https://github.com/kubernetes-sigs/descheduler/issues/979
-func (d *descheduler) runProfiles(ctx context.Context, client clientset.Interface, nodes []*v1.Node) {
+func (d *descheduler) runProfiles(ctx context.Context, client clientset.Interface, nodes []*v1.Node, getPodsAssignedToNode podutil.GetPodsAssignedToNodeFunc) {
 	var span trace.Span
 	ctx, span = tracing.Tracer().Start(ctx, "runProfiles")
 	defer span.End()
@@ -420,9 +586,12 @@ func (d *descheduler) runProfiles(ctx context.Context, client clientset.Interfac
 			frameworkprofile.WithClientSet(client),
 			frameworkprofile.WithSharedInformerFactory(d.sharedInformerFactory),
 			frameworkprofile.WithPodEvictor(d.podEvictor),
-			frameworkprofile.WithGetPodsAssignedToNodeFnc(d.getPodsAssignedToNode),
+			frameworkprofile.WithGetPodsAssignedToNodeFnc(getPodsAssignedToNode),
 			frameworkprofile.WithMetricsCollector(d.metricsCollector),
+			frameworkprofile.WithDeviceAccounting(d.deviceAccounting),
 			frameworkprofile.WithPrometheusClient(d.prometheusClient),
+			frameworkprofile.WithRand(d.rand),
+			frameworkprofile.WithSharedCache(d.sharedCache),
 		)
 		if err != nil {
 			klog.ErrorS(err, "unable to create a profile", "profile", profile.Name)
@@ -462,12 +631,13 @@ func Run(ctx context.Context, rs *options.DeschedulerServer) error {
 	if rs.KubeconfigFile != "" && clientConnection.Kubeconfig == "" {
 		clientConnection.Kubeconfig = rs.KubeconfigFile
 	}
-	rsclient, eventClient, err := createClients(clientConnection)
+	rsclient, eventClient, evictionClient, err := createClients(clientConnection, rs.EvictionClientQPS, rs.EvictionClientBurst)
 	if err != nil {
 		return err
 	}
 	rs.Client = rsclient
 	rs.EventClient = eventClient
+	rs.EvictionClient = evictionClient
 
 	deschedulerPolicy, err := LoadPolicyConfig(rs.PolicyConfigFile, rs.Client, pluginregistry.PluginRegistry)
 	if err != nil {
@@ -487,7 +657,7 @@ func Run(ctx context.Context, rs *options.DeschedulerServer) error {
 		return err
 	}
 
-	if (deschedulerPolicy.MetricsCollector != nil && deschedulerPolicy.MetricsCollector.Enabled) || metricsProviderListToMap(deschedulerPolicy.MetricsProviders)[api.KubernetesMetrics] != nil {
+	if usesKubernetesMetrics(deschedulerPolicy) {
 		metricsClient, err := client.CreateMetricsClient(clientConnection, "descheduler")
 		if err != nil {
 			return err
@@ -576,6 +746,9 @@ const (
 	noReconciliation tokenReconciliation = iota
 	inClusterReconciliation
 	secretReconciliation
+	// fileCredentials needs no reconciliation, the credentials are read
+	// from files on every query.
+	fileCredentials
 )
 
 func RunDeschedulerStrategies(ctx context.Context, rs *options.DeschedulerServer, deschedulerPolicy *api.DeschedulerPolicy, evictionPolicyGroupVersion string) error {
@@ -583,7 +756,7 @@ func RunDeschedulerStrategies(ctx context.Context, rs *options.DeschedulerServer
 	ctx, span = tracing.Tracer().Start(ctx, "RunDeschedulerStrategies")
 	defer span.End()
 
-	sharedInformerFactory := informers.NewSharedInformerFactoryWithOptions(rs.Client, 0, informers.WithTransform(trimManagedFields))
+	sharedInformerFactory := newSharedInformerFactory(rs.Client)
 
 	var nodeSelector string
 	if deschedulerPolicy.NodeSelector != nil {
@@ -606,8 +779,10 @@ func RunDeschedulerStrategies(ctx context.Context, rs *options.DeschedulerServer
 	if prometheusProvider != nil && prometheusProvider.Prometheus != nil && prometheusProvider.Prometheus.URL != "" {
 		if prometheusProvider.Prometheus.AuthToken != nil {
 			// Will get reconciled
-			namespacedSharedInformerFactory = informers.NewSharedInformerFactoryWithOptions(rs.Client, 0, informers.WithTransform(trimManagedFields), informers.WithNamespace(prometheusProvider.Prometheus.AuthToken.SecretReference.Namespace))
+			namespacedSharedInformerFactory = newSharedInformerFactory(rs.Client, informers.WithNamespace(prometheusProvider.Prometheus.AuthToken.SecretReference.Namespace))
 			metricProviderTokenReconciliation = secretReconciliation
+		} else if prometheusProvider.Prometheus.TokenFile != "" || prometheusProvider.Prometheus.BasicAuth != nil {
+			metricProviderTokenReconciliation = fileCredentials
 		} else {
 			// Use the sa token and assume it has the sufficient permissions to authenticate
 			metricProviderTokenReconciliation = inClusterReconciliation
@@ -651,7 +826,17 @@ func RunDeschedulerStrategies(ctx context.Context, rs *options.DeschedulerServer
 		go descheduler.runAuthenticationSecretReconciler(ctx)
 	}
 
-	wait.NonSlidingUntil(func() {
+	// a client created beforehand, e.g. for a local run, is kept.
+	if metricProviderTokenReconciliation == fileCredentials && descheduler.prometheusClient == nil {
+		prometheusClient, transport, err := client.CreatePrometheusClient(prometheusProvider.Prometheus.URL, "", prometheusClientOptions(prometheusProvider.Prometheus))
+		if err != nil {
+			return fmt.Errorf("unable to create a prometheus client: %v", err)
+		}
+		descheduler.prometheusClient = prometheusClient
+		descheduler.previousPrometheusClientTransport = transport
+	}
+
+	runUntil(ctx, func() {
 		if metricProviderTokenReconciliation == inClusterReconciliation {
 			// Read the sa token and assume it has the sufficient permissions to authenticate
 			if err := descheduler.reconcileInClusterSAToken(); err != nil {
@@ -682,7 +867,7 @@ func RunDeschedulerStrategies(ctx context.Context, rs *options.DeschedulerServer
 		if rs.DeschedulingInterval.Seconds() == 0 {
 			cancel()
 		}
-	}, rs.DeschedulingInterval, ctx.Done())
+	}, rs.DeschedulingInterval, descheduler.terminationNotices)
 
 	return nil
 }
@@ -696,23 +881,72 @@ func GetPluginConfig(pluginName string, pluginConfigs []api.PluginConfig) (*api.
 	return nil, 0
 }
 
-func createClients(clientConnection componentbaseconfig.ClientConnectionConfiguration) (clientset.Interface, clientset.Interface, error) {
+func createClients(clientConnection componentbaseconfig.ClientConnectionConfiguration, evictionQPS float32, evictionBurst int32) (clientset.Interface, clientset.Interface, clientset.Interface, error) {
 	kClient, err := client.CreateClient(clientConnection, "descheduler")
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	eventClient, err := client.CreateClient(clientConnection, "")
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
-	return kClient, eventClient, nil
+	// evictions get a client of their own, with its own rate limiter, so
+	// a large batch of evictions does not delay the informers watches.
+	evictionClient, err := client.CreateClient(evictionClientConnection(clientConnection, evictionQPS, evictionBurst), "descheduler")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return kClient, eventClient, evictionClient, nil
 }
 
-func trimManagedFields(obj interface{}) (interface{}, error) {
+// evictionClientConnection returns the connection configuration of the
+// eviction client. Unset (zero) eviction limits fall back to the ones of
+// the client connection.
+func evictionClientConnection(clientConnection componentbaseconfig.ClientConnectionConfiguration, evictionQPS float32, evictionBurst int32) componentbaseconfig.ClientConnectionConfiguration {
+	evictionConnection := clientConnection
+	if evictionQPS > 0 {
+		evictionConnection.QPS = evictionQPS
+	}
+	if evictionBurst > 0 {
+		evictionConnection.Burst = evictionBurst
+	}
+	return evictionConnection
+}
+
+// newSharedInformerFactory builds the informer factory with objects trimmed
+// before they are cached. The initial lists are chunked by the informers
+// reflectors already, whenever the API server serves them in pages.
+func newSharedInformerFactory(client clientset.Interface, opts ...informers.SharedInformerOption) informers.SharedInformerFactory {
+	opts = append([]informers.SharedInformerOption{
+		informers.WithTransform(trimObject),
+	}, opts...)
+	return informers.NewSharedInformerFactoryWithOptions(client, 0, opts...)
+}
+
+// trimObject drops the fields none of the plugins read before an object
+// is stored in the informers cache. On pods this includes the container
+// environments, which are often the largest part of the pod spec.
+func trimObject(obj interface{}) (interface{}, error) {
 	if accessor, err := meta.Accessor(obj); err == nil {
 		accessor.SetManagedFields(nil)
 	}
+	if pod, ok := obj.(*v1.Pod); ok {
+		trimContainers(pod.Spec.InitContainers)
+		trimContainers(pod.Spec.Containers)
+		for i := range pod.Spec.EphemeralContainers {
+			pod.Spec.EphemeralContainers[i].Env = nil
+			pod.Spec.EphemeralContainers[i].EnvFrom = nil
+		}
+	}
 	return obj, nil
 }
+
+func trimContainers(containers []v1.Container) {
+	for i := range containers {
+		containers[i].Env = nil
+		containers[i].EnvFrom = nil
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math/rand"
 	"net/http"
+	"reflect"
 	"testing"
 	"time"
 
@@ -13,13 +14,15 @@ import (
 	policy "k8s.io/api/policy/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	apiversion "k8s.io/apimachinery/pkg/version"
+	"k8s.io/apimachinery/pkg/watch"
 	fakediscovery "k8s.io/client-go/discovery/fake"
-	"k8s.io/client-go/informers"
 	fakeclientset "k8s.io/client-go/kubernetes/fake"
 	core "k8s.io/client-go/testing"
+	componentbaseconfig "k8s.io/component-base/config"
 	"k8s.io/component-base/featuregate"
 	"k8s.io/klog/v2"
 	"k8s.io/metrics/pkg/apis/metrics/v1beta1"
@@ -58,7 +61,8 @@ var (
 func initFeatureGates() featuregate.FeatureGate {
 	featureGates := featuregate.NewFeatureGate()
 	featureGates.Add(map[featuregate.Feature]featuregate.FeatureSpec{
-		features.EvictionsInBackground: {Default: false, PreRelease: featuregate.Alpha},
+		features.EvictionsInBackground:     {Default: false, PreRelease: featuregate.Alpha},
+		features.DynamicResourceAllocation: {Default: false, PreRelease: featuregate.Alpha},
 	})
 	return featureGates
 }
@@ -190,7 +194,7 @@ func initDescheduler(t *testing.T, ctx context.Context, featureGates featuregate
 	rs.DefaultFeatureGates = featureGates
 	rs.MetricsClient = metricsClient
 
-	sharedInformerFactory := informers.NewSharedInformerFactoryWithOptions(rs.Client, 0, informers.WithTransform(trimManagedFields))
+	sharedInformerFactory := newSharedInformerFactory(rs.Client)
 	eventBroadcaster, eventRecorder := utils.GetRecorderAndBroadcaster(ctx, client)
 
 	descheduler, err := newDescheduler(ctx, rs, internalDeschedulerPolicy, "v1", eventRecorder, sharedInformerFactory, nil)
@@ -544,6 +548,44 @@ func TestPodEvictorReset(t *testing.T) {
 	}
 }
 
+func TestEvictionClient(t *testing.T) {
+	initPluginRegistry()
+
+	ctx := context.Background()
+	node1 := test.BuildTestNode("n1", 2000, 3000, 10, taintNodeNoSchedule)
+	node2 := test.BuildTestNode("n2", 2000, 3000, 10, nil)
+	nodes := []*v1.Node{node1, node2}
+
+	ownerRef1 := test.GetReplicaSetOwnerRefList()
+	updatePod := func(pod *v1.Pod) {
+		pod.Namespace = "dev"
+		pod.ObjectMeta.OwnerReferences = ownerRef1
+	}
+
+	p1 := test.BuildTestPod("p1", 100, 0, node1.Name, updatePod)
+	p2 := test.BuildTestPod("p2", 100, 0, node1.Name, updatePod)
+
+	internalDeschedulerPolicy := removePodsViolatingNodeTaintsPolicy()
+	ctxCancel, cancel := context.WithCancel(ctx)
+	rs, descheduler, client := initDescheduler(t, ctxCancel, initFeatureGates(), internalDeschedulerPolicy, nil, node1, node2, p1, p2)
+	defer cancel()
+
+	var evictedPods []string
+	client.PrependReactor("create", "pods", podEvictionReactionTestingFnc(&evictedPods, nil, nil))
+
+	evictionClient := fakeclientset.NewSimpleClientset(p1, p2)
+	var evictedThroughEvictionClient []string
+	evictionClient.PrependReactor("create", "pods", podEvictionReactionTestingFnc(&evictedThroughEvictionClient, nil, nil))
+	rs.EvictionClient = evictionClient
+
+	if err := descheduler.runDeschedulerLoop(ctx, nodes); err != nil {
+		t.Fatalf("Unable to run a descheduling loop: %v", err)
+	}
+	if len(evictedPods) != 0 || len(evictedThroughEvictionClient) != 2 {
+		t.Fatalf("Expected (0,2) pods evicted through the (main,eviction) clients, got (%v, %v) instead", len(evictedPods), len(evictedThroughEvictionClient))
+	}
+}
+
 func checkTotals(t *testing.T, ctx context.Context, descheduler *descheduler, totalEvictionRequests, totalEvicted uint) {
 	if total := descheduler.podEvictor.TotalEvictionRequests(); total != totalEvictionRequests {
 		t.Fatalf("Expected %v total eviction requests, got %v instead", totalEvictionRequests, total)
@@ -593,7 +635,8 @@ func TestEvictionRequestsCache(t *testing.T) {
 	ctxCancel, cancel := context.WithCancel(ctx)
 	featureGates := featuregate.NewFeatureGate()
 	featureGates.Add(map[featuregate.Feature]featuregate.FeatureSpec{
-		features.EvictionsInBackground: {Default: true, PreRelease: featuregate.Alpha},
+		features.EvictionsInBackground:     {Default: true, PreRelease: featuregate.Alpha},
+		features.DynamicResourceAllocation: {Default: false, PreRelease: featuregate.Alpha},
 	})
 	_, descheduler, client := initDescheduler(t, ctxCancel, featureGates, internalDeschedulerPolicy, nil, node1, node2, p1, p2, p3, p4)
 	defer cancel()
@@ -735,7 +778,8 @@ func TestDeschedulingLimits(t *testing.T) {
 			ctxCancel, cancel := context.WithCancel(ctx)
 			featureGates := featuregate.NewFeatureGate()
 			featureGates.Add(map[featuregate.Feature]featuregate.FeatureSpec{
-				features.EvictionsInBackground: {Default: true, PreRelease: featuregate.Alpha},
+				features.EvictionsInBackground:     {Default: true, PreRelease: featuregate.Alpha},
+				features.DynamicResourceAllocation: {Default: false, PreRelease: featuregate.Alpha},
 			})
 			_, descheduler, client := initDescheduler(t, ctxCancel, featureGates, tc.policy, nil, node1, node2)
 			defer cancel()
@@ -867,3 +911,211 @@ func TestLoadAwareDescheduling(t *testing.T) {
 	}
 	t.Logf("Total evictions: %v", totalEs)
 }
+
+func TestInformerCacheTrimsPods(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pod := test.BuildTestPod("p1", 100, 0, "n1", func(pod *v1.Pod) {
+		pod.ObjectMeta.ManagedFields = []metav1.ManagedFieldsEntry{{Manager: "kubectl"}}
+		pod.Spec.InitContainers = []v1.Container{{Name: "init", Env: []v1.EnvVar{{Name: "FOO", Value: "bar"}}}}
+		pod.Spec.Containers[0].Env = []v1.EnvVar{{Name: "FOO", Value: "bar"}}
+		pod.Spec.Containers[0].EnvFrom = []v1.EnvFromSource{{ConfigMapRef: &v1.ConfigMapEnvSource{}}}
+	})
+
+	sharedInformerFactory := newSharedInformerFactory(fakeclientset.NewSimpleClientset(pod))
+	podLister := sharedInformerFactory.Core().V1().Pods().Lister()
+	sharedInformerFactory.Start(ctx.Done())
+	sharedInformerFactory.WaitForCacheSync(ctx.Done())
+
+	cached, err := podLister.Pods(pod.Namespace).Get(pod.Name)
+	if err != nil {
+		t.Fatalf("Unable to get the pod from the informer cache: %v", err)
+	}
+	if len(cached.ManagedFields) != 0 {
+		t.Errorf("Expected managed fields to be trimmed, got %v", cached.ManagedFields)
+	}
+	for _, container := range append(cached.Spec.InitContainers, cached.Spec.Containers...) {
+		if len(container.Env) != 0 || len(container.EnvFrom) != 0 {
+			t.Errorf("Expected the environment of container %q to be trimmed", container.Name)
+		}
+	}
+	if cached.Spec.Containers[0].Resources.Requests.Cpu().MilliValue() != 100 {
+		t.Errorf("Expected the container resources to be kept")
+	}
+}
+
+func TestInformerListsPodsInPages(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var pods []v1.Pod
+	for i := 0; i < 1200; i++ {
+		pods = append(pods, *test.BuildTestPod(fmt.Sprintf("p%d", i), 100, 0, "n1", nil))
+	}
+
+	// serve the pods in pages of the requested size, as the API server
+	// does when listing from etcd.
+	var limits []int64
+	client := fakeclientset.NewSimpleClientset()
+	client.PrependReactor("list", "pods", func(action core.Action) (bool, runtime.Object, error) {
+		opts := action.(core.ListActionImpl).GetListOptions()
+		limits = append(limits, opts.Limit)
+		start := 0
+		if opts.Continue != "" {
+			fmt.Sscanf(opts.Continue, "%d", &start)
+		}
+		end := len(pods)
+		if opts.Limit > 0 && start+int(opts.Limit) < end {
+			end = start + int(opts.Limit)
+		}
+		list := &v1.PodList{Items: pods[start:end]}
+		if end < len(pods) {
+			list.Continue = fmt.Sprintf("%d", end)
+		}
+		return true, list, nil
+	})
+	watchOptions := make(chan metav1.ListOptions, 1)
+	client.PrependWatchReactor("pods", func(action core.Action) (bool, watch.Interface, error) {
+		select {
+		case watchOptions <- action.(core.WatchActionImpl).ListOptions:
+		default:
+		}
+		return false, nil, nil
+	})
+
+	sharedInformerFactory := newSharedInformerFactory(client)
+	podLister := sharedInformerFactory.Core().V1().Pods().Lister()
+	sharedInformerFactory.Start(ctx.Done())
+	sharedInformerFactory.WaitForCacheSync(ctx.Done())
+
+	cached, err := podLister.List(labels.Everything())
+	if err != nil {
+		t.Fatalf("Unable to list the pods from the informer cache: %v", err)
+	}
+	if len(cached) != len(pods) {
+		t.Errorf("Expected %v pods in the informer cache, got %v", len(pods), len(cached))
+	}
+	if !reflect.DeepEqual(limits, []int64{500, 500, 500}) {
+		t.Errorf("Expected the pods to be listed in 3 pages of 500, got requests with limits %v", limits)
+	}
+	select {
+	case opts := <-watchOptions:
+		if opts.Limit != 0 {
+			t.Errorf("Expected the pods to be watched without a limit, got %v", opts.Limit)
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("Expected the pods to be watched")
+	}
+}
+
+func TestRunUntilTerminationNotice(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	trigger := make(chan struct{}, 1)
+	runs := 0
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		runUntil(ctx, func() {
+			runs++
+			switch runs {
+			case 1:
+				trigger <- struct{}{}
+			case 2:
+				cancel()
+			}
+		}, time.Hour, trigger)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the termination notice to trigger a run ahead of the interval")
+	}
+	if runs != 2 {
+		t.Errorf("expected 2 runs, got %d", runs)
+	}
+}
+
+func TestRunUntilNonSliding(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// every run takes the whole period, the next one starts as soon as
+	// the previous one ends.
+	period := 100 * time.Millisecond
+	runs := 0
+	start := time.Now()
+	runUntil(ctx, func() {
+		time.Sleep(period)
+		if runs++; runs == 3 {
+			cancel()
+		}
+	}, period, nil)
+
+	if elapsed := time.Since(start); elapsed > 4*period+period/2 {
+		t.Errorf("expected the period to include the duration of the runs, 3 runs took %v", elapsed)
+	}
+}
+
+func TestTerminationNoticeHandler(t *testing.T) {
+	d := &descheduler{terminationNotices: make(chan struct{}, 1)}
+	handler := d.terminationNoticeHandler()
+
+	node := test.BuildTestNode("n1", 2000, 3000, 10, nil)
+	tainted := node.DeepCopy()
+	tainted.Spec.Taints = []v1.Taint{{Key: "aws-node-termination-handler/spot-itn", Effect: v1.TaintEffectNoSchedule}}
+
+	handler.OnUpdate(node, node)
+	if len(d.terminationNotices) != 0 {
+		t.Fatalf("expected no termination notice for an untainted node")
+	}
+
+	handler.OnUpdate(node, tainted)
+	handler.OnUpdate(tainted, tainted)
+	if len(d.terminationNotices) != 1 {
+		t.Fatalf("expected a single termination notice, got %d", len(d.terminationNotices))
+	}
+}
+
+func TestEvictionClientConnection(t *testing.T) {
+	clientConnection := componentbaseconfig.ClientConnectionConfiguration{QPS: 5, Burst: 10}
+
+	tests := []struct {
+		description   string
+		evictionQPS   float32
+		evictionBurst int32
+		expectedQPS   float32
+		expectedBurst int32
+	}{
+		{
+			description:   "unset limits fall back to the client connection",
+			expectedQPS:   5,
+			expectedBurst: 10,
+		},
+		{
+			description:   "set limits override the client connection",
+			evictionQPS:   50,
+			evictionBurst: 100,
+			expectedQPS:   50,
+			expectedBurst: 100,
+		},
+		{
+			description:   "limits are overridden independently",
+			evictionQPS:   50,
+			expectedQPS:   50,
+			expectedBurst: 10,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			got := evictionClientConnection(clientConnection, test.evictionQPS, test.evictionBurst)
+			if got.QPS != test.expectedQPS || got.Burst != test.expectedBurst {
+				t.Errorf("expected qps %v and burst %v, got qps %v and burst %v", test.expectedQPS, test.expectedBurst, got.QPS, got.Burst)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package evictions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/descheduler/metrics"
+	"sigs.k8s.io/descheduler/pkg/api"
+)
+
+const (
+	// EvictionEventType is the type of the CloudEvents emitted for every eviction.
+	EvictionEventType = "io.k8s.sigs.descheduler.eviction"
+	// CycleEventType is the type of the CloudEvents emitted at the end of every
+	// descheduling cycle.
+	CycleEventType = "io.k8s.sigs.descheduler.cycle"
+
+	defaultCloudEventsSource = "descheduler"
+	// cloudEventsQueueSize bounds the events waiting to be sent, events are
+	// dropped once the queue is full so a slow sink can not hold evictions.
+	cloudEventsQueueSize = 1024
+)
+
+// defaultCloudEventsTimeout is used when the cloud events timeout is not configured.
+var defaultCloudEventsTimeout = 10 * time.Second
+
+// cloudEvent is a CloudEvent in the structured content mode.
+type cloudEvent struct {
+	SpecVersion     string `json:"specversion"`
+	ID              string `json:"id"`
+	Source          string `json:"source"`
+	Type            string `json:"type"`
+	Subject         string `json:"subject,omitempty"`
+	Time            string `json:"time"`
+	DataContentType string `json:"datacontenttype"`
+	Data            any    `json:"data"`
+}
+
+// evictionEventData is the data of the eviction events.
+type evictionEventData struct {
+	Pod       string `json:"pod"`
+	Namespace string `json:"namespace"`
+	Node      string `json:"node,omitempty"`
+	Profile   string `json:"profile,omitempty"`
+	Strategy  string `json:"strategy,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+	DryRun    bool   `json:"dryRun"`
+}
+
+// cycleEventData is the data of the cycle events.
+type cycleEventData struct {
+	Evicted          uint            `json:"evicted"`
+	EvictionRequests uint            `json:"evictionRequests"`
+	PerStrategy      map[string]uint `json:"perStrategy,omitempty"`
+	DryRun           bool            `json:"dryRun"`
+}
+
+// eventExporter sends CloudEvents to an HTTP sink, other transports such as
+// Kafka are not supported. events are queued and sent in the background, in
+// the order they were emitted.
+type eventExporter struct {
+	url        string
+	source     string
+	dryRun     bool
+	httpClient *http.Client
+	queue      chan cloudEvent
+}
+
+// newEventExporter returns an exporter for the provided configuration, nil
+// if cloud events are not configured. events are sent until the context is
+// done.
+func newEventExporter(ctx context.Context, config *api.CloudEvents, dryRun bool) *eventExporter {
+	if config == nil {
+		return nil
+	}
+
+	timeout := defaultCloudEventsTimeout
+	if config.Timeout != nil {
+		timeout = config.Timeout.Duration
+	}
+
+	source := config.Source
+	if source == "" {
+		source = defaultCloudEventsSource
+	}
+
+	exporter := &eventExporter{
+		url:        config.SinkURL,
+		source:     source,
+		dryRun:     dryRun,
+		httpClient: &http.Client{Timeout: timeout},
+		queue:      make(chan cloudEvent, cloudEventsQueueSize),
+	}
+	go exporter.run(ctx)
+	return exporter
+}
+
+// podEvicted emits the event of an eviction.
+func (e *eventExporter) podEvicted(data evictionEventData) {
+	data.DryRun = e.dryRun
+	e.emit(EvictionEventType, data.Namespace+"/"+data.Pod, data)
+}
+
+// cycleFinished emits the summary of a descheduling cycle.
+func (e *eventExporter) cycleFinished(data cycleEventData) {
+	data.DryRun = e.dryRun
+	e.emit(CycleEventType, "", data)
+}
+
+func (e *eventExporter) emit(eventType, subject string, data any) {
+	event := cloudEvent{
+		SpecVersion:     "1.0",
+		ID:              string(uuid.NewUUID()),
+		Source:          e.source,
+		Type:            eventType,
+		Subject:         subject,
+		Time:            time.Now().UTC().Format(time.RFC3339Nano),
+		DataContentType: "application/json",
+		Data:            data,
+	}
+	select {
+	case e.queue <- event:
+	default:
+		klog.V(1).InfoS("CloudEvents queue is full, dropping event", "type", eventType, "subject", subject)
+		metrics.CloudEventsDropped.With(map[string]string{"type": eventType}).Inc()
+	}
+}
+
+func (e *eventExporter) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-e.queue:
+			if err := e.send(ctx, event); err != nil {
+				klog.ErrorS(err, "unable to send CloudEvent", "type", event.Type, "subject", event.Subject)
+			}
+		}
+	}
+}
+
+// send posts the event to the sink in the structured content mode.
+func (e *eventExporter) send(ctx context.Context, event cloudEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("unable to encode event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("unable to build event request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/cloudevents+json")
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to send event: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("sink returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package evictions
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"k8s.io/component-base/metrics/testutil"
+
+	"sigs.k8s.io/descheduler/metrics"
+	"sigs.k8s.io/descheduler/pkg/api"
+)
+
+func TestEventExporter(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	type received struct {
+		contentType string
+		event       map[string]any
+	}
+	events := make(chan received, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("unable to decode event: %v", err)
+		}
+		events <- received{contentType: r.Header.Get("Content-Type"), event: event}
+	}))
+	defer server.Close()
+
+	exporter := newEventExporter(ctx, &api.CloudEvents{SinkURL: server.URL, Source: "descheduler/test"}, true)
+	exporter.podEvicted(evictionEventData{Pod: "p1", Namespace: "default", Node: "n1", Strategy: "RemoveDuplicates"})
+	exporter.cycleFinished(cycleEventData{Evicted: 1, PerStrategy: map[string]uint{"RemoveDuplicates": 1}})
+
+	for _, expected := range []struct {
+		eventType string
+		subject   any
+		dataKey   string
+		dataValue any
+	}{
+		{eventType: EvictionEventType, subject: "default/p1", dataKey: "strategy", dataValue: "RemoveDuplicates"},
+		{eventType: CycleEventType, subject: nil, dataKey: "evicted", dataValue: float64(1)},
+	} {
+		select {
+		case got := <-events:
+			if got.contentType != "application/cloudevents+json" {
+				t.Errorf("unexpected content type %q", got.contentType)
+			}
+			if got.event["specversion"] != "1.0" || got.event["source"] != "descheduler/test" {
+				t.Errorf("unexpected event attributes %v", got.event)
+			}
+			if got.event["type"] != expected.eventType || got.event["subject"] != expected.subject {
+				t.Errorf("expected %s event for %v, got %v", expected.eventType, expected.subject, got.event)
+			}
+			data, _ := got.event["data"].(map[string]any)
+			if data[expected.dataKey] != expected.dataValue || data["dryRun"] != true {
+				t.Errorf("unexpected event data %v", data)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected a %s event", expected.eventType)
+		}
+	}
+
+	if newEventExporter(ctx, nil, false) != nil {
+		t.Errorf("expected no exporter without a configuration")
+	}
+}
+
+func TestEventExporterCountsDroppedEvents(t *testing.T) {
+	metrics.Register()
+
+	labels := map[string]string{"type": EvictionEventType}
+	before, err := testutil.GetCounterMetricValue(metrics.CloudEventsDropped.With(labels))
+	if err != nil {
+		t.Fatalf("unable to read counter: %v", err)
+	}
+
+	// nothing consumes the queue, the second event does not fit in it.
+	exporter := &eventExporter{source: defaultCloudEventsSource, queue: make(chan cloudEvent, 1)}
+	exporter.podEvicted(evictionEventData{Pod: "p1", Namespace: "default"})
+	exporter.podEvicted(evictionEventData{Pod: "p2", Namespace: "default"})
+
+	after, err := testutil.GetCounterMetricValue(metrics.CloudEventsDropped.With(labels))
+	if err != nil {
+		t.Fatalf("unable to read counter: %v", err)
+	}
+	if after-before != 1 {
+		t.Errorf("expected 1 dropped event to be counted, got %v", after-before)
+	}
+}
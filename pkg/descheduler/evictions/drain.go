@@ -0,0 +1,328 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package evictions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/descheduler/pkg/api"
+)
+
+var (
+	// defaultDrainTimeout is used when the connection draining timeout is not configured.
+	defaultDrainTimeout = 30 * time.Second
+	// defaultSessionDrainPeriod is used when the session draining period is not configured.
+	defaultSessionDrainPeriod = 30 * time.Second
+	// defaultDrainBudget is used when the draining budget is not configured.
+	defaultDrainBudget = 2 * time.Minute
+	// drainPollInterval controls how often the pod readiness is checked after the drain request.
+	drainPollInterval = time.Second
+)
+
+// errDrainBudgetExhausted is returned when a pod is not drained because the
+// draining budget of the cycle is exhausted.
+var errDrainBudgetExhausted = fmt.Errorf("draining budget of the cycle exhausted")
+
+// drainBudget is the time draining pods may still take during the current
+// descheduling cycle. the time is reserved before a pod is drained and the
+// unused part of the reservation is given back afterwards, so pods drained
+// concurrently never exceed the budget.
+type drainBudget struct {
+	mu        sync.Mutex
+	total     time.Duration
+	remaining time.Duration
+}
+
+// newDrainBudget returns a budget of the configured duration, the default one
+// if not configured.
+func newDrainBudget(config *metav1.Duration) *drainBudget {
+	total := defaultDrainBudget
+	if config != nil {
+		total = config.Duration
+	}
+	return &drainBudget{total: total, remaining: total}
+}
+
+// take reserves up to max of the remaining budget, zero is returned once the
+// budget is exhausted.
+func (b *drainBudget) take(max time.Duration) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	reserved := min(max, b.remaining)
+	b.remaining -= reserved
+	return reserved
+}
+
+// release gives back the part of a reservation that was not used.
+func (b *drainBudget) release(reserved, used time.Duration) {
+	if used >= reserved {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.remaining += reserved - used
+}
+
+// reset restores the whole budget, at the start of every cycle.
+func (b *drainBudget) reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.remaining = b.total
+}
+
+// connectionDrainer asks pods to drain their connections before they are evicted.
+type connectionDrainer struct {
+	port       int32
+	path       string
+	timeout    time.Duration
+	budget     *drainBudget
+	httpClient *http.Client
+}
+
+// newConnectionDrainer returns a drainer for the provided configuration, nil if connection
+// draining is not configured.
+func newConnectionDrainer(config *api.ConnectionDraining) *connectionDrainer {
+	if config == nil {
+		return nil
+	}
+
+	timeout := defaultDrainTimeout
+	if config.Timeout != nil {
+		timeout = config.Timeout.Duration
+	}
+
+	path := config.Path
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	return &connectionDrainer{
+		port:       config.Port,
+		path:       path,
+		timeout:    timeout,
+		budget:     newDrainBudget(config.Budget),
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// applies returns true if the pod exposes the drain port in one of its containers.
+func (d *connectionDrainer) applies(pod *v1.Pod) bool {
+	if pod.Status.PodIP == "" {
+		return false
+	}
+	for _, container := range pod.Spec.Containers {
+		for _, port := range container.Ports {
+			if port.ContainerPort == d.port {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// drain sends the drain request to the pod and waits for the pod to stop being ready, or to
+// be gone, until the timeout expires or the draining budget of the cycle is exhausted.
+func (d *connectionDrainer) drain(ctx context.Context, client clientset.Interface, pod *v1.Pod) error {
+	reserved := d.budget.take(d.timeout)
+	if reserved <= 0 {
+		return errDrainBudgetExhausted
+	}
+	start := time.Now()
+	defer func() { d.budget.release(reserved, time.Since(start)) }()
+
+	ctx, cancel := context.WithTimeout(ctx, reserved)
+	defer cancel()
+
+	endpoint := "http://" + net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(int(d.port))) + d.path
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("unable to build drain request: %w", err)
+	}
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to send drain request: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("drain request returned status %d", resp.StatusCode)
+	}
+
+	err = wait.PollUntilContextCancel(ctx, drainPollInterval, true, func(ctx context.Context) (bool, error) {
+		current, err := client.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		if err != nil {
+			return false, nil
+		}
+		return !isPodReady(current), nil
+	})
+	if err != nil {
+		return fmt.Errorf("pod still ready after drain request: %w", err)
+	}
+	return nil
+}
+
+// isPodReady returns true if the pod ready condition is true.
+func isPodReady(pod *v1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// sessionDrainer takes pods out of rotation, by setting their readiness gate
+// to false, and waits for the load balancers to stop sending them new
+// sessions before they are evicted.
+type sessionDrainer struct {
+	readinessGate v1.PodConditionType
+	annotation    string
+	period        time.Duration
+	budget        *drainBudget
+}
+
+// newSessionDrainer returns a drainer for the provided configuration, nil if
+// session draining is not configured.
+func newSessionDrainer(config *api.SessionDraining) *sessionDrainer {
+	if config == nil {
+		return nil
+	}
+
+	period := defaultSessionDrainPeriod
+	if config.Period != nil {
+		period = config.Period.Duration
+	}
+
+	return &sessionDrainer{
+		readinessGate: v1.PodConditionType(config.ReadinessGate),
+		annotation:    config.Annotation,
+		period:        period,
+		budget:        newDrainBudget(config.Budget),
+	}
+}
+
+// applies returns true if the pod declares the readiness gate.
+func (d *sessionDrainer) applies(pod *v1.Pod) bool {
+	for _, gate := range pod.Spec.ReadinessGates {
+		if gate.ConditionType == d.readinessGate {
+			return true
+		}
+	}
+	return false
+}
+
+// drain sets the readiness gate condition of the pod to false, and the
+// annotation if configured, then waits for the drain period, or for what is
+// left of the draining budget of the cycle. pods are left untouched once the
+// budget is exhausted.
+func (d *sessionDrainer) drain(ctx context.Context, client clientset.Interface, pod *v1.Pod) error {
+	reserved := d.budget.take(d.period)
+	if reserved <= 0 {
+		return errDrainBudgetExhausted
+	}
+	start := time.Now()
+	defer func() { d.budget.release(reserved, time.Since(start)) }()
+
+	if err := d.setReadinessGate(ctx, client, pod, v1.ConditionFalse, "Descheduled", "pod is about to be evicted by the descheduler"); err != nil {
+		return err
+	}
+	if err := d.setAnnotation(ctx, client, pod, ptr.To("true")); err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(reserved):
+		return nil
+	}
+}
+
+// undrain puts back into rotation a pod whose eviction failed after it was
+// drained: the readiness gate condition is set back to true and the
+// annotation, if configured, removed.
+func (d *sessionDrainer) undrain(ctx context.Context, client clientset.Interface, pod *v1.Pod) error {
+	if err := d.setReadinessGate(ctx, client, pod, v1.ConditionTrue, "EvictionFailed", "pod eviction by the descheduler failed"); err != nil {
+		return err
+	}
+	return d.setAnnotation(ctx, client, pod, nil)
+}
+
+// setReadinessGate sets the readiness gate condition of the pod.
+func (d *sessionDrainer) setReadinessGate(ctx context.Context, client clientset.Interface, pod *v1.Pod, status v1.ConditionStatus, reason, message string) error {
+	patch, err := json.Marshal(map[string]any{
+		"status": map[string]any{
+			"conditions": []v1.PodCondition{{
+				Type:               d.readinessGate,
+				Status:             status,
+				Reason:             reason,
+				Message:            message,
+				LastTransitionTime: metav1.Now(),
+			}},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := client.CoreV1().Pods(pod.Namespace).Patch(
+		ctx, pod.Name, types.StrategicMergePatchType, patch, metav1.PatchOptions{}, "status",
+	); err != nil {
+		return fmt.Errorf("unable to set readiness gate: %w", err)
+	}
+	return nil
+}
+
+// setAnnotation sets the annotation of the pod, if configured, to the value
+// or removes it when the value is nil.
+func (d *sessionDrainer) setAnnotation(ctx context.Context, client clientset.Interface, pod *v1.Pod, value *string) error {
+	if d.annotation == "" {
+		return nil
+	}
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"annotations": map[string]*string{d.annotation: value},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := client.CoreV1().Pods(pod.Namespace).Patch(
+		ctx, pod.Name, types.MergePatchType, patch, metav1.PatchOptions{},
+	); err != nil {
+		return fmt.Errorf("unable to set annotation: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,266 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package evictions
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	core "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/events"
+
+	"sigs.k8s.io/descheduler/pkg/api"
+	"sigs.k8s.io/descheduler/test"
+)
+
+func TestConnectionDrainer(t *testing.T) {
+	drainPollInterval = 10 * time.Millisecond
+
+	for _, tc := range []struct {
+		description string
+		status      int
+		unready     bool
+		wantErr     bool
+	}{
+		{
+			description: "pod stops being ready after the drain request",
+			status:      http.StatusOK,
+			unready:     true,
+		},
+		{
+			description: "pod remains ready after the drain request",
+			status:      http.StatusOK,
+			wantErr:     true,
+		},
+		{
+			description: "drain request fails",
+			status:      http.StatusInternalServerError,
+			unready:     true,
+			wantErr:     true,
+		},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			ctx := context.Background()
+			client := fake.NewClientset()
+
+			var method, path string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				method, path = r.Method, r.URL.RequestURI()
+				if tc.unready {
+					pod, _ := client.CoreV1().Pods("default").Get(ctx, "p1", metav1.GetOptions{})
+					pod.Status.Conditions = []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionFalse}}
+					client.CoreV1().Pods("default").UpdateStatus(ctx, pod, metav1.UpdateOptions{})
+				}
+				w.WriteHeader(tc.status)
+			}))
+			defer server.Close()
+
+			host, rawPort, _ := net.SplitHostPort(server.Listener.Addr().String())
+			port, _ := strconv.Atoi(rawPort)
+
+			pod := test.BuildTestPod("p1", 100, 0, "node1", func(pod *v1.Pod) {
+				pod.Status.PodIP = host
+				pod.Status.Conditions = []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}}
+				pod.Spec.Containers[0].Ports = []v1.ContainerPort{{ContainerPort: int32(port)}}
+			})
+			if _, err := client.CoreV1().Pods("default").Create(ctx, pod, metav1.CreateOptions{}); err != nil {
+				t.Fatalf("unable to create pod: %v", err)
+			}
+
+			drainer := newConnectionDrainer(&api.ConnectionDraining{
+				Port:    int32(port),
+				Path:    "drain_listeners?graceful",
+				Timeout: &metav1.Duration{Duration: 200 * time.Millisecond},
+			})
+			if !drainer.applies(pod) {
+				t.Fatalf("expected the drainer to apply to the pod")
+			}
+
+			err := drainer.drain(ctx, client, pod)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected error %t, got %v", tc.wantErr, err)
+			}
+			if method != http.MethodPost || path != "/drain_listeners?graceful" {
+				t.Errorf("unexpected drain request %s %s", method, path)
+			}
+		})
+	}
+}
+
+func TestDrainBudget(t *testing.T) {
+	budget := newDrainBudget(&metav1.Duration{Duration: time.Minute})
+
+	if reserved := budget.take(40 * time.Second); reserved != 40*time.Second {
+		t.Fatalf("expected 40s to be reserved, got %v", reserved)
+	}
+	if reserved := budget.take(40 * time.Second); reserved != 20*time.Second {
+		t.Fatalf("expected the 20s left to be reserved, got %v", reserved)
+	}
+	budget.release(20*time.Second, 5*time.Second)
+	if reserved := budget.take(40 * time.Second); reserved != 15*time.Second {
+		t.Fatalf("expected the 15s released to be reserved, got %v", reserved)
+	}
+	if reserved := budget.take(time.Second); reserved != 0 {
+		t.Fatalf("expected the budget to be exhausted, got %v reserved", reserved)
+	}
+
+	budget.reset()
+	if reserved := budget.take(40 * time.Second); reserved != 40*time.Second {
+		t.Fatalf("expected the budget to be restored, got %v reserved", reserved)
+	}
+
+	drainer := newConnectionDrainer(&api.ConnectionDraining{Port: 15000, Budget: &metav1.Duration{}})
+	if err := drainer.drain(context.Background(), fake.NewClientset(), test.BuildTestPod("p1", 100, 0, "node1", nil)); err != errDrainBudgetExhausted {
+		t.Errorf("expected no pod to be drained without a budget, got %v", err)
+	}
+}
+
+func TestConnectionDrainerApplies(t *testing.T) {
+	drainer := newConnectionDrainer(&api.ConnectionDraining{Port: 15000})
+
+	pod := test.BuildTestPod("p1", 100, 0, "node1", func(pod *v1.Pod) {
+		pod.Status.PodIP = "10.0.0.1"
+		pod.Spec.Containers[0].Ports = []v1.ContainerPort{{ContainerPort: 8080}}
+	})
+	if drainer.applies(pod) {
+		t.Errorf("expected the drainer not to apply to a pod not exposing the drain port")
+	}
+
+	pod.Spec.Containers[0].Ports = append(pod.Spec.Containers[0].Ports, v1.ContainerPort{ContainerPort: 15000})
+	if !drainer.applies(pod) {
+		t.Errorf("expected the drainer to apply to a pod exposing the drain port")
+	}
+
+	pod.Status.PodIP = ""
+	if drainer.applies(pod) {
+		t.Errorf("expected the drainer not to apply to a pod without an IP")
+	}
+
+	if newConnectionDrainer(nil) != nil {
+		t.Errorf("expected no drainer without a configuration")
+	}
+}
+
+func TestSessionDrainer(t *testing.T) {
+	ctx := context.Background()
+
+	gate := v1.PodConditionType("example.com/serving")
+	pod := test.BuildTestPod("p1", 100, 0, "node1", func(pod *v1.Pod) {
+		pod.Spec.ReadinessGates = []v1.PodReadinessGate{{ConditionType: gate}}
+		pod.Status.Conditions = []v1.PodCondition{
+			{Type: v1.PodReady, Status: v1.ConditionTrue},
+			{Type: gate, Status: v1.ConditionTrue},
+		}
+	})
+	client := fake.NewClientset(pod)
+
+	drainer := newSessionDrainer(&api.SessionDraining{
+		ReadinessGate: string(gate),
+		Annotation:    "example.com/draining",
+		Period:        &metav1.Duration{Duration: 10 * time.Millisecond},
+	})
+	if !drainer.applies(pod) {
+		t.Fatalf("expected the drainer to apply to a pod declaring the readiness gate")
+	}
+	if drainer.applies(test.BuildTestPod("p2", 100, 0, "node1", nil)) {
+		t.Errorf("expected the drainer not to apply to a pod without the readiness gate")
+	}
+
+	if err := drainer.drain(ctx, client, pod); err != nil {
+		t.Fatalf("unexpected error draining the pod: %v", err)
+	}
+
+	drained, err := client.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unable to get pod: %v", err)
+	}
+	if drained.Annotations["example.com/draining"] != "true" {
+		t.Errorf("expected the pod to be annotated, got %v", drained.Annotations)
+	}
+	for _, condition := range drained.Status.Conditions {
+		if condition.Type == gate && condition.Status != v1.ConditionFalse {
+			t.Errorf("expected the readiness gate to be false, got %s", condition.Status)
+		}
+		if condition.Type == v1.PodReady && condition.Status != v1.ConditionTrue {
+			t.Errorf("expected the other conditions to be preserved")
+		}
+	}
+
+	if newSessionDrainer(nil) != nil {
+		t.Errorf("expected no drainer without a configuration")
+	}
+}
+
+func TestSessionDrainerFailedEviction(t *testing.T) {
+	ctx := context.Background()
+
+	gate := v1.PodConditionType("example.com/serving")
+	pod := test.BuildTestPod("p1", 100, 0, "node1", func(pod *v1.Pod) {
+		pod.UID = "p1"
+		pod.Spec.ReadinessGates = []v1.PodReadinessGate{{ConditionType: gate}}
+		pod.Status.Conditions = []v1.PodCondition{{Type: gate, Status: v1.ConditionTrue}}
+	})
+	client := fake.NewClientset(pod)
+	client.PrependReactor("create", "pods/eviction", func(action core.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewTooManyRequests("disruption budget exceeded", 10)
+	})
+
+	factory := informers.NewSharedInformerFactory(client, 0)
+	podEvictor, err := NewPodEvictor(
+		ctx,
+		client,
+		&events.FakeRecorder{},
+		factory.Core().V1().Pods().Informer(),
+		initFeatureGates(),
+		NewOptions().WithSessionDraining(&api.SessionDraining{
+			ReadinessGate: string(gate),
+			Annotation:    "example.com/draining",
+			Period:        &metav1.Duration{Duration: 10 * time.Millisecond},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error when creating a pod evictor: %v", err)
+	}
+
+	if err := podEvictor.EvictPod(ctx, pod, EvictOptions{}); err == nil {
+		t.Fatalf("expected the eviction to fail")
+	}
+
+	current, err := client.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unable to get pod: %v", err)
+	}
+	if _, ok := current.Annotations["example.com/draining"]; ok {
+		t.Errorf("expected the annotation to be removed, got %v", current.Annotations)
+	}
+	for _, condition := range current.Status.Conditions {
+		if condition.Type == gate && condition.Status != v1.ConditionTrue {
+			t.Errorf("expected the readiness gate to be set back to true, got %s", condition.Status)
+		}
+	}
+}
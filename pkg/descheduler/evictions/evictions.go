@@ -19,6 +19,8 @@ package evictions
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -29,6 +31,8 @@ import (
 	policy "k8s.io/api/policy/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	clientset "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
@@ -215,9 +219,16 @@ type PodEvictor struct {
 	maxPodsToEvictPerNamespace       *uint
 	maxPodsToEvictTotal              *uint
 	gracePeriodSeconds               *int64
+	drainer                          *connectionDrainer
+	sessionDrainer                   *sessionDrainer
+	notifier                         *notifier
+	exporter                         *eventExporter
 	nodePodCount                     nodePodEvictedCount
 	namespacePodCount                namespacePodEvictCount
+	strategyPodCount                 map[string]uint
 	totalPodCount                    uint
+	evictedPods                      sets.Set[types.UID]
+	decisions                        []Decision
 	metricsEnabled                   bool
 	eventRecorder                    events.EventRecorder
 	erCache                          *evictionRequestsCache
@@ -249,9 +260,15 @@ func NewPodEvictor(
 		maxPodsToEvictPerNamespace:       options.maxPodsToEvictPerNamespace,
 		maxPodsToEvictTotal:              options.maxPodsToEvictTotal,
 		gracePeriodSeconds:               options.gracePeriodSeconds,
+		drainer:                          newConnectionDrainer(options.connectionDraining),
+		sessionDrainer:                   newSessionDrainer(options.sessionDraining),
+		notifier:                         newNotifier(options.notifications, options.dryRun),
+		exporter:                         newEventExporter(ctx, options.cloudEvents, options.dryRun),
 		metricsEnabled:                   options.metricsEnabled,
 		nodePodCount:                     make(nodePodEvictedCount),
 		namespacePodCount:                make(namespacePodEvictCount),
+		strategyPodCount:                 map[string]uint{},
+		evictedPods:                      sets.New[types.UID](),
 		featureGates:                     featureGates,
 	}
 
@@ -389,12 +406,73 @@ func (pe *PodEvictor) TotalEvicted() uint {
 	return pe.totalPodCount
 }
 
+// WasEvicted returns true if the pod has been evicted since the counters
+// were last reset.
+func (pe *PodEvictor) WasEvicted(pod *v1.Pod) bool {
+	pe.mu.RLock()
+	defer pe.mu.RUnlock()
+	return pe.evictedPods.Has(pod.UID)
+}
+
+// DryRun returns true if evictions are only simulated.
+func (pe *PodEvictor) DryRun() bool {
+	return pe.dryRun
+}
+
 func (pe *PodEvictor) ResetCounters() {
 	pe.mu.Lock()
 	defer pe.mu.Unlock()
 	pe.nodePodCount = make(nodePodEvictedCount)
 	pe.namespacePodCount = make(namespacePodEvictCount)
+	pe.strategyPodCount = map[string]uint{}
 	pe.totalPodCount = 0
+	pe.evictedPods = sets.New[types.UID]()
+	pe.decisions = nil
+	if pe.drainer != nil {
+		pe.drainer.budget.reset()
+	}
+	if pe.sessionDrainer != nil {
+		pe.sessionDrainer.budget.reset()
+	}
+	if pe.notifier != nil {
+		pe.notifier.reset()
+	}
+}
+
+// Decision describes a pod evicted, or evicted in dry run mode, since the
+// counters were last reset.
+type Decision struct {
+	Pod       string `json:"pod"`
+	Namespace string `json:"namespace"`
+	Node      string `json:"node,omitempty"`
+	Profile   string `json:"profile,omitempty"`
+	Strategy  string `json:"strategy,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// Decisions returns the evictions performed since the counters were last
+// reset, in the order they were performed.
+func (pe *PodEvictor) Decisions() []Decision {
+	pe.mu.RLock()
+	defer pe.mu.RUnlock()
+	return slices.Clone(pe.decisions)
+}
+
+// NotifyCycleSummary reports the evictions performed since the counters were
+// last reset to the configured notification webhook and CloudEvents sink.
+func (pe *PodEvictor) NotifyCycleSummary(ctx context.Context) {
+	pe.mu.Lock()
+	defer pe.mu.Unlock()
+	if pe.notifier != nil {
+		pe.notifier.summarize(ctx, pe.totalPodCount, pe.strategyPodCount)
+	}
+	if pe.exporter != nil {
+		pe.exporter.cycleFinished(cycleEventData{
+			Evicted:          pe.totalPodCount,
+			EvictionRequests: pe.evictionRequestsTotal(),
+			PerStrategy:      maps.Clone(pe.strategyPodCount),
+		})
+	}
 }
 
 func (pe *PodEvictor) SetClient(client clientset.Interface) {
@@ -451,6 +529,29 @@ type EvictOptions struct {
 	ProfileName string
 	// StrategyName allows for passing details about strategy for observability.
 	StrategyName string
+	// Details allows for passing the context the pod was selected for
+	// eviction in, e.g. the usage of its node. It is appended to the
+	// message of the eviction event.
+	Details string
+}
+
+// maxEventNoteLength is the maximum length of the message of an event
+// accepted by the api server.
+const maxEventNoteLength = 1024
+
+// evictionEventNote returns the message of the event emitted for a pod
+// evicted from the node, the details are truncated if the message would
+// otherwise be refused.
+func evictionEventNote(node, details string) string {
+	note := fmt.Sprintf("pod eviction from %v node by sigs.k8s.io/descheduler", node)
+	if len(details) == 0 {
+		return note
+	}
+	note = fmt.Sprintf("%s: %s", note, details)
+	if len(note) > maxEventNoteLength {
+		note = note[:maxEventNoteLength-3] + "..."
+	}
+	return note
 }
 
 // EvictPod evicts a pod while exercising eviction limits.
@@ -471,56 +572,47 @@ func (pe *PodEvictor) EvictPod(ctx context.Context, pod *v1.Pod, opts EvictOptio
 		}
 	}
 
-	pe.mu.Lock()
-	defer pe.mu.Unlock()
-
 	var span trace.Span
 	ctx, span = tracing.Tracer().Start(ctx, "EvictPod", trace.WithAttributes(attribute.String("podName", pod.Name), attribute.String("podNamespace", pod.Namespace), attribute.String("reason", opts.Reason), attribute.String("operation", tracing.EvictOperation)))
 	defer span.End()
 
-	if pe.maxPodsToEvictTotal != nil && pe.totalPodCount+pe.evictionRequestsTotal()+1 > *pe.maxPodsToEvictTotal {
-		err := NewEvictionTotalLimitError()
-		if pe.metricsEnabled {
-			metrics.PodsEvicted.With(map[string]string{"result": err.Error(), "strategy": opts.StrategyName, "namespace": pod.Namespace, "node": pod.Spec.NodeName, "profile": opts.ProfileName}).Inc()
-		}
-		span.AddEvent("Eviction Failed", trace.WithAttributes(attribute.String("node", pod.Spec.NodeName), attribute.String("err", err.Error())))
-		klog.ErrorS(err, "Error evicting pod", "limit", *pe.maxPodsToEvictTotal)
-		if pe.evictionFailureEventNotification {
-			pe.eventRecorder.Eventf(pod, nil, v1.EventTypeWarning, "EvictionFailed", "Descheduled", "pod eviction from %v node by sigs.k8s.io/descheduler failed: total eviction limit exceeded (%v)", pod.Spec.NodeName, *pe.maxPodsToEvictTotal)
-		}
-		return err
-	}
-
-	if pod.Spec.NodeName != "" {
-		if pe.maxPodsToEvictPerNode != nil && pe.nodePodCount[pod.Spec.NodeName]+pe.evictionRequestsPerNode(pod.Spec.NodeName)+1 > *pe.maxPodsToEvictPerNode {
-			err := NewEvictionNodeLimitError(pod.Spec.NodeName)
-			if pe.metricsEnabled {
-				metrics.PodsEvicted.With(map[string]string{"result": err.Error(), "strategy": opts.StrategyName, "namespace": pod.Namespace, "node": pod.Spec.NodeName, "profile": opts.ProfileName}).Inc()
-			}
-			span.AddEvent("Eviction Failed", trace.WithAttributes(attribute.String("node", pod.Spec.NodeName), attribute.String("err", err.Error())))
-			klog.ErrorS(err, "Error evicting pod", "limit", *pe.maxPodsToEvictPerNode, "node", pod.Spec.NodeName)
-			if pe.evictionFailureEventNotification {
-				pe.eventRecorder.Eventf(pod, nil, v1.EventTypeWarning, "EvictionFailed", "Descheduled", "pod eviction from %v node by sigs.k8s.io/descheduler failed: node eviction limit exceeded (%v)", pod.Spec.NodeName, *pe.maxPodsToEvictPerNode)
-			}
+	// draining may take a while, it is done without holding the lock so
+	// the counters and the other evictions are not blocked meanwhile. the
+	// limits are checked before so pods that are not to be evicted are not
+	// drained, and once more after the drain.
+	var sessionDrained bool
+	if pe.drains(pod) {
+		pe.mu.RLock()
+		client := pe.client
+		err := pe.checkLimits(pod, opts, span)
+		pe.mu.RUnlock()
+		if err != nil {
 			return err
 		}
+		sessionDrained = pe.drainPod(ctx, client, pod)
 	}
 
-	if pe.maxPodsToEvictPerNamespace != nil && pe.namespacePodCount[pod.Namespace]+pe.evictionRequestsPerNamespace(pod.Namespace)+1 > *pe.maxPodsToEvictPerNamespace {
-		err := NewEvictionNamespaceLimitError(pod.Namespace)
-		if pe.metricsEnabled {
-			metrics.PodsEvicted.With(map[string]string{"result": err.Error(), "strategy": opts.StrategyName, "namespace": pod.Namespace, "node": pod.Spec.NodeName, "profile": opts.ProfileName}).Inc()
+	pe.mu.Lock()
+	defer pe.mu.Unlock()
+
+	// pods drained but not evicted are put back into rotation.
+	undrain := func() {
+		if !sessionDrained {
+			return
 		}
-		span.AddEvent("Eviction Failed", trace.WithAttributes(attribute.String("node", pod.Spec.NodeName), attribute.String("err", err.Error())))
-		klog.ErrorS(err, "Error evicting pod", "limit", *pe.maxPodsToEvictPerNamespace, "namespace", pod.Namespace, "pod", klog.KObj(pod))
-		if pe.evictionFailureEventNotification {
-			pe.eventRecorder.Eventf(pod, nil, v1.EventTypeWarning, "EvictionFailed", "Descheduled", "pod eviction from %v node by sigs.k8s.io/descheduler failed: namespace eviction limit exceeded (%v)", pod.Spec.NodeName, *pe.maxPodsToEvictPerNamespace)
+		if err := pe.sessionDrainer.undrain(ctx, pe.client, pod); err != nil {
+			klog.ErrorS(err, "Unable to put the pod back into rotation after a failed eviction", "pod", klog.KObj(pod))
 		}
+	}
+
+	if err := pe.checkLimits(pod, opts, span); err != nil {
+		undrain()
 		return err
 	}
 
 	ignore, err := pe.evictPod(ctx, pod)
 	if err != nil {
+		undrain()
 		// err is used only for logging purposes
 		span.AddEvent("Eviction Failed", trace.WithAttributes(attribute.String("node", pod.Spec.NodeName), attribute.String("err", err.Error())))
 		klog.ErrorS(err, "Error evicting pod", "pod", klog.KObj(pod), "reason", opts.Reason)
@@ -542,6 +634,35 @@ func (pe *PodEvictor) EvictPod(ctx context.Context, pod *v1.Pod, opts EvictOptio
 	}
 	pe.namespacePodCount[pod.Namespace]++
 	pe.totalPodCount++
+	pe.evictedPods.Insert(pod.UID)
+
+	strategy := opts.StrategyName
+	if len(strategy) == 0 {
+		strategy = "NotSet"
+	}
+	pe.strategyPodCount[strategy]++
+	pe.decisions = append(pe.decisions, Decision{
+		Pod:       pod.Name,
+		Namespace: pod.Namespace,
+		Node:      pod.Spec.NodeName,
+		Profile:   opts.ProfileName,
+		Strategy:  opts.StrategyName,
+		Reason:    opts.Reason,
+	})
+
+	if pe.notifier != nil {
+		pe.notifier.podEvicted(ctx, pe.totalPodCount, pe.strategyPodCount)
+	}
+	if pe.exporter != nil {
+		pe.exporter.podEvicted(evictionEventData{
+			Pod:       pod.Name,
+			Namespace: pod.Namespace,
+			Node:      pod.Spec.NodeName,
+			Profile:   opts.ProfileName,
+			Strategy:  opts.StrategyName,
+			Reason:    opts.Reason,
+		})
+	}
 
 	if pe.metricsEnabled {
 		metrics.PodsEvicted.With(map[string]string{"result": "success", "strategy": opts.StrategyName, "namespace": pod.Namespace, "node": pod.Spec.NodeName, "profile": opts.ProfileName}).Inc()
@@ -558,11 +679,90 @@ func (pe *PodEvictor) EvictPod(ctx context.Context, pod *v1.Pod, opts EvictOptio
 				reason = "NotSet"
 			}
 		}
-		pe.eventRecorder.Eventf(pod, nil, v1.EventTypeNormal, reason, "Descheduled", "pod eviction from %v node by sigs.k8s.io/descheduler", pod.Spec.NodeName)
+		pe.eventRecorder.Eventf(pod, nil, v1.EventTypeNormal, reason, "Descheduled", "%s", evictionEventNote(pod.Spec.NodeName, opts.Details))
 	}
 	return nil
 }
 
+// checkLimits returns an error if evicting the pod would exceed any of the
+// eviction limits. the caller is expected to hold the lock.
+func (pe *PodEvictor) checkLimits(pod *v1.Pod, opts EvictOptions, span trace.Span) error {
+	if pe.maxPodsToEvictTotal != nil && pe.totalPodCount+pe.evictionRequestsTotal()+1 > *pe.maxPodsToEvictTotal {
+		err := NewEvictionTotalLimitError()
+		if pe.metricsEnabled {
+			metrics.PodsEvicted.With(map[string]string{"result": err.Error(), "strategy": opts.StrategyName, "namespace": pod.Namespace, "node": pod.Spec.NodeName, "profile": opts.ProfileName}).Inc()
+		}
+		span.AddEvent("Eviction Failed", trace.WithAttributes(attribute.String("node", pod.Spec.NodeName), attribute.String("err", err.Error())))
+		klog.ErrorS(err, "Error evicting pod", "limit", *pe.maxPodsToEvictTotal)
+		if pe.evictionFailureEventNotification {
+			pe.eventRecorder.Eventf(pod, nil, v1.EventTypeWarning, "EvictionFailed", "Descheduled", "pod eviction from %v node by sigs.k8s.io/descheduler failed: total eviction limit exceeded (%v)", pod.Spec.NodeName, *pe.maxPodsToEvictTotal)
+		}
+		return err
+	}
+
+	if pod.Spec.NodeName != "" {
+		if pe.maxPodsToEvictPerNode != nil && pe.nodePodCount[pod.Spec.NodeName]+pe.evictionRequestsPerNode(pod.Spec.NodeName)+1 > *pe.maxPodsToEvictPerNode {
+			err := NewEvictionNodeLimitError(pod.Spec.NodeName)
+			if pe.metricsEnabled {
+				metrics.PodsEvicted.With(map[string]string{"result": err.Error(), "strategy": opts.StrategyName, "namespace": pod.Namespace, "node": pod.Spec.NodeName, "profile": opts.ProfileName}).Inc()
+			}
+			span.AddEvent("Eviction Failed", trace.WithAttributes(attribute.String("node", pod.Spec.NodeName), attribute.String("err", err.Error())))
+			klog.ErrorS(err, "Error evicting pod", "limit", *pe.maxPodsToEvictPerNode, "node", pod.Spec.NodeName)
+			if pe.evictionFailureEventNotification {
+				pe.eventRecorder.Eventf(pod, nil, v1.EventTypeWarning, "EvictionFailed", "Descheduled", "pod eviction from %v node by sigs.k8s.io/descheduler failed: node eviction limit exceeded (%v)", pod.Spec.NodeName, *pe.maxPodsToEvictPerNode)
+			}
+			return err
+		}
+	}
+
+	if pe.maxPodsToEvictPerNamespace != nil && pe.namespacePodCount[pod.Namespace]+pe.evictionRequestsPerNamespace(pod.Namespace)+1 > *pe.maxPodsToEvictPerNamespace {
+		err := NewEvictionNamespaceLimitError(pod.Namespace)
+		if pe.metricsEnabled {
+			metrics.PodsEvicted.With(map[string]string{"result": err.Error(), "strategy": opts.StrategyName, "namespace": pod.Namespace, "node": pod.Spec.NodeName, "profile": opts.ProfileName}).Inc()
+		}
+		span.AddEvent("Eviction Failed", trace.WithAttributes(attribute.String("node", pod.Spec.NodeName), attribute.String("err", err.Error())))
+		klog.ErrorS(err, "Error evicting pod", "limit", *pe.maxPodsToEvictPerNamespace, "namespace", pod.Namespace, "pod", klog.KObj(pod))
+		if pe.evictionFailureEventNotification {
+			pe.eventRecorder.Eventf(pod, nil, v1.EventTypeWarning, "EvictionFailed", "Descheduled", "pod eviction from %v node by sigs.k8s.io/descheduler failed: namespace eviction limit exceeded (%v)", pod.Spec.NodeName, *pe.maxPodsToEvictPerNamespace)
+		}
+		return err
+	}
+	return nil
+}
+
+// drains tells if the pod is drained before it is evicted.
+func (pe *PodEvictor) drains(pod *v1.Pod) bool {
+	if pe.dryRun {
+		return false
+	}
+	return (pe.sessionDrainer != nil && pe.sessionDrainer.applies(pod)) || (pe.drainer != nil && pe.drainer.applies(pod))
+}
+
+// drainPod drains the sessions and the connections of the pod and returns
+// whether the pod was taken out of rotation. draining is best effort, the pod
+// is evicted regardless. a pod asked to drain its connections can not be told
+// otherwise, if its eviction then fails it keeps running drained until it is
+// evicted in a later cycle.
+func (pe *PodEvictor) drainPod(ctx context.Context, client clientset.Interface, pod *v1.Pod) bool {
+	var sessionDrained bool
+	if pe.sessionDrainer != nil && pe.sessionDrainer.applies(pod) {
+		err := pe.sessionDrainer.drain(ctx, client, pod)
+		if err != nil {
+			klog.V(3).InfoS("Unable to drain pod sessions before eviction", "pod", klog.KObj(pod), "err", err)
+		}
+		// the pod may be out of rotation even if the drain period was
+		// interrupted.
+		sessionDrained = err != errDrainBudgetExhausted
+	}
+
+	if pe.drainer != nil && pe.drainer.applies(pod) {
+		if err := pe.drainer.drain(ctx, client, pod); err != nil {
+			klog.V(3).InfoS("Unable to drain pod connections before eviction", "pod", klog.KObj(pod), "err", err)
+		}
+	}
+	return sessionDrained
+}
+
 // return (ignore, err)
 func (pe *PodEvictor) evictPod(ctx context.Context, pod *v1.Pod) (bool, error) {
 	deleteOptions := &metav1.DeleteOptions{
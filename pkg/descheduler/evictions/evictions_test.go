@@ -179,6 +179,7 @@ func TestNewPodEvictor(t *testing.T) {
 		maxPodsToEvictTotal              *uint
 		maxPodsToEvictPerNode            *uint
 		maxPodsToEvictPerNamespace       *uint
+		opts                             EvictOptions
 		expectedNodeEvictions            uint
 		expectedTotalEvictions           uint
 		expectedError                    error
@@ -200,6 +201,18 @@ func TestNewPodEvictor(t *testing.T) {
 			expectedError:                    nil,
 			events:                           []string{"Normal NotSet pod eviction from node node by sigs.k8s.io/descheduler"},
 		},
+		{
+			description:                "one eviction expected with details",
+			pod:                        pod1,
+			maxPodsToEvictTotal:        utilptr.To[uint](1),
+			maxPodsToEvictPerNode:      utilptr.To[uint](1),
+			maxPodsToEvictPerNamespace: utilptr.To[uint](1),
+			opts:                       EvictOptions{StrategyName: "LowNodeUtilization", Details: "node overutilized"},
+			expectedNodeEvictions:      1,
+			expectedTotalEvictions:     1,
+			expectedError:              nil,
+			events:                     []string{"Normal LowNodeUtilization pod eviction from node node by sigs.k8s.io/descheduler: node overutilized"},
+		},
 		{
 			description:                      "eviction limit exceeded on total with eviction failure event notification",
 			pod:                              pod1,
@@ -355,7 +368,7 @@ func TestNewPodEvictor(t *testing.T) {
 
 			stubNode := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node"}}
 
-			if actualErr := podEvictor.EvictPod(ctx, test.pod, EvictOptions{}); actualErr != nil && actualErr.Error() != test.expectedError.Error() {
+			if actualErr := podEvictor.EvictPod(ctx, test.pod, test.opts); actualErr != nil && actualErr.Error() != test.expectedError.Error() {
 				t.Errorf("Expected error: %v, got: %v", test.expectedError, actualErr)
 			}
 
@@ -0,0 +1,148 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package evictions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/descheduler/pkg/api"
+)
+
+// defaultNotificationTimeout is used when the notifications timeout is not configured.
+var defaultNotificationTimeout = 10 * time.Second
+
+// notifier posts eviction summaries and alerts to a webhook. it is not safe
+// for concurrent use, the pod evictor serializes the calls.
+type notifier struct {
+	url              string
+	summaryThreshold uint
+	alertThreshold   *uint
+	dryRun           bool
+	httpClient       *http.Client
+	alertPosted      bool
+}
+
+// newNotifier returns a notifier for the provided configuration, nil if
+// notifications are not configured.
+func newNotifier(config *api.Notifications, dryRun bool) *notifier {
+	if config == nil {
+		return nil
+	}
+
+	timeout := defaultNotificationTimeout
+	if config.Timeout != nil {
+		timeout = config.Timeout.Duration
+	}
+
+	summaryThreshold := uint(1)
+	if config.SummaryThreshold != nil {
+		summaryThreshold = *config.SummaryThreshold
+	}
+
+	return &notifier{
+		url:              config.WebhookURL,
+		summaryThreshold: summaryThreshold,
+		alertThreshold:   config.AlertThreshold,
+		dryRun:           dryRun,
+		httpClient:       &http.Client{Timeout: timeout},
+	}
+}
+
+// reset prepares the notifier for a new cycle.
+func (n *notifier) reset() {
+	n.alertPosted = false
+}
+
+// podEvicted is called after every eviction with the number of pods evicted
+// during the cycle, in total and per strategy. once the total crosses the
+// alert threshold an alert is posted, in the background so evictions are not
+// held back by the webhook.
+func (n *notifier) podEvicted(ctx context.Context, total uint, perStrategy map[string]uint) {
+	if n.alertThreshold == nil || n.alertPosted || total < *n.alertThreshold {
+		return
+	}
+	n.alertPosted = true
+	text := fmt.Sprintf("%s evicted %d pods so far in the current cycle: %s", n.sender(), total, breakdown(perStrategy))
+	go n.post(context.WithoutCancel(ctx), text)
+}
+
+// summarize posts the summary of the cycle if enough pods were evicted.
+func (n *notifier) summarize(ctx context.Context, total uint, perStrategy map[string]uint) {
+	if total == 0 || total < n.summaryThreshold {
+		return
+	}
+	n.post(ctx, fmt.Sprintf("%s evicted %d pods during the last cycle: %s", n.sender(), total, breakdown(perStrategy)))
+}
+
+// sender names the descheduler in the messages, dry runs are called out.
+func (n *notifier) sender() string {
+	if n.dryRun {
+		return "Descheduler (dry run)"
+	}
+	return "Descheduler"
+}
+
+// breakdown lists the number of pods evicted by each strategy.
+func breakdown(perStrategy map[string]uint) string {
+	strategies := make([]string, 0, len(perStrategy))
+	for strategy := range perStrategy {
+		strategies = append(strategies, strategy)
+	}
+	sort.Strings(strategies)
+
+	parts := make([]string, 0, len(strategies))
+	for _, strategy := range strategies {
+		parts = append(parts, fmt.Sprintf("%s: %d", strategy, perStrategy[strategy]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// post sends the message to the webhook using a Slack compatible payload.
+// notifications are best effort, failures are only logged.
+func (n *notifier) post(ctx context.Context, text string) {
+	body, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		klog.ErrorS(err, "unable to encode notification")
+		return
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		klog.ErrorS(err, "unable to build notification request")
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		klog.ErrorS(err, "unable to post notification")
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		klog.ErrorS(fmt.Errorf("webhook returned status %d", resp.StatusCode), "unable to post notification")
+	}
+}
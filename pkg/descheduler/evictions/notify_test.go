@@ -0,0 +1,95 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package evictions
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/descheduler/pkg/api"
+)
+
+func TestNotifier(t *testing.T) {
+	ctx := context.Background()
+
+	messages := make(chan string, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Text string `json:"text"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("unable to decode notification: %v", err)
+		}
+		messages <- payload.Text
+	}))
+	defer server.Close()
+
+	n := newNotifier(&api.Notifications{
+		WebhookURL:       server.URL,
+		SummaryThreshold: ptr.To[uint](3),
+		AlertThreshold:   ptr.To[uint](2),
+	}, false)
+
+	perStrategy := map[string]uint{"RemoveDuplicates": 1}
+	n.podEvicted(ctx, 1, perStrategy)
+	n.summarize(ctx, 1, perStrategy)
+	select {
+	case msg := <-messages:
+		t.Fatalf("unexpected notification below the thresholds: %q", msg)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	perStrategy["LowNodeUtilization"] = 1
+	n.podEvicted(ctx, 2, perStrategy)
+	select {
+	case msg := <-messages:
+		expected := "Descheduler evicted 2 pods so far in the current cycle: LowNodeUtilization: 1, RemoveDuplicates: 1"
+		if msg != expected {
+			t.Errorf("expected alert %q, got %q", expected, msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected an alert once the alert threshold was crossed")
+	}
+
+	// the alert is posted once per cycle.
+	perStrategy["LowNodeUtilization"] = 2
+	n.podEvicted(ctx, 3, perStrategy)
+	n.summarize(ctx, 3, perStrategy)
+	msg := <-messages
+	expected := "Descheduler evicted 3 pods during the last cycle: LowNodeUtilization: 2, RemoveDuplicates: 1"
+	if msg != expected {
+		t.Errorf("expected summary %q, got %q", expected, msg)
+	}
+
+	n.reset()
+	n.summarize(ctx, 0, map[string]uint{})
+	select {
+	case msg := <-messages:
+		t.Fatalf("unexpected notification without evictions: %q", msg)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	if newNotifier(nil, false) != nil {
+		t.Errorf("expected no notifier without a configuration")
+	}
+}
@@ -2,6 +2,8 @@ package evictions
 
 import (
 	policy "k8s.io/api/policy/v1"
+
+	"sigs.k8s.io/descheduler/pkg/api"
 )
 
 type Options struct {
@@ -13,6 +15,10 @@ type Options struct {
 	evictionFailureEventNotification bool
 	metricsEnabled                   bool
 	gracePeriodSeconds               *int64
+	connectionDraining               *api.ConnectionDraining
+	sessionDraining                  *api.SessionDraining
+	notifications                    *api.Notifications
+	cloudEvents                      *api.CloudEvents
 }
 
 // NewOptions returns an Options with default values.
@@ -52,6 +58,26 @@ func (o *Options) WithGracePeriodSeconds(gracePeriodSeconds *int64) *Options {
 	return o
 }
 
+func (o *Options) WithConnectionDraining(connectionDraining *api.ConnectionDraining) *Options {
+	o.connectionDraining = connectionDraining
+	return o
+}
+
+func (o *Options) WithSessionDraining(sessionDraining *api.SessionDraining) *Options {
+	o.sessionDraining = sessionDraining
+	return o
+}
+
+func (o *Options) WithNotifications(notifications *api.Notifications) *Options {
+	o.notifications = notifications
+	return o
+}
+
+func (o *Options) WithCloudEvents(cloudEvents *api.CloudEvents) *Options {
+	o.cloudEvents = cloudEvents
+	return o
+}
+
 func (o *Options) WithMetricsEnabled(metricsEnabled bool) *Options {
 	o.metricsEnabled = metricsEnabled
 	return o
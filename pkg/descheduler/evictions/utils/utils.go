@@ -28,6 +28,12 @@ const (
 	// Each plugin will decide whether the soft preference will be respected.
 	// If configured the soft preference turns into a mandatory no-eviction policy for the DefaultEvictor plugin.
 	SoftNoEvictionAnnotationKey = "descheduler.alpha.kubernetes.io/prefer-no-eviction"
+	// SafeToEvictAnnotationKey set to "false" protects a pod from eviction.
+	// Set to "true" it overrides the cluster autoscaler annotation.
+	SafeToEvictAnnotationKey = "descheduler.alpha.kubernetes.io/safe-to-evict"
+	// ClusterAutoscalerSafeToEvictAnnotationKey set to "false" marks a pod
+	// the cluster autoscaler does not evict, the descheduler honors it too.
+	ClusterAutoscalerSafeToEvictAnnotationKey = "cluster-autoscaler.kubernetes.io/safe-to-evict"
 )
 
 // SupportEviction uses Discovery API to find out if the server support eviction subresource
@@ -67,3 +73,14 @@ func HaveNoEvictionAnnotation(pod *corev1.Pod) bool {
 	_, found := pod.ObjectMeta.Annotations[SoftNoEvictionAnnotationKey]
 	return found
 }
+
+// HaveUnsafeToEvictAnnotation checks if the pod is marked as not safe to
+// evict, either for the descheduler or for the cluster autoscaler. The
+// descheduler annotation takes precedence so pods the autoscaler does not
+// evict can still be descheduled.
+func HaveUnsafeToEvictAnnotation(pod *corev1.Pod) bool {
+	if value, found := pod.ObjectMeta.Annotations[SafeToEvictAnnotationKey]; found {
+		return value == "false"
+	}
+	return pod.ObjectMeta.Annotations[ClusterAutoscalerSafeToEvictAnnotationKey] == "false"
+}
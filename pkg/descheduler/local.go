@@ -0,0 +1,176 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package descheduler
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/events"
+	componentbaseconfig "k8s.io/component-base/config"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/descheduler/cmd/descheduler/app/options"
+	"sigs.k8s.io/descheduler/pkg/api"
+	"sigs.k8s.io/descheduler/pkg/descheduler/client"
+	eutils "sigs.k8s.io/descheduler/pkg/descheduler/evictions/utils"
+	"sigs.k8s.io/descheduler/pkg/framework/pluginregistry"
+)
+
+// LocalOptions configures how a descheduler running out of the cluster
+// reaches the metrics sources of the policy.
+type LocalOptions struct {
+	// PrometheusURL overrides the url of the Prometheus metrics provider,
+	// e.g. to query a Prometheus port-forwarded by hand.
+	PrometheusURL string
+	// PrometheusService is a namespace/name[:port] service a local port is
+	// forwarded to, the Prometheus metrics provider is queried through it.
+	// It takes precedence over PrometheusURL.
+	PrometheusService string
+	// PrometheusAuthToken authenticates the Prometheus queries. When empty
+	// the token is read from the secret referred by the policy, if any.
+	PrometheusAuthToken string
+}
+
+// RunLocal runs a single descheduling cycle of the policy against the
+// cluster of the kubeconfig, in dry run mode, and reports the decisions
+// taken. It is meant to evaluate a policy from outside of the cluster: no
+// pod is evicted and no event is emitted. The kubeconfig defaults to the
+// one kubectl uses. Node and pod metrics are read through the API server,
+// Prometheus is reached through a port-forward or an overridden url.
+func RunLocal(ctx context.Context, rs *options.DeschedulerServer, opts LocalOptions) (*DecisionReport, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	rs.DryRun = true
+	rs.DisableMetrics = true
+
+	clientConnection := rs.ClientConnection
+	if rs.KubeconfigFile != "" && clientConnection.Kubeconfig == "" {
+		clientConnection.Kubeconfig = rs.KubeconfigFile
+	}
+	if clientConnection.Kubeconfig == "" {
+		clientConnection.Kubeconfig = localKubeconfig()
+	}
+	rsclient, eventClient, evictionClient, err := createClients(clientConnection, rs.EvictionClientQPS, rs.EvictionClientBurst)
+	if err != nil {
+		return nil, err
+	}
+	rs.Client = rsclient
+	rs.EventClient = eventClient
+	rs.EvictionClient = evictionClient
+
+	deschedulerPolicy, err := LoadPolicyConfig(rs.PolicyConfigFile, rs.Client, pluginregistry.PluginRegistry)
+	if err != nil {
+		return nil, err
+	}
+	if deschedulerPolicy == nil {
+		return nil, fmt.Errorf("deschedulerPolicy is nil")
+	}
+
+	evictionPolicyGroupVersion, err := eutils.SupportEviction(rs.Client)
+	if err != nil {
+		return nil, err
+	}
+	if len(evictionPolicyGroupVersion) == 0 {
+		return nil, fmt.Errorf("the cluster does not support the eviction API")
+	}
+
+	if usesKubernetesMetrics(deschedulerPolicy) {
+		metricsClient, err := client.CreateMetricsClient(clientConnection, "descheduler")
+		if err != nil {
+			return nil, err
+		}
+		rs.MetricsClient = metricsClient
+	}
+
+	if err := setupLocalPrometheusClient(ctx, rs, clientConnection, deschedulerPolicy, opts); err != nil {
+		return nil, err
+	}
+
+	sharedInformerFactory := newSharedInformerFactory(rs.Client)
+	desch, err := newDescheduler(ctx, rs, deschedulerPolicy, evictionPolicyGroupVersion, &events.FakeRecorder{}, sharedInformerFactory, nil)
+	if err != nil {
+		return nil, err
+	}
+	sharedInformerFactory.Start(ctx.Done())
+	sharedInformerFactory.WaitForCacheSync(ctx.Done())
+
+	return runReportedCycle(ctx, desch, rs.Client, deschedulerPolicy)
+}
+
+// localKubeconfig returns the kubeconfig kubectl would use.
+func localKubeconfig() string {
+	if kubeconfig := os.Getenv(clientcmd.RecommendedConfigPathEnvVar); kubeconfig != "" {
+		return kubeconfig
+	}
+	return clientcmd.RecommendedHomeFile
+}
+
+// setupLocalPrometheusClient creates the Prometheus client of the policy
+// Prometheus metrics provider, if any. The client is created once for the
+// run, the token of the secret referred by the policy is read right away
+// rather than watched.
+func setupLocalPrometheusClient(ctx context.Context, rs *options.DeschedulerServer, clientConnection componentbaseconfig.ClientConnectionConfiguration, deschedulerPolicy *api.DeschedulerPolicy, opts LocalOptions) error {
+	prometheusProvider := metricsProviderListToMap(deschedulerPolicy.MetricsProviders)[api.PrometheusMetrics]
+	if prometheusProvider == nil || prometheusProvider.Prometheus == nil {
+		return nil
+	}
+
+	prometheusURL := prometheusProvider.Prometheus.URL
+	switch {
+	case opts.PrometheusService != "":
+		ref, err := client.ParseServiceReference(opts.PrometheusService)
+		if err != nil {
+			return err
+		}
+		port, err := client.PortForwardService(ctx, clientConnection, ref)
+		if err != nil {
+			return err
+		}
+		prometheusURL = fmt.Sprintf("http://localhost:%d", port)
+		klog.V(1).InfoS("Forwarding a local port to Prometheus", "service", ref, "url", prometheusURL)
+	case opts.PrometheusURL != "":
+		prometheusURL = opts.PrometheusURL
+	}
+
+	authToken := opts.PrometheusAuthToken
+	if authToken == "" && prometheusProvider.Prometheus.AuthToken != nil && prometheusProvider.Prometheus.AuthToken.SecretReference != nil {
+		ref := prometheusProvider.Prometheus.AuthToken.SecretReference
+		secret, err := rs.Client.CoreV1().Secrets(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("unable to get %v/%v secret: %v", ref.Namespace, ref.Name, err)
+		}
+		authToken = string(secret.Data[prometheusAuthTokenSecretKey])
+		if authToken == "" {
+			return fmt.Errorf("prometheus authentication token secret missing %q data or empty", prometheusAuthTokenSecretKey)
+		}
+	}
+	// the token is resolved once, the reference is dropped from the policy
+	// so the descheduler does not watch the secret.
+	prometheusProvider.Prometheus.AuthToken = nil
+
+	prometheusClient, err := client.CreateLocalPrometheusClient(prometheusURL, authToken, prometheusClientOptions(prometheusProvider.Prometheus))
+	if err != nil {
+		return fmt.Errorf("unable to create a prometheus client: %v", err)
+	}
+	rs.PrometheusClient = prometheusClient
+	return nil
+}
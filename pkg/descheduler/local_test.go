@@ -0,0 +1,187 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package descheduler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakeclientset "k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/descheduler/cmd/descheduler/app/options"
+	"sigs.k8s.io/descheduler/pkg/api"
+)
+
+func TestSetupLocalPrometheusClient(t *testing.T) {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "prometheus-token"},
+		Data:       map[string][]byte{prometheusAuthTokenSecretKey: []byte("secret-token")},
+	}
+
+	tests := []struct {
+		name          string
+		authToken     *api.AuthToken
+		opts          LocalOptions
+		expectedToken string
+	}{
+		{
+			name:          "token read from the secret of the policy",
+			authToken:     &api.AuthToken{SecretReference: &api.SecretReference{Namespace: "kube-system", Name: "prometheus-token"}},
+			expectedToken: "Bearer secret-token",
+		},
+		{
+			name:          "token overridden",
+			authToken:     &api.AuthToken{SecretReference: &api.SecretReference{Namespace: "kube-system", Name: "prometheus-token"}},
+			opts:          LocalOptions{PrometheusAuthToken: "flag-token"},
+			expectedToken: "Bearer flag-token",
+		},
+		{
+			name: "no token",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var authorization string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				authorization = r.Header.Get("Authorization")
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+			}))
+			defer server.Close()
+
+			rs, err := options.NewDeschedulerServer()
+			if err != nil {
+				t.Fatalf("unable to initialize server: %v", err)
+			}
+			rs.Client = fakeclientset.NewSimpleClientset(secret)
+			deschedulerPolicy := &api.DeschedulerPolicy{
+				MetricsProviders: []api.MetricsProvider{{
+					Source: api.PrometheusMetrics,
+					Prometheus: &api.Prometheus{
+						URL:       "https://prometheus.monitoring.svc:9090",
+						AuthToken: tc.authToken,
+					},
+				}},
+			}
+			tc.opts.PrometheusURL = server.URL
+
+			if err := setupLocalPrometheusClient(context.Background(), rs, rs.ClientConnection, deschedulerPolicy, tc.opts); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if deschedulerPolicy.MetricsProviders[0].Prometheus.AuthToken != nil {
+				t.Errorf("expected the token reference to be dropped from the policy")
+			}
+			if rs.PrometheusClient == nil {
+				t.Fatalf("expected a prometheus client")
+			}
+			if _, _, err := promv1.NewAPI(rs.PrometheusClient).Query(context.Background(), "up", time.Now()); err != nil {
+				t.Fatalf("unexpected query error: %v", err)
+			}
+			if authorization != tc.expectedToken {
+				t.Errorf("expected %q authorization, got %q instead", tc.expectedToken, authorization)
+			}
+		})
+	}
+}
+
+func TestSetupLocalPrometheusClientMissingSecret(t *testing.T) {
+	rs, err := options.NewDeschedulerServer()
+	if err != nil {
+		t.Fatalf("unable to initialize server: %v", err)
+	}
+	rs.Client = fakeclientset.NewSimpleClientset()
+	deschedulerPolicy := &api.DeschedulerPolicy{
+		MetricsProviders: []api.MetricsProvider{{
+			Source: api.PrometheusMetrics,
+			Prometheus: &api.Prometheus{
+				URL:       "https://prometheus.monitoring.svc:9090",
+				AuthToken: &api.AuthToken{SecretReference: &api.SecretReference{Namespace: "kube-system", Name: "prometheus-token"}},
+			},
+		}},
+	}
+	if err := setupLocalPrometheusClient(context.Background(), rs, rs.ClientConnection, deschedulerPolicy, LocalOptions{}); err == nil {
+		t.Fatalf("expected an error for a missing secret")
+	}
+	if rs.PrometheusClient != nil {
+		t.Errorf("expected no prometheus client")
+	}
+}
+
+func TestRunLocalWithoutEvictionSupport(t *testing.T) {
+	// an api server without the policy group, hence no eviction api.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api":
+			w.Write([]byte(`{"kind":"APIVersions","versions":["v1"]}`))
+		case "/apis":
+			w.Write([]byte(`{"kind":"APIGroupList","groups":[]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	kubeconfig := filepath.Join(dir, "kubeconfig")
+	if err := os.WriteFile(kubeconfig, []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: local
+  cluster:
+    server: `+server.URL+`
+contexts:
+- name: local
+  context:
+    cluster: local
+current-context: local
+`), 0o600); err != nil {
+		t.Fatalf("unable to write the kubeconfig: %v", err)
+	}
+	policy := filepath.Join(dir, "policy.yaml")
+	if err := os.WriteFile(policy, []byte(`apiVersion: "descheduler/v1alpha2"
+kind: "DeschedulerPolicy"
+profiles: []
+`), 0o600); err != nil {
+		t.Fatalf("unable to write the policy: %v", err)
+	}
+
+	rs, err := options.NewDeschedulerServer()
+	if err != nil {
+		t.Fatalf("unable to initialize server: %v", err)
+	}
+	rs.KubeconfigFile = kubeconfig
+	rs.PolicyConfigFile = policy
+
+	report, err := RunLocal(context.Background(), rs, LocalOptions{})
+	if err == nil || !strings.Contains(err.Error(), "eviction API") {
+		t.Fatalf("expected an error for a cluster without the eviction api, got %v", err)
+	}
+	if report != nil {
+		t.Errorf("expected no report, got %v", report)
+	}
+}
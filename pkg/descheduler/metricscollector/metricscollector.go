@@ -37,14 +37,33 @@ import (
 
 const (
 	beta float64 = 0.9
+
+	// DefaultSmoothingFactor is the weight of the latest sample in the
+	// usage reported by AllNodesUsage and NodeUsage.
+	DefaultSmoothingFactor = 1 - beta
+
+	// MaxSamples is the number of raw samples kept for each node. At the
+	// 5s collection interval it covers the last five minutes, older samples
+	// weigh less than 1% with the default smoothing factor.
+	MaxSamples = 60
 )
 
+// usageSample is a raw node usage sample, cpu in millicores and memory in
+// bytes.
+type usageSample struct {
+	cpu    int64
+	memory int64
+}
+
 type MetricsCollector struct {
 	nodeLister       listercorev1.NodeLister
 	metricsClientset metricsclient.Interface
 	nodeSelector     labels.Selector
 
 	nodes map[string]api.ReferencedResourceList
+	// samples keeps the latest raw samples of each node, oldest first, so
+	// the usage can be smoothed with other factors than the default one.
+	samples map[string][]usageSample
 
 	mu sync.RWMutex
 	// hasSynced signals at least one sync succeeded
@@ -57,6 +76,7 @@ func NewMetricsCollector(nodeLister listercorev1.NodeLister, metricsClientset me
 		metricsClientset: metricsClientset,
 		nodeSelector:     nodeSelector,
 		nodes:            make(map[string]api.ReferencedResourceList),
+		samples:          make(map[string][]usageSample),
 	}
 }
 
@@ -107,6 +127,39 @@ func (mc *MetricsCollector) NodeUsage(node *v1.Node) (api.ReferencedResourceList
 	}, nil
 }
 
+// SmoothedNodesUsage returns the usage of the nodes as the exponentially
+// weighted moving average of the samples kept for them, factor being the
+// weight of the latest sample. A factor of 1 returns the latest sample.
+func (mc *MetricsCollector) SmoothedNodesUsage(factor float64) map[string]api.ReferencedResourceList {
+	mc.mu.RLock()
+	defer mc.mu.RUnlock()
+
+	allNodesUsage := make(map[string]api.ReferencedResourceList)
+	for nodeName, samples := range mc.samples {
+		if len(samples) == 0 {
+			continue
+		}
+		cpu, memory := float64(samples[0].cpu), float64(samples[0].memory)
+		for _, sample := range samples[1:] {
+			cpu = (1-factor)*cpu + factor*float64(sample.cpu)
+			memory = (1-factor)*memory + factor*float64(sample.memory)
+		}
+		allNodesUsage[nodeName] = api.ReferencedResourceList{
+			v1.ResourceCPU:    resource.NewMilliQuantity(int64(math.Round(cpu)), resource.DecimalSI),
+			v1.ResourceMemory: resource.NewQuantity(int64(math.Round(memory)), resource.BinarySI),
+		}
+	}
+	return allNodesUsage
+}
+
+// NodeSamples returns the number of samples kept for the node, at most
+// MaxSamples.
+func (mc *MetricsCollector) NodeSamples(nodeName string) int {
+	mc.mu.RLock()
+	defer mc.mu.RUnlock()
+	return len(mc.samples[nodeName])
+}
+
 func (mc *MetricsCollector) HasSynced() bool {
 	return mc.hasSynced
 }
@@ -131,6 +184,15 @@ func (mc *MetricsCollector) Collect(ctx context.Context) error {
 			continue
 		}
 
+		samples := mc.samples[node.Name]
+		if len(samples) == MaxSamples {
+			samples = append(samples[:0], samples[1:]...)
+		}
+		mc.samples[node.Name] = append(samples, usageSample{
+			cpu:    metrics.Usage.Cpu().MilliValue(),
+			memory: metrics.Usage.Memory().Value(),
+		})
+
 		if _, exists := mc.nodes[node.Name]; !exists {
 			mc.nodes[node.Name] = api.ReferencedResourceList{
 				v1.ResourceCPU:    utilptr.To[resource.Quantity](metrics.Usage.Cpu().DeepCopy()),
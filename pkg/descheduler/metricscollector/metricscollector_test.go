@@ -140,3 +140,49 @@ func TestMetricsCollectorConvergence(t *testing.T) {
 		t.Fatalf("The node usage did not converged to 900+-1")
 	}
 }
+
+func TestMetricsCollectorSmoothedNodesUsage(t *testing.T) {
+	gvr := schema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "nodes"}
+
+	n1 := test.BuildTestNode("n1", 2000, 3000, 10, nil)
+	n1metrics := test.BuildNodeMetrics("n1", 1400, 1714978816)
+
+	clientset := fakeclientset.NewSimpleClientset(n1)
+	metricsClientset := fakemetricsclient.NewSimpleClientset()
+	metricsClientset.Tracker().Create(gvr, n1metrics, "")
+
+	ctx := context.TODO()
+	sharedInformerFactory := informers.NewSharedInformerFactory(clientset, 0)
+	nodeLister := sharedInformerFactory.Core().V1().Nodes().Lister()
+	sharedInformerFactory.Start(ctx.Done())
+	sharedInformerFactory.WaitForCacheSync(ctx.Done())
+
+	collector := NewMetricsCollector(nodeLister, metricsClientset, labels.Everything())
+	if samples := collector.NodeSamples(n1.Name); samples != 0 {
+		t.Fatalf("expected no samples before collecting, got %v", samples)
+	}
+
+	for _, cpu := range []int64{1400, 500, 900} {
+		n1metrics.Usage[v1.ResourceCPU] = *resource.NewMilliQuantity(cpu, resource.DecimalSI)
+		metricsClientset.Tracker().Update(gvr, n1metrics, "")
+		collector.Collect(ctx)
+	}
+
+	t.Logf("The default smoothing factor matches the usage reported by NodeUsage")
+	checkCpuNodeUsage(t, collector.SmoothedNodesUsage(DefaultSmoothingFactor)[n1.Name], 1269)
+	t.Logf("A factor of 0.5 weighs the latest sample as much as the previous ones")
+	checkCpuNodeUsage(t, collector.SmoothedNodesUsage(0.5)[n1.Name], 925)
+	t.Logf("A factor of 1 reports the latest sample")
+	checkCpuNodeUsage(t, collector.SmoothedNodesUsage(1)[n1.Name], 900)
+
+	if samples := collector.NodeSamples(n1.Name); samples != 3 {
+		t.Fatalf("expected 3 samples, got %v", samples)
+	}
+	for i := 0; i < MaxSamples; i++ {
+		collector.Collect(ctx)
+	}
+	if samples := collector.NodeSamples(n1.Name); samples != MaxSamples {
+		t.Fatalf("expected samples to be capped at %v, got %v", MaxSamples, samples)
+	}
+	checkCpuNodeUsage(t, collector.SmoothedNodesUsage(0.5)[n1.Name], 900)
+}
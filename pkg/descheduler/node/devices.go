@@ -0,0 +1,191 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"errors"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	resourcev1beta1 "k8s.io/api/resource/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/informers"
+	resourcelisters "k8s.io/client-go/listers/resource/v1beta1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	utilptr "k8s.io/utils/ptr"
+)
+
+// DeviceAccounting accounts the devices handed out through Dynamic Resource
+// Allocation (DRA) so they can be compared to the node capacity as any other
+// resource. Devices are counted per driver and reported under the name of
+// the driver, e.g. gpu.example.com: the capacity of a node is the number of
+// devices its resource slices publish, the request of a pod is the number of
+// devices allocated to its resource claims.
+//
+// A nil DeviceAccounting accounts no device.
+type DeviceAccounting struct {
+	sliceIndexer cache.Indexer
+	claimLister  resourcelisters.ResourceClaimLister
+}
+
+// resourceSliceNodeNameIndex indexes the resource slices by the name of the
+// node they are local to.
+const resourceSliceNodeNameIndex = "spec.nodeName"
+
+// NewDeviceAccounting registers the resource slices and resource claims
+// informers in the factory and returns a DeviceAccounting reading from them.
+// It must be called before the factory is started.
+func NewDeviceAccounting(sharedInformerFactory informers.SharedInformerFactory) (*DeviceAccounting, error) {
+	sliceInformer := sharedInformerFactory.Resource().V1beta1().ResourceSlices().Informer()
+	if _, ok := sliceInformer.GetIndexer().GetIndexers()[resourceSliceNodeNameIndex]; !ok {
+		if err := sliceInformer.AddIndexers(cache.Indexers{
+			resourceSliceNodeNameIndex: func(obj interface{}) ([]string, error) {
+				slice, ok := obj.(*resourcev1beta1.ResourceSlice)
+				if !ok {
+					return []string{}, errors.New("unexpected object")
+				}
+				if slice.Spec.NodeName == "" {
+					return []string{}, nil
+				}
+				return []string{slice.Spec.NodeName}, nil
+			},
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	return &DeviceAccounting{
+		sliceIndexer: sliceInformer.GetIndexer(),
+		claimLister:  sharedInformerFactory.Resource().V1beta1().ResourceClaims().Lister(),
+	}, nil
+}
+
+// NodeCapacity returns the number of devices, per driver, local to the node.
+// Devices of resource slices not bound to a single node (e.g. network
+// attached devices) are not part of any node capacity. Only the latest
+// generation of every pool is counted.
+func (d *DeviceAccounting) NodeCapacity(node *v1.Node) v1.ResourceList {
+	if d == nil {
+		return nil
+	}
+	objs, err := d.sliceIndexer.ByIndex(resourceSliceNodeNameIndex, node.Name)
+	if err != nil {
+		klog.V(4).InfoS("Unable to list resource slices", "node", klog.KObj(node), "err", err)
+		return nil
+	}
+
+	type pool struct {
+		driver, name string
+	}
+	generations := make(map[pool]int64)
+	local := make([]*resourcev1beta1.ResourceSlice, 0, len(objs))
+	for _, obj := range objs {
+		slice, ok := obj.(*resourcev1beta1.ResourceSlice)
+		if !ok {
+			continue
+		}
+		key := pool{driver: slice.Spec.Driver, name: slice.Spec.Pool.Name}
+		if generation, ok := generations[key]; !ok || slice.Spec.Pool.Generation > generation {
+			generations[key] = slice.Spec.Pool.Generation
+		}
+		local = append(local, slice)
+	}
+
+	capacity := v1.ResourceList{}
+	for _, slice := range local {
+		key := pool{driver: slice.Spec.Driver, name: slice.Spec.Pool.Name}
+		if slice.Spec.Pool.Generation < generations[key] {
+			continue
+		}
+		addDevices(capacity, slice.Spec.Driver, int64(len(slice.Spec.Devices)))
+	}
+	return capacity
+}
+
+// PodRequests returns the number of devices, per driver, allocated to the
+// resource claims of the pod. Claims not allocated yet, or not created yet,
+// request no device. Devices allocated with admin access are not exclusive
+// to the claim and are not counted.
+func (d *DeviceAccounting) PodRequests(pod *v1.Pod) v1.ResourceList {
+	if d == nil || len(pod.Spec.ResourceClaims) == 0 {
+		return nil
+	}
+	requests := v1.ResourceList{}
+	for _, podClaim := range pod.Spec.ResourceClaims {
+		claimName := podResourceClaimName(pod, podClaim)
+		if claimName == "" {
+			continue
+		}
+		claim, err := d.claimLister.ResourceClaims(pod.Namespace).Get(claimName)
+		if err != nil {
+			klog.V(4).InfoS("Unable to get resource claim", "pod", klog.KObj(pod), "claim", claimName, "err", err)
+			continue
+		}
+		if claim.Status.Allocation == nil {
+			continue
+		}
+		for _, result := range claim.Status.Allocation.Devices.Results {
+			if utilptr.Deref(result.AdminAccess, false) {
+				continue
+			}
+			addDevices(requests, result.Driver, 1)
+		}
+	}
+	return requests
+}
+
+// PodClaimsVersion returns the resource versions of the resource claims of
+// the pod, it changes whenever the devices allocated to the pod may change.
+// Claims not created yet have no version.
+func (d *DeviceAccounting) PodClaimsVersion(pod *v1.Pod) string {
+	if d == nil || len(pod.Spec.ResourceClaims) == 0 {
+		return ""
+	}
+	versions := make([]string, 0, len(pod.Spec.ResourceClaims))
+	for _, podClaim := range pod.Spec.ResourceClaims {
+		version := ""
+		if claimName := podResourceClaimName(pod, podClaim); claimName != "" {
+			if claim, err := d.claimLister.ResourceClaims(pod.Namespace).Get(claimName); err == nil {
+				version = claim.ResourceVersion
+			}
+		}
+		versions = append(versions, version)
+	}
+	return strings.Join(versions, ",")
+}
+
+// podResourceClaimName returns the name of the resource claim a claim of the
+// pod refers to. Claims created from a template are named in the pod status.
+func podResourceClaimName(pod *v1.Pod, podClaim v1.PodResourceClaim) string {
+	if podClaim.ResourceClaimName != nil {
+		return *podClaim.ResourceClaimName
+	}
+	for _, status := range pod.Status.ResourceClaimStatuses {
+		if status.Name == podClaim.Name {
+			return utilptr.Deref(status.ResourceClaimName, "")
+		}
+	}
+	return ""
+}
+
+func addDevices(list v1.ResourceList, driver string, count int64) {
+	name := v1.ResourceName(driver)
+	quantity := list[name]
+	quantity.Add(*resource.NewQuantity(count, resource.DecimalSI))
+	list[name] = quantity
+}
@@ -0,0 +1,372 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	resourcev1beta1 "k8s.io/api/resource/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	utilptr "k8s.io/utils/ptr"
+
+	podutil "sigs.k8s.io/descheduler/pkg/descheduler/pod"
+	"sigs.k8s.io/descheduler/test"
+)
+
+func buildTestResourceSlice(name, nodeName, driver, pool string, generation int64, devices int) *resourcev1beta1.ResourceSlice {
+	slice := &resourcev1beta1.ResourceSlice{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: resourcev1beta1.ResourceSliceSpec{
+			Driver:   driver,
+			NodeName: nodeName,
+			Pool: resourcev1beta1.ResourcePool{
+				Name:               pool,
+				Generation:         generation,
+				ResourceSliceCount: 1,
+			},
+		},
+	}
+	for i := 0; i < devices; i++ {
+		slice.Spec.Devices = append(slice.Spec.Devices, resourcev1beta1.Device{Name: fmt.Sprintf("device-%d", i)})
+	}
+	return slice
+}
+
+func buildTestResourceClaim(name, driver string, devices int, adminAccess bool) *resourcev1beta1.ResourceClaim {
+	claim := &resourcev1beta1.ResourceClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Status: resourcev1beta1.ResourceClaimStatus{
+			Allocation: &resourcev1beta1.AllocationResult{},
+		},
+	}
+	for i := 0; i < devices; i++ {
+		claim.Status.Allocation.Devices.Results = append(claim.Status.Allocation.Devices.Results, resourcev1beta1.DeviceRequestAllocationResult{
+			Request:     "req",
+			Driver:      driver,
+			Pool:        "pool",
+			Device:      fmt.Sprintf("device-%d", i),
+			AdminAccess: utilptr.To(adminAccess),
+		})
+	}
+	return claim
+}
+
+// withResourceClaim makes the pod consume the claim, either directly or
+// through a claim generated from a template.
+func withResourceClaim(claimName string, fromTemplate bool) func(*v1.Pod) {
+	return func(pod *v1.Pod) {
+		podClaim := v1.PodResourceClaim{Name: "devices"}
+		if fromTemplate {
+			podClaim.ResourceClaimTemplateName = utilptr.To("template")
+			pod.Status.ResourceClaimStatuses = append(pod.Status.ResourceClaimStatuses, v1.PodResourceClaimStatus{
+				Name:              podClaim.Name,
+				ResourceClaimName: utilptr.To(claimName),
+			})
+		} else {
+			podClaim.ResourceClaimName = utilptr.To(claimName)
+		}
+		pod.Spec.ResourceClaims = append(pod.Spec.ResourceClaims, podClaim)
+	}
+}
+
+func startDeviceAccounting(ctx context.Context, t *testing.T, objs ...runtime.Object) (*DeviceAccounting, podutil.GetPodsAssignedToNodeFunc) {
+	sharedInformerFactory := informers.NewSharedInformerFactory(fake.NewSimpleClientset(objs...), 0)
+	getPodsAssignedToNode, err := podutil.BuildGetPodsAssignedToNodeFunc(sharedInformerFactory.Core().V1().Pods().Informer())
+	if err != nil {
+		t.Fatalf("Build get pods assigned to node function error: %v", err)
+	}
+	devices, err := NewDeviceAccounting(sharedInformerFactory)
+	if err != nil {
+		t.Fatalf("Build device accounting error: %v", err)
+	}
+	sharedInformerFactory.Start(ctx.Done())
+	sharedInformerFactory.WaitForCacheSync(ctx.Done())
+	return devices, getPodsAssignedToNode
+}
+
+func TestDeviceAccountingNodeCapacity(t *testing.T) {
+	node := test.BuildTestNode("n1", 2000, 3000, 10, nil)
+
+	tests := []struct {
+		name     string
+		slices   []runtime.Object
+		expected v1.ResourceList
+	}{
+		{
+			name:     "no resource slice",
+			expected: v1.ResourceList{},
+		},
+		{
+			name: "devices of every driver are counted",
+			slices: []runtime.Object{
+				buildTestResourceSlice("gpu-1", "n1", "gpu.example.com", "n1", 1, 4),
+				buildTestResourceSlice("gpu-2", "n1", "gpu.example.com", "n1-extra", 1, 2),
+				buildTestResourceSlice("nic", "n1", "nic.example.com", "n1", 1, 1),
+			},
+			expected: v1.ResourceList{
+				"gpu.example.com": resource.MustParse("6"),
+				"nic.example.com": resource.MustParse("1"),
+			},
+		},
+		{
+			name: "slices of other nodes are ignored",
+			slices: []runtime.Object{
+				buildTestResourceSlice("gpu-1", "n1", "gpu.example.com", "n1", 1, 4),
+				buildTestResourceSlice("gpu-2", "n2", "gpu.example.com", "n2", 1, 8),
+				buildTestResourceSlice("gpu-3", "", "gpu.example.com", "network", 1, 8),
+			},
+			expected: v1.ResourceList{
+				"gpu.example.com": resource.MustParse("4"),
+			},
+		},
+		{
+			name: "only the latest pool generation is counted",
+			slices: []runtime.Object{
+				buildTestResourceSlice("gpu-old", "n1", "gpu.example.com", "n1", 1, 4),
+				buildTestResourceSlice("gpu-new", "n1", "gpu.example.com", "n1", 2, 3),
+			},
+			expected: v1.ResourceList{
+				"gpu.example.com": resource.MustParse("3"),
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			devices, _ := startDeviceAccounting(ctx, t, tc.slices...)
+			if capacity := devices.NodeCapacity(node); !equality.Semantic.DeepEqual(capacity, tc.expected) {
+				t.Errorf("expected capacity %v, got %v", tc.expected, capacity)
+			}
+		})
+	}
+}
+
+func TestNewDeviceAccountingSharedFactory(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	node := test.BuildTestNode("n1", 2000, 3000, 10, nil)
+	sharedInformerFactory := informers.NewSharedInformerFactory(fake.NewSimpleClientset(
+		buildTestResourceSlice("gpu-1", "n1", "gpu.example.com", "n1", 1, 4),
+	), 0)
+	first, err := NewDeviceAccounting(sharedInformerFactory)
+	if err != nil {
+		t.Fatalf("Build device accounting error: %v", err)
+	}
+	second, err := NewDeviceAccounting(sharedInformerFactory)
+	if err != nil {
+		t.Fatalf("Build device accounting error: %v", err)
+	}
+	sharedInformerFactory.Start(ctx.Done())
+	sharedInformerFactory.WaitForCacheSync(ctx.Done())
+
+	expected := v1.ResourceList{"gpu.example.com": resource.MustParse("4")}
+	for _, devices := range []*DeviceAccounting{first, second} {
+		if capacity := devices.NodeCapacity(node); !equality.Semantic.DeepEqual(capacity, expected) {
+			t.Errorf("expected capacity %v, got %v", expected, capacity)
+		}
+	}
+}
+
+func TestDeviceAccountingPodRequests(t *testing.T) {
+	unallocated := buildTestResourceClaim("unallocated", "gpu.example.com", 0, false)
+	unallocated.Status.Allocation = nil
+
+	tests := []struct {
+		name     string
+		pod      *v1.Pod
+		expected v1.ResourceList
+	}{
+		{
+			name:     "pod without claims",
+			pod:      test.BuildTestPod("p1", 100, 0, "n1", nil),
+			expected: nil,
+		},
+		{
+			name: "claim referenced by name",
+			pod:  test.BuildTestPod("p1", 100, 0, "n1", withResourceClaim("two-gpus", false)),
+			expected: v1.ResourceList{
+				"gpu.example.com": resource.MustParse("2"),
+			},
+		},
+		{
+			name: "claim generated from a template",
+			pod:  test.BuildTestPod("p1", 100, 0, "n1", withResourceClaim("two-gpus", true)),
+			expected: v1.ResourceList{
+				"gpu.example.com": resource.MustParse("2"),
+			},
+		},
+		{
+			name: "claims of several drivers",
+			pod: test.BuildTestPod("p1", 100, 0, "n1", func(pod *v1.Pod) {
+				withResourceClaim("two-gpus", false)(pod)
+				pod.Spec.ResourceClaims = append(pod.Spec.ResourceClaims, v1.PodResourceClaim{Name: "nic", ResourceClaimName: utilptr.To("nic")})
+			}),
+			expected: v1.ResourceList{
+				"gpu.example.com": resource.MustParse("2"),
+				"nic.example.com": resource.MustParse("1"),
+			},
+		},
+		{
+			name:     "unallocated claim",
+			pod:      test.BuildTestPod("p1", 100, 0, "n1", withResourceClaim("unallocated", false)),
+			expected: v1.ResourceList{},
+		},
+		{
+			name:     "missing claim",
+			pod:      test.BuildTestPod("p1", 100, 0, "n1", withResourceClaim("missing", false)),
+			expected: v1.ResourceList{},
+		},
+		{
+			name:     "admin access devices",
+			pod:      test.BuildTestPod("p1", 100, 0, "n1", withResourceClaim("admin", false)),
+			expected: v1.ResourceList{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			devices, _ := startDeviceAccounting(ctx, t,
+				buildTestResourceClaim("two-gpus", "gpu.example.com", 2, false),
+				buildTestResourceClaim("nic", "nic.example.com", 1, false),
+				buildTestResourceClaim("admin", "gpu.example.com", 1, true),
+				unallocated,
+			)
+			if requests := devices.PodRequests(tc.pod); !equality.Semantic.DeepEqual(requests, tc.expected) {
+				t.Errorf("expected requests %v, got %v", tc.expected, requests)
+			}
+		})
+	}
+}
+
+func TestDeviceAccountingPodClaimsVersion(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	claim := buildTestResourceClaim("two-gpus", "gpu.example.com", 2, false)
+	claim.ResourceVersion = "7"
+	devices, _ := startDeviceAccounting(ctx, t, claim)
+
+	for _, tc := range []struct {
+		name     string
+		pod      *v1.Pod
+		expected string
+	}{
+		{
+			name:     "pod without claims",
+			pod:      test.BuildTestPod("p1", 100, 0, "n1", nil),
+			expected: "",
+		},
+		{
+			name:     "claim referenced by name",
+			pod:      test.BuildTestPod("p1", 100, 0, "n1", withResourceClaim("two-gpus", false)),
+			expected: "7",
+		},
+		{
+			name: "claim not created yet",
+			pod: test.BuildTestPod("p1", 100, 0, "n1", func(pod *v1.Pod) {
+				withResourceClaim("two-gpus", false)(pod)
+				withResourceClaim("missing", false)(pod)
+			}),
+			expected: "7,",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if version := devices.PodClaimsVersion(tc.pod); version != tc.expected {
+				t.Errorf("expected version %q, got %q", tc.expected, version)
+			}
+		})
+	}
+}
+
+func TestNodeFitDevices(t *testing.T) {
+	node := test.BuildTestNode("n1", 64000, 128*1000*1000*1000, 10, nil)
+
+	tests := []struct {
+		name       string
+		pod        *v1.Pod
+		podsOnNode []*v1.Pod
+		err        error
+	}{
+		{
+			name: "devices available",
+			pod:  test.BuildTestPod("p1", 1000, 0, "n2", withResourceClaim("two-gpus", false)),
+			podsOnNode: []*v1.Pod{
+				test.BuildTestPod("p2", 1000, 0, "n1", withResourceClaim("one-gpu", true)),
+			},
+		},
+		{
+			name: "insufficient devices",
+			pod:  test.BuildTestPod("p1", 1000, 0, "n2", withResourceClaim("two-gpus", false)),
+			podsOnNode: []*v1.Pod{
+				test.BuildTestPod("p2", 1000, 0, "n1", withResourceClaim("two-gpus-other", true)),
+			},
+			err: errors.New("insufficient gpu.example.com"),
+		},
+		{
+			name: "driver missing on the node",
+			pod:  test.BuildTestPod("p1", 1000, 0, "n2", withResourceClaim("nic", false)),
+			err:  errors.New("insufficient nic.example.com"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			objs := []runtime.Object{
+				node,
+				tc.pod,
+				buildTestResourceSlice("gpu", "n1", "gpu.example.com", "n1", 1, 3),
+				buildTestResourceClaim("one-gpu", "gpu.example.com", 1, false),
+				buildTestResourceClaim("two-gpus", "gpu.example.com", 2, false),
+				buildTestResourceClaim("two-gpus-other", "gpu.example.com", 2, false),
+				buildTestResourceClaim("nic", "nic.example.com", 1, false),
+			}
+			for _, pod := range tc.podsOnNode {
+				objs = append(objs, pod)
+			}
+
+			devices, getPodsAssignedToNode := startDeviceAccounting(ctx, t, objs...)
+			err := NodeFitWithDevices(getPodsAssignedToNode, devices, tc.pod, node)
+			if (err == nil && tc.err != nil) || (err != nil && (tc.err == nil || err.Error() != tc.err.Error())) {
+				t.Errorf("expected error %v, got %v", tc.err, err)
+			}
+
+			// without device accounting the claims are invisible
+			if err := NodeFit(getPodsAssignedToNode, tc.pod, node); err != nil {
+				t.Errorf("expected the pod to fit without device accounting, got %v", err)
+			}
+		})
+	}
+}
@@ -18,14 +18,19 @@ package node
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"sync/atomic"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	clientset "k8s.io/client-go/kubernetes"
 	listersv1 "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/util/workqueue"
@@ -37,6 +42,51 @@ import (
 
 const workersCount = 100
 
+const (
+	// ToBeDeletedByClusterAutoscalerTaint is set by the cluster autoscaler
+	// on nodes it is draining and about to remove.
+	ToBeDeletedByClusterAutoscalerTaint = "ToBeDeletedByClusterAutoscaler"
+	// DeletionCandidateOfClusterAutoscalerTaint is set by the cluster
+	// autoscaler on nodes it considers unneeded and may remove soon.
+	DeletionCandidateOfClusterAutoscalerTaint = "DeletionCandidateOfClusterAutoscaler"
+	// KarpenterDisruptedTaint is set by karpenter on nodes it is about to
+	// disrupt, e.g. as part of a consolidation.
+	KarpenterDisruptedTaint = "karpenter.sh/disrupted"
+	// KarpenterLegacyDisruptionTaint is the taint used by karpenter
+	// releases prior to v1 for the same purpose.
+	KarpenterLegacyDisruptionTaint = "karpenter.sh/disruption"
+
+	// AWSSpotInterruptionTaint is set by the aws node termination handler
+	// when the instance receives a spot interruption notice.
+	AWSSpotInterruptionTaint = "aws-node-termination-handler/spot-itn"
+	// AWSScheduledMaintenanceTaint is set by the aws node termination
+	// handler when the instance has a scheduled maintenance event.
+	AWSScheduledMaintenanceTaint = "aws-node-termination-handler/scheduled-maintenance"
+	// AWSASGLifecycleTerminationTaint is set by the aws node termination
+	// handler when the auto scaling group is terminating the instance.
+	AWSASGLifecycleTerminationTaint = "aws-node-termination-handler/asg-lifecycle-termination"
+	// GCPImpendingTerminationTaint is set by the gcp node termination
+	// handler when the instance is about to be preempted or shut down.
+	GCPImpendingTerminationTaint = "cloud.google.com/impending-node-termination"
+
+	// CandidateForScaleDownKey is the key of the annotation and of the
+	// taint the descheduler sets on the nodes it emptied, for node
+	// autoscalers to pick them up.
+	CandidateForScaleDownKey = "descheduler.io/candidate-for-scale-down"
+	// ScaleDownMarksKey is the annotation recording the marks the
+	// descheduler set on a node it emptied, and when, so they can be
+	// removed if the node is not scaled down. See ScaleDownRecord.
+	ScaleDownMarksKey = "descheduler.io/scale-down-marks"
+
+	// KarpenterDoNotDisruptKey is the annotation preventing karpenter from
+	// voluntarily disrupting, e.g. consolidating, the node.
+	KarpenterDoNotDisruptKey = "karpenter.sh/do-not-disrupt"
+	// DoNotDisruptUntilKey is the annotation recording until when the
+	// descheduler keeps the KarpenterDoNotDisruptKey annotation it set on
+	// a node. See MarkNodeDoNotDisrupt.
+	DoNotDisruptUntilKey = "descheduler.io/do-not-disrupt-until"
+)
+
 // ReadyNodes returns ready nodes irrespective of whether they are
 // schedulable or not.
 func ReadyNodes(ctx context.Context, client clientset.Interface, nodeLister listersv1.NodeLister, nodeSelector string) ([]*v1.Node, error) {
@@ -111,6 +161,13 @@ func IsReady(node *v1.Node) bool {
 // deciding if a pod would fit on a node, but more predicates may be added in the future.
 // There should be no methods to modify nodes or pods in this method.
 func NodeFit(nodeIndexer podutil.GetPodsAssignedToNodeFunc, pod *v1.Pod, node *v1.Node) error {
+	return NodeFitWithDevices(nodeIndexer, nil, pod, node)
+}
+
+// NodeFitWithDevices is NodeFit accounting the devices allocated through
+// Dynamic Resource Allocation as part of the requests, when devices is not
+// nil.
+func NodeFitWithDevices(nodeIndexer podutil.GetPodsAssignedToNodeFunc, devices *DeviceAccounting, pod *v1.Pod, node *v1.Node) error {
 	// Check node selector and required affinity
 	if ok, err := utils.PodMatchNodeSelector(pod, node); err != nil {
 		return err
@@ -118,6 +175,11 @@ func NodeFit(nodeIndexer podutil.GetPodsAssignedToNodeFunc, pod *v1.Pod, node *v
 		return errors.New("pod node selector does not match the node label")
 	}
 
+	// Check the operating system the pod requires, if any
+	if pod.Spec.OS != nil && string(pod.Spec.OS.Name) != OperatingSystem(node) {
+		return fmt.Errorf("pod requires %q operating system, node runs %q", pod.Spec.OS.Name, OperatingSystem(node))
+	}
+
 	// Check taints (we only care about NoSchedule and NoExecute taints)
 	ok := utils.TolerationsTolerateTaintsWithFilter(pod.Spec.Tolerations, node.Spec.Taints, func(taint *v1.Taint) bool {
 		return taint.Effect == v1.TaintEffectNoSchedule || taint.Effect == v1.TaintEffectNoExecute
@@ -128,7 +190,7 @@ func NodeFit(nodeIndexer podutil.GetPodsAssignedToNodeFunc, pod *v1.Pod, node *v
 
 	// Check if the pod can fit on a node based off it's requests
 	if pod.Spec.NodeName == "" || pod.Spec.NodeName != node.Name {
-		if ok, reqError := fitsRequest(nodeIndexer, pod, node); !ok {
+		if ok, reqError := fitsRequest(nodeIndexer, devices, pod, node); !ok {
 			return reqError
 		}
 	}
@@ -138,6 +200,11 @@ func NodeFit(nodeIndexer podutil.GetPodsAssignedToNodeFunc, pod *v1.Pod, node *v
 		return errors.New("node is not schedulable")
 	}
 
+	// Check if node is about to be removed by a node autoscaler
+	if IsNodeMarkedForDeletion(node) {
+		return errors.New("node is marked for deletion by a node autoscaler")
+	}
+
 	// Check if pod matches inter-pod anti-affinity rule of pod on node
 	if match, err := podMatchesInterPodAntiAffinity(nodeIndexer, pod, node); err != nil {
 		return err
@@ -148,7 +215,7 @@ func NodeFit(nodeIndexer podutil.GetPodsAssignedToNodeFunc, pod *v1.Pod, node *v
 	return nil
 }
 
-func podFitsNodes(nodeIndexer podutil.GetPodsAssignedToNodeFunc, pod *v1.Pod, nodes []*v1.Node, excludeFilter func(pod *v1.Pod, node *v1.Node) bool) bool {
+func podFitsNodes(nodeIndexer podutil.GetPodsAssignedToNodeFunc, devices *DeviceAccounting, pod *v1.Pod, nodes []*v1.Node, excludeFilter func(pod *v1.Pod, node *v1.Node) bool) bool {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
@@ -158,7 +225,7 @@ func podFitsNodes(nodeIndexer podutil.GetPodsAssignedToNodeFunc, pod *v1.Pod, no
 		if excludeFilter != nil && excludeFilter(pod, node) {
 			return
 		}
-		err := NodeFit(nodeIndexer, pod, node)
+		err := NodeFitWithDevices(nodeIndexer, devices, pod, node)
 		if err == nil {
 			klog.V(4).InfoS("Pod fits on node", "pod", klog.KObj(pod), "node", klog.KObj(node))
 			atomic.AddInt32(&filteredLen, 1)
@@ -177,7 +244,13 @@ func podFitsNodes(nodeIndexer podutil.GetPodsAssignedToNodeFunc, pod *v1.Pod, no
 // PodFitsAnyOtherNode checks if the given pod will fit any of the given nodes, besides the node
 // the pod is already running on. The predicates used to determine if the pod will fit can be found in the NodeFit function.
 func PodFitsAnyOtherNode(nodeIndexer podutil.GetPodsAssignedToNodeFunc, pod *v1.Pod, nodes []*v1.Node) bool {
-	return podFitsNodes(nodeIndexer, pod, nodes, func(pod *v1.Pod, node *v1.Node) bool {
+	return PodFitsAnyOtherNodeWithDevices(nodeIndexer, nil, pod, nodes)
+}
+
+// PodFitsAnyOtherNodeWithDevices is PodFitsAnyOtherNode accounting the
+// devices allocated through Dynamic Resource Allocation, see NodeFitWithDevices.
+func PodFitsAnyOtherNodeWithDevices(nodeIndexer podutil.GetPodsAssignedToNodeFunc, devices *DeviceAccounting, pod *v1.Pod, nodes []*v1.Node) bool {
+	return podFitsNodes(nodeIndexer, devices, pod, nodes, func(pod *v1.Pod, node *v1.Node) bool {
 		return pod.Spec.NodeName == node.Name
 	})
 }
@@ -185,13 +258,25 @@ func PodFitsAnyOtherNode(nodeIndexer podutil.GetPodsAssignedToNodeFunc, pod *v1.
 // PodFitsAnyNode checks if the given pod will fit any of the given nodes. The predicates used
 // to determine if the pod will fit can be found in the NodeFit function.
 func PodFitsAnyNode(nodeIndexer podutil.GetPodsAssignedToNodeFunc, pod *v1.Pod, nodes []*v1.Node) bool {
-	return podFitsNodes(nodeIndexer, pod, nodes, nil)
+	return PodFitsAnyNodeWithDevices(nodeIndexer, nil, pod, nodes)
+}
+
+// PodFitsAnyNodeWithDevices is PodFitsAnyNode accounting the devices
+// allocated through Dynamic Resource Allocation, see NodeFitWithDevices.
+func PodFitsAnyNodeWithDevices(nodeIndexer podutil.GetPodsAssignedToNodeFunc, devices *DeviceAccounting, pod *v1.Pod, nodes []*v1.Node) bool {
+	return podFitsNodes(nodeIndexer, devices, pod, nodes, nil)
 }
 
 // PodFitsCurrentNode checks if the given pod will fit onto the given node. The predicates used
 // to determine if the pod will fit can be found in the NodeFit function.
 func PodFitsCurrentNode(nodeIndexer podutil.GetPodsAssignedToNodeFunc, pod *v1.Pod, node *v1.Node) bool {
-	err := NodeFit(nodeIndexer, pod, node)
+	return PodFitsCurrentNodeWithDevices(nodeIndexer, nil, pod, node)
+}
+
+// PodFitsCurrentNodeWithDevices is PodFitsCurrentNode accounting the devices
+// allocated through Dynamic Resource Allocation, see NodeFitWithDevices.
+func PodFitsCurrentNodeWithDevices(nodeIndexer podutil.GetPodsAssignedToNodeFunc, devices *DeviceAccounting, pod *v1.Pod, node *v1.Node) bool {
+	err := NodeFitWithDevices(nodeIndexer, devices, pod, node)
 	if err == nil {
 		klog.V(4).InfoS("Pod fits on node", "pod", klog.KObj(pod), "node", klog.KObj(node))
 		return true
@@ -203,26 +288,282 @@ func PodFitsCurrentNode(nodeIndexer podutil.GetPodsAssignedToNodeFunc, pod *v1.P
 	return false
 }
 
+// OperatingSystem returns the operating system of the node, as reported by
+// the kubelet through the kubernetes.io/os label. Nodes without the label
+// are assumed to run linux.
+func OperatingSystem(node *v1.Node) string {
+	if os, ok := node.Labels[v1.LabelOSStable]; ok && os != "" {
+		return os
+	}
+	return string(v1.Linux)
+}
+
 // IsNodeUnschedulable checks if the node is unschedulable. This is a helper function to check only in case of
 // underutilized node so that they won't be accounted for.
 func IsNodeUnschedulable(node *v1.Node) bool {
 	return node.Spec.Unschedulable
 }
 
+// IsNodeMarkedForDeletion checks if the cluster autoscaler or karpenter has
+// tainted the node as being scaled down or consolidated, or if the node is
+// being terminated by its cloud provider. Pods can not be moved onto such
+// nodes.
+func IsNodeMarkedForDeletion(node *v1.Node) bool {
+	for _, taint := range node.Spec.Taints {
+		switch taint.Key {
+		case ToBeDeletedByClusterAutoscalerTaint,
+			KarpenterDisruptedTaint,
+			KarpenterLegacyDisruptionTaint:
+			return true
+		}
+	}
+	return IsNodeBeingTerminated(node)
+}
+
+// IsNodeCandidateForDeletion checks if the cluster autoscaler tainted the
+// node as unneeded, or if the descheduler tainted it as a candidate for
+// scale down. Both taints have the PreferNoSchedule effect, pods still fit
+// such nodes but should rather not be moved onto them.
+func IsNodeCandidateForDeletion(node *v1.Node) bool {
+	return HasTaint(node, DeletionCandidateOfClusterAutoscalerTaint) ||
+		HasTaint(node, CandidateForScaleDownKey)
+}
+
+// IsNodeBeingTerminated checks if a cloud provider termination handler has
+// tainted the node after receiving a termination notice, e.g. a spot
+// interruption. Such nodes are usually gone within a couple of minutes.
+func IsNodeBeingTerminated(node *v1.Node) bool {
+	for _, taint := range node.Spec.Taints {
+		switch taint.Key {
+		case AWSSpotInterruptionTaint,
+			AWSScheduledMaintenanceTaint,
+			AWSASGLifecycleTerminationTaint,
+			GCPImpendingTerminationTaint:
+			return true
+		}
+	}
+	return false
+}
+
+// ScaleDownMarks tells how a node is marked as a candidate for scale down.
+type ScaleDownMarks struct {
+	// Annotate sets the CandidateForScaleDownKey annotation.
+	Annotate bool `json:"annotate,omitempty"`
+	// Taint sets the CandidateForScaleDownKey taint, with the
+	// PreferNoSchedule effect so pods still fit the node if needed.
+	Taint bool `json:"taint,omitempty"`
+	// Cordon marks the node unschedulable.
+	Cordon bool `json:"cordon,omitempty"`
+}
+
+// ScaleDownRecord is kept in the ScaleDownMarksKey annotation of the nodes
+// marked as candidates for scale down. Only the marks the descheduler set
+// are recorded, e.g. a node cordoned by someone else is not recorded as
+// cordoned, so removing the marks leaves the node as it was found.
+type ScaleDownRecord struct {
+	ScaleDownMarks `json:",inline"`
+	// Value is the value of the annotation and of the taint.
+	Value string `json:"value"`
+	// MarkedAt is when the node was first marked.
+	MarkedAt metav1.Time `json:"markedAt"`
+}
+
+// ScaleDownRecordOf returns the record of the marks the descheduler set on
+// the node, nil is returned if the node is not marked.
+func ScaleDownRecordOf(node *v1.Node) *ScaleDownRecord {
+	raw, ok := node.Annotations[ScaleDownMarksKey]
+	if !ok {
+		return nil
+	}
+	record := &ScaleDownRecord{}
+	if err := json.Unmarshal([]byte(raw), record); err != nil {
+		klog.V(3).InfoS("Unable to parse the scale down marks of the node", "node", klog.KObj(node), "err", err)
+		return nil
+	}
+	return record
+}
+
+// MarkNodeForScaleDown marks the node as a candidate for scale down, value is
+// used as the value of the annotation and of the taint. The node is only
+// patched when any of the marks is missing, the marks set are recorded in the
+// ScaleDownMarksKey annotation. Conflicting patches fail, the marks are
+// expected to be set again later on.
+func MarkNodeForScaleDown(ctx context.Context, client clientset.Interface, name, value string, marks ScaleDownMarks) error {
+	node, err := client.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	record := ScaleDownRecordOf(node)
+	if record == nil {
+		record = &ScaleDownRecord{Value: value, MarkedAt: metav1.Now()}
+	}
+
+	var changed bool
+	annotations := map[string]any{}
+	spec := map[string]any{}
+	if marks.Annotate && node.Annotations[CandidateForScaleDownKey] != value {
+		annotations[CandidateForScaleDownKey] = value
+		record.Annotate = true
+		changed = true
+	}
+	if marks.Taint && !HasTaint(node, CandidateForScaleDownKey) {
+		spec["taints"] = append(slices.Clone(node.Spec.Taints), v1.Taint{
+			Key:    CandidateForScaleDownKey,
+			Value:  value,
+			Effect: v1.TaintEffectPreferNoSchedule,
+		})
+		record.Taint = true
+		changed = true
+	}
+	if marks.Cordon && !node.Spec.Unschedulable {
+		spec["unschedulable"] = true
+		record.Cordon = true
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	annotations[ScaleDownMarksKey] = string(raw)
+	_, err = patchNode(ctx, client, node, annotations, spec)
+	return err
+}
+
+// UnmarkNodeForScaleDown removes the marks the descheduler recorded setting
+// on the node, along with the record, and returns the updated node. Marks
+// removed by someone else in the meantime are left alone. Conflicting
+// patches fail.
+func UnmarkNodeForScaleDown(ctx context.Context, client clientset.Interface, node *v1.Node) (*v1.Node, error) {
+	record := ScaleDownRecordOf(node)
+	if record == nil {
+		return node, nil
+	}
+
+	annotations := map[string]any{ScaleDownMarksKey: nil}
+	spec := map[string]any{}
+	if record.Annotate && node.Annotations[CandidateForScaleDownKey] == record.Value {
+		annotations[CandidateForScaleDownKey] = nil
+	}
+	if record.Taint && HasTaint(node, CandidateForScaleDownKey) {
+		spec["taints"] = slices.DeleteFunc(slices.Clone(node.Spec.Taints), func(taint v1.Taint) bool {
+			return taint.Key == CandidateForScaleDownKey
+		})
+	}
+	if record.Cordon && node.Spec.Unschedulable {
+		spec["unschedulable"] = false
+	}
+	return patchNode(ctx, client, node, annotations, spec)
+}
+
+// DoNotDisruptUntil returns until when the descheduler keeps the node
+// annotated with KarpenterDoNotDisruptKey, false is returned if the
+// descheduler did not annotate the node.
+func DoNotDisruptUntil(node *v1.Node) (time.Time, bool) {
+	raw, ok := node.Annotations[DoNotDisruptUntilKey]
+	if !ok {
+		return time.Time{}, false
+	}
+	until, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		klog.V(3).InfoS("Unable to parse the do not disrupt expiration of the node", "node", klog.KObj(node), "err", err)
+		// the annotation is still ours, it is removed as if expired.
+		return time.Time{}, true
+	}
+	return until, true
+}
+
+// MarkNodeDoNotDisrupt annotates the node with KarpenterDoNotDisruptKey until
+// the provided time, recorded in the DoNotDisruptUntilKey annotation. Nodes
+// annotated by someone else are left alone, nodes annotated by the
+// descheduler have the time extended. Conflicting patches fail, the node is
+// expected to be annotated again later on.
+func MarkNodeDoNotDisrupt(ctx context.Context, client clientset.Interface, name string, until time.Time) error {
+	node, err := client.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if _, annotated := node.Annotations[KarpenterDoNotDisruptKey]; annotated {
+		if _, ours := DoNotDisruptUntil(node); !ours {
+			return nil
+		}
+	}
+
+	annotations := map[string]any{
+		KarpenterDoNotDisruptKey: "true",
+		DoNotDisruptUntilKey:     until.UTC().Format(time.RFC3339),
+	}
+	_, err = patchNode(ctx, client, node, annotations, nil)
+	return err
+}
+
+// UnmarkNodeDoNotDisrupt removes the annotations MarkNodeDoNotDisrupt set on
+// the node and returns the updated node. A KarpenterDoNotDisruptKey
+// annotation changed by someone else in the meantime is left alone.
+// Conflicting patches fail.
+func UnmarkNodeDoNotDisrupt(ctx context.Context, client clientset.Interface, node *v1.Node) (*v1.Node, error) {
+	if _, ours := DoNotDisruptUntil(node); !ours {
+		return node, nil
+	}
+
+	annotations := map[string]any{DoNotDisruptUntilKey: nil}
+	if node.Annotations[KarpenterDoNotDisruptKey] == "true" {
+		annotations[KarpenterDoNotDisruptKey] = nil
+	}
+	return patchNode(ctx, client, node, annotations, nil)
+}
+
+// patchNode merge patches the annotations and the spec of the node and
+// returns the patched node. the resource version of the node is part of the
+// patch so it fails if the node changed since it was read, taints are
+// replaced as a whole.
+func patchNode(ctx context.Context, client clientset.Interface, node *v1.Node, annotations, spec map[string]any) (*v1.Node, error) {
+	patch := map[string]any{
+		"metadata": map[string]any{
+			"resourceVersion": node.ResourceVersion,
+			"annotations":     annotations,
+		},
+	}
+	if len(spec) > 0 {
+		patch["spec"] = spec
+	}
+	raw, err := json.Marshal(patch)
+	if err != nil {
+		return nil, err
+	}
+	return client.CoreV1().Nodes().Patch(ctx, node.Name, types.MergePatchType, raw, metav1.PatchOptions{})
+}
+
+// HasTaint checks if the node has a taint matching the given one, either a
+// taint key, e.g. infra, or a key=value pair, e.g. dedicated=gpu. The
+// effect of the taint is not taken into account.
+func HasTaint(node *v1.Node, taint string) bool {
+	key, value, withValue := strings.Cut(taint, "=")
+	for _, nodeTaint := range node.Spec.Taints {
+		if nodeTaint.Key == key && (!withValue || nodeTaint.Value == value) {
+			return true
+		}
+	}
+	return false
+}
+
 // fitsRequest determines if a pod can fit on a node based on its resource requests. It returns true if
 // the pod will fit.
-func fitsRequest(nodeIndexer podutil.GetPodsAssignedToNodeFunc, pod *v1.Pod, node *v1.Node) (bool, error) {
+func fitsRequest(nodeIndexer podutil.GetPodsAssignedToNodeFunc, devices *DeviceAccounting, pod *v1.Pod, node *v1.Node) (bool, error) {
 	// Get pod requests
-	podRequests, _ := utils.PodRequestsAndLimits(pod)
+	podRequests := podRequestsWithDevices(devices, pod)
 	resourceNames := []v1.ResourceName{v1.ResourcePods}
 	for name := range podRequests {
 		resourceNames = append(resourceNames, name)
 	}
 
-	availableResources, err := nodeAvailableResources(nodeIndexer, node, resourceNames,
+	availableResources, err := nodeAvailableResources(nodeIndexer, node, devices.NodeCapacity(node), resourceNames,
 		func(pod *v1.Pod) (v1.ResourceList, error) {
-			req, _ := utils.PodRequestsAndLimits(pod)
-			return req, nil
+			return podRequestsWithDevices(devices, pod), nil
 		},
 	)
 	if err != nil {
@@ -244,8 +585,19 @@ func fitsRequest(nodeIndexer podutil.GetPodsAssignedToNodeFunc, pod *v1.Pod, nod
 	return true, nil
 }
 
+// podRequestsWithDevices returns the resources requested by the pod, the
+// devices allocated to its resource claims included.
+func podRequestsWithDevices(devices *DeviceAccounting, pod *v1.Pod) v1.ResourceList {
+	req, _ := utils.PodRequestsAndLimits(pod)
+	for name, quantity := range devices.PodRequests(pod) {
+		req[name] = quantity
+	}
+	return req
+}
+
 // nodeAvailableResources returns resources mapped to the quanitity available on the node.
-func nodeAvailableResources(nodeIndexer podutil.GetPodsAssignedToNodeFunc, node *v1.Node, resourceNames []v1.ResourceName, podUtilization podutil.PodUtilizationFnc) (api.ReferencedResourceList, error) {
+// deviceCapacity holds the devices published for the node through Dynamic Resource Allocation.
+func nodeAvailableResources(nodeIndexer podutil.GetPodsAssignedToNodeFunc, node *v1.Node, deviceCapacity v1.ResourceList, resourceNames []v1.ResourceName, podUtilization podutil.PodUtilizationFnc) (api.ReferencedResourceList, error) {
 	podsOnNode, err := podutil.ListPodsOnANode(node.Name, nodeIndexer, nil)
 	if err != nil {
 		return nil, err
@@ -268,7 +620,9 @@ func nodeAvailableResources(nodeIndexer podutil.GetPodsAssignedToNodeFunc, node
 		} else {
 			if _, exists := node.Status.Allocatable[name]; exists {
 				allocatableResource := node.Status.Allocatable[name]
-				remainingResources[name] = resource.NewQuantity(allocatableResource.Value()-nodeUtilization[name].Value(), resource.DecimalSI)
+				remainingResources[name] = resource.NewQuantity(allocatableResource.Value()-nodeUtilization[name].Value(), utils.ResourceQuantityFormat(name))
+			} else if deviceCount, exists := deviceCapacity[name]; exists {
+				remainingResources[name] = resource.NewQuantity(deviceCount.Value()-nodeUtilization[name].Value(), resource.DecimalSI)
 			} else {
 				remainingResources[name] = resource.NewQuantity(0, resource.DecimalSI)
 			}
@@ -290,7 +644,7 @@ func NodeUtilization(pods []*v1.Pod, resourceNames []v1.ResourceName, podUtiliza
 		case v1.ResourcePods:
 			totalUtilization[name] = resource.NewQuantity(int64(len(pods)), resource.DecimalSI)
 		default:
-			totalUtilization[name] = resource.NewQuantity(0, resource.DecimalSI)
+			totalUtilization[name] = resource.NewQuantity(0, utils.ResourceQuantityFormat(name))
 		}
 	}
 
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"sync"
 	"testing"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -114,6 +115,326 @@ func TestIsNodeUnschedulable(t *testing.T) {
 	}
 }
 
+func TestIsNodeMarkedForDeletion(t *testing.T) {
+	tests := []struct {
+		description string
+		node        *v1.Node
+		marked      bool
+		candidate   bool
+	}{
+		{
+			description: "Node without taints",
+			node:        &v1.Node{},
+			marked:      false,
+		},
+		{
+			description: "Node with an unrelated taint",
+			node: &v1.Node{
+				Spec: v1.NodeSpec{Taints: []v1.Taint{{Key: "foo", Effect: v1.TaintEffectNoSchedule}}},
+			},
+			marked: false,
+		},
+		{
+			description: "Node being removed by the cluster autoscaler",
+			node: &v1.Node{
+				Spec: v1.NodeSpec{Taints: []v1.Taint{{Key: ToBeDeletedByClusterAutoscalerTaint, Effect: v1.TaintEffectNoSchedule}}},
+			},
+			marked: true,
+		},
+		{
+			description: "Node considered unneeded by the cluster autoscaler",
+			node: &v1.Node{
+				Spec: v1.NodeSpec{Taints: []v1.Taint{{Key: DeletionCandidateOfClusterAutoscalerTaint, Effect: v1.TaintEffectPreferNoSchedule}}},
+			},
+			candidate: true,
+		},
+		{
+			description: "Node being disrupted by karpenter",
+			node: &v1.Node{
+				Spec: v1.NodeSpec{Taints: []v1.Taint{{Key: KarpenterDisruptedTaint, Effect: v1.TaintEffectNoSchedule}}},
+			},
+			marked: true,
+		},
+		{
+			description: "Node being disrupted by a karpenter release prior to v1",
+			node: &v1.Node{
+				Spec: v1.NodeSpec{Taints: []v1.Taint{{Key: KarpenterLegacyDisruptionTaint, Value: "disrupting", Effect: v1.TaintEffectNoSchedule}}},
+			},
+			marked: true,
+		},
+		{
+			description: "Node receiving a spot interruption notice",
+			node: &v1.Node{
+				Spec: v1.NodeSpec{Taints: []v1.Taint{{Key: AWSSpotInterruptionTaint, Effect: v1.TaintEffectNoSchedule}}},
+			},
+			marked: true,
+		},
+		{
+			description: "Node emptied by the descheduler",
+			node: &v1.Node{
+				Spec: v1.NodeSpec{Taints: []v1.Taint{{Key: CandidateForScaleDownKey, Effect: v1.TaintEffectPreferNoSchedule}}},
+			},
+			candidate: true,
+		},
+	}
+	for _, test := range tests {
+		if marked := IsNodeMarkedForDeletion(test.node); marked != test.marked {
+			t.Errorf("Test %#v failed, expected %v, got %v", test.description, test.marked, marked)
+		}
+		if candidate := IsNodeCandidateForDeletion(test.node); candidate != test.candidate {
+			t.Errorf("Test %#v failed, expected candidate %v, got %v", test.description, test.candidate, candidate)
+		}
+	}
+}
+
+func TestIsNodeBeingTerminated(t *testing.T) {
+	tests := []struct {
+		description string
+		node        *v1.Node
+		terminated  bool
+	}{
+		{
+			description: "Node without taints",
+			node:        &v1.Node{},
+			terminated:  false,
+		},
+		{
+			description: "Node being removed by the cluster autoscaler",
+			node: &v1.Node{
+				Spec: v1.NodeSpec{Taints: []v1.Taint{{Key: ToBeDeletedByClusterAutoscalerTaint, Effect: v1.TaintEffectNoSchedule}}},
+			},
+			terminated: false,
+		},
+		{
+			description: "Node receiving an aws spot interruption notice",
+			node: &v1.Node{
+				Spec: v1.NodeSpec{Taints: []v1.Taint{{Key: AWSSpotInterruptionTaint, Value: "spot-itn", Effect: v1.TaintEffectNoSchedule}}},
+			},
+			terminated: true,
+		},
+		{
+			description: "Node terminated by its aws auto scaling group",
+			node: &v1.Node{
+				Spec: v1.NodeSpec{Taints: []v1.Taint{{Key: AWSASGLifecycleTerminationTaint, Effect: v1.TaintEffectNoExecute}}},
+			},
+			terminated: true,
+		},
+		{
+			description: "Node about to be preempted on gcp",
+			node: &v1.Node{
+				Spec: v1.NodeSpec{Taints: []v1.Taint{{Key: GCPImpendingTerminationTaint, Effect: v1.TaintEffectNoSchedule}}},
+			},
+			terminated: true,
+		},
+	}
+	for _, test := range tests {
+		if terminated := IsNodeBeingTerminated(test.node); terminated != test.terminated {
+			t.Errorf("Test %#v failed, expected %v, got %v", test.description, test.terminated, terminated)
+		}
+	}
+}
+
+func TestMarkNodeForScaleDown(t *testing.T) {
+	tests := []struct {
+		description string
+		marks       ScaleDownMarks
+		unscheduled bool
+		annotated   bool
+		tainted     bool
+		cordoned    bool
+	}{
+		{
+			description: "No marks",
+		},
+		{
+			description: "Annotated",
+			marks:       ScaleDownMarks{Annotate: true},
+			annotated:   true,
+		},
+		{
+			description: "Annotated, tainted and cordoned",
+			marks:       ScaleDownMarks{Annotate: true, Taint: true, Cordon: true},
+			annotated:   true,
+			tainted:     true,
+			cordoned:    true,
+		},
+		{
+			description: "Already cordoned",
+			marks:       ScaleDownMarks{Taint: true, Cordon: true},
+			unscheduled: true,
+			tainted:     true,
+			cordoned:    true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			ctx := context.Background()
+			client := fake.NewSimpleClientset(&v1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "n1"},
+				Spec: v1.NodeSpec{
+					Unschedulable: test.unscheduled,
+					Taints:        []v1.Taint{{Key: "dedicated", Value: "infra", Effect: v1.TaintEffectNoSchedule}},
+				},
+			})
+
+			// marking the node twice must not duplicate the taint.
+			for i := 0; i < 2; i++ {
+				if err := MarkNodeForScaleDown(ctx, client, "n1", "HighNodeUtilization", test.marks); err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+			}
+
+			node, err := client.CoreV1().Nodes().Get(ctx, "n1", metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if annotated := node.Annotations[CandidateForScaleDownKey] == "HighNodeUtilization"; annotated != test.annotated {
+				t.Errorf("Expected the node to be annotated to be %v, got %v", test.annotated, annotated)
+			}
+			if tainted := HasTaint(node, CandidateForScaleDownKey); tainted != test.tainted {
+				t.Errorf("Expected the node to be tainted to be %v, got %v", test.tainted, tainted)
+			}
+			if len(node.Spec.Taints) > 2 {
+				t.Errorf("Expected a single taint to be added at most, got %v", node.Spec.Taints)
+			}
+			if node.Spec.Unschedulable != test.cordoned {
+				t.Errorf("Expected the node to be cordoned to be %v, got %v", test.cordoned, node.Spec.Unschedulable)
+			}
+
+			// removing the marks leaves the node as it was found.
+			if _, err := UnmarkNodeForScaleDown(ctx, client, node); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			node, err = client.CoreV1().Nodes().Get(ctx, "n1", metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if _, ok := node.Annotations[CandidateForScaleDownKey]; ok {
+				t.Errorf("Expected the annotation to be removed")
+			}
+			if ScaleDownRecordOf(node) != nil {
+				t.Errorf("Expected the record to be removed")
+			}
+			if HasTaint(node, CandidateForScaleDownKey) || !HasTaint(node, "dedicated=infra") {
+				t.Errorf("Expected only the scale down taint to be removed, got %v", node.Spec.Taints)
+			}
+			if node.Spec.Unschedulable != test.unscheduled {
+				t.Errorf("Expected the node to be cordoned to be %v, got %v", test.unscheduled, node.Spec.Unschedulable)
+			}
+		})
+	}
+}
+
+func TestMarkNodeDoNotDisrupt(t *testing.T) {
+	until := time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		description string
+		annotations map[string]string
+		marked      bool
+	}{
+		{
+			description: "Node without annotations",
+			marked:      true,
+		},
+		{
+			description: "Node annotated by the descheduler",
+			annotations: map[string]string{
+				KarpenterDoNotDisruptKey: "true",
+				DoNotDisruptUntilKey:     until.Add(-time.Hour).Format(time.RFC3339),
+			},
+			marked: true,
+		},
+		{
+			description: "Node annotated by someone else",
+			annotations: map[string]string{KarpenterDoNotDisruptKey: "true"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			ctx := context.Background()
+			client := fake.NewSimpleClientset(&v1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "n1", Annotations: test.annotations},
+			})
+
+			if err := MarkNodeDoNotDisrupt(ctx, client, "n1", until); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			node, err := client.CoreV1().Nodes().Get(ctx, "n1", metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if node.Annotations[KarpenterDoNotDisruptKey] != "true" {
+				t.Errorf("Expected the node to be annotated, got %v", node.Annotations)
+			}
+			recorded, ours := DoNotDisruptUntil(node)
+			if ours != test.marked || (test.marked && !recorded.Equal(until)) {
+				t.Errorf("Expected the node to be recorded as marked until %v to be %v, got %v until %v", until, test.marked, ours, recorded)
+			}
+
+			// removing the annotations only removes the ones the
+			// descheduler set.
+			if _, err := UnmarkNodeDoNotDisrupt(ctx, client, node); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			node, err = client.CoreV1().Nodes().Get(ctx, "n1", metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if _, annotated := node.Annotations[KarpenterDoNotDisruptKey]; annotated == test.marked {
+				t.Errorf("Expected the node to remain annotated to be %v, got %v", !test.marked, annotated)
+			}
+			if _, ours := DoNotDisruptUntil(node); ours {
+				t.Errorf("Expected the record to be removed")
+			}
+		})
+	}
+}
+
+func TestHasTaint(t *testing.T) {
+	node := &v1.Node{
+		Spec: v1.NodeSpec{Taints: []v1.Taint{
+			{Key: "dedicated", Value: "gpu", Effect: v1.TaintEffectNoSchedule},
+			{Key: "infra", Effect: v1.TaintEffectPreferNoSchedule},
+		}},
+	}
+	tests := []struct {
+		description string
+		taint       string
+		matches     bool
+	}{
+		{
+			description: "Taint key",
+			taint:       "dedicated",
+			matches:     true,
+		},
+		{
+			description: "Taint key and value",
+			taint:       "dedicated=gpu",
+			matches:     true,
+		},
+		{
+			description: "Taint key with a different value",
+			taint:       "dedicated=infra",
+			matches:     false,
+		},
+		{
+			description: "Taint without value",
+			taint:       "infra=",
+			matches:     true,
+		},
+		{
+			description: "Unknown taint key",
+			taint:       "spot",
+			matches:     false,
+		},
+	}
+	for _, test := range tests {
+		if matches := HasTaint(node, test.taint); matches != test.matches {
+			t.Errorf("Test %#v failed, expected %v, got %v", test.description, test.matches, matches)
+		}
+	}
+}
+
 func TestPodFitsCurrentNode(t *testing.T) {
 	nodeLabelKey := "kubernetes.io/desiredNode"
 	nodeLabelValue := "yes"
@@ -932,7 +1253,7 @@ func TestPodFitsNodes(t *testing.T) {
 	sharedInformerFactory.WaitForCacheSync(ctx.Done())
 
 	var nodesTraversed sync.Map
-	podFitsNodes(getPodsAssignedToNode, pod, nodes, func(pod *v1.Pod, node *v1.Node) bool {
+	podFitsNodes(getPodsAssignedToNode, nil, pod, nodes, func(pod *v1.Pod, node *v1.Node) bool {
 		nodesTraversed.Store(node.Name, node)
 		return true
 	})
@@ -1014,6 +1335,25 @@ func TestNodeFit(t *testing.T) {
 				test.PodWithPodAntiAffinity(test.BuildTestPod("p2", 1000, 1000, node.Name, nil), "foo", "bar"),
 			},
 		},
+		{
+			description: "Pod requires windows, node without os label",
+			pod: test.BuildTestPod("p1", 1000, 1000, "", func(pod *v1.Pod) {
+				pod.Spec.OS = &v1.PodOS{Name: v1.Windows}
+			}),
+			node:       nodeNolabel,
+			podsOnNode: []*v1.Pod{},
+			err:        errors.New("pod requires \"windows\" operating system, node runs \"linux\""),
+		},
+		{
+			description: "Pod requires windows, node runs windows",
+			pod: test.BuildTestPod("p1", 1000, 1000, "", func(pod *v1.Pod) {
+				pod.Spec.OS = &v1.PodOS{Name: v1.Windows}
+			}),
+			node: test.BuildTestNode("node", 64000, 128*1000*1000*1000, 2, func(node *v1.Node) {
+				node.ObjectMeta.Labels = map[string]string{v1.LabelOSStable: "windows"}
+			}),
+			podsOnNode: []*v1.Pod{},
+		},
 		{
 			description: "Pod fits on node",
 			pod:         test.BuildTestPod("p1", 1000, 1000, "", func(pod *v1.Pod) {}),
@@ -0,0 +1,124 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// Platform is the operating system and the architecture of a node.
+type Platform struct {
+	OS           string
+	Architecture string
+}
+
+// String returns the platform in the os/arch form used by image manifests.
+func (p Platform) String() string {
+	return p.OS + "/" + p.Architecture
+}
+
+// NodePlatform returns the platform of the node, as reported by the kubelet
+// through the kubernetes.io/os and kubernetes.io/arch labels. The
+// architecture is empty when the node does not report it.
+func NodePlatform(node *v1.Node) Platform {
+	return Platform{
+		OS:           OperatingSystem(node),
+		Architecture: node.Labels[v1.LabelArchStable],
+	}
+}
+
+// ImagePlatforms keeps track of the platforms container images are known to
+// run on. The descheduler does not inspect image manifests: an image is known
+// to run on a platform once a node of that platform reports to hold it.
+//
+// A nil ImagePlatforms knows no image.
+type ImagePlatforms struct {
+	images map[string]sets.Set[Platform]
+}
+
+// NewImagePlatforms indexes the images held by the provided nodes by the
+// platform of the nodes.
+func NewImagePlatforms(nodes []*v1.Node) *ImagePlatforms {
+	images := make(map[string]sets.Set[Platform])
+	for _, node := range nodes {
+		platform := NodePlatform(node)
+		for _, image := range node.Status.Images {
+			for _, name := range image.Names {
+				name = normalizeImageName(name)
+				if _, ok := images[name]; !ok {
+					images[name] = sets.New[Platform]()
+				}
+				images[name].Insert(platform)
+			}
+		}
+	}
+	return &ImagePlatforms{images: images}
+}
+
+// PodRunsOn tells if the pod, running on a node of the source platform, can
+// run on a node of the destination platform. The operating system the pod
+// requires must match the destination one. Moving the pod to a different
+// platform also requires all its images to be known to run there, the
+// images of the pod are only known to run on the source platform. When the
+// architecture of any of the platforms is unknown the images are assumed to
+// run on both.
+func (i *ImagePlatforms) PodRunsOn(pod *v1.Pod, source, destination Platform) bool {
+	if pod.Spec.OS != nil && string(pod.Spec.OS.Name) != destination.OS {
+		return false
+	}
+	if source == destination || source.Architecture == "" || destination.Architecture == "" {
+		return true
+	}
+	for _, image := range podImages(pod) {
+		if i == nil || !i.images[normalizeImageName(image)].Has(destination) {
+			return false
+		}
+	}
+	return true
+}
+
+// podImages returns the images of all the containers of the pod.
+func podImages(pod *v1.Pod) []string {
+	var images []string
+	for _, container := range pod.Spec.InitContainers {
+		images = append(images, container.Image)
+	}
+	for _, container := range pod.Spec.Containers {
+		images = append(images, container.Image)
+	}
+	return images
+}
+
+// normalizeImageName expands the short forms of an image reference, e.g.
+// nginx becomes docker.io/library/nginx:latest, so the images of the pods
+// can be compared with the ones reported by the nodes.
+func normalizeImageName(image string) string {
+	name := image
+	slash := strings.Index(name, "/")
+	if slash == -1 {
+		name = "docker.io/library/" + name
+	} else if domain := name[:slash]; !strings.ContainsAny(domain, ".:") && domain != "localhost" {
+		name = "docker.io/" + name
+	}
+	if !strings.Contains(name, "@") && !strings.Contains(name[strings.LastIndex(name, "/"):], ":") {
+		name += ":latest"
+	}
+	return name
+}
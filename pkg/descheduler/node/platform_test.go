@@ -0,0 +1,134 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+
+	"sigs.k8s.io/descheduler/test"
+)
+
+func TestNormalizeImageName(t *testing.T) {
+	for image, expected := range map[string]string{
+		"nginx":                               "docker.io/library/nginx:latest",
+		"nginx:1.25":                          "docker.io/library/nginx:1.25",
+		"bitnami/nginx":                       "docker.io/bitnami/nginx:latest",
+		"docker.io/library/nginx:1.25":        "docker.io/library/nginx:1.25",
+		"registry.k8s.io/pause:3.9":           "registry.k8s.io/pause:3.9",
+		"localhost/app":                       "localhost/app:latest",
+		"registry:5000/app":                   "registry:5000/app:latest",
+		"registry.k8s.io/pause@sha256:abcdef": "registry.k8s.io/pause@sha256:abcdef",
+	} {
+		if name := normalizeImageName(image); name != expected {
+			t.Errorf("expected %q to be normalized to %q, got %q", image, expected, name)
+		}
+	}
+}
+
+func TestImagePlatformsPodRunsOn(t *testing.T) {
+	buildNode := func(name, os, arch string, images ...string) *v1.Node {
+		return test.BuildTestNode(name, 1000, 1000, 10, func(node *v1.Node) {
+			node.Labels[v1.LabelOSStable] = os
+			if arch != "" {
+				node.Labels[v1.LabelArchStable] = arch
+			}
+			node.Status.Images = append(node.Status.Images, v1.ContainerImage{Names: images})
+		})
+	}
+	buildPod := func(apply func(*v1.Pod)) *v1.Pod {
+		return test.BuildTestPod("p1", 100, 0, "n1", func(pod *v1.Pod) {
+			pod.Spec.Containers[0].Image = "nginx:1.25"
+			if apply != nil {
+				apply(pod)
+			}
+		})
+	}
+
+	amd64 := Platform{OS: "linux", Architecture: "amd64"}
+	arm64 := Platform{OS: "linux", Architecture: "arm64"}
+	windows := Platform{OS: "windows", Architecture: "amd64"}
+
+	tests := []struct {
+		name        string
+		nodes       []*v1.Node
+		pod         *v1.Pod
+		destination Platform
+		expected    bool
+	}{
+		{
+			name:        "same platform",
+			pod:         buildPod(nil),
+			destination: amd64,
+			expected:    true,
+		},
+		{
+			name:        "images not known to run on the destination",
+			nodes:       []*v1.Node{buildNode("n1", "linux", "amd64", "docker.io/library/nginx:1.25")},
+			pod:         buildPod(nil),
+			destination: arm64,
+			expected:    false,
+		},
+		{
+			name:        "images held by a node of the destination platform",
+			nodes:       []*v1.Node{buildNode("n2", "linux", "arm64", "docker.io/library/nginx:1.25")},
+			pod:         buildPod(nil),
+			destination: arm64,
+			expected:    true,
+		},
+		{
+			name:  "init container images not known to run on the destination",
+			nodes: []*v1.Node{buildNode("n2", "linux", "arm64", "docker.io/library/nginx:1.25")},
+			pod: buildPod(func(pod *v1.Pod) {
+				pod.Spec.InitContainers = []v1.Container{{Name: "init", Image: "busybox"}}
+			}),
+			destination: arm64,
+			expected:    false,
+		},
+		{
+			name:        "unknown destination architecture",
+			pod:         buildPod(nil),
+			destination: Platform{OS: "linux"},
+			expected:    true,
+		},
+		{
+			name: "pod requiring another operating system",
+			pod: buildPod(func(pod *v1.Pod) {
+				pod.Spec.OS = &v1.PodOS{Name: v1.Windows}
+			}),
+			destination: amd64,
+			expected:    false,
+		},
+		{
+			name:        "images held by a node of another operating system",
+			nodes:       []*v1.Node{buildNode("n2", "windows", "amd64", "docker.io/library/nginx:1.25")},
+			pod:         buildPod(nil),
+			destination: windows,
+			expected:    true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			images := NewImagePlatforms(tc.nodes)
+			if runs := images.PodRunsOn(tc.pod, amd64, tc.destination); runs != tc.expected {
+				t.Errorf("expected the pod to run on %v: %v, got %v", tc.destination, tc.expected, runs)
+			}
+		})
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package pod
 
 import (
+	"fmt"
 	"sort"
 
 	v1 "k8s.io/api/core/v1"
@@ -151,6 +152,45 @@ func BuildGetPodsAssignedToNodeFunc(podInformer cache.SharedIndexInformer) (GetP
 	return getPodsAssignedToNode, nil
 }
 
+// BuildPodsByNodeIndex lists the pods assigned to each of the provided nodes
+// once and returns a function serving them from the resulting index. It is
+// meant to be built once per descheduling cycle so plugins and usage clients
+// do not scan the informer indexer over and over. Pods for which exclude
+// returns true are left out of the results, this allows to hide pods that
+// were evicted after the index was built. Nodes that are not part of the
+// index are looked up through getPodsAssignedToNode.
+func BuildPodsByNodeIndex(
+	nodes []*v1.Node,
+	getPodsAssignedToNode GetPodsAssignedToNodeFunc,
+	exclude func(*v1.Pod) bool,
+) (GetPodsAssignedToNodeFunc, error) {
+	index := make(map[string][]*v1.Pod, len(nodes))
+	for _, node := range nodes {
+		pods, err := getPodsAssignedToNode(node.Name, nil)
+		if err != nil {
+			return nil, fmt.Errorf("error listing pods assigned to %q node: %v", node.Name, err)
+		}
+		index[node.Name] = pods
+	}
+
+	return func(nodeName string, filter FilterFunc) ([]*v1.Pod, error) {
+		if exclude != nil {
+			filter = WrapFilterFuncs(func(pod *v1.Pod) bool { return !exclude(pod) }, filter)
+		}
+		pods, ok := index[nodeName]
+		if !ok {
+			return getPodsAssignedToNode(nodeName, filter)
+		}
+		result := make([]*v1.Pod, 0, len(pods))
+		for _, pod := range pods {
+			if filter == nil || filter(pod) {
+				result = append(result, pod)
+			}
+		}
+		return result, nil
+	}, nil
+}
+
 func ConvertToPods(objs []interface{}, filter FilterFunc) []*v1.Pod {
 	pods := make([]*v1.Pod, 0, len(objs))
 	for _, obj := range objs {
@@ -245,7 +285,9 @@ func IsGuaranteedPod(pod *v1.Pod) bool {
 
 // SortPodsBasedOnPriorityLowToHigh sorts pods based on their priorities from low to high.
 // If pods have same priorities, they will be sorted by QoS in the following order:
-// BestEffort, Burstable, Guaranteed
+// BestEffort, Burstable, Guaranteed. Pods with the same priority and QoS are
+// sorted with the ones not preferring to avoid eviction first. Remaining ties
+// are broken by namespace and name so the order is deterministic.
 func SortPodsBasedOnPriorityLowToHigh(pods []*v1.Pod) {
 	sort.Slice(pods, func(i, j int) bool {
 		if pods[i].Spec.Priority == nil && pods[j].Spec.Priority != nil {
@@ -254,40 +296,51 @@ func SortPodsBasedOnPriorityLowToHigh(pods []*v1.Pod) {
 		if pods[j].Spec.Priority == nil && pods[i].Spec.Priority != nil {
 			return false
 		}
-		if (pods[j].Spec.Priority == nil && pods[i].Spec.Priority == nil) || (*pods[i].Spec.Priority == *pods[j].Spec.Priority) {
-			iIsBestEffortPod := IsBestEffortPod(pods[i])
-			jIsBestEffortPod := IsBestEffortPod(pods[j])
-			iIsBurstablePod := IsBurstablePod(pods[i])
-			jIsBurstablePod := IsBurstablePod(pods[j])
-			iIsGuaranteedPod := IsGuaranteedPod(pods[i])
-			jIsGuaranteedPod := IsGuaranteedPod(pods[j])
-			if (iIsBestEffortPod && jIsBestEffortPod) || (iIsBurstablePod && jIsBurstablePod) || (iIsGuaranteedPod && jIsGuaranteedPod) {
-				iHasNoEvictonPolicy := evictionutils.HaveNoEvictionAnnotation(pods[i])
-				jHasNoEvictonPolicy := evictionutils.HaveNoEvictionAnnotation(pods[j])
-				if !iHasNoEvictonPolicy {
-					return true
-				}
-				if !jHasNoEvictonPolicy {
-					return false
-				}
-				return true
-			}
-			if iIsBestEffortPod {
-				return true
-			}
-			if iIsBurstablePod && jIsGuaranteedPod {
-				return true
-			}
-			return false
+		if pods[i].Spec.Priority != nil && *pods[i].Spec.Priority != *pods[j].Spec.Priority {
+			return *pods[i].Spec.Priority < *pods[j].Spec.Priority
+		}
+
+		if iQoS, jQoS := qosRank(pods[i]), qosRank(pods[j]); iQoS != jQoS {
+			return iQoS < jQoS
+		}
+
+		iHasNoEvictonPolicy := evictionutils.HaveNoEvictionAnnotation(pods[i])
+		jHasNoEvictonPolicy := evictionutils.HaveNoEvictionAnnotation(pods[j])
+		if iHasNoEvictonPolicy != jHasNoEvictonPolicy {
+			return !iHasNoEvictonPolicy
 		}
 
-		return *pods[i].Spec.Priority < *pods[j].Spec.Priority
+		return lessByNamespacedName(pods[i], pods[j])
 	})
 }
 
+// qosRank returns the position of the pod QoS class in the eviction order:
+// BestEffort, Burstable, Guaranteed.
+func qosRank(pod *v1.Pod) int {
+	switch {
+	case IsBestEffortPod(pod):
+		return 0
+	case IsBurstablePod(pod):
+		return 1
+	default:
+		return 2
+	}
+}
+
+// lessByNamespacedName orders pods by namespace and then by name.
+func lessByNamespacedName(a, b *v1.Pod) bool {
+	if a.Namespace != b.Namespace {
+		return a.Namespace < b.Namespace
+	}
+	return a.Name < b.Name
+}
+
 // SortPodsBasedOnAge sorts Pods from oldest to most recent in place
 func SortPodsBasedOnAge(pods []*v1.Pod) {
 	sort.Slice(pods, func(i, j int) bool {
+		if pods[i].CreationTimestamp.Equal(&pods[j].CreationTimestamp) {
+			return lessByNamespacedName(pods[i], pods[j])
+		}
 		return pods[i].CreationTimestamp.Before(&pods[j].CreationTimestamp)
 	})
 }
@@ -224,6 +224,26 @@ func TestSortPodsBasedOnPriorityLowToHigh(t *testing.T) {
 	}
 }
 
+func TestSortPodsBasedOnPriorityLowToHighTies(t *testing.T) {
+	n1 := test.BuildTestNode("n1", 4000, 3000, 9, nil)
+
+	podList := []*v1.Pod{}
+	for _, name := range []string{"p3", "p1", "p2"} {
+		podList = append(podList, test.BuildTestPod(name, 400, 0, n1.Name, func(pod *v1.Pod) {
+			test.SetPodPriority(pod, lowPriority)
+			test.MakeBurstablePod(pod)
+		}))
+	}
+	// "default" namespace sorts after "a" so p3 goes first.
+	podList[0].Namespace = "a"
+
+	SortPodsBasedOnPriorityLowToHigh(podList)
+	expected := []string{"p3", "p1", "p2"}
+	if !reflect.DeepEqual(getPodListNames(podList), expected) {
+		t.Errorf("Pods were sorted in an unexpected order: %v, expected %v", getPodListNames(podList), expected)
+	}
+}
+
 func TestSortPodsBasedOnAge(t *testing.T) {
 	podList := make([]*v1.Pod, 9)
 	n1 := test.BuildTestNode("n1", 4000, 3000, int64(len(podList)), nil)
@@ -307,3 +327,72 @@ func TestGroupByNodeName(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildPodsByNodeIndex(t *testing.T) {
+	n1 := test.BuildTestNode("n1", 2000, 3000, 10, nil)
+	n2 := test.BuildTestNode("n2", 2000, 3000, 10, nil)
+	p1 := test.BuildTestPod("p1", 100, 0, n1.Name, nil)
+	p2 := test.BuildTestPod("p2", 100, 0, n1.Name, nil)
+	p3 := test.BuildTestPod("p3", 100, 0, n2.Name, nil)
+
+	pods := map[string][]*v1.Pod{n1.Name: {p1, p2}, n2.Name: {p3}}
+	calls := map[string]int{}
+	getPodsAssignedToNode := func(nodeName string, filter FilterFunc) ([]*v1.Pod, error) {
+		calls[nodeName]++
+		var result []*v1.Pod
+		for _, pod := range pods[nodeName] {
+			if filter == nil || filter(pod) {
+				result = append(result, pod)
+			}
+		}
+		return result, nil
+	}
+
+	evicted := map[string]bool{}
+	index, err := BuildPodsByNodeIndex(
+		[]*v1.Node{n1}, getPodsAssignedToNode,
+		func(pod *v1.Pod) bool { return evicted[pod.Name] },
+	)
+	if err != nil {
+		t.Fatalf("unexpected error building the index: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		result, err := ListPodsOnANode(n1.Name, index, nil)
+		if err != nil {
+			t.Fatalf("unexpected error listing pods: %v", err)
+		}
+		if len(result) != 2 {
+			t.Errorf("expected 2 pods on %v, got %v", n1.Name, len(result))
+		}
+	}
+	if calls[n1.Name] != 1 {
+		t.Errorf("expected pods on %v to be listed once, got %v", n1.Name, calls[n1.Name])
+	}
+
+	result, err := ListPodsOnANode(n1.Name, index, func(pod *v1.Pod) bool { return pod.Name != p1.Name })
+	if err != nil {
+		t.Fatalf("unexpected error listing pods: %v", err)
+	}
+	if !reflect.DeepEqual(result, []*v1.Pod{p2}) {
+		t.Errorf("expected the filter to be applied, got %v", result)
+	}
+
+	evicted[p2.Name] = true
+	result, err = ListPodsOnANode(n1.Name, index, nil)
+	if err != nil {
+		t.Fatalf("unexpected error listing pods: %v", err)
+	}
+	if !reflect.DeepEqual(result, []*v1.Pod{p1}) {
+		t.Errorf("expected evicted pods to be excluded, got %v", result)
+	}
+
+	// nodes that were not indexed are delegated.
+	result, err = ListPodsOnANode(n2.Name, index, nil)
+	if err != nil {
+		t.Fatalf("unexpected error listing pods: %v", err)
+	}
+	if !reflect.DeepEqual(result, []*v1.Pod{p3}) || calls[n2.Name] != 1 {
+		t.Errorf("expected pods on %v to be listed through the fallback, got %v", n2.Name, result)
+	}
+}
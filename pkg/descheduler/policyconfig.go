@@ -19,10 +19,14 @@ package descheduler
 import (
 	"context"
 	"fmt"
+	"maps"
 	"net/url"
 	"os"
+	"slices"
+	"strings"
 
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	clientset "k8s.io/client-go/kubernetes"
@@ -145,6 +149,17 @@ func validateDeschedulerConfiguration(in api.DeschedulerPolicy, registry pluginr
 				errorsInPolicy = append(errorsInPolicy, fmt.Errorf("in profile %s: plugin %s in pluginConfig not registered", profile.Name, pluginConfig.Name))
 				continue
 			}
+			for _, os := range pluginConfig.ExcludedOperatingSystems {
+				if errs := validation.IsValidLabelValue(os); os == "" || len(errs) > 0 {
+					errorsInPolicy = append(errorsInPolicy, fmt.Errorf("in profile %s: plugin %s excludes invalid operating system %q", profile.Name, pluginConfig.Name, os))
+				}
+			}
+			for _, taint := range pluginConfig.ExcludedTaints {
+				key, value, _ := strings.Cut(taint, "=")
+				if len(validation.IsQualifiedName(key)) > 0 || len(validation.IsValidLabelValue(value)) > 0 {
+					errorsInPolicy = append(errorsInPolicy, fmt.Errorf("in profile %s: plugin %s excludes invalid taint %q", profile.Name, pluginConfig.Name, taint))
+				}
+			}
 
 			pluginUtilities := registry[pluginConfig.Name]
 			if pluginUtilities.PluginArgValidator == nil {
@@ -189,6 +204,82 @@ func validateDeschedulerConfiguration(in api.DeschedulerPolicy, registry pluginr
 					errorsInPolicy = append(errorsInPolicy, fmt.Errorf("prometheus authToken secret reference does not set both namespace and name"))
 				}
 			}
+
+			credentials := 0
+			if prometheusConfig.Prometheus.AuthToken != nil {
+				credentials++
+			}
+			if prometheusConfig.Prometheus.TokenFile != "" {
+				credentials++
+			}
+			if basicAuth := prometheusConfig.Prometheus.BasicAuth; basicAuth != nil {
+				credentials++
+				if basicAuth.Username == "" || basicAuth.PasswordFile == "" {
+					errorsInPolicy = append(errorsInPolicy, fmt.Errorf("prometheus basicAuth does not set both username and passwordFile"))
+				}
+			}
+			if credentials > 1 {
+				errorsInPolicy = append(errorsInPolicy, fmt.Errorf("only one of prometheus authToken, tokenFile and basicAuth can be set"))
+			}
+			if tls := prometheusConfig.Prometheus.TLS; tls != nil && (tls.CertFile == "") != (tls.KeyFile == "") {
+				errorsInPolicy = append(errorsInPolicy, fmt.Errorf("prometheus tls does not set both certFile and keyFile"))
+			}
+			for _, name := range slices.Sorted(maps.Keys(prometheusConfig.Prometheus.Headers)) {
+				if errs := validation.IsHTTPHeaderName(name); len(errs) > 0 {
+					errorsInPolicy = append(errorsInPolicy, fmt.Errorf("prometheus header %q is not valid: %s", name, strings.Join(errs, ", ")))
+				} else if strings.EqualFold(name, "Authorization") {
+					errorsInPolicy = append(errorsInPolicy, fmt.Errorf("prometheus header %q can not be set, use the authentication settings instead", name))
+				}
+			}
+		}
+	}
+
+	if in.ConnectionDraining != nil {
+		if in.ConnectionDraining.Port < 1 || in.ConnectionDraining.Port > 65535 {
+			errorsInPolicy = append(errorsInPolicy, fmt.Errorf("connection draining port %d is not in [1, 65535] range", in.ConnectionDraining.Port))
+		}
+		if in.ConnectionDraining.Timeout != nil && in.ConnectionDraining.Timeout.Duration < 0 {
+			errorsInPolicy = append(errorsInPolicy, fmt.Errorf("connection draining timeout must not be negative"))
+		}
+		if in.ConnectionDraining.Budget != nil && in.ConnectionDraining.Budget.Duration < 0 {
+			errorsInPolicy = append(errorsInPolicy, fmt.Errorf("connection draining budget must not be negative"))
+		}
+	}
+
+	if in.SessionDraining != nil {
+		if errs := validation.IsQualifiedName(in.SessionDraining.ReadinessGate); len(errs) > 0 {
+			errorsInPolicy = append(errorsInPolicy, fmt.Errorf("session draining readiness gate %q is not valid: %s", in.SessionDraining.ReadinessGate, strings.Join(errs, ", ")))
+		}
+		if in.SessionDraining.Annotation != "" {
+			if errs := validation.IsQualifiedName(in.SessionDraining.Annotation); len(errs) > 0 {
+				errorsInPolicy = append(errorsInPolicy, fmt.Errorf("session draining annotation %q is not valid: %s", in.SessionDraining.Annotation, strings.Join(errs, ", ")))
+			}
+		}
+		if in.SessionDraining.Period != nil && in.SessionDraining.Period.Duration < 0 {
+			errorsInPolicy = append(errorsInPolicy, fmt.Errorf("session draining period must not be negative"))
+		}
+		if in.SessionDraining.Budget != nil && in.SessionDraining.Budget.Duration < 0 {
+			errorsInPolicy = append(errorsInPolicy, fmt.Errorf("session draining budget must not be negative"))
+		}
+	}
+
+	if in.Notifications != nil {
+		u, err := url.Parse(in.Notifications.WebhookURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errorsInPolicy = append(errorsInPolicy, fmt.Errorf("notifications webhook URL must be an http or https URL"))
+		}
+		if in.Notifications.Timeout != nil && in.Notifications.Timeout.Duration < 0 {
+			errorsInPolicy = append(errorsInPolicy, fmt.Errorf("notifications timeout must not be negative"))
+		}
+	}
+
+	if in.CloudEvents != nil {
+		u, err := url.Parse(in.CloudEvents.SinkURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errorsInPolicy = append(errorsInPolicy, fmt.Errorf("cloudEvents sink URL must be an http or https URL"))
+		}
+		if in.CloudEvents.Timeout != nil && in.CloudEvents.Timeout.Duration < 0 {
+			errorsInPolicy = append(errorsInPolicy, fmt.Errorf("cloudEvents timeout must not be negative"))
 		}
 	}
 
@@ -20,8 +20,10 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/conversion"
 	fakeclientset "k8s.io/client-go/kubernetes/fake"
 	utilptr "k8s.io/utils/ptr"
@@ -122,6 +124,60 @@ profiles:
 				},
 			},
 		},
+		{
+			description: "v1alpha2 to internal, excluded nodes",
+			policy: []byte(`apiVersion: "descheduler/v1alpha2"
+kind: "DeschedulerPolicy"
+profiles:
+  - name: ProfileName
+    pluginConfig:
+    - name: "RemovePodsHavingTooManyRestarts"
+      excludedOperatingSystems:
+      - windows
+      excludedTaints:
+      - dedicated=gpu
+      args:
+        podRestartThreshold: 100
+    plugins:
+      deschedule:
+        enabled:
+          - "RemovePodsHavingTooManyRestarts"
+`),
+			result: &api.DeschedulerPolicy{
+				Profiles: []api.DeschedulerProfile{
+					{
+						Name: "ProfileName",
+						PluginConfigs: []api.PluginConfig{
+							{
+								Name: defaultevictor.PluginName,
+								Args: &defaultevictor.DefaultEvictorArgs{
+									PriorityThreshold: &api.PriorityThreshold{Value: utilptr.To[int32](2000000000)},
+								},
+							},
+							{
+								Name: removepodshavingtoomanyrestarts.PluginName,
+								Args: &removepodshavingtoomanyrestarts.RemovePodsHavingTooManyRestartsArgs{
+									PodRestartThreshold: 100,
+								},
+								ExcludedOperatingSystems: []string{"windows"},
+								ExcludedTaints:           []string{"dedicated=gpu"},
+							},
+						},
+						Plugins: api.Plugins{
+							Filter: api.PluginSet{
+								Enabled: []string{defaultevictor.PluginName},
+							},
+							PreEvictionFilter: api.PluginSet{
+								Enabled: []string{defaultevictor.PluginName},
+							},
+							Deschedule: api.PluginSet{
+								Enabled: []string{removepodshavingtoomanyrestarts.PluginName},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			description: "v1alpha2 to internal, validate error handling (priorityThreshold exceeding maximum)",
 			policy: []byte(`apiVersion: "descheduler/v1alpha2"
@@ -211,6 +267,48 @@ func TestValidateDeschedulerConfiguration(t *testing.T) {
 			},
 			result: fmt.Errorf("[in profile RemoveFailedPods: only one of Include/Exclude namespaces can be set, in profile RemovePodsViolatingTopologySpreadConstraint: only one of Include/Exclude namespaces can be set]"),
 		},
+		{
+			description: "invalid excluded operating system error",
+			deschedulerPolicy: api.DeschedulerPolicy{
+				Profiles: []api.DeschedulerProfile{
+					{
+						Name: removefailedpods.PluginName,
+						Plugins: api.Plugins{
+							Deschedule: api.PluginSet{Enabled: []string{removefailedpods.PluginName}},
+						},
+						PluginConfigs: []api.PluginConfig{
+							{
+								Name:                     removefailedpods.PluginName,
+								Args:                     &removefailedpods.RemoveFailedPodsArgs{},
+								ExcludedOperatingSystems: []string{"windows", "not an os"},
+							},
+						},
+					},
+				},
+			},
+			result: fmt.Errorf("in profile RemoveFailedPods: plugin RemoveFailedPods excludes invalid operating system \"not an os\""),
+		},
+		{
+			description: "invalid excluded taint error",
+			deschedulerPolicy: api.DeschedulerPolicy{
+				Profiles: []api.DeschedulerProfile{
+					{
+						Name: removefailedpods.PluginName,
+						Plugins: api.Plugins{
+							Deschedule: api.PluginSet{Enabled: []string{removefailedpods.PluginName}},
+						},
+						PluginConfigs: []api.PluginConfig{
+							{
+								Name:           removefailedpods.PluginName,
+								Args:           &removefailedpods.RemoveFailedPodsArgs{},
+								ExcludedTaints: []string{"dedicated=gpu", "infra", "=gpu", "dedicated=not valid"},
+							},
+						},
+					},
+				},
+			},
+			result: fmt.Errorf("[in profile RemoveFailedPods: plugin RemoveFailedPods excludes invalid taint \"=gpu\", in profile RemoveFailedPods: plugin RemoveFailedPods excludes invalid taint \"dedicated=not valid\"]"),
+		},
 		{
 			description: "Duplicit metrics providers error",
 			deschedulerPolicy: api.DeschedulerPolicy{
@@ -305,6 +403,95 @@ func TestValidateDeschedulerConfiguration(t *testing.T) {
 			},
 			result: fmt.Errorf("prometheus authToken secret reference does not set both namespace and name"),
 		},
+		{
+			description: "prometheus authtoken and token file error",
+			deschedulerPolicy: api.DeschedulerPolicy{
+				MetricsProviders: []api.MetricsProvider{
+					{
+						Source: api.PrometheusMetrics,
+						Prometheus: &api.Prometheus{
+							URL: "https://example.com:80",
+							AuthToken: &api.AuthToken{
+								SecretReference: &api.SecretReference{
+									Namespace: "secretnamespace",
+									Name:      "secretname",
+								},
+							},
+							TokenFile: "/etc/prometheus/token",
+						},
+					},
+				},
+			},
+			result: fmt.Errorf("only one of prometheus authToken, tokenFile and basicAuth can be set"),
+		},
+		{
+			description: "prometheus basic auth missing password file error",
+			deschedulerPolicy: api.DeschedulerPolicy{
+				MetricsProviders: []api.MetricsProvider{
+					{
+						Source: api.PrometheusMetrics,
+						Prometheus: &api.Prometheus{
+							URL:       "https://example.com:80",
+							BasicAuth: &api.BasicAuth{Username: "descheduler"},
+						},
+					},
+				},
+			},
+			result: fmt.Errorf("prometheus basicAuth does not set both username and passwordFile"),
+		},
+		{
+			description: "prometheus client certificate missing key error",
+			deschedulerPolicy: api.DeschedulerPolicy{
+				MetricsProviders: []api.MetricsProvider{
+					{
+						Source: api.PrometheusMetrics,
+						Prometheus: &api.Prometheus{
+							URL: "https://example.com:80",
+							TLS: &api.PrometheusTLS{CertFile: "/etc/prometheus/tls.crt"},
+						},
+					},
+				},
+			},
+			result: fmt.Errorf("prometheus tls does not set both certFile and keyFile"),
+		},
+		{
+			description: "prometheus authorization header error",
+			deschedulerPolicy: api.DeschedulerPolicy{
+				MetricsProviders: []api.MetricsProvider{
+					{
+						Source: api.PrometheusMetrics,
+						Prometheus: &api.Prometheus{
+							URL:     "https://example.com:80",
+							Headers: map[string]string{"authorization": "Bearer token"},
+						},
+					},
+				},
+			},
+			result: fmt.Errorf("prometheus header \"authorization\" can not be set, use the authentication settings instead"),
+		},
+		{
+			description: "valid prometheus mutual tls with basic auth and headers",
+			deschedulerPolicy: api.DeschedulerPolicy{
+				MetricsProviders: []api.MetricsProvider{
+					{
+						Source: api.PrometheusMetrics,
+						Prometheus: &api.Prometheus{
+							URL: "https://example.com:80",
+							BasicAuth: &api.BasicAuth{
+								Username:     "descheduler",
+								PasswordFile: "/etc/prometheus/password",
+							},
+							TLS: &api.PrometheusTLS{
+								CAFile:   "/etc/prometheus/ca.crt",
+								CertFile: "/etc/prometheus/tls.crt",
+								KeyFile:  "/etc/prometheus/tls.key",
+							},
+							Headers: map[string]string{"X-Scope-OrgID": "tenant"},
+						},
+					},
+				},
+			},
+		},
 		{
 			description: "prometheus authtoken missing secret reference namespace error",
 			deschedulerPolicy: api.DeschedulerPolicy{
@@ -362,6 +549,101 @@ func TestValidateDeschedulerConfiguration(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "connection draining port out of range",
+			deschedulerPolicy: api.DeschedulerPolicy{
+				ConnectionDraining: &api.ConnectionDraining{
+					Port: 70000,
+					Path: "/drain",
+				},
+			},
+			result: fmt.Errorf("connection draining port 70000 is not in [1, 65535] range"),
+		},
+		{
+			description: "connection draining negative budget",
+			deschedulerPolicy: api.DeschedulerPolicy{
+				ConnectionDraining: &api.ConnectionDraining{
+					Port:   15000,
+					Budget: &metav1.Duration{Duration: -time.Minute},
+				},
+			},
+			result: fmt.Errorf("connection draining budget must not be negative"),
+		},
+		{
+			description: "valid connection draining",
+			deschedulerPolicy: api.DeschedulerPolicy{
+				ConnectionDraining: &api.ConnectionDraining{
+					Port:    15000,
+					Path:    "/drain_listeners?graceful",
+					Timeout: &metav1.Duration{Duration: time.Minute},
+				},
+			},
+		},
+		{
+			description: "session draining invalid readiness gate",
+			deschedulerPolicy: api.DeschedulerPolicy{
+				SessionDraining: &api.SessionDraining{
+					ReadinessGate: "example.com/not a condition",
+				},
+			},
+			result: fmt.Errorf("session draining readiness gate \"example.com/not a condition\" is not valid: name part must consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyName',  or 'my.name',  or '123-abc', regex used for validation is '([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]')"),
+		},
+		{
+			description: "session draining negative budget",
+			deschedulerPolicy: api.DeschedulerPolicy{
+				SessionDraining: &api.SessionDraining{
+					ReadinessGate: "example.com/serving",
+					Budget:        &metav1.Duration{Duration: -time.Minute},
+				},
+			},
+			result: fmt.Errorf("session draining budget must not be negative"),
+		},
+		{
+			description: "valid session draining",
+			deschedulerPolicy: api.DeschedulerPolicy{
+				SessionDraining: &api.SessionDraining{
+					ReadinessGate: "example.com/serving",
+					Annotation:    "example.com/draining",
+					Period:        &metav1.Duration{Duration: time.Minute},
+				},
+			},
+		},
+		{
+			description: "notifications webhook URL without scheme",
+			deschedulerPolicy: api.DeschedulerPolicy{
+				Notifications: &api.Notifications{
+					WebhookURL: "hooks.slack.com/services/T000/B000/XXXX",
+				},
+			},
+			result: fmt.Errorf("notifications webhook URL must be an http or https URL"),
+		},
+		{
+			description: "valid notifications",
+			deschedulerPolicy: api.DeschedulerPolicy{
+				Notifications: &api.Notifications{
+					WebhookURL:     "https://hooks.slack.com/services/T000/B000/XXXX",
+					AlertThreshold: utilptr.To[uint](50),
+				},
+			},
+		},
+		{
+			description: "cloud events sink URL without host",
+			deschedulerPolicy: api.DeschedulerPolicy{
+				CloudEvents: &api.CloudEvents{
+					SinkURL: "http://",
+				},
+			},
+			result: fmt.Errorf("cloudEvents sink URL must be an http or https URL"),
+		},
+		{
+			description: "valid cloud events",
+			deschedulerPolicy: api.DeschedulerPolicy{
+				CloudEvents: &api.CloudEvents{
+					SinkURL: "http://kafka-sink-ingress.knative-eventing.svc/default/descheduler",
+					Source:  "descheduler/prod-cluster",
+				},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -0,0 +1,229 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package descheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	clientset "k8s.io/client-go/kubernetes"
+	fakeclientset "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	fakemetricsclient "k8s.io/metrics/pkg/client/clientset/versioned/fake"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/descheduler/cmd/descheduler/app/options"
+	"sigs.k8s.io/descheduler/pkg/api"
+	"sigs.k8s.io/descheduler/pkg/descheduler/evictions"
+	nodeutil "sigs.k8s.io/descheduler/pkg/descheduler/node"
+	"sigs.k8s.io/descheduler/pkg/features"
+	"sigs.k8s.io/descheduler/pkg/framework/pluginregistry"
+)
+
+// defaultScenarioSeed seeds the randomized choices of the scenarios not
+// providing a seed, scenarios are always run in seeded mode.
+const defaultScenarioSeed = 1
+
+var (
+	nodeMetricsGVR = schema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "nodes"}
+	podMetricsGVR  = schema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "pods"}
+)
+
+// Scenario describes the state of a cluster and the policy to run against
+// it. Scenarios answer "what if" questions without access to a cluster.
+type Scenario struct {
+	// Nodes of the cluster.
+	Nodes []v1.Node `json:"nodes"`
+	// Pods of the cluster. Pods without a UID get one derived from their
+	// namespace and name, pods without a phase are running.
+	Pods []v1.Pod `json:"pods"`
+	// Metrics served through the metrics.k8s.io API, used by policies
+	// relying on the actual utilization.
+	Metrics ScenarioMetrics `json:"metrics,omitempty"`
+	// Policy is the descheduler policy, as it is written in a policy file.
+	Policy json.RawMessage `json:"policy"`
+	// Seed of the randomized choices, as the --seed flag. Scenarios are
+	// always run in seeded mode, a seed of 1 is used when none is set.
+	Seed int64 `json:"seed,omitempty"`
+}
+
+// ScenarioMetrics are the node and pod metrics of a scenario.
+type ScenarioMetrics struct {
+	Nodes []v1beta1.NodeMetrics `json:"nodes,omitempty"`
+	Pods  []v1beta1.PodMetrics  `json:"pods,omitempty"`
+}
+
+// DecisionReport describes the decisions taken by a policy when run
+// against a scenario.
+type DecisionReport struct {
+	// Evictions in the order they were performed.
+	Evictions []evictions.Decision `json:"evictions"`
+	// Nodes considered by the policy, sorted by name.
+	Nodes []NodeReport `json:"nodes"`
+}
+
+// NodeReport describes the effect of the evictions on a node.
+type NodeReport struct {
+	Name       string `json:"name"`
+	PodsBefore int    `json:"podsBefore"`
+	PodsAfter  int    `json:"podsAfter"`
+}
+
+// LoadScenario reads a scenario from a YAML or JSON file.
+func LoadScenario(scenarioFile string) (*Scenario, error) {
+	data, err := os.ReadFile(scenarioFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scenario file %q: %v", scenarioFile, err)
+	}
+
+	scenario := &Scenario{}
+	if err := yaml.UnmarshalStrict(data, scenario); err != nil {
+		return nil, fmt.Errorf("failed decoding scenario %q: %v", scenarioFile, err)
+	}
+	return scenario, nil
+}
+
+// RunScenario runs a single descheduling cycle of the scenario policy, in
+// dry run mode, against the scenario objects and reports the decisions
+// taken. The plugins referred by the policy must be registered.
+func RunScenario(ctx context.Context, scenario *Scenario) (*DecisionReport, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var objects []runtime.Object
+	for i := range scenario.Nodes {
+		objects = append(objects, scenario.Nodes[i].DeepCopy())
+	}
+	for i := range scenario.Pods {
+		pod := scenario.Pods[i].DeepCopy()
+		if pod.UID == "" {
+			pod.UID = types.UID(pod.Namespace + "/" + pod.Name)
+		}
+		if pod.Status.Phase == "" {
+			pod.Status.Phase = v1.PodRunning
+		}
+		objects = append(objects, pod)
+	}
+	client := fakeclientset.NewSimpleClientset(objects...)
+
+	deschedulerPolicy, err := decode("scenario", scenario.Policy, client, pluginregistry.PluginRegistry)
+	if err != nil {
+		return nil, err
+	}
+
+	rs, err := options.NewDeschedulerServer()
+	if err != nil {
+		return nil, fmt.Errorf("unable to initialize server: %v", err)
+	}
+	rs.Client = client
+	rs.EventClient = fakeclientset.NewSimpleClientset()
+	rs.DryRun = true
+	rs.DisableMetrics = true
+	rs.Seed = scenario.Seed
+	if rs.Seed == 0 {
+		rs.Seed = defaultScenarioSeed
+	}
+	rs.DefaultFeatureGates = features.DefaultMutableFeatureGate
+
+	if len(scenario.Metrics.Nodes) > 0 || len(scenario.Metrics.Pods) > 0 {
+		metricsClient := fakemetricsclient.NewSimpleClientset()
+		for i := range scenario.Metrics.Nodes {
+			if err := metricsClient.Tracker().Create(nodeMetricsGVR, scenario.Metrics.Nodes[i].DeepCopy(), ""); err != nil {
+				return nil, fmt.Errorf("unable to add node metrics: %v", err)
+			}
+		}
+		for i := range scenario.Metrics.Pods {
+			podMetrics := scenario.Metrics.Pods[i].DeepCopy()
+			if err := metricsClient.Tracker().Create(podMetricsGVR, podMetrics, podMetrics.Namespace); err != nil {
+				return nil, fmt.Errorf("unable to add pod metrics: %v", err)
+			}
+		}
+		rs.MetricsClient = metricsClient
+	}
+
+	sharedInformerFactory := newSharedInformerFactory(client)
+	desch, err := newDescheduler(ctx, rs, deschedulerPolicy, "v1", &events.FakeRecorder{}, sharedInformerFactory, nil)
+	if err != nil {
+		return nil, err
+	}
+	sharedInformerFactory.Start(ctx.Done())
+	sharedInformerFactory.WaitForCacheSync(ctx.Done())
+
+	return runReportedCycle(ctx, desch, client, deschedulerPolicy)
+}
+
+// runReportedCycle collects the metrics, if any, then runs a single
+// descheduling cycle and reports the decisions taken. The descheduler is
+// expected to run in dry run mode with its informers synced.
+func runReportedCycle(ctx context.Context, desch *descheduler, client clientset.Interface, deschedulerPolicy *api.DeschedulerPolicy) (*DecisionReport, error) {
+	if desch.metricsCollector != nil {
+		if err := desch.metricsCollector.Collect(ctx); err != nil {
+			return nil, fmt.Errorf("unable to collect metrics: %v", err)
+		}
+	}
+
+	var nodeSelector string
+	if deschedulerPolicy.NodeSelector != nil {
+		nodeSelector = *deschedulerPolicy.NodeSelector
+	}
+	nodes, err := nodeutil.ReadyNodes(ctx, client, desch.sharedInformerFactory.Core().V1().Nodes().Lister(), nodeSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	podsBefore := map[string]int{}
+	for _, node := range nodes {
+		pods, err := desch.getPodsAssignedToNode(node.Name, nil)
+		if err != nil {
+			return nil, err
+		}
+		podsBefore[node.Name] = len(pods)
+	}
+
+	if err := desch.runDeschedulerLoop(ctx, nodes); err != nil {
+		return nil, err
+	}
+
+	report := &DecisionReport{Evictions: desch.podEvictor.Decisions()}
+	if report.Evictions == nil {
+		report.Evictions = []evictions.Decision{}
+	}
+
+	evicted := map[string]int{}
+	for _, decision := range report.Evictions {
+		evicted[decision.Node]++
+	}
+	for _, node := range nodes {
+		report.Nodes = append(report.Nodes, NodeReport{
+			Name:       node.Name,
+			PodsBefore: podsBefore[node.Name],
+			PodsAfter:  podsBefore[node.Name] - evicted[node.Name],
+		})
+	}
+	sort.Slice(report.Nodes, func(i, j int) bool {
+		return report.Nodes[i].Name < report.Nodes[j].Name
+	})
+	return report, nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package descheduler
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"sigs.k8s.io/yaml"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden decision reports of the scenarios")
+
+// TestScenarios runs every scenario under testdata/scenarios and compares
+// its decision report with the golden report stored next to it. Run the
+// test with -update to regenerate the golden reports.
+func TestScenarios(t *testing.T) {
+	scenarioFiles, err := filepath.Glob(filepath.Join("testdata", "scenarios", "*.yaml"))
+	if err != nil {
+		t.Fatalf("Unable to list scenarios: %v", err)
+	}
+	if len(scenarioFiles) == 0 {
+		t.Fatalf("No scenario found")
+	}
+
+	for _, scenarioFile := range scenarioFiles {
+		name := strings.TrimSuffix(filepath.Base(scenarioFile), ".yaml")
+		t.Run(name, func(t *testing.T) {
+			SetupPlugins()
+
+			scenario, err := LoadScenario(scenarioFile)
+			if err != nil {
+				t.Fatalf("Unable to load scenario: %v", err)
+			}
+			report, err := RunScenario(context.Background(), scenario)
+			if err != nil {
+				t.Fatalf("Unable to run scenario: %v", err)
+			}
+			got, err := yaml.Marshal(report)
+			if err != nil {
+				t.Fatalf("Unable to encode the decision report: %v", err)
+			}
+
+			goldenFile := strings.TrimSuffix(scenarioFile, ".yaml") + ".golden"
+			if *updateGolden {
+				if err := os.WriteFile(goldenFile, got, 0o644); err != nil {
+					t.Fatalf("Unable to update the golden report: %v", err)
+				}
+			}
+			want, err := os.ReadFile(goldenFile)
+			if err != nil {
+				t.Fatalf("Unable to read the golden report: %v", err)
+			}
+			if diff := cmp.Diff(string(want), string(got)); diff != "" {
+				t.Errorf("Decision report differs from %v (-want +got):\n%s", goldenFile, diff)
+			}
+		})
+	}
+}
+
+func TestLoadScenarioRejectsUnknownFields(t *testing.T) {
+	scenarioFile := filepath.Join(t.TempDir(), "scenario.yaml")
+	if err := os.WriteFile(scenarioFile, []byte("nodes: []\npods: []\npolicies: {}\n"), 0o644); err != nil {
+		t.Fatalf("Unable to write scenario: %v", err)
+	}
+	if _, err := LoadScenario(scenarioFile); err == nil {
+		t.Errorf("Expected an error loading a scenario with unknown fields")
+	}
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package descheduler
+
+import (
+	"cmp"
+	"slices"
+
+	v1 "k8s.io/api/core/v1"
+
+	podutil "sigs.k8s.io/descheduler/pkg/descheduler/pod"
+	frameworktypes "sigs.k8s.io/descheduler/pkg/framework/types"
+)
+
+// seededNodeOrder returns a copy of the nodes in an order that only depends
+// on the seed of the source and on the node names.
+func seededNodeOrder(rand *frameworktypes.Rand, nodes []*v1.Node) []*v1.Node {
+	result := slices.Clone(nodes)
+	slices.SortFunc(result, func(a, b *v1.Node) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	rand.Derive("nodes").Shuffle(len(result), func(i, j int) {
+		result[i], result[j] = result[j], result[i]
+	})
+	return result
+}
+
+// seededPodOrder wraps getPodsAssignedToNode so the pods of every node are
+// returned in an order that only depends on the seed of the source, on
+// the node name and on the pod namespaces and names.
+func seededPodOrder(rand *frameworktypes.Rand, getPodsAssignedToNode podutil.GetPodsAssignedToNodeFunc) podutil.GetPodsAssignedToNodeFunc {
+	return func(nodeName string, filter podutil.FilterFunc) ([]*v1.Pod, error) {
+		pods, err := getPodsAssignedToNode(nodeName, filter)
+		if err != nil {
+			return nil, err
+		}
+		pods = slices.Clone(pods)
+		slices.SortFunc(pods, func(a, b *v1.Pod) int {
+			return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+		})
+		rand.Derive("pods/"+nodeName).Shuffle(len(pods), func(i, j int) {
+			pods[i], pods[j] = pods[j], pods[i]
+		})
+		return pods, nil
+	}
+}
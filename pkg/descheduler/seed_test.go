@@ -0,0 +1,150 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package descheduler
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/api/core/v1"
+
+	podutil "sigs.k8s.io/descheduler/pkg/descheduler/pod"
+	frameworktypes "sigs.k8s.io/descheduler/pkg/framework/types"
+	"sigs.k8s.io/descheduler/test"
+)
+
+func nodeNames(nodes []*v1.Node) []string {
+	var names []string
+	for _, node := range nodes {
+		names = append(names, node.Name)
+	}
+	return names
+}
+
+func podNames(pods []*v1.Pod) []string {
+	var names []string
+	for _, pod := range pods {
+		names = append(names, pod.Namespace+"/"+pod.Name)
+	}
+	return names
+}
+
+func TestSeededNodeOrder(t *testing.T) {
+	var nodes []*v1.Node
+	for i := 0; i < 10; i++ {
+		nodes = append(nodes, test.BuildTestNode(fmt.Sprintf("n%d", i), 1000, 1000, 10, nil))
+	}
+	reversed := slices.Clone(nodes)
+	slices.Reverse(reversed)
+
+	expected := nodeNames(seededNodeOrder(frameworktypes.NewRand(42), nodes))
+	if got := nodeNames(seededNodeOrder(frameworktypes.NewRand(42), reversed)); !cmp.Equal(expected, got) {
+		t.Errorf("Expected the same order for the same seed, got %v and %v", expected, got)
+	}
+	if got := nodeNames(nodes); got[0] != "n0" {
+		t.Errorf("Expected the provided nodes to be left untouched, got %v", got)
+	}
+
+	sorted := slices.Clone(expected)
+	slices.Sort(sorted)
+	if !cmp.Equal(sorted, nodeNames(nodes)) {
+		t.Errorf("Expected a permutation of the nodes, got %v", expected)
+	}
+
+	differs := false
+	for seed := int64(1); seed < 10 && !differs; seed++ {
+		differs = !cmp.Equal(expected, nodeNames(seededNodeOrder(frameworktypes.NewRand(seed), nodes)))
+	}
+	if !differs {
+		t.Errorf("Expected different seeds to produce different orders")
+	}
+}
+
+func TestSeededPodOrder(t *testing.T) {
+	var pods []*v1.Pod
+	for i := 0; i < 10; i++ {
+		pods = append(pods, test.BuildTestPod(fmt.Sprintf("p%d", i), 100, 0, "n1", nil))
+	}
+	listed := func(order []*v1.Pod) podutil.GetPodsAssignedToNodeFunc {
+		return func(nodeName string, filter podutil.FilterFunc) ([]*v1.Pod, error) {
+			return slices.Clone(order), nil
+		}
+	}
+	reversed := slices.Clone(pods)
+	slices.Reverse(reversed)
+
+	rand := frameworktypes.NewRand(42)
+	first, err := seededPodOrder(rand, listed(pods))("n1", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	second, err := seededPodOrder(rand, listed(reversed))("n1", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !cmp.Equal(podNames(first), podNames(second)) {
+		t.Errorf("Expected the same order for the same seed, got %v and %v", podNames(first), podNames(second))
+	}
+
+	// every node gets its own order so the order of a node does not
+	// depend on the nodes listed before it.
+	other, err := seededPodOrder(rand, listed(pods))("n2", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cmp.Equal(podNames(first), podNames(other)) {
+		t.Errorf("Expected different nodes to get different orders, got %v", podNames(first))
+	}
+}
+
+// TestScenarioSeed checks runs sharing a seed take the same decisions when
+// pods can't be told apart.
+func TestScenarioSeed(t *testing.T) {
+	SetupPlugins()
+
+	scenario, err := LoadScenario(filepath.Join("testdata", "scenarios", "lownodeutilization-requests.yaml"))
+	if err != nil {
+		t.Fatalf("Unable to load scenario: %v", err)
+	}
+
+	evicted := func(seed int64) []string {
+		scenario.Seed = seed
+		report, err := RunScenario(context.Background(), scenario)
+		if err != nil {
+			t.Fatalf("Unable to run scenario: %v", err)
+		}
+		var pods []string
+		for _, decision := range report.Evictions {
+			pods = append(pods, decision.Namespace+"/"+decision.Pod)
+		}
+		return pods
+	}
+
+	expected := evicted(7)
+	if len(expected) == 0 {
+		t.Fatalf("Expected the scenario to evict pods")
+	}
+	for i := 0; i < 3; i++ {
+		if got := evicted(7); !cmp.Equal(expected, got) {
+			t.Errorf("Expected the same evictions for the same seed, got %v and %v", expected, got)
+		}
+	}
+}
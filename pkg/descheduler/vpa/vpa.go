@@ -0,0 +1,170 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vpa
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+
+	frameworktypes "sigs.k8s.io/descheduler/pkg/framework/types"
+	"sigs.k8s.io/descheduler/pkg/utils"
+)
+
+// VerticalPodAutoscaler holds the fields of a VerticalPodAutoscaler the
+// descheduler reads. The VPA types are not vendored, objects are decoded
+// from the autoscaling.k8s.io/v1 API.
+type VerticalPodAutoscaler struct {
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec   Spec   `json:"spec"`
+	Status Status `json:"status"`
+}
+
+type Spec struct {
+	TargetRef    *autoscalingv1.CrossVersionObjectReference `json:"targetRef,omitempty"`
+	UpdatePolicy *UpdatePolicy                              `json:"updatePolicy,omitempty"`
+}
+
+type UpdatePolicy struct {
+	UpdateMode string `json:"updateMode,omitempty"`
+}
+
+type Status struct {
+	Recommendation *Recommendation `json:"recommendation,omitempty"`
+}
+
+type Recommendation struct {
+	ContainerRecommendations []ContainerRecommendation `json:"containerRecommendations,omitempty"`
+}
+
+type ContainerRecommendation struct {
+	ContainerName string          `json:"containerName"`
+	Target        v1.ResourceList `json:"target"`
+	LowerBound    v1.ResourceList `json:"lowerBound,omitempty"`
+	UpperBound    v1.ResourceList `json:"upperBound,omitempty"`
+}
+
+// Targets tells if the VerticalPodAutoscaler targets the workload of the pod.
+func (vpa *VerticalPodAutoscaler) Targets(pod *v1.Pod) bool {
+	ref := vpa.Spec.TargetRef
+	if ref == nil || ref.Name == "" {
+		return false
+	}
+	return utils.GetPodWorkloads(pod)[ref.Kind] == ref.Name
+}
+
+// ContainerRecommendation returns the recommendation for the container, nil
+// if there is none.
+func (vpa *VerticalPodAutoscaler) ContainerRecommendation(container string) *ContainerRecommendation {
+	if vpa.Status.Recommendation == nil {
+		return nil
+	}
+	for i, recommendation := range vpa.Status.Recommendation.ContainerRecommendations {
+		if recommendation.ContainerName == container {
+			return &vpa.Status.Recommendation.ContainerRecommendations[i]
+		}
+	}
+	return nil
+}
+
+// Lister lists the VerticalPodAutoscalers of a namespace.
+type Lister interface {
+	List(ctx context.Context, namespace string) ([]VerticalPodAutoscaler, error)
+}
+
+// RESTLister lists VerticalPodAutoscalers through the API server. All the
+// objects are listed at once, on first use, and grouped by namespace. The
+// lister is expected to live for a single descheduling cycle, see ListerFor.
+// Clusters without the VPA have no objects.
+type RESTLister struct {
+	client rest.Interface
+	mu     sync.Mutex
+	loaded bool
+	cache  map[string][]VerticalPodAutoscaler
+}
+
+var _ Lister = &RESTLister{}
+
+func NewRESTLister(client rest.Interface) *RESTLister {
+	return &RESTLister{client: client, cache: map[string][]VerticalPodAutoscaler{}}
+}
+
+func (l *RESTLister) List(ctx context.Context, namespace string) ([]VerticalPodAutoscaler, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.loaded {
+		if err := l.load(ctx); err != nil {
+			return nil, err
+		}
+		l.loaded = true
+	}
+	return l.cache[namespace], nil
+}
+
+// load lists the VerticalPodAutoscalers of all the namespaces, callers must
+// hold the lock.
+func (l *RESTLister) load(ctx context.Context) error {
+	// the fake clientset used in tests has no rest client.
+	if l.client == nil {
+		return nil
+	}
+
+	raw, err := l.client.Get().
+		AbsPath("/apis/autoscaling.k8s.io/v1/verticalpodautoscalers").
+		Do(ctx).
+		Raw()
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	var list struct {
+		Items []VerticalPodAutoscaler `json:"items"`
+	}
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return err
+	}
+	for _, vpa := range list.Items {
+		l.cache[vpa.Namespace] = append(l.cache[vpa.Namespace], vpa)
+	}
+	return nil
+}
+
+// ListerFor returns the lister the plugins of the profile of the handle
+// share during the descheduling cycle, so the VerticalPodAutoscalers are
+// listed once per cycle no matter how many plugins read them.
+func ListerFor(handle frameworktypes.Handle) (Lister, error) {
+	obj, err := frameworktypes.SharedObjectsFor(handle).GetOrCreate(
+		"vpa/lister",
+		func() (any, error) {
+			return NewRESTLister(handle.ClientSet().Discovery().RESTClient()), nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return obj.(Lister), nil
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vpa
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	restfake "k8s.io/client-go/rest/fake"
+	utilptr "k8s.io/utils/ptr"
+
+	frameworkfake "sigs.k8s.io/descheduler/pkg/framework/fake"
+	"sigs.k8s.io/descheduler/test"
+)
+
+func buildTestVPA(kind, name string, recommendations ...ContainerRecommendation) VerticalPodAutoscaler {
+	vpa := VerticalPodAutoscaler{}
+	vpa.Spec.TargetRef = &autoscalingv1.CrossVersionObjectReference{Kind: kind, Name: name, APIVersion: "apps/v1"}
+	if len(recommendations) > 0 {
+		vpa.Status.Recommendation = &Recommendation{ContainerRecommendations: recommendations}
+	}
+	return vpa
+}
+
+func TestVerticalPodAutoscalerTargets(t *testing.T) {
+	pod := test.BuildTestPod("p1", 100, 0, "n1", func(pod *v1.Pod) {
+		pod.Labels = map[string]string{"pod-template-hash": "5d8f7c"}
+		pod.OwnerReferences = []metav1.OwnerReference{
+			{Kind: "ReplicaSet", Name: "web-5d8f7c", Controller: utilptr.To(true)},
+		}
+	})
+
+	tests := []struct {
+		name     string
+		vpa      VerticalPodAutoscaler
+		expected bool
+	}{
+		{
+			name:     "deployment of the pod",
+			vpa:      buildTestVPA("Deployment", "web"),
+			expected: true,
+		},
+		{
+			name:     "replicaset of the pod",
+			vpa:      buildTestVPA("ReplicaSet", "web-5d8f7c"),
+			expected: true,
+		},
+		{
+			name: "another deployment",
+			vpa:  buildTestVPA("Deployment", "api"),
+		},
+		{
+			name: "workload of the same name and another kind",
+			vpa:  buildTestVPA("StatefulSet", "web"),
+		},
+		{
+			name: "no target",
+			vpa:  VerticalPodAutoscaler{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if targets := tc.vpa.Targets(pod); targets != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, targets)
+			}
+		})
+	}
+}
+
+func TestVerticalPodAutoscalerContainerRecommendation(t *testing.T) {
+	vpa := buildTestVPA("Deployment", "web",
+		ContainerRecommendation{ContainerName: "app", Target: v1.ResourceList{v1.ResourceCPU: resource.MustParse("200m")}},
+		ContainerRecommendation{ContainerName: "sidecar", Target: v1.ResourceList{v1.ResourceCPU: resource.MustParse("50m")}},
+	)
+
+	recommendation := vpa.ContainerRecommendation("sidecar")
+	if recommendation == nil || recommendation.Target.Cpu().MilliValue() != 50 {
+		t.Errorf("expected the sidecar recommendation, got %v", recommendation)
+	}
+	if recommendation := vpa.ContainerRecommendation("other"); recommendation != nil {
+		t.Errorf("expected no recommendation, got %v", recommendation)
+	}
+	withoutStatus := buildTestVPA("Deployment", "web")
+	if recommendation := withoutStatus.ContainerRecommendation("app"); recommendation != nil {
+		t.Errorf("expected no recommendation without status, got %v", recommendation)
+	}
+}
+
+func TestRESTListerWithoutClient(t *testing.T) {
+	vpas, err := NewRESTLister(nil).List(context.TODO(), "default")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(vpas) != 0 {
+		t.Errorf("expected no VerticalPodAutoscaler, got %v", vpas)
+	}
+}
+
+func TestRESTListerListsOnce(t *testing.T) {
+	var requests atomic.Int32
+	client := &restfake.RESTClient{
+		NegotiatedSerializer: scheme.Codecs.WithoutConversion(),
+		Client: restfake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+			requests.Add(1)
+			if req.URL.Path != "/apis/autoscaling.k8s.io/v1/verticalpodautoscalers" {
+				t.Errorf("unexpected request path %q", req.URL.Path)
+			}
+			body := `{"items": [
+				{"metadata": {"name": "web", "namespace": "a"}, "spec": {"targetRef": {"kind": "Deployment", "name": "web"}}},
+				{"metadata": {"name": "db", "namespace": "a"}, "spec": {"targetRef": {"kind": "StatefulSet", "name": "db"}}},
+				{"metadata": {"name": "api", "namespace": "b"}, "spec": {"targetRef": {"kind": "Deployment", "name": "api"}}}
+			]}`
+			header := http.Header{"Content-Type": []string{runtime.ContentTypeJSON}}
+			return &http.Response{StatusCode: http.StatusOK, Header: header, Body: io.NopCloser(strings.NewReader(body))}, nil
+		}),
+	}
+
+	lister := NewRESTLister(client)
+	expected := map[string]int{"a": 2, "b": 1, "c": 0}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		for namespace, count := range expected {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				vpas, err := lister.List(context.TODO(), namespace)
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+					return
+				}
+				if len(vpas) != count {
+					t.Errorf("expected %d VerticalPodAutoscalers in namespace %q, got %d", count, namespace, len(vpas))
+				}
+				for _, vpa := range vpas {
+					if vpa.Namespace != namespace {
+						t.Errorf("expected VerticalPodAutoscaler of namespace %q, got %q", namespace, vpa.Namespace)
+					}
+				}
+			}()
+		}
+	}
+	wg.Wait()
+
+	if got := requests.Load(); got != 1 {
+		t.Errorf("expected a single list request, got %d", got)
+	}
+}
+
+func TestListerForIsShared(t *testing.T) {
+	handle := &frameworkfake.HandleImpl{ClientsetImpl: fake.NewSimpleClientset()}
+	first, err := ListerFor(handle)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := ListerFor(handle)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first != second {
+		t.Errorf("expected the plugins of a profile to share the lister")
+	}
+}
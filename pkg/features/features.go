@@ -18,6 +18,13 @@ const (
 	// of code conflicts because changes are more likely to be scattered
 	// across the file.
 
+	// alpha: v1.34
+	//
+	// Account the devices allocated through Dynamic Resource Allocation
+	// against the node capacity in node utilization and node fit checks.
+	// Requires the resource.k8s.io/v1beta1 API to be served.
+	DynamicResourceAllocation featuregate.Feature = "DynamicResourceAllocation"
+
 	// owner: @ingvagabund
 	// kep: https://github.com/kubernetes-sigs/descheduler/issues/1397
 	// alpha: v1.31
@@ -38,6 +45,8 @@ func init() {
 // Entries are separated from each other with blank lines to avoid sweeping gofmt changes
 // when adding or removing one entry.
 var defaultDeschedulerFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
+	DynamicResourceAllocation: {Default: false, PreRelease: featuregate.Alpha},
+
 	EvictionsInBackground: {Default: false, PreRelease: featuregate.Alpha},
 }
 
@@ -9,6 +9,7 @@ import (
 
 	"sigs.k8s.io/descheduler/pkg/descheduler/evictions"
 	"sigs.k8s.io/descheduler/pkg/descheduler/metricscollector"
+	nodeutil "sigs.k8s.io/descheduler/pkg/descheduler/node"
 	podutil "sigs.k8s.io/descheduler/pkg/descheduler/pod"
 	frameworktypes "sigs.k8s.io/descheduler/pkg/framework/types"
 
@@ -22,10 +23,24 @@ type HandleImpl struct {
 	EvictorFilterImpl             frameworktypes.EvictorPlugin
 	PodEvictorImpl                *evictions.PodEvictor
 	MetricsCollectorImpl          *metricscollector.MetricsCollector
+	DeviceAccountingImpl          *nodeutil.DeviceAccounting
 	PrometheusClientImpl          promapi.Client
+	SharedObjectsImpl             *frameworktypes.SharedObjects
+	SharedCacheImpl               *frameworktypes.SharedCache
+	RandImpl                      *frameworktypes.Rand
+	ProfileNameImpl               string
+	// EvictorImpl, when set, is returned by Evictor in place of the
+	// handle itself, e.g. to decorate the evictor in tests.
+	EvictorImpl frameworktypes.Evictor
 }
 
 var _ frameworktypes.Handle = &HandleImpl{}
+var _ frameworktypes.DeviceAccountingHandle = &HandleImpl{}
+var _ frameworktypes.SharedObjectsHandle = &HandleImpl{}
+var _ frameworktypes.SharedCacheHandle = &HandleImpl{}
+var _ frameworktypes.RandHandle = &HandleImpl{}
+var _ frameworktypes.ProfileNameHandle = &HandleImpl{}
+var _ frameworktypes.DryRunEvictor = &HandleImpl{}
 
 func (hi *HandleImpl) ClientSet() clientset.Interface {
 	return hi.ClientsetImpl
@@ -39,6 +54,10 @@ func (hi *HandleImpl) MetricsCollector() *metricscollector.MetricsCollector {
 	return hi.MetricsCollectorImpl
 }
 
+func (hi *HandleImpl) DeviceAccounting() *nodeutil.DeviceAccounting {
+	return hi.DeviceAccountingImpl
+}
+
 func (hi *HandleImpl) GetPodsAssignedToNodeFunc() podutil.GetPodsAssignedToNodeFunc {
 	return hi.GetPodsAssignedToNodeFuncImpl
 }
@@ -47,7 +66,35 @@ func (hi *HandleImpl) SharedInformerFactory() informers.SharedInformerFactory {
 	return hi.SharedInformerFactoryImpl
 }
 
+func (hi *HandleImpl) SharedObjects() *frameworktypes.SharedObjects {
+	if hi.SharedObjectsImpl == nil {
+		hi.SharedObjectsImpl = frameworktypes.NewSharedObjects()
+	}
+	return hi.SharedObjectsImpl
+}
+
+func (hi *HandleImpl) SharedCache() *frameworktypes.SharedCache {
+	if hi.SharedCacheImpl == nil {
+		hi.SharedCacheImpl = frameworktypes.NewSharedCache()
+	}
+	return hi.SharedCacheImpl
+}
+
+func (hi *HandleImpl) Rand() *frameworktypes.Rand {
+	if hi.RandImpl == nil {
+		hi.RandImpl = frameworktypes.NewRand(0)
+	}
+	return hi.RandImpl
+}
+
+func (hi *HandleImpl) ProfileName() string {
+	return hi.ProfileNameImpl
+}
+
 func (hi *HandleImpl) Evictor() frameworktypes.Evictor {
+	if hi.EvictorImpl != nil {
+		return hi.EvictorImpl
+	}
 	return hi
 }
 
@@ -62,3 +109,7 @@ func (hi *HandleImpl) PreEvictionFilter(pod *v1.Pod) bool {
 func (hi *HandleImpl) Evict(ctx context.Context, pod *v1.Pod, opts evictions.EvictOptions) error {
 	return hi.PodEvictorImpl.EvictPod(ctx, pod, opts)
 }
+
+func (hi *HandleImpl) DryRun() bool {
+	return hi.PodEvictorImpl.DryRun()
+}
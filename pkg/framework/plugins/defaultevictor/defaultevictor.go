@@ -31,6 +31,7 @@ import (
 	evictionutils "sigs.k8s.io/descheduler/pkg/descheduler/evictions/utils"
 	nodeutil "sigs.k8s.io/descheduler/pkg/descheduler/node"
 	podutil "sigs.k8s.io/descheduler/pkg/descheduler/pod"
+	"sigs.k8s.io/descheduler/pkg/descheduler/vpa"
 	frameworktypes "sigs.k8s.io/descheduler/pkg/framework/types"
 	"sigs.k8s.io/descheduler/pkg/utils"
 )
@@ -53,6 +54,8 @@ type DefaultEvictor struct {
 	args        *DefaultEvictorArgs
 	constraints []constraint
 	handle      frameworktypes.Handle
+	vpaLister   vpa.Lister
+	policy      *policyChecker
 }
 
 // IsPodEvictableBasedOnPriority checks if the given pod is evictable based on priority resolved from pod Spec.
@@ -77,6 +80,7 @@ func New(args runtime.Object, handle frameworktypes.Handle) (frameworktypes.Plug
 	ev := &DefaultEvictor{
 		handle: handle,
 		args:   defaultEvictorArgs,
+		policy: newPolicyChecker(defaultEvictorArgs.PolicyCheck),
 	}
 
 	if defaultEvictorArgs.EvictFailedBarePods {
@@ -211,6 +215,62 @@ func New(args runtime.Object, handle frameworktypes.Handle) (frameworktypes.Plug
 		})
 	}
 
+	if defaultEvictorArgs.IgnorePodsOfScalingWorkloads {
+		cooldown := DefaultScalingCooldown
+		if defaultEvictorArgs.ScalingCooldown != nil {
+			cooldown = defaultEvictorArgs.ScalingCooldown.Duration
+		}
+		hpaLister := handle.SharedInformerFactory().Autoscaling().V2().HorizontalPodAutoscalers().Lister()
+		ev.constraints = append(ev.constraints, func(pod *v1.Pod) error {
+			scaling, err := utils.IsPodOwnerScaling(pod, hpaLister, cooldown)
+			if err != nil {
+				return fmt.Errorf("unable to check if pod owner is being scaled: %w", err)
+			}
+			if scaling {
+				return fmt.Errorf("pod owner is being scaled by a HorizontalPodAutoscaler")
+			}
+			return nil
+		})
+	}
+
+	if defaultEvictorArgs.IgnorePodsOfRollingOutWorkloads {
+		rsIndexer, err := getReplicaSetIndexer(handle)
+		if err != nil {
+			return nil, err
+		}
+		ev.constraints = append(ev.constraints, func(pod *v1.Pod) error {
+			rollingOut, err := utils.IsPodOwnerRollingOut(pod, rsIndexer)
+			if err != nil {
+				return fmt.Errorf("unable to check if pod owner is rolling out: %w", err)
+			}
+			if rollingOut {
+				return fmt.Errorf("pod owner is in the middle of a rollout")
+			}
+			return nil
+		})
+	}
+
+	if defaultEvictorArgs.IgnorePodsBeingResized {
+		vpaLister, err := vpa.ListerFor(handle)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get the VerticalPodAutoscaler lister: %w", err)
+		}
+		ev.vpaLister = vpaLister
+		ev.constraints = append(ev.constraints, func(pod *v1.Pod) error {
+			if utils.IsPodResizing(pod) {
+				return fmt.Errorf("pod has an in-place resize in progress")
+			}
+			vpas, err := ev.vpaLister.List(context.TODO(), pod.Namespace)
+			if err != nil {
+				return fmt.Errorf("unable to list VerticalPodAutoscalers: %w", err)
+			}
+			if isPodPendingVPAUpdate(pod, vpas) {
+				return fmt.Errorf("pod is about to be updated by a VerticalPodAutoscaler")
+			}
+			return nil
+		})
+	}
+
 	return ev, nil
 }
 
@@ -220,17 +280,23 @@ func (d *DefaultEvictor) Name() string {
 }
 
 func (d *DefaultEvictor) PreEvictionFilter(pod *v1.Pod) bool {
+	if evictionutils.HaveUnsafeToEvictAnnotation(pod) {
+		klog.V(4).InfoS("Pod is annotated as not safe to evict", "pod", klog.KObj(pod))
+		return false
+	}
 	if d.args.NodeFit {
 		nodes, err := nodeutil.ReadyNodes(context.TODO(), d.handle.ClientSet(), d.handle.SharedInformerFactory().Core().V1().Nodes().Lister(), d.args.NodeSelector)
 		if err != nil {
 			klog.ErrorS(err, "unable to list ready nodes", "pod", klog.KObj(pod))
 			return false
 		}
-		if !nodeutil.PodFitsAnyOtherNode(d.handle.GetPodsAssignedToNodeFunc(), pod, nodes) {
+		if !nodeutil.PodFitsAnyOtherNodeWithDevices(d.handle.GetPodsAssignedToNodeFunc(), frameworktypes.DeviceAccountingFor(d.handle), pod, nodes) {
 			klog.InfoS("pod does not fit on any other node because of nodeSelector(s), Taint(s), or nodes marked as unschedulable", "pod", klog.KObj(pod))
 			return false
 		}
-		return true
+	}
+	if d.policy != nil && !d.policy.allows(context.TODO(), pod) {
+		return false
 	}
 	return true
 }
@@ -298,3 +364,23 @@ func getPodIndexerByOwnerRefs(indexName string, handle frameworktypes.Handle) (c
 
 	return indexer, nil
 }
+
+func getReplicaSetIndexer(handle frameworktypes.Handle) (cache.Indexer, error) {
+	rsInformer := handle.SharedInformerFactory().Apps().V1().ReplicaSets().Informer()
+	indexer := rsInformer.GetIndexer()
+
+	// do not reinitialize the indexers, if they've been defined already
+	indexers := utils.ReplicaSetIndexers()
+	for name := range indexer.GetIndexers() {
+		delete(indexers, name)
+	}
+	if len(indexers) == 0 {
+		return indexer, nil
+	}
+
+	if err := rsInformer.AddIndexers(indexers); err != nil {
+		return nil, err
+	}
+
+	return indexer, nil
+}
@@ -21,16 +21,22 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	v1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/descheduler/pkg/api"
 	evictionutils "sigs.k8s.io/descheduler/pkg/descheduler/evictions/utils"
 	podutil "sigs.k8s.io/descheduler/pkg/descheduler/pod"
+	"sigs.k8s.io/descheduler/pkg/descheduler/vpa"
 	frameworkfake "sigs.k8s.io/descheduler/pkg/framework/fake"
 	frameworktypes "sigs.k8s.io/descheduler/pkg/framework/types"
 	"sigs.k8s.io/descheduler/pkg/utils"
@@ -42,6 +48,8 @@ type testCase struct {
 	pods                    []*v1.Pod
 	nodes                   []*v1.Node
 	pdbs                    []*policyv1.PodDisruptionBudget
+	hpas                    []*autoscalingv2.HorizontalPodAutoscaler
+	replicaSets             []*appsv1.ReplicaSet
 	evictFailedBarePods     bool
 	evictLocalStoragePods   bool
 	evictSystemCriticalPods bool
@@ -53,6 +61,10 @@ type testCase struct {
 	result                  bool
 	ignorePodsWithoutPDB    bool
 	noEvictionPolicy        NoEvictionPolicy
+	ignoreScalingWorkloads  bool
+	ignoreRollingOut        bool
+	ignoreBeingResized      bool
+	vpas                    []vpa.VerticalPodAutoscaler
 }
 
 func TestDefaultEvictorPreEvictionFilter(t *testing.T) {
@@ -289,6 +301,45 @@ func TestDefaultEvictorPreEvictionFilter(t *testing.T) {
 				}),
 			},
 			result: true,
+		}, {
+			description: "Pod annotated as not safe to evict for the cluster autoscaler",
+			pods: []*v1.Pod{
+				test.BuildTestPod("p1", 400, 0, n1.Name, func(pod *v1.Pod) {
+					pod.ObjectMeta.OwnerReferences = test.GetNormalPodOwnerRefList()
+					pod.Annotations = map[string]string{evictionutils.ClusterAutoscalerSafeToEvictAnnotationKey: "false"}
+				}),
+			},
+			result: false,
+		}, {
+			description: "Pod annotated as safe to evict for the cluster autoscaler",
+			pods: []*v1.Pod{
+				test.BuildTestPod("p1", 400, 0, n1.Name, func(pod *v1.Pod) {
+					pod.ObjectMeta.OwnerReferences = test.GetNormalPodOwnerRefList()
+					pod.Annotations = map[string]string{evictionutils.ClusterAutoscalerSafeToEvictAnnotationKey: "true"}
+				}),
+			},
+			result: true,
+		}, {
+			description: "Pod annotated as not safe to evict for the descheduler",
+			pods: []*v1.Pod{
+				test.BuildTestPod("p1", 400, 0, n1.Name, func(pod *v1.Pod) {
+					pod.ObjectMeta.OwnerReferences = test.GetNormalPodOwnerRefList()
+					pod.Annotations = map[string]string{evictionutils.SafeToEvictAnnotationKey: "false"}
+				}),
+			},
+			result: false,
+		}, {
+			description: "Pod not safe to evict for the cluster autoscaler but safe to evict for the descheduler",
+			pods: []*v1.Pod{
+				test.BuildTestPod("p1", 400, 0, n1.Name, func(pod *v1.Pod) {
+					pod.ObjectMeta.OwnerReferences = test.GetNormalPodOwnerRefList()
+					pod.Annotations = map[string]string{
+						evictionutils.ClusterAutoscalerSafeToEvictAnnotationKey: "false",
+						evictionutils.SafeToEvictAnnotationKey:                  "true",
+					}
+				}),
+			},
+			result: true,
 		},
 	}
 
@@ -724,6 +775,177 @@ func TestDefaultEvictorFilter(t *testing.T) {
 			},
 			ignorePodsWithoutPDB: true,
 			result:               true,
+		}, {
+			description: "ignorePodsOfScalingWorkloads, deployment being scaled, no eviction",
+			pods: []*v1.Pod{
+				test.BuildTestPod("p1", 1, 1, n1.Name, scalablePod("web-5d8f7c", "5d8f7c")),
+			},
+			hpas: []*autoscalingv2.HorizontalPodAutoscaler{
+				buildTestHPA("Deployment", "web", 3, 5, nil),
+			},
+			ignoreScalingWorkloads: true,
+		}, {
+			description: "ignorePodsOfScalingWorkloads, deployment recently scaled, no eviction",
+			pods: []*v1.Pod{
+				test.BuildTestPod("p1", 1, 1, n1.Name, scalablePod("web-5d8f7c", "5d8f7c")),
+			},
+			hpas: []*autoscalingv2.HorizontalPodAutoscaler{
+				buildTestHPA("Deployment", "web", 5, 5, &metav1.Time{Time: time.Now().Add(-time.Minute)}),
+			},
+			ignoreScalingWorkloads: true,
+		}, {
+			description: "ignorePodsOfScalingWorkloads, deployment scaled long ago, evicts",
+			pods: []*v1.Pod{
+				test.BuildTestPod("p1", 1, 1, n1.Name, scalablePod("web-5d8f7c", "5d8f7c")),
+			},
+			hpas: []*autoscalingv2.HorizontalPodAutoscaler{
+				buildTestHPA("Deployment", "web", 5, 5, &metav1.Time{Time: time.Now().Add(-time.Hour)}),
+			},
+			ignoreScalingWorkloads: true,
+			result:                 true,
+		}, {
+			description: "ignorePodsOfScalingWorkloads, other deployment being scaled, evicts",
+			pods: []*v1.Pod{
+				test.BuildTestPod("p1", 1, 1, n1.Name, scalablePod("web-5d8f7c", "5d8f7c")),
+			},
+			hpas: []*autoscalingv2.HorizontalPodAutoscaler{
+				buildTestHPA("Deployment", "api", 3, 5, nil),
+			},
+			ignoreScalingWorkloads: true,
+			result:                 true,
+		}, {
+			description: "ignorePodsOfScalingWorkloads not set, deployment being scaled, evicts",
+			pods: []*v1.Pod{
+				test.BuildTestPod("p1", 1, 1, n1.Name, scalablePod("web-5d8f7c", "5d8f7c")),
+			},
+			hpas: []*autoscalingv2.HorizontalPodAutoscaler{
+				buildTestHPA("Deployment", "web", 3, 5, nil),
+			},
+			result: true,
+		}, {
+			description: "ignorePodsOfRollingOutWorkloads, deployment mid-rollout, no eviction",
+			pods: []*v1.Pod{
+				test.BuildTestPod("p1", 1, 1, n1.Name, scalablePod("web-5d8f7c", "5d8f7c")),
+			},
+			replicaSets: []*appsv1.ReplicaSet{
+				buildTestReplicaSet("web-5d8f7c", "Deployment", "web", 2),
+				buildTestReplicaSet("web-7b9c4d", "Deployment", "web", 1),
+			},
+			ignoreRollingOut: true,
+		}, {
+			description: "ignorePodsOfRollingOutWorkloads, argo rollout canary, no eviction",
+			pods: []*v1.Pod{
+				test.BuildTestPod("p1", 1, 1, n1.Name, scalablePod("web-5d8f7c", "5d8f7c")),
+			},
+			replicaSets: []*appsv1.ReplicaSet{
+				buildTestReplicaSet("web-5d8f7c", "Rollout", "web", 4),
+				buildTestReplicaSet("web-7b9c4d", "Rollout", "web", 1),
+			},
+			ignoreRollingOut: true,
+		}, {
+			description: "ignorePodsOfRollingOutWorkloads, deployment rolled out, evicts",
+			pods: []*v1.Pod{
+				test.BuildTestPod("p1", 1, 1, n1.Name, scalablePod("web-5d8f7c", "5d8f7c")),
+			},
+			replicaSets: []*appsv1.ReplicaSet{
+				buildTestReplicaSet("web-5d8f7c", "Deployment", "web", 3),
+				buildTestReplicaSet("web-7b9c4d", "Deployment", "web", 0),
+				buildTestReplicaSet("api-6c8d9e", "Deployment", "api", 2),
+			},
+			ignoreRollingOut: true,
+			result:           true,
+		}, {
+			description: "ignorePodsOfRollingOutWorkloads, flagger canary under analysis, no eviction",
+			pods: []*v1.Pod{
+				test.BuildTestPod("p1", 1, 1, n1.Name, scalablePod("web-primary-5d8f7c", "5d8f7c")),
+			},
+			replicaSets: []*appsv1.ReplicaSet{
+				buildTestReplicaSet("web-primary-5d8f7c", "Deployment", "web-primary", 3),
+				buildTestReplicaSet("web-7b9c4d", "Deployment", "web", 1),
+			},
+			ignoreRollingOut: true,
+		}, {
+			description: "ignorePodsOfRollingOutWorkloads, flagger canary scaled to zero, evicts",
+			pods: []*v1.Pod{
+				test.BuildTestPod("p1", 1, 1, n1.Name, scalablePod("web-primary-5d8f7c", "5d8f7c")),
+			},
+			replicaSets: []*appsv1.ReplicaSet{
+				buildTestReplicaSet("web-primary-5d8f7c", "Deployment", "web-primary", 3),
+				buildTestReplicaSet("web-7b9c4d", "Deployment", "web", 0),
+			},
+			ignoreRollingOut: true,
+			result:           true,
+		}, {
+			description: "ignorePodsOfRollingOutWorkloads not set, deployment mid-rollout, evicts",
+			pods: []*v1.Pod{
+				test.BuildTestPod("p1", 1, 1, n1.Name, scalablePod("web-5d8f7c", "5d8f7c")),
+			},
+			replicaSets: []*appsv1.ReplicaSet{
+				buildTestReplicaSet("web-5d8f7c", "Deployment", "web", 2),
+				buildTestReplicaSet("web-7b9c4d", "Deployment", "web", 1),
+			},
+			result: true,
+		}, {
+			description: "ignorePodsBeingResized, recommendation far from the request, no eviction",
+			pods: []*v1.Pod{
+				test.BuildTestPod("p1", 100, 0, n1.Name, scalablePod("web-5d8f7c", "5d8f7c")),
+			},
+			vpas: []vpa.VerticalPodAutoscaler{
+				buildTestVPA("Deployment", "web", "", resource.MustParse("200m")),
+			},
+			ignoreBeingResized: true,
+		}, {
+			description: "ignorePodsBeingResized, recommendation close to the request, evicts",
+			pods: []*v1.Pod{
+				test.BuildTestPod("p1", 100, 0, n1.Name, scalablePod("web-5d8f7c", "5d8f7c")),
+			},
+			vpas: []vpa.VerticalPodAutoscaler{
+				buildTestVPA("Deployment", "web", "", resource.MustParse("105m")),
+			},
+			ignoreBeingResized: true,
+			result:             true,
+		}, {
+			description: "ignorePodsBeingResized, vpa in Off mode, evicts",
+			pods: []*v1.Pod{
+				test.BuildTestPod("p1", 100, 0, n1.Name, scalablePod("web-5d8f7c", "5d8f7c")),
+			},
+			vpas: []vpa.VerticalPodAutoscaler{
+				buildTestVPA("Deployment", "web", "Off", resource.MustParse("200m")),
+			},
+			ignoreBeingResized: true,
+			result:             true,
+		}, {
+			description: "ignorePodsBeingResized, vpa targeting another workload, evicts",
+			pods: []*v1.Pod{
+				test.BuildTestPod("p1", 100, 0, n1.Name, scalablePod("web-5d8f7c", "5d8f7c")),
+			},
+			vpas: []vpa.VerticalPodAutoscaler{
+				buildTestVPA("Deployment", "api", "", resource.MustParse("200m")),
+			},
+			ignoreBeingResized: true,
+			result:             true,
+		}, {
+			description: "ignorePodsBeingResized, in-place resize in progress, no eviction",
+			pods: []*v1.Pod{
+				test.BuildTestPod("p1", 100, 0, n1.Name, func(pod *v1.Pod) {
+					scalablePod("web-5d8f7c", "5d8f7c")(pod)
+					pod.Status.Conditions = []v1.PodCondition{
+						{Type: v1.PodResizeInProgress, Status: v1.ConditionTrue},
+					}
+				}),
+			},
+			ignoreBeingResized: true,
+		}, {
+			description: "ignorePodsBeingResized not set, in-place resize in progress, evicts",
+			pods: []*v1.Pod{
+				test.BuildTestPod("p1", 100, 0, n1.Name, func(pod *v1.Pod) {
+					scalablePod("web-5d8f7c", "5d8f7c")(pod)
+					pod.Status.Conditions = []v1.PodCondition{
+						{Type: v1.PodResizeInProgress, Status: v1.ConditionTrue},
+					}
+				}),
+			},
+			result: true,
 		}, {
 			description: "ignorePvcPods is set, pod with PVC, not evicts",
 			pods: []*v1.Pod{
@@ -818,6 +1040,66 @@ func TestReinitialization(t *testing.T) {
 	}
 }
 
+// scalablePod sets the pod owner to a ReplicaSet created by a Deployment.
+func scalablePod(replicaSet, hash string) func(*v1.Pod) {
+	return func(pod *v1.Pod) {
+		pod.Labels = map[string]string{"pod-template-hash": hash}
+		pod.ObjectMeta.OwnerReferences = []metav1.OwnerReference{
+			{Kind: "ReplicaSet", APIVersion: "apps/v1", Name: replicaSet, UID: types.UID(replicaSet), Controller: ptr.To(true)},
+		}
+	}
+}
+
+func buildTestReplicaSet(name, ownerKind, ownerName string, replicas int32) *appsv1.ReplicaSet {
+	return &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			UID:       types.UID(name),
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: ownerKind, Name: ownerName, UID: types.UID(ownerKind + "/" + ownerName), Controller: ptr.To(true)},
+			},
+		},
+		Spec:   appsv1.ReplicaSetSpec{Replicas: ptr.To(replicas)},
+		Status: appsv1.ReplicaSetStatus{Replicas: replicas},
+	}
+}
+
+func buildTestHPA(kind, name string, current, desired int32, lastScaleTime *metav1.Time) *autoscalingv2.HorizontalPodAutoscaler {
+	return &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{Kind: kind, Name: name, APIVersion: "apps/v1"},
+		},
+		Status: autoscalingv2.HorizontalPodAutoscalerStatus{
+			CurrentReplicas: current,
+			DesiredReplicas: desired,
+			LastScaleTime:   lastScaleTime,
+		},
+	}
+}
+
+// staticVPALister returns the same VerticalPodAutoscalers for every namespace.
+type staticVPALister []vpa.VerticalPodAutoscaler
+
+func (l staticVPALister) List(context.Context, string) ([]vpa.VerticalPodAutoscaler, error) {
+	return l, nil
+}
+
+func buildTestVPA(kind, name, updateMode string, cpuTarget resource.Quantity) vpa.VerticalPodAutoscaler {
+	autoscaler := vpa.VerticalPodAutoscaler{}
+	autoscaler.Spec.TargetRef = &autoscalingv1.CrossVersionObjectReference{Kind: kind, Name: name, APIVersion: "apps/v1"}
+	if updateMode != "" {
+		autoscaler.Spec.UpdatePolicy = &vpa.UpdatePolicy{UpdateMode: updateMode}
+	}
+	autoscaler.Status.Recommendation = &vpa.Recommendation{
+		ContainerRecommendations: []vpa.ContainerRecommendation{
+			{Target: v1.ResourceList{v1.ResourceCPU: cpuTarget}},
+		},
+	}
+	return autoscaler
+}
+
 func initializePlugin(ctx context.Context, test testCase) (frameworktypes.Plugin, error) {
 	var objs []runtime.Object
 	for _, node := range test.nodes {
@@ -829,12 +1111,20 @@ func initializePlugin(ctx context.Context, test testCase) (frameworktypes.Plugin
 	for _, pdb := range test.pdbs {
 		objs = append(objs, pdb)
 	}
+	for _, hpa := range test.hpas {
+		objs = append(objs, hpa)
+	}
+	for _, rs := range test.replicaSets {
+		objs = append(objs, rs)
+	}
 
 	fakeClient := fake.NewSimpleClientset(objs...)
 
 	sharedInformerFactory := informers.NewSharedInformerFactory(fakeClient, 0)
 	podInformer := sharedInformerFactory.Core().V1().Pods().Informer()
 	_ = sharedInformerFactory.Policy().V1().PodDisruptionBudgets().Lister()
+	_ = sharedInformerFactory.Autoscaling().V2().HorizontalPodAutoscalers().Lister()
+	_ = sharedInformerFactory.Apps().V1().ReplicaSets().Lister()
 
 	getPodsAssignedToNode, err := podutil.BuildGetPodsAssignedToNodeFunc(podInformer)
 	if err != nil {
@@ -857,6 +1147,10 @@ func initializePlugin(ctx context.Context, test testCase) (frameworktypes.Plugin
 		MinPodAge:            test.minPodAge,
 		IgnorePodsWithoutPDB: test.ignorePodsWithoutPDB,
 		NoEvictionPolicy:     test.noEvictionPolicy,
+
+		IgnorePodsOfScalingWorkloads:    test.ignoreScalingWorkloads,
+		IgnorePodsOfRollingOutWorkloads: test.ignoreRollingOut,
+		IgnorePodsBeingResized:          test.ignoreBeingResized,
 	}
 
 	evictorPlugin, err := New(
@@ -869,6 +1163,9 @@ func initializePlugin(ctx context.Context, test testCase) (frameworktypes.Plugin
 	if err != nil {
 		return nil, fmt.Errorf("unable to initialize the plugin: %v", err)
 	}
+	if test.ignoreBeingResized {
+		evictorPlugin.(*DefaultEvictor).vpaLister = staticVPALister(test.vpas)
+	}
 
 	return evictorPlugin, nil
 }
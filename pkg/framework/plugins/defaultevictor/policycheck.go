@@ -0,0 +1,106 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package defaultevictor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// defaultPolicyCheckTimeout is used when the policy check timeout is not
+// configured.
+const defaultPolicyCheckTimeout = 5 * time.Second
+
+// policyChecker queries an OPA endpoint, through its data API, to decide if
+// a pod can be evicted. the pod is sent as the query input and the policy
+// is expected to evaluate to a boolean.
+type policyChecker struct {
+	url        string
+	failOpen   bool
+	httpClient *http.Client
+}
+
+// newPolicyChecker returns a checker for the provided configuration, nil if
+// no policy check is configured.
+func newPolicyChecker(config *PolicyCheck) *policyChecker {
+	if config == nil {
+		return nil
+	}
+
+	timeout := defaultPolicyCheckTimeout
+	if config.Timeout != nil {
+		timeout = config.Timeout.Duration
+	}
+
+	return &policyChecker{
+		url:        config.URL,
+		failOpen:   config.FailOpen,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// allows returns true if the policy allows the eviction of the pod. when
+// the policy can not be evaluated the eviction is allowed only if the
+// checker fails open.
+func (c *policyChecker) allows(ctx context.Context, pod *v1.Pod) bool {
+	allowed, err := c.query(ctx, pod)
+	if err != nil {
+		klog.ErrorS(err, "unable to evaluate the eviction policy", "pod", klog.KObj(pod), "failOpen", c.failOpen)
+		return c.failOpen
+	}
+	if !allowed {
+		klog.V(4).InfoS("Eviction denied by policy", "pod", klog.KObj(pod))
+	}
+	return allowed
+}
+
+// query sends the pod to the policy endpoint and returns the decision. an
+// undefined decision, returned by OPA when no rule matches, denies the
+// eviction.
+func (c *policyChecker) query(ctx context.Context, pod *v1.Pod) (bool, error) {
+	body, err := json.Marshal(map[string]any{"input": map[string]any{"pod": pod}})
+	if err != nil {
+		return false, fmt.Errorf("unable to encode policy input: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("unable to build policy request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("unable to send policy request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("policy request returned status %d", resp.StatusCode)
+	}
+
+	var decision struct {
+		Result *bool `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&decision); err != nil {
+		return false, fmt.Errorf("unable to decode policy decision: %w", err)
+	}
+	return decision.Result != nil && *decision.Result, nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package defaultevictor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+
+	"sigs.k8s.io/descheduler/test"
+)
+
+func TestPolicyChecker(t *testing.T) {
+	for _, tc := range []struct {
+		description string
+		status      int
+		response    string
+		failOpen    bool
+		allowed     bool
+	}{
+		{
+			description: "policy allows the eviction",
+			status:      http.StatusOK,
+			response:    `{"result": true}`,
+			allowed:     true,
+		},
+		{
+			description: "policy denies the eviction",
+			status:      http.StatusOK,
+			response:    `{"result": false}`,
+		},
+		{
+			description: "undefined decision denies the eviction",
+			status:      http.StatusOK,
+			response:    `{}`,
+		},
+		{
+			description: "policy evaluation fails, fail closed",
+			status:      http.StatusInternalServerError,
+		},
+		{
+			description: "policy evaluation fails, fail open",
+			status:      http.StatusInternalServerError,
+			failOpen:    true,
+			allowed:     true,
+		},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			var input struct {
+				Input struct {
+					Pod *v1.Pod `json:"pod"`
+				} `json:"input"`
+			}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+					t.Errorf("unable to decode policy input: %v", err)
+				}
+				w.WriteHeader(tc.status)
+				w.Write([]byte(tc.response))
+			}))
+			defer server.Close()
+
+			checker := newPolicyChecker(&PolicyCheck{URL: server.URL, FailOpen: tc.failOpen})
+			pod := test.BuildTestPod("p1", 100, 0, "node1", nil)
+			if allowed := checker.allows(context.Background(), pod); allowed != tc.allowed {
+				t.Errorf("expected allowed to be %t, got %t", tc.allowed, allowed)
+			}
+			if input.Input.Pod == nil || input.Input.Pod.Name != pod.Name {
+				t.Errorf("expected the pod to be sent as the policy input")
+			}
+		})
+	}
+}
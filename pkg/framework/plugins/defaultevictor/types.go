@@ -14,6 +14,8 @@ limitations under the License.
 package defaultevictor
 
 import (
+	"time"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/descheduler/pkg/api"
 )
@@ -38,8 +40,43 @@ type DefaultEvictorArgs struct {
 	MinPodAge               *metav1.Duration       `json:"minPodAge,omitempty"`
 	IgnorePodsWithoutPDB    bool                   `json:"ignorePodsWithoutPDB,omitempty"`
 	NoEvictionPolicy        NoEvictionPolicy       `json:"noEvictionPolicy,omitempty"`
+
+	// IgnorePodsOfScalingWorkloads excludes pods whose owner is being scaled
+	// by a HorizontalPodAutoscaler, or was within the ScalingCooldown.
+	IgnorePodsOfScalingWorkloads bool             `json:"ignorePodsOfScalingWorkloads,omitempty"`
+	ScalingCooldown              *metav1.Duration `json:"scalingCooldown,omitempty"`
+
+	// IgnorePodsOfRollingOutWorkloads excludes pods whose owner is in the
+	// middle of a rollout or a canary.
+	IgnorePodsOfRollingOutWorkloads bool `json:"ignorePodsOfRollingOutWorkloads,omitempty"`
+
+	// IgnorePodsBeingResized excludes pods the VerticalPodAutoscaler updater
+	// is about to update and pods with an in-place resize in progress.
+	IgnorePodsBeingResized bool `json:"ignorePodsBeingResized,omitempty"`
+
+	// PolicyCheck queries an external policy before evicting a pod.
+	PolicyCheck *PolicyCheck `json:"policyCheck,omitempty"`
 }
 
+// +k8s:deepcopy-gen=true
+
+// PolicyCheck configures an OPA, or Gatekeeper, policy evaluated for every
+// pod about to be evicted. The pod is sent as the "pod" field of the query
+// input, the eviction proceeds only if the policy evaluates to true.
+type PolicyCheck struct {
+	// URL of the policy in the OPA data API, e.g.
+	// http://opa.opa.svc:8181/v1/data/descheduler/allow
+	URL string `json:"url"`
+	// Timeout of the policy queries, defaults to 5 seconds.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// FailOpen allows evictions when the policy can not be evaluated.
+	FailOpen bool `json:"failOpen,omitempty"`
+}
+
+// DefaultScalingCooldown is the time after the last scale event during which
+// pods of a scaled workload are not evicted when no cooldown is configured.
+const DefaultScalingCooldown = 5 * time.Minute
+
 // NoEvictionPolicy dictates whether a no-eviction policy is preferred or mandatory.
 // Needs to be used with caution as this will give users ability to protect their pods
 // from eviction. Which might work against enfored policies. E.g. plugins evicting pods
@@ -15,6 +15,7 @@ package defaultevictor
 
 import (
 	"fmt"
+	"net/url"
 
 	"k8s.io/klog/v2"
 
@@ -38,5 +39,19 @@ func ValidateDefaultEvictorArgs(obj runtime.Object) error {
 		}
 	}
 
+	if args.ScalingCooldown != nil && args.ScalingCooldown.Duration < 0 {
+		return fmt.Errorf("scalingCooldown must not be negative")
+	}
+
+	if args.PolicyCheck != nil {
+		u, err := url.Parse(args.PolicyCheck.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("policyCheck url must be an http or https url")
+		}
+		if args.PolicyCheck.Timeout != nil && args.PolicyCheck.Timeout.Duration < 0 {
+			return fmt.Errorf("policyCheck timeout must not be negative")
+		}
+	}
+
 	return nil
 }
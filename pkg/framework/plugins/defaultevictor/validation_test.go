@@ -19,7 +19,9 @@ package defaultevictor
 import (
 	"fmt"
 	"testing"
+	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilptr "k8s.io/utils/ptr"
 	"sigs.k8s.io/descheduler/pkg/api"
@@ -46,6 +48,33 @@ func TestValidateDefaultEvictorArgs(t *testing.T) {
 				NoEvictionPolicy: "invalid-no-eviction-policy",
 			},
 			errInfo: fmt.Errorf("noEvictionPolicy accepts only %q values", []NoEvictionPolicy{PreferredNoEvictionPolicy, MandatoryNoEvictionPolicy}),
+		}, {
+			name: "passing negative scaling cooldown",
+			args: &DefaultEvictorArgs{
+				IgnorePodsOfScalingWorkloads: true,
+				ScalingCooldown:              &metav1.Duration{Duration: -time.Minute},
+			},
+			errInfo: fmt.Errorf("scalingCooldown must not be negative"),
+		}, {
+			name: "passing invalid policy check url",
+			args: &DefaultEvictorArgs{
+				PolicyCheck: &PolicyCheck{URL: "opa:8181/v1/data/descheduler/allow"},
+			},
+			errInfo: fmt.Errorf("policyCheck url must be an http or https url"),
+		}, {
+			name: "passing negative policy check timeout",
+			args: &DefaultEvictorArgs{
+				PolicyCheck: &PolicyCheck{
+					URL:     "http://opa:8181/v1/data/descheduler/allow",
+					Timeout: &metav1.Duration{Duration: -time.Second},
+				},
+			},
+			errInfo: fmt.Errorf("policyCheck timeout must not be negative"),
+		}, {
+			name: "passing valid policy check",
+			args: &DefaultEvictorArgs{
+				PolicyCheck: &PolicyCheck{URL: "https://opa:8181/v1/data/descheduler/allow"},
+			},
 		},
 	}
 
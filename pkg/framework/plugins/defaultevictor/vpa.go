@@ -0,0 +1,71 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package defaultevictor
+
+import (
+	"math"
+
+	v1 "k8s.io/api/core/v1"
+
+	"sigs.k8s.io/descheduler/pkg/descheduler/vpa"
+)
+
+// vpaChangeThreshold is the relative difference between a container request
+// and its recommendation above which the VPA updater applies the
+// recommendation, it matches the updater default.
+const vpaChangeThreshold = 0.1
+
+// isPodPendingVPAUpdate checks if any of the VerticalPodAutoscalers targeting
+// the pod workload is about to update the pod. that is the case when the
+// request of one of its containers is outside the recommended range or
+// differs from the recommended target by more than vpaChangeThreshold.
+func isPodPendingVPAUpdate(pod *v1.Pod, vpas []vpa.VerticalPodAutoscaler) bool {
+	for _, autoscaler := range vpas {
+		if !autoscaler.Targets(pod) {
+			continue
+		}
+		if policy := autoscaler.Spec.UpdatePolicy; policy != nil && (policy.UpdateMode == "Off" || policy.UpdateMode == "Initial") {
+			continue
+		}
+		for _, container := range pod.Spec.Containers {
+			recommendation := autoscaler.ContainerRecommendation(container.Name)
+			if recommendation != nil && isContainerPendingUpdate(container, *recommendation) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isContainerPendingUpdate compares the container requests with the
+// recommendation for the container.
+func isContainerPendingUpdate(container v1.Container, recommendation vpa.ContainerRecommendation) bool {
+	for name, target := range recommendation.Target {
+		request, ok := container.Resources.Requests[name]
+		if !ok || request.IsZero() {
+			continue
+		}
+		if lower, ok := recommendation.LowerBound[name]; ok && request.Cmp(lower) < 0 {
+			return true
+		}
+		if upper, ok := recommendation.UpperBound[name]; ok && request.Cmp(upper) > 0 {
+			return true
+		}
+		diff := math.Abs(float64(target.MilliValue()-request.MilliValue())) / float64(request.MilliValue())
+		if diff > vpaChangeThreshold {
+			return true
+		}
+	}
+	return false
+}
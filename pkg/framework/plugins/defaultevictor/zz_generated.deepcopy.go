@@ -46,6 +46,16 @@ func (in *DefaultEvictorArgs) DeepCopyInto(out *DefaultEvictorArgs) {
 		*out = new(v1.Duration)
 		**out = **in
 	}
+	if in.ScalingCooldown != nil {
+		in, out := &in.ScalingCooldown, &out.ScalingCooldown
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.PolicyCheck != nil {
+		in, out := &in.PolicyCheck, &out.PolicyCheck
+		*out = new(PolicyCheck)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -66,3 +76,24 @@ func (in *DefaultEvictorArgs) DeepCopyObject() runtime.Object {
 	}
 	return nil
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyCheck) DeepCopyInto(out *PolicyCheck) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyCheck.
+func (in *PolicyCheck) DeepCopy() *PolicyCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyCheck)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	nodeutil "sigs.k8s.io/descheduler/pkg/descheduler/node"
+	podutil "sigs.k8s.io/descheduler/pkg/descheduler/pod"
+	"sigs.k8s.io/descheduler/pkg/utils"
+)
+
+// affinityAssessor tells the pods whose affinity is better satisfied on the
+// node they run on than on any of the destination nodes apart. such pods are
+// expected to be scheduled back on their node once evicted. nodes are scored
+// the way the scheduler NodeAffinity and InterPodAffinity plugins do: the
+// weights of the preferred node affinity terms the node matches, plus the
+// weights of the preferred pod affinity terms satisfied in the topology
+// domain of the node, minus the weights of the preferred pod anti-affinity
+// terms violated there. nodes violating a required pod affinity term are
+// not feasible at all. it is not safe for concurrent use.
+type affinityAssessor struct {
+	policy      AffinityAwareness
+	nodeIndexer podutil.GetPodsAssignedToNodeFunc
+	// nodes are all the nodes the topology domains are resolved against.
+	nodes []*v1.Node
+	pods  map[string][]*v1.Pod
+}
+
+// newAffinityAssessor returns an assessor for the provided nodes, nil is
+// returned if the affinity of the pods is to be ignored.
+func newAffinityAssessor(
+	policy AffinityAwareness,
+	nodeIndexer podutil.GetPodsAssignedToNodeFunc,
+	nodes []*v1.Node,
+) *affinityAssessor {
+	if policy == "" {
+		return nil
+	}
+	return &affinityAssessor{
+		policy:      policy,
+		nodeIndexer: nodeIndexer,
+		nodes:       nodes,
+		pods:        map[string][]*v1.Pod{},
+	}
+}
+
+// apply skips or moves to the end the pods, among the eviction candidates of
+// the source node, bound to it by their affinity. it returns the remaining
+// candidates and the number of skipped pods.
+func (a *affinityAssessor) apply(pods []*v1.Pod, source *v1.Node, destinations []*v1.Node) ([]*v1.Pod, int) {
+	if a == nil {
+		return pods, 0
+	}
+	var free, bound []*v1.Pod
+	for _, pod := range pods {
+		if !a.boundToSource(pod, source, destinations) {
+			free = append(free, pod)
+			continue
+		}
+		klog.V(3).InfoS(
+			"Pod affinity is best satisfied on its node",
+			"pod", klog.KObj(pod),
+			"node", klog.KObj(source),
+			"policy", a.policy,
+		)
+		bound = append(bound, pod)
+	}
+	if a.policy == AffinityAwarenessSkip {
+		return free, len(bound)
+	}
+	return append(free, bound...), 0
+}
+
+// boundToSource tells if no destination node, other than the source one,
+// satisfies the affinity of the pod as well as the source node does.
+func (a *affinityAssessor) boundToSource(pod *v1.Pod, source *v1.Node, destinations []*v1.Node) bool {
+	if !hasAffinityPreferences(pod) {
+		return false
+	}
+	feasible, sourceScore := a.score(pod, source)
+	if !feasible {
+		return false
+	}
+	for _, destination := range destinations {
+		if destination.Name == source.Name {
+			continue
+		}
+		if feasible, score := a.score(pod, destination); feasible && score >= sourceScore {
+			return false
+		}
+	}
+	return true
+}
+
+// hasAffinityPreferences tells if the pod expresses any preference the
+// assessor scores, required node affinity is left to the node fit checks.
+func hasAffinityPreferences(pod *v1.Pod) bool {
+	affinity := pod.Spec.Affinity
+	if affinity == nil {
+		return false
+	}
+	if affinity.NodeAffinity != nil && len(affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution) > 0 {
+		return true
+	}
+	return affinity.PodAffinity != nil || affinity.PodAntiAffinity != nil
+}
+
+// score returns if the pod can run on the node as far as its required pod
+// affinity is concerned, and how much the pod prefers the node.
+func (a *affinityAssessor) score(pod *v1.Pod, node *v1.Node) (bool, int64) {
+	score := int64(nodeutil.GetNodeWeightGivenPodPreferredAffinity(pod, node))
+
+	affinity := pod.Spec.Affinity
+	if affinity.PodAffinity != nil {
+		for i := range affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution {
+			if !a.termSatisfied(pod, &affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution[i], node) {
+				return false, 0
+			}
+		}
+		for i := range affinity.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+			term := &affinity.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution[i]
+			if a.termSatisfied(pod, &term.PodAffinityTerm, node) {
+				score += int64(term.Weight)
+			}
+		}
+	}
+	if affinity.PodAntiAffinity != nil {
+		for i := range affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+			term := &affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution[i]
+			if a.termSatisfied(pod, &term.PodAffinityTerm, node) {
+				score -= int64(term.Weight)
+			}
+		}
+	}
+	return true, score
+}
+
+// termSatisfied tells if a pod, other than the provided one, matching the
+// term runs in the topology domain of the node.
+func (a *affinityAssessor) termSatisfied(pod *v1.Pod, term *v1.PodAffinityTerm, node *v1.Node) bool {
+	domain, ok := node.Labels[term.TopologyKey]
+	if !ok {
+		return false
+	}
+	selector, err := metav1.LabelSelectorAsSelector(term.LabelSelector)
+	if err != nil {
+		klog.ErrorS(err, "Unable to convert LabelSelector into Selector")
+		return false
+	}
+	namespaces := utils.GetNamespacesFromPodAffinityTerm(pod, term)
+	for _, candidate := range a.nodes {
+		if candidate.Labels[term.TopologyKey] != domain {
+			continue
+		}
+		for _, other := range a.podsOn(candidate) {
+			if other.UID == pod.UID {
+				continue
+			}
+			if utils.PodMatchesTermsNamespaceAndSelector(other, namespaces, selector) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// podsOn returns the pods running on the node, listed once per assessor.
+func (a *affinityAssessor) podsOn(node *v1.Node) []*v1.Pod {
+	if pods, ok := a.pods[node.Name]; ok {
+		return pods
+	}
+	pods, err := podutil.ListPodsOnANode(node.Name, a.nodeIndexer, nil)
+	if err != nil {
+		klog.ErrorS(err, "Unable to list the pods of node", "node", klog.KObj(node))
+	}
+	a.pods[node.Name] = pods
+	return pods
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	podutil "sigs.k8s.io/descheduler/pkg/descheduler/pod"
+	"sigs.k8s.io/descheduler/test"
+)
+
+func TestAffinityAssessor(t *testing.T) {
+	n1 := test.BuildTestNode("n1", 4000, 3000, 10, func(node *v1.Node) {
+		node.Labels["disk"] = "ssd"
+		node.Labels[v1.LabelTopologyZone] = "zone-a"
+	})
+	n2 := test.BuildTestNode("n2", 4000, 3000, 10, func(node *v1.Node) {
+		node.Labels[v1.LabelTopologyZone] = "zone-b"
+	})
+	n3 := test.BuildTestNode("n3", 4000, 3000, 10, func(node *v1.Node) {
+		node.Labels[v1.LabelTopologyZone] = "zone-b"
+	})
+
+	podAffinityTerm := func(app string) v1.PodAffinityTerm {
+		return v1.PodAffinityTerm{
+			LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": app}},
+			TopologyKey:   v1.LabelTopologyZone,
+		}
+	}
+	withLabel := func(app string) func(*v1.Pod) {
+		return func(pod *v1.Pod) {
+			pod.Labels = map[string]string{"app": app}
+			pod.UID = types.UID(pod.Name)
+		}
+	}
+	withAffinity := func(affinity *v1.Affinity) func(*v1.Pod) {
+		return func(pod *v1.Pod) {
+			pod.Spec.Affinity = affinity
+			pod.UID = types.UID(pod.Name)
+		}
+	}
+
+	plain := test.BuildTestPod("plain", 100, 0, n1.Name, withLabel("plain"))
+	prefersSSD := test.BuildTestPod("prefers-ssd", 100, 0, n1.Name, withAffinity(&v1.Affinity{
+		NodeAffinity: &v1.NodeAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []v1.PreferredSchedulingTerm{{
+				Weight: 10,
+				Preference: v1.NodeSelectorTerm{
+					MatchExpressions: []v1.NodeSelectorRequirement{{Key: "disk", Operator: v1.NodeSelectorOpIn, Values: []string{"ssd"}}},
+				},
+			}},
+		},
+	}))
+	nearCache := test.BuildTestPod("near-cache", 100, 0, n1.Name, withAffinity(&v1.Affinity{
+		PodAffinity: &v1.PodAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []v1.WeightedPodAffinityTerm{{Weight: 10, PodAffinityTerm: podAffinityTerm("cache")}},
+		},
+	}))
+	nearDB := test.BuildTestPod("near-db", 100, 0, n1.Name, withAffinity(&v1.Affinity{
+		PodAffinity: &v1.PodAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []v1.WeightedPodAffinityTerm{{Weight: 10, PodAffinityTerm: podAffinityTerm("db")}},
+		},
+	}))
+	awayFromWeb := test.BuildTestPod("away-from-web", 100, 0, n1.Name, withAffinity(&v1.Affinity{
+		PodAntiAffinity: &v1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []v1.WeightedPodAffinityTerm{{Weight: 10, PodAffinityTerm: podAffinityTerm("web")}},
+		},
+	}))
+	requiresItself := test.BuildTestPod("requires-itself", 100, 0, n1.Name, func(pod *v1.Pod) {
+		withLabel("self")(pod)
+		pod.Spec.Affinity = &v1.Affinity{
+			PodAffinity: &v1.PodAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: []v1.PodAffinityTerm{podAffinityTerm("self")},
+			},
+		}
+	})
+
+	// the cache only runs in zone-a, the web server in zone-b and the
+	// database in both zones.
+	running := []*v1.Pod{
+		test.BuildTestPod("cache", 100, 0, n1.Name, withLabel("cache")),
+		test.BuildTestPod("db-1", 100, 0, n1.Name, withLabel("db")),
+		test.BuildTestPod("db-2", 100, 0, n3.Name, withLabel("db")),
+		test.BuildTestPod("web", 100, 0, n2.Name, withLabel("web")),
+	}
+	candidates := []*v1.Pod{plain, prefersSSD, nearCache, nearDB, awayFromWeb, requiresItself}
+	getPodsAssignedToNode := func(node string, filter podutil.FilterFunc) ([]*v1.Pod, error) {
+		var pods []*v1.Pod
+		for _, pod := range append(running, candidates...) {
+			if pod.Spec.NodeName == node && (filter == nil || filter(pod)) {
+				pods = append(pods, pod)
+			}
+		}
+		return pods, nil
+	}
+
+	for _, tc := range []struct {
+		name     string
+		policy   AffinityAwareness
+		expected []string
+		skipped  int
+	}{
+		{
+			name:     "affinity ignored",
+			expected: []string{"plain", "prefers-ssd", "near-cache", "near-db", "away-from-web", "requires-itself"},
+		},
+		{
+			name:     "pods bound to their node skipped",
+			policy:   AffinityAwarenessSkip,
+			expected: []string{"plain", "near-db", "requires-itself"},
+			skipped:  3,
+		},
+		{
+			name:     "pods bound to their node evicted last",
+			policy:   AffinityAwarenessDeprioritize,
+			expected: []string{"plain", "near-db", "requires-itself", "prefers-ssd", "near-cache", "away-from-web"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			nodes := []*v1.Node{n1, n2, n3}
+			assessor := newAffinityAssessor(tc.policy, getPodsAssignedToNode, nodes)
+			pods, skipped := assessor.apply(candidates, n1, []*v1.Node{n2, n3})
+			var names []string
+			for _, pod := range pods {
+				names = append(names, pod.Name)
+			}
+			if len(names) != len(tc.expected) {
+				t.Fatalf("expected pods %v, got %v instead", tc.expected, names)
+			}
+			for i := range names {
+				if names[i] != tc.expected[i] {
+					t.Fatalf("expected pods %v, got %v instead", tc.expected, names)
+				}
+			}
+			if skipped != tc.skipped {
+				t.Errorf("expected %v skipped pods, got %v instead", tc.skipped, skipped)
+			}
+		})
+	}
+}
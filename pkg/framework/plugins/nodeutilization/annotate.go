@@ -0,0 +1,103 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/descheduler/pkg/descheduler/evictions"
+	frameworktypes "sigs.k8s.io/descheduler/pkg/framework/types"
+)
+
+// EvictionRequestedAnnotationKey is set, in the Annotate mode, on the pods
+// the plugins would have evicted. Its value is the time the pod was last
+// selected in the RFC 3339 format.
+const EvictionRequestedAnnotationKey = "descheduler.alpha.kubernetes.io/eviction-requested"
+
+// EvictionReasonAnnotationKey is set next to EvictionRequestedAnnotationKey.
+// It tells why the pod was selected, as the eviction event would have.
+const EvictionReasonAnnotationKey = "descheduler.alpha.kubernetes.io/eviction-reason"
+
+// annotatingEvictor wraps an evictor so the pods selected for eviction are
+// annotated instead of evicted. as with the dry run evictor the pod filters
+// of the wrapped evictor are still honored while its eviction limits are
+// not.
+type annotatingEvictor struct {
+	frameworktypes.Evictor
+	client clientset.Interface
+}
+
+var _ frameworktypes.Evictor = &annotatingEvictor{}
+var _ frameworktypes.DryRunEvictor = &annotatingEvictor{}
+
+// evictorForMode returns the evictor doing what the mode asks for with the
+// pods selected for eviction.
+func evictorForMode(evictor frameworktypes.Evictor, client clientset.Interface, mode BalanceMode) frameworktypes.Evictor {
+	if mode != BalanceModeAnnotate {
+		return evictor
+	}
+	return &annotatingEvictor{Evictor: evictor, client: client}
+}
+
+// DryRun returns true if the wrapped evictor only simulates evictions.
+func (e *annotatingEvictor) DryRun() bool {
+	return frameworktypes.IsDryRun(e.Evictor)
+}
+
+// Evict annotates the pod with the time it was selected and the reason it
+// was selected for. in dry run mode the pod is left untouched.
+func (e *annotatingEvictor) Evict(ctx context.Context, pod *v1.Pod, opts evictions.EvictOptions) error {
+	reason := opts.Details
+	if reason == "" {
+		reason = opts.StrategyName
+	}
+
+	if frameworktypes.IsDryRun(e.Evictor) {
+		klog.V(1).InfoS("Pod annotated for eviction in dry run mode", "pod", klog.KObj(pod), "reason", reason)
+		return nil
+	}
+
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"annotations": map[string]string{
+				EvictionRequestedAnnotationKey: time.Now().UTC().Format(time.RFC3339),
+				EvictionReasonAnnotationKey:    reason,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := e.client.CoreV1().Pods(pod.Namespace).Patch(
+		ctx, pod.Name, types.MergePatchType, patch, metav1.PatchOptions{},
+	); err != nil {
+		return fmt.Errorf("unable to annotate pod %s/%s: %v", pod.Namespace, pod.Name, err)
+	}
+
+	klog.V(1).InfoS("Pod annotated for eviction", "pod", klog.KObj(pod), "reason", reason)
+	return nil
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	core "k8s.io/client-go/testing"
+
+	"sigs.k8s.io/descheduler/pkg/api"
+	"sigs.k8s.io/descheduler/pkg/descheduler/evictions"
+	"sigs.k8s.io/descheduler/pkg/framework/plugins/defaultevictor"
+	frameworktesting "sigs.k8s.io/descheduler/pkg/framework/testing"
+	frameworktypes "sigs.k8s.io/descheduler/pkg/framework/types"
+	"sigs.k8s.io/descheduler/test"
+)
+
+func TestLowNodeUtilizationAnnotateMode(t *testing.T) {
+	ctx := context.Background()
+
+	nodes := []*v1.Node{
+		test.BuildTestNode("n1", 4000, 3000, 10, nil),
+		test.BuildTestNode("n2", 4000, 3000, 10, nil),
+	}
+	objs := []runtime.Object{nodes[0], nodes[1]}
+	for i := 0; i < 4; i++ {
+		objs = append(objs, test.BuildTestPod(fmt.Sprintf("n1-p%d", i), 800, 0, "n1", test.SetRSOwnerRef))
+	}
+	objs = append(objs, test.BuildTestPod("n2-p0", 400, 0, "n2", test.SetRSOwnerRef))
+
+	for _, tc := range []struct {
+		name      string
+		dryRun    bool
+		annotated []string
+	}{
+		{
+			name:      "pods annotated",
+			annotated: []string{"n1-p0", "n1-p1"},
+		},
+		{
+			name:   "nothing annotated in dry run mode",
+			dryRun: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(objs...)
+			evicted := 0
+			client.PrependReactor("create", "pods", func(action core.Action) (bool, runtime.Object, error) {
+				if action.GetSubresource() == "eviction" {
+					evicted++
+				}
+				return false, nil, nil
+			})
+
+			handle, _, err := frameworktesting.InitFrameworkHandle(
+				ctx,
+				client,
+				evictions.NewOptions().WithDryRun(tc.dryRun),
+				defaultevictor.DefaultEvictorArgs{},
+				func(pods []*v1.Pod) {
+					sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+				},
+			)
+			if err != nil {
+				t.Fatalf("Unable to initialize a framework handle: %v", err)
+			}
+
+			plugin, err := NewLowNodeUtilization(&LowNodeUtilizationArgs{
+				Thresholds:       api.ResourceThresholds{v1.ResourceCPU: 30},
+				TargetThresholds: api.ResourceThresholds{v1.ResourceCPU: 50},
+				Mode:             BalanceModeAnnotate,
+			}, handle)
+			if err != nil {
+				t.Fatalf("Unable to initialize the plugin: %v", err)
+			}
+
+			if status := plugin.(frameworktypes.BalancePlugin).Balance(ctx, nodes); status != nil && status.Err != nil {
+				t.Fatalf("Unexpected error: %v", status.Err)
+			}
+			if evicted != 0 {
+				t.Errorf("Expected no pod to be evicted, got %v", evicted)
+			}
+
+			pods, err := client.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+			if err != nil {
+				t.Fatalf("Unable to list pods: %v", err)
+			}
+			var annotated []string
+			for _, pod := range pods.Items {
+				if _, ok := pod.Annotations[EvictionRequestedAnnotationKey]; !ok {
+					continue
+				}
+				annotated = append(annotated, pod.Name)
+				if reason := pod.Annotations[EvictionReasonAnnotationKey]; !strings.HasPrefix(reason, LowNodeUtilizationPluginName) {
+					t.Errorf("Expected pod %v to be annotated with the reason it was selected, got %q", pod.Name, reason)
+				}
+			}
+			sort.Strings(annotated)
+			if strings.Join(annotated, ",") != strings.Join(tc.annotated, ",") {
+				t.Errorf("Expected pods %v to be annotated, got %v", tc.annotated, annotated)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	v1 "k8s.io/api/core/v1"
+
+	"sigs.k8s.io/descheduler/metrics"
+	"sigs.k8s.io/descheduler/pkg/tracing"
+)
+
+// reasons pods considered for eviction are skipped for, as reported by the
+// balance_pods_total metric.
+const (
+	skipReasonTaints           = "taints"
+	skipReasonFilter           = "filter"
+	skipReasonAffinity         = "affinity"
+	skipReasonWorkloadLimit    = "workload_limit"
+	skipReasonDisruptionBudget = "disruption_budget"
+	skipReasonNoFit            = "no_fit"
+	skipReasonUsageUnavailable = "usage_unavailable"
+	skipReasonNoRoom           = "no_room"
+	skipReasonEvictionFailed   = "eviction_failed"
+)
+
+// results of the pods considered for eviction, as reported by the
+// balance_pods_total metric.
+const (
+	balancePodsResultConsidered = "considered"
+	balancePodsResultEvicted    = "evicted"
+	balancePodsResultSkipped    = "skipped"
+)
+
+// node classifications, as reported by the balance_nodes_classified metric.
+const (
+	classificationUnderutilized = "underutilized"
+	classificationOverutilized  = "overutilized"
+	classificationAppropriate   = "appropriately_utilized"
+)
+
+// podSkipped accounts for a pod skipped for the provided reason.
+func (s *balanceSummary) podSkipped(reason string) {
+	s.skipped++
+	s.skippedPerReason[reason]++
+}
+
+// remaining keeps what the destination nodes could still accept once the
+// evictions are over.
+func (s *balanceSummary) remaining(headroom *platformHeadroom) {
+	s.available = headroom.total()
+}
+
+// exportMetrics reports the outcome of the invocation through the
+// descheduler metrics.
+func (s *balanceSummary) exportMetrics() {
+	appropriate := max(len(s.usage)-s.underutilized-s.overutilized, 0)
+	for classification, count := range map[string]int{
+		classificationUnderutilized: s.underutilized,
+		classificationOverutilized:  s.overutilized,
+		classificationAppropriate:   appropriate,
+	} {
+		metrics.BalanceNodesClassified.With(map[string]string{
+			"strategy":       s.plugin,
+			"classification": classification,
+		}).Set(float64(count))
+	}
+
+	pods := func(result, reason string) map[string]string {
+		return map[string]string{"strategy": s.plugin, "result": result, "reason": reason}
+	}
+	metrics.BalancePods.With(pods(balancePodsResultConsidered, "")).Add(float64(s.considered))
+	metrics.BalancePods.With(pods(balancePodsResultEvicted, "")).Add(float64(s.evicted))
+	for reason, count := range s.skippedPerReason {
+		metrics.BalancePods.With(pods(balancePodsResultSkipped, reason)).Add(float64(count))
+	}
+
+	for name, quantity := range s.available {
+		if quantity == nil {
+			continue
+		}
+		metrics.BalanceTotalAvailableUsage.With(map[string]string{
+			"strategy": s.plugin,
+			"resource": string(name),
+		}).Set(quantity.AsApproximateFloat64())
+	}
+}
+
+// String returns the name of the usage client type, as reported by the
+// usage_client_sync_duration_seconds metric.
+func (t UsageClientType) String() string {
+	switch t {
+	case requestedUsageClientType:
+		return "requested"
+	case actualUsageClientType:
+		return "actual"
+	case prometheusUsageClientType:
+		return "prometheus"
+	case compositeUsageClientType:
+		return "composite"
+	case vpaRecommendationUsageClientType:
+		return "vpa_recommendation"
+	case customMetricsUsageClientType:
+		return "custom_metrics"
+	case openTelemetryUsageClientType:
+		return "open_telemetry"
+	case staticUsageClientType:
+		return "static"
+	default:
+		return "unknown"
+	}
+}
+
+// usageClientTypeName returns the name of the type of the usage client.
+func usageClientTypeName(client UsageClient) string {
+	switch client.(type) {
+	case *requestedUsageClient:
+		return requestedUsageClientType.String()
+	case *actualUsageClient:
+		return actualUsageClientType.String()
+	case *prometheusUsageClient:
+		return prometheusUsageClientType.String()
+	case *compositeUsageClient:
+		return compositeUsageClientType.String()
+	case *vpaRecommendationUsageClient:
+		return vpaRecommendationUsageClientType.String()
+	case *customMetricsUsageClient:
+		return customMetricsUsageClientType.String()
+	case *openTelemetryUsageClient:
+		return openTelemetryUsageClientType.String()
+	case *staticUsageClient:
+		return staticUsageClientType.String()
+	default:
+		return "unknown"
+	}
+}
+
+// syncUsageClient syncs the usage client and reports how long the sync took,
+// through the metrics and through a span.
+func syncUsageClient(ctx context.Context, client UsageClient, nodes []*v1.Node) error {
+	clientType := usageClientTypeName(client)
+	ctx, span := tracing.Tracer().Start(ctx, "UsageClient.Sync", trace.WithAttributes(
+		attribute.String("client", clientType),
+		attribute.Int("nodes", len(nodes)),
+	))
+
+	start := time.Now()
+	err := client.Sync(ctx, nodes)
+	metrics.UsageClientSyncDuration.With(map[string]string{
+		"client": clientType,
+	}).Observe(time.Since(start).Seconds())
+	endSpan(span, err)
+	return err
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/component-base/metrics/testutil"
+
+	"sigs.k8s.io/descheduler/metrics"
+	"sigs.k8s.io/descheduler/pkg/api"
+	"sigs.k8s.io/descheduler/pkg/framework/plugins/defaultevictor"
+	frameworktesting "sigs.k8s.io/descheduler/pkg/framework/testing"
+	frameworktypes "sigs.k8s.io/descheduler/pkg/framework/types"
+	"sigs.k8s.io/descheduler/test"
+)
+
+func TestBalanceMetrics(t *testing.T) {
+	ctx := context.Background()
+	metrics.Register()
+
+	n1 := test.BuildTestNode("n1", 4000, 3000, 10, nil)
+	n2 := test.BuildTestNode("n2", 4000, 3000, 10, nil)
+	n3 := test.BuildTestNode("n3", 4000, 3000, 10, nil)
+	nodes := []*v1.Node{n1, n2, n3}
+
+	// n1 is overutilized, n2 underutilized and n3 appropriately utilized.
+	objs := []runtime.Object{n1, n2, n3}
+	for _, name := range []string{"p1", "p2", "p3", "p4"} {
+		objs = append(objs, test.BuildTestPod(name, 800, 0, n1.Name, test.SetRSOwnerRef))
+	}
+	objs = append(objs, test.BuildTestPod("p5", 400, 0, n2.Name, test.SetRSOwnerRef))
+	objs = append(objs, test.BuildTestPod("p6", 1600, 0, n3.Name, test.SetRSOwnerRef))
+
+	handle, podEvictor, err := frameworktesting.InitFrameworkHandle(
+		ctx,
+		fake.NewSimpleClientset(objs...),
+		nil,
+		defaultevictor.DefaultEvictorArgs{},
+		func(pods []*v1.Pod) {
+			sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+		},
+	)
+	if err != nil {
+		t.Fatalf("Unable to initialize a framework handle: %v", err)
+	}
+
+	plugin, err := NewLowNodeUtilization(&LowNodeUtilizationArgs{
+		Thresholds:       api.ResourceThresholds{v1.ResourceCPU: 30},
+		TargetThresholds: api.ResourceThresholds{v1.ResourceCPU: 50},
+	}, handle)
+	if err != nil {
+		t.Fatalf("Unable to initialize the plugin: %v", err)
+	}
+
+	pods := func(result string) float64 {
+		value, err := testutil.GetCounterMetricValue(metrics.BalancePods.With(map[string]string{
+			"strategy": LowNodeUtilizationPluginName, "result": result, "reason": "",
+		}))
+		if err != nil {
+			t.Fatalf("Unable to read the %v pods: %v", result, err)
+		}
+		return value
+	}
+	syncs := func() uint64 {
+		count, err := testutil.GetHistogramMetricCount(metrics.UsageClientSyncDuration.With(map[string]string{
+			"client": requestedUsageClientType.String(),
+		}))
+		if err != nil {
+			t.Fatalf("Unable to read the usage client syncs: %v", err)
+		}
+		return count
+	}
+	consideredBefore, evictedBefore, syncsBefore := pods(balancePodsResultConsidered), pods(balancePodsResultEvicted), syncs()
+
+	if status := plugin.(frameworktypes.BalancePlugin).Balance(ctx, nodes); status != nil && status.Err != nil {
+		t.Fatalf("Unexpected error: %v", status.Err)
+	}
+	if evicted := podEvictor.TotalEvicted(); evicted != 2 {
+		t.Fatalf("Expected 2 pods to be evicted, got %v", evicted)
+	}
+
+	for classification, expected := range map[string]float64{
+		classificationUnderutilized: 1,
+		classificationOverutilized:  1,
+		classificationAppropriate:   1,
+	} {
+		value, err := testutil.GetGaugeMetricValue(metrics.BalanceNodesClassified.With(map[string]string{
+			"strategy": LowNodeUtilizationPluginName, "classification": classification,
+		}))
+		if err != nil {
+			t.Fatalf("Unable to read the %v nodes: %v", classification, err)
+		}
+		if value != expected {
+			t.Errorf("Expected %v %v nodes, got %v", expected, classification, value)
+		}
+	}
+
+	if evicted := pods(balancePodsResultEvicted) - evictedBefore; evicted != 2 {
+		t.Errorf("Expected 2 evicted pods to be reported, got %v", evicted)
+	}
+	if considered := pods(balancePodsResultConsidered) - consideredBefore; considered < 2 {
+		t.Errorf("Expected at least 2 considered pods to be reported, got %v", considered)
+	}
+	// the achieved utilization drop is assessed with the usage synced
+	// on the next cycle, not with a sync of its own.
+	if synced := syncs() - syncsBefore; synced != 1 {
+		t.Errorf("Expected 1 usage client sync to be reported, got %v", synced)
+	}
+
+	// n2 is the only destination, the 1600m of cpu it has left below its
+	// 50% target threshold are taken by the two evicted pods.
+	available, err := testutil.GetGaugeMetricValue(metrics.BalanceTotalAvailableUsage.With(map[string]string{
+		"strategy": LowNodeUtilizationPluginName, "resource": string(v1.ResourceCPU),
+	}))
+	if err != nil {
+		t.Fatalf("Unable to read the total available usage: %v", err)
+	}
+	if available != 0 {
+		t.Errorf("Expected no cpu to be left on the destination nodes, got %v", available)
+	}
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/descheduler/pkg/api"
+	"sigs.k8s.io/descheduler/pkg/framework/plugins/nodeutilization/classifier"
+	"sigs.k8s.io/descheduler/pkg/framework/plugins/nodeutilization/normalizer"
+)
+
+// benchmarkClusterSizes are the number of nodes the classification pipeline
+// is benchmarked with.
+var benchmarkClusterSizes = []int{100, 1000, 10000}
+
+// syntheticCluster holds the data the classification pipeline works on for
+// a generated cluster.
+type syntheticCluster struct {
+	nodes      []*v1.Node
+	usages     map[string]api.ReferencedResourceList
+	capacities map[string]api.ReferencedResourceList
+}
+
+// newSyntheticCluster generates a cluster with the provided number of nodes.
+// Nodes come in a few different shapes and have a random usage between 0 and
+// their capacity. The same seed always generates the same cluster.
+func newSyntheticCluster(size int, seed int64) *syntheticCluster {
+	rnd := rand.New(rand.NewSource(seed))
+	shapes := []struct {
+		cpu, memory, pods int64
+	}{
+		{cpu: 4000, memory: 16 << 30, pods: 110},
+		{cpu: 8000, memory: 32 << 30, pods: 110},
+		{cpu: 16000, memory: 64 << 30, pods: 250},
+	}
+
+	cluster := &syntheticCluster{
+		usages:     map[string]api.ReferencedResourceList{},
+		capacities: map[string]api.ReferencedResourceList{},
+	}
+	for i := 0; i < size; i++ {
+		shape := shapes[rnd.Intn(len(shapes))]
+		node := &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("node-%d", i)},
+			Status: v1.NodeStatus{
+				Capacity: v1.ResourceList{
+					v1.ResourceCPU:    *resource.NewMilliQuantity(shape.cpu, resource.DecimalSI),
+					v1.ResourceMemory: *resource.NewQuantity(shape.memory, resource.BinarySI),
+					v1.ResourcePods:   *resource.NewQuantity(shape.pods, resource.DecimalSI),
+				},
+			},
+		}
+		cluster.nodes = append(cluster.nodes, node)
+		cluster.capacities[node.Name] = referencedResourceListForNodeCapacity(node)
+		cluster.usages[node.Name] = api.ReferencedResourceList{
+			v1.ResourceCPU:    resource.NewMilliQuantity(rnd.Int63n(shape.cpu), resource.DecimalSI),
+			v1.ResourceMemory: resource.NewQuantity(rnd.Int63n(shape.memory), resource.BinarySI),
+			v1.ResourcePods:   resource.NewQuantity(rnd.Int63n(shape.pods), resource.DecimalSI),
+		}
+	}
+	return cluster
+}
+
+// nodeInfos returns the cluster nodes as NodeInfo structs.
+func (c *syntheticCluster) nodeInfos() []NodeInfo {
+	infos := make([]NodeInfo, 0, len(c.nodes))
+	for _, node := range c.nodes {
+		infos = append(infos, NodeInfo{
+			NodeUsage: NodeUsage{node: node, usage: c.usages[node.Name]},
+		})
+	}
+	return infos
+}
+
+var (
+	benchmarkLowThresholds  = api.ResourceThresholds{v1.ResourceCPU: 20, v1.ResourceMemory: 20, v1.ResourcePods: 20}
+	benchmarkHighThresholds = api.ResourceThresholds{v1.ResourceCPU: 70, v1.ResourceMemory: 70, v1.ResourcePods: 70}
+)
+
+func benchmarkNormalize(b *testing.B, cluster *syntheticCluster) {
+	for i := 0; i < b.N; i++ {
+		normalizer.Normalize(cluster.usages, cluster.capacities, ResourceUsageToResourceThreshold)
+	}
+}
+
+func benchmarkAssess(b *testing.B, cluster *syntheticCluster) {
+	for i := 0; i < b.N; i++ {
+		assessNodesUsagesAndRelativeThresholds(
+			cluster.usages, cluster.capacities, benchmarkLowThresholds, benchmarkHighThresholds,
+		)
+	}
+}
+
+func benchmarkClassify(b *testing.B, cluster *syntheticCluster) {
+	usage, thresholds := assessNodesUsagesAndStaticThresholds(
+		cluster.usages, cluster.capacities, benchmarkLowThresholds, benchmarkHighThresholds,
+	)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		classifier.Classify(
+			usage, thresholds,
+			func(_ string, usage, threshold api.ResourceThresholds) bool {
+				return isNodeBelowThreshold(usage, threshold)
+			},
+			func(_ string, usage, threshold api.ResourceThresholds) bool {
+				return isNodeAboveThreshold(usage, threshold)
+			},
+		)
+	}
+}
+
+func benchmarkSortNodesByUsage(b *testing.B, cluster *syntheticCluster) {
+	infos := cluster.nodeInfos()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// alternate the order so every iteration has sorting to do.
+		sortNodesByUsage(infos, i%2 == 0, nil)
+	}
+}
+
+// runClusterBenchmarks runs the provided benchmark once for each of the
+// cluster sizes.
+func runClusterBenchmarks(b *testing.B, fn func(*testing.B, *syntheticCluster)) {
+	for _, size := range benchmarkClusterSizes {
+		cluster := newSyntheticCluster(size, 1)
+		b.Run(fmt.Sprintf("nodes=%d", size), func(b *testing.B) {
+			b.ReportAllocs()
+			fn(b, cluster)
+		})
+	}
+}
+
+func BenchmarkNormalize(b *testing.B) {
+	runClusterBenchmarks(b, benchmarkNormalize)
+}
+
+func BenchmarkAssessNodesUsagesAndRelativeThresholds(b *testing.B) {
+	runClusterBenchmarks(b, benchmarkAssess)
+}
+
+func BenchmarkClassify(b *testing.B) {
+	runClusterBenchmarks(b, benchmarkClassify)
+}
+
+func BenchmarkSortNodesByUsage(b *testing.B) {
+	runClusterBenchmarks(b, benchmarkSortNodesByUsage)
+}
+
+// TestClassificationPipelinePerformanceBudgets makes sure each step of the
+// classification pipeline stays within its time budget for a 10k nodes
+// cluster. Budgets are set an order of magnitude above the measured times so
+// only real regressions trip them. Wall clock budgets depend on the machine
+// the tests run on, the test only runs with PERFORMANCE_BUDGETS set.
+func TestClassificationPipelinePerformanceBudgets(t *testing.T) {
+	if os.Getenv("PERFORMANCE_BUDGETS") == "" {
+		t.Skip("skipping performance budgets, set PERFORMANCE_BUDGETS to run them")
+	}
+
+	cluster := newSyntheticCluster(10000, 1)
+	for _, tc := range []struct {
+		name   string
+		fn     func(*testing.B, *syntheticCluster)
+		budget time.Duration
+	}{
+		{name: "normalize", fn: benchmarkNormalize, budget: 500 * time.Millisecond},
+		{name: "assess", fn: benchmarkAssess, budget: 500 * time.Millisecond},
+		{name: "classify", fn: benchmarkClassify, budget: 250 * time.Millisecond},
+		{name: "sort", fn: benchmarkSortNodesByUsage, budget: 500 * time.Millisecond},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result := testing.Benchmark(func(b *testing.B) { tc.fn(b, cluster) })
+			elapsed := time.Duration(result.NsPerOp())
+			t.Logf("%s: %v per operation, %v allocations", tc.name, elapsed, result.AllocsPerOp())
+			if elapsed > tc.budget {
+				t.Errorf("%s took %v per operation, over the %v budget", tc.name, elapsed, tc.budget)
+			}
+		})
+	}
+}
@@ -15,6 +15,12 @@ limitations under the License.
 
 package classifier
 
+import (
+	"context"
+
+	"k8s.io/client-go/util/workqueue"
+)
+
 // Classifier is a function that classifies a resource usage based on a limit.
 // The function should return true if the resource usage matches the classifier
 // intent.
@@ -34,6 +40,15 @@ type Values[K comparable, V any] map[K]V
 // inside the list requires a classifier to evaluate.
 type Limits[K comparable, V any] map[K][]V
 
+// classifyParallelism is the number of workers evaluating the classifiers
+// concurrently. classifyChunkSize is the number of values each worker
+// evaluates at once, this keeps the synchronization overhead low when the
+// classifiers are cheap.
+const (
+	classifyParallelism = 16
+	classifyChunkSize   = 64
+)
+
 // Classify is a function that classifies based on classifier functions. This
 // function receives Values, a list of n Limits (indexed by name), and a list
 // of n Classifiers. The classifier at n position is called to evaluate the
@@ -42,6 +57,8 @@ type Limits[K comparable, V any] map[K][]V
 // evaluated. This function returns a slice of maps, each position in the
 // returned slice correspond to one of the classifiers (e.g. if n limits
 // and classifiers are provided, the returned slice will have n maps).
+// Values are evaluated in parallel, classifiers must be safe to be called
+// concurrently.
 func Classify[K comparable, V any](
 	values Values[K, V], limits Limits[K, V], classifiers ...Classifier[K, V],
 ) []map[K]V {
@@ -50,17 +67,39 @@ func Classify[K comparable, V any](
 		result[i] = make(map[K]V)
 	}
 
-	for index, usage := range values {
-		for i, limit := range limits[index] {
-			if len(classifiers) <= i {
-				continue
-			}
-			if !classifiers[i](index, usage, limit) {
-				continue
+	indexes := make([]K, 0, len(values))
+	for index := range values {
+		indexes = append(indexes, index)
+	}
+
+	// each worker only writes the position of the value it evaluates so
+	// no locking is needed. the result maps are populated afterwards.
+	classes := make([]int, len(indexes))
+	workqueue.ParallelizeUntil(
+		context.Background(), classifyParallelism, len(indexes),
+		func(piece int) {
+			index := indexes[piece]
+			classes[piece] = -1
+			for i, limit := range limits[index] {
+				if len(classifiers) <= i {
+					continue
+				}
+				if !classifiers[i](index, values[index], limit) {
+					continue
+				}
+				classes[piece] = i
+				break
 			}
-			result[i][index] = usage
-			break
+		},
+		workqueue.WithChunkSize(classifyChunkSize),
+	)
+
+	for piece, class := range classes {
+		if class < 0 {
+			continue
 		}
+		index := indexes[piece]
+		result[class][index] = values[index]
 	}
 
 	return result
@@ -70,7 +109,17 @@ func Classify[K comparable, V any](
 // map. The function receives a Comparer function that is used to compare all
 // the map values. The returned Classifier will return true only if the
 // provided Comparer function returns a value less than 0 for all the values.
+// It is equivalent to ForMapAll.
 func ForMap[K, I comparable, V any, M ~map[I]V](cmp Comparer[V]) Classifier[K, M] {
+	return ForMapAll[K, I, V, M](cmp)
+}
+
+// ForMapAll returns a classifier that compares all values in a map against
+// their limits. The returned Classifier returns true only if the provided
+// Comparer function returns a value less than 0 for all the values with a
+// limit, e.g. a node is underutilized only if all its resources are below
+// their thresholds. Values without a limit are ignored.
+func ForMapAll[K, I comparable, V any, M ~map[I]V](cmp Comparer[V]) Classifier[K, M] {
 	return func(_ K, usages, limits M) bool {
 		for idx, usage := range usages {
 			if limit, ok := limits[idx]; ok {
@@ -82,3 +131,52 @@ func ForMap[K, I comparable, V any, M ~map[I]V](cmp Comparer[V]) Classifier[K, M
 		return true
 	}
 }
+
+// ForMapAny returns a classifier that compares all values in a map against
+// their limits. The returned Classifier returns true if the provided Comparer
+// function returns a value less than 0 for at least one of the values with a
+// limit, e.g. a node is overutilized as soon as one of its resources is above
+// its threshold. It is equivalent to ForMapMinCount with a count of 1.
+func ForMapAny[K, I comparable, V any, M ~map[I]V](cmp Comparer[V]) Classifier[K, M] {
+	return ForMapMinCount[K, I, V, M](1, cmp)
+}
+
+// ForMapMinCount returns a classifier that compares all values in a map
+// against their limits. The returned Classifier returns true if the provided
+// Comparer function returns a value less than 0 for at least n of the values
+// with a limit, e.g. a node is overutilized when two of its resources are
+// above their thresholds. A count of 0 or less always returns true.
+func ForMapMinCount[K, I comparable, V any, M ~map[I]V](n int, cmp Comparer[V]) Classifier[K, M] {
+	return func(_ K, usages, limits M) bool {
+		count := 0
+		for idx, usage := range usages {
+			if count >= n {
+				break
+			}
+			if limit, ok := limits[idx]; ok && cmp(usage, limit) < 0 {
+				count++
+			}
+		}
+		return count >= n
+	}
+}
+
+// WithHysteresis returns a classifier that evaluates values with two limits
+// so values hovering around a limit do not flap between classes. Values not
+// classified by the classifier on the previous run are evaluated against the
+// limit provided to the returned Classifier (the enter limit), values the
+// previous function reports as classified against their exitLimits entry,
+// usually a looser one. Values without an exit limit are always evaluated
+// against the enter limit.
+func WithHysteresis[K comparable, V any](
+	classifier Classifier[K, V], exitLimits map[K]V, previous func(K) bool,
+) Classifier[K, V] {
+	return func(key K, value, limit V) bool {
+		if previous(key) {
+			if exit, ok := exitLimits[key]; ok {
+				return classifier(key, value, exit)
+			}
+		}
+		return classifier(key, value, limit)
+	}
+}
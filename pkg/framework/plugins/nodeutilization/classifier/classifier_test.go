@@ -16,6 +16,7 @@ limitations under the License.
 package classifier
 
 import (
+	"fmt"
 	"reflect"
 	"testing"
 
@@ -737,3 +738,164 @@ func TestClassify(t *testing.T) {
 		})
 	}
 }
+
+func TestClassifyManyValues(t *testing.T) {
+	usage := map[string]int{}
+	limits := map[string][]int{}
+	expected := []map[string]int{{}, {}}
+	for i := 0; i < 5000; i++ {
+		name := fmt.Sprintf("node%d", i)
+		usage[name] = i % 10
+		limits[name] = []int{3, 6}
+		switch {
+		case i%10 < 3:
+			expected[0][name] = i % 10
+		case i%10 > 6:
+			expected[1][name] = i % 10
+		}
+	}
+
+	result := Classify(
+		usage, limits,
+		func(_ string, usage, limit int) bool {
+			return usage < limit
+		},
+		func(_ string, usage, limit int) bool {
+			return usage > limit
+		},
+	)
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("unexpected result: %v", result)
+	}
+}
+
+func TestForMapAggregations(t *testing.T) {
+	lessThan := func(usage, limit int) int {
+		return usage - limit
+	}
+	limits := map[string]int{"cpu": 50, "memory": 50, "pods": 50}
+
+	for _, tt := range []struct {
+		name       string
+		classifier Classifier[string, map[string]int]
+		usage      map[string]int
+		expected   bool
+	}{
+		{
+			name:       "all, every value below its limit",
+			classifier: ForMapAll[string, string, int, map[string]int](lessThan),
+			usage:      map[string]int{"cpu": 10, "memory": 20, "pods": 30},
+			expected:   true,
+		},
+		{
+			name:       "all, one value above its limit",
+			classifier: ForMapAll[string, string, int, map[string]int](lessThan),
+			usage:      map[string]int{"cpu": 10, "memory": 80, "pods": 30},
+			expected:   false,
+		},
+		{
+			name:       "all, values without limit are ignored",
+			classifier: ForMapAll[string, string, int, map[string]int](lessThan),
+			usage:      map[string]int{"cpu": 10, "gpu": 80},
+			expected:   true,
+		},
+		{
+			name:       "any, one value below its limit",
+			classifier: ForMapAny[string, string, int, map[string]int](lessThan),
+			usage:      map[string]int{"cpu": 80, "memory": 20, "pods": 80},
+			expected:   true,
+		},
+		{
+			name:       "any, no value below its limit",
+			classifier: ForMapAny[string, string, int, map[string]int](lessThan),
+			usage:      map[string]int{"cpu": 80, "memory": 80, "gpu": 10},
+			expected:   false,
+		},
+		{
+			name:       "any, no value",
+			classifier: ForMapAny[string, string, int, map[string]int](lessThan),
+			usage:      map[string]int{},
+			expected:   false,
+		},
+		{
+			name:       "min count, enough values below their limit",
+			classifier: ForMapMinCount[string, string, int, map[string]int](2, lessThan),
+			usage:      map[string]int{"cpu": 10, "memory": 20, "pods": 80},
+			expected:   true,
+		},
+		{
+			name:       "min count, not enough values below their limit",
+			classifier: ForMapMinCount[string, string, int, map[string]int](2, lessThan),
+			usage:      map[string]int{"cpu": 10, "memory": 80, "pods": 80},
+			expected:   false,
+		},
+		{
+			name:       "min count, zero",
+			classifier: ForMapMinCount[string, string, int, map[string]int](0, lessThan),
+			usage:      map[string]int{"cpu": 80},
+			expected:   true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.classifier("node1", tt.usage, limits); result != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestWithHysteresis(t *testing.T) {
+	above := func(_ string, usage, limit int) bool {
+		return usage > limit
+	}
+	previous := map[string]bool{"node2": true, "node3": true}
+	classifier := WithHysteresis(
+		above,
+		map[string]int{"node1": 60, "node2": 60},
+		func(name string) bool { return previous[name] },
+	)
+
+	for _, tt := range []struct {
+		name     string
+		node     string
+		usage    int
+		expected bool
+	}{
+		{
+			name:     "not previously classified, below the enter limit",
+			node:     "node1",
+			usage:    70,
+			expected: false,
+		},
+		{
+			name:     "not previously classified, above the enter limit",
+			node:     "node1",
+			usage:    90,
+			expected: true,
+		},
+		{
+			name:     "previously classified, above the exit limit",
+			node:     "node2",
+			usage:    70,
+			expected: true,
+		},
+		{
+			name:     "previously classified, below the exit limit",
+			node:     "node2",
+			usage:    50,
+			expected: false,
+		},
+		{
+			name:     "previously classified, without exit limit",
+			node:     "node3",
+			usage:    70,
+			expected: false,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := classifier(tt.node, tt.usage, 80); result != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
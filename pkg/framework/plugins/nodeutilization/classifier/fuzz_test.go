@@ -0,0 +1,182 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package classifier
+
+import (
+	"cmp"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/descheduler/pkg/api"
+)
+
+var (
+	fuzzNodes     = []string{"node1", "node2", "node3", "node4"}
+	fuzzResources = []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory, v1.ResourcePods}
+)
+
+// fuzzReader hands out values decoded from the fuzzer input, zero values
+// are returned once the input is exhausted.
+type fuzzReader struct {
+	data []byte
+}
+
+func (r *fuzzReader) byte() byte {
+	if len(r.data) == 0 {
+		return 0
+	}
+	b := r.data[0]
+	r.data = r.data[1:]
+	return b
+}
+
+func (r *fuzzReader) percentage() api.Percentage {
+	if len(r.data) < 8 {
+		r.data = nil
+		return 0
+	}
+	value := math.Float64frombits(binary.LittleEndian.Uint64(r.data[:8]))
+	r.data = r.data[8:]
+	return api.Percentage(value)
+}
+
+// thresholds decodes a set of percentages, the resource set is picked by
+// the input so usages and limits may not share the same resources.
+func (r *fuzzReader) thresholds() api.ResourceThresholds {
+	result := api.ResourceThresholds{}
+	mask := r.byte()
+	for i, name := range fuzzResources {
+		if mask&(1<<i) != 0 {
+			result[name] = r.percentage()
+		}
+	}
+	return result
+}
+
+// fuzzInput decodes the usage and a list of limits, of any length, for
+// every node picked by the input.
+func fuzzInput(data []byte) (Values[string, api.ResourceThresholds], Limits[string, api.ResourceThresholds]) {
+	reader := &fuzzReader{data: data}
+	values := Values[string, api.ResourceThresholds]{}
+	limits := Limits[string, api.ResourceThresholds]{}
+	for len(reader.data) > 0 {
+		node := fuzzNodes[int(reader.byte())%len(fuzzNodes)]
+		values[node] = reader.thresholds()
+		limits[node] = nil
+		for i := int(reader.byte()) % 4; i > 0; i-- {
+			limits[node] = append(limits[node], reader.thresholds())
+		}
+	}
+	return values, limits
+}
+
+func addFuzzSeeds(f *testing.F) {
+	seed := func(node, mask byte, usage float64, limits ...float64) []byte {
+		data := []byte{node, mask}
+		data = binary.LittleEndian.AppendUint64(data, math.Float64bits(usage))
+		data = append(data, byte(len(limits)))
+		for _, limit := range limits {
+			data = append(data, mask)
+			data = binary.LittleEndian.AppendUint64(data, math.Float64bits(limit))
+		}
+		return data
+	}
+	f.Add([]byte{})
+	f.Add(append(seed(0, 1, 10, 20, 80), seed(1, 1, 90, 20, 80)...))
+	f.Add(append(seed(0, 1, math.NaN(), 20, 80), seed(1, 2, 50, 20)...))
+	f.Add(append(seed(0, 1, math.Inf(1), math.Inf(-1), 80), seed(1, 4, -5)...))
+	f.Add(seed(2, 3, 50, 20, 80, 90))
+}
+
+// belowLimits and aboveLimits are the classifiers used by the nodeutilization
+// plugins, NaN is ordered before any other value.
+var (
+	belowLimits = ForMap[string, v1.ResourceName, api.Percentage, api.ResourceThresholds](
+		func(usage, limit api.Percentage) int { return cmp.Compare(usage, limit) },
+	)
+	aboveLimits = ForMap[string, v1.ResourceName, api.Percentage, api.ResourceThresholds](
+		func(usage, limit api.Percentage) int { return cmp.Compare(limit, usage) },
+	)
+)
+
+func FuzzClassify(f *testing.F) {
+	addFuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		values, limits := fuzzInput(data)
+		classifiers := []Classifier[string, api.ResourceThresholds]{belowLimits, aboveLimits}
+
+		result := Classify(values, limits, classifiers...)
+		if len(result) != len(classifiers) {
+			t.Fatalf("expected %v classes, got %v", len(classifiers), len(result))
+		}
+
+		for node, usage := range values {
+			// the expected class is the first classifier accepting the
+			// node, classifiers without a limit are skipped.
+			expected := -1
+			for i, limit := range limits[node] {
+				if i < len(classifiers) && classifiers[i](node, usage, limit) {
+					expected = i
+					break
+				}
+			}
+
+			found := -1
+			for i, class := range result {
+				if _, ok := class[node]; !ok {
+					continue
+				}
+				if found >= 0 {
+					t.Fatalf("node %v classified in classes %v and %v", node, found, i)
+				}
+				found = i
+			}
+			if found != expected {
+				t.Errorf("node %v: expected class %v, got %v", node, expected, found)
+			}
+		}
+
+		for i, class := range result {
+			for node := range class {
+				if _, ok := values[node]; !ok {
+					t.Errorf("unknown node %v in class %v", node, i)
+				}
+			}
+		}
+	})
+}
+
+func FuzzForMap(f *testing.F) {
+	addFuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		reader := &fuzzReader{data: data}
+		usage, limit := reader.thresholds(), reader.thresholds()
+
+		// resources without a limit are not compared, a node without
+		// any limit in common is always accepted.
+		expected := true
+		for name, value := range usage {
+			if threshold, ok := limit[name]; ok && cmp.Compare(value, threshold) >= 0 {
+				expected = false
+			}
+		}
+		if got := belowLimits("node1", usage, limit); got != expected {
+			t.Errorf("usage %v, limit %v: expected %v, got %v", usage, limit, expected, got)
+		}
+	})
+}
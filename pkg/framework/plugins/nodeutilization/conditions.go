@@ -0,0 +1,77 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+
+	nodeutil "sigs.k8s.io/descheduler/pkg/descheduler/node"
+)
+
+// nodeConditionAction returns the action for the node given its conditions.
+// if the node matches multiple rules drain takes precedence over avoid. an
+// empty action is returned if the node does not match any rule.
+func nodeConditionAction(node *v1.Node, rules []NodeConditionRule) NodeConditionAction {
+	var action NodeConditionAction
+	for _, rule := range rules {
+		status := rule.Status
+		if status == "" {
+			status = v1.ConditionTrue
+		}
+		for _, condition := range node.Status.Conditions {
+			if condition.Type != rule.Type || condition.Status != status {
+				continue
+			}
+			if rule.Action == NodeConditionActionDrain {
+				return NodeConditionActionDrain
+			}
+			action = rule.Action
+		}
+	}
+	return action
+}
+
+// isNodeToDrain returns true if the node must be drained, either because it
+// is being terminated by its cloud provider or given its conditions.
+func isNodeToDrain(node *v1.Node, rules []NodeConditionRule) bool {
+	if nodeutil.IsNodeBeingTerminated(node) {
+		return true
+	}
+	return nodeConditionAction(node, rules) == NodeConditionActionDrain
+}
+
+// anyNodeToDrain returns true if any of the nodes must be drained given
+// their conditions.
+func anyNodeToDrain(nodes []*v1.Node, rules []NodeConditionRule) bool {
+	for _, node := range nodes {
+		if isNodeToDrain(node, rules) {
+			return true
+		}
+	}
+	return false
+}
+
+// preferNodesToDrain moves the nodes to be drained to the beginning of the
+// list. the relative order of the nodes is kept.
+func preferNodesToDrain(nodes []NodeInfo, rules []NodeConditionRule) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return isNodeToDrain(nodes[i].node, rules) &&
+			!isNodeToDrain(nodes[j].node, rules)
+	})
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+
+	"sigs.k8s.io/descheduler/test"
+)
+
+func withNodeCondition(conditionType v1.NodeConditionType, status v1.ConditionStatus) func(*v1.Node) {
+	return func(node *v1.Node) {
+		node.Status.Conditions = append(
+			node.Status.Conditions,
+			v1.NodeCondition{Type: conditionType, Status: status},
+		)
+	}
+}
+
+func TestNodeConditionAction(t *testing.T) {
+	rules := []NodeConditionRule{
+		{Type: "FrequentKubeletRestart", Action: NodeConditionActionAvoid},
+		{Type: "KernelDeadlock", Action: NodeConditionActionDrain},
+		{Type: "NetworkReachable", Status: v1.ConditionFalse, Action: NodeConditionActionDrain},
+	}
+
+	for _, tc := range []struct {
+		name     string
+		node     *v1.Node
+		expected NodeConditionAction
+	}{
+		{
+			name:     "node without conditions",
+			node:     test.BuildTestNode("n1", 1000, 1000, 10, nil),
+			expected: "",
+		},
+		{
+			name:     "node matching an avoid rule",
+			node:     test.BuildTestNode("n1", 1000, 1000, 10, withNodeCondition("FrequentKubeletRestart", v1.ConditionTrue)),
+			expected: NodeConditionActionAvoid,
+		},
+		{
+			name:     "node with condition in a different status",
+			node:     test.BuildTestNode("n1", 1000, 1000, 10, withNodeCondition("KernelDeadlock", v1.ConditionFalse)),
+			expected: "",
+		},
+		{
+			name:     "node matching a rule with an explicit status",
+			node:     test.BuildTestNode("n1", 1000, 1000, 10, withNodeCondition("NetworkReachable", v1.ConditionFalse)),
+			expected: NodeConditionActionDrain,
+		},
+		{
+			name: "drain takes precedence over avoid",
+			node: test.BuildTestNode("n1", 1000, 1000, 10, func(node *v1.Node) {
+				withNodeCondition("KernelDeadlock", v1.ConditionTrue)(node)
+				withNodeCondition("FrequentKubeletRestart", v1.ConditionTrue)(node)
+			}),
+			expected: NodeConditionActionDrain,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if action := nodeConditionAction(tc.node, rules); action != tc.expected {
+				t.Errorf("expected action %q, got %q", tc.expected, action)
+			}
+		})
+	}
+}
+
+func TestPreferNodesToDrain(t *testing.T) {
+	rules := []NodeConditionRule{{Type: "KernelDeadlock", Action: NodeConditionActionDrain}}
+	nodes := []NodeInfo{
+		{NodeUsage: NodeUsage{node: test.BuildTestNode("n1", 1000, 1000, 10, nil)}},
+		{NodeUsage: NodeUsage{node: test.BuildTestNode("n2", 1000, 1000, 10, withNodeCondition("KernelDeadlock", v1.ConditionTrue))}},
+		{NodeUsage: NodeUsage{node: test.BuildTestNode("n3", 1000, 1000, 10, nil)}},
+		{NodeUsage: NodeUsage{node: test.BuildTestNode("n4", 1000, 1000, 10, withNodeCondition("KernelDeadlock", v1.ConditionTrue))}},
+	}
+
+	preferNodesToDrain(nodes, rules)
+
+	expected := []string{"n2", "n4", "n1", "n3"}
+	for i, name := range expected {
+		if nodes[i].node.Name != name {
+			t.Errorf("expected node %s at position %d, got %s", name, i, nodes[i].node.Name)
+		}
+	}
+}
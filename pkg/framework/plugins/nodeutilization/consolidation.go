@@ -0,0 +1,387 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/descheduler/pkg/api"
+	"sigs.k8s.io/descheduler/pkg/descheduler/evictions"
+	nodeutil "sigs.k8s.io/descheduler/pkg/descheduler/node"
+	podutil "sigs.k8s.io/descheduler/pkg/descheduler/pod"
+	"sigs.k8s.io/descheduler/pkg/framework/plugins/nodeutilization/normalizer"
+	frameworktypes "sigs.k8s.io/descheduler/pkg/framework/types"
+	"sigs.k8s.io/descheduler/pkg/utils"
+)
+
+const NodeConsolidationPluginName = "NodeConsolidation"
+
+// this lines makes sure that NodeConsolidation implements the BalancePlugin
+// interface.
+var _ frameworktypes.BalancePlugin = &NodeConsolidation{}
+
+// NodeConsolidation evicts all the pods of the emptiest nodes when the rest
+// of the nodes can host them, so a node autoscaler can remove the drained
+// nodes. Contrary to HighNodeUtilization, the nodes to drain are chosen by
+// simulating the packing of their pods onto the other nodes, pod by pod.
+type NodeConsolidation struct {
+	handle        frameworktypes.Handle
+	args          *NodeConsolidationArgs
+	podFilter     func(pod *v1.Pod) bool
+	resourceNames []v1.ResourceName
+	usageClient   UsageClient
+}
+
+// NewNodeConsolidation builds plugin from its arguments while passing a
+// handle.
+func NewNodeConsolidation(
+	genericArgs runtime.Object, handle frameworktypes.Handle,
+) (frameworktypes.Plugin, error) {
+	args, ok := genericArgs.(*NodeConsolidationArgs)
+	if !ok {
+		return nil, fmt.Errorf(
+			"want args to be of type NodeConsolidationArgs, got %T",
+			genericArgs,
+		)
+	}
+
+	podFilter, err := podutil.
+		NewOptions().
+		WithFilter(handle.Evictor().Filter).
+		BuildFilterFunc()
+	if err != nil {
+		return nil, fmt.Errorf("error initializing pod filter function: %v", err)
+	}
+
+	// pods are packed by all the resources the target thresholds are
+	// provided for, on top of cpu, memory and pods.
+	resourceNames := uniquifyResourceNames(
+		append(
+			getResourceNames(args.TargetThresholds),
+			v1.ResourceCPU,
+			v1.ResourceMemory,
+			v1.ResourcePods,
+		),
+	)
+
+	var usageClient UsageClient
+	if args.MetricsUtilization != nil {
+		usageClient, err = usageClientForMetrics(args.MetricsUtilization, args.UsageCacheTTL.Duration, handle, resourceNames)
+	} else {
+		usageClient, err = sharedUsageClientFor(
+			handle,
+			usageClientKey(requestedUsageClientType, resourceNames),
+			args.UsageCacheTTL.Duration,
+			func() (UsageClient, error) {
+				return newRequestedUsageClient(
+					resourceNames,
+					handle.GetPodsAssignedToNodeFunc(),
+					frameworktypes.DeviceAccountingFor(handle),
+					nodeUsageSnapshotsFor(handle, resourceNames),
+				), nil
+			},
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &NodeConsolidation{
+		handle:        handle,
+		args:          args,
+		podFilter:     podFilter,
+		resourceNames: resourceNames,
+		usageClient:   usageClient,
+	}, nil
+}
+
+// Name retrieves the plugin name.
+func (n *NodeConsolidation) Name() string {
+	return NodeConsolidationPluginName
+}
+
+// Balance holds the main logic of the plugin. It simulates the packing of the
+// pods of the emptiest nodes onto the other nodes and evicts the pods of the
+// nodes that could be emptied that way.
+func (n *NodeConsolidation) Balance(ctx context.Context, nodes []*v1.Node) *frameworktypes.Status {
+	summary := newBalanceSummary(NodeConsolidationPluginName)
+	defer summary.log()
+	defer summary.annotateSpan(ctx)
+	defer summary.writeRunStatus(ctx, n.handle.ClientSet(), n.args.RunStatus)
+	summary.budgets = newDisruptionBudgets(n.handle.SharedInformerFactory())
+
+	evictor := evictorForMode(n.handle.Evictor(), n.handle.ClientSet(), n.args.Mode)
+	if n.args.DryRun {
+		summary.dryRun = newDryRunEvictor(evictor)
+		evictor = summary.dryRun
+	}
+
+	// the nodes marked for scale down in previous runs that are still
+	// around are used as any other node once their marks are removed.
+	if !frameworktypes.IsDryRun(evictor) {
+		nodes = clearScaleDownMarks(
+			ctx, n.handle.ClientSet(), NodeConsolidationPluginName, n.args.ScaleDownHintsTTL, nodes,
+			n.handle.GetPodsAssignedToNodeFunc(), n.podFilter,
+		)
+	}
+
+	// nodes that can not receive pods are neither drained nor used to
+	// host the pods of the drained nodes.
+	var schedulable []*v1.Node
+	for _, node := range nodes {
+		if nodeutil.IsNodeUnschedulable(node) || nodeutil.IsNodeMarkedForDeletion(node) || nodeutil.IsNodeCandidateForDeletion(node) {
+			klog.V(2).InfoS("Node is not schedulable, ignoring it", "node", klog.KObj(node))
+			continue
+		}
+		schedulable = append(schedulable, node)
+	}
+	if len(schedulable) < 2 {
+		klog.V(1).InfoS("Not enough schedulable nodes to consolidate, nothing to do here")
+		return nil
+	}
+
+	if err := n.usageClient.Sync(ctx, schedulable); err != nil {
+		summary.failed(err)
+		return &frameworktypes.Status{
+			Err: fmt.Errorf("error getting node usage: %v", err),
+		}
+	}
+
+	nodesMap, nodesUsageMap, podListMap := getNodeUsageSnapshot(schedulable, n.usageClient)
+	capacities := referencedResourceListForNodesCapacity(schedulable)
+	addDeviceCapacities(capacities, schedulable, frameworktypes.DeviceAccountingFor(n.handle))
+	usage := normalizer.Normalize(nodesUsageMap, capacities, ResourceUsageToResourceThreshold)
+	summary.assessed(usage, nil)
+
+	nodeInfos := make([]NodeInfo, 0, len(schedulable))
+	for _, name := range sortedNodeNames(nodesMap) {
+		nodeInfos = append(nodeInfos, NodeInfo{
+			NodeUsage: NodeUsage{
+				node:    nodesMap[name],
+				usage:   nodesUsageMap[name],
+				allPods: podListMap[name],
+			},
+			available: capNodeCapacitiesToThreshold(
+				capacities[name],
+				n.args.TargetThresholds,
+				n.resourceNames,
+			),
+		})
+	}
+
+	drained, kept := planConsolidation(
+		nodeInfos, usage, n.podFilter, n.usageClient.PodUsage, n.resourceNames, n.args.MaxNodesToDrain,
+	)
+	summary.underutilized, summary.overutilized = len(drained), len(kept)
+	if len(drained) == 0 {
+		klog.V(1).InfoS("No node can be drained onto the other nodes, nothing to do here")
+		return nil
+	}
+	for _, node := range drained {
+		summary.classified(node.node.Name, "drained")
+		klog.InfoS("Node can be drained onto the other nodes", "node", klog.KObj(node.node))
+	}
+
+	// the drained nodes are emptied, the evictions only stop once the
+	// nodes left can not take any more pods.
+	continueEvictionCond := func(_ NodeInfo, avail api.ReferencedResourceList) bool {
+		for name := range avail {
+			if avail[name].CmpInt64(0) < 1 {
+				return false
+			}
+		}
+		return true
+	}
+
+	opts := &evictionOptions{
+		evictableNamespaces: n.args.EvictableNamespaces,
+		podEvictor:          evictor,
+		evictOptions:        evictions.EvictOptions{StrategyName: NodeConsolidationPluginName},
+		podFilter:           n.podFilter,
+		resourceNames:       n.resourceNames,
+		continueEviction:    continueEvictionCond,
+		usageClient:         n.usageClient,
+		limits:              n.args.EvictionLimits,
+		scoringStrategy:     &ScoringStrategy{Type: MostAllocated},
+		nodeIndexer:         n.handle.GetPodsAssignedToNodeFunc(),
+		devices:             frameworktypes.DeviceAccountingFor(n.handle),
+	}
+	evictPodsFromSourceNodes(ctx, drained, kept, opts, summary)
+
+	if n.args.DryRun {
+		summary.projected(drained, capacities)
+	}
+
+	// annotated pods are still running, their nodes are not drained.
+	if !frameworktypes.IsDryRun(evictor) && n.args.Mode != BalanceModeAnnotate {
+		markDrainedNodes(
+			ctx, n.handle.ClientSet(), NodeConsolidationPluginName, n.args.ScaleDownHints, drained, n.podFilter, summary,
+		)
+	}
+
+	// other plugins sharing the usage client must not rely on the usage
+	// collected before the evictions.
+	if summary.evicted > 0 {
+		invalidateUsageClient(n.usageClient)
+	}
+	return nil
+}
+
+// planConsolidation simulates, from the emptiest node on, the packing of the
+// pods of every node onto the other nodes, the biggest pods first, each onto
+// the most allocated node it fits on as far as its node selector, its
+// tolerations and the available resources of the nodes are concerned. nodes
+// whose pods can all be packed are drained, the pods they host are then
+// accounted in the usage of the nodes they were packed onto, which are not
+// drained anymore. it returns the nodes to drain, at most maxNodes, and the
+// nodes left.
+func planConsolidation(
+	nodes []NodeInfo,
+	usage map[string]api.ResourceThresholds,
+	podFilter func(pod *v1.Pod) bool,
+	podUsage PodUsageFunc,
+	resourceNames []v1.ResourceName,
+	maxNodes int,
+) ([]NodeInfo, []NodeInfo) {
+	candidates := make([]NodeInfo, len(nodes))
+	copy(candidates, nodes)
+	sortNodesByScore(candidates, true, func(node NodeInfo) float64 {
+		var score float64
+		for _, value := range usage[node.node.Name] {
+			score += float64(value)
+		}
+		return score
+	})
+
+	var allNodes []*v1.Node
+	for _, node := range nodes {
+		allNodes = append(allNodes, node.node)
+	}
+	images := nodeutil.NewImagePlatforms(allNodes)
+
+	// hosts holds the nodes left with the pods packed onto them so far.
+	hosts := make([]NodeInfo, len(nodes))
+	copy(hosts, nodes)
+	drained := map[string]bool{}
+	// hosting holds the nodes the pods of the drained nodes were packed
+	// onto, draining them would move the pods once more.
+	hosting := map[string]bool{}
+	for _, candidate := range candidates {
+		if len(drained) >= maxNodes {
+			break
+		}
+		if hosting[candidate.node.Name] {
+			continue
+		}
+
+		pods, ok := podsToPack(candidate, podFilter, podUsage)
+		if !ok {
+			continue
+		}
+
+		var others []NodeInfo
+		for _, host := range hosts {
+			if host.node.Name != candidate.node.Name {
+				others = append(others, host)
+			}
+		}
+		ranker := newDestinationRanker(&ScoringStrategy{Type: MostAllocated}, others, resourceNames, images)
+		source := nodeutil.NodePlatform(candidate.node)
+		packed := true
+		for _, pod := range pods {
+			destination := ranker.pick(pod.pod, pod.usage, source)
+			if destination == nil {
+				klog.V(3).InfoS(
+					"Node can not be drained, pod does not fit on any other node",
+					"node", klog.KObj(candidate.node),
+					"pod", klog.KObj(pod.pod),
+				)
+				packed = false
+				break
+			}
+			ranker.assign(pod.pod, destination, pod.usage)
+		}
+		if !packed {
+			continue
+		}
+
+		drained[candidate.node.Name] = true
+		for _, placement := range ranker.placements {
+			hosting[placement.node] = true
+		}
+		hosts = ranker.destinations
+	}
+
+	var drainedNodes, keptNodes []NodeInfo
+	for _, node := range nodes {
+		if drained[node.node.Name] {
+			drainedNodes = append(drainedNodes, node)
+			continue
+		}
+		keptNodes = append(keptNodes, node)
+	}
+	return drainedNodes, keptNodes
+}
+
+// packedPod is a pod to pack onto another node, together with its usage.
+type packedPod struct {
+	pod   *v1.Pod
+	usage api.ReferencedResourceList
+}
+
+// podsToPack returns the pods of the node to pack onto the other nodes, the
+// biggest first. DaemonSet and static pods stay with the node and are left
+// out. false is returned if any other pod can not be evicted, the node can
+// not be emptied then.
+func podsToPack(node NodeInfo, podFilter func(pod *v1.Pod) bool, podUsage PodUsageFunc) ([]packedPod, bool) {
+	var pods []packedPod
+	for _, pod := range node.allPods {
+		if utils.IsDaemonsetPod(pod.OwnerReferences) || utils.IsMirrorPod(pod) || utils.IsStaticPod(pod) {
+			continue
+		}
+		if !podFilter(pod) {
+			klog.V(3).InfoS(
+				"Node can not be drained, pod can not be evicted",
+				"node", klog.KObj(node.node),
+				"pod", klog.KObj(pod),
+			)
+			return nil, false
+		}
+		usage, err := podUsage(pod)
+		if err != nil {
+			klog.V(3).InfoS(
+				"Node can not be drained, unable to get the usage of pod",
+				"node", klog.KObj(node.node),
+				"pod", klog.KObj(pod),
+				"err", err,
+			)
+			return nil, false
+		}
+		pods = append(pods, packedPod{pod: pod, usage: usage})
+	}
+
+	sort.SliceStable(pods, func(i, j int) bool {
+		return nodeUsageScore(pods[i].usage, nil) > nodeUsageScore(pods[j].usage, nil)
+	})
+	return pods, true
+}
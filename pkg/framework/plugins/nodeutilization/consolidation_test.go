@@ -0,0 +1,157 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	policy "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	core "k8s.io/client-go/testing"
+
+	"sigs.k8s.io/descheduler/pkg/api"
+	"sigs.k8s.io/descheduler/pkg/framework/plugins/defaultevictor"
+	frameworktesting "sigs.k8s.io/descheduler/pkg/framework/testing"
+	frameworktypes "sigs.k8s.io/descheduler/pkg/framework/types"
+	"sigs.k8s.io/descheduler/test"
+)
+
+func TestNodeConsolidation(t *testing.T) {
+	n1 := test.BuildTestNode("n1", 4000, 3000, 10, nil)
+	n2 := test.BuildTestNode("n2", 4000, 3000, 10, nil)
+	n3 := test.BuildTestNode("n3", 4000, 3000, 10, nil)
+	unschedulable := test.BuildTestNode("n3", 4000, 3000, 10, func(node *v1.Node) {
+		node.Spec.Unschedulable = true
+	})
+
+	// n1 runs 3000m, n2 2000m and n3 500m out of 4000m.
+	basePods := func(n3Pod func(*v1.Pod)) []*v1.Pod {
+		return []*v1.Pod{
+			test.BuildTestPod("p1", 1000, 0, n1.Name, test.SetRSOwnerRef),
+			test.BuildTestPod("p2", 1000, 0, n1.Name, test.SetRSOwnerRef),
+			test.BuildTestPod("p3", 1000, 0, n1.Name, test.SetRSOwnerRef),
+			test.BuildTestPod("p4", 1000, 0, n2.Name, test.SetRSOwnerRef),
+			test.BuildTestPod("p5", 1000, 0, n2.Name, test.SetRSOwnerRef),
+			test.BuildTestPod("p6", 500, 0, n3.Name, n3Pod),
+			test.BuildTestPod("ds", 100, 0, n3.Name, test.SetDSOwnerRef),
+		}
+	}
+
+	for _, tc := range []struct {
+		name            string
+		nodes           []*v1.Node
+		pods            []*v1.Pod
+		maxNodesToDrain int
+		dryRun          bool
+		expectedEvicted []string
+	}{
+		{
+			name:            "emptiest node drained",
+			nodes:           []*v1.Node{n1, n2, n3},
+			pods:            basePods(test.SetRSOwnerRef),
+			maxNodesToDrain: 1,
+			expectedEvicted: []string{"p6"},
+		},
+		{
+			name:            "nodes hosting the pods of a drained node kept",
+			nodes:           []*v1.Node{n1, n2, n3},
+			pods:            basePods(test.SetRSOwnerRef),
+			maxNodesToDrain: 2,
+			expectedEvicted: []string{"p6"},
+		},
+		{
+			name:            "node with a pod that can not be evicted kept",
+			nodes:           []*v1.Node{n1, n2, n3},
+			pods:            basePods(nil),
+			maxNodesToDrain: 1,
+			expectedEvicted: []string{"p4", "p5"},
+		},
+		{
+			name:            "unschedulable nodes ignored",
+			nodes:           []*v1.Node{n1, n2, unschedulable},
+			pods:            basePods(test.SetRSOwnerRef),
+			maxNodesToDrain: 1,
+		},
+		{
+			name:            "dry run",
+			nodes:           []*v1.Node{n1, n2, n3},
+			pods:            basePods(test.SetRSOwnerRef),
+			maxNodesToDrain: 1,
+			dryRun:          true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			var objs []runtime.Object
+			for _, node := range tc.nodes {
+				objs = append(objs, node)
+			}
+			for _, pod := range tc.pods {
+				objs = append(objs, pod)
+			}
+			fakeClient := fake.NewSimpleClientset(objs...)
+
+			var evicted []string
+			fakeClient.PrependReactor("create", "pods", func(action core.Action) (bool, runtime.Object, error) {
+				obj := action.(core.CreateAction).GetObject()
+				if eviction, ok := obj.(*policy.Eviction); ok {
+					evicted = append(evicted, eviction.Name)
+				}
+				return true, obj, nil
+			})
+
+			handle, _, err := frameworktesting.InitFrameworkHandle(
+				ctx,
+				fakeClient,
+				nil,
+				defaultevictor.DefaultEvictorArgs{},
+				func(pods []*v1.Pod) {
+					sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+				},
+			)
+			if err != nil {
+				t.Fatalf("Unable to initialize a framework handle: %v", err)
+			}
+
+			plugin, err := NewNodeConsolidation(&NodeConsolidationArgs{
+				TargetThresholds: api.ResourceThresholds{v1.ResourceCPU: 80},
+				MaxNodesToDrain:  tc.maxNodesToDrain,
+				DryRun:           tc.dryRun,
+			}, handle)
+			if err != nil {
+				t.Fatalf("Unable to initialize the plugin: %v", err)
+			}
+
+			status := plugin.(frameworktypes.BalancePlugin).Balance(ctx, tc.nodes)
+			if status != nil && status.Err != nil {
+				t.Fatalf("Unexpected error: %v", status.Err)
+			}
+
+			sort.Strings(evicted)
+			if fmt.Sprint(evicted) != fmt.Sprint(tc.expectedEvicted) {
+				t.Errorf("Expected pods %v to be evicted, got %v instead", tc.expectedEvicted, evicted)
+			}
+		})
+	}
+}
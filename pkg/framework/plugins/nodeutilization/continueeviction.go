@@ -0,0 +1,129 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"sigs.k8s.io/descheduler/pkg/api"
+)
+
+// allOf returns a condition holding when all the provided ones do.
+func allOf(conds ...continueEvictionCond) continueEvictionCond {
+	return func(nodeInfo NodeInfo, available api.ReferencedResourceList) bool {
+		for _, cond := range conds {
+			if !cond(nodeInfo, available) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// anyOf returns a condition holding when any of the provided ones does.
+func anyOf(conds ...continueEvictionCond) continueEvictionCond {
+	return func(nodeInfo NodeInfo, available api.ReferencedResourceList) bool {
+		for _, cond := range conds {
+			if cond(nodeInfo, available) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// destinationsHaveRoom holds while the destination nodes can accept more of
+// every resource.
+func destinationsHaveRoom(_ NodeInfo, available api.ReferencedResourceList) bool {
+	for name := range available {
+		if available[name].CmpInt64(0) < 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// aboveTarget holds while the node usage is above its target thresholds, the
+// available resources of the node are capped to them.
+func aboveTarget(nodeInfo NodeInfo, _ api.ReferencedResourceList) bool {
+	return isNodeAboveTargetUtilization(nodeInfo.NodeUsage, nodeInfo.available)
+}
+
+// aboveLimits holds while the node usage is above the limits of the node.
+// nodes without limits are never above them.
+func aboveLimits(limits map[string]api.ReferencedResourceList) continueEvictionCond {
+	return func(nodeInfo NodeInfo, _ api.ReferencedResourceList) bool {
+		limit, ok := limits[nodeInfo.node.Name]
+		return ok && isNodeAboveTargetUtilization(nodeInfo.NodeUsage, limit)
+	}
+}
+
+// belowPodCount holds while fewer than count pods were evicted from the node
+// during the invocation.
+func belowPodCount(count uint, summary *balanceSummary) continueEvictionCond {
+	return func(nodeInfo NodeInfo, _ api.ReferencedResourceList) bool {
+		return uint(summary.evictedPerNode[nodeInfo.node.Name]) < count
+	}
+}
+
+// belowResourceAmount holds while the pods evicted from the node, i.e. the
+// drop of the node usage since initialUsage, use less than amount of every
+// resource of amount.
+func belowResourceAmount(amount api.ReferencedResourceList, initialUsage map[string]api.ReferencedResourceList) continueEvictionCond {
+	return func(nodeInfo NodeInfo, _ api.ReferencedResourceList) bool {
+		initial := initialUsage[nodeInfo.node.Name]
+		for name, limit := range amount {
+			before, after := initial[name], nodeInfo.usage[name]
+			if before == nil || after == nil {
+				continue
+			}
+			evicted := before.DeepCopy()
+			evicted.Sub(*after)
+			if evicted.Cmp(*limit) >= 0 {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// continueEvictionStrategyCond returns the condition of the strategy pods
+// are evicted from the overutilized nodes under. lowLimits are the node
+// capacities capped to their low thresholds and initialUsage the usage of
+// the nodes prior to any eviction.
+func continueEvictionStrategyCond(
+	strategy *ContinueEvictionStrategy,
+	lowLimits map[string]api.ReferencedResourceList,
+	initialUsage map[string]api.ReferencedResourceList,
+	summary *balanceSummary,
+) continueEvictionCond {
+	if strategy == nil {
+		return aboveTarget
+	}
+	switch strategy.Type {
+	case UntilBelowLow:
+		return aboveLimits(lowLimits)
+	case FixedPodCount:
+		return belowPodCount(strategy.PodCount, summary)
+	case FixedResourceAmount:
+		amount := api.ReferencedResourceList{}
+		for name, quantity := range strategy.ResourceAmount {
+			amount[name] = &quantity
+		}
+		return belowResourceAmount(amount, initialUsage)
+	default:
+		return aboveTarget
+	}
+}
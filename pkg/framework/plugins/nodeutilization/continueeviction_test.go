@@ -0,0 +1,117 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/descheduler/pkg/api"
+	"sigs.k8s.io/descheduler/pkg/framework/plugins/defaultevictor"
+	frameworktesting "sigs.k8s.io/descheduler/pkg/framework/testing"
+	frameworktypes "sigs.k8s.io/descheduler/pkg/framework/types"
+	"sigs.k8s.io/descheduler/test"
+)
+
+func TestLowNodeUtilizationContinueEvictionStrategy(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		strategy *ContinueEvictionStrategy
+		expected uint
+	}{
+		{
+			name:     "no strategy",
+			expected: 2,
+		},
+		{
+			name:     "until below target",
+			strategy: &ContinueEvictionStrategy{Type: UntilBelowTarget},
+			expected: 2,
+		},
+		{
+			name:     "until below low",
+			strategy: &ContinueEvictionStrategy{Type: UntilBelowLow},
+			expected: 3,
+		},
+		{
+			name:     "fixed pod count",
+			strategy: &ContinueEvictionStrategy{Type: FixedPodCount, PodCount: 1},
+			expected: 1,
+		},
+		{
+			name: "fixed resource amount",
+			strategy: &ContinueEvictionStrategy{
+				Type:           FixedResourceAmount,
+				ResourceAmount: v1.ResourceList{v1.ResourceCPU: resource.MustParse("2")},
+			},
+			expected: 3,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			// n1 runs at 80% of its cpu, n2 and n3 have room for
+			// all of its pods but one.
+			nodes := []*v1.Node{
+				test.BuildTestNode("n1", 4000, 3000, 10, nil),
+				test.BuildTestNode("n2", 4000, 3000, 10, nil),
+				test.BuildTestNode("n3", 4000, 3000, 10, nil),
+			}
+			objs := []runtime.Object{nodes[0], nodes[1], nodes[2]}
+			for i := 0; i < 4; i++ {
+				objs = append(objs, test.BuildTestPod(fmt.Sprintf("n1-p%d", i), 800, 0, "n1", test.SetRSOwnerRef))
+			}
+			objs = append(objs, test.BuildTestPod("n2-p0", 400, 0, "n2", test.SetRSOwnerRef))
+
+			handle, podEvictor, err := frameworktesting.InitFrameworkHandle(
+				ctx,
+				fake.NewSimpleClientset(objs...),
+				nil,
+				defaultevictor.DefaultEvictorArgs{},
+				func(pods []*v1.Pod) {
+					sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+				},
+			)
+			if err != nil {
+				t.Fatalf("Unable to initialize a framework handle: %v", err)
+			}
+
+			plugin, err := NewLowNodeUtilization(&LowNodeUtilizationArgs{
+				Thresholds:               api.ResourceThresholds{v1.ResourceCPU: 30},
+				TargetThresholds:         api.ResourceThresholds{v1.ResourceCPU: 50},
+				ContinueEvictionStrategy: tc.strategy,
+			}, handle)
+			if err != nil {
+				t.Fatalf("Unable to initialize the plugin: %v", err)
+			}
+
+			if status := plugin.(frameworktypes.BalancePlugin).Balance(ctx, nodes); status != nil && status.Err != nil {
+				t.Fatalf("Unexpected error: %v", status.Err)
+			}
+			if evicted := podEvictor.TotalEvicted(); evicted != tc.expected {
+				t.Errorf("Expected %v pods to be evicted, got %v", tc.expected, evicted)
+			}
+		})
+	}
+}
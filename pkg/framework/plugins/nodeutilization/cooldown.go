@@ -0,0 +1,151 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/lru"
+
+	frameworktypes "sigs.k8s.io/descheduler/pkg/framework/types"
+)
+
+// LastEvictionAnnotationKey is set on the nodes pods were evicted from when
+// the cooldown is annotated. Its value is the time of the last eviction in
+// the RFC 3339 format.
+const LastEvictionAnnotationKey = "descheduler.alpha.kubernetes.io/last-eviction"
+
+// lastEvictionsCacheSize is the maximum number of nodes whose last eviction
+// is kept in memory.
+const lastEvictionsCacheSize = 4096
+
+// lastEvictionsFor returns the time pods were last evicted from every node by
+// the plugins of the profile of the handle, indexed by node.
+func lastEvictionsFor(handle frameworktypes.Handle) *lru.Cache {
+	return sharedLRU(
+		handle,
+		fmt.Sprintf("nodeutilization/lastevictions/%s", frameworktypes.ProfileNameFor(handle)),
+		lastEvictionsCacheSize,
+	)
+}
+
+// lastEviction returns the time pods were last evicted from the node, the
+// latest of the one kept in memory and the one found in the node annotation.
+func lastEviction(lastEvictions *lru.Cache, node *v1.Node) (time.Time, bool) {
+	var last time.Time
+	if value, ok := lastEvictions.Get(node.Name); ok {
+		last = value.(time.Time)
+	}
+	if value, ok := node.Annotations[LastEvictionAnnotationKey]; ok {
+		annotated, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			klog.V(2).InfoS(
+				"Unable to parse the last eviction annotation of the node",
+				"node", klog.KObj(node),
+				"value", value,
+			)
+		} else if annotated.After(last) {
+			last = annotated
+		}
+	}
+	return last, !last.IsZero()
+}
+
+// nodesOutOfCooldown returns the nodes no pod was evicted from during the
+// cooldown duration. all nodes are returned if no cooldown is configured.
+func nodesOutOfCooldown(lastEvictions *lru.Cache, nodes []NodeInfo, cooldown *Cooldown, now time.Time) []NodeInfo {
+	if cooldown == nil {
+		return nodes
+	}
+
+	result := make([]NodeInfo, 0, len(nodes))
+	for _, node := range nodes {
+		last, ok := lastEviction(lastEvictions, node.node)
+		if ok && now.Sub(last) < cooldown.Duration.Duration {
+			klog.V(2).InfoS(
+				"Pods were recently evicted from the node, thus skipped",
+				"node", klog.KObj(node.node),
+				"lastEviction", last,
+				"cooldown", cooldown.Duration.Duration,
+			)
+			continue
+		}
+		result = append(result, node)
+	}
+	return result
+}
+
+// recordLastEvictions records the time pods were evicted from the nodes of
+// the summary. when requested the nodes are annotated too, annotations are
+// best effort, failures are logged and do not interrupt the process.
+func recordLastEvictions(
+	ctx context.Context,
+	client clientset.Interface,
+	lastEvictions *lru.Cache,
+	cooldown *Cooldown,
+	summary *balanceSummary,
+	now time.Time,
+) {
+	if cooldown == nil {
+		return
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(summary.evictedPerNode)) {
+		if summary.evictedPerNode[name] == 0 {
+			continue
+		}
+		lastEvictions.Add(name, now)
+		if !cooldown.Annotate {
+			continue
+		}
+		if err := patchLastEviction(ctx, client, name, now); err != nil {
+			klog.ErrorS(
+				err, "unable to annotate the node with its last eviction",
+				"node", klog.KRef("", name),
+			)
+		}
+	}
+}
+
+// patchLastEviction sets the last eviction annotation on the node.
+func patchLastEviction(ctx context.Context, client clientset.Interface, name string, now time.Time) error {
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"annotations": map[string]string{
+				LastEvictionAnnotationKey: now.UTC().Format(time.RFC3339),
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = client.CoreV1().Nodes().Patch(
+		ctx, name, types.MergePatchType, patch, metav1.PatchOptions{},
+	)
+	return err
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/lru"
+
+	"sigs.k8s.io/descheduler/pkg/api"
+	"sigs.k8s.io/descheduler/pkg/framework/plugins/defaultevictor"
+	frameworktesting "sigs.k8s.io/descheduler/pkg/framework/testing"
+	frameworktypes "sigs.k8s.io/descheduler/pkg/framework/types"
+	"sigs.k8s.io/descheduler/test"
+)
+
+func withLastEviction(last time.Time) func(*v1.Node) {
+	return func(node *v1.Node) {
+		node.Annotations = map[string]string{
+			LastEvictionAnnotationKey: last.Format(time.RFC3339),
+		}
+	}
+}
+
+func TestNodesOutOfCooldown(t *testing.T) {
+	now := time.Now()
+	lastEvictions := lru.New(lastEvictionsCacheSize)
+	lastEvictions.Add("n1", now.Add(-time.Minute))
+	lastEvictions.Add("n2", now.Add(-time.Hour))
+	// the annotation is more recent than the eviction kept in memory.
+	lastEvictions.Add("n4", now.Add(-time.Hour))
+
+	var nodes []NodeInfo
+	for _, node := range []*v1.Node{
+		test.BuildTestNode("n1", 1000, 1000, 10, nil),
+		test.BuildTestNode("n2", 1000, 1000, 10, nil),
+		test.BuildTestNode("n3", 1000, 1000, 10, withLastEviction(now.Add(-time.Minute))),
+		test.BuildTestNode("n4", 1000, 1000, 10, withLastEviction(now.Add(-time.Minute))),
+		test.BuildTestNode("n5", 1000, 1000, 10, nil),
+	} {
+		nodes = append(nodes, NodeInfo{NodeUsage: NodeUsage{node: node}})
+	}
+
+	for _, tc := range []struct {
+		name     string
+		cooldown *Cooldown
+		expected []string
+	}{
+		{
+			name:     "no cooldown",
+			expected: []string{"n1", "n2", "n3", "n4", "n5"},
+		},
+		{
+			name:     "nodes cooling down are skipped",
+			cooldown: &Cooldown{Duration: metav1.Duration{Duration: 10 * time.Minute}},
+			expected: []string{"n2", "n5"},
+		},
+		{
+			name:     "cooldowns expire",
+			cooldown: &Cooldown{Duration: metav1.Duration{Duration: 30 * time.Second}},
+			expected: []string{"n1", "n2", "n3", "n4", "n5"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var names []string
+			for _, node := range nodesOutOfCooldown(lastEvictions, nodes, tc.cooldown, now) {
+				names = append(names, node.node.Name)
+			}
+			if !slices.Equal(names, tc.expected) {
+				t.Errorf("Expected %v, got %v", tc.expected, names)
+			}
+		})
+	}
+}
+
+func TestLowNodeUtilizationCooldown(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		cooldown *Cooldown
+		expected uint
+	}{
+		{
+			name:     "no cooldown",
+			expected: 2,
+		},
+		{
+			name:     "node cooling down",
+			cooldown: &Cooldown{Duration: metav1.Duration{Duration: time.Hour}},
+			expected: 0,
+		},
+		{
+			name:     "node cooling down and annotated",
+			cooldown: &Cooldown{Duration: metav1.Duration{Duration: time.Hour}, Annotate: true},
+			expected: 0,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			nodes := []*v1.Node{
+				test.BuildTestNode("n1", 4000, 3000, 10, nil),
+				test.BuildTestNode("n2", 4000, 3000, 10, nil),
+			}
+			objs := []runtime.Object{nodes[0], nodes[1]}
+			for i := 0; i < 4; i++ {
+				objs = append(objs, test.BuildTestPod(fmt.Sprintf("n1-p%d", i), 800, 0, "n1", test.SetRSOwnerRef))
+			}
+			objs = append(objs, test.BuildTestPod("n2-p0", 400, 0, "n2", test.SetRSOwnerRef))
+
+			client := fake.NewSimpleClientset(objs...)
+			handle, podEvictor, err := frameworktesting.InitFrameworkHandle(
+				ctx,
+				client,
+				nil,
+				defaultevictor.DefaultEvictorArgs{},
+				func(pods []*v1.Pod) {
+					sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+				},
+			)
+			if err != nil {
+				t.Fatalf("Unable to initialize a framework handle: %v", err)
+			}
+
+			plugin, err := NewLowNodeUtilization(&LowNodeUtilizationArgs{
+				Thresholds:       api.ResourceThresholds{v1.ResourceCPU: 30},
+				TargetThresholds: api.ResourceThresholds{v1.ResourceCPU: 50},
+				Cooldown:         tc.cooldown,
+			}, handle)
+			if err != nil {
+				t.Fatalf("Unable to initialize the plugin: %v", err)
+			}
+
+			// the first cycle evicts pods from n1, the second one runs
+			// with the same snapshot of the cluster.
+			for i, expected := range []uint{2, tc.expected} {
+				before := podEvictor.TotalEvicted()
+				if status := plugin.(frameworktypes.BalancePlugin).Balance(ctx, nodes); status != nil && status.Err != nil {
+					t.Fatalf("Unexpected error: %v", status.Err)
+				}
+				if evicted := podEvictor.TotalEvicted() - before; evicted != expected {
+					t.Errorf("Expected %v pods to be evicted in cycle %v, got %v", expected, i, evicted)
+				}
+			}
+
+			node, err := client.CoreV1().Nodes().Get(ctx, "n1", metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("Unable to get the node: %v", err)
+			}
+			_, annotated := node.Annotations[LastEvictionAnnotationKey]
+			if expected := tc.cooldown != nil && tc.cooldown.Annotate; annotated != expected {
+				t.Errorf("Expected the node to be annotated: %v, got %v", expected, annotated)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// NodeCostProvider returns the cost of running a node, e.g. its hourly
+// price. Costs are only compared with each other so any unit will do as long
+// as all the nodes share it. false is returned when the cost of the node is
+// not known, the node is then considered free.
+type NodeCostProvider interface {
+	NodeCost(node *v1.Node) (float64, bool)
+}
+
+var (
+	nodeCostProvidersLock sync.RWMutex
+	// nodeCostProviders are the providers registered by name.
+	nodeCostProviders = map[string]NodeCostProvider{}
+)
+
+// RegisterNodeCostProvider makes the provider available under the provided
+// name, replacing the provider previously registered under it, if any.
+func RegisterNodeCostProvider(name string, provider NodeCostProvider) {
+	nodeCostProvidersLock.Lock()
+	defer nodeCostProvidersLock.Unlock()
+	nodeCostProviders[name] = provider
+}
+
+// nodeCostProviderFor returns the provider reading the node costs where the
+// configuration points to. nil is returned when the cost of the nodes is to
+// be ignored.
+func nodeCostProviderFor(cost *NodeCost) (NodeCostProvider, error) {
+	if cost == nil {
+		return nil, nil
+	}
+	switch {
+	case cost.Label != "" && cost.Annotation == "" && cost.Provider == "":
+		return metadataNodeCost{key: cost.Label}, nil
+	case cost.Annotation != "" && cost.Label == "" && cost.Provider == "":
+		return metadataNodeCost{key: cost.Annotation, annotation: true}, nil
+	case cost.Provider != "" && cost.Label == "" && cost.Annotation == "":
+		nodeCostProvidersLock.RLock()
+		defer nodeCostProvidersLock.RUnlock()
+		provider, ok := nodeCostProviders[cost.Provider]
+		if !ok {
+			return nil, fmt.Errorf("unknown nodeCost provider %q", cost.Provider)
+		}
+		return provider, nil
+	}
+	return nil, fmt.Errorf("nodeCost requires exactly one of label, annotation or provider")
+}
+
+// nodeCost returns the cost of the node as told by the provider, zero if the
+// provider is nil or does not know the cost of the node.
+func nodeCost(provider NodeCostProvider, node *v1.Node) float64 {
+	if provider == nil {
+		return 0
+	}
+	cost, ok := provider.NodeCost(node)
+	if !ok {
+		return 0
+	}
+	return cost
+}
+
+// metadataNodeCost reads the cost of the nodes from one of their labels or
+// annotations, holding a decimal number.
+type metadataNodeCost struct {
+	key        string
+	annotation bool
+}
+
+func (m metadataNodeCost) NodeCost(node *v1.Node) (float64, bool) {
+	values := node.Labels
+	if m.annotation {
+		values = node.Annotations
+	}
+	value, ok := values[m.key]
+	if !ok {
+		return 0, false
+	}
+	cost, err := strconv.ParseFloat(value, 64)
+	if err != nil || cost < 0 {
+		klog.V(4).InfoS("Ignoring invalid node cost", "node", klog.KObj(node), "key", m.key, "value", value)
+		return 0, false
+	}
+	return cost, true
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"sigs.k8s.io/descheduler/pkg/api"
+	nodeutil "sigs.k8s.io/descheduler/pkg/descheduler/node"
+	frameworktesting "sigs.k8s.io/descheduler/pkg/framework/testing"
+	"sigs.k8s.io/descheduler/test"
+)
+
+type fakeNodeCostProvider map[string]float64
+
+func (f fakeNodeCostProvider) NodeCost(node *v1.Node) (float64, bool) {
+	cost, ok := f[node.Name]
+	return cost, ok
+}
+
+func TestNodeCostProviders(t *testing.T) {
+	RegisterNodeCostProvider("fake", fakeNodeCostProvider{"n1": 3})
+
+	node := test.BuildTestNode("n1", 4000, 3000, 10, func(node *v1.Node) {
+		node.Labels["price"] = "0.5"
+		node.Labels["invalid"] = "cheap"
+		node.Annotations = map[string]string{"price": "1.25"}
+	})
+
+	for _, tc := range []struct {
+		name     string
+		cost     *NodeCost
+		expected float64
+	}{
+		{
+			name: "costs ignored",
+		},
+		{
+			name:     "cost read from a label",
+			cost:     &NodeCost{Label: "price"},
+			expected: 0.5,
+		},
+		{
+			name:     "cost read from an annotation",
+			cost:     &NodeCost{Annotation: "price"},
+			expected: 1.25,
+		},
+		{
+			name: "invalid cost",
+			cost: &NodeCost{Label: "invalid"},
+		},
+		{
+			name: "missing cost",
+			cost: &NodeCost{Label: "missing"},
+		},
+		{
+			name:     "cost told by a registered provider",
+			cost:     &NodeCost{Provider: "fake"},
+			expected: 3,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			provider, err := nodeCostProviderFor(tc.cost)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cost := nodeCost(provider, node); cost != tc.expected {
+				t.Errorf("expected cost %v, got %v instead", tc.expected, cost)
+			}
+		})
+	}
+}
+
+func TestSortNodesByUsageCostTies(t *testing.T) {
+	usage := withUsage(frameworktesting.BuildNodeUsage().WithCPU("1"))
+	withCost := func(name string, cost float64) NodeInfo {
+		nodeInfo := *BuildTestNodeInfo(name, usage)
+		nodeInfo.cost = cost
+		return nodeInfo
+	}
+
+	for _, ascending := range []bool{true, false} {
+		nodeInfoList := []NodeInfo{
+			withCost("node1", 1),
+			withCost("node2", 0),
+			withCost("node3", 2),
+			withCost("node4", 1),
+		}
+		sortNodesByUsage(nodeInfoList, ascending, nil)
+
+		names := []string{}
+		for _, nodeInfo := range nodeInfoList {
+			names = append(names, nodeInfo.node.Name)
+		}
+		expected := []string{"node3", "node1", "node4", "node2"}
+		if !reflect.DeepEqual(names, expected) {
+			t.Errorf("ascending=%v: expected %v, got %v", ascending, expected, names)
+		}
+	}
+}
+
+func TestDestinationRankerCostTies(t *testing.T) {
+	resourceNames := []v1.ResourceName{v1.ResourceCPU}
+	destination := func(name string, cost float64) NodeInfo {
+		return NodeInfo{
+			NodeUsage: NodeUsage{
+				node:  test.BuildTestNode(name, 2000, 2000, 10, nil),
+				usage: api.ReferencedResourceList{v1.ResourceCPU: resource.NewMilliQuantity(0, resource.DecimalSI)},
+			},
+			available: api.ReferencedResourceList{v1.ResourceCPU: resource.NewMilliQuantity(1600, resource.DecimalSI)},
+			cost:      cost,
+		}
+	}
+	podUsage := api.ReferencedResourceList{v1.ResourceCPU: resource.NewMilliQuantity(400, resource.DecimalSI)}
+	pod := test.BuildTestPod("p1", 400, 0, "source", nil)
+
+	// the nodes score the same until a pod lands on the cheapest one.
+	destinations := []NodeInfo{destination("n1", 2), destination("n2", 1), destination("n3", 3)}
+	ranker := newDestinationRanker(&ScoringStrategy{Type: MostAllocated}, destinations, resourceNames, nil)
+	for i, expected := range []string{"n2", "n2", "n2", "n2", "n1"} {
+		picked := ranker.pick(pod, podUsage, nodeutil.Platform{})
+		if picked == nil || picked.node.Name != expected {
+			t.Fatalf("pod %d: expected node %q, got %v", i, expected, picked)
+		}
+		ranker.assign(pod, picked, podUsage)
+	}
+}
@@ -0,0 +1,276 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/descheduler/pkg/api"
+	nodeutil "sigs.k8s.io/descheduler/pkg/descheduler/node"
+)
+
+// DecisionRecord describes the eviction of a pod by a nodeutilization
+// plugin with enough context to tell, later on, why the pod was picked.
+// usages are the ones the plugin relied on when the pod was picked, the
+// percentages and thresholds are the ones the node was classified with.
+type DecisionRecord struct {
+	Time                time.Time                  `json:"time"`
+	Plugin              string                     `json:"plugin"`
+	Namespace           string                     `json:"namespace"`
+	Pod                 string                     `json:"pod"`
+	Node                string                     `json:"node"`
+	Reason              string                     `json:"reason"`
+	NodeUsage           api.ReferencedResourceList `json:"nodeUsage"`
+	NodeUsagePercentage api.ResourceThresholds     `json:"nodeUsagePercentage"`
+	Thresholds          []api.ResourceThresholds   `json:"thresholds"`
+	PodUsage            api.ReferencedResourceList `json:"podUsage,omitempty"`
+	Destination         string                     `json:"destination,omitempty"`
+	DryRun              bool                       `json:"dryRun"`
+}
+
+// DecisionSink receives a record for every pod evicted by the
+// nodeutilization plugins. sinks are called synchronously, during the
+// eviction process, and are expected to return quickly.
+type DecisionSink interface {
+	Record(record DecisionRecord) error
+}
+
+var (
+	decisionSinksLock sync.Mutex
+	// decisionSinks are the sinks registered through RegisterDecisionSink,
+	// all the plugins send their records to them.
+	decisionSinks []*registeredDecisionSink
+	// fileDecisionSinks are the file sinks indexed by path, plugins
+	// configured with the same path share the file.
+	fileDecisionSinks = map[string]*fileDecisionSink{}
+)
+
+// registeredDecisionSink wraps a registered sink so it can be told apart
+// from other registrations of the same sink when unregistered.
+type registeredDecisionSink struct {
+	DecisionSink
+}
+
+// RegisterDecisionSink registers a sink all the nodeutilization plugins
+// send their decision records to, e.g. when the descheduler is embedded in
+// another program. the returned function unregisters the sink.
+func RegisterDecisionSink(sink DecisionSink) func() {
+	decisionSinksLock.Lock()
+	defer decisionSinksLock.Unlock()
+	registered := &registeredDecisionSink{DecisionSink: sink}
+	decisionSinks = append(decisionSinks, registered)
+	return func() {
+		decisionSinksLock.Lock()
+		defer decisionSinksLock.Unlock()
+		decisionSinks = slices.DeleteFunc(decisionSinks, func(s *registeredDecisionSink) bool {
+			return s == registered
+		})
+	}
+}
+
+// decisionSinksFor returns the sinks a plugin sends its records to: the
+// log, the file configured in the decision log, if any, and the registered
+// sinks.
+func decisionSinksFor(decisionLog *DecisionLog) []DecisionSink {
+	decisionSinksLock.Lock()
+	defer decisionSinksLock.Unlock()
+
+	sinks := []DecisionSink{logDecisionSink{}}
+	if decisionLog != nil && decisionLog.Path != "" {
+		sink, ok := fileDecisionSinks[decisionLog.Path]
+		if !ok {
+			sink = &fileDecisionSink{path: decisionLog.Path}
+			fileDecisionSinks[decisionLog.Path] = sink
+		}
+		sinks = append(sinks, sink)
+	}
+	for _, sink := range decisionSinks {
+		sinks = append(sinks, sink)
+	}
+	return sinks
+}
+
+// logDecisionSink emits the records as structured log entries.
+type logDecisionSink struct{}
+
+func (logDecisionSink) Record(record DecisionRecord) error {
+	klog.V(1).InfoS(
+		"Eviction decision",
+		"plugin", record.Plugin,
+		"pod", klog.KRef(record.Namespace, record.Pod),
+		"node", record.Node,
+		"reason", record.Reason,
+		"nodeUsage", record.NodeUsage,
+		"nodeUsagePercentage", record.NodeUsagePercentage,
+		"thresholds", record.Thresholds,
+		"podUsage", record.PodUsage,
+		"destination", record.Destination,
+		"dryRun", record.DryRun,
+	)
+	return nil
+}
+
+// fileDecisionSink appends the records to a file, one JSON document per
+// line. the file is opened upon the first record and kept open.
+type fileDecisionSink struct {
+	path string
+	mu   sync.Mutex
+	file *os.File
+}
+
+func (s *fileDecisionSink) Record(record DecisionRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		file, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return fmt.Errorf("unable to open the decision log: %v", err)
+		}
+		s.file = file
+	}
+	_, err = s.file.Write(append(data, '\n'))
+	return err
+}
+
+// decisionReason tells why pods are evicted from the node.
+func decisionReason(node *v1.Node, category string) string {
+	switch {
+	case nodeutil.IsNodeMarkedForDeletion(node):
+		return "node marked for deletion by a node autoscaler"
+	case nodeutil.IsNodeBeingTerminated(node):
+		return "node being terminated"
+	default:
+		return fmt.Sprintf("node %s", category)
+	}
+}
+
+// eventDetails describes why the pod is evicted from the node, for the
+// eviction event of the pod: the plugin, the reason the node is drained, its
+// usage and thresholds as percentages of its capacity and, when known, the
+// destination node with the resources it has left once the pod is placed on
+// it.
+func (s *balanceSummary) eventDetails(
+	nodeInfo NodeInfo,
+	podUsage api.ReferencedResourceList,
+	destination *NodeInfo,
+) string {
+	node := nodeInfo.node.Name
+	details := []string{fmt.Sprintf("%s: %s", s.plugin, decisionReason(nodeInfo.node, s.categories[node]))}
+	if usage := s.usage[node]; len(usage) > 0 {
+		details = append(details, "usage "+formatPercentages(usage))
+	}
+	if thresholds := s.thresholds[node]; len(thresholds) > 0 {
+		formatted := make([]string, 0, len(thresholds))
+		for _, threshold := range thresholds {
+			formatted = append(formatted, formatPercentages(threshold))
+		}
+		details = append(details, "thresholds "+strings.Join(formatted, " / "))
+	}
+	if destination != nil {
+		details = append(details, fmt.Sprintf(
+			"expected on node %s with %s left",
+			destination.node.Name, formatHeadroom(destination, podUsage),
+		))
+	}
+	return strings.Join(details, ", ")
+}
+
+// formatPercentages formats the percentages by resource name, e.g.
+// "cpu=12.50% memory=40.00%".
+func formatPercentages(percentages api.ResourceThresholds) string {
+	formatted := make([]string, 0, len(percentages))
+	for _, name := range slices.Sorted(maps.Keys(percentages)) {
+		formatted = append(formatted, fmt.Sprintf("%s=%.2f%%", name, percentages[name]))
+	}
+	return strings.Join(formatted, " ")
+}
+
+// formatHeadroom formats the resources the destination node has left below
+// its threshold once the pod is placed on it, e.g. "cpu=1500m memory=2Gi".
+func formatHeadroom(destination *NodeInfo, podUsage api.ReferencedResourceList) string {
+	var formatted []string
+	for _, name := range slices.Sorted(maps.Keys(destination.available)) {
+		if destination.available[name] == nil || destination.usage[name] == nil {
+			continue
+		}
+		quantity := destination.available[name].DeepCopy()
+		quantity.Sub(*destination.usage[name])
+		if podUsage[name] != nil {
+			quantity.Sub(*podUsage[name])
+		}
+		if quantity.Sign() < 0 {
+			quantity.Set(0)
+		}
+		formatted = append(formatted, fmt.Sprintf("%s=%s", name, quantity.String()))
+	}
+	return strings.Join(formatted, " ")
+}
+
+// recordDecision sends the record of the pod evicted from the node to the
+// sinks of the summary. failing sinks do not prevent the eviction.
+func (s *balanceSummary) recordDecision(
+	pod *v1.Pod,
+	nodeInfo NodeInfo,
+	podUsage api.ReferencedResourceList,
+	destination *NodeInfo,
+	dryRun bool,
+) {
+	if len(s.sinks) == 0 {
+		return
+	}
+
+	node := nodeInfo.node.Name
+	record := DecisionRecord{
+		Time:                time.Now(),
+		Plugin:              s.plugin,
+		Namespace:           pod.Namespace,
+		Pod:                 pod.Name,
+		Node:                node,
+		Reason:              decisionReason(nodeInfo.node, s.categories[node]),
+		NodeUsage:           copyUsage(nodeInfo.usage),
+		NodeUsagePercentage: s.usage[node],
+		Thresholds:          s.thresholds[node],
+		DryRun:              dryRun,
+	}
+	if podUsage != nil {
+		record.PodUsage = copyUsage(podUsage)
+	}
+	if destination != nil {
+		record.Destination = destination.node.Name
+	}
+
+	for _, sink := range s.sinks {
+		if err := sink.Record(record); err != nil {
+			klog.ErrorS(err, "unable to record the eviction decision", "pod", klog.KObj(pod))
+		}
+	}
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/descheduler/pkg/api"
+	"sigs.k8s.io/descheduler/pkg/framework/plugins/defaultevictor"
+	frameworktesting "sigs.k8s.io/descheduler/pkg/framework/testing"
+	frameworktypes "sigs.k8s.io/descheduler/pkg/framework/types"
+	"sigs.k8s.io/descheduler/test"
+)
+
+// recordingDecisionSink keeps the records it receives.
+type recordingDecisionSink struct {
+	records []DecisionRecord
+}
+
+func (s *recordingDecisionSink) Record(record DecisionRecord) error {
+	s.records = append(s.records, record)
+	return nil
+}
+
+func TestDecisionRecords(t *testing.T) {
+	ctx := context.Background()
+
+	n1 := test.BuildTestNode("n1", 4000, 3000, 10, nil)
+	n2 := test.BuildTestNode("n2", 4000, 3000, 10, nil)
+	nodes := []*v1.Node{n1, n2}
+
+	objs := []runtime.Object{n1, n2}
+	for _, name := range []string{"p1", "p2", "p3", "p4"} {
+		objs = append(objs, test.BuildTestPod(name, 800, 0, n1.Name, test.SetRSOwnerRef))
+	}
+	objs = append(objs, test.BuildTestPod("p5", 400, 0, n2.Name, test.SetRSOwnerRef))
+
+	handle, podEvictor, err := frameworktesting.InitFrameworkHandle(
+		ctx,
+		fake.NewSimpleClientset(objs...),
+		nil,
+		defaultevictor.DefaultEvictorArgs{},
+		func(pods []*v1.Pod) {
+			sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+		},
+	)
+	if err != nil {
+		t.Fatalf("Unable to initialize a framework handle: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "decisions.jsonl")
+	plugin, err := NewLowNodeUtilization(&LowNodeUtilizationArgs{
+		Thresholds:       api.ResourceThresholds{v1.ResourceCPU: 30},
+		TargetThresholds: api.ResourceThresholds{v1.ResourceCPU: 50},
+		DecisionLog:      &DecisionLog{Path: path},
+	}, handle)
+	if err != nil {
+		t.Fatalf("Unable to initialize the plugin: %v", err)
+	}
+
+	sink := &recordingDecisionSink{}
+	unregister := RegisterDecisionSink(sink)
+	defer unregister()
+
+	if status := plugin.(frameworktypes.BalancePlugin).Balance(ctx, nodes); status != nil && status.Err != nil {
+		t.Fatalf("Unexpected error: %v", status.Err)
+	}
+	if evicted := podEvictor.TotalEvicted(); evicted != 2 {
+		t.Fatalf("Expected 2 pods to be evicted, got %v", evicted)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Unable to open the decision log: %v", err)
+	}
+	defer file.Close()
+	var logged []DecisionRecord
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record DecisionRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("Unable to decode decision record %q: %v", scanner.Text(), err)
+		}
+		logged = append(logged, record)
+	}
+
+	for source, records := range map[string][]DecisionRecord{"file": logged, "sink": sink.records} {
+		if len(records) != 2 {
+			t.Fatalf("Expected 2 records in the %v, got %v", source, len(records))
+		}
+		for i, expected := range []struct {
+			pod     string
+			nodeCPU int64
+		}{
+			{pod: "p1", nodeCPU: 3200},
+			{pod: "p2", nodeCPU: 2400},
+		} {
+			record := records[i]
+			if record.Plugin != LowNodeUtilizationPluginName || record.Pod != expected.pod || record.Namespace != "default" || record.Node != "n1" {
+				t.Errorf("Unexpected record in the %v: %+v", source, record)
+			}
+			if record.Reason != "node overutilized" {
+				t.Errorf("Unexpected reason in the %v: %q", source, record.Reason)
+			}
+			if cpu := record.NodeUsage[v1.ResourceCPU].MilliValue(); cpu != expected.nodeCPU {
+				t.Errorf("Expected node cpu usage %vm in the %v, got %vm", expected.nodeCPU, source, cpu)
+			}
+			if cpu := record.PodUsage[v1.ResourceCPU].MilliValue(); cpu != 800 {
+				t.Errorf("Expected pod cpu usage 800m in the %v, got %vm", source, cpu)
+			}
+			if record.NodeUsagePercentage[v1.ResourceCPU] != 80 {
+				t.Errorf("Expected node cpu usage 80%% in the %v, got %v", source, record.NodeUsagePercentage[v1.ResourceCPU])
+			}
+			if len(record.Thresholds) != 2 || record.Thresholds[0][v1.ResourceCPU] != 30 || record.Thresholds[1][v1.ResourceCPU] != 50 {
+				t.Errorf("Unexpected thresholds in the %v: %v", source, record.Thresholds)
+			}
+			if record.DryRun {
+				t.Errorf("Unexpected dry run record in the %v", source)
+			}
+		}
+	}
+
+	unregister()
+	if sinks := decisionSinksFor(nil); len(sinks) != 1 {
+		t.Errorf("Expected only the log sink once the sink is unregistered, got %v", sinks)
+	}
+}
+
+func TestEventDetails(t *testing.T) {
+	n1 := test.BuildTestNode("n1", 4000, 3000, 10, nil)
+	n2 := test.BuildTestNode("n2", 4000, 3000, 10, nil)
+
+	summary := newBalanceSummary(LowNodeUtilizationPluginName)
+	summary.classified(n1.Name, "overutilized")
+	summary.assessed(
+		map[string]api.ResourceThresholds{n1.Name: {v1.ResourceCPU: 80, v1.ResourceMemory: 12.5}},
+		map[string][]api.ResourceThresholds{n1.Name: {
+			{v1.ResourceCPU: 30, v1.ResourceMemory: 30},
+			{v1.ResourceCPU: 50, v1.ResourceMemory: 50},
+		}},
+	)
+
+	source := NodeInfo{NodeUsage: NodeUsage{node: n1}}
+	destination := &NodeInfo{
+		NodeUsage: NodeUsage{
+			node:  n2,
+			usage: frameworktesting.BuildNodeUsage().WithCPU("1").WithMemory("1000").Build(),
+		},
+		available: frameworktesting.BuildNodeUsage().WithCPU("2").WithMemory("1500").Build(),
+	}
+	podUsage := frameworktesting.BuildPodUsage().WithCPU("800m").WithMemory("100").Build()
+
+	for _, tc := range []struct {
+		name        string
+		destination *NodeInfo
+		expected    string
+	}{
+		{
+			name:     "without destination",
+			expected: "LowNodeUtilization: node overutilized, usage cpu=80.00% memory=12.50%, thresholds cpu=30.00% memory=30.00% / cpu=50.00% memory=50.00%",
+		},
+		{
+			name:        "with destination",
+			destination: destination,
+			expected:    "LowNodeUtilization: node overutilized, usage cpu=80.00% memory=12.50%, thresholds cpu=30.00% memory=30.00% / cpu=50.00% memory=50.00%, expected on node n2 with cpu=200m memory=400 left",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if details := summary.eventDetails(source, podUsage, tc.destination); details != tc.expected {
+				t.Errorf("Expected details %q, got %q", tc.expected, details)
+			}
+		})
+	}
+}
@@ -50,3 +50,12 @@ func SetDefaults_HighNodeUtilizationArgs(obj runtime.Object) {
 		args.NumberOfNodes = 0
 	}
 }
+
+// SetDefaults_NodeConsolidationArgs drains a single node per cycle unless
+// told otherwise.
+func SetDefaults_NodeConsolidationArgs(obj runtime.Object) {
+	args := obj.(*NodeConsolidationArgs)
+	if args.MaxNodesToDrain == 0 {
+		args.MaxNodesToDrain = 1
+	}
+}
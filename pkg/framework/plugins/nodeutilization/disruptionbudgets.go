@@ -0,0 +1,142 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	policy "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	policyv1 "k8s.io/client-go/listers/policy/v1"
+	"k8s.io/klog/v2"
+)
+
+// disruptionBudgets looks ahead, before a pod is evicted, at the
+// PodDisruptionBudgets covering it so pods the api server would refuse to
+// evict are not attempted at all. the disruptions the budgets allow are read
+// from their status, which is not updated during the cycle, so the evictions
+// of the cycle are charged to the budgets as they happen.
+type disruptionBudgets struct {
+	lister policyv1.PodDisruptionBudgetLister
+	// charged counts the evictions charged to every budget.
+	charged map[types.NamespacedName]int32
+}
+
+// newDisruptionBudgets returns the budgets known to the informer factory, nil
+// is returned if there is no factory to read them from.
+func newDisruptionBudgets(factory informers.SharedInformerFactory) *disruptionBudgets {
+	if factory == nil {
+		return nil
+	}
+	return &disruptionBudgets{
+		lister:  factory.Policy().V1().PodDisruptionBudgets().Lister(),
+		charged: map[types.NamespacedName]int32{},
+	}
+}
+
+// check returns why the eviction of the pod would be refused because of its
+// budget, nil is returned if the pod can be evicted.
+func (b *disruptionBudgets) check(pod *v1.Pod) error {
+	pdb, err := b.budgetOf(pod)
+	if err != nil || pdb == nil {
+		return err
+	}
+	if !disruptionCharged(pod, pdb) {
+		return nil
+	}
+	allowed := pdb.Status.DisruptionsAllowed - b.charged[types.NamespacedName{Namespace: pdb.Namespace, Name: pdb.Name}]
+	if allowed <= 0 {
+		return fmt.Errorf("PodDisruptionBudget %s/%s allows no more disruptions", pdb.Namespace, pdb.Name)
+	}
+	return nil
+}
+
+// evicted charges the eviction of the pod to its budget, if any.
+func (b *disruptionBudgets) evicted(pod *v1.Pod) {
+	pdb, err := b.budgetOf(pod)
+	if err != nil || pdb == nil || !disruptionCharged(pod, pdb) {
+		return
+	}
+	b.charged[types.NamespacedName{Namespace: pdb.Namespace, Name: pdb.Name}]++
+}
+
+// budgetOf returns the budget covering the pod. as the api server does, pods
+// covered by more than one budget can not be evicted at all and pods that are
+// not running, or already terminating, are not covered by any budget.
+func (b *disruptionBudgets) budgetOf(pod *v1.Pod) (*policy.PodDisruptionBudget, error) {
+	if b == nil || pod.DeletionTimestamp != nil {
+		return nil, nil
+	}
+	switch pod.Status.Phase {
+	case v1.PodPending, v1.PodSucceeded, v1.PodFailed:
+		return nil, nil
+	}
+
+	pdbs, err := b.lister.PodDisruptionBudgets(pod.Namespace).List(labels.Everything())
+	if err != nil {
+		klog.ErrorS(err, "Unable to list PodDisruptionBudgets", "namespace", pod.Namespace)
+		return nil, nil
+	}
+	var matching []*policy.PodDisruptionBudget
+	for _, pdb := range pdbs {
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil {
+			// an invalid selector never matches any pod.
+			continue
+		}
+		if selector.Matches(labels.Set(pod.Labels)) {
+			matching = append(matching, pdb)
+		}
+	}
+	switch len(matching) {
+	case 0:
+		return nil, nil
+	case 1:
+		return matching[0], nil
+	default:
+		return nil, fmt.Errorf("pod is covered by %d PodDisruptionBudgets", len(matching))
+	}
+}
+
+// disruptionCharged tells if evicting the pod consumes a disruption of its
+// budget. pods that are not ready do not when the budget lets unhealthy pods
+// go, either always or as long as enough pods are healthy.
+func disruptionCharged(pod *v1.Pod, pdb *policy.PodDisruptionBudget) bool {
+	if podReady(pod) {
+		return true
+	}
+	unhealthyPolicy := pdb.Spec.UnhealthyPodEvictionPolicy
+	if unhealthyPolicy != nil && *unhealthyPolicy == policy.AlwaysAllow {
+		return false
+	}
+	healthy := pdb.Status.CurrentHealthy >= pdb.Status.DesiredHealthy && pdb.Status.DesiredHealthy > 0
+	return !healthy
+}
+
+// podReady tells if the ready condition of the pod is true.
+func podReady(pod *v1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
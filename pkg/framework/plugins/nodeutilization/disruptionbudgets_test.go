@@ -0,0 +1,171 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	policy "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/descheduler/pkg/api"
+	"sigs.k8s.io/descheduler/pkg/framework/plugins/defaultevictor"
+	frameworktesting "sigs.k8s.io/descheduler/pkg/framework/testing"
+	frameworktypes "sigs.k8s.io/descheduler/pkg/framework/types"
+	"sigs.k8s.io/descheduler/test"
+)
+
+func buildTestPDB(name, app string, allowed int32, apply func(*policy.PodDisruptionBudget)) *policy.PodDisruptionBudget {
+	pdb := &policy.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: policy.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": app}},
+		},
+		Status: policy.PodDisruptionBudgetStatus{DisruptionsAllowed: allowed},
+	}
+	if apply != nil {
+		apply(pdb)
+	}
+	return pdb
+}
+
+func withApp(app string, ready bool) func(*v1.Pod) {
+	return func(pod *v1.Pod) {
+		test.SetRSOwnerRef(pod)
+		pod.Labels = map[string]string{"app": app}
+		pod.Status.Phase = v1.PodRunning
+		status := v1.ConditionFalse
+		if ready {
+			status = v1.ConditionTrue
+		}
+		pod.Status.Conditions = []v1.PodCondition{{Type: v1.PodReady, Status: status}}
+	}
+}
+
+func TestDisruptionBudgets(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := fake.NewSimpleClientset(
+		buildTestPDB("web", "web", 1, nil),
+		buildTestPDB("db", "db", 0, func(pdb *policy.PodDisruptionBudget) {
+			pdb.Spec.UnhealthyPodEvictionPolicy = ptr.To(policy.AlwaysAllow)
+		}),
+		buildTestPDB("cache", "cache", 0, func(pdb *policy.PodDisruptionBudget) {
+			pdb.Status.CurrentHealthy, pdb.Status.DesiredHealthy = 2, 2
+		}),
+		buildTestPDB("queue-1", "queue", 5, nil),
+		buildTestPDB("queue-2", "queue", 5, nil),
+	)
+	factory := informers.NewSharedInformerFactory(client, 0)
+	budgets := newDisruptionBudgets(factory)
+	factory.Start(ctx.Done())
+	factory.WaitForCacheSync(ctx.Done())
+
+	pending := test.BuildTestPod("db-pending", 100, 0, "n1", withApp("db", false))
+	pending.Status.Phase = v1.PodPending
+
+	for _, step := range []struct {
+		pod     *v1.Pod
+		blocked bool
+	}{
+		{pod: test.BuildTestPod("web-1", 100, 0, "n1", withApp("web", true))},
+		{pod: test.BuildTestPod("web-2", 100, 0, "n1", withApp("web", true)), blocked: true},
+		{pod: test.BuildTestPod("db-1", 100, 0, "n1", withApp("db", true)), blocked: true},
+		{pod: test.BuildTestPod("db-2", 100, 0, "n1", withApp("db", false))},
+		{pod: pending},
+		{pod: test.BuildTestPod("cache-1", 100, 0, "n1", withApp("cache", false))},
+		{pod: test.BuildTestPod("cache-2", 100, 0, "n1", withApp("cache", true)), blocked: true},
+		{pod: test.BuildTestPod("queue-1", 100, 0, "n1", withApp("queue", true)), blocked: true},
+		{pod: test.BuildTestPod("plain", 100, 0, "n1", withApp("plain", true))},
+	} {
+		err := budgets.check(step.pod)
+		if blocked := err != nil; blocked != step.blocked {
+			t.Fatalf("expected pod %v to be blocked to be %v, got %v instead: %v", step.pod.Name, step.blocked, blocked, err)
+		}
+		if err == nil {
+			budgets.evicted(step.pod)
+		}
+	}
+
+	var none *disruptionBudgets
+	if err := none.check(test.BuildTestPod("web-3", 100, 0, "n1", withApp("web", true))); err != nil {
+		t.Errorf("expected no budget to block pods, got %v instead", err)
+	}
+}
+
+func TestLowNodeUtilizationDisruptionBudgets(t *testing.T) {
+	ctx := context.Background()
+
+	n1 := test.BuildTestNode("n1", 4000, 3000, 10, nil)
+	n2 := test.BuildTestNode("n2", 4000, 3000, 10, nil)
+	nodes := []*v1.Node{n1, n2}
+
+	objs := []runtime.Object{n1, n2, buildTestPDB("web", "web", 1, nil)}
+	for _, name := range []string{"p1", "p2", "p3", "p4"} {
+		objs = append(objs, test.BuildTestPod(name, 800, 0, n1.Name, withApp("web", true)))
+	}
+
+	handle, podEvictor, err := frameworktesting.InitFrameworkHandle(
+		ctx,
+		fake.NewSimpleClientset(objs...),
+		nil,
+		defaultevictor.DefaultEvictorArgs{},
+		func(pods []*v1.Pod) {
+			sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+		},
+	)
+	if err != nil {
+		t.Fatalf("Unable to initialize a framework handle: %v", err)
+	}
+	handle.SharedInformerFactory().Policy().V1().PodDisruptionBudgets().Informer()
+	handle.SharedInformerFactory().Start(ctx.Done())
+	handle.SharedInformerFactory().WaitForCacheSync(ctx.Done())
+
+	plugin, err := NewLowNodeUtilization(&LowNodeUtilizationArgs{
+		Thresholds:       api.ResourceThresholds{v1.ResourceCPU: 30},
+		TargetThresholds: api.ResourceThresholds{v1.ResourceCPU: 50},
+	}, handle)
+	if err != nil {
+		t.Fatalf("Unable to initialize the plugin: %v", err)
+	}
+
+	var summary *balanceSummary
+	summaryObserver = func(s *balanceSummary) { summary = s }
+	defer func() { summaryObserver = nil }()
+
+	if status := plugin.(frameworktypes.BalancePlugin).Balance(ctx, nodes); status != nil && status.Err != nil {
+		t.Fatalf("Unexpected error: %v", status.Err)
+	}
+
+	if evicted := podEvictor.TotalEvicted(); evicted != 1 {
+		t.Errorf("Expected a single pod to be evicted, got %v", evicted)
+	}
+	if summary == nil {
+		t.Fatalf("No balance summary observed")
+	}
+	if summary.blockedByBudget != 3 {
+		t.Errorf("Expected 3 pods blocked by their disruption budget, got %v", summary.blockedByBudget)
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"context"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	nodeutil "sigs.k8s.io/descheduler/pkg/descheduler/node"
+)
+
+// doNotDisruptNodes returns the destination nodes the evicted pods are
+// expected to be scheduled onto: the nodes they were placed on when a
+// scoring strategy is configured, all the destination nodes otherwise.
+func doNotDisruptNodes(destinationNodes []NodeInfo, placements []podPlacement) []*v1.Node {
+	if len(placements) == 0 {
+		nodes := make([]*v1.Node, 0, len(destinationNodes))
+		for _, node := range destinationNodes {
+			nodes = append(nodes, node.node)
+		}
+		return nodes
+	}
+
+	placed := sets.New[string]()
+	for _, placement := range placements {
+		placed.Insert(placement.node)
+	}
+	var nodes []*v1.Node
+	for _, node := range destinationNodes {
+		if placed.Has(node.node.Name) {
+			nodes = append(nodes, node.node)
+		}
+	}
+	return nodes
+}
+
+// markDoNotDisrupt annotates the nodes with karpenter.sh/do-not-disrupt for
+// the ttl, so karpenter does not consolidate the nodes pods were just moved
+// onto and moves them back. nodes annotated for more than half the ttl
+// still are not patched again. marks are best effort, failures are logged
+// and do not interrupt the process.
+func markDoNotDisrupt(ctx context.Context, client clientset.Interface, ttl time.Duration, nodes []*v1.Node, now time.Time) {
+	for _, node := range nodes {
+		if until, ours := nodeutil.DoNotDisruptUntil(node); ours && until.Sub(now) > ttl/2 {
+			continue
+		}
+		if err := nodeutil.MarkNodeDoNotDisrupt(ctx, client, node.Name, now.Add(ttl)); err != nil {
+			klog.ErrorS(err, "unable to annotate the node as not to be disrupted", "node", klog.KObj(node))
+			continue
+		}
+		klog.V(1).InfoS("Node annotated as not to be disrupted", "node", klog.KObj(node), "ttl", ttl)
+	}
+}
+
+// clearDoNotDisruptHints removes the karpenter.sh/do-not-disrupt annotation
+// the descheduler set on the nodes once expired. failures are logged and do
+// not interrupt the process.
+func clearDoNotDisruptHints(ctx context.Context, client clientset.Interface, nodes []*v1.Node, now time.Time) {
+	for _, node := range nodes {
+		until, ours := nodeutil.DoNotDisruptUntil(node)
+		if !ours || now.Before(until) {
+			continue
+		}
+		if _, err := nodeutil.UnmarkNodeDoNotDisrupt(ctx, client, node); err != nil {
+			klog.ErrorS(err, "unable to remove the do not disrupt annotation of the node", "node", klog.KObj(node))
+			continue
+		}
+		klog.V(1).InfoS("Do not disrupt annotation removed from the node", "node", klog.KObj(node))
+	}
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/descheduler/pkg/api"
+	nodeutil "sigs.k8s.io/descheduler/pkg/descheduler/node"
+	"sigs.k8s.io/descheduler/pkg/framework/plugins/defaultevictor"
+	frameworktesting "sigs.k8s.io/descheduler/pkg/framework/testing"
+	frameworktypes "sigs.k8s.io/descheduler/pkg/framework/types"
+	"sigs.k8s.io/descheduler/test"
+)
+
+func TestLowNodeUtilizationDoNotDisruptHints(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		ttl       time.Duration
+		annotated []bool
+	}{
+		{
+			name:      "no hints",
+			annotated: []bool{false, false, false},
+		},
+		{
+			name:      "destination nodes annotated",
+			ttl:       15 * time.Minute,
+			annotated: []bool{false, true, true},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			nodes := []*v1.Node{
+				test.BuildTestNode("n1", 4000, 3000, 10, nil),
+				test.BuildTestNode("n2", 4000, 3000, 10, nil),
+				test.BuildTestNode("n3", 4000, 3000, 10, nil),
+			}
+			objs := []runtime.Object{nodes[0], nodes[1], nodes[2]}
+			for i := 0; i < 4; i++ {
+				objs = append(objs, test.BuildTestPod(fmt.Sprintf("n1-p%d", i), 800, 0, "n1", test.SetRSOwnerRef))
+			}
+			objs = append(objs, test.BuildTestPod("n2-p0", 400, 0, "n2", test.SetRSOwnerRef))
+
+			client := fake.NewSimpleClientset(objs...)
+			handle, podEvictor, err := frameworktesting.InitFrameworkHandle(
+				ctx,
+				client,
+				nil,
+				defaultevictor.DefaultEvictorArgs{},
+				func(pods []*v1.Pod) {
+					sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+				},
+			)
+			if err != nil {
+				t.Fatalf("Unable to initialize a framework handle: %v", err)
+			}
+
+			plugin, err := NewLowNodeUtilization(&LowNodeUtilizationArgs{
+				Thresholds:          api.ResourceThresholds{v1.ResourceCPU: 30},
+				TargetThresholds:    api.ResourceThresholds{v1.ResourceCPU: 50},
+				DoNotDisruptHintTTL: metav1.Duration{Duration: tc.ttl},
+			}, handle)
+			if err != nil {
+				t.Fatalf("Unable to initialize the plugin: %v", err)
+			}
+
+			start := time.Now()
+			if status := plugin.(frameworktypes.BalancePlugin).Balance(ctx, nodes); status != nil && status.Err != nil {
+				t.Fatalf("Unexpected error: %v", status.Err)
+			}
+			if podEvictor.TotalEvicted() == 0 {
+				t.Fatalf("Expected pods to be evicted")
+			}
+
+			for i, expected := range tc.annotated {
+				node, err := client.CoreV1().Nodes().Get(ctx, nodes[i].Name, metav1.GetOptions{})
+				if err != nil {
+					t.Fatalf("Unable to get the node: %v", err)
+				}
+				if annotated := node.Annotations[nodeutil.KarpenterDoNotDisruptKey] == "true"; annotated != expected {
+					t.Errorf("Expected node %v to be annotated to be %v, got %v", node.Name, expected, annotated)
+				}
+				until, ours := nodeutil.DoNotDisruptUntil(node)
+				if ours != expected || (expected && until.Before(start.Add(tc.ttl).Truncate(time.Second))) {
+					t.Errorf("Expected node %v to be recorded as annotated for %v to be %v, got %v until %v", node.Name, tc.ttl, expected, ours, until)
+				}
+			}
+		})
+	}
+}
+
+func TestClearDoNotDisruptHints(t *testing.T) {
+	ctx := context.Background()
+
+	now := time.Now()
+	annotated := func(name string, until *time.Time) *v1.Node {
+		return test.BuildTestNode(name, 4000, 3000, 10, func(node *v1.Node) {
+			node.Annotations = map[string]string{nodeutil.KarpenterDoNotDisruptKey: "true"}
+			if until != nil {
+				node.Annotations[nodeutil.DoNotDisruptUntilKey] = until.Format(time.RFC3339)
+			}
+		})
+	}
+	recent, expired := now.Add(time.Minute), now.Add(-time.Minute)
+	nodes := []*v1.Node{
+		annotated("recent", &recent),
+		annotated("expired", &expired),
+		annotated("someone-else", nil),
+	}
+	objs := []runtime.Object{}
+	for _, node := range nodes {
+		objs = append(objs, node)
+	}
+
+	client := fake.NewSimpleClientset(objs...)
+	clearDoNotDisruptHints(ctx, client, nodes, now)
+
+	for i, expected := range []bool{true, false, true} {
+		node, err := client.CoreV1().Nodes().Get(ctx, nodes[i].Name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if _, stillAnnotated := node.Annotations[nodeutil.KarpenterDoNotDisruptKey]; stillAnnotated != expected {
+			t.Errorf("Expected node %v to be annotated to be %v, got %v", node.Name, expected, stillAnnotated)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/descheduler/pkg/api"
+	"sigs.k8s.io/descheduler/pkg/descheduler/evictions"
+	"sigs.k8s.io/descheduler/pkg/framework/plugins/nodeutilization/normalizer"
+	frameworktypes "sigs.k8s.io/descheduler/pkg/framework/types"
+)
+
+// appropriatelyUtilized is the category reported for the nodes that were
+// neither classified as under nor as overutilized.
+const appropriatelyUtilized = "appropriatelyUtilized"
+
+// dryRunEvictor wraps an evictor so the whole eviction process runs without
+// evicting any pod. the pods that would have been evicted are recorded
+// instead. the pod filters of the wrapped evictor are still honored while
+// its eviction limits are not, they are only evaluated upon eviction.
+type dryRunEvictor struct {
+	frameworktypes.Evictor
+	pods []*v1.Pod
+}
+
+var _ frameworktypes.Evictor = &dryRunEvictor{}
+var _ frameworktypes.DryRunEvictor = &dryRunEvictor{}
+
+func newDryRunEvictor(evictor frameworktypes.Evictor) *dryRunEvictor {
+	return &dryRunEvictor{Evictor: evictor}
+}
+
+// Evict records the pod as one that would have been evicted.
+func (e *dryRunEvictor) Evict(_ context.Context, pod *v1.Pod, _ evictions.EvictOptions) error {
+	e.pods = append(e.pods, pod)
+	return nil
+}
+
+func (e *dryRunEvictor) DryRun() bool {
+	return true
+}
+
+// dryRunNodeReport is the outcome of the balancing for a single node.
+// usages are percentages of the node capacity, the projected usage is only
+// reported for the nodes pods would have been evicted from.
+type dryRunNodeReport struct {
+	Name           string                 `json:"name"`
+	Category       string                 `json:"category"`
+	Usage          api.ResourceThresholds `json:"usage"`
+	ProjectedUsage api.ResourceThresholds `json:"projectedUsage,omitempty"`
+}
+
+// dryRunPodReport is a pod that would have been evicted.
+type dryRunPodReport struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Node      string `json:"node"`
+}
+
+// dryRunDestinationReport is what would have been left of a destination
+// node once the evicted pods expected on it were placed.
+type dryRunDestinationReport struct {
+	Name     string                     `json:"name"`
+	Pods     int                        `json:"pods"`
+	Headroom api.ReferencedResourceList `json:"headroom"`
+}
+
+// dryRunReport is what a Balance invocation would have done had it not
+// been running in dry run mode.
+type dryRunReport struct {
+	Nodes        []dryRunNodeReport        `json:"nodes"`
+	Pods         []dryRunPodReport         `json:"pods"`
+	Destinations []dryRunDestinationReport `json:"destinations,omitempty"`
+	// BlockedByDisruptionBudget is the number of pods that were not
+	// evicted as their disruption budget was exhausted.
+	BlockedByDisruptionBudget int `json:"blockedByDisruptionBudget,omitempty"`
+}
+
+// projected keeps the usage of the source nodes as left by the eviction
+// process, i.e. once the pods that would be evicted are gone.
+func (s *balanceSummary) projected(sourceNodes []NodeInfo, capacities map[string]api.ReferencedResourceList) {
+	usage := map[string]api.ReferencedResourceList{}
+	for _, node := range sourceNodes {
+		if s.evictedPerNode[node.node.Name] > 0 {
+			usage[node.node.Name] = node.usage
+		}
+	}
+	s.projectedUsage = normalizer.Normalize(usage, capacities, ResourceUsageToResourceThreshold)
+}
+
+// dryRunReport builds the report of the summary, nil is returned if the
+// balancing was not running in dry run mode.
+func (s *balanceSummary) dryRunReport() *dryRunReport {
+	if s.dryRun == nil {
+		return nil
+	}
+
+	report := &dryRunReport{BlockedByDisruptionBudget: s.blockedByBudget}
+	for _, name := range sortedNodeNames(s.usage) {
+		category, ok := s.categories[name]
+		if !ok {
+			category = appropriatelyUtilized
+		}
+		node := dryRunNodeReport{
+			Name:     name,
+			Category: category,
+			Usage:    normalizer.Round(s.usage[name]),
+		}
+		if projected, ok := s.projectedUsage[name]; ok {
+			node.ProjectedUsage = normalizer.Round(projected)
+		}
+		report.Nodes = append(report.Nodes, node)
+	}
+	for _, pod := range s.dryRun.pods {
+		report.Pods = append(report.Pods, dryRunPodReport{
+			Namespace: pod.Namespace,
+			Name:      pod.Name,
+			Node:      pod.Spec.NodeName,
+		})
+	}
+	for _, name := range sortedNodeNames(s.destinations) {
+		report.Destinations = append(report.Destinations, dryRunDestinationReport{
+			Name:     name,
+			Pods:     s.destinations[name].pods,
+			Headroom: s.destinations[name].available,
+		})
+	}
+	return report
+}
+
+// logDryRunReport emits the dry run report as a single structured log
+// entry, if any.
+func (s *balanceSummary) logDryRunReport() {
+	report := s.dryRunReport()
+	if report == nil {
+		return
+	}
+	klog.InfoS(
+		"Balance dry run report",
+		"plugin", s.plugin,
+		"nodes", report.Nodes,
+		"pods", report.Pods,
+		"destinations", report.Destinations,
+		"blockedByDisruptionBudget", report.BlockedByDisruptionBudget,
+	)
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/descheduler/pkg/api"
+	"sigs.k8s.io/descheduler/pkg/framework/plugins/defaultevictor"
+	frameworktesting "sigs.k8s.io/descheduler/pkg/framework/testing"
+	frameworktypes "sigs.k8s.io/descheduler/pkg/framework/types"
+	"sigs.k8s.io/descheduler/test"
+)
+
+func TestDryRunReport(t *testing.T) {
+	ctx := context.Background()
+
+	n1 := test.BuildTestNode("n1", 4000, 3000, 10, nil)
+	n2 := test.BuildTestNode("n2", 4000, 3000, 10, nil)
+	n3 := test.BuildTestNode("n3", 4000, 3000, 10, nil)
+	nodes := []*v1.Node{n1, n2, n3}
+
+	objs := []runtime.Object{n1, n2, n3}
+	for _, name := range []string{"p1", "p2", "p3", "p4"} {
+		objs = append(objs, test.BuildTestPod(name, 800, 0, n1.Name, test.SetRSOwnerRef))
+	}
+	objs = append(objs, test.BuildTestPod("p5", 400, 0, n2.Name, test.SetRSOwnerRef))
+	objs = append(objs, test.BuildTestPod("p6", 1600, 0, n3.Name, test.SetRSOwnerRef))
+
+	handle, podEvictor, err := frameworktesting.InitFrameworkHandle(
+		ctx,
+		fake.NewSimpleClientset(objs...),
+		nil,
+		defaultevictor.DefaultEvictorArgs{},
+		func(pods []*v1.Pod) {
+			sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+		},
+	)
+	if err != nil {
+		t.Fatalf("Unable to initialize a framework handle: %v", err)
+	}
+
+	plugin, err := NewLowNodeUtilization(&LowNodeUtilizationArgs{
+		Thresholds:       api.ResourceThresholds{v1.ResourceCPU: 30},
+		TargetThresholds: api.ResourceThresholds{v1.ResourceCPU: 50},
+		DryRun:           true,
+	}, handle)
+	if err != nil {
+		t.Fatalf("Unable to initialize the plugin: %v", err)
+	}
+
+	var summary *balanceSummary
+	summaryObserver = func(s *balanceSummary) { summary = s }
+	defer func() { summaryObserver = nil }()
+
+	if status := plugin.(frameworktypes.BalancePlugin).Balance(ctx, nodes); status != nil && status.Err != nil {
+		t.Fatalf("Unexpected error: %v", status.Err)
+	}
+
+	if evicted := podEvictor.TotalEvicted(); evicted != 0 {
+		t.Errorf("Expected no pod to be evicted in dry run mode, got %v", evicted)
+	}
+	if summary == nil {
+		t.Fatalf("No balance summary observed")
+	}
+
+	expected := &dryRunReport{
+		Nodes: []dryRunNodeReport{
+			{
+				Name:           "n1",
+				Category:       "overutilized",
+				Usage:          api.ResourceThresholds{v1.ResourceCPU: 80, v1.ResourceMemory: 0, v1.ResourcePods: 40},
+				ProjectedUsage: api.ResourceThresholds{v1.ResourceCPU: 40, v1.ResourceMemory: 0, v1.ResourcePods: 20},
+			},
+			{
+				Name:     "n2",
+				Category: "underutilized",
+				Usage:    api.ResourceThresholds{v1.ResourceCPU: 10, v1.ResourceMemory: 0, v1.ResourcePods: 10},
+			},
+			{
+				Name:     "n3",
+				Category: appropriatelyUtilized,
+				Usage:    api.ResourceThresholds{v1.ResourceCPU: 40, v1.ResourceMemory: 0, v1.ResourcePods: 10},
+			},
+		},
+		Pods: []dryRunPodReport{
+			{Namespace: "default", Name: "p1", Node: "n1"},
+			{Namespace: "default", Name: "p2", Node: "n1"},
+		},
+	}
+	if diff := cmp.Diff(expected, summary.dryRunReport()); diff != "" {
+		t.Errorf("Unexpected dry run report (-want +got):\n%s", diff)
+	}
+}
+
+func TestDryRunReportDisabled(t *testing.T) {
+	summary := newBalanceSummary(LowNodeUtilizationPluginName)
+	summary.assessed(map[string]api.ResourceThresholds{"n1": {v1.ResourceCPU: 50}}, nil)
+	if report := summary.dryRunReport(); report != nil {
+		t.Errorf("Expected no report outside of dry run mode, got %v", report)
+	}
+}
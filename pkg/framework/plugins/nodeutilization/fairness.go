@@ -0,0 +1,110 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"math"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/descheduler/pkg/api"
+)
+
+// namespaceQueue holds the eviction candidates of a namespace, in the order
+// they were sorted in, along with the share of the node usage they account
+// for and the number of them already picked.
+type namespaceQueue struct {
+	pods   []*v1.Pod
+	share  float64
+	picked int
+}
+
+// spreadAcrossNamespaces reorders the eviction candidates of a source node
+// so the evictions are spread across their namespaces, every namespace
+// being evicted a number of pods proportional to the share of the node
+// usage its candidates account for. the pods of a namespace keep the order
+// they were sorted in and, among namespaces equally entitled to the next
+// eviction, the pod sorted first goes first. namespaces whose usage is not
+// known are evicted last.
+func spreadAcrossNamespaces(
+	policy FairnessPolicy,
+	pods []*v1.Pod,
+	nodeUsage api.ReferencedResourceList,
+	podUsage PodUsageFunc,
+) []*v1.Pod {
+	if policy != FairnessPolicyProportional || len(pods) < 2 {
+		return pods
+	}
+
+	// position is where every pod was sorted, it breaks the ties.
+	position := make(map[*v1.Pod]int, len(pods))
+	queues := map[string]*namespaceQueue{}
+	var namespaces []string
+	for i, pod := range pods {
+		position[pod] = i
+		queue, ok := queues[pod.Namespace]
+		if !ok {
+			queue = &namespaceQueue{}
+			queues[pod.Namespace] = queue
+			namespaces = append(namespaces, pod.Namespace)
+		}
+		queue.pods = append(queue.pods, pod)
+
+		usage, err := podUsage(pod)
+		if err != nil {
+			klog.V(4).InfoS("Unable to get the pod usage, pod is considered as not consuming anything", "pod", klog.KObj(pod), "err", err)
+			continue
+		}
+		queue.share += podUsageShare(usage, nodeUsage)
+	}
+	if len(namespaces) < 2 {
+		return pods
+	}
+
+	// entitlement grows as a namespace is picked, the namespace picked
+	// next is the one that would be the least over its share once its
+	// next pod is evicted.
+	entitlement := func(queue *namespaceQueue) float64 {
+		if queue.share <= 0 {
+			return math.Inf(1)
+		}
+		return float64(queue.picked+1) / queue.share
+	}
+
+	result := make([]*v1.Pod, 0, len(pods))
+	for len(result) < len(pods) {
+		var next *namespaceQueue
+		for _, namespace := range namespaces {
+			queue := queues[namespace]
+			if queue.picked == len(queue.pods) {
+				continue
+			}
+			if next == nil {
+				next = queue
+				continue
+			}
+			current, candidate := entitlement(next), entitlement(queue)
+			if candidate < current || (candidate == current && position[queue.pods[queue.picked]] < position[next.pods[next.picked]]) {
+				next = queue
+			}
+		}
+		result = append(result, next.pods[next.picked])
+		next.picked++
+	}
+	return result
+}
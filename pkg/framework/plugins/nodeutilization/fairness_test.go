@@ -0,0 +1,98 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"sigs.k8s.io/descheduler/pkg/api"
+	"sigs.k8s.io/descheduler/test"
+)
+
+func TestSpreadAcrossNamespaces(t *testing.T) {
+	inNamespace := func(namespace string) func(*v1.Pod) {
+		return func(pod *v1.Pod) {
+			pod.Namespace = namespace
+		}
+	}
+
+	// pods are provided in the order they were sorted in, the namespace
+	// "a" accounts for 45% of the node usage, "b" for 20% and "c" for
+	// 2.5%. the usage of the pods of "d" is not known.
+	pods := []*v1.Pod{
+		test.BuildTestPod("a1", 600, 0, "n1", inNamespace("a")),
+		test.BuildTestPod("d1", 100, 0, "n1", inNamespace("d")),
+		test.BuildTestPod("a2", 600, 0, "n1", inNamespace("a")),
+		test.BuildTestPod("a3", 600, 0, "n1", inNamespace("a")),
+		test.BuildTestPod("b1", 400, 0, "n1", inNamespace("b")),
+		test.BuildTestPod("b2", 400, 0, "n1", inNamespace("b")),
+		test.BuildTestPod("c1", 100, 0, "n1", inNamespace("c")),
+	}
+	nodeUsage := api.ReferencedResourceList{v1.ResourceCPU: resource.NewMilliQuantity(4000, resource.DecimalSI)}
+	podUsage := func(pod *v1.Pod) (api.ReferencedResourceList, error) {
+		if pod.Namespace == "d" {
+			return nil, fmt.Errorf("unknown usage")
+		}
+		return api.ReferencedResourceList{v1.ResourceCPU: pod.Spec.Containers[0].Resources.Requests.Cpu()}, nil
+	}
+
+	for _, tc := range []struct {
+		name     string
+		policy   FairnessPolicy
+		pods     []*v1.Pod
+		expected []string
+	}{
+		{
+			name:     "no policy",
+			pods:     pods,
+			expected: []string{"a1", "d1", "a2", "a3", "b1", "b2", "c1"},
+		},
+		{
+			name:     "none",
+			policy:   FairnessPolicyNone,
+			pods:     pods,
+			expected: []string{"a1", "d1", "a2", "a3", "b1", "b2", "c1"},
+		},
+		{
+			name:     "proportional",
+			policy:   FairnessPolicyProportional,
+			pods:     pods,
+			expected: []string{"a1", "a2", "b1", "a3", "b2", "c1", "d1"},
+		},
+		{
+			name:     "proportional within a single namespace",
+			policy:   FairnessPolicyProportional,
+			pods:     []*v1.Pod{pods[3], pods[0], pods[2]},
+			expected: []string{"a3", "a1", "a2"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var names []string
+			for _, pod := range spreadAcrossNamespaces(tc.policy, tc.pods, nodeUsage, podUsage) {
+				names = append(names, pod.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tc.expected, ",") {
+				t.Errorf("expected pods %v, got %v instead", tc.expected, names)
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/descheduler/pkg/api"
+	"sigs.k8s.io/descheduler/pkg/framework/plugins/defaultevictor"
+	frameworktesting "sigs.k8s.io/descheduler/pkg/framework/testing"
+	frameworktypes "sigs.k8s.io/descheduler/pkg/framework/types"
+	"sigs.k8s.io/descheduler/test"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden decision files of the nodeutilization plugins")
+
+// goldenFixture describes the nodes and pods a plugin is run against. node
+// cpu is expressed in millicores, pods are owned by a replica set unless
+// they are unevictable.
+type goldenFixture struct {
+	Plugin string           `json:"plugin"`
+	Args   json.RawMessage  `json:"args"`
+	Nodes  []goldenNodeSpec `json:"nodes"`
+	Pods   []goldenPodSpec  `json:"pods"`
+}
+
+type goldenNodeSpec struct {
+	Name          string `json:"name"`
+	CPU           int64  `json:"cpu"`
+	Memory        int64  `json:"memory"`
+	Pods          int64  `json:"pods"`
+	Unschedulable bool   `json:"unschedulable,omitempty"`
+}
+
+type goldenPodSpec struct {
+	Name        string `json:"name"`
+	Node        string `json:"node"`
+	CPU         int64  `json:"cpu"`
+	Memory      int64  `json:"memory"`
+	Priority    *int32 `json:"priority,omitempty"`
+	Unevictable bool   `json:"unevictable,omitempty"`
+}
+
+// goldenDecisions is the classification and the eviction plan of a plugin
+// as stored in the golden files.
+type goldenDecisions struct {
+	Nodes     []goldenNode     `json:"nodes"`
+	Evictions []goldenEviction `json:"evictions"`
+	Skipped   int              `json:"skipped"`
+}
+
+type goldenNode struct {
+	Name       string                   `json:"name"`
+	Category   string                   `json:"category,omitempty"`
+	Usage      api.ResourceThresholds   `json:"usage,omitempty"`
+	Thresholds []api.ResourceThresholds `json:"thresholds,omitempty"`
+}
+
+type goldenEviction struct {
+	Pod  string `json:"pod"`
+	Node string `json:"node"`
+}
+
+// TestGoldenDecisions runs the plugins against every fixture under
+// testdata/golden and compares their classification and eviction plan
+// with the golden file stored next to the fixture. Run the test with
+// -update to regenerate the golden files.
+func TestGoldenDecisions(t *testing.T) {
+	fixtureFiles, err := filepath.Glob(filepath.Join("testdata", "golden", "*.yaml"))
+	if err != nil {
+		t.Fatalf("Unable to list fixtures: %v", err)
+	}
+	if len(fixtureFiles) == 0 {
+		t.Fatalf("No fixture found")
+	}
+
+	for _, fixtureFile := range fixtureFiles {
+		name := strings.TrimSuffix(filepath.Base(fixtureFile), ".yaml")
+		t.Run(name, func(t *testing.T) {
+			data, err := os.ReadFile(fixtureFile)
+			if err != nil {
+				t.Fatalf("Unable to read fixture: %v", err)
+			}
+			fixture := &goldenFixture{}
+			if err := yaml.UnmarshalStrict(data, fixture); err != nil {
+				t.Fatalf("Unable to decode fixture: %v", err)
+			}
+
+			got, err := yaml.Marshal(runGoldenFixture(t, fixture))
+			if err != nil {
+				t.Fatalf("Unable to encode the decisions: %v", err)
+			}
+
+			goldenFile := strings.TrimSuffix(fixtureFile, ".yaml") + ".golden"
+			if *updateGolden {
+				if err := os.WriteFile(goldenFile, got, 0o644); err != nil {
+					t.Fatalf("Unable to update the golden file: %v", err)
+				}
+			}
+			want, err := os.ReadFile(goldenFile)
+			if err != nil {
+				t.Fatalf("Unable to read the golden file: %v", err)
+			}
+			if diff := cmp.Diff(string(want), string(got)); diff != "" {
+				t.Errorf("Decisions differ from %v (-want +got):\n%s", goldenFile, diff)
+			}
+		})
+	}
+}
+
+// runGoldenFixture runs a single Balance invocation of the fixture plugin
+// and collects the decisions it took.
+func runGoldenFixture(t *testing.T, fixture *goldenFixture) *goldenDecisions {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var objs []runtime.Object
+	var nodes []*v1.Node
+	for _, spec := range fixture.Nodes {
+		node := test.BuildTestNode(spec.Name, spec.CPU, spec.Memory, spec.Pods, func(node *v1.Node) {
+			node.Spec.Unschedulable = spec.Unschedulable
+		})
+		nodes = append(nodes, node)
+		objs = append(objs, node)
+	}
+	for _, spec := range fixture.Pods {
+		objs = append(objs, test.BuildTestPod(spec.Name, spec.CPU, spec.Memory, spec.Node, func(pod *v1.Pod) {
+			if !spec.Unevictable {
+				test.SetRSOwnerRef(pod)
+			}
+			pod.Spec.Priority = spec.Priority
+		}))
+	}
+
+	// pods are handed to the plugin sorted by name so pods sharing the
+	// same priority are always evicted in the same order.
+	handle, podEvictor, err := frameworktesting.InitFrameworkHandle(
+		ctx,
+		fake.NewSimpleClientset(objs...),
+		nil,
+		defaultevictor.DefaultEvictorArgs{},
+		func(pods []*v1.Pod) {
+			sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+		},
+	)
+	if err != nil {
+		t.Fatalf("Unable to initialize a framework handle: %v", err)
+	}
+
+	var plugin frameworktypes.Plugin
+	switch fixture.Plugin {
+	case LowNodeUtilizationPluginName:
+		args := &LowNodeUtilizationArgs{}
+		if err := yaml.UnmarshalStrict(fixture.Args, args); err != nil {
+			t.Fatalf("Unable to decode the plugin args: %v", err)
+		}
+		SetDefaults_LowNodeUtilizationArgs(args)
+		if err := ValidateLowNodeUtilizationArgs(args); err != nil {
+			t.Fatalf("Invalid plugin args: %v", err)
+		}
+		plugin, err = NewLowNodeUtilization(args, handle)
+	case HighNodeUtilizationPluginName:
+		args := &HighNodeUtilizationArgs{}
+		if err := yaml.UnmarshalStrict(fixture.Args, args); err != nil {
+			t.Fatalf("Unable to decode the plugin args: %v", err)
+		}
+		SetDefaults_HighNodeUtilizationArgs(args)
+		if err := ValidateHighNodeUtilizationArgs(args); err != nil {
+			t.Fatalf("Invalid plugin args: %v", err)
+		}
+		plugin, err = NewHighNodeUtilization(args, handle)
+	default:
+		t.Fatalf("Unknown plugin %q", fixture.Plugin)
+	}
+	if err != nil {
+		t.Fatalf("Unable to initialize the plugin: %v", err)
+	}
+
+	var summary *balanceSummary
+	summaryObserver = func(s *balanceSummary) { summary = s }
+	defer func() { summaryObserver = nil }()
+
+	if status := plugin.(frameworktypes.BalancePlugin).Balance(ctx, nodes); status != nil && status.Err != nil {
+		t.Fatalf("Unexpected error: %v", status.Err)
+	}
+	if summary == nil {
+		t.Fatalf("No balance summary observed")
+	}
+
+	decisions := &goldenDecisions{Evictions: []goldenEviction{}, Skipped: summary.skipped}
+	for _, node := range nodes {
+		golden := goldenNode{
+			Name:     node.Name,
+			Category: summary.categories[node.Name],
+			Usage:    roundGoldenPercentages(summary.usage[node.Name]),
+		}
+		for _, threshold := range summary.thresholds[node.Name] {
+			golden.Thresholds = append(golden.Thresholds, roundGoldenPercentages(threshold))
+		}
+		decisions.Nodes = append(decisions.Nodes, golden)
+	}
+	for _, decision := range podEvictor.Decisions() {
+		decisions.Evictions = append(decisions.Evictions, goldenEviction{
+			Pod:  fmt.Sprintf("%s/%s", decision.Namespace, decision.Pod),
+			Node: decision.Node,
+		})
+	}
+	return decisions
+}
+
+// roundGoldenPercentages rounds percentages to two decimals, enough to
+// catch changes in the threshold math without being sensitive to floating
+// point noise.
+func roundGoldenPercentages(values api.ResourceThresholds) api.ResourceThresholds {
+	if values == nil {
+		return nil
+	}
+	result := api.ResourceThresholds{}
+	for name, value := range values {
+		result[name] = api.Percentage(math.Round(float64(value)*100) / 100)
+	}
+	return result
+}
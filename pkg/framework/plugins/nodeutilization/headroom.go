@@ -0,0 +1,218 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"math"
+	"slices"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"sigs.k8s.io/descheduler/pkg/api"
+	nodeutil "sigs.k8s.io/descheduler/pkg/descheduler/node"
+	"sigs.k8s.io/descheduler/pkg/utils"
+)
+
+// platformHeadroom keeps track of the resources available in the destination
+// nodes, grouped by the platform (operating system and architecture) of the
+// nodes. an evicted pod is only accounted in the headroom of a platform it
+// can run on so rebalancing a fleet mixing architectures does not count the
+// nodes a pod can't run on as available capacity.
+type platformHeadroom struct {
+	available map[nodeutil.Platform]api.ReferencedResourceList
+	// podSlots holds the number of pods the destination nodes of each
+	// platform can still accept. they are tracked whether or not pods
+	// are among the resources the thresholds are set for.
+	podSlots     map[nodeutil.Platform]int64
+	destinations map[nodeutil.Platform][]*v1.Node
+	images       *nodeutil.ImagePlatforms
+}
+
+// newPlatformHeadroom assesses the resources available in the destination
+// nodes for each of their platforms. the images held by both the source and
+// the destination nodes tell what platforms the pods can run on.
+func newPlatformHeadroom(
+	sourceNodes, destinationNodes []NodeInfo, resourceNames []v1.ResourceName,
+) (*platformHeadroom, error) {
+	grouped := map[nodeutil.Platform][]NodeInfo{}
+	for _, node := range destinationNodes {
+		platform := nodeutil.NodePlatform(node.node)
+		grouped[platform] = append(grouped[platform], node)
+	}
+
+	headroom := &platformHeadroom{
+		available:    map[nodeutil.Platform]api.ReferencedResourceList{},
+		podSlots:     map[nodeutil.Platform]int64{},
+		destinations: map[nodeutil.Platform][]*v1.Node{},
+	}
+	for platform, nodes := range grouped {
+		available, err := assessAvailableResourceInNodes(nodes, resourceNames)
+		if err != nil {
+			return nil, err
+		}
+		headroom.available[platform] = available
+		headroom.podSlots[platform] = freePodSlots(nodes)
+		for _, node := range nodes {
+			headroom.destinations[platform] = append(headroom.destinations[platform], node.node)
+		}
+	}
+
+	var nodes []*v1.Node
+	for _, node := range append(slices.Clone(sourceNodes), destinationNodes...) {
+		nodes = append(nodes, node.node)
+	}
+	headroom.images = nodeutil.NewImagePlatforms(nodes)
+	return headroom, nil
+}
+
+// total returns the resources available across all the platforms.
+func (h *platformHeadroom) total() api.ReferencedResourceList {
+	total := api.ReferencedResourceList{}
+	for _, available := range h.available {
+		for name, quantity := range available {
+			if _, ok := total[name]; !ok {
+				total[name] = resource.NewQuantity(0, quantity.Format)
+			}
+			total[name].Add(*quantity)
+		}
+	}
+	return total
+}
+
+// freePodSlots returns the number of pods the nodes can still accept, their
+// allocatable pods minus the pods running on them. nodes not reporting their
+// allocatable pods are not constrained.
+func freePodSlots(nodes []NodeInfo) int64 {
+	var slots int64
+	for _, node := range nodes {
+		allocatable, ok := node.node.Status.Allocatable[v1.ResourcePods]
+		if !ok {
+			return math.MaxInt64
+		}
+		slots += max(0, allocatable.Value()-int64(len(node.allPods)))
+	}
+	return slots
+}
+
+// hasPodSlots tells if any destination node can still accept a pod.
+func (h *platformHeadroom) hasPodSlots() bool {
+	for _, slots := range h.podSlots {
+		if slots > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// takePodSlot accounts a pod evicted to the destination nodes of the
+// platform.
+func (h *platformHeadroom) takePodSlot(platform nodeutil.Platform) {
+	if h.podSlots[platform] > 0 {
+		h.podSlots[platform]--
+	}
+}
+
+// pick returns the platform the pod is accounted in once evicted from a node
+// of the source platform. the source platform is preferred, the remaining
+// ones are tried in alphabetical order. a platform is picked if the pod can
+// run on it, if its destination nodes can accept one more pod and hasRoom,
+// when provided, returns true for its available resources. false is returned
+// if no platform can be picked.
+func (h *platformHeadroom) pick(
+	pod *v1.Pod, source nodeutil.Platform, hasRoom func(api.ReferencedResourceList) bool,
+) (nodeutil.Platform, bool) {
+	for _, platform := range h.platforms(source) {
+		if !h.images.PodRunsOn(pod, source, platform) {
+			continue
+		}
+		if !h.selectorAllows(pod, platform) {
+			continue
+		}
+		if h.podSlots[platform] <= 0 {
+			continue
+		}
+		if hasRoom != nil && !hasRoom(h.available[platform]) {
+			continue
+		}
+		return platform, true
+	}
+	return nodeutil.Platform{}, false
+}
+
+// platforms returns the platforms of the destination nodes, the source one
+// first if any destination node runs it.
+func (h *platformHeadroom) platforms(source nodeutil.Platform) []nodeutil.Platform {
+	platforms := make([]nodeutil.Platform, 0, len(h.available))
+	for platform := range h.available {
+		platforms = append(platforms, platform)
+	}
+	slices.SortFunc(platforms, func(a, b nodeutil.Platform) int {
+		switch {
+		case a == source:
+			return -1
+		case b == source:
+			return 1
+		default:
+			return strings.Compare(a.String(), b.String())
+		}
+	})
+	return platforms
+}
+
+// selectorAllows tells if the pod node selector, or its required node
+// affinity, allows the pod on any of the destination nodes of the platform.
+// only pods constraining the operating system or the architecture of their
+// nodes are evaluated, the remaining node constraints are left to the
+// scheduler.
+func (h *platformHeadroom) selectorAllows(pod *v1.Pod, platform nodeutil.Platform) bool {
+	if !podConstrainsPlatform(pod) {
+		return true
+	}
+	for _, node := range h.destinations[platform] {
+		if ok, err := utils.PodMatchNodeSelector(pod, node); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// podConstrainsPlatform tells if the pod node selector, or its required node
+// affinity, refers to the operating system or the architecture labels.
+func podConstrainsPlatform(pod *v1.Pod) bool {
+	isPlatformLabel := func(key string) bool {
+		return key == v1.LabelOSStable || key == v1.LabelArchStable
+	}
+	for key := range pod.Spec.NodeSelector {
+		if isPlatformLabel(key) {
+			return true
+		}
+	}
+	affinity := pod.Spec.Affinity
+	if affinity == nil || affinity.NodeAffinity == nil || affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return false
+	}
+	for _, term := range affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		for _, requirement := range term.MatchExpressions {
+			if isPlatformLabel(requirement.Key) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -20,10 +20,13 @@ import (
 	"context"
 	"fmt"
 	"slices"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/flowcontrol"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/lru"
 	"sigs.k8s.io/descheduler/pkg/api"
 	"sigs.k8s.io/descheduler/pkg/descheduler/evictions"
 	nodeutil "sigs.k8s.io/descheduler/pkg/descheduler/node"
@@ -44,13 +47,20 @@ var _ frameworktypes.BalancePlugin = &HighNodeUtilization{}
 // can schedule according to its plugin. Note that CPU/Memory requests are used
 // to calculate nodes' utilization and not the actual resource usage.
 type HighNodeUtilization struct {
-	handle         frameworktypes.Handle
-	args           *HighNodeUtilizationArgs
-	podFilter      func(pod *v1.Pod) bool
-	criteria       []any
-	resourceNames  []v1.ResourceName
-	highThresholds api.ResourceThresholds
-	usageClient    usageClient
+	handle              frameworktypes.Handle
+	args                *HighNodeUtilizationArgs
+	podFilter           func(pod *v1.Pod) bool
+	criteria            []any
+	resourceNames       []v1.ResourceName
+	highThresholds      api.ResourceThresholds
+	usageClient         UsageClient
+	overcommit          []overcommitRule
+	podSorter           PodSorter
+	costProvider        NodeCostProvider
+	evictionRateLimiter flowcontrol.RateLimiter
+	classifications     nodeClasses
+	lastEvictions       *lru.Cache
+	utilizationDeltas   *pendingUtilizationDeltas
 }
 
 // NewHighNodeUtilization builds plugin from its arguments while passing a handle.
@@ -112,17 +122,55 @@ func NewHighNodeUtilization(
 		),
 	)
 
+	// the usage client is shared with other plugins of the profile that
+	// use the same configuration so the usage is only collected once.
+	usageClient, err := sharedUsageClientFor(
+		handle,
+		usageClientKey(requestedUsageClientType, resourceNames),
+		args.UsageCacheTTL.Duration,
+		func() (UsageClient, error) {
+			return newRequestedUsageClient(
+				resourceNames,
+				handle.GetPodsAssignedToNodeFunc(),
+				frameworktypes.DeviceAccountingFor(handle),
+				nodeUsageSnapshotsFor(handle, resourceNames),
+			), nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	overcommit, err := parseOvercommitRules(args.Overcommit)
+	if err != nil {
+		return nil, err
+	}
+
+	podSorter, err := podSorterFor(args.PodEvictionOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	costProvider, err := nodeCostProviderFor(args.NodeCost)
+	if err != nil {
+		return nil, err
+	}
+
 	return &HighNodeUtilization{
-		handle:         handle,
-		args:           args,
-		resourceNames:  resourceNames,
-		highThresholds: highThresholds,
-		criteria:       thresholdsToKeysAndValues(args.Thresholds),
-		podFilter:      podFilter,
-		usageClient: newRequestedUsageClient(
-			resourceNames,
-			handle.GetPodsAssignedToNodeFunc(),
-		),
+		handle:              handle,
+		args:                args,
+		resourceNames:       resourceNames,
+		highThresholds:      highThresholds,
+		criteria:            thresholdsToKeysAndValues(args.Thresholds),
+		podFilter:           podFilter,
+		usageClient:         usageClient,
+		overcommit:          overcommit,
+		podSorter:           podSorter,
+		costProvider:        costProvider,
+		evictionRateLimiter: evictionRateLimiterFor(handle, HighNodeUtilizationPluginName, args.EvictionRateLimit),
+		classifications:     classificationsFor(handle, HighNodeUtilizationPluginName),
+		lastEvictions:       lastEvictionsFor(handle),
+		utilizationDeltas:   utilizationDeltasFor(handle, HighNodeUtilizationPluginName),
 	}, nil
 }
 
@@ -135,7 +183,30 @@ func (h *HighNodeUtilization) Name() string {
 // utilized nodes. The goal here is to concentrate pods in fewer nodes so that
 // less nodes are used.
 func (h *HighNodeUtilization) Balance(ctx context.Context, nodes []*v1.Node) *frameworktypes.Status {
-	if err := h.usageClient.sync(ctx, nodes); err != nil {
+	summary := newBalanceSummary(HighNodeUtilizationPluginName)
+	defer summary.log()
+	defer summary.annotateSpan(ctx)
+	defer summary.writeRunStatus(ctx, h.handle.ClientSet(), h.args.RunStatus)
+	summary.sinks = decisionSinksFor(h.args.DecisionLog)
+	summary.budgets = newDisruptionBudgets(h.handle.SharedInformerFactory())
+
+	evictor := evictorForMode(h.handle.Evictor(), h.handle.ClientSet(), h.args.Mode)
+	if h.args.DryRun {
+		summary.dryRun = newDryRunEvictor(evictor)
+		evictor = summary.dryRun
+	}
+
+	// the nodes marked for scale down in previous runs that are still
+	// around are used as any other node once their marks are removed.
+	if !frameworktypes.IsDryRun(evictor) {
+		nodes = clearScaleDownMarks(
+			ctx, h.handle.ClientSet(), HighNodeUtilizationPluginName, h.args.ScaleDownHintsTTL, nodes,
+			h.handle.GetPodsAssignedToNodeFunc(), h.podFilter,
+		)
+	}
+
+	if err := h.usageClient.Sync(ctx, nodes); err != nil {
+		summary.failed(err)
 		return &frameworktypes.Status{
 			Err: fmt.Errorf("error getting node usage: %v", err),
 		}
@@ -145,6 +216,12 @@ func (h *HighNodeUtilization) Balance(ctx context.Context, nodes []*v1.Node) *fr
 	// here is based on this snapshot.
 	nodesMap, nodesUsageMap, podListMap := getNodeUsageSnapshot(nodes, h.usageClient)
 	capacities := referencedResourceListForNodesCapacity(nodes)
+	addDeviceCapacities(capacities, nodes, frameworktypes.DeviceAccountingFor(h.handle))
+	overcommitCapacities(capacities, nodes, h.overcommit)
+
+	// the usage of the nodes pods were evicted from on the previous cycle
+	// tells how accurate the eviction model was.
+	measureUtilizationDeltas(h.utilizationDeltas, nodesUsageMap, capacities, summary)
 
 	// node usages are not presented as percentages over the capacity.
 	// we need to normalize them to be able to compare them with the
@@ -154,14 +231,36 @@ func (h *HighNodeUtilization) Balance(ctx context.Context, nodes []*v1.Node) *fr
 		nodesUsageMap, capacities, h.args.Thresholds, h.highThresholds,
 	)
 
+	summary.assessed(usage, thresholds)
+
+	// underutilized nodes. nodes reporting a condition to drain are
+	// underutilized regardless of usage.
+	underutilized := func(nodeName string, usage, threshold api.ResourceThresholds) bool {
+		if isNodeToDrain(nodesMap[nodeName], h.args.NodeConditions) {
+			return true
+		}
+		return isNodeBelowWeightedThreshold(usage, threshold, h.args.ResourceWeights)
+	}
+
+	// with an hysteresis the nodes underutilized on the previous cycle
+	// stay underutilized until their usage goes above the thresholds by
+	// more than the hysteresis, so nodes hovering around the thresholds
+	// are not drained one cycle and used as destination the next one.
+	if h.args.Hysteresis > 0 {
+		underutilized = classifier.WithHysteresis(
+			underutilized,
+			exitThresholds(thresholds, 0, h.args.Hysteresis),
+			func(nodeName string) bool {
+				return wasClassified(h.classifications, nodeName, 0)
+			},
+		)
+	}
+
 	// classify nodes in two groups: underutilized and schedulable. we will
 	// later try to move pods from the first group to the second.
-	nodeGroups := classifier.Classify(
-		usage, thresholds,
-		// underutilized nodes.
-		func(nodeName string, usage, threshold api.ResourceThresholds) bool {
-			return isNodeBelowThreshold(usage, threshold)
-		},
+	nodeGroups := classifyNodes(
+		ctx, HighNodeUtilizationPluginName, usage, thresholds,
+		underutilized,
 		// schedulable nodes.
 		func(nodeName string, usage, threshold api.ResourceThresholds) bool {
 			if nodeutil.IsNodeUnschedulable(nodesMap[nodeName]) {
@@ -171,9 +270,27 @@ func (h *HighNodeUtilization) Balance(ctx context.Context, nodes []*v1.Node) *fr
 				)
 				return false
 			}
+			if nodeutil.IsNodeMarkedForDeletion(nodesMap[nodeName]) || nodeutil.IsNodeCandidateForDeletion(nodesMap[nodeName]) {
+				klog.V(2).InfoS(
+					"Node is marked for deletion by a node autoscaler",
+					"node", klog.KObj(nodesMap[nodeName]),
+				)
+				return false
+			}
+			if action := nodeConditionAction(nodesMap[nodeName], h.args.NodeConditions); action != "" {
+				klog.V(2).InfoS(
+					"Node reports a condition to avoid",
+					"node", klog.KObj(nodesMap[nodeName]),
+					"action", action,
+				)
+				return false
+			}
 			return true
 		},
 	)
+	if h.args.Hysteresis > 0 {
+		recordClassifications(h.classifications, nodeGroups)
+	}
 
 	// the nodeplugin package works by means of NodeInfo structures. these
 	// structures hold a series of information about the nodes. now that
@@ -183,7 +300,8 @@ func (h *HighNodeUtilization) Balance(ctx context.Context, nodes []*v1.Node) *fr
 	nodeInfos := make([][]NodeInfo, 2)
 	category := []string{"underutilized", "overutilized"}
 	for i := range nodeGroups {
-		for nodeName := range nodeGroups[i] {
+		for _, nodeName := range sortedNodeNames(nodeGroups[i]) {
+			summary.classified(nodeName, category[i])
 			klog.InfoS(
 				"Node has been classified",
 				"category", category[i],
@@ -198,15 +316,17 @@ func (h *HighNodeUtilization) Balance(ctx context.Context, nodes []*v1.Node) *fr
 					allPods: podListMap[nodeName],
 				},
 				available: capNodeCapacitiesToThreshold(
-					nodesMap[nodeName],
+					capacities[nodeName],
 					thresholds[nodeName][1],
 					h.resourceNames,
 				),
+				cost: nodeCost(h.costProvider, nodesMap[nodeName]),
 			})
 		}
 	}
 
 	lowNodes, schedulableNodes := nodeInfos[0], nodeInfos[1]
+	summary.underutilized, summary.overutilized = len(lowNodes), len(schedulableNodes)
 
 	klog.V(1).InfoS("Criteria for a node below target utilization", h.criteria...)
 	klog.V(1).InfoS("Number of underutilized nodes", "totalNumber", len(lowNodes))
@@ -237,6 +357,14 @@ func (h *HighNodeUtilization) Balance(ctx context.Context, nodes []*v1.Node) *fr
 		return nil
 	}
 
+	// nodes pods were recently evicted from are left alone until their
+	// cooldown expires, the scheduler needs time to place the pods.
+	lowNodes = nodesOutOfCooldown(h.lastEvictions, lowNodes, h.args.Cooldown, time.Now())
+	if len(lowNodes) == 0 {
+		klog.V(1).InfoS("All underutilized nodes are cooling down, nothing to do here")
+		return nil
+	}
+
 	// stops the eviction process if the total available capacity sage has
 	// dropped to zero - no more pods can be scheduled. this will signalize
 	// to stop if any of the available resources has dropped to zero.
@@ -249,22 +377,62 @@ func (h *HighNodeUtilization) Balance(ctx context.Context, nodes []*v1.Node) *fr
 		return true
 	}
 
-	// sorts the nodes by the usage in ascending order.
-	sortNodesByUsage(lowNodes, true)
-
-	evictPodsFromSourceNodes(
-		ctx,
-		h.args.EvictableNamespaces,
-		lowNodes,
-		schedulableNodes,
-		h.handle.Evictor(),
-		evictions.EvictOptions{StrategyName: HighNodeUtilizationPluginName},
-		h.podFilter,
-		h.resourceNames,
-		continueEvictionCond,
-		h.usageClient,
-		nil,
-	)
+	// sorts the nodes by the usage in ascending order, nodes about to be
+	// removed by a node autoscaler are drained first, followed by nodes
+	// reporting a condition to drain.
+	sortNodesByWeightedUsage(lowNodes, true, usage, h.args.ResourceWeights)
+	preferNodesToDrain(lowNodes, h.args.NodeConditions)
+	preferNodesMarkedForDeletion(lowNodes)
+
+	// keep the usage of the source nodes prior to any eviction so we can
+	// later compare the predicted and the achieved utilization drops.
+	preEvictionUsage := copyNodesUsage(lowNodes)
+
+	opts := &evictionOptions{
+		evictableNamespaces: h.args.EvictableNamespaces,
+		podEvictor:          evictor,
+		evictOptions:        evictions.EvictOptions{StrategyName: HighNodeUtilizationPluginName},
+		podFilter:           h.podFilter,
+		resourceNames:       h.resourceNames,
+		continueEviction:    continueEvictionCond,
+		usageClient:         h.usageClient,
+		limits:              h.args.EvictionLimits,
+		scoringStrategy:     h.args.ScoringStrategy,
+		evictionOrder:       h.args.EvictionOrder,
+		podSorter:           h.podSorter,
+		affinityAwareness:   h.args.AffinityAwareness,
+		fairnessPolicy:      h.args.FairnessPolicy,
+		rateLimiter:         h.evictionRateLimiter,
+		nodeIndexer:         h.handle.GetPodsAssignedToNodeFunc(),
+		devices:             frameworktypes.DeviceAccountingFor(h.handle),
+	}
+	placements := evictPodsFromSourceNodes(ctx, lowNodes, schedulableNodes, opts, summary)
+
+	if h.args.DryRun {
+		summary.projected(lowNodes, capacities)
+	}
+
+	if h.args.SchedulingHints && !frameworktypes.IsDryRun(evictor) {
+		publishSchedulingHints(ctx, h.handle.ClientSet(), placements)
+	}
+
+	// annotated pods are still running, their nodes are neither drained
+	// nor is their usage dropped yet.
+	if !frameworktypes.IsDryRun(evictor) && h.args.Mode != BalanceModeAnnotate {
+		recordUtilizationDeltas(h.utilizationDeltas, lowNodes, preEvictionUsage, capacities, summary)
+		markDrainedNodes(
+			ctx, h.handle.ClientSet(), HighNodeUtilizationPluginName, h.args.ScaleDownHints, lowNodes, h.podFilter, summary,
+		)
+	}
+	if !frameworktypes.IsDryRun(evictor) {
+		recordLastEvictions(ctx, h.handle.ClientSet(), h.lastEvictions, h.args.Cooldown, summary, time.Now())
+	}
+
+	// other plugins sharing the usage client must not rely on the usage
+	// collected before the evictions.
+	if summary.evicted > 0 {
+		invalidateUsageClient(h.usageClient)
+	}
 
 	return nil
 }
@@ -27,6 +27,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/fake"
 	core "k8s.io/client-go/testing"
+	"k8s.io/utils/ptr"
 
 	"sigs.k8s.io/descheduler/pkg/api"
 	"sigs.k8s.io/descheduler/pkg/descheduler/evictions"
@@ -49,6 +50,7 @@ func TestHighNodeUtilization(t *testing.T) {
 		name                string
 		thresholds          api.ResourceThresholds
 		evictionModes       []EvictionMode
+		evictionLimits      *api.EvictionLimits
 		nodes               []*v1.Node
 		pods                []*v1.Pod
 		expectedPodsEvicted uint
@@ -481,6 +483,27 @@ func TestHighNodeUtilization(t *testing.T) {
 			},
 			expectedPodsEvicted: 0,
 		},
+		{
+			name: "with total eviction limit",
+			thresholds: api.ResourceThresholds{
+				v1.ResourceCPU: 30,
+			},
+			evictionLimits: &api.EvictionLimits{
+				Total: ptr.To[uint](1),
+			},
+			nodes: []*v1.Node{
+				test.BuildTestNode(n1NodeName, 4000, 3000, 10, nil),
+				test.BuildTestNode(n2NodeName, 4000, 3000, 10, nil),
+				test.BuildTestNode(n3NodeName, 4000, 3000, 10, nil),
+			},
+			pods: []*v1.Pod{
+				test.BuildTestPod("p1", 400, 0, n1NodeName, test.SetRSOwnerRef),
+				test.BuildTestPod("p2", 400, 0, n2NodeName, test.SetRSOwnerRef),
+				// These won't be evicted.
+				test.BuildTestPod("p3", 2000, 0, n3NodeName, test.SetRSOwnerRef),
+			},
+			expectedPodsEvicted: 1,
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -524,8 +547,9 @@ func TestHighNodeUtilization(t *testing.T) {
 
 			plugin, err := NewHighNodeUtilization(
 				&HighNodeUtilizationArgs{
-					Thresholds:    testCase.thresholds,
-					EvictionModes: testCase.evictionModes,
+					Thresholds:     testCase.thresholds,
+					EvictionModes:  testCase.evictionModes,
+					EvictionLimits: testCase.evictionLimits,
 				},
 				handle,
 			)
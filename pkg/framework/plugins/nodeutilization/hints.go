@@ -0,0 +1,110 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+// PreferredNodesAnnotationKey is set on the owners of the evicted pods. Its
+// value is a comma separated list of the nodes the pods are expected to be
+// scheduled on. A scheduler plugin can use it to place the replacement pods.
+const PreferredNodesAnnotationKey = "descheduler.alpha.kubernetes.io/preferred-nodes"
+
+// ownerKey identifies the controller owning a pod.
+type ownerKey struct {
+	namespace string
+	kind      string
+	name      string
+}
+
+// publishSchedulingHints annotates the owners of the evicted pods with the
+// nodes the pods are expected to be scheduled on. only ReplicaSets and
+// StatefulSets are annotated, pods owned by other kinds are ignored. hints
+// are best effort, failures are logged and do not interrupt the process.
+func publishSchedulingHints(ctx context.Context, client clientset.Interface, placements []podPlacement) {
+	hints := map[ownerKey]sets.Set[string]{}
+	for _, placement := range placements {
+		owner := metav1.GetControllerOf(placement.pod)
+		if owner == nil || owner.APIVersion != appsv1.SchemeGroupVersion.String() {
+			continue
+		}
+		if owner.Kind != "ReplicaSet" && owner.Kind != "StatefulSet" {
+			continue
+		}
+		key := ownerKey{namespace: placement.pod.Namespace, kind: owner.Kind, name: owner.Name}
+		if _, ok := hints[key]; !ok {
+			hints[key] = sets.New[string]()
+		}
+		hints[key].Insert(placement.node)
+	}
+
+	for key, nodes := range hints {
+		if err := patchPreferredNodes(ctx, client, key, sets.List(nodes)); err != nil {
+			klog.ErrorS(
+				err, "unable to publish scheduling hint",
+				"kind", key.kind,
+				"owner", klog.KRef(key.namespace, key.name),
+			)
+			continue
+		}
+		klog.V(3).InfoS(
+			"Published scheduling hint",
+			"kind", key.kind,
+			"owner", klog.KRef(key.namespace, key.name),
+			"nodes", nodes,
+		)
+	}
+}
+
+// patchPreferredNodes sets the preferred nodes annotation on the owner.
+func patchPreferredNodes(ctx context.Context, client clientset.Interface, key ownerKey, nodes []string) error {
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"annotations": map[string]string{
+				PreferredNodesAnnotationKey: strings.Join(nodes, ","),
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	switch key.kind {
+	case "ReplicaSet":
+		_, err = client.AppsV1().ReplicaSets(key.namespace).Patch(
+			ctx, key.name, types.MergePatchType, patch, metav1.PatchOptions{},
+		)
+	case "StatefulSet":
+		_, err = client.AppsV1().StatefulSets(key.namespace).Patch(
+			ctx, key.name, types.MergePatchType, patch, metav1.PatchOptions{},
+		)
+	default:
+		err = fmt.Errorf("unsupported owner kind %s", key.kind)
+	}
+	return err
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/descheduler/test"
+)
+
+func TestPublishSchedulingHints(t *testing.T) {
+	ctx := context.Background()
+
+	ownedBy := func(kind, apiVersion, name string) func(*v1.Pod) {
+		return func(pod *v1.Pod) {
+			pod.ObjectMeta.OwnerReferences = []metav1.OwnerReference{
+				{Kind: kind, APIVersion: apiVersion, Name: name, Controller: ptr.To(true)},
+			}
+		}
+	}
+
+	rs := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "rs", Namespace: "default"}}
+	sts := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "sts", Namespace: "default"}}
+	client := fake.NewSimpleClientset(rs, sts)
+
+	placements := []podPlacement{
+		{pod: test.BuildTestPod("p1", 100, 0, "n1", ownedBy("ReplicaSet", "apps/v1", "rs")), node: "n3"},
+		{pod: test.BuildTestPod("p2", 100, 0, "n1", ownedBy("ReplicaSet", "apps/v1", "rs")), node: "n2"},
+		{pod: test.BuildTestPod("p3", 100, 0, "n1", ownedBy("ReplicaSet", "apps/v1", "rs")), node: "n3"},
+		{pod: test.BuildTestPod("p4", 100, 0, "n1", ownedBy("StatefulSet", "apps/v1", "sts")), node: "n2"},
+		{pod: test.BuildTestPod("p5", 100, 0, "n1", ownedBy("Job", "batch/v1", "job")), node: "n2"},
+		{pod: test.BuildTestPod("p6", 100, 0, "n1", nil), node: "n2"},
+	}
+	publishSchedulingHints(ctx, client, placements)
+
+	rs, err := client.AppsV1().ReplicaSets("default").Get(ctx, "rs", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unable to get replicaset: %v", err)
+	}
+	if hint := rs.Annotations[PreferredNodesAnnotationKey]; hint != "n2,n3" {
+		t.Errorf("expected replicaset hint to be %q, got %q", "n2,n3", hint)
+	}
+
+	sts, err = client.AppsV1().StatefulSets("default").Get(ctx, "sts", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unable to get statefulset: %v", err)
+	}
+	if hint := sts.Annotations[PreferredNodesAnnotationKey]; hint != "n2" {
+		t.Errorf("expected statefulset hint to be %q, got %q", "n2", hint)
+	}
+
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "patch" && action.GetResource().Resource == "jobs" {
+			t.Errorf("expected jobs not to be annotated")
+		}
+	}
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"k8s.io/utils/lru"
+
+	"sigs.k8s.io/descheduler/pkg/api"
+	frameworktypes "sigs.k8s.io/descheduler/pkg/framework/types"
+)
+
+// nodeStateTTL is how long the state the plugins keep about the nodes across
+// descheduling cycles is kept once the plugins were last built.
+const nodeStateTTL = 24 * time.Hour
+
+// sharedObject returns the object stored under the key in the shared cache,
+// creating it if missing. the plugins are created again on every cycle, the
+// state they keep across cycles lives in the cache the descheduler owns.
+func sharedObject[T any](handle frameworktypes.Handle, key string, create func() T) T {
+	cache := frameworktypes.SharedCacheFor(handle)
+	obj, ok := cache.Get(key)
+	if !ok {
+		obj = create()
+	}
+	cache.Set(key, obj, nodeStateTTL)
+	return obj.(T)
+}
+
+// sharedLRU returns the LRU cache stored under the key in the shared cache,
+// creating it if missing.
+func sharedLRU(handle frameworktypes.Handle, key string, size int) *lru.Cache {
+	return sharedObject(handle, key, func() *lru.Cache { return lru.New(size) })
+}
+
+// nodeClasses holds the class, i.e. the index of the classifier, every node
+// was classified in on the previous descheduling cycle.
+type nodeClasses map[string]int
+
+// classificationsFor returns the classes the nodes were classified in by the
+// plugin of the profile of the handle on the previous descheduling cycle.
+func classificationsFor(handle frameworktypes.Handle, plugin string) nodeClasses {
+	return sharedObject(
+		handle,
+		fmt.Sprintf("nodeutilization/classifications/%s/%s", frameworktypes.ProfileNameFor(handle), plugin),
+		func() nodeClasses { return nodeClasses{} },
+	)
+}
+
+// wasClassified tells if the node was classified in the class, i.e. the
+// index of its classifier, on the previous descheduling cycle.
+func wasClassified(classifications nodeClasses, node string, class int) bool {
+	value, ok := classifications[node]
+	return ok && value == class
+}
+
+// recordClassifications replaces the recorded classes with the classes of
+// the classified nodes, the nodes no classifier matched, or no longer in the
+// cluster, are forgotten.
+func recordClassifications[V any](classifications nodeClasses, groups []map[string]V) {
+	clear(classifications)
+	for class, group := range groups {
+		for node := range group {
+			if _, ok := classifications[node]; !ok {
+				classifications[node] = class
+			}
+		}
+	}
+}
+
+// exitThresholds returns the thresholds at the position shifted by delta,
+// kept within the [0; 100] range. nodes leave the class they were
+// classified in once their usage crosses these thresholds.
+func exitThresholds(
+	thresholds map[string][]api.ResourceThresholds, position int, delta api.Percentage,
+) map[string]api.ResourceThresholds {
+	result := map[string]api.ResourceThresholds{}
+	for node, nodeThresholds := range thresholds {
+		if len(nodeThresholds) <= position {
+			continue
+		}
+		shifted := api.ResourceThresholds{}
+		for name, value := range nodeThresholds[position] {
+			shifted[name] = min(max(value+delta, MinResourcePercentage), MaxResourcePercentage)
+		}
+		result[node] = shifted
+	}
+	return result
+}
+
+// heldThresholds returns the thresholds the nodes are held to. nodes
+// classified on the previous cycle are held to the exit thresholds of their
+// class, at the position of the class, the remaining ones to the provided
+// thresholds.
+func heldThresholds(
+	classifications nodeClasses,
+	thresholds map[string][]api.ResourceThresholds,
+	exits []map[string]api.ResourceThresholds,
+) map[string][]api.ResourceThresholds {
+	result := map[string][]api.ResourceThresholds{}
+	for node, nodeThresholds := range thresholds {
+		result[node] = nodeThresholds
+		for class, exit := range exits {
+			if _, ok := exit[node]; !ok || !wasClassified(classifications, node, class) {
+				continue
+			}
+			held := slices.Clone(nodeThresholds)
+			held[class] = exit[node]
+			result[node] = held
+			break
+		}
+	}
+	return result
+}
@@ -0,0 +1,236 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/descheduler/pkg/api"
+	frameworkfake "sigs.k8s.io/descheduler/pkg/framework/fake"
+	"sigs.k8s.io/descheduler/pkg/framework/plugins/defaultevictor"
+	frameworktesting "sigs.k8s.io/descheduler/pkg/framework/testing"
+	frameworktypes "sigs.k8s.io/descheduler/pkg/framework/types"
+	"sigs.k8s.io/descheduler/test"
+)
+
+func TestRecordClassifications(t *testing.T) {
+	classifications := nodeClasses{}
+	recordClassifications(classifications, []map[string]int{
+		{"n1": 1},
+		{"n2": 1},
+	})
+	for _, tc := range []struct {
+		node     string
+		class    int
+		expected bool
+	}{
+		{node: "n1", class: 0, expected: true},
+		{node: "n1", class: 1, expected: false},
+		{node: "n2", class: 1, expected: true},
+		{node: "n3", class: 0, expected: false},
+		{node: "n3", class: 1, expected: false},
+	} {
+		if classified := wasClassified(classifications, tc.node, tc.class); classified != tc.expected {
+			t.Errorf("Expected %v in class %v: %v, got %v", tc.node, tc.class, tc.expected, classified)
+		}
+	}
+
+	// n1 is no longer classified.
+	recordClassifications(classifications, []map[string]int{{}, {"n2": 1}})
+	if wasClassified(classifications, "n1", 0) {
+		t.Errorf("Expected n1 to be forgotten")
+	}
+	if len(classifications) != 1 {
+		t.Errorf("Expected only n2 to be kept, got %v", classifications)
+	}
+}
+
+func TestClassificationsFor(t *testing.T) {
+	cache := frameworktypes.NewSharedCache()
+	handle := func(profile string) frameworktypes.Handle {
+		return &frameworkfake.HandleImpl{SharedCacheImpl: cache, ProfileNameImpl: profile}
+	}
+
+	// the plugins are built again on every cycle.
+	classificationsFor(handle("p1"), LowNodeUtilizationPluginName)["n1"] = 1
+	if !wasClassified(classificationsFor(handle("p1"), LowNodeUtilizationPluginName), "n1", 1) {
+		t.Errorf("Expected the classifications to be kept across cycles")
+	}
+	if wasClassified(classificationsFor(handle("p2"), LowNodeUtilizationPluginName), "n1", 1) {
+		t.Errorf("Expected the classifications to be kept per profile")
+	}
+	if wasClassified(classificationsFor(handle("p1"), HighNodeUtilizationPluginName), "n1", 1) {
+		t.Errorf("Expected the classifications to be kept per plugin")
+	}
+}
+
+func TestExitThresholds(t *testing.T) {
+	thresholds := map[string][]api.ResourceThresholds{
+		"n1": {{v1.ResourceCPU: 20, v1.ResourceMemory: 5}, {v1.ResourceCPU: 50, v1.ResourceMemory: 95}},
+	}
+
+	for _, tc := range []struct {
+		name     string
+		position int
+		delta    api.Percentage
+		expected map[string]api.ResourceThresholds
+	}{
+		{
+			name:     "low thresholds raised",
+			position: 0,
+			delta:    10,
+			expected: map[string]api.ResourceThresholds{"n1": {v1.ResourceCPU: 30, v1.ResourceMemory: 15}},
+		},
+		{
+			name:     "high thresholds lowered",
+			position: 1,
+			delta:    -10,
+			expected: map[string]api.ResourceThresholds{"n1": {v1.ResourceCPU: 40, v1.ResourceMemory: 85}},
+		},
+		{
+			name:     "thresholds kept within range",
+			position: 1,
+			delta:    10,
+			expected: map[string]api.ResourceThresholds{"n1": {v1.ResourceCPU: 60, v1.ResourceMemory: 100}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if result := exitThresholds(thresholds, tc.position, tc.delta); !reflect.DeepEqual(result, tc.expected) {
+				t.Errorf("Expected %v, got %v", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestLowNodeUtilizationHysteresis(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		hysteresis api.Percentage
+		expected   uint
+	}{
+		{
+			name:     "no hysteresis",
+			expected: 0,
+		},
+		{
+			name:       "node stays overutilized",
+			hysteresis: 10,
+			expected:   1,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			nodes := []*v1.Node{
+				test.BuildTestNode("n1", 4000, 3000, 10, nil),
+				test.BuildTestNode("n2", 4000, 3000, 10, nil),
+			}
+			objs := []runtime.Object{nodes[0], nodes[1]}
+			for i := 0; i < 5; i++ {
+				objs = append(objs, test.BuildTestPod(fmt.Sprintf("n1-p%d", i), 400, 0, "n1", test.SetRSOwnerRef))
+			}
+			objs = append(objs, test.BuildTestPod("n2-p0", 400, 0, "n2", test.SetRSOwnerRef))
+
+			handle, podEvictor, err := frameworktesting.InitFrameworkHandle(
+				ctx,
+				fake.NewSimpleClientset(objs...),
+				nil,
+				defaultevictor.DefaultEvictorArgs{},
+				func(pods []*v1.Pod) {
+					sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+				},
+			)
+			if err != nil {
+				t.Fatalf("Unable to initialize a framework handle: %v", err)
+			}
+
+			plugin, err := NewLowNodeUtilization(&LowNodeUtilizationArgs{
+				Thresholds:       api.ResourceThresholds{v1.ResourceCPU: 30},
+				TargetThresholds: api.ResourceThresholds{v1.ResourceCPU: 55},
+				Hysteresis:       tc.hysteresis,
+			}, handle)
+			if err != nil {
+				t.Fatalf("Unable to initialize the plugin: %v", err)
+			}
+
+			// n1 was classified as overutilized on the previous cycle,
+			// its cpu usage is 50% now, below the target threshold but
+			// above the target threshold minus the hysteresis.
+			classificationsFor(handle, LowNodeUtilizationPluginName)["n1"] = 1
+			if status := plugin.(frameworktypes.BalancePlugin).Balance(ctx, nodes); status != nil && status.Err != nil {
+				t.Fatalf("Unexpected error: %v", status.Err)
+			}
+			if evicted := podEvictor.TotalEvicted(); evicted != tc.expected {
+				t.Errorf("Expected %v pods to be evicted, got %v", tc.expected, evicted)
+			}
+		})
+	}
+}
+
+func TestLowNodeUtilizationHysteresisSkippedCycle(t *testing.T) {
+	ctx := context.Background()
+
+	nodes := []*v1.Node{
+		test.BuildTestNode("n1", 4000, 3000, 10, nil),
+		test.BuildTestNode("n2", 4000, 3000, 10, nil),
+	}
+	objs := []runtime.Object{
+		nodes[0],
+		nodes[1],
+		test.BuildTestPod("n1-p0", 400, 0, "n1", test.SetRSOwnerRef),
+		test.BuildTestPod("n2-p0", 400, 0, "n2", test.SetRSOwnerRef),
+	}
+
+	handle, _, err := frameworktesting.InitFrameworkHandle(
+		ctx,
+		fake.NewSimpleClientset(objs...),
+		nil,
+		defaultevictor.DefaultEvictorArgs{},
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("Unable to initialize a framework handle: %v", err)
+	}
+
+	plugin, err := NewLowNodeUtilization(&LowNodeUtilizationArgs{
+		Thresholds:       api.ResourceThresholds{v1.ResourceCPU: 30},
+		TargetThresholds: api.ResourceThresholds{v1.ResourceCPU: 55},
+		Hysteresis:       10,
+	}, handle)
+	if err != nil {
+		t.Fatalf("Unable to initialize the plugin: %v", err)
+	}
+
+	// n1 was classified as overutilized on the previous cycle, its cpu
+	// usage dropped to 10% since, no node is above the target thresholds
+	// and the cycle is skipped.
+	classificationsFor(handle, LowNodeUtilizationPluginName)["n1"] = 1
+	if status := plugin.(frameworktypes.BalancePlugin).Balance(ctx, nodes); status != nil && status.Err != nil {
+		t.Fatalf("Unexpected error: %v", status.Err)
+	}
+	if wasClassified(classificationsFor(handle, LowNodeUtilizationPluginName), "n1", 1) {
+		t.Errorf("Expected n1 to no longer be recorded as overutilized")
+	}
+}
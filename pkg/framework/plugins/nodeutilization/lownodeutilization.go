@@ -19,15 +19,25 @@ package nodeutilization
 import (
 	"context"
 	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/flowcontrol"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/lru"
 
+	"sigs.k8s.io/descheduler/metrics"
 	"sigs.k8s.io/descheduler/pkg/api"
 	"sigs.k8s.io/descheduler/pkg/descheduler/evictions"
 	nodeutil "sigs.k8s.io/descheduler/pkg/descheduler/node"
 	podutil "sigs.k8s.io/descheduler/pkg/descheduler/pod"
+	"sigs.k8s.io/descheduler/pkg/descheduler/vpa"
 	"sigs.k8s.io/descheduler/pkg/framework/plugins/nodeutilization/classifier"
 	"sigs.k8s.io/descheduler/pkg/framework/plugins/nodeutilization/normalizer"
 	frameworktypes "sigs.k8s.io/descheduler/pkg/framework/types"
@@ -43,14 +53,27 @@ var _ frameworktypes.BalancePlugin = &LowNodeUtilization{}
 // nodes. Note that CPU/Memory requests are used to calculate nodes'
 // utilization and not the actual resource usage.
 type LowNodeUtilization struct {
-	handle                frameworktypes.Handle
-	args                  *LowNodeUtilizationArgs
-	podFilter             func(pod *v1.Pod) bool
+	handle    frameworktypes.Handle
+	args      *LowNodeUtilizationArgs
+	podFilter func(pod *v1.Pod) bool
+	// thresholds and targetThresholds include the resources whose
+	// thresholds are quantities, resolved for every node on Balance.
+	thresholds            api.ResourceThresholds
+	targetThresholds      api.ResourceThresholds
 	underCriteria         []any
 	overCriteria          []any
 	resourceNames         []v1.ResourceName
 	extendedResourceNames []v1.ResourceName
-	usageClient           usageClient
+	usageClient           UsageClient
+	overcommit            []overcommitRule
+	podSorter             PodSorter
+	costProvider          NodeCostProvider
+	nodePools             []nodePool
+	evictionRateLimiter   flowcontrol.RateLimiter
+	podResizer            PodResizer
+	classifications       nodeClasses
+	lastEvictions         *lru.Cache
+	utilizationDeltas     *pendingUtilizationDeltas
 }
 
 // NewLowNodeUtilization builds plugin from its arguments while passing a
@@ -70,7 +93,9 @@ func NewLowNodeUtilization(
 	// resourceNames holds a list of resources for which the user has
 	// provided thresholds for. extendedResourceNames holds those as well
 	// as cpu, memory and pods if no prometheus collection is used.
-	resourceNames := getResourceNames(args.Thresholds)
+	thresholds := thresholdsWithQuantities(args.Thresholds, args.ThresholdQuantities, MinResourcePercentage)
+	targetThresholds := thresholdsWithQuantities(args.TargetThresholds, args.TargetThresholdQuantities, MaxResourcePercentage)
+	resourceNames := nodePoolsResourceNames(thresholds, args.NodePools)
 	extendedResourceNames := resourceNames
 
 	// if we are using prometheus we need to validate we have everything we
@@ -81,6 +106,10 @@ func NewLowNodeUtilization(
 		if err := validatePrometheusMetricsUtilization(args); err != nil {
 			return nil, err
 		}
+	} else if metrics != nil && (metrics.Source == api.CustomMetrics || metrics.Source == api.OpenTelemetryMetrics) {
+		if err := validateMetricResourceThresholds(args); err != nil {
+			return nil, err
+		}
 	} else {
 		extendedResourceNames = uniquifyResourceNames(
 			append(
@@ -104,25 +133,93 @@ func NewLowNodeUtilization(
 	// different way provides its own "usageClient". here we make sure we
 	// have the correct one or an error is triggered. XXX MetricsServer is
 	// deprecated, removed once dropped.
-	var usageClient usageClient = newRequestedUsageClient(
-		extendedResourceNames, handle.GetPodsAssignedToNodeFunc(),
-	)
+	// usage clients are shared with other plugins of the profile that use
+	// the same configuration so the usage is only collected once.
+	requestedClient := func() (UsageClient, error) {
+		return sharedUsageClientFor(
+			handle,
+			usageClientKey(requestedUsageClientType, extendedResourceNames),
+			args.UsageCacheTTL.Duration,
+			func() (UsageClient, error) {
+				return newRequestedUsageClient(
+					extendedResourceNames,
+					handle.GetPodsAssignedToNodeFunc(),
+					frameworktypes.DeviceAccountingFor(handle),
+					nodeUsageSnapshotsFor(handle, extendedResourceNames),
+				), nil
+			},
+		)
+	}
+
+	var client UsageClient
 	if metrics != nil {
-		usageClient, err = usageClientForMetrics(args, handle, extendedResourceNames)
+		client, err = usageClientForMetrics(metrics, args.UsageCacheTTL.Duration, handle, extendedResourceNames)
+	} else {
+		client, err = requestedClient()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// the actual utilization can be blended with the utilization
+	// computed from the pod requests.
+	if metrics != nil && metrics.Weights != nil {
+		requested, err := requestedClient()
 		if err != nil {
 			return nil, err
 		}
+		client = newCompositeUsageClient(
+			[]UsageClient{requested, client},
+			[]float64{metrics.Weights.Requests, metrics.Weights.Metrics},
+		)
+	}
+
+	overcommit, err := parseOvercommitRules(args.Overcommit)
+	if err != nil {
+		return nil, err
+	}
+
+	podSorter, err := podSorterFor(args.PodEvictionOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	costProvider, err := nodeCostProviderFor(args.NodeCost)
+	if err != nil {
+		return nil, err
+	}
+
+	nodePools, err := parseNodePools(args.NodePools)
+	if err != nil {
+		return nil, err
 	}
 
 	return &LowNodeUtilization{
-		handle:                handle,
-		args:                  args,
-		underCriteria:         thresholdsToKeysAndValues(args.Thresholds),
-		overCriteria:          thresholdsToKeysAndValues(args.TargetThresholds),
+		handle:           handle,
+		args:             args,
+		thresholds:       thresholds,
+		targetThresholds: targetThresholds,
+		underCriteria: append(
+			thresholdsToKeysAndValues(args.Thresholds),
+			quantitiesToKeysAndValues(args.ThresholdQuantities)...,
+		),
+		overCriteria: append(
+			thresholdsToKeysAndValues(args.TargetThresholds),
+			quantitiesToKeysAndValues(args.TargetThresholdQuantities)...,
+		),
 		resourceNames:         resourceNames,
 		extendedResourceNames: extendedResourceNames,
 		podFilter:             podFilter,
-		usageClient:           usageClient,
+		usageClient:           client,
+		overcommit:            overcommit,
+		podSorter:             podSorter,
+		costProvider:          costProvider,
+		nodePools:             nodePools,
+		evictionRateLimiter:   evictionRateLimiterFor(handle, LowNodeUtilizationPluginName, args.EvictionRateLimit),
+		podResizer:            &clientPodResizer{client: handle.ClientSet()},
+		classifications:       classificationsFor(handle, LowNodeUtilizationPluginName),
+		lastEvictions:         lastEvictionsFor(handle),
+		utilizationDeltas:     utilizationDeltasFor(handle, LowNodeUtilizationPluginName),
 	}, nil
 }
 
@@ -135,7 +232,33 @@ func (l *LowNodeUtilization) Name() string {
 // utilized nodes to under utilized nodes. The goal here is to evenly
 // distribute pods across nodes.
 func (l *LowNodeUtilization) Balance(ctx context.Context, nodes []*v1.Node) *frameworktypes.Status {
-	if err := l.usageClient.sync(ctx, nodes); err != nil {
+	summary := newBalanceSummary(LowNodeUtilizationPluginName)
+	defer summary.log()
+	defer summary.annotateSpan(ctx)
+	defer summary.writeRunStatus(ctx, l.handle.ClientSet(), l.args.RunStatus)
+	summary.sinks = decisionSinksFor(l.args.DecisionLog)
+	summary.budgets = newDisruptionBudgets(l.handle.SharedInformerFactory())
+
+	evictor := evictorForMode(l.handle.Evictor(), l.handle.ClientSet(), l.args.Mode)
+	if l.args.DryRun {
+		summary.dryRun = newDryRunEvictor(evictor)
+		evictor = summary.dryRun
+	}
+
+	// when balancing within topology domains the nodes outside of any
+	// domain are left out altogether.
+	if l.args.TopologyKey != "" {
+		nodes = nodesInTopologyDomains(nodes, l.args.TopologyKey)
+	}
+
+	// the nodes annotated as not to be disrupted in previous runs get
+	// the annotation removed once expired.
+	if !frameworktypes.IsDryRun(evictor) {
+		clearDoNotDisruptHints(ctx, l.handle.ClientSet(), nodes, time.Now())
+	}
+
+	if err := l.usageClient.Sync(ctx, nodes); err != nil {
+		summary.failed(err)
 		return &frameworktypes.Status{
 			Err: fmt.Errorf("error getting node usage: %v", err),
 		}
@@ -146,38 +269,88 @@ func (l *LowNodeUtilization) Balance(ctx context.Context, nodes []*v1.Node) *fra
 	// underutilized or overutilized.
 	nodesMap, nodesUsageMap, podListMap := getNodeUsageSnapshot(nodes, l.usageClient)
 	capacities := referencedResourceListForNodesCapacity(nodes)
+	addDeviceCapacities(capacities, nodes, frameworktypes.DeviceAccountingFor(l.handle))
+	overcommitCapacities(capacities, nodes, l.overcommit)
+
+	// the usage of the nodes pods were evicted from on the previous cycle
+	// tells how accurate the eviction model was.
+	measureUtilizationDeltas(l.utilizationDeltas, nodesUsageMap, capacities, summary)
 
 	// usage, by default, is exposed in absolute values. we need to normalize
 	// them (convert them to percentages) to be able to compare them with the
 	// user provided thresholds. thresholds are already provided in percentage
 	// in the <0; 100> interval.
-	var usage map[string]api.ResourceThresholds
-	var thresholds map[string][]api.ResourceThresholds
-	if l.args.UseDeviationThresholds {
+	assess, filter := usageAssessor(assessNodesUsagesAndStaticThresholds), true
+	switch {
+	case l.args.UseDeviationThresholds:
 		// here the thresholds provided by the user represent
 		// deviations from the average so we need to treat them
 		// differently. when calculating the average we only
 		// need to consider the resources for which the user
-		// has provided thresholds.
-		usage, thresholds = assessNodesUsagesAndRelativeThresholds(
-			filterResourceNames(nodesUsageMap, l.resourceNames),
-			capacities,
-			l.args.Thresholds,
-			l.args.TargetThresholds,
-		)
-	} else {
-		usage, thresholds = assessNodesUsagesAndStaticThresholds(
-			nodesUsageMap,
-			capacities,
-			l.args.Thresholds,
-			l.args.TargetThresholds,
+		// has provided thresholds. the average can be replaced
+		// by an aggregate outlier nodes do not skew.
+		assess = relativeThresholdsAssessor(l.args.DeviationCenter, l.args.DeviationTrimPercentage)
+	case l.args.UseStdDeviationThresholds:
+		// same as above but the thresholds provided by the user
+		// are the number of standard deviations from the average.
+		assess = assessNodesUsagesAndStdDeviationThresholds
+	case l.args.UsePercentileThresholds:
+		// here the thresholds provided by the user are the
+		// percentiles of the nodes usage.
+		assess = assessNodesUsagesAndPercentileThresholds
+	default:
+		filter = false
+	}
+
+	// nodes are assessed per node pool, with the thresholds of the pool,
+	// and, when balancing within topology domains, per domain. each group
+	// has its own average and percentiles.
+	usage, thresholds := assessNodesUsagesPerGroup(
+		thresholdsGroups(nodes, l.nodePools, l.thresholds, l.targetThresholds, l.args.TopologyKey),
+		assess,
+		nodesUsageMap,
+		capacities,
+		filter,
+	)
+	resolveThresholdQuantities(thresholds, capacities, l.args.ThresholdQuantities, l.args.TargetThresholdQuantities)
+
+	summary.assessed(usage, thresholds)
+
+	// with an hysteresis the nodes classified on the previous cycle stay
+	// in their class until their usage crosses the thresholds by more
+	// than the hysteresis, so nodes hovering around a threshold do not
+	// flap. overutilized nodes are then relieved down to the target
+	// thresholds minus the hysteresis.
+	var exits []map[string]api.ResourceThresholds
+	held := thresholds
+	if l.args.Hysteresis > 0 {
+		exits = []map[string]api.ResourceThresholds{
+			exitThresholds(thresholds, 0, l.args.Hysteresis),
+			exitThresholds(thresholds, 1, -l.args.Hysteresis),
+		}
+		held = heldThresholds(l.classifications, thresholds, exits)
+	}
+
+	// if even the most utilized node in the cluster is not above the
+	// lowest target threshold there is nothing to balance. the whole
+	// classification and eviction pipeline can be skipped. no node is
+	// overutilized then, the nodes classified on the previous cycle are
+	// forgotten so they are not held to the exit thresholds next cycle.
+	if noNodeAboveThresholds(usage, held, 1) && !anyNodeToDrain(nodes, l.args.NodeConditions) {
+		clear(l.classifications)
+		klog.V(1).InfoS(
+			"No node can be above target utilization, skipping",
+			"plugin", LowNodeUtilizationPluginName,
 		)
+		metrics.BalanceSkipped.With(
+			map[string]string{"strategy": LowNodeUtilizationPluginName},
+		).Inc()
+		return nil
 	}
 
 	// classify nodes in under and over utilized. we will later try to move
 	// pods from the overutilized nodes to the underutilized ones.
-	nodeGroups := classifier.Classify(
-		usage, thresholds,
+	classifiers := []classifier.Classifier[string, api.ResourceThresholds]{
 		// underutilization criteria processing. nodes that are
 		// underutilized but aren't schedulable are ignored.
 		func(nodeName string, usage, threshold api.ResourceThresholds) bool {
@@ -188,13 +361,47 @@ func (l *LowNodeUtilization) Balance(ctx context.Context, nodes []*v1.Node) *fra
 				)
 				return false
 			}
-			return isNodeBelowThreshold(usage, threshold)
+			if nodeutil.IsNodeMarkedForDeletion(nodesMap[nodeName]) || nodeutil.IsNodeCandidateForDeletion(nodesMap[nodeName]) {
+				klog.V(2).InfoS(
+					"Node is marked for deletion by a node autoscaler, thus not considered as underutilized",
+					"node", klog.KObj(nodesMap[nodeName]),
+				)
+				return false
+			}
+			if action := nodeConditionAction(nodesMap[nodeName], l.args.NodeConditions); action != "" {
+				klog.V(2).InfoS(
+					"Node reports a condition to avoid, thus not considered as underutilized",
+					"node", klog.KObj(nodesMap[nodeName]),
+					"action", action,
+				)
+				return false
+			}
+			return isNodeBelowWeightedThreshold(usage, threshold, l.args.ResourceWeights)
 		},
-		// overutilization criteria evaluation.
+		// overutilization criteria evaluation. nodes reporting a
+		// condition to drain are overutilized regardless of usage.
 		func(nodeName string, usage, threshold api.ResourceThresholds) bool {
-			return isNodeAboveThreshold(usage, threshold)
+			if isNodeToDrain(nodesMap[nodeName], l.args.NodeConditions) {
+				return true
+			}
+			return isNodeAboveWeightedThreshold(usage, threshold, l.args.ResourceWeights)
 		},
-	)
+	}
+
+	for i, exit := range exits {
+		classifiers[i] = classifier.WithHysteresis(
+			classifiers[i],
+			exit,
+			func(nodeName string) bool {
+				return wasClassified(l.classifications, nodeName, i)
+			},
+		)
+	}
+
+	nodeGroups := classifyNodes(ctx, LowNodeUtilizationPluginName, usage, thresholds, classifiers...)
+	if l.args.Hysteresis > 0 {
+		recordClassifications(l.classifications, nodeGroups)
+	}
 
 	// the nodeutilization package was designed to work with NodeInfo
 	// structs. these structs holds information about how utilized a node
@@ -204,8 +411,9 @@ func (l *LowNodeUtilization) Balance(ctx context.Context, nodes []*v1.Node) *fra
 	categories := []string{"underutilized", "overutilized"}
 	classifiedNodes := map[string]bool{}
 	for i := range nodeGroups {
-		for nodeName := range nodeGroups[i] {
+		for _, nodeName := range sortedNodeNames(nodeGroups[i]) {
 			classifiedNodes[nodeName] = true
+			summary.classified(nodeName, categories[i])
 
 			klog.InfoS(
 				"Node has been classified",
@@ -222,27 +430,29 @@ func (l *LowNodeUtilization) Balance(ctx context.Context, nodes []*v1.Node) *fra
 					allPods: podListMap[nodeName],
 				},
 				available: capNodeCapacitiesToThreshold(
-					nodesMap[nodeName],
-					thresholds[nodeName][1],
+					capacities[nodeName],
+					held[nodeName][1],
 					l.extendedResourceNames,
 				),
+				cost: nodeCost(l.costProvider, nodesMap[nodeName]),
 			})
 		}
 	}
 
 	// log nodes that are appropriately utilized.
-	for nodeName := range nodesMap {
-		if !classifiedNodes[nodeName] {
+	for _, node := range nodes {
+		if !classifiedNodes[node.Name] {
 			klog.InfoS(
 				"Node is appropriately utilized",
-				"node", klog.KObj(nodesMap[nodeName]),
-				"usage", nodesUsageMap[nodeName],
-				"usagePercentage", normalizer.Round(usage[nodeName]),
+				"node", klog.KObj(node),
+				"usage", nodesUsageMap[node.Name],
+				"usagePercentage", normalizer.Round(usage[node.Name]),
 			)
 		}
 	}
 
 	lowNodes, highNodes := nodeInfos[0], nodeInfos[1]
+	summary.underutilized, summary.overutilized = len(lowNodes), len(highNodes)
 
 	// log messages for nodes with low and high utilization
 	klog.V(1).InfoS("Criteria for a node under utilization", l.underCriteria...)
@@ -276,42 +486,132 @@ func (l *LowNodeUtilization) Balance(ctx context.Context, nodes []*v1.Node) *fra
 		return nil
 	}
 
-	// this is a stop condition for the eviction process. we stop as soon
-	// as the node usage drops below the threshold.
-	continueEvictionCond := func(nodeInfo NodeInfo, totalAvailableUsage api.ReferencedResourceList) bool {
-		if !isNodeAboveTargetUtilization(nodeInfo.NodeUsage, nodeInfo.available) {
-			return false
+	// nodes pods were recently evicted from are left alone until their
+	// cooldown expires, the scheduler needs time to rebalance the cluster.
+	highNodes = nodesOutOfCooldown(l.lastEvictions, highNodes, l.args.Cooldown, time.Now())
+	if len(highNodes) == 0 {
+		klog.V(1).InfoS("All overutilized nodes are cooling down, nothing to do here")
+		return nil
+	}
+
+	// sort the nodes by the usage in descending order, nodes about to be
+	// removed by a node autoscaler are drained first, followed by nodes
+	// reporting a condition to drain.
+	sortNodesByWeightedUsage(highNodes, false, usage, l.args.ResourceWeights)
+	preferNodesToDrain(highNodes, l.args.NodeConditions)
+	preferNodesMarkedForDeletion(highNodes)
+
+	// keep the usage of the source nodes prior to any eviction so we can
+	// later compare the predicted and the achieved utilization drops.
+	preEvictionUsage := copyNodesUsage(highNodes)
+
+	// this is a stop condition for the eviction process. by default we
+	// stop as soon as the node usage drops below the target threshold,
+	// the strategy may drive it further down or evict a fixed amount
+	// instead. nodes to drain are drained regardless.
+	var lowLimits map[string]api.ReferencedResourceList
+	if l.args.ContinueEvictionStrategy != nil && l.args.ContinueEvictionStrategy.Type == UntilBelowLow {
+		lowLimits = map[string]api.ReferencedResourceList{}
+		for _, node := range highNodes {
+			lowLimits[node.node.Name] = capNodeCapacitiesToThreshold(
+				capacities[node.node.Name],
+				held[node.node.Name][0],
+				l.extendedResourceNames,
+			)
 		}
-		for name := range totalAvailableUsage {
-			if totalAvailableUsage[name].CmpInt64(0) < 1 {
-				return false
+	}
+	continueEvictionCond := allOf(
+		anyOf(
+			continueEvictionStrategyCond(l.args.ContinueEvictionStrategy, lowLimits, preEvictionUsage, summary),
+			func(nodeInfo NodeInfo, _ api.ReferencedResourceList) bool {
+				return isNodeToDrain(nodeInfo.node, l.args.NodeConditions)
+			},
+		),
+		destinationsHaveRoom,
+	)
+
+	// pods shrunk in place may be enough to bring their nodes below the
+	// target thresholds, they are not evicted during this invocation.
+	podFilter := l.podFilter
+	if l.args.InPlaceResize != nil {
+		resized := shrinkPods(ctx, l.podResizer, l.args.InPlaceResize, highNodes, l.podFilter, frameworktypes.IsDryRun(evictor), summary)
+		podFilter = func(pod *v1.Pod) bool {
+			return !resized.Has(pod.UID) && l.podFilter(pod)
+		}
+	}
+
+	opts := &evictionOptions{
+		evictableNamespaces: l.args.EvictableNamespaces,
+		podEvictor:          evictor,
+		evictOptions:        evictions.EvictOptions{StrategyName: LowNodeUtilizationPluginName},
+		podFilter:           podFilter,
+		resourceNames:       l.extendedResourceNames,
+		continueEviction:    continueEvictionCond,
+		usageClient:         l.usageClient,
+		limits:              l.args.EvictionLimits,
+		scoringStrategy:     l.args.ScoringStrategy,
+		evictionOrder:       l.args.EvictionOrder,
+		podSorter:           l.podSorter,
+		affinityAwareness:   l.args.AffinityAwareness,
+		fairnessPolicy:      l.args.FairnessPolicy,
+		rateLimiter:         l.evictionRateLimiter,
+		nodeIndexer:         l.handle.GetPodsAssignedToNodeFunc(),
+		devices:             frameworktypes.DeviceAccountingFor(l.handle),
+	}
+	evictFromSourceNodes := func(sourceNodes, destinationNodes []NodeInfo) []podPlacement {
+		return evictPodsFromSourceNodes(ctx, sourceNodes, destinationNodes, opts, summary)
+	}
+
+	// when balancing within topology domains pods are only moved from the
+	// overutilized nodes to the underutilized nodes of the same domain.
+	var placements []podPlacement
+	if l.args.TopologyKey == "" {
+		placements = evictFromSourceNodes(highNodes, lowNodes)
+	} else {
+		lowDomains := nodeInfosByTopologyDomain(lowNodes, l.args.TopologyKey)
+		highDomains := nodeInfosByTopologyDomain(highNodes, l.args.TopologyKey)
+		for _, domain := range slices.Sorted(maps.Keys(highDomains)) {
+			if len(lowDomains[domain]) == 0 {
+				klog.V(1).InfoS(
+					"No node is underutilized in the topology domain, nothing to do here",
+					"domain", domain,
+				)
+				continue
 			}
+			placements = append(
+				placements,
+				evictFromSourceNodes(highDomains[domain], lowDomains[domain])...,
+			)
 		}
+	}
 
-		return true
+	if l.args.DryRun {
+		summary.projected(highNodes, capacities)
 	}
 
-	// sort the nodes by the usage in descending order
-	sortNodesByUsage(highNodes, false)
+	if l.args.SchedulingHints && !frameworktypes.IsDryRun(evictor) {
+		publishSchedulingHints(ctx, l.handle.ClientSet(), placements)
+	}
 
-	var nodeLimit *uint
-	if l.args.EvictionLimits != nil {
-		nodeLimit = l.args.EvictionLimits.Node
+	// annotated pods are still running, the usage of their nodes has not
+	// dropped yet.
+	if !frameworktypes.IsDryRun(evictor) && l.args.Mode != BalanceModeAnnotate {
+		recordUtilizationDeltas(l.utilizationDeltas, highNodes, preEvictionUsage, capacities, summary)
+	}
+	if !frameworktypes.IsDryRun(evictor) {
+		recordLastEvictions(ctx, l.handle.ClientSet(), l.lastEvictions, l.args.Cooldown, summary, time.Now())
 	}
 
-	evictPodsFromSourceNodes(
-		ctx,
-		l.args.EvictableNamespaces,
-		highNodes,
-		lowNodes,
-		l.handle.Evictor(),
-		evictions.EvictOptions{StrategyName: LowNodeUtilizationPluginName},
-		l.podFilter,
-		l.extendedResourceNames,
-		continueEvictionCond,
-		l.usageClient,
-		nodeLimit,
-	)
+	// annotated pods are not moved yet, their destinations are left alone.
+	if ttl := l.args.DoNotDisruptHintTTL.Duration; ttl > 0 && summary.evicted > 0 && !frameworktypes.IsDryRun(evictor) && l.args.Mode != BalanceModeAnnotate {
+		markDoNotDisrupt(ctx, l.handle.ClientSet(), ttl, doNotDisruptNodes(lowNodes, placements), time.Now())
+	}
+
+	// other plugins sharing the usage client must not rely on the usage
+	// collected before the evictions.
+	if summary.evicted > 0 || summary.resized > 0 {
+		invalidateUsageClient(l.usageClient)
+	}
 
 	return nil
 }
@@ -327,51 +627,185 @@ func validatePrometheusMetricsUtilization(args *LowNodeUtilizationArgs) error {
 		return fmt.Errorf("prometheus query is missing")
 	}
 
-	uResourceNames := getResourceNames(args.Thresholds)
-	oResourceNames := getResourceNames(args.TargetThresholds)
-	if len(uResourceNames) != 1 || uResourceNames[0] != MetricResource {
-		return fmt.Errorf(
-			"thresholds are expected to specify a single instance of %q resource, got %v instead",
-			MetricResource, uResourceNames,
-		)
-	}
+	return validateMetricResourceThresholds(args)
+}
 
-	if len(oResourceNames) != 1 || oResourceNames[0] != MetricResource {
-		return fmt.Errorf(
-			"targetThresholds are expected to specify a single instance of %q resource, got %v instead",
-			MetricResource, oResourceNames,
-		)
+// validateMetricResourceThresholds validates the thresholds, including the
+// ones of the node pools, refer only to the MetricResource, the metrics
+// sources reporting the share of the nodes in use report no other resource.
+func validateMetricResourceThresholds(args *LowNodeUtilizationArgs) error {
+	pairs := [][]api.ResourceThresholds{{args.Thresholds, args.TargetThresholds}}
+	for _, pool := range args.NodePools {
+		pairs = append(pairs, []api.ResourceThresholds{pool.Thresholds, pool.TargetThresholds})
 	}
+	for _, pair := range pairs {
+		uResourceNames := getResourceNames(pair[0])
+		oResourceNames := getResourceNames(pair[1])
+		if len(uResourceNames) != 1 || uResourceNames[0] != MetricResource {
+			return fmt.Errorf(
+				"thresholds are expected to specify a single instance of %q resource, got %v instead",
+				MetricResource, uResourceNames,
+			)
+		}
 
+		if len(oResourceNames) != 1 || oResourceNames[0] != MetricResource {
+			return fmt.Errorf(
+				"targetThresholds are expected to specify a single instance of %q resource, got %v instead",
+				MetricResource, oResourceNames,
+			)
+		}
+	}
 	return nil
 }
 
 // usageClientForMetrics returns the correct usage client based on the
 // metrics source. XXX MetricsServer is deprecated, removed once dropped.
 func usageClientForMetrics(
-	args *LowNodeUtilizationArgs, handle frameworktypes.Handle, resources []v1.ResourceName,
-) (usageClient, error) {
-	metrics := args.MetricsUtilization
+	metrics *MetricsUtilization, cacheTTL time.Duration, handle frameworktypes.Handle, resources []v1.ResourceName,
+) (UsageClient, error) {
 	switch {
 	case metrics.MetricsServer, metrics.Source == api.KubernetesMetrics:
 		if handle.MetricsCollector() == nil {
 			return nil, fmt.Errorf("metrics client not initialized")
 		}
-		return newActualUsageClient(
+		collectors, err := resourceUsageCollectorsFor(
 			resources,
-			handle.GetPodsAssignedToNodeFunc(),
-			handle.MetricsCollector(),
-		), nil
+			metrics.ExtendedResources,
+			handle.PrometheusClient(),
+			coreRESTClient(handle.ClientSet()),
+		)
+		if err != nil {
+			return nil, err
+		}
+		keyParts := []string{
+			strconv.FormatFloat(metrics.SmoothingFactor, 'g', -1, 64),
+			strconv.Itoa(metrics.MinSamples),
+		}
+		for _, extended := range metrics.ExtendedResources {
+			keyParts = append(keyParts, string(extended.Name), extended.Query, extended.NodeLabel, extended.PodQuery)
+		}
+		return sharedUsageClientFor(
+			handle,
+			usageClientKey(actualUsageClientType, resources, keyParts...),
+			cacheTTL,
+			func() (UsageClient, error) {
+				return newActualUsageClient(
+					resources,
+					handle.GetPodsAssignedToNodeFunc(),
+					handle.MetricsCollector(),
+					metrics.SmoothingFactor,
+					metrics.MinSamples,
+					collectors,
+				), nil
+			},
+		)
+
+	case metrics.Source == api.VPARecommendations:
+		return sharedUsageClientFor(
+			handle,
+			usageClientKey(vpaRecommendationUsageClientType, resources),
+			cacheTTL,
+			func() (UsageClient, error) {
+				vpaLister, err := vpa.ListerFor(handle)
+				if err != nil {
+					return nil, err
+				}
+				return newVPARecommendationUsageClient(
+					resources,
+					handle.GetPodsAssignedToNodeFunc(),
+					vpaLister,
+					frameworktypes.DeviceAccountingFor(handle),
+				), nil
+			},
+		)
+
+	case metrics.Source == api.CustomMetrics:
+		restClient := handle.ClientSet().Discovery().RESTClient()
+		if restClient == nil {
+			return nil, fmt.Errorf("custom metrics client not initialized")
+		}
+		custom := metrics.CustomMetrics
+		keyParts := []string{custom.MetricName, custom.PodMetricName, metav1.FormatLabelSelector(custom.Selector)}
+		if custom.External != nil {
+			keyParts = append(keyParts, custom.External.Namespace, custom.External.NodeLabel)
+		}
+		return sharedUsageClientFor(
+			handle,
+			usageClientKey(customMetricsUsageClientType, customMetricsResourceNames, keyParts...),
+			cacheTTL,
+			func() (UsageClient, error) {
+				return newCustomMetricsUsageClient(
+					handle.GetPodsAssignedToNodeFunc(),
+					restClient,
+					metrics.CustomMetrics,
+				)
+			},
+		)
+
+	case metrics.Source == api.OpenTelemetryMetrics:
+		otel := metrics.OpenTelemetry
+		return sharedUsageClientFor(
+			handle,
+			usageClientKey(openTelemetryUsageClientType, openTelemetryResourceNames, otel.Endpoint, otel.MetricName, otel.NodeAttribute, otel.PodMetricName),
+			cacheTTL,
+			func() (UsageClient, error) {
+				return newOpenTelemetryUsageClient(
+					handle.GetPodsAssignedToNodeFunc(),
+					&http.Client{Timeout: defaultOpenTelemetryScrapeTimeout},
+					otel,
+				), nil
+			},
+		)
+
+	case metrics.Source == api.StaticMetrics:
+		static := metrics.Static
+		load := StaticUsageFromFile(static.File)
+		keyParts := []string{static.File}
+		if static.ConfigMap != nil {
+			load = StaticUsageFromConfigMap(handle.ClientSet(), static.ConfigMap.Namespace, static.ConfigMap.Name, static.ConfigMap.Key)
+			keyParts = []string{static.ConfigMap.Namespace, static.ConfigMap.Name, static.ConfigMap.Key}
+		}
+		return sharedUsageClientFor(
+			handle,
+			usageClientKey(staticUsageClientType, resources, keyParts...),
+			cacheTTL,
+			func() (UsageClient, error) {
+				return NewStaticUsageClient(resources, handle.GetPodsAssignedToNodeFunc(), load), nil
+			},
+		)
 
 	case metrics.Source == api.PrometheusMetrics:
 		if handle.PrometheusClient() == nil {
 			return nil, fmt.Errorf("prometheus client not initialized")
 		}
-		return newPrometheusUsageClient(
-			handle.GetPodsAssignedToNodeFunc(),
-			handle.PrometheusClient(),
+		keyParts := []string{
 			metrics.Prometheus.Query,
-		), nil
+			strconv.Itoa(metrics.Prometheus.NodesPerQuery),
+			metrics.Prometheus.NodeLabel,
+			metrics.Prometheus.PodQuery,
+		}
+		if r := metrics.Prometheus.Range; r != nil {
+			keyParts = append(keyParts, r.Window.Duration.String(), r.Step.Duration.String(), string(r.Aggregation))
+		}
+		return sharedUsageClientFor(
+			handle,
+			usageClientKey(prometheusUsageClientType, prometheusResourceNames, keyParts...),
+			cacheTTL,
+			func() (UsageClient, error) {
+				return newPrometheusUsageClient(
+					handle.GetPodsAssignedToNodeFunc(),
+					handle.PrometheusClient(),
+					metrics.Prometheus.Query,
+					metrics.Prometheus.NodesPerQuery,
+					metrics.Prometheus.NodeLabel,
+					nil,
+					metrics.Prometheus.PodQuery,
+					withPrometheusQueryTimeout(metrics.Prometheus.Timeout.Duration),
+					withPrometheusRetries(metrics.Prometheus.Retries, metrics.Prometheus.Backoff.Duration),
+					withPrometheusRange(metrics.Prometheus.Range),
+				), nil
+			},
+		)
 	case metrics.Source != "":
 		return nil, fmt.Errorf("unrecognized metrics source")
 	default:
@@ -19,13 +19,19 @@ package nodeutilization
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 
 	v1 "k8s.io/api/core/v1"
 	policy "k8s.io/api/policy/v1"
+	resourcev1beta1 "k8s.io/api/resource/v1beta1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes/fake"
 	core "k8s.io/client-go/testing"
@@ -36,6 +42,7 @@ import (
 	"sigs.k8s.io/descheduler/pkg/api"
 	"sigs.k8s.io/descheduler/pkg/descheduler/evictions"
 	"sigs.k8s.io/descheduler/pkg/descheduler/metricscollector"
+	nodeutil "sigs.k8s.io/descheduler/pkg/descheduler/node"
 	"sigs.k8s.io/descheduler/pkg/framework/plugins/defaultevictor"
 	frameworktesting "sigs.k8s.io/descheduler/pkg/framework/testing"
 	frameworktypes "sigs.k8s.io/descheduler/pkg/framework/types"
@@ -67,7 +74,185 @@ func TestLowNodeUtilization(t *testing.T) {
 		evictedPods                    []string
 		evictableNamespaces            *api.Namespaces
 		evictionLimits                 *api.EvictionLimits
+		nodeConditions                 []NodeConditionRule
+		overcommit                     []OvercommitRule
 	}{
+		{
+			name: "overcommitted node pool not overutilized",
+			thresholds: api.ResourceThresholds{
+				v1.ResourceCPU: 30,
+			},
+			targetThresholds: api.ResourceThresholds{
+				v1.ResourceCPU: 50,
+			},
+			nodes: []*v1.Node{
+				// n1 runs at 60% of its cpu, 40% once overcommitted.
+				test.BuildTestNode(n1NodeName, 4000, 3000, 10, func(node *v1.Node) {
+					node.Labels = map[string]string{"pool": "batch"}
+				}),
+				test.BuildTestNode(n2NodeName, 4000, 3000, 10, nil),
+				test.BuildTestNode(n3NodeName, 4000, 3000, 10, nil),
+			},
+			pods: []*v1.Pod{
+				test.BuildTestPod("p1", 400, 0, n1NodeName, test.SetRSOwnerRef),
+				test.BuildTestPod("p2", 400, 0, n1NodeName, test.SetRSOwnerRef),
+				test.BuildTestPod("p3", 400, 0, n1NodeName, test.SetRSOwnerRef),
+				test.BuildTestPod("p4", 400, 0, n1NodeName, test.SetRSOwnerRef),
+				test.BuildTestPod("p5", 400, 0, n1NodeName, test.SetRSOwnerRef),
+				test.BuildTestPod("p6", 400, 0, n1NodeName, test.SetRSOwnerRef),
+				test.BuildTestPod("p7", 400, 0, n2NodeName, test.SetRSOwnerRef),
+			},
+			nodemetricses: []*v1beta1.NodeMetrics{
+				test.BuildNodeMetrics(n1NodeName, 2401, 0),
+				test.BuildNodeMetrics(n2NodeName, 401, 0),
+				test.BuildNodeMetrics(n3NodeName, 11, 0),
+			},
+			podmetricses: []*v1beta1.PodMetrics{
+				test.BuildPodMetrics("p1", 401, 0),
+				test.BuildPodMetrics("p2", 401, 0),
+				test.BuildPodMetrics("p3", 401, 0),
+				test.BuildPodMetrics("p4", 401, 0),
+				test.BuildPodMetrics("p5", 401, 0),
+				test.BuildPodMetrics("p6", 401, 0),
+				test.BuildPodMetrics("p7", 401, 0),
+			},
+			overcommit: []OvercommitRule{
+				{
+					NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "batch"}},
+					Factors:      map[v1.ResourceName]float64{v1.ResourceCPU: 1.5},
+				},
+			},
+			expectedPodsEvicted:            0,
+			expectedPodsWithMetricsEvicted: 0,
+		},
+		{
+			name: "overcommit not matching the overutilized node",
+			thresholds: api.ResourceThresholds{
+				v1.ResourceCPU: 30,
+			},
+			targetThresholds: api.ResourceThresholds{
+				v1.ResourceCPU: 50,
+			},
+			nodes: []*v1.Node{
+				test.BuildTestNode(n1NodeName, 4000, 3000, 10, nil),
+				test.BuildTestNode(n2NodeName, 4000, 3000, 10, nil),
+				test.BuildTestNode(n3NodeName, 4000, 3000, 10, nil),
+			},
+			pods: []*v1.Pod{
+				test.BuildTestPod("p1", 400, 0, n1NodeName, test.SetRSOwnerRef),
+				test.BuildTestPod("p2", 400, 0, n1NodeName, test.SetRSOwnerRef),
+				test.BuildTestPod("p3", 400, 0, n1NodeName, test.SetRSOwnerRef),
+				test.BuildTestPod("p4", 400, 0, n1NodeName, test.SetRSOwnerRef),
+				test.BuildTestPod("p5", 400, 0, n1NodeName, test.SetRSOwnerRef),
+				test.BuildTestPod("p6", 400, 0, n1NodeName, test.SetRSOwnerRef),
+				test.BuildTestPod("p7", 400, 0, n2NodeName, test.SetRSOwnerRef),
+			},
+			nodemetricses: []*v1beta1.NodeMetrics{
+				test.BuildNodeMetrics(n1NodeName, 2401, 0),
+				test.BuildNodeMetrics(n2NodeName, 401, 0),
+				test.BuildNodeMetrics(n3NodeName, 11, 0),
+			},
+			podmetricses: []*v1beta1.PodMetrics{
+				test.BuildPodMetrics("p1", 401, 0),
+				test.BuildPodMetrics("p2", 401, 0),
+				test.BuildPodMetrics("p3", 401, 0),
+				test.BuildPodMetrics("p4", 401, 0),
+				test.BuildPodMetrics("p5", 401, 0),
+				test.BuildPodMetrics("p6", 401, 0),
+				test.BuildPodMetrics("p7", 401, 0),
+			},
+			overcommit: []OvercommitRule{
+				{
+					NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "batch"}},
+					Factors:      map[v1.ResourceName]float64{v1.ResourceCPU: 1.5},
+				},
+			},
+			expectedPodsEvicted:            1,
+			expectedPodsWithMetricsEvicted: 1,
+		},
+		{
+			name: "node reporting a condition to drain",
+			thresholds: api.ResourceThresholds{
+				v1.ResourceCPU:  30,
+				v1.ResourcePods: 30,
+			},
+			targetThresholds: api.ResourceThresholds{
+				v1.ResourceCPU:  50,
+				v1.ResourcePods: 50,
+			},
+			nodes: []*v1.Node{
+				test.BuildTestNode(n1NodeName, 4000, 3000, 9, withNodeCondition("KernelDeadlock", v1.ConditionTrue)),
+				test.BuildTestNode(n2NodeName, 4000, 3000, 10, nil),
+				test.BuildTestNode(n3NodeName, 4000, 3000, 10, withNodeCondition("FrequentKubeletRestart", v1.ConditionTrue)),
+			},
+			pods: []*v1.Pod{
+				// n1 is appropriately utilized but reports a
+				// condition to drain.
+				test.BuildTestPod("p1", 400, 0, n1NodeName, test.SetRSOwnerRef),
+				test.BuildTestPod("p2", 400, 0, n1NodeName, test.SetRSOwnerRef),
+				test.BuildTestPod("p3", 400, 0, n1NodeName, test.SetRSOwnerRef),
+				test.BuildTestPod("p4", 400, 0, n1NodeName, test.SetRSOwnerRef),
+				test.BuildTestPod("p5", 400, 0, n2NodeName, test.SetRSOwnerRef),
+			},
+			nodemetricses: []*v1beta1.NodeMetrics{
+				test.BuildNodeMetrics(n1NodeName, 1601, 0),
+				test.BuildNodeMetrics(n2NodeName, 401, 0),
+				test.BuildNodeMetrics(n3NodeName, 11, 0),
+			},
+			podmetricses: []*v1beta1.PodMetrics{
+				test.BuildPodMetrics("p1", 401, 0),
+				test.BuildPodMetrics("p2", 401, 0),
+				test.BuildPodMetrics("p3", 401, 0),
+				test.BuildPodMetrics("p4", 401, 0),
+				test.BuildPodMetrics("p5", 401, 0),
+			},
+			evictionLimits: &api.EvictionLimits{
+				Node: ptr.To[uint](2),
+			},
+			nodeConditions: []NodeConditionRule{
+				{Type: "KernelDeadlock", Action: NodeConditionActionDrain},
+				{Type: "FrequentKubeletRestart", Action: NodeConditionActionAvoid},
+			},
+			expectedPodsEvicted:            2,
+			expectedPodsWithMetricsEvicted: 2,
+		},
+		{
+			name: "node reporting a condition to drain without rules",
+			thresholds: api.ResourceThresholds{
+				v1.ResourceCPU:  30,
+				v1.ResourcePods: 30,
+			},
+			targetThresholds: api.ResourceThresholds{
+				v1.ResourceCPU:  50,
+				v1.ResourcePods: 50,
+			},
+			nodes: []*v1.Node{
+				test.BuildTestNode(n1NodeName, 4000, 3000, 9, withNodeCondition("KernelDeadlock", v1.ConditionTrue)),
+				test.BuildTestNode(n2NodeName, 4000, 3000, 10, nil),
+				test.BuildTestNode(n3NodeName, 4000, 3000, 10, nil),
+			},
+			pods: []*v1.Pod{
+				test.BuildTestPod("p1", 400, 0, n1NodeName, test.SetRSOwnerRef),
+				test.BuildTestPod("p2", 400, 0, n1NodeName, test.SetRSOwnerRef),
+				test.BuildTestPod("p3", 400, 0, n1NodeName, test.SetRSOwnerRef),
+				test.BuildTestPod("p4", 400, 0, n1NodeName, test.SetRSOwnerRef),
+				test.BuildTestPod("p5", 400, 0, n2NodeName, test.SetRSOwnerRef),
+			},
+			nodemetricses: []*v1beta1.NodeMetrics{
+				test.BuildNodeMetrics(n1NodeName, 1601, 0),
+				test.BuildNodeMetrics(n2NodeName, 401, 0),
+				test.BuildNodeMetrics(n3NodeName, 11, 0),
+			},
+			podmetricses: []*v1beta1.PodMetrics{
+				test.BuildPodMetrics("p1", 401, 0),
+				test.BuildPodMetrics("p2", 401, 0),
+				test.BuildPodMetrics("p3", 401, 0),
+				test.BuildPodMetrics("p4", 401, 0),
+				test.BuildPodMetrics("p5", 401, 0),
+			},
+			expectedPodsEvicted:            0,
+			expectedPodsWithMetricsEvicted: 0,
+		},
 		{
 			name: "no evictable pods",
 			thresholds: api.ResourceThresholds{
@@ -417,6 +602,51 @@ func TestLowNodeUtilization(t *testing.T) {
 			expectedPodsEvicted:            4,
 			expectedPodsWithMetricsEvicted: 4,
 		},
+		{
+			name: "without priorities stop when destination pod slots are depleted",
+			thresholds: api.ResourceThresholds{
+				v1.ResourceCPU: 30,
+			},
+			targetThresholds: api.ResourceThresholds{
+				v1.ResourceCPU: 50,
+			},
+			nodes: []*v1.Node{
+				test.BuildTestNode(n1NodeName, 4000, 3000, 10, nil),
+				// n2 has room for 4 more pods cpu wise but can only
+				// accept 2 more pods.
+				test.BuildTestNode(n2NodeName, 4000, 3000, 3, nil),
+				test.BuildTestNode(n3NodeName, 4000, 3000, 10, test.SetNodeUnschedulable),
+			},
+			pods: []*v1.Pod{
+				test.BuildTestPod("p1", 400, 0, n1NodeName, test.SetRSOwnerRef),
+				test.BuildTestPod("p2", 400, 0, n1NodeName, test.SetRSOwnerRef),
+				test.BuildTestPod("p3", 400, 0, n1NodeName, test.SetRSOwnerRef),
+				test.BuildTestPod("p4", 400, 0, n1NodeName, test.SetRSOwnerRef),
+				test.BuildTestPod("p5", 400, 0, n1NodeName, test.SetRSOwnerRef),
+				test.BuildTestPod("p6", 400, 0, n1NodeName, test.SetRSOwnerRef),
+				test.BuildTestPod("p7", 400, 0, n1NodeName, test.SetRSOwnerRef),
+				test.BuildTestPod("p8", 400, 0, n1NodeName, test.SetRSOwnerRef),
+				test.BuildTestPod("p9", 400, 0, n2NodeName, test.SetRSOwnerRef),
+			},
+			nodemetricses: []*v1beta1.NodeMetrics{
+				test.BuildNodeMetrics(n1NodeName, 3201, 0),
+				test.BuildNodeMetrics(n2NodeName, 401, 0),
+				test.BuildNodeMetrics(n3NodeName, 0, 0),
+			},
+			podmetricses: []*v1beta1.PodMetrics{
+				test.BuildPodMetrics("p1", 401, 0),
+				test.BuildPodMetrics("p2", 401, 0),
+				test.BuildPodMetrics("p3", 401, 0),
+				test.BuildPodMetrics("p4", 401, 0),
+				test.BuildPodMetrics("p5", 401, 0),
+				test.BuildPodMetrics("p6", 401, 0),
+				test.BuildPodMetrics("p7", 401, 0),
+				test.BuildPodMetrics("p8", 401, 0),
+			},
+			// 3 pods would bring n1 down to the cpu target threshold.
+			expectedPodsEvicted:            2,
+			expectedPodsWithMetricsEvicted: 2,
+		},
 		{
 			name: "with priorities",
 			thresholds: api.ResourceThresholds{
@@ -771,96 +1001,230 @@ func TestLowNodeUtilization(t *testing.T) {
 			expectedPodsWithMetricsEvicted: 0,
 		},
 		{
-			name: "without priorities, but only other node is unschedulable",
+			name: "with ephemeral storage",
 			thresholds: api.ResourceThresholds{
-				v1.ResourceCPU:  30,
-				v1.ResourcePods: 30,
+				v1.ResourcePods:             30,
+				v1.ResourceEphemeralStorage: 30,
 			},
 			targetThresholds: api.ResourceThresholds{
-				v1.ResourceCPU:  50,
-				v1.ResourcePods: 50,
+				v1.ResourcePods:             50,
+				v1.ResourceEphemeralStorage: 50,
 			},
 			nodes: []*v1.Node{
-				test.BuildTestNode(n1NodeName, 4000, 3000, 9, nil),
-				test.BuildTestNode(n2NodeName, 4000, 3000, 10, test.SetNodeUnschedulable),
+				test.BuildTestNode(n1NodeName, 4000, 3000, 10, func(node *v1.Node) {
+					setNodeResource(node, v1.ResourceEphemeralStorage, "8Gi")
+				}),
+				test.BuildTestNode(n2NodeName, 4000, 3000, 10, func(node *v1.Node) {
+					setNodeResource(node, v1.ResourceEphemeralStorage, "8Gi")
+				}),
+				test.BuildTestNode(n3NodeName, 4000, 3000, 10, test.SetNodeUnschedulable),
 			},
 			pods: []*v1.Pod{
-				test.BuildTestPod("p1", 400, 0, n1NodeName, test.SetRSOwnerRef),
-				test.BuildTestPod("p2", 400, 0, n1NodeName, test.SetRSOwnerRef),
-				test.BuildTestPod("p3", 400, 0, n1NodeName, test.SetRSOwnerRef),
-				test.BuildTestPod("p4", 400, 0, n1NodeName, test.SetRSOwnerRef),
-				test.BuildTestPod("p5", 400, 0, n1NodeName, test.SetRSOwnerRef),
-				// These won't be evicted.
-				test.BuildTestPod("p6", 400, 0, n1NodeName, test.SetDSOwnerRef),
-				test.BuildTestPod("p7", 400, 0, n1NodeName, func(pod *v1.Pod) {
-					// A pod with local storage.
-					test.SetNormalOwnerRef(pod)
-					pod.Spec.Volumes = []v1.Volume{
-						{
-							Name: "sample",
-							VolumeSource: v1.VolumeSource{
-								HostPath: &v1.HostPathVolumeSource{Path: "somePath"},
-								EmptyDir: &v1.EmptyDirVolumeSource{
-									SizeLimit: resource.NewQuantity(int64(10), resource.BinarySI),
-								},
-							},
-						},
-					}
-					// A Mirror Pod.
-					pod.Annotations = test.GetMirrorPodAnnotation()
+				test.BuildTestPod("p1", 0, 0, n1NodeName, func(pod *v1.Pod) {
+					test.SetRSOwnerRef(pod)
+					setPodResourceRequest(pod, v1.ResourceEphemeralStorage, "1Gi")
 				}),
-				test.BuildTestPod("p8", 400, 0, n1NodeName, func(pod *v1.Pod) {
-					// A Critical Pod.
-					test.SetNormalOwnerRef(pod)
-					pod.Namespace = "kube-system"
-					priority := utils.SystemCriticalPriority
-					pod.Spec.Priority = &priority
+				test.BuildTestPod("p2", 0, 0, n1NodeName, func(pod *v1.Pod) {
+					test.SetRSOwnerRef(pod)
+					setPodResourceRequest(pod, v1.ResourceEphemeralStorage, "1Gi")
+				}),
+				test.BuildTestPod("p3", 0, 0, n1NodeName, func(pod *v1.Pod) {
+					test.SetRSOwnerRef(pod)
+					setPodResourceRequest(pod, v1.ResourceEphemeralStorage, "1Gi")
+				}),
+				test.BuildTestPod("p4", 0, 0, n1NodeName, func(pod *v1.Pod) {
+					test.SetRSOwnerRef(pod)
+					setPodResourceRequest(pod, v1.ResourceEphemeralStorage, "1Gi")
+				}),
+				test.BuildTestPod("p5", 0, 0, n1NodeName, func(pod *v1.Pod) {
+					test.SetRSOwnerRef(pod)
+					setPodResourceRequest(pod, v1.ResourceEphemeralStorage, "1Gi")
+				}),
+				test.BuildTestPod("p6", 0, 0, n1NodeName, func(pod *v1.Pod) {
+					test.SetRSOwnerRef(pod)
+					setPodResourceRequest(pod, v1.ResourceEphemeralStorage, "1Gi")
+				}),
+				test.BuildTestPod("p7", 0, 0, n1NodeName, func(pod *v1.Pod) {
+					test.SetRSOwnerRef(pod)
+					setPodResourceRequest(pod, v1.ResourceEphemeralStorage, "1Gi")
+				}),
+				test.BuildTestPod("p9", 0, 0, n2NodeName, func(pod *v1.Pod) {
+					test.SetRSOwnerRef(pod)
+					setPodResourceRequest(pod, v1.ResourceEphemeralStorage, "1Gi")
 				}),
 			},
 			nodemetricses: []*v1beta1.NodeMetrics{
 				test.BuildNodeMetrics(n1NodeName, 3201, 0),
 				test.BuildNodeMetrics(n2NodeName, 401, 0),
+				test.BuildNodeMetrics(n3NodeName, 11, 0),
 			},
 			podmetricses: []*v1beta1.PodMetrics{
 				test.BuildPodMetrics("p1", 401, 0),
 				test.BuildPodMetrics("p2", 401, 0),
 				test.BuildPodMetrics("p3", 401, 0),
-				test.BuildPodMetrics("p4", 401, 0),
-				test.BuildPodMetrics("p5", 401, 0),
 			},
-			expectedPodsEvicted:            0,
+			// 2 pods would bring n1 down to the pods target threshold, the third one is evicted to bring its ephemeral storage down as well
+			expectedPodsEvicted:            3,
 			expectedPodsWithMetricsEvicted: 0,
 		},
 		{
-			name: "without priorities, but only other node doesn't match pod node selector for p4 and p5",
+			name: "with hugepages",
 			thresholds: api.ResourceThresholds{
-				v1.ResourceCPU:  30,
 				v1.ResourcePods: 30,
+				"hugepages-2Mi": 30,
 			},
 			targetThresholds: api.ResourceThresholds{
-				v1.ResourceCPU:  50,
 				v1.ResourcePods: 50,
+				"hugepages-2Mi": 50,
 			},
 			nodes: []*v1.Node{
-				test.BuildTestNode(n1NodeName, 4000, 3000, 9, nil),
+				test.BuildTestNode(n1NodeName, 4000, 3000, 10, func(node *v1.Node) {
+					setNodeResource(node, "hugepages-2Mi", "16Mi")
+				}),
 				test.BuildTestNode(n2NodeName, 4000, 3000, 10, func(node *v1.Node) {
-					node.ObjectMeta.Labels = map[string]string{
-						nodeSelectorKey: notMatchingNodeSelectorValue,
-					}
+					setNodeResource(node, "hugepages-2Mi", "16Mi")
 				}),
+				test.BuildTestNode(n3NodeName, 4000, 3000, 10, test.SetNodeUnschedulable),
 			},
 			pods: []*v1.Pod{
-				test.BuildTestPod("p1", 400, 0, n1NodeName, test.SetRSOwnerRef),
-				test.BuildTestPod("p2", 400, 0, n1NodeName, test.SetRSOwnerRef),
-				test.BuildTestPod("p3", 400, 0, n1NodeName, test.SetRSOwnerRef),
-				// These won't be evicted.
-				test.BuildTestPod("p4", 400, 0, n1NodeName, func(pod *v1.Pod) {
-					// A pod selecting nodes in the "west" datacenter
-					test.SetNormalOwnerRef(pod)
-					pod.Spec.NodeSelector = map[string]string{
-						nodeSelectorKey: nodeSelectorValue,
-					}
-				}),
+				test.BuildTestPod("p1", 0, 0, n1NodeName, func(pod *v1.Pod) {
+					test.SetRSOwnerRef(pod)
+					setPodResourceRequest(pod, "hugepages-2Mi", "2Mi")
+				}),
+				test.BuildTestPod("p2", 0, 0, n1NodeName, func(pod *v1.Pod) {
+					test.SetRSOwnerRef(pod)
+					setPodResourceRequest(pod, "hugepages-2Mi", "2Mi")
+				}),
+				test.BuildTestPod("p3", 0, 0, n1NodeName, func(pod *v1.Pod) {
+					test.SetRSOwnerRef(pod)
+					setPodResourceRequest(pod, "hugepages-2Mi", "2Mi")
+				}),
+				test.BuildTestPod("p4", 0, 0, n1NodeName, func(pod *v1.Pod) {
+					test.SetRSOwnerRef(pod)
+					setPodResourceRequest(pod, "hugepages-2Mi", "2Mi")
+				}),
+				test.BuildTestPod("p5", 0, 0, n1NodeName, func(pod *v1.Pod) {
+					test.SetRSOwnerRef(pod)
+					setPodResourceRequest(pod, "hugepages-2Mi", "2Mi")
+				}),
+				test.BuildTestPod("p6", 0, 0, n1NodeName, func(pod *v1.Pod) {
+					test.SetRSOwnerRef(pod)
+					setPodResourceRequest(pod, "hugepages-2Mi", "2Mi")
+				}),
+				test.BuildTestPod("p7", 0, 0, n1NodeName, func(pod *v1.Pod) {
+					test.SetRSOwnerRef(pod)
+					setPodResourceRequest(pod, "hugepages-2Mi", "2Mi")
+				}),
+				test.BuildTestPod("p9", 0, 0, n2NodeName, func(pod *v1.Pod) {
+					test.SetRSOwnerRef(pod)
+					setPodResourceRequest(pod, "hugepages-2Mi", "2Mi")
+				}),
+			},
+			nodemetricses: []*v1beta1.NodeMetrics{
+				test.BuildNodeMetrics(n1NodeName, 3201, 0),
+				test.BuildNodeMetrics(n2NodeName, 401, 0),
+				test.BuildNodeMetrics(n3NodeName, 11, 0),
+			},
+			podmetricses: []*v1beta1.PodMetrics{
+				test.BuildPodMetrics("p1", 401, 0),
+				test.BuildPodMetrics("p2", 401, 0),
+				test.BuildPodMetrics("p3", 401, 0),
+			},
+			// 2 pods would bring n1 down to the pods target threshold, the third one is evicted to bring its hugepages down as well
+			expectedPodsEvicted:            3,
+			expectedPodsWithMetricsEvicted: 0,
+		},
+		{
+			name: "without priorities, but only other node is unschedulable",
+			thresholds: api.ResourceThresholds{
+				v1.ResourceCPU:  30,
+				v1.ResourcePods: 30,
+			},
+			targetThresholds: api.ResourceThresholds{
+				v1.ResourceCPU:  50,
+				v1.ResourcePods: 50,
+			},
+			nodes: []*v1.Node{
+				test.BuildTestNode(n1NodeName, 4000, 3000, 9, nil),
+				test.BuildTestNode(n2NodeName, 4000, 3000, 10, test.SetNodeUnschedulable),
+			},
+			pods: []*v1.Pod{
+				test.BuildTestPod("p1", 400, 0, n1NodeName, test.SetRSOwnerRef),
+				test.BuildTestPod("p2", 400, 0, n1NodeName, test.SetRSOwnerRef),
+				test.BuildTestPod("p3", 400, 0, n1NodeName, test.SetRSOwnerRef),
+				test.BuildTestPod("p4", 400, 0, n1NodeName, test.SetRSOwnerRef),
+				test.BuildTestPod("p5", 400, 0, n1NodeName, test.SetRSOwnerRef),
+				// These won't be evicted.
+				test.BuildTestPod("p6", 400, 0, n1NodeName, test.SetDSOwnerRef),
+				test.BuildTestPod("p7", 400, 0, n1NodeName, func(pod *v1.Pod) {
+					// A pod with local storage.
+					test.SetNormalOwnerRef(pod)
+					pod.Spec.Volumes = []v1.Volume{
+						{
+							Name: "sample",
+							VolumeSource: v1.VolumeSource{
+								HostPath: &v1.HostPathVolumeSource{Path: "somePath"},
+								EmptyDir: &v1.EmptyDirVolumeSource{
+									SizeLimit: resource.NewQuantity(int64(10), resource.BinarySI),
+								},
+							},
+						},
+					}
+					// A Mirror Pod.
+					pod.Annotations = test.GetMirrorPodAnnotation()
+				}),
+				test.BuildTestPod("p8", 400, 0, n1NodeName, func(pod *v1.Pod) {
+					// A Critical Pod.
+					test.SetNormalOwnerRef(pod)
+					pod.Namespace = "kube-system"
+					priority := utils.SystemCriticalPriority
+					pod.Spec.Priority = &priority
+				}),
+			},
+			nodemetricses: []*v1beta1.NodeMetrics{
+				test.BuildNodeMetrics(n1NodeName, 3201, 0),
+				test.BuildNodeMetrics(n2NodeName, 401, 0),
+			},
+			podmetricses: []*v1beta1.PodMetrics{
+				test.BuildPodMetrics("p1", 401, 0),
+				test.BuildPodMetrics("p2", 401, 0),
+				test.BuildPodMetrics("p3", 401, 0),
+				test.BuildPodMetrics("p4", 401, 0),
+				test.BuildPodMetrics("p5", 401, 0),
+			},
+			expectedPodsEvicted:            0,
+			expectedPodsWithMetricsEvicted: 0,
+		},
+		{
+			name: "without priorities, but only other node doesn't match pod node selector for p4 and p5",
+			thresholds: api.ResourceThresholds{
+				v1.ResourceCPU:  30,
+				v1.ResourcePods: 30,
+			},
+			targetThresholds: api.ResourceThresholds{
+				v1.ResourceCPU:  50,
+				v1.ResourcePods: 50,
+			},
+			nodes: []*v1.Node{
+				test.BuildTestNode(n1NodeName, 4000, 3000, 9, nil),
+				test.BuildTestNode(n2NodeName, 4000, 3000, 10, func(node *v1.Node) {
+					node.ObjectMeta.Labels = map[string]string{
+						nodeSelectorKey: notMatchingNodeSelectorValue,
+					}
+				}),
+			},
+			pods: []*v1.Pod{
+				test.BuildTestPod("p1", 400, 0, n1NodeName, test.SetRSOwnerRef),
+				test.BuildTestPod("p2", 400, 0, n1NodeName, test.SetRSOwnerRef),
+				test.BuildTestPod("p3", 400, 0, n1NodeName, test.SetRSOwnerRef),
+				// These won't be evicted.
+				test.BuildTestPod("p4", 400, 0, n1NodeName, func(pod *v1.Pod) {
+					// A pod selecting nodes in the "west" datacenter
+					test.SetNormalOwnerRef(pod)
+					pod.Spec.NodeSelector = map[string]string{
+						nodeSelectorKey: nodeSelectorValue,
+					}
+				}),
 				test.BuildTestPod("p5", 400, 0, n1NodeName, func(pod *v1.Pod) {
 					// A pod selecting nodes in the "west" datacenter
 					test.SetNormalOwnerRef(pod)
@@ -1319,6 +1683,90 @@ func TestLowNodeUtilization(t *testing.T) {
 			expectedPodsEvicted:            2,
 			expectedPodsWithMetricsEvicted: 2,
 		},
+		{
+			name: "with node and total eviction limits",
+			thresholds: api.ResourceThresholds{
+				v1.ResourceCPU: 30,
+			},
+			targetThresholds: api.ResourceThresholds{
+				v1.ResourceCPU: 50,
+			},
+			evictionLimits: &api.EvictionLimits{
+				Node:  ptr.To[uint](2),
+				Total: ptr.To[uint](3),
+			},
+			nodes: []*v1.Node{
+				test.BuildTestNode(n1NodeName, 4000, 3000, 10, nil),
+				test.BuildTestNode(n2NodeName, 4000, 3000, 10, nil),
+				test.BuildTestNode(n3NodeName, 4000, 3000, 10, nil),
+			},
+			pods: func() []*v1.Pod {
+				pods := []*v1.Pod{test.BuildTestPod("p0", 400, 0, n2NodeName, test.SetRSOwnerRef)}
+				for i := 1; i <= 8; i++ {
+					pods = append(pods, test.BuildTestPod(fmt.Sprintf("p%d", i), 400, 0, n1NodeName, test.SetRSOwnerRef))
+					pods = append(pods, test.BuildTestPod(fmt.Sprintf("p%d", i+8), 400, 0, n3NodeName, test.SetRSOwnerRef))
+				}
+				return pods
+			}(),
+			nodemetricses: []*v1beta1.NodeMetrics{
+				test.BuildNodeMetrics(n1NodeName, 3201, 0),
+				test.BuildNodeMetrics(n2NodeName, 401, 0),
+				test.BuildNodeMetrics(n3NodeName, 3201, 0),
+			},
+			podmetricses: func() []*v1beta1.PodMetrics {
+				var metrics []*v1beta1.PodMetrics
+				for i := 0; i <= 16; i++ {
+					metrics = append(metrics, test.BuildPodMetrics(fmt.Sprintf("p%d", i), 401, 0))
+				}
+				return metrics
+			}(),
+			expectedPodsEvicted:            3,
+			expectedPodsWithMetricsEvicted: 3,
+		},
+		{
+			name: "with workload eviction limit",
+			thresholds: api.ResourceThresholds{
+				v1.ResourceCPU: 30,
+			},
+			targetThresholds: api.ResourceThresholds{
+				v1.ResourceCPU: 50,
+			},
+			evictionLimits: &api.EvictionLimits{
+				Workload: ptr.To[uint](1),
+			},
+			nodes: []*v1.Node{
+				test.BuildTestNode(n1NodeName, 4000, 3000, 10, nil),
+				test.BuildTestNode(n2NodeName, 4000, 3000, 10, nil),
+				test.BuildTestNode(n3NodeName, 4000, 3000, 10, nil),
+			},
+			pods: []*v1.Pod{
+				// the replicas of both ReplicaSets belong to the same
+				// deployment, only one of them is evicted.
+				test.BuildTestPod("p1", 400, 0, n1NodeName, controlledBy("ReplicaSet", "web-abc", "abc")),
+				test.BuildTestPod("p2", 400, 0, n1NodeName, controlledBy("ReplicaSet", "web-abc", "abc")),
+				test.BuildTestPod("p3", 400, 0, n1NodeName, controlledBy("ReplicaSet", "web-def", "def")),
+				test.BuildTestPod("p4", 400, 0, n1NodeName, controlledBy("ReplicaSet", "web-def", "def")),
+				test.BuildTestPod("p5", 400, 0, n1NodeName, controlledBy("StatefulSet", "db", "")),
+				test.BuildTestPod("p6", 400, 0, n1NodeName, controlledBy("StatefulSet", "db", "")),
+				test.BuildTestPod("p7", 400, 0, n1NodeName, controlledBy("StatefulSet", "db", "")),
+				test.BuildTestPod("p8", 400, 0, n1NodeName, controlledBy("StatefulSet", "db", "")),
+				test.BuildTestPod("p9", 400, 0, n2NodeName, test.SetRSOwnerRef),
+			},
+			nodemetricses: []*v1beta1.NodeMetrics{
+				test.BuildNodeMetrics(n1NodeName, 3201, 0),
+				test.BuildNodeMetrics(n2NodeName, 401, 0),
+				test.BuildNodeMetrics(n3NodeName, 11, 0),
+			},
+			podmetricses: func() []*v1beta1.PodMetrics {
+				var metrics []*v1beta1.PodMetrics
+				for i := 1; i <= 9; i++ {
+					metrics = append(metrics, test.BuildPodMetrics(fmt.Sprintf("p%d", i), 401, 0))
+				}
+				return metrics
+			}(),
+			expectedPodsEvicted:            2,
+			expectedPodsWithMetricsEvicted: 2,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -1398,6 +1846,8 @@ func TestLowNodeUtilization(t *testing.T) {
 					EvictionLimits:         tc.evictionLimits,
 					EvictableNamespaces:    tc.evictableNamespaces,
 					MetricsUtilization:     metricsUtilization,
+					NodeConditions:         tc.nodeConditions,
+					Overcommit:             tc.overcommit,
 				},
 					handle)
 				if err != nil {
@@ -1572,6 +2022,586 @@ func TestLowNodeUtilizationWithTaints(t *testing.T) {
 	}
 }
 
+func TestLowNodeUtilizationNodeFit(t *testing.T) {
+	ctx := context.Background()
+
+	n1 := test.BuildTestNode("n1", 2000, 3000, 10, func(node *v1.Node) {
+		node.Labels = map[string]string{"disk": "ssd", v1.LabelHostname: "n1"}
+	})
+	n2 := test.BuildTestNode("n2", 1000, 3000, 10, func(node *v1.Node) {
+		node.Labels = map[string]string{v1.LabelHostname: "n2"}
+	})
+
+	buildSourcePods := func(apply func(*v1.Pod)) []*v1.Pod {
+		var pods []*v1.Pod
+		for i := 1; i <= 8; i++ {
+			pods = append(pods, test.BuildTestPod(fmt.Sprintf("pod_%d_%s", i, n1.Name), 100, 0, n1.Name, func(pod *v1.Pod) {
+				test.SetRSOwnerRef(pod)
+				if apply != nil {
+					apply(pod)
+				}
+			}))
+		}
+		return pods
+	}
+
+	tests := []struct {
+		name              string
+		pods              []*v1.Pod
+		evictionsExpected uint
+	}{
+		{
+			name: "pods fit on the destination node",
+			pods: append(
+				buildSourcePods(nil),
+				test.BuildTestPod("pod_9_n2", 100, 0, n2.Name, test.SetRSOwnerRef),
+			),
+			evictionsExpected: 1,
+		},
+		{
+			name: "node selector only matching the source node",
+			pods: append(
+				buildSourcePods(func(pod *v1.Pod) {
+					pod.Spec.NodeSelector = map[string]string{"disk": "ssd"}
+				}),
+				test.BuildTestPod("pod_9_n2", 100, 0, n2.Name, test.SetRSOwnerRef),
+			),
+			evictionsExpected: 0,
+		},
+		{
+			name: "required node affinity only matching the source node",
+			pods: append(
+				buildSourcePods(func(pod *v1.Pod) {
+					pod.Spec.Affinity = &v1.Affinity{
+						NodeAffinity: &v1.NodeAffinity{
+							RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{
+								NodeSelectorTerms: []v1.NodeSelectorTerm{
+									{
+										MatchExpressions: []v1.NodeSelectorRequirement{
+											{Key: v1.LabelHostname, Operator: v1.NodeSelectorOpIn, Values: []string{"n1"}},
+										},
+									},
+								},
+							},
+						},
+					}
+				}),
+				test.BuildTestPod("pod_9_n2", 100, 0, n2.Name, test.SetRSOwnerRef),
+			),
+			evictionsExpected: 0,
+		},
+		{
+			name: "requests exceeding the destination node available resources",
+			pods: append(
+				buildSourcePods(nil),
+				test.BuildTestPod("pod_9_n2", 950, 0, n2.Name, test.SetRSOwnerRef),
+			),
+			evictionsExpected: 0,
+		},
+	}
+
+	for _, item := range tests {
+		t.Run(item.name, func(t *testing.T) {
+			objs := []runtime.Object{n1, n2}
+			for _, pod := range item.pods {
+				objs = append(objs, pod)
+			}
+
+			handle, podEvictor, err := frameworktesting.InitFrameworkHandle(
+				ctx,
+				fake.NewSimpleClientset(objs...),
+				nil,
+				defaultevictor.DefaultEvictorArgs{},
+				nil,
+			)
+			if err != nil {
+				t.Fatalf("Unable to initialize a framework handle: %v", err)
+			}
+
+			plugin, err := NewLowNodeUtilization(&LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourcePods: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourcePods: 70,
+				},
+			},
+				handle)
+			if err != nil {
+				t.Fatalf("Unable to initialize the plugin: %v", err)
+			}
+			plugin.(frameworktypes.BalancePlugin).Balance(ctx, []*v1.Node{n1, n2})
+
+			if item.evictionsExpected != podEvictor.TotalEvicted() {
+				t.Errorf("Expected %v evictions, got %v", item.evictionsExpected, podEvictor.TotalEvicted())
+			}
+		})
+	}
+}
+
+var _ UsageClient = &frameworktesting.FakeUsageClient{}
+
+func TestLowNodeUtilizationWithFakeUsageClient(t *testing.T) {
+	ctx := context.Background()
+
+	n1 := test.BuildTestNode("n1", 1000, 3000, 10, nil)
+	n2 := test.BuildTestNode("n2", 1000, 3000, 10, nil)
+	pods := []*v1.Pod{
+		test.BuildTestPod("p1", 400, 0, n1.Name, test.SetRSOwnerRef),
+		test.BuildTestPod("p2", 400, 0, n1.Name, test.SetRSOwnerRef),
+	}
+
+	for _, tc := range []struct {
+		name              string
+		syncErrors        []error
+		usageErrors       bool
+		expectedErr       bool
+		evictionsExpected uint
+	}{
+		{
+			name:              "pods evicted from the overutilized node",
+			evictionsExpected: 1,
+		},
+		{
+			name:        "sync failure",
+			syncErrors:  []error{fmt.Errorf("metrics server unavailable")},
+			expectedErr: true,
+		},
+		{
+			name:        "pod usage failure",
+			usageErrors: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			objs := []runtime.Object{n1, n2}
+			for _, pod := range pods {
+				objs = append(objs, pod)
+			}
+			handle, podEvictor, err := frameworktesting.InitFrameworkHandle(
+				ctx,
+				fake.NewSimpleClientset(objs...),
+				nil,
+				defaultevictor.DefaultEvictorArgs{},
+				nil,
+			)
+			if err != nil {
+				t.Fatalf("Unable to initialize a framework handle: %v", err)
+			}
+
+			plugin, err := NewLowNodeUtilization(&LowNodeUtilizationArgs{
+				Thresholds:       api.ResourceThresholds{v1.ResourceCPU: 30},
+				TargetThresholds: api.ResourceThresholds{v1.ResourceCPU: 50},
+			}, handle)
+			if err != nil {
+				t.Fatalf("Unable to initialize the plugin: %v", err)
+			}
+
+			builder := frameworktesting.BuildUsageClient().
+				WithNode(n1.Name, frameworktesting.BuildNodeUsage().WithCPU("800m").WithMemory("0").WithPods(2), pods...).
+				WithNode(n2.Name, frameworktesting.BuildNodeUsage().WithCPU("0").WithMemory("0").WithPods(0)).
+				WithSyncErrors(tc.syncErrors...)
+			if tc.usageErrors {
+				for _, pod := range pods {
+					builder.WithPodUsageError(pod, fmt.Errorf("no usage for pod"))
+				}
+			}
+			usageClient := builder.Build()
+			plugin.(*LowNodeUtilization).usageClient = usageClient
+
+			status := plugin.(frameworktypes.BalancePlugin).Balance(ctx, []*v1.Node{n1, n2})
+			if (status != nil && status.Err != nil) != tc.expectedErr {
+				t.Errorf("Expected error %t, got status %v", tc.expectedErr, status)
+			}
+			if usageClient.SyncCalls() == 0 {
+				t.Errorf("Expected the usage client to be synced")
+			}
+			if podEvictor.TotalEvicted() != tc.evictionsExpected {
+				t.Errorf("Expected %v evictions, got %v", tc.evictionsExpected, podEvictor.TotalEvicted())
+			}
+		})
+	}
+}
+
+// TestLowNodeUtilizationWithDevices classifies the nodes by the devices
+// allocated to the pods through Dynamic Resource Allocation.
+func TestLowNodeUtilizationWithDevices(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const driver = "gpu.example.com"
+	buildSlice := func(nodeName string) *resourcev1beta1.ResourceSlice {
+		slice := &resourcev1beta1.ResourceSlice{
+			ObjectMeta: metav1.ObjectMeta{Name: nodeName},
+			Spec: resourcev1beta1.ResourceSliceSpec{
+				Driver:   driver,
+				NodeName: nodeName,
+				Pool:     resourcev1beta1.ResourcePool{Name: nodeName, ResourceSliceCount: 1},
+			},
+		}
+		for i := 0; i < 4; i++ {
+			slice.Spec.Devices = append(slice.Spec.Devices, resourcev1beta1.Device{Name: fmt.Sprintf("gpu-%d", i)})
+		}
+		return slice
+	}
+	buildPodWithGPU := func(name, nodeName string) (*v1.Pod, *resourcev1beta1.ResourceClaim) {
+		claim := &resourcev1beta1.ResourceClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: name + "-gpu", Namespace: "default"},
+			Status: resourcev1beta1.ResourceClaimStatus{
+				Allocation: &resourcev1beta1.AllocationResult{
+					Devices: resourcev1beta1.DeviceAllocationResult{
+						Results: []resourcev1beta1.DeviceRequestAllocationResult{
+							{Request: "gpu", Driver: driver, Pool: nodeName, Device: "gpu"},
+						},
+					},
+				},
+			},
+		}
+		pod := test.BuildTestPod(name, 100, 0, nodeName, func(pod *v1.Pod) {
+			test.SetRSOwnerRef(pod)
+			pod.Spec.ResourceClaims = []v1.PodResourceClaim{{Name: "gpu", ResourceClaimName: ptr.To(claim.Name)}}
+		})
+		return pod, claim
+	}
+
+	n1 := test.BuildTestNode("n1", 4000, 3000, 10, nil)
+	n2 := test.BuildTestNode("n2", 4000, 3000, 10, nil)
+	objs := []runtime.Object{n1, n2, buildSlice(n1.Name), buildSlice(n2.Name)}
+	for i := 0; i < 4; i++ {
+		pod, claim := buildPodWithGPU(fmt.Sprintf("p%d", i), n1.Name)
+		objs = append(objs, pod, claim)
+	}
+	fakeClient := fake.NewSimpleClientset(objs...)
+
+	handle, podEvictor, err := frameworktesting.InitFrameworkHandle(ctx, fakeClient, nil, defaultevictor.DefaultEvictorArgs{}, nil)
+	if err != nil {
+		t.Fatalf("Unable to initialize a framework handle: %v", err)
+	}
+	sharedInformerFactory := informers.NewSharedInformerFactory(fakeClient, 0)
+	handle.DeviceAccountingImpl, err = nodeutil.NewDeviceAccounting(sharedInformerFactory)
+	if err != nil {
+		t.Fatalf("Build device accounting error: %v", err)
+	}
+	sharedInformerFactory.Start(ctx.Done())
+	sharedInformerFactory.WaitForCacheSync(ctx.Done())
+
+	plugin, err := NewLowNodeUtilization(&LowNodeUtilizationArgs{
+		Thresholds:       api.ResourceThresholds{driver: 30},
+		TargetThresholds: api.ResourceThresholds{driver: 50},
+	}, handle)
+	if err != nil {
+		t.Fatalf("Unable to initialize the plugin: %v", err)
+	}
+	plugin.(frameworktypes.BalancePlugin).Balance(ctx, []*v1.Node{n1, n2})
+
+	// n1 runs all of its four devices, n2 none. two pods are moved so
+	// n2 reaches the target threshold.
+	if podEvictor.TotalEvicted() != 2 {
+		t.Errorf("Expected 2 evictions, got %v", podEvictor.TotalEvicted())
+	}
+}
+
+// TestLowNodeUtilizationEvictionOrder checks the pods of the lowest
+// priority are evicted from all the source nodes first when the evictions
+// are sequenced by priority bands.
+func TestLowNodeUtilizationEvictionOrder(t *testing.T) {
+	buildPod := func(name, nodeName string, priority int32) *v1.Pod {
+		return test.BuildTestPod(name, 950, 0, nodeName, func(pod *v1.Pod) {
+			test.SetRSOwnerRef(pod)
+			test.SetPodPriority(pod, priority)
+		})
+	}
+
+	n1 := test.BuildTestNode("n1", 4000, 3000, 10, nil)
+	n2 := test.BuildTestNode("n2", 4000, 3000, 10, nil)
+	n3 := test.BuildTestNode("n3", 4000, 3000, 10, nil)
+	n4 := test.BuildTestNode("n4", 4000, 3000, 10, nil)
+	pods := []*v1.Pod{
+		buildPod("n1-low", n1.Name, 0),
+		buildPod("n1-high-1", n1.Name, 100),
+		buildPod("n1-high-2", n1.Name, 100),
+		buildPod("n1-high-3", n1.Name, 100),
+		buildPod("n2-low-1", n2.Name, 0),
+		buildPod("n2-low-2", n2.Name, 0),
+		buildPod("n2-high", n2.Name, 100),
+	}
+
+	for _, tc := range []struct {
+		name          string
+		evictionOrder EvictionOrder
+		expected      []string
+	}{
+		{
+			name:     "per node",
+			expected: []string{"n1-low", "n1-high-1"},
+		},
+		{
+			name:          "priority bands",
+			evictionOrder: EvictionOrderPriorityBands,
+			expected:      []string{"n1-low", "n2-low-1"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			objs := []runtime.Object{n1, n2, n3, n4}
+			for _, pod := range pods {
+				objs = append(objs, pod)
+			}
+			fakeClient := fake.NewSimpleClientset(objs...)
+
+			var evicted []string
+			fakeClient.PrependReactor("create", "pods", func(action core.Action) (bool, runtime.Object, error) {
+				if action.GetSubresource() == "eviction" {
+					evicted = append(evicted, action.(core.CreateAction).GetObject().(*policy.Eviction).Name)
+				}
+				return false, nil, nil
+			})
+
+			handle, _, err := frameworktesting.InitFrameworkHandle(
+				ctx,
+				fakeClient,
+				evictions.NewOptions().WithMaxPodsToEvictTotal(ptr.To[uint](2)),
+				defaultevictor.DefaultEvictorArgs{},
+				nil,
+			)
+			if err != nil {
+				t.Fatalf("Unable to initialize a framework handle: %v", err)
+			}
+
+			plugin, err := NewLowNodeUtilization(&LowNodeUtilizationArgs{
+				Thresholds:       api.ResourceThresholds{v1.ResourceCPU: 30},
+				TargetThresholds: api.ResourceThresholds{v1.ResourceCPU: 50},
+				EvictionOrder:    tc.evictionOrder,
+			}, handle)
+			if err != nil {
+				t.Fatalf("Unable to initialize the plugin: %v", err)
+			}
+			plugin.(frameworktypes.BalancePlugin).Balance(ctx, []*v1.Node{n1, n2, n3, n4})
+
+			if !slices.Equal(evicted, tc.expected) {
+				t.Errorf("Expected %v to be evicted, got %v", tc.expected, evicted)
+			}
+		})
+	}
+}
+
+// TestLowNodeUtilizationSelectsCandidatesInBatches checks the eviction
+// candidates of the source nodes are selected in parallel batches as the
+// nodes are reached: the pods are evicted in the source nodes order and the
+// nodes past the batch the limit is hit in are never filtered.
+func TestLowNodeUtilizationSelectsCandidatesInBatches(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the higher the index the higher the usage, the source nodes are
+	// drained from the last one to the first one.
+	sourceNodes := 40
+	var nodes []*v1.Node
+	objs := []runtime.Object{}
+	for i := 0; i < sourceNodes; i++ {
+		node := test.BuildTestNode(fmt.Sprintf("src-%02d", i), 4000, 3000, 10, nil)
+		nodes = append(nodes, node)
+		objs = append(objs, node)
+		for j := 0; j < 3; j++ {
+			objs = append(objs, test.BuildTestPod(fmt.Sprintf("%s-p%d", node.Name, j), int64(900+10*i), 0, node.Name, test.SetRSOwnerRef))
+		}
+	}
+	destination := test.BuildTestNode("dst", 64000, 3000, 100, nil)
+	nodes = append(nodes, destination)
+	objs = append(objs, destination)
+	fakeClient := fake.NewSimpleClientset(objs...)
+
+	var evicted []string
+	fakeClient.PrependReactor("create", "pods", func(action core.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() == "eviction" {
+			evicted = append(evicted, action.(core.CreateAction).GetObject().(*policy.Eviction).Name)
+		}
+		return false, nil, nil
+	})
+
+	handle, _, err := frameworktesting.InitFrameworkHandle(
+		ctx,
+		fakeClient,
+		evictions.NewOptions().WithMaxPodsToEvictTotal(ptr.To[uint](3)),
+		defaultevictor.DefaultEvictorArgs{},
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("Unable to initialize a framework handle: %v", err)
+	}
+
+	plugin, err := NewLowNodeUtilization(&LowNodeUtilizationArgs{
+		Thresholds:       api.ResourceThresholds{v1.ResourceCPU: 30},
+		TargetThresholds: api.ResourceThresholds{v1.ResourceCPU: 50},
+	}, handle)
+	if err != nil {
+		t.Fatalf("Unable to initialize the plugin: %v", err)
+	}
+
+	var mu sync.Mutex
+	filtered := sets.New[string]()
+	podFilter := plugin.(*LowNodeUtilization).podFilter
+	plugin.(*LowNodeUtilization).podFilter = func(pod *v1.Pod) bool {
+		mu.Lock()
+		filtered.Insert(pod.Spec.NodeName)
+		mu.Unlock()
+		return podFilter(pod)
+	}
+	plugin.(frameworktypes.BalancePlugin).Balance(ctx, nodes)
+
+	var evictedFrom []string
+	for _, name := range evicted {
+		evictedFrom = append(evictedFrom, name[:strings.LastIndex(name, "-")])
+	}
+	if expected := []string{"src-39", "src-39", "src-38"}; !slices.Equal(evictedFrom, expected) {
+		t.Errorf("Expected pods to be evicted from %v, got %v (%v)", expected, evictedFrom, evicted)
+	}
+
+	expected := sets.New[string]()
+	for i := sourceNodes - 1; i >= sourceNodes-sourceNodesParallelism; i-- {
+		expected.Insert(fmt.Sprintf("src-%02d", i))
+	}
+	if !filtered.Equal(expected) {
+		t.Errorf("Expected only the pods of the first batch of source nodes to be filtered, got the pods of %v", sets.List(filtered))
+	}
+}
+
+// TestLowNodeUtilizationWithEvictionFailures runs the plugin against an
+// evictor and an API server failing evictions at random, the evictions
+// accounted for must match the evictions that went through and the limits
+// must hold no matter which evictions failed.
+func TestLowNodeUtilizationWithEvictionFailures(t *testing.T) {
+	n1 := test.BuildTestNode("n1", 1000, 3000, 10, nil)
+	n2 := test.BuildTestNode("n2", 1000, 3000, 10, nil)
+	n3 := test.BuildTestNode("n3", 1000, 3000, 10, nil)
+
+	var pods []*v1.Pod
+	for i := 0; i < 8; i++ {
+		pods = append(pods, test.BuildTestPod(fmt.Sprintf("p%d", i), 100, 0, n1.Name, test.SetRSOwnerRef))
+	}
+
+	for _, tc := range []struct {
+		name            string
+		rate            float64
+		failures        []frameworktesting.ChaosFailure
+		decorate        bool
+		evictionOptions *evictions.Options
+		maxPerNode      *uint
+		// maxEvicted is the number of evictions needed to bring n1
+		// down to the target threshold, or the configured limit.
+		maxEvicted uint
+	}{
+		{
+			name:       "api server failing every eviction",
+			rate:       1,
+			maxEvicted: 0,
+		},
+		{
+			name:       "api server failing half of the evictions",
+			rate:       0.5,
+			maxEvicted: 3,
+		},
+		{
+			name:       "api server throttling evictions",
+			rate:       0.7,
+			failures:   []frameworktesting.ChaosFailure{frameworktesting.ChaosTooManyRequests},
+			maxEvicted: 3,
+		},
+		{
+			name:       "api server failing evictions with a node limit",
+			rate:       0.5,
+			maxPerNode: ptr.To[uint](2),
+			maxEvicted: 2,
+		},
+		{
+			name:            "api server failing evictions with a total limit",
+			rate:            0.5,
+			evictionOptions: evictions.NewOptions().WithMaxPodsToEvictTotal(ptr.To[uint](1)),
+			maxEvicted:      1,
+		},
+		{
+			name:       "evictor failing half of the evictions",
+			rate:       0.5,
+			decorate:   true,
+			maxEvicted: 3,
+		},
+		{
+			name:       "evictor failing evictions with a node limit",
+			rate:       0.5,
+			decorate:   true,
+			maxPerNode: ptr.To[uint](2),
+			maxEvicted: 2,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// every seed fails a different set of evictions.
+			for seed := int64(0); seed < 5; seed++ {
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+
+				objs := []runtime.Object{n1, n2, n3}
+				for _, pod := range pods {
+					objs = append(objs, pod)
+				}
+				fakeClient := fake.NewSimpleClientset(objs...)
+
+				chaos := frameworktesting.NewChaos(seed, tc.rate, tc.failures...)
+				if !tc.decorate {
+					fakeClient.PrependReactor("create", "pods/eviction", chaos.EvictionReactor())
+				}
+
+				handle, podEvictor, err := frameworktesting.InitFrameworkHandle(
+					ctx,
+					fakeClient,
+					tc.evictionOptions,
+					defaultevictor.DefaultEvictorArgs{},
+					nil,
+				)
+				if err != nil {
+					t.Fatalf("Unable to initialize a framework handle: %v", err)
+				}
+				if tc.decorate {
+					handle.EvictorImpl = chaos.Evictor(handle)
+				}
+
+				plugin, err := NewLowNodeUtilization(&LowNodeUtilizationArgs{
+					Thresholds:       api.ResourceThresholds{v1.ResourceCPU: 30},
+					TargetThresholds: api.ResourceThresholds{v1.ResourceCPU: 50},
+					EvictionLimits:   &api.EvictionLimits{Node: tc.maxPerNode},
+				}, handle)
+				if err != nil {
+					t.Fatalf("Unable to initialize the plugin: %v", err)
+				}
+
+				status := plugin.(frameworktypes.BalancePlugin).Balance(ctx, []*v1.Node{n1, n2, n3})
+				if status != nil && status.Err != nil {
+					t.Fatalf("seed %v: unexpected error: %v", seed, status.Err)
+				}
+
+				evicted := podEvictor.TotalEvicted()
+				if evicted > tc.maxEvicted {
+					t.Errorf("seed %v: expected at most %v evictions, got %v", seed, tc.maxEvicted, evicted)
+				}
+				// failed evictions are neither counted nor subtracted
+				// from the node usage, every pod is retried until the
+				// node is balanced, the limit is hit or the pods run out.
+				if (evicted == 0 || evicted < tc.maxEvicted) && chaos.Passed()+chaos.TotalInjected() != len(pods) {
+					t.Errorf("seed %v: expected every pod to be tried, got %v evictions let through and %v failures", seed, chaos.Passed(), chaos.TotalInjected())
+				}
+				if uint(chaos.Passed()) != evicted {
+					t.Errorf("seed %v: expected %v evictions accounted for, got %v", seed, chaos.Passed(), evicted)
+				}
+				for failure := range chaos.Injected() {
+					if len(tc.failures) > 0 && !slices.Contains(tc.failures, failure) {
+						t.Errorf("seed %v: unexpected failure %v injected", seed, failure)
+					}
+				}
+			}
+		})
+	}
+}
+
 func withLocalStorage(pod *v1.Pod) {
 	// A pod with local storage.
 	test.SetNormalOwnerRef(pod)
@@ -1606,12 +2636,59 @@ func TestLowNodeUtilizationWithPrometheusMetrics(t *testing.T) {
 	testCases := []struct {
 		name                string
 		samples             model.Vector
+		podSamples          map[string]float64
 		nodes               []*v1.Node
 		pods                []*v1.Pod
 		expectedPodsEvicted uint
 		evictedPods         []string
 		args                *LowNodeUtilizationArgs
 	}{
+		{
+			name: "with instance:node_cpu:rate:sum query and pod query",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					MetricResource: 30,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					MetricResource: 50,
+				},
+				MetricsUtilization: &MetricsUtilization{
+					Source: api.PrometheusMetrics,
+					Prometheus: &Prometheus{
+						Query:    "instance:node_cpu:rate:sum",
+						PodQuery: `pod:node_cpu:share{namespace="{{.Namespace}}",pod="{{.Pod}}"}`,
+					},
+				},
+			},
+			samples: model.Vector{
+				frameworktesting.PrometheusSample("instance:node_cpu:rate:sum", n1NodeName, 0.5695757575757561),
+				frameworktesting.PrometheusSample("instance:node_cpu:rate:sum", n2NodeName, 0.4245454545454522),
+				frameworktesting.PrometheusSample("instance:node_cpu:rate:sum", n3NodeName, 0.20381818181818104),
+			},
+			// every pod uses 4% of n1, evicting two of them takes n1 below
+			// the target threshold.
+			podSamples: map[string]float64{
+				"p1": 0.04, "p2": 0.04, "p3": 0.04, "p4": 0.04, "p5": 0.04,
+			},
+			nodes: []*v1.Node{
+				test.BuildTestNode(n1NodeName, 4000, 3000, 9, nil),
+				test.BuildTestNode(n2NodeName, 4000, 3000, 10, nil),
+				test.BuildTestNode(n3NodeName, 4000, 3000, 10, nil),
+			},
+			pods: []*v1.Pod{
+				test.BuildTestPod("p1", 400, 0, n1NodeName, test.SetRSOwnerRef),
+				test.BuildTestPod("p2", 400, 0, n1NodeName, test.SetRSOwnerRef),
+				test.BuildTestPod("p3", 400, 0, n1NodeName, test.SetRSOwnerRef),
+				test.BuildTestPod("p4", 400, 0, n1NodeName, test.SetRSOwnerRef),
+				test.BuildTestPod("p5", 400, 0, n1NodeName, test.SetRSOwnerRef),
+				// These won't be evicted.
+				test.BuildTestPod("p6", 400, 0, n1NodeName, test.SetDSOwnerRef),
+				test.BuildTestPod("p7", 400, 0, n1NodeName, withLocalStorage),
+				test.BuildTestPod("p8", 400, 0, n1NodeName, withCriticalPod),
+				test.BuildTestPod("p9", 400, 0, n2NodeName, test.SetRSOwnerRef),
+			},
+			expectedPodsEvicted: 2,
+		},
 		{
 			name: "with instance:node_cpu:rate:sum query",
 			args: &LowNodeUtilizationArgs{
@@ -1629,9 +2706,9 @@ func TestLowNodeUtilizationWithPrometheusMetrics(t *testing.T) {
 				},
 			},
 			samples: model.Vector{
-				sample("instance:node_cpu:rate:sum", n1NodeName, 0.5695757575757561),
-				sample("instance:node_cpu:rate:sum", n2NodeName, 0.4245454545454522),
-				sample("instance:node_cpu:rate:sum", n3NodeName, 0.20381818181818104),
+				frameworktesting.PrometheusSample("instance:node_cpu:rate:sum", n1NodeName, 0.5695757575757561),
+				frameworktesting.PrometheusSample("instance:node_cpu:rate:sum", n2NodeName, 0.4245454545454522),
+				frameworktesting.PrometheusSample("instance:node_cpu:rate:sum", n3NodeName, 0.20381818181818104),
 			},
 			nodes: []*v1.Node{
 				test.BuildTestNode(n1NodeName, 4000, 3000, 9, nil),
@@ -1672,9 +2749,9 @@ func TestLowNodeUtilizationWithPrometheusMetrics(t *testing.T) {
 				},
 			},
 			samples: model.Vector{
-				sample("instance:node_cpu:rate:sum", n1NodeName, 0.5695757575757561),
-				sample("instance:node_cpu:rate:sum", n2NodeName, 0.4245454545454522),
-				sample("instance:node_cpu:rate:sum", n3NodeName, 0.20381818181818104),
+				frameworktesting.PrometheusSample("instance:node_cpu:rate:sum", n1NodeName, 0.5695757575757561),
+				frameworktesting.PrometheusSample("instance:node_cpu:rate:sum", n2NodeName, 0.4245454545454522),
+				frameworktesting.PrometheusSample("instance:node_cpu:rate:sum", n3NodeName, 0.20381818181818104),
 			},
 			nodes: []*v1.Node{
 				test.BuildTestNode(n1NodeName, 4000, 3000, 9, nil),
@@ -1716,9 +2793,9 @@ func TestLowNodeUtilizationWithPrometheusMetrics(t *testing.T) {
 				},
 			},
 			samples: model.Vector{
-				sample("instance:node_cpu:rate:sum", n1NodeName, 0.5695757575757561),
-				sample("instance:node_cpu:rate:sum", n2NodeName, 0.4245454545454522),
-				sample("instance:node_cpu:rate:sum", n3NodeName, 0.20381818181818104),
+				frameworktesting.PrometheusSample("instance:node_cpu:rate:sum", n1NodeName, 0.5695757575757561),
+				frameworktesting.PrometheusSample("instance:node_cpu:rate:sum", n2NodeName, 0.4245454545454522),
+				frameworktesting.PrometheusSample("instance:node_cpu:rate:sum", n3NodeName, 0.20381818181818104),
 			},
 			nodes: []*v1.Node{
 				test.BuildTestNode(n1NodeName, 4000, 3000, 9, nil),
@@ -1753,9 +2830,9 @@ func TestLowNodeUtilizationWithPrometheusMetrics(t *testing.T) {
 				},
 			},
 			samples: model.Vector{
-				sample("instance:node_cpu:rate:sum", n1NodeName, 1),
-				sample("instance:node_cpu:rate:sum", n2NodeName, 0.5),
-				sample("instance:node_cpu:rate:sum", n3NodeName, 0),
+				frameworktesting.PrometheusSample("instance:node_cpu:rate:sum", n1NodeName, 1),
+				frameworktesting.PrometheusSample("instance:node_cpu:rate:sum", n2NodeName, 0.5),
+				frameworktesting.PrometheusSample("instance:node_cpu:rate:sum", n3NodeName, 0),
 			},
 			nodes: []*v1.Node{
 				test.BuildTestNode(n1NodeName, 4000, 3000, 9, nil),
@@ -1820,9 +2897,17 @@ func TestLowNodeUtilizationWithPrometheusMetrics(t *testing.T) {
 					t.Fatalf("Unable to initialize a framework handle: %v", err)
 				}
 
-				handle.PrometheusClientImpl = &fakePromClient{
-					result:   tc.samples,
-					dataType: model.ValVector,
+				handle.PrometheusClientImpl = &frameworktesting.FakePrometheusClient{
+					Handler: func(query string) frameworktesting.PrometheusResponse {
+						for pod, usage := range tc.podSamples {
+							if strings.Contains(query, fmt.Sprintf("pod=%q", pod)) {
+								return frameworktesting.PrometheusResponse{Result: model.Vector{
+									frameworktesting.PrometheusSample("pod:node_cpu:share", n1NodeName, usage),
+								}}
+							}
+						}
+						return frameworktesting.PrometheusResponse{Result: tc.samples}
+					},
 				}
 				plugin, err := NewLowNodeUtilization(tc.args, handle)
 				if err != nil {
@@ -1846,3 +2931,97 @@ func TestLowNodeUtilizationWithPrometheusMetrics(t *testing.T) {
 		t.Run(tc.name, testFnc(false, tc.expectedPodsEvicted))
 	}
 }
+
+// setNodeResource sets the capacity and the allocatable of a resource of
+// the node, e.g. ephemeral storage or hugepages.
+func setNodeResource(node *v1.Node, resourceName v1.ResourceName, quantity string) {
+	node.Status.Capacity[resourceName] = resource.MustParse(quantity)
+	node.Status.Allocatable[resourceName] = resource.MustParse(quantity)
+}
+
+// setPodResourceRequest sets the request of a resource of the pod.
+func setPodResourceRequest(pod *v1.Pod, resourceName v1.ResourceName, quantity string) {
+	pod.Spec.Containers[0].Resources.Requests[resourceName] = resource.MustParse(quantity)
+}
+
+func TestLowNodeUtilizationMixedArchitectures(t *testing.T) {
+	const image = "registry.example.com/app:1.0"
+
+	buildNode := func(name, arch string, images ...string) *v1.Node {
+		return test.BuildTestNode(name, 4000, 3000, 10, func(node *v1.Node) {
+			node.Labels[v1.LabelArchStable] = arch
+			for _, name := range images {
+				node.Status.Images = append(node.Status.Images, v1.ContainerImage{Names: []string{name}})
+			}
+		})
+	}
+
+	for _, tc := range []struct {
+		name         string
+		destination  *v1.Node
+		nodeSelector map[string]string
+		expected     int
+	}{
+		{
+			name:        "destination of the same architecture",
+			destination: buildNode("n2", "amd64"),
+			expected:    2,
+		},
+		{
+			name:        "images not known to run on the destination architecture",
+			destination: buildNode("n2", "arm64"),
+			expected:    0,
+		},
+		{
+			name:        "images held by a node of the destination architecture",
+			destination: buildNode("n2", "arm64", image),
+			expected:    2,
+		},
+		{
+			name:         "pods requiring the source architecture",
+			destination:  buildNode("n2", "arm64", image),
+			nodeSelector: map[string]string{v1.LabelArchStable: "amd64"},
+			expected:     0,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			n1 := buildNode("n1", "amd64", image)
+			objs := []runtime.Object{n1, tc.destination}
+			for i := 0; i < 4; i++ {
+				objs = append(objs, test.BuildTestPod(fmt.Sprintf("p%d", i), 950, 0, n1.Name, func(pod *v1.Pod) {
+					test.SetRSOwnerRef(pod)
+					pod.Spec.Containers[0].Image = image
+					pod.Spec.NodeSelector = tc.nodeSelector
+				}))
+			}
+			fakeClient := fake.NewSimpleClientset(objs...)
+
+			handle, podEvictor, err := frameworktesting.InitFrameworkHandle(
+				ctx,
+				fakeClient,
+				nil,
+				defaultevictor.DefaultEvictorArgs{},
+				nil,
+			)
+			if err != nil {
+				t.Fatalf("Unable to initialize a framework handle: %v", err)
+			}
+
+			plugin, err := NewLowNodeUtilization(&LowNodeUtilizationArgs{
+				Thresholds:       api.ResourceThresholds{v1.ResourceCPU: 30},
+				TargetThresholds: api.ResourceThresholds{v1.ResourceCPU: 50},
+			}, handle)
+			if err != nil {
+				t.Fatalf("Unable to initialize the plugin: %v", err)
+			}
+			plugin.(frameworktypes.BalancePlugin).Balance(ctx, []*v1.Node{n1, tc.destination})
+
+			if evicted := podEvictor.TotalEvicted(); evicted != uint(tc.expected) {
+				t.Errorf("Expected %v pods to be evicted, got %v", tc.expected, evicted)
+			}
+		})
+	}
+}
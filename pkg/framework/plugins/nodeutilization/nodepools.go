@@ -0,0 +1,153 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/descheduler/pkg/api"
+)
+
+// defaultThresholdsGroup is the name of the group of the nodes matching no
+// node pool, they are assessed with the plugin thresholds.
+const defaultThresholdsGroup = "default"
+
+// nodePool is a NodePoolThresholds with its node selector parsed.
+type nodePool struct {
+	selector         labels.Selector
+	thresholds       api.ResourceThresholds
+	targetThresholds api.ResourceThresholds
+}
+
+// parseNodePools parses the node selectors of the node pools.
+func parseNodePools(pools []NodePoolThresholds) ([]nodePool, error) {
+	parsed := make([]nodePool, 0, len(pools))
+	for _, pool := range pools {
+		selector, err := metav1.LabelSelectorAsSelector(pool.NodeSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid node pool node selector: %v", err)
+		}
+		parsed = append(parsed, nodePool{
+			selector:         selector,
+			thresholds:       pool.Thresholds,
+			targetThresholds: pool.TargetThresholds,
+		})
+	}
+	return parsed, nil
+}
+
+// nodePoolsResourceNames returns the resources the plugin thresholds and
+// the thresholds of any of the node pools refer to.
+func nodePoolsResourceNames(thresholds api.ResourceThresholds, pools []NodePoolThresholds) []v1.ResourceName {
+	all := maps.Clone(thresholds)
+	for _, pool := range pools {
+		maps.Copy(all, pool.Thresholds)
+	}
+	return getResourceNames(all)
+}
+
+// thresholdsGroup is a set of nodes whose usage is assessed together, with
+// the same thresholds. with deviation or percentile thresholds the average
+// and the percentiles are computed among the nodes of the group.
+type thresholdsGroup struct {
+	name             string
+	nodes            []string
+	thresholds       api.ResourceThresholds
+	targetThresholds api.ResourceThresholds
+}
+
+// thresholdsGroups splits the nodes by the first node pool they match, the
+// nodes matching none are assessed with the provided thresholds. when a
+// topology key is provided groups are further split by topology domain.
+func thresholdsGroups(
+	nodes []*v1.Node,
+	pools []nodePool,
+	thresholds, targetThresholds api.ResourceThresholds,
+	topologyKey string,
+) []thresholdsGroup {
+	groups := map[string]*thresholdsGroup{}
+	for _, node := range nodes {
+		group := thresholdsGroup{
+			name:             defaultThresholdsGroup,
+			thresholds:       thresholds,
+			targetThresholds: targetThresholds,
+		}
+		for i, pool := range pools {
+			if pool.selector.Matches(labels.Set(node.Labels)) {
+				group = thresholdsGroup{
+					name:             fmt.Sprintf("pool-%d", i),
+					thresholds:       pool.thresholds,
+					targetThresholds: pool.targetThresholds,
+				}
+				break
+			}
+		}
+		if topologyKey != "" {
+			group.name = fmt.Sprintf("%s/%s", group.name, node.Labels[topologyKey])
+		}
+
+		if _, ok := groups[group.name]; !ok {
+			groups[group.name] = &group
+		}
+		groups[group.name].nodes = append(groups[group.name].nodes, node.Name)
+	}
+
+	result := make([]thresholdsGroup, 0, len(groups))
+	for _, name := range slices.Sorted(maps.Keys(groups)) {
+		result = append(result, *groups[name])
+	}
+	return result
+}
+
+// assessNodesUsagesPerGroup assesses the usage and the thresholds of the
+// nodes of each group independently. when filter is set only the usage of
+// the resources the thresholds of the group refer to is considered.
+func assessNodesUsagesPerGroup(
+	groups []thresholdsGroup,
+	assess usageAssessor,
+	rawUsages, rawCapacities map[string]api.ReferencedResourceList,
+	filter bool,
+) (map[string]api.ResourceThresholds, map[string][]api.ResourceThresholds) {
+	usage := map[string]api.ResourceThresholds{}
+	thresholds := map[string][]api.ResourceThresholds{}
+	for _, group := range groups {
+		groupUsages := map[string]api.ReferencedResourceList{}
+		groupCapacities := map[string]api.ReferencedResourceList{}
+		for _, name := range group.nodes {
+			groupUsages[name] = rawUsages[name]
+			groupCapacities[name] = rawCapacities[name]
+		}
+		if filter {
+			groupUsages = filterResourceNames(groupUsages, getResourceNames(group.thresholds))
+		}
+
+		klog.V(3).InfoS("Assessing the usage of the node group", "group", group.name)
+		groupUsage, groupThresholds := assess(
+			groupUsages, groupCapacities, group.thresholds, group.targetThresholds,
+		)
+		maps.Copy(usage, groupUsage)
+		maps.Copy(thresholds, groupThresholds)
+	}
+	return usage, thresholds
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/descheduler/pkg/api"
+	"sigs.k8s.io/descheduler/pkg/framework/plugins/defaultevictor"
+	frameworktesting "sigs.k8s.io/descheduler/pkg/framework/testing"
+	frameworktypes "sigs.k8s.io/descheduler/pkg/framework/types"
+	"sigs.k8s.io/descheduler/test"
+)
+
+func withLabel(key, value string) func(*v1.Node) {
+	return func(node *v1.Node) {
+		node.Labels[key] = value
+	}
+}
+
+func TestThresholdsGroups(t *testing.T) {
+	gpu := NodePoolThresholds{
+		NodeSelector:     &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "gpu"}},
+		Thresholds:       api.ResourceThresholds{v1.ResourceCPU: 40},
+		TargetThresholds: api.ResourceThresholds{v1.ResourceCPU: 90},
+	}
+	pools, err := parseNodePools([]NodePoolThresholds{gpu})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	nodes := []*v1.Node{
+		test.BuildTestNode("n1", 1000, 1000, 10, withLabel("pool", "gpu")),
+		test.BuildTestNode("n2", 1000, 1000, 10, nil),
+		test.BuildTestNode("n3", 1000, 1000, 10, withZone("a")),
+		test.BuildTestNode("n4", 1000, 1000, 10, withZone("b")),
+	}
+	thresholds := api.ResourceThresholds{v1.ResourceCPU: 20}
+	targetThresholds := api.ResourceThresholds{v1.ResourceCPU: 50}
+
+	for _, tc := range []struct {
+		name        string
+		topologyKey string
+		expected    []thresholdsGroup
+	}{
+		{
+			name: "node pools",
+			expected: []thresholdsGroup{
+				{name: "default", nodes: []string{"n2", "n3", "n4"}, thresholds: thresholds, targetThresholds: targetThresholds},
+				{name: "pool-0", nodes: []string{"n1"}, thresholds: gpu.Thresholds, targetThresholds: gpu.TargetThresholds},
+			},
+		},
+		{
+			name:        "node pools and topology domains",
+			topologyKey: testTopologyKey,
+			expected: []thresholdsGroup{
+				{name: "default/", nodes: []string{"n2"}, thresholds: thresholds, targetThresholds: targetThresholds},
+				{name: "default/a", nodes: []string{"n3"}, thresholds: thresholds, targetThresholds: targetThresholds},
+				{name: "default/b", nodes: []string{"n4"}, thresholds: thresholds, targetThresholds: targetThresholds},
+				{name: "pool-0/", nodes: []string{"n1"}, thresholds: gpu.Thresholds, targetThresholds: gpu.TargetThresholds},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			groups := thresholdsGroups(nodes, pools, thresholds, targetThresholds, tc.topologyKey)
+			if diff := cmp.Diff(tc.expected, groups, cmp.AllowUnexported(thresholdsGroup{})); diff != "" {
+				t.Errorf("Unexpected groups (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAssessNodesUsagesPerGroup(t *testing.T) {
+	usages := map[string]api.ReferencedResourceList{
+		"n1": frameworktesting.BuildNodeUsage().WithCPU("3200m").WithMemory("100").Build(),
+		"n2": frameworktesting.BuildNodeUsage().WithCPU("800m").WithMemory("100").Build(),
+		"n3": frameworktesting.BuildNodeUsage().WithCPU("400m").WithMemory("100").Build(),
+		"n4": frameworktesting.BuildNodeUsage().WithCPU("400m").WithMemory("100").Build(),
+	}
+	capacities := map[string]api.ReferencedResourceList{}
+	for name := range usages {
+		capacities[name] = frameworktesting.BuildNodeUsage().WithCPU("4").WithMemory("1000").Build()
+	}
+	deviation := api.ResourceThresholds{v1.ResourceCPU: 10}
+	groups := []thresholdsGroup{
+		{name: "a", nodes: []string{"n1", "n2"}, thresholds: deviation, targetThresholds: deviation},
+		{name: "b", nodes: []string{"n3", "n4"}, thresholds: deviation, targetThresholds: api.ResourceThresholds{v1.ResourceCPU: 20}},
+	}
+
+	usage, thresholds := assessNodesUsagesPerGroup(
+		groups, assessNodesUsagesAndRelativeThresholds, usages, capacities, true,
+	)
+
+	if len(usage) != 4 {
+		t.Fatalf("Expected the usage of 4 nodes, got %v", usage)
+	}
+	for node, nodeUsage := range usage {
+		if _, ok := nodeUsage[v1.ResourceMemory]; ok {
+			t.Errorf("Expected the usage of %v to be filtered, got %v", node, nodeUsage)
+		}
+	}
+	// the average of group a is 50%, the one of group b is 10%.
+	for node, expected := range map[string][]api.Percentage{
+		"n1": {40, 60},
+		"n2": {40, 60},
+		"n3": {0, 30},
+		"n4": {0, 30},
+	} {
+		low, high := thresholds[node][0][v1.ResourceCPU], thresholds[node][1][v1.ResourceCPU]
+		if low != expected[0] || high != expected[1] {
+			t.Errorf("Expected thresholds %v for %v, got [%v %v]", expected, node, low, high)
+		}
+	}
+}
+
+func TestLowNodeUtilizationNodePools(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		nodePools []NodePoolThresholds
+		expected  map[string]uint
+	}{
+		{
+			name:     "plugin thresholds only",
+			expected: map[string]uint{"n1": 2},
+		},
+		{
+			name: "gpu nodes tolerate a higher usage",
+			nodePools: []NodePoolThresholds{
+				{
+					NodeSelector:     &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "gpu"}},
+					Thresholds:       api.ResourceThresholds{v1.ResourceCPU: 30},
+					TargetThresholds: api.ResourceThresholds{v1.ResourceCPU: 90},
+				},
+			},
+			expected: map[string]uint{"n2": 2},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			// n1 and n2 are equally used, n1 is a gpu node.
+			nodes := []*v1.Node{
+				test.BuildTestNode("n1", 4000, 3000, 10, withLabel("pool", "gpu")),
+				test.BuildTestNode("n2", 4000, 3000, 10, nil),
+				test.BuildTestNode("n3", 4000, 3000, 10, nil),
+			}
+			objs := []runtime.Object{}
+			for _, node := range nodes {
+				objs = append(objs, node)
+			}
+			for _, node := range []string{"n1", "n2"} {
+				for i := 0; i < 4; i++ {
+					objs = append(objs, test.BuildTestPod(fmt.Sprintf("%s-p%d", node, i), 800, 0, node, test.SetRSOwnerRef))
+				}
+			}
+			objs = append(objs, test.BuildTestPod("n3-p0", 400, 0, "n3", test.SetRSOwnerRef))
+
+			handle, podEvictor, err := frameworktesting.InitFrameworkHandle(
+				ctx,
+				fake.NewSimpleClientset(objs...),
+				nil,
+				defaultevictor.DefaultEvictorArgs{},
+				func(pods []*v1.Pod) {
+					sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+				},
+			)
+			if err != nil {
+				t.Fatalf("Unable to initialize a framework handle: %v", err)
+			}
+
+			plugin, err := NewLowNodeUtilization(&LowNodeUtilizationArgs{
+				Thresholds:       api.ResourceThresholds{v1.ResourceCPU: 30},
+				TargetThresholds: api.ResourceThresholds{v1.ResourceCPU: 50},
+				NodePools:        tc.nodePools,
+			}, handle)
+			if err != nil {
+				t.Fatalf("Unable to initialize the plugin: %v", err)
+			}
+
+			if status := plugin.(frameworktypes.BalancePlugin).Balance(ctx, nodes); status != nil && status.Err != nil {
+				t.Fatalf("Unexpected error: %v", status.Err)
+			}
+
+			for _, node := range nodes {
+				if evicted := podEvictor.NodeEvicted(node); evicted != tc.expected[node.Name] {
+					t.Errorf("Expected %v pods to be evicted from %v, got %v", tc.expected[node.Name], node.Name, evicted)
+				}
+			}
+		})
+	}
+}
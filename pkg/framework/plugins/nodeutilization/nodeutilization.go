@@ -20,22 +20,30 @@ import (
 	"context"
 	"fmt"
 	"maps"
+	"math"
 	"slices"
 	"sort"
+	"time"
 
 	"sigs.k8s.io/descheduler/pkg/api"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
+	"sigs.k8s.io/descheduler/metrics"
 	"sigs.k8s.io/descheduler/pkg/descheduler/evictions"
 	nodeutil "sigs.k8s.io/descheduler/pkg/descheduler/node"
 	"sigs.k8s.io/descheduler/pkg/descheduler/pod"
 	podutil "sigs.k8s.io/descheduler/pkg/descheduler/pod"
 	"sigs.k8s.io/descheduler/pkg/framework/plugins/nodeutilization/normalizer"
 	frameworktypes "sigs.k8s.io/descheduler/pkg/framework/types"
+	"sigs.k8s.io/descheduler/pkg/tracing"
 	"sigs.k8s.io/descheduler/pkg/utils"
 )
 
@@ -81,6 +89,9 @@ const (
 	MinResourcePercentage = 0
 	// MaxResourcePercentage is the maximum value of a resource's percentage
 	MaxResourcePercentage = 100
+	// sourceNodesParallelism is the number of source nodes whose eviction
+	// candidates are selected in parallel, in batches of that size.
+	sourceNodesParallelism = 16
 )
 
 // NodeUsage stores a node's info, pods on it, thresholds and its resource
@@ -98,6 +109,136 @@ type NodeUsage struct {
 type NodeInfo struct {
 	NodeUsage
 	available api.ReferencedResourceList
+	// cost is the cost of running the node, zero if not known.
+	cost float64
+}
+
+// balanceSummary gathers the outcome of a single Balance invocation so it
+// can be logged as one structured entry once the invocation is over,
+// instead of having to correlate the many log lines emitted during it.
+type balanceSummary struct {
+	plugin         string
+	start          time.Time
+	underutilized  int
+	overutilized   int
+	evicted        int
+	skipped        int
+	evictedPerNode map[string]int
+	deltas         map[string]utilizationDelta
+	usage          map[string]api.ResourceThresholds
+	thresholds     map[string][]api.ResourceThresholds
+	categories     map[string]string
+	// dryRun records the pods that would have been evicted when the
+	// plugin runs in dry run mode, nil otherwise.
+	dryRun         *dryRunEvictor
+	projectedUsage map[string]api.ResourceThresholds
+	// destinations holds the headroom left in the destination nodes once
+	// the evicted pods are placed, only known with a scoring strategy.
+	destinations map[string]destinationHeadroom
+	// sinks receive a decision record for every evicted pod.
+	sinks []DecisionSink
+	// evictedPerWorkload counts the evicted replicas of every workload.
+	evictedPerWorkload map[ownerKey]uint
+	// budgets are the disruption budgets the evictions are charged to,
+	// blockedByBudget counts the pods skipped as their budget is
+	// exhausted.
+	budgets         *disruptionBudgets
+	blockedByBudget int
+	// considered counts the pods inspected for eviction, skippedPerReason
+	// the pods skipped by the reason they were skipped for.
+	considered       int
+	skippedPerReason map[string]int
+	// available is what the destination nodes could still accept once
+	// the evictions are over.
+	available api.ReferencedResourceList
+	// errors hit during the invocation, reported in the run status.
+	errors []string
+	// resized counts the pods shrunk in place instead of being evicted.
+	resized int
+}
+
+// summaryObserver, when set, is handed every summary once the Balance
+// invocation it belongs to is over. it is meant to be used by tests.
+var summaryObserver func(*balanceSummary)
+
+// utilizationDelta holds, for a single node, by how much its utilization was
+// expected to drop after pods were evicted from it and by how much it has
+// actually dropped. values are percentages of the node capacity.
+type utilizationDelta struct {
+	predicted api.ResourceThresholds
+	achieved  api.ResourceThresholds
+}
+
+// newBalanceSummary returns a summary for the provided plugin. the summary
+// duration is measured from the moment this function is called.
+func newBalanceSummary(plugin string) *balanceSummary {
+	return &balanceSummary{
+		plugin:         plugin,
+		start:          time.Now(),
+		evictedPerNode: map[string]int{},
+		deltas:         map[string]utilizationDelta{},
+		categories:     map[string]string{},
+
+		evictedPerWorkload: map[ownerKey]uint{},
+		skippedPerReason:   map[string]int{},
+	}
+}
+
+// assessed keeps the normalized usage and thresholds of every node, as
+// they were used to classify the nodes.
+func (s *balanceSummary) assessed(usage map[string]api.ResourceThresholds, thresholds map[string][]api.ResourceThresholds) {
+	s.usage, s.thresholds = usage, thresholds
+}
+
+// classified accounts for a node classified in the provided category.
+func (s *balanceSummary) classified(node, category string) {
+	s.categories[node] = category
+}
+
+// podEvicted accounts for a pod evicted from the provided node.
+func (s *balanceSummary) podEvicted(node string) {
+	s.evicted++
+	s.evictedPerNode[node]++
+}
+
+// placed keeps the headroom left in the destination nodes of the ranker once
+// the evicted pods are placed on them.
+func (s *balanceSummary) placed(ranker *destinationRanker) {
+	headroom := ranker.headroom()
+	for _, name := range sortedNodeNames(headroom) {
+		klog.V(3).InfoS(
+			"Destination node headroom",
+			append([]any{"node", name, "evictedPods", headroom[name].pods}, usageToKeysAndValues(headroom[name].available)...)...,
+		)
+		if s.destinations == nil {
+			s.destinations = map[string]destinationHeadroom{}
+		}
+		s.destinations[name] = headroom[name]
+	}
+}
+
+// keysAndValues converts the summary into a list of keys and values.
+func (s *balanceSummary) keysAndValues() []any {
+	return []any{
+		"plugin", s.plugin,
+		"underutilizedNodes", s.underutilized,
+		"overutilizedNodes", s.overutilized,
+		"evictedPods", s.evicted,
+		"skippedPods", s.skipped,
+		"blockedByDisruptionBudget", s.blockedByBudget,
+		"resizedPods", s.resized,
+		"duration", time.Since(s.start),
+	}
+}
+
+// log emits the summary as a single structured log entry.
+func (s *balanceSummary) log() {
+	klog.InfoS("Balance summary", s.keysAndValues()...)
+	s.logDryRunReport()
+	s.exportMetrics()
+	if summaryObserver != nil {
+		summaryObserver(s)
+	}
 }
 
 // continueEvictionCont is a function that determines if we should keep
@@ -110,7 +251,7 @@ type continueEvictionCond func(NodeInfo, api.ReferencedResourceList) bool
 // by node name.
 func getNodeUsageSnapshot(
 	nodes []*v1.Node,
-	usageClient usageClient,
+	usageClient UsageClient,
 ) (
 	map[string]*v1.Node,
 	map[string]api.ReferencedResourceList,
@@ -124,8 +265,8 @@ func getNodeUsageSnapshot(
 
 	for _, node := range nodes {
 		nodesMap[node.Name] = node
-		nodesUsageMap[node.Name] = usageClient.nodeUtilization(node.Name)
-		podListMap[node.Name] = usageClient.pods(node.Name)
+		nodesUsageMap[node.Name] = usageClient.NodeUtilization(node.Name)
+		podListMap[node.Name] = usageClient.Pods(node.Name)
 	}
 
 	return nodesMap, nodesUsageMap, podListMap
@@ -135,12 +276,19 @@ func getNodeUsageSnapshot(
 // and values. this is useful for logging.
 func thresholdsToKeysAndValues(thresholds api.ResourceThresholds) []any {
 	result := []any{}
-	for name, value := range thresholds {
-		result = append(result, name, fmt.Sprintf("%.2f%%", value))
+	for _, name := range sets.List(sets.KeySet(thresholds)) {
+		result = append(result, string(name), fmt.Sprintf("%.2f%%", thresholds[name]))
 	}
 	return result
 }
 
+// sortedNodeNames returns the node names indexing the provided map in
+// alphabetical order. nodes are processed in this order so the outcome
+// does not depend on the map iteration order.
+func sortedNodeNames[V any](nodes map[string]V) []string {
+	return sets.List(sets.KeySet(nodes))
+}
+
 // usageToKeysAndValues converts a ReferencedResourceList into a list of
 // keys and values. this is useful for logging.
 func usageToKeysAndValues(usage api.ReferencedResourceList) []any {
@@ -154,57 +302,265 @@ func usageToKeysAndValues(usage api.ReferencedResourceList) []any {
 	if quantity, exists := usage[v1.ResourcePods]; exists {
 		keysAndValues = append(keysAndValues, "Pods", quantity.Value())
 	}
-	for name := range usage {
+	for _, name := range sets.List(sets.KeySet(usage)) {
 		if !nodeutil.IsBasicResource(name) {
-			keysAndValues = append(keysAndValues, name, usage[name].Value())
+			keysAndValues = append(keysAndValues, string(name), usage[name].Value())
 		}
 	}
 	return keysAndValues
 }
 
+// evictionOptions holds what drives the eviction of the pods of the source
+// nodes, whatever nodes they are moved between. the plugins build it once
+// per Balance invocation.
+type evictionOptions struct {
+	evictableNamespaces *api.Namespaces
+	podEvictor          frameworktypes.Evictor
+	evictOptions        evictions.EvictOptions
+	podFilter           func(pod *v1.Pod) bool
+	resourceNames       []v1.ResourceName
+	continueEviction    continueEvictionCond
+	usageClient         UsageClient
+	limits              *api.EvictionLimits
+	scoringStrategy     *ScoringStrategy
+	evictionOrder       EvictionOrder
+	podSorter           PodSorter
+	affinityAwareness   AffinityAwareness
+	fairnessPolicy      FairnessPolicy
+	rateLimiter         flowcontrol.RateLimiter
+	nodeIndexer         podutil.GetPodsAssignedToNodeFunc
+	devices             *nodeutil.DeviceAccounting
+}
+
+// nodeLimit returns the maximum number of pods evicted per node, nil if
+// not limited.
+func (o *evictionOptions) nodeLimit() *uint {
+	if o.limits == nil {
+		return nil
+	}
+	return o.limits.Node
+}
+
+// totalLimit returns the maximum number of pods evicted per cycle, nil if
+// not limited.
+func (o *evictionOptions) totalLimit() *uint {
+	if o.limits == nil {
+		return nil
+	}
+	return o.limits.Total
+}
+
+// workloadLimit returns the maximum number of pods evicted per workload,
+// nil if not limited.
+func (o *evictionOptions) workloadLimit() *uint {
+	if o.limits == nil {
+		return nil
+	}
+	return o.limits.Workload
+}
+
+// evictionDestinations tracks, while pods are evicted from the source nodes,
+// the room left on the destination nodes and where the evicted pods are
+// expected to land.
+type evictionDestinations struct {
+	headroom *platformHeadroom
+	taints   map[string][]v1.Taint
+	podFits  func(pod *v1.Pod) bool
+	ranker   *destinationRanker
+}
+
 // evictPodsFromSourceNodes evicts pods based on priority, if all the pods on
 // the node have priority, if not evicts them based on QoS as fallback option.
+// a pod sorter, if provided, sorts the pods of every node before that.
+// the pods are evicted node after node unless the eviction order sequences
+// them by priority across all the source nodes. when a scoring strategy is
+// provided the evicted pods are returned together with the node they are
+// expected to be scheduled on.
 func evictPodsFromSourceNodes(
 	ctx context.Context,
-	evictableNamespaces *api.Namespaces,
 	sourceNodes, destinationNodes []NodeInfo,
-	podEvictor frameworktypes.Evictor,
-	evictOptions evictions.EvictOptions,
-	podFilter func(pod *v1.Pod) bool,
-	resourceNames []v1.ResourceName,
-	continueEviction continueEvictionCond,
-	usageClient usageClient,
-	maxNoOfPodsToEvictPerNode *uint,
-) {
-	available, err := assessAvailableResourceInNodes(destinationNodes, resourceNames)
+	opts *evictionOptions,
+	summary *balanceSummary,
+) []podPlacement {
+	// the evictions are issued within the span so the eviction spans
+	// are nested in it.
+	ctx, span := tracing.Tracer().Start(ctx, "evictPodsFromSourceNodes", trace.WithAttributes(
+		attribute.String("plugin", summary.plugin),
+		attribute.Int("sourceNodes", len(sourceNodes)),
+		attribute.Int("destinationNodes", len(destinationNodes)),
+	))
+	defer func() {
+		span.SetAttributes(
+			attribute.Int("evictedPods", summary.evicted),
+			attribute.Int("skippedPods", summary.skipped),
+		)
+		span.End()
+	}()
+
+	headroom, err := newPlatformHeadroom(sourceNodes, destinationNodes, opts.resourceNames)
 	if err != nil {
 		klog.ErrorS(err, "unable to assess available resources in nodes")
-		return
+		span.RecordError(err)
+		summary.failed(err)
+		return nil
 	}
 
-	klog.V(1).InfoS("Total capacity to be moved", usageToKeysAndValues(available)...)
+	// when a scoring strategy is configured every evicted pod is matched
+	// against the destination node the scheduler is expected to pick.
+	ranker := newDestinationRanker(opts.scoringStrategy, destinationNodes, opts.resourceNames, headroom.images)
+	defer summary.placed(ranker)
+	defer summary.remaining(headroom)
+
+	klog.V(1).InfoS("Total capacity to be moved", usageToKeysAndValues(headroom.total())...)
 
 	destinationTaints := make(map[string][]v1.Taint, len(destinationNodes))
+	destinations := make([]*v1.Node, 0, len(destinationNodes))
 	for _, node := range destinationNodes {
 		destinationTaints[node.node.Name] = node.node.Spec.Taints
+		destinations = append(destinations, node.node)
 	}
 
+	// podFits tells if the scheduler could place the pod on any of the
+	// destination nodes other than the one it runs on. pods fitting none
+	// of them would land back on the node they are evicted from.
+	podFits := func(pod *v1.Pod) bool {
+		return nodeutil.PodFitsAnyOtherNodeWithDevices(opts.nodeIndexer, opts.devices, pod, destinations)
+	}
+
+	// pods expected to be scheduled back on their node because of their
+	// affinity are skipped or evicted last.
+	nodes := slices.Clone(destinations)
 	for _, node := range sourceNodes {
+		nodes = append(nodes, node.node)
+	}
+	affinity := newAffinityAssessor(opts.affinityAwareness, opts.nodeIndexer, nodes)
+
+	// prepareCandidates selects the eviction candidates (filters and sorts
+	// the pods) of the source nodes in the [from, to) range. filtering is
+	// independent for each of the source nodes so it is done in parallel,
+	// custom pod eviction orders and the fairness policy are applied
+	// serially as the usage clients are not safe to be called
+	// concurrently.
+	candidates := make([][]*v1.Pod, len(sourceNodes))
+	prepareCandidates := func(from, to int) {
+		workqueue.ParallelizeUntil(ctx, sourceNodesParallelism, to-from, func(j int) {
+			i := from + j
+			node := sourceNodes[i]
+			nonRemovablePods, removablePods := classifyPods(node.allPods, opts.podFilter)
+			klog.V(2).InfoS(
+				"Pods on node",
+				"node", klog.KObj(node.node),
+				"allPods", len(node.allPods),
+				"nonRemovablePods", len(nonRemovablePods),
+				"removablePods", len(removablePods),
+			)
+
+			// sort the evictable Pods based on priority. This also sorts
+			// them based on QoS. If there are multiple pods with same
+			// priority, they are sorted based on QoS tiers.
+			podutil.SortPodsBasedOnPriorityLowToHigh(removablePods)
+			candidates[i] = removablePods
+		})
+
+		for i := from; i < to; i++ {
+			node := sourceNodes[i]
+			if opts.podSorter != nil {
+				opts.podSorter.Sort(candidates[i], node.usage, opts.usageClient.PodUsage)
+			}
+			candidates[i] = spreadAcrossNamespaces(opts.fairnessPolicy, candidates[i], node.usage, opts.usageClient.PodUsage)
+
+			var skipped int
+			candidates[i], skipped = affinity.apply(candidates[i], node.node, destinations)
+			for range skipped {
+				summary.podSkipped(skipReasonAffinity)
+			}
+		}
+	}
+
+	destinationsState := &evictionDestinations{
+		headroom: headroom,
+		taints:   destinationTaints,
+		podFits:  podFits,
+		ranker:   ranker,
+	}
+
+	// totalLimitReached tells if the plugin evicted as many pods as it is
+	// allowed to during the cycle. the summary spans the whole cycle so
+	// the limit holds across calls.
+	totalLimitReached := func() bool {
+		limit := opts.totalLimit()
+		return limit != nil && uint(summary.evicted) >= *limit
+	}
+
+	// evictFromNode evicts pods among the provided ones from the i-th
+	// source node. the per node limit holds across calls for the same
+	// node. it returns false when no more pods can be evicted at all.
+	evicted := make([]uint, len(sourceNodes))
+	evictFromNode := func(i int, pods []*v1.Pod) bool {
+		node := sourceNodes[i]
+		if totalLimitReached() {
+			return false
+		}
+
+		// the per node limit does not apply to nodes being terminated
+		// by their cloud provider, their pods are leaving anyway.
+		nodeLimit := opts.nodeLimit()
+		if nodeutil.IsNodeBeingTerminated(node.node) {
+			nodeLimit = nil
+		}
+		if nodeLimit != nil {
+			if evicted[i] >= *nodeLimit {
+				return true
+			}
+			nodeLimit = ptr.To(*nodeLimit - evicted[i])
+		}
+
+		count, err := evictPods(ctx, pods, node, nodeLimit, destinationsState, opts, summary)
+		evicted[i] += count
+		if _, ok := err.(*evictions.EvictionTotalLimitError); ok {
+			return false
+		}
+		if _, ok := err.(*rateLimitError); ok {
+			return false
+		}
+		return !totalLimitReached() && headroom.hasPodSlots()
+	}
+
+	if opts.evictionOrder == EvictionOrderPriorityBands {
+		// a priority band is exhausted on all the source nodes before
+		// any pod of the next, higher, band is evicted, the candidates of
+		// all the source nodes are needed upfront.
+		prepareCandidates(0, len(sourceNodes))
+		for _, band := range priorityBands(candidates) {
+			klog.V(3).InfoS("Evicting pods of priority band", "priority", band)
+			for i := range sourceNodes {
+				pods := podsOfPriorityBand(candidates[i], band)
+				if len(pods) == 0 {
+					continue
+				}
+				if !evictFromNode(i, pods) {
+					return ranker.evictedPlacements()
+				}
+			}
+		}
+		return ranker.evictedPlacements()
+	}
+
+	for i, node := range sourceNodes {
+		// the candidates are selected in batches as the source nodes are
+		// reached, the nodes left once no more pods can be evicted are
+		// not filtered at all.
+		if i%sourceNodesParallelism == 0 {
+			prepareCandidates(i, min(i+sourceNodesParallelism, len(sourceNodes)))
+		}
+
 		klog.V(3).InfoS(
 			"Evicting pods from node",
 			"node", klog.KObj(node.node),
 			"usage", node.usage,
 		)
 
-		nonRemovablePods, removablePods := classifyPods(node.allPods, podFilter)
-		klog.V(2).InfoS(
-			"Pods on node",
-			"node", klog.KObj(node.node),
-			"allPods", len(node.allPods),
-			"nonRemovablePods", len(nonRemovablePods),
-			"removablePods", len(removablePods),
-		)
-
+		removablePods := candidates[i]
 		if len(removablePods) == 0 {
 			klog.V(1).InfoS(
 				"No removable pods on node, try next node",
@@ -217,60 +573,80 @@ func evictPodsFromSourceNodes(
 			"Evicting pods based on priority, if they have same priority, they'll be evicted based on QoS tiers",
 		)
 
-		// sort the evictable Pods based on priority. This also sorts
-		// them based on QoS. If there are multiple pods with same
-		// priority, they are sorted based on QoS tiers.
-		podutil.SortPodsBasedOnPriorityLowToHigh(removablePods)
-
-		if err := evictPods(
-			ctx,
-			evictableNamespaces,
-			removablePods,
-			node,
-			available,
-			destinationTaints,
-			podEvictor,
-			evictOptions,
-			continueEviction,
-			usageClient,
-			maxNoOfPodsToEvictPerNode,
-		); err != nil {
-			switch err.(type) {
-			case *evictions.EvictionTotalLimitError:
-				return
-			default:
-			}
+		if !evictFromNode(i, removablePods) {
+			break
 		}
 	}
+	return ranker.evictedPlacements()
+}
+
+// podPriorityBand returns the priority band of the pod. pods without a
+// priority belong to the lowest band.
+func podPriorityBand(pod *v1.Pod) int64 {
+	if pod.Spec.Priority == nil {
+		return math.MinInt64
+	}
+	return int64(*pod.Spec.Priority)
+}
+
+// priorityBands returns the priority bands of the provided pods, from the
+// lowest to the highest.
+func priorityBands(pods [][]*v1.Pod) []int64 {
+	bands := sets.New[int64]()
+	for _, nodePods := range pods {
+		for _, pod := range nodePods {
+			bands.Insert(podPriorityBand(pod))
+		}
+	}
+	return sets.List(bands)
+}
+
+// podsOfPriorityBand returns the pods of the priority band, in the order
+// they are provided.
+func podsOfPriorityBand(pods []*v1.Pod, band int64) []*v1.Pod {
+	var result []*v1.Pod
+	for _, pod := range pods {
+		if podPriorityBand(pod) == band {
+			result = append(result, pod)
+		}
+	}
+	return result
 }
 
 // evictPods keeps evicting pods until the continueEviction function returns
 // false or we can't or shouldn't evict any more pods. available node resources
-// are updated after each eviction.
+// are updated after each eviction. pods are only evicted if they fit on a
+// destination node, as the scheduler would assess it, and if a destination
+// node of a platform they can run on has room for them, pod slots included.
+// it returns the number of evicted pods.
 func evictPods(
 	ctx context.Context,
-	evictableNamespaces *api.Namespaces,
 	inputPods []*v1.Pod,
 	nodeInfo NodeInfo,
-	totalAvailableUsage api.ReferencedResourceList,
-	destinationTaints map[string][]v1.Taint,
-	podEvictor frameworktypes.Evictor,
-	evictOptions evictions.EvictOptions,
-	continueEviction continueEvictionCond,
-	usageClient usageClient,
 	maxNoOfPodsToEvictPerNode *uint,
-) error {
+	destinations *evictionDestinations,
+	opts *evictionOptions,
+	summary *balanceSummary,
+) (uint, error) {
 	// preemptive check to see if we should continue evicting pods.
-	if !continueEviction(nodeInfo, totalAvailableUsage) {
-		return nil
+	headroom, ranker := destinations.headroom, destinations.ranker
+	if !opts.continueEviction(nodeInfo, headroom.total()) {
+		return 0, nil
+	}
+
+	source := nodeutil.NodePlatform(nodeInfo.node)
+	hasRoom := func(available api.ReferencedResourceList) bool {
+		return opts.continueEviction(nodeInfo, available)
 	}
 
 	// some namespaces can be excluded from the eviction process.
 	var excludedNamespaces sets.Set[string]
-	if evictableNamespaces != nil {
-		excludedNamespaces = sets.New(evictableNamespaces.Exclude...)
+	if opts.evictableNamespaces != nil {
+		excludedNamespaces = sets.New(opts.evictableNamespaces.Exclude...)
 	}
 
+	maxNoOfPodsToEvictTotal, maxNoOfPodsToEvictPerWorkload := opts.totalLimit(), opts.workloadLimit()
+
 	var evictionCounter uint = 0
 	for _, pod := range inputPods {
 		if maxNoOfPodsToEvictPerNode != nil && evictionCounter >= *maxNoOfPodsToEvictPerNode {
@@ -280,12 +656,27 @@ func evictPods(
 			)
 			break
 		}
+		if maxNoOfPodsToEvictTotal != nil && uint(summary.evicted) >= *maxNoOfPodsToEvictTotal {
+			klog.V(3).InfoS(
+				"Max number of evictions per cycle per plugin reached",
+				"limit", *maxNoOfPodsToEvictTotal,
+			)
+			break
+		}
+		// destination nodes may run out of pods they can accept well
+		// before running out of cpu or memory.
+		if !headroom.hasPodSlots() {
+			klog.V(3).InfoS("Destination nodes can not accept any more pods")
+			break
+		}
 
-		if !utils.PodToleratesTaints(pod, destinationTaints) {
+		summary.considered++
+		if !utils.PodToleratesTaints(pod, destinations.taints) {
 			klog.V(3).InfoS(
 				"Skipping eviction for pod, doesn't tolerate node taint",
 				"pod", klog.KObj(pod),
 			)
+			summary.podSkipped(skipReasonTaints)
 			continue
 		}
 
@@ -293,66 +684,297 @@ func evictPods(
 		// filter and on the excluded namespaces.
 		preEvictionFilterWithOptions, err := podutil.
 			NewOptions().
-			WithFilter(podEvictor.PreEvictionFilter).
+			WithFilter(opts.podEvictor.PreEvictionFilter).
 			WithoutNamespaces(excludedNamespaces).
 			BuildFilterFunc()
 		if err != nil {
 			klog.ErrorS(err, "could not build preEvictionFilter with namespace exclusion")
+			summary.podSkipped(skipReasonFilter)
 			continue
 		}
 
 		if !preEvictionFilterWithOptions(pod) {
+			summary.podSkipped(skipReasonFilter)
+			continue
+		}
+
+		// replicas of the same workload are not all evicted at once,
+		// whatever node they run on.
+		if summary.workloadLimitReached(pod, maxNoOfPodsToEvictPerWorkload) {
+			klog.V(3).InfoS(
+				"Skipping eviction for pod, max number of evictions per workload reached",
+				"pod", klog.KObj(pod),
+				"limit", *maxNoOfPodsToEvictPerWorkload,
+			)
+			summary.podSkipped(skipReasonWorkloadLimit)
+			continue
+		}
+
+		// evicting pods whose disruption budget is exhausted would be
+		// refused by the api server, wasting the eviction limits.
+		if err := summary.budgets.check(pod); err != nil {
+			klog.V(3).InfoS(
+				"Skipping eviction for pod, its disruption budget does not allow it",
+				"pod", klog.KObj(pod),
+				"reason", err,
+			)
+			summary.podSkipped(skipReasonDisruptionBudget)
+			summary.blockedByBudget++
+			continue
+		}
+
+		// the node selector, the affinity, the taints and the requests
+		// of the pod must allow it on a destination node.
+		if !destinations.podFits(pod) {
+			klog.V(3).InfoS(
+				"Skipping eviction for pod, it does not fit on any destination node",
+				"pod", klog.KObj(pod),
+			)
+			summary.podSkipped(skipReasonNoFit)
 			continue
 		}
 
+		// the pod usage is only retrieved for pods that passed all
+		// the filters as it may require a call to the metrics api.
 		// in case podUsage does not support resource counting (e.g.
 		// provided metric does not quantify pod resource utilization).
 		unconstrainedResourceEviction := false
-		podUsage, err := usageClient.podUsage(pod)
+		podUsage, err := opts.usageClient.PodUsage(pod)
 		if err != nil {
 			if _, ok := err.(*notSupportedError); !ok {
 				klog.Errorf(
 					"unable to get pod usage for %v/%v: %v",
 					pod.Namespace, pod.Name, err,
 				)
+				summary.podSkipped(skipReasonUsageUnavailable)
 				continue
 			}
 			unconstrainedResourceEviction = true
 		}
 
-		if err := podEvictor.Evict(ctx, pod, evictOptions); err != nil {
+		// the pod must be able to run on the platform of the nodes
+		// whose available resources it is accounted in. without the
+		// pod usage only the platform compatibility is evaluated.
+		roomCheck := hasRoom
+		if unconstrainedResourceEviction {
+			roomCheck = nil
+		}
+		platform, ok := headroom.pick(pod, source, roomCheck)
+		if !ok {
+			klog.V(3).InfoS(
+				"Skipping eviction for pod, no destination node of a platform it can run on has room for it",
+				"pod", klog.KObj(pod),
+				"platform", source.String(),
+			)
+			summary.podSkipped(skipReasonNoRoom)
+			continue
+		}
+
+		// pods are only evicted if the node the scheduler is expected
+		// to place them on is among the destination nodes.
+		var destination *NodeInfo
+		if ranker != nil && !unconstrainedResourceEviction {
+			if destination = ranker.pick(pod, podUsage, source); destination == nil {
+				klog.V(3).InfoS(
+					"Skipping eviction for pod, it does not fit on any destination node",
+					"pod", klog.KObj(pod),
+				)
+				summary.podSkipped(skipReasonNoFit)
+				continue
+			}
+			platform = nodeutil.NodePlatform(destination.node)
+		}
+
+		// pods selected in dry run mode are not evicted, they do not
+		// take tokens.
+		if opts.rateLimiter != nil && !frameworktypes.IsDryRun(opts.podEvictor) {
+			if err := takeEvictionToken(opts.rateLimiter); err != nil {
+				return evictionCounter, err
+			}
+		}
+
+		// the eviction event tells why the pod was picked.
+		podEvictOptions := opts.evictOptions
+		podEvictOptions.Details = summary.eventDetails(nodeInfo, podUsage, destination)
+		if err := opts.podEvictor.Evict(ctx, pod, podEvictOptions); err != nil {
 			switch err.(type) {
 			case *evictions.EvictionNodeLimitError, *evictions.EvictionTotalLimitError:
-				return err
+				return evictionCounter, err
 			default:
 				klog.Errorf("eviction failed: %v", err)
+				summary.failed(err)
+				summary.podSkipped(skipReasonEvictionFailed)
 				continue
 			}
 		}
+		summary.podEvicted(nodeInfo.node.Name)
+		summary.workloadEvicted(pod)
+		summary.budgets.evicted(pod)
+		summary.recordDecision(pod, nodeInfo, podUsage, destination, frameworktypes.IsDryRun(opts.podEvictor))
+		headroom.takePodSlot(platform)
+		if destination != nil {
+			ranker.assign(pod, destination, podUsage)
+		}
 
+		evictionCounter++
 		if maxNoOfPodsToEvictPerNode == nil && unconstrainedResourceEviction {
 			klog.V(3).InfoS("Currently, only a single pod eviction is allowed")
 			break
 		}
 
-		evictionCounter++
 		klog.V(3).InfoS("Evicted pods", "pod", klog.KObj(pod))
 		if unconstrainedResourceEviction {
 			continue
 		}
 
-		subtractPodUsageFromNodeAvailability(totalAvailableUsage, &nodeInfo, podUsage)
+		subtractPodUsageFromNodeAvailability(headroom.available[platform], &nodeInfo, podUsage)
 
 		keysAndValues := []any{"node", nodeInfo.node.Name}
 		keysAndValues = append(keysAndValues, usageToKeysAndValues(nodeInfo.usage)...)
 		klog.V(3).InfoS("Updated node usage", keysAndValues...)
 
 		// make sure we should continue evicting pods.
-		if !continueEviction(nodeInfo, totalAvailableUsage) {
+		if !opts.continueEviction(nodeInfo, headroom.total()) {
 			break
 		}
 	}
-	return nil
+	return evictionCounter, nil
+}
+
+// copyNodesUsage returns a deep copy of the usage of the provided nodes
+// indexed by node name. the usage of the nodes is updated in place during
+// the eviction process, this copy preserves the values prior to it.
+func copyNodesUsage(nodes []NodeInfo) map[string]api.ReferencedResourceList {
+	result := map[string]api.ReferencedResourceList{}
+	for _, node := range nodes {
+		result[node.node.Name] = copyUsage(node.usage)
+	}
+	return result
+}
+
+// copyUsage returns a deep copy of the provided usage, nil quantities are
+// omitted.
+func copyUsage(from api.ReferencedResourceList) api.ReferencedResourceList {
+	usage := api.ReferencedResourceList{}
+	for name, quantity := range from {
+		if quantity == nil {
+			continue
+		}
+		usage[name] = ptr.To(quantity.DeepCopy())
+	}
+	return usage
+}
+
+// pendingUtilizationDeltas holds the utilization drops predicted for the
+// nodes pods were evicted from, until they are measured on the next cycle,
+// and the gauge series set by the last measurement. it is kept in the shared
+// cache as the plugins are created again on every cycle.
+type pendingUtilizationDeltas struct {
+	before    map[string]api.ResourceThresholds
+	predicted map[string]api.ResourceThresholds
+	evicted   map[string]int
+	series    []map[string]string
+}
+
+// utilizationDeltasFor returns the pending utilization deltas of the plugin
+// of the profile of the handle.
+func utilizationDeltasFor(handle frameworktypes.Handle, plugin string) *pendingUtilizationDeltas {
+	cache := frameworktypes.SharedCacheFor(handle)
+	key := fmt.Sprintf("nodeutilization/utilizationdeltas/%s/%s", frameworktypes.ProfileNameFor(handle), plugin)
+	obj, ok := cache.Get(key)
+	if !ok {
+		obj = &pendingUtilizationDeltas{}
+	}
+	cache.Set(key, obj, nodeStateTTL)
+	return obj.(*pendingUtilizationDeltas)
+}
+
+// recordUtilizationDeltas keeps the utilization drop predicted during the
+// eviction process for the nodes pods were evicted from. the achieved drop
+// can not be measured within the same cycle: the evicted pods are hidden
+// from the pods index and the usage sources have not caught up yet. it is
+// measured by measureUtilizationDeltas on the next cycle instead.
+func recordUtilizationDeltas(
+	deltas *pendingUtilizationDeltas,
+	sourceNodes []NodeInfo,
+	preEvictionUsage map[string]api.ReferencedResourceList,
+	capacities map[string]api.ReferencedResourceList,
+	summary *balanceSummary,
+) {
+	beforeUsage := map[string]api.ReferencedResourceList{}
+	predictedUsage := map[string]api.ReferencedResourceList{}
+	deltas.evicted = map[string]int{}
+	for _, node := range sourceNodes {
+		name := node.node.Name
+		if summary.evictedPerNode[name] == 0 {
+			continue
+		}
+		beforeUsage[name] = preEvictionUsage[name]
+		predictedUsage[name] = node.usage
+		deltas.evicted[name] = summary.evictedPerNode[name]
+	}
+
+	deltas.before = normalizer.Normalize(beforeUsage, capacities, ResourceUsageToResourceThreshold)
+	predicted := normalizer.Normalize(predictedUsage, capacities, ResourceUsageToResourceThreshold)
+	deltas.predicted = map[string]api.ResourceThresholds{}
+	for name, usage := range predicted {
+		deltas.predicted[name] = normalizer.Sum(deltas.before[name], normalizer.Negate(usage))
+	}
+}
+
+// measureUtilizationDeltas compares, for the nodes pods were evicted from on
+// the previous cycle, the utilization drop predicted then with the one
+// achieved, i.e. the difference between the usage before the evictions and
+// the current usage. both deltas are stored in the summary and their average
+// over the measured nodes is exposed as metrics so the accuracy of the
+// eviction model can be assessed. the series set by the previous measurement
+// are deleted first, a plugin that evicted nothing since does not keep
+// reporting stale deltas.
+func measureUtilizationDeltas(
+	deltas *pendingUtilizationDeltas,
+	usage map[string]api.ReferencedResourceList,
+	capacities map[string]api.ReferencedResourceList,
+	summary *balanceSummary,
+) {
+	for _, labels := range deltas.series {
+		metrics.BalancePredictedUtilizationDelta.Delete(labels)
+		metrics.BalanceAchievedUtilizationDelta.Delete(labels)
+	}
+	deltas.series = nil
+
+	current := normalizer.Normalize(usage, capacities, ResourceUsageToResourceThreshold)
+	predicted, achieved := map[string]api.ResourceThresholds{}, map[string]api.ResourceThresholds{}
+	for _, name := range slices.Sorted(maps.Keys(deltas.predicted)) {
+		// nodes gone, or not assessed this time, can not be measured.
+		nodeUsage, ok := current[name]
+		if !ok {
+			continue
+		}
+
+		delta := utilizationDelta{
+			predicted: deltas.predicted[name],
+			achieved:  normalizer.Sum(deltas.before[name], normalizer.Negate(nodeUsage)),
+		}
+		summary.deltas[name] = delta
+		predicted[name], achieved[name] = delta.predicted, delta.achieved
+
+		klog.V(1).InfoS(
+			"Node utilization after the evictions of the previous cycle",
+			"node", klog.KRef("", name),
+			"evictedPods", deltas.evicted[name],
+			"predictedDelta", normalizer.Round(delta.predicted),
+			"achievedDelta", normalizer.Round(delta.achieved),
+		)
+	}
+
+	averagePredicted, averageAchieved := normalizer.Average(predicted), normalizer.Average(achieved)
+	for resourceName := range averagePredicted {
+		labels := map[string]string{"strategy": summary.plugin, "resource": string(resourceName)}
+		metrics.BalancePredictedUtilizationDelta.With(labels).Set(float64(averagePredicted[resourceName]))
+		metrics.BalanceAchievedUtilizationDelta.With(labels).Set(float64(averageAchieved[resourceName]))
+		deltas.series = append(deltas.series, labels)
+	}
+	deltas.before, deltas.predicted, deltas.evicted = nil, nil, nil
 }
 
 // subtractPodUsageFromNodeAvailability subtracts the pod usage from the node
@@ -375,35 +997,165 @@ func subtractPodUsageFromNodeAvailability(
 }
 
 // sortNodesByUsage sorts nodes based on usage according to the given plugin.
-func sortNodesByUsage(nodes []NodeInfo, ascending bool) {
-	sort.Slice(nodes, func(i, j int) bool {
-		ti := resource.NewQuantity(0, resource.DecimalSI).Value()
-		tj := resource.NewQuantity(0, resource.DecimalSI).Value()
-		for resourceName := range nodes[i].usage {
-			if resourceName == v1.ResourceCPU {
-				ti += nodes[i].usage[resourceName].MilliValue()
-			} else {
-				ti += nodes[i].usage[resourceName].Value()
-			}
+// the usage of each node is summed up once, before sorting, with every
+// resource multiplied by its weight. resources without a weight (or all of
+// them if weights is nil) have a weight of one.
+func sortNodesByUsage(nodes []NodeInfo, ascending bool, weights map[v1.ResourceName]float64) {
+	sortNodesByScore(nodes, ascending, func(node NodeInfo) float64 {
+		return nodeUsageScore(node.usage, weights)
+	})
+}
+
+// sortNodesByWeightedUsage sorts nodes based on their usage percentages,
+// each multiplied by the weight of its resource, so resources measured in
+// different units are compared on the same scale. without weights nodes
+// are sorted by their absolute usage instead.
+func sortNodesByWeightedUsage(
+	nodes []NodeInfo,
+	ascending bool,
+	usage map[string]api.ResourceThresholds,
+	weights map[v1.ResourceName]float64,
+) {
+	if len(weights) == 0 {
+		sortNodesByUsage(nodes, ascending, nil)
+		return
+	}
+	sortNodesByScore(nodes, ascending, func(node NodeInfo) float64 {
+		var score float64
+		for name, value := range usage[node.node.Name] {
+			score += resourceWeight(weights, name) * float64(value)
 		}
-		for resourceName := range nodes[j].usage {
-			if resourceName == v1.ResourceCPU {
-				tj += nodes[j].usage[resourceName].MilliValue()
-			} else {
-				tj += nodes[j].usage[resourceName].Value()
+		return score
+	})
+}
+
+// sortNodesByScore sorts nodes by the provided score, computed once for
+// every node before sorting.
+func sortNodesByScore(nodes []NodeInfo, ascending bool, score func(NodeInfo) float64) {
+	scored := make([]scoredNodeInfo, len(nodes))
+	for i := range nodes {
+		scored[i] = scoredNodeInfo{
+			score:    score(nodes[i]),
+			NodeInfo: nodes[i],
+		}
+	}
+
+	sort.Slice(scored, func(i, j int) bool {
+		// nodes with the same score are ordered by cost, the most
+		// expensive first as nodes are drained in this order, then by
+		// name so the result does not depend on the order nodes were
+		// provided.
+		if scored[i].score == scored[j].score {
+			if scored[i].cost != scored[j].cost {
+				return scored[i].cost > scored[j].cost
 			}
+			return scored[i].node.Name < scored[j].node.Name
 		}
 
 		// Return ascending order for HighNodeUtilization plugin
 		if ascending {
-			return ti < tj
+			return scored[i].score < scored[j].score
 		}
 
 		// Return descending order for LowNodeUtilization plugin
-		return ti > tj
+		return scored[i].score > scored[j].score
+	})
+
+	for i := range scored {
+		nodes[i] = scored[i].NodeInfo
+	}
+}
+
+// preferNodesMarkedForDeletion moves the nodes a node autoscaler is about
+// to remove, or considers removing, to the front of the list, keeping the
+// relative order of the remaining nodes. pods are evicted from those nodes
+// first as they are likely to be rescheduled elsewhere anyway.
+func preferNodesMarkedForDeletion(nodes []NodeInfo) {
+	marked := func(node *v1.Node) bool {
+		return nodeutil.IsNodeMarkedForDeletion(node) || nodeutil.IsNodeCandidateForDeletion(node)
+	}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return marked(nodes[i].node) && !marked(nodes[j].node)
+	})
+	// nodes being terminated by their cloud provider go first, they
+	// will be gone in a couple of minutes.
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return nodeutil.IsNodeBeingTerminated(nodes[i].node) &&
+			!nodeutil.IsNodeBeingTerminated(nodes[j].node)
 	})
 }
 
+// scoredNodeInfo is a NodeInfo together with its usage score.
+type scoredNodeInfo struct {
+	NodeInfo
+	score float64
+}
+
+// nodeUsageScore sums up the usage of all resources, cpu is taken in milli
+// values and all other resources in their default unit. each resource is
+// multiplied by its weight, if one is provided.
+func nodeUsageScore(usage api.ReferencedResourceList, weights map[v1.ResourceName]float64) float64 {
+	var score float64
+	for resourceName, quantity := range usage {
+		if quantity == nil {
+			continue
+		}
+
+		value := quantity.Value()
+		if resourceName == v1.ResourceCPU {
+			value = quantity.MilliValue()
+		}
+
+		score += resourceWeight(weights, resourceName) * float64(value)
+	}
+	return score
+}
+
+// resourceWeight returns the weight of the resource, resources without a
+// weight have a weight of one.
+func resourceWeight(weights map[v1.ResourceName]float64, name v1.ResourceName) float64 {
+	weight, ok := weights[name]
+	if !ok {
+		return 1
+	}
+	return weight
+}
+
+// weightedAverage averages the values of the resources present in the
+// threshold, each one multiplied by the weight of its resource.
+func weightedAverage(values, threshold api.ResourceThresholds, weights map[v1.ResourceName]float64) float64 {
+	var sum, total float64
+	for name := range threshold {
+		weight := resourceWeight(weights, name)
+		sum += weight * float64(values[name])
+		total += weight
+	}
+	if total == 0 {
+		return 0
+	}
+	return sum / total
+}
+
+// isNodeAboveWeightedThreshold checks if a node is over a threshold. with
+// weights the weighted average of the usage has to be above the weighted
+// average of the threshold, without them isNodeAboveThreshold is used.
+func isNodeAboveWeightedThreshold(usage, threshold api.ResourceThresholds, weights map[v1.ResourceName]float64) bool {
+	if len(weights) == 0 {
+		return isNodeAboveThreshold(usage, threshold)
+	}
+	return weightedAverage(threshold, threshold, weights) < weightedAverage(usage, threshold, weights)
+}
+
+// isNodeBelowWeightedThreshold checks if a node is under a threshold. with
+// weights the weighted average of the usage has to be below the weighted
+// average of the threshold, without them isNodeBelowThreshold is used.
+func isNodeBelowWeightedThreshold(usage, threshold api.ResourceThresholds, weights map[v1.ResourceName]float64) bool {
+	if len(weights) == 0 {
+		return isNodeBelowThreshold(usage, threshold)
+	}
+	return weightedAverage(threshold, threshold, weights) >= weightedAverage(usage, threshold, weights)
+}
+
 // isNodeAboveTargetUtilization checks if a node is overutilized
 // At least one resource has to be above the high threshold
 func isNodeAboveTargetUtilization(usage NodeUsage, threshold api.ReferencedResourceList) bool {
@@ -427,6 +1179,41 @@ func isNodeAboveThreshold(usage, threshold api.ResourceThresholds) bool {
 	return false
 }
 
+// noNodeAboveThresholds is a cheap check telling if no node can be above
+// its threshold. for each resource it compares the highest usage in the
+// cluster against the lowest threshold among all nodes, if the former does
+// not exceed the latter for any resource no node can be classified as
+// overutilized. thresholds are indexed by node name and the threshold at
+// the provided position is used.
+func noNodeAboveThresholds(
+	usage map[string]api.ResourceThresholds,
+	thresholds map[string][]api.ResourceThresholds,
+	position int,
+) bool {
+	highestUsage := api.ResourceThresholds{}
+	for _, nodeUsage := range usage {
+		for name, value := range nodeUsage {
+			if current, ok := highestUsage[name]; !ok || value > current {
+				highestUsage[name] = value
+			}
+		}
+	}
+
+	lowestThreshold := api.ResourceThresholds{}
+	for _, nodeThresholds := range thresholds {
+		if len(nodeThresholds) <= position {
+			continue
+		}
+		for name, value := range nodeThresholds[position] {
+			if current, ok := lowestThreshold[name]; !ok || value < current {
+				lowestThreshold[name] = value
+			}
+		}
+	}
+
+	return !isNodeAboveThreshold(highestUsage, lowestThreshold)
+}
+
 // isNodeBelowThreshold checks if a node is under a threshold
 // All resources have to be below the threshold
 func isNodeBelowThreshold(usage, threshold api.ResourceThresholds) bool {
@@ -463,6 +1250,26 @@ func classifyPods(pods []*v1.Pod, filter func(pod *v1.Pod) bool) ([]*v1.Pod, []*
 	return nonRemovablePods, removablePods
 }
 
+// usageAssessor converts the raw usage data into percentage and assesses the
+// thresholds of each node out of the user provided ones. Returns the usage
+// (pct) and the thresholds (pct) for each node.
+type usageAssessor func(
+	rawUsages, rawCapacities map[string]api.ReferencedResourceList,
+	low, high api.ResourceThresholds,
+) (map[string]api.ResourceThresholds, map[string][]api.ResourceThresholds)
+
+// normalizeNodesUsage converts the raw usage of the nodes (Mi, Gi, etc) into
+// percentages of their capacity. nodes whose capacity is unknown can not be
+// assessed, rather than silently shrinking the set of nodes being balanced
+// they are reported before being left out.
+func normalizeNodesUsage(rawUsages, rawCapacities map[string]api.ReferencedResourceList) map[string]api.ResourceThresholds {
+	usage, err := normalizer.NormalizeStrict(rawUsages, rawCapacities, ResourceUsageToResourceThreshold)
+	if err != nil {
+		klog.ErrorS(err, "Unable to assess the usage of nodes without capacity, they are not balanced")
+	}
+	return usage
+}
+
 // assessNodesUsagesAndStaticThresholds converts the raw usage data into
 // percentage. Returns the usage (pct) and the thresholds (pct) for each
 // node.
@@ -472,9 +1279,7 @@ func assessNodesUsagesAndStaticThresholds(
 ) (map[string]api.ResourceThresholds, map[string][]api.ResourceThresholds) {
 	// first we normalize the node usage from the raw data (Mi, Gi, etc)
 	// into api.Percentage values.
-	usage := normalizer.Normalize(
-		rawUsages, rawCapacities, ResourceUsageToResourceThreshold,
-	)
+	usage := normalizeNodesUsage(rawUsages, rawCapacities)
 
 	// we are not taking the average and applying deviations to it we can
 	// simply replicate the same threshold across all nodes and return.
@@ -492,19 +1297,117 @@ func assessNodesUsagesAndRelativeThresholds(
 	rawUsages, rawCapacities map[string]api.ReferencedResourceList,
 	lowSpan, highSpan api.ResourceThresholds,
 ) (map[string]api.ResourceThresholds, map[string][]api.ResourceThresholds) {
-	// first we normalize the node usage from the raw data (Mi, Gi, etc)
-	// into api.Percentage values.
-	usage := normalizer.Normalize(
-		rawUsages, rawCapacities, ResourceUsageToResourceThreshold,
+	return relativeThresholdsAssessor(DeviationCenterAverage, 0)(
+		rawUsages, rawCapacities, lowSpan, highSpan,
 	)
+}
 
-	// calculate the average usage.
+// defaultDeviationTrimPercentage is the percentage of the least and of the
+// most used nodes left out of the trimmed mean when none is configured.
+const defaultDeviationTrimPercentage = 10
+
+// relativeThresholdsAssessor returns an assessor converting the raw usage
+// data into percentage and calculating the thresholds around the center
+// of the usage: the average, the median or the trimmed mean, trimming pct
+// percent of the nodes on each side.
+func relativeThresholdsAssessor(center DeviationCenter, pct api.Percentage) usageAssessor {
+	return func(
+		rawUsages, rawCapacities map[string]api.ReferencedResourceList,
+		lowSpan, highSpan api.ResourceThresholds,
+	) (map[string]api.ResourceThresholds, map[string][]api.ResourceThresholds) {
+		// first we normalize the node usage from the raw data (Mi, Gi,
+		// etc) into api.Percentage values.
+		usage := normalizeNodesUsage(rawUsages, rawCapacities)
+
+		var centerUsage api.ResourceThresholds
+		switch center {
+		case DeviationCenterMedian:
+			centerUsage = normalizer.Median(usage)
+		case DeviationCenterTrimmedMean:
+			if pct == 0 {
+				pct = defaultDeviationTrimPercentage
+			}
+			centerUsage = normalizer.TrimmedMean(usage, float64(pct))
+		default:
+			center = DeviationCenterAverage
+			centerUsage = normalizer.Average(usage)
+		}
+		klog.V(3).InfoS(
+			"Assessed usage center",
+			append([]any{"center", center}, thresholdsToKeysAndValues(centerUsage)...)...,
+		)
+
+		return usage, thresholdsAroundAverage(usage, centerUsage, lowSpan, highSpan)
+	}
+}
+
+// assessNodesUsagesAndStdDeviationThresholds converts the raw usage data
+// into percentage. Thresholds are calculated based on the average usage
+// and on its standard deviation, lowFactors and highFactors are the number
+// of standard deviations below and above the average. Returns the usage
+// (pct) and the thresholds (pct) for each node.
+func assessNodesUsagesAndStdDeviationThresholds(
+	rawUsages, rawCapacities map[string]api.ReferencedResourceList,
+	lowFactors, highFactors api.ResourceThresholds,
+) (map[string]api.ResourceThresholds, map[string][]api.ResourceThresholds) {
+	usage := normalizeNodesUsage(rawUsages, rawCapacities)
+
+	// calculate the average usage and how spread the usage is around it.
 	average := normalizer.Average(usage)
+	stdDeviation := normalizer.StdDeviation(usage, average)
 	klog.V(3).InfoS(
 		"Assessed average usage",
 		thresholdsToKeysAndValues(average)...,
 	)
+	klog.V(3).InfoS(
+		"Assessed usage standard deviation",
+		thresholdsToKeysAndValues(stdDeviation)...,
+	)
+
+	return usage, thresholdsAroundAverage(
+		usage,
+		average,
+		normalizer.Multiply(lowFactors, stdDeviation),
+		normalizer.Multiply(highFactors, stdDeviation),
+	)
+}
+
+// assessNodesUsagesAndPercentileThresholds converts the raw usage data into
+// percentage. Thresholds are the usage of the nodes at the lowPercentile
+// and highPercentile percentiles, nodes at or below the first are
+// underutilized and nodes above the second are overutilized. Returns the
+// usage (pct) and the thresholds (pct) for each node.
+func assessNodesUsagesAndPercentileThresholds(
+	rawUsages, rawCapacities map[string]api.ReferencedResourceList,
+	lowPercentile, highPercentile api.ResourceThresholds,
+) (map[string]api.ResourceThresholds, map[string][]api.ResourceThresholds) {
+	usage := normalizeNodesUsage(rawUsages, rawCapacities)
+
+	lowerThresholds := normalizer.Percentile(usage, lowPercentile)
+	klog.V(3).InfoS(
+		"Assessed thresholds for underutilized nodes",
+		thresholdsToKeysAndValues(lowerThresholds)...,
+	)
+
+	higherThresholds := normalizer.Percentile(usage, highPercentile)
+	klog.V(3).InfoS(
+		"Assessed thresholds for overutilized nodes",
+		thresholdsToKeysAndValues(higherThresholds)...,
+	)
 
+	thresholds := normalizer.Replicate(
+		slices.Collect(maps.Keys(usage)),
+		[]api.ResourceThresholds{lowerThresholds, higherThresholds},
+	)
+	return usage, thresholds
+}
+
+// thresholdsAroundAverage returns, for each node, the thresholds spanning
+// from the average usage minus lowSpan to the average usage plus highSpan.
+func thresholdsAroundAverage(
+	usage map[string]api.ResourceThresholds,
+	average, lowSpan, highSpan api.ResourceThresholds,
+) map[string][]api.ResourceThresholds {
 	// decrease the provided threshold from the average to get the low
 	// span. also make sure the resulting values are between 0 and 100.
 	lowerThresholds := normalizer.Clamp(
@@ -526,12 +1429,10 @@ func assessNodesUsagesAndRelativeThresholds(
 	)
 
 	// replicate the same assessed thresholds to all nodes.
-	thresholds := normalizer.Replicate(
+	return normalizer.Replicate(
 		slices.Collect(maps.Keys(usage)),
 		[]api.ResourceThresholds{lowerThresholds, higherThresholds},
 	)
-
-	return usage, thresholds
 }
 
 // referencedResourceListForNodesCapacity returns a ReferencedResourceList for
@@ -570,6 +1471,22 @@ func referencedResourceListForNodeCapacity(node *v1.Node) api.ReferencedResource
 	return referenced
 }
 
+// addDeviceCapacities adds the devices published for the nodes through
+// Dynamic Resource Allocation to their capacities. Resources the nodes
+// report themselves are left untouched.
+func addDeviceCapacities(capacities map[string]api.ReferencedResourceList, nodes []*v1.Node, devices *nodeutil.DeviceAccounting) {
+	if devices == nil {
+		return
+	}
+	for _, node := range nodes {
+		for name, quantity := range devices.NodeCapacity(node) {
+			if _, ok := capacities[node.Name][name]; !ok {
+				capacities[node.Name][name] = ptr.To(quantity)
+			}
+		}
+	}
+}
+
 // ResourceUsage2ResourceThreshold is an implementation of a Normalizer that
 // converts a set of resource usages and totals into percentage. This function
 // operates on Quantity Value() for all the resources except CPU, where it uses
@@ -634,14 +1551,14 @@ func filterResourceNames(
 // thresholds. if a threshold is not set for a resource, the full capacity is
 // returned.
 func capNodeCapacitiesToThreshold(
-	node *v1.Node,
+	capacities api.ReferencedResourceList,
 	thresholds api.ResourceThresholds,
 	resourceNames []v1.ResourceName,
 ) api.ReferencedResourceList {
 	capped := api.ReferencedResourceList{}
 	for _, resourceName := range resourceNames {
 		capped[resourceName] = capNodeCapacityToThreshold(
-			node, thresholds, resourceName,
+			capacities, thresholds, resourceName,
 		)
 	}
 	return capped
@@ -650,10 +1567,9 @@ func capNodeCapacitiesToThreshold(
 // capNodeCapacityToThreshold caps the node capacity to the given threshold. if
 // no threshold is set for the resource, the full capacity is returned.
 func capNodeCapacityToThreshold(
-	node *v1.Node, thresholds api.ResourceThresholds, resourceName v1.ResourceName,
+	capacities api.ReferencedResourceList, thresholds api.ResourceThresholds, resourceName v1.ResourceName,
 ) *resource.Quantity {
-	capacities := referencedResourceListForNodeCapacity(node)
-	if _, ok := capacities[resourceName]; !ok {
+	if quantity, ok := capacities[resourceName]; !ok || quantity == nil {
 		// if the node knows nothing about the resource we return a
 		// zero capacity for it.
 		return resource.NewQuantity(0, resource.DecimalSI)
@@ -661,7 +1577,7 @@ func capNodeCapacityToThreshold(
 
 	// if no threshold is set then we simply return the full capacity.
 	if _, ok := thresholds[resourceName]; !ok {
-		return capacities[resourceName]
+		return ptr.To(capacities[resourceName].DeepCopy())
 	}
 
 	// now that we have a capacity and a threshold we need to do the math
@@ -669,12 +1585,10 @@ func capNodeCapacityToThreshold(
 	quantity := capacities[resourceName]
 	threshold := thresholds[resourceName]
 
-	// we have a different format for memory. all the other resources are
-	// in the DecimalSI format.
-	format := resource.DecimalSI
-	if resourceName == v1.ResourceMemory {
-		format = resource.BinarySI
-	}
+	// resources measured in bytes (memory, ephemeral storage and huge
+	// pages) use the BinarySI format. all the other resources are in the
+	// DecimalSI format.
+	format := utils.ResourceQuantityFormat(resourceName)
 
 	// this is what we use to cap the capacity. thresholds are expected to
 	// be in the <0;100> interval.
@@ -732,7 +1646,7 @@ func assessAvailableResourceInNodes(
 			// first time seeing this resource, initialize it.
 			if _, ok := available[resourceName]; !ok {
 				available[resourceName] = resource.NewQuantity(
-					0, resource.DecimalSI,
+					0, utils.ResourceQuantityFormat(resourceName),
 				)
 			}
 
@@ -19,6 +19,7 @@ package nodeutilization
 import (
 	"math"
 	"reflect"
+	"slices"
 	"testing"
 
 	v1 "k8s.io/api/core/v1"
@@ -26,8 +27,10 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/descheduler/pkg/api"
+	nodeutil "sigs.k8s.io/descheduler/pkg/descheduler/node"
 	"sigs.k8s.io/descheduler/pkg/framework/plugins/nodeutilization/classifier"
 	"sigs.k8s.io/descheduler/pkg/framework/plugins/nodeutilization/normalizer"
+	frameworktesting "sigs.k8s.io/descheduler/pkg/framework/testing"
 )
 
 func BuildTestNodeInfo(name string, apply func(*NodeInfo)) *NodeInfo {
@@ -54,6 +57,13 @@ func BuildTestNodeInfo(name string, apply func(*NodeInfo)) *NodeInfo {
 	return nodeInfo
 }
 
+// withUsage sets the usage of the node built by BuildTestNodeInfo.
+func withUsage(usage *frameworktesting.UsageBuilder) func(*NodeInfo) {
+	return func(nodeInfo *NodeInfo) {
+		nodeInfo.usage = usage.Build()
+	}
+}
+
 var (
 	lowPriority      = int32(0)
 	highPriority     = int32(10000)
@@ -64,61 +74,45 @@ func TestSortNodesByUsage(t *testing.T) {
 	tests := []struct {
 		name                  string
 		nodeInfoList          []NodeInfo
+		weights               map[v1.ResourceName]float64
 		expectedNodeInfoNames []string
 	}{
 		{
 			name: "cpu memory pods",
 			nodeInfoList: []NodeInfo{
-				*BuildTestNodeInfo("node1", func(nodeInfo *NodeInfo) {
-					nodeInfo.usage = api.ReferencedResourceList{
-						v1.ResourceCPU:    resource.NewMilliQuantity(1730, resource.DecimalSI),
-						v1.ResourceMemory: resource.NewQuantity(3038982964, resource.BinarySI),
-						v1.ResourcePods:   resource.NewQuantity(25, resource.BinarySI),
-					}
-				}),
-				*BuildTestNodeInfo("node2", func(nodeInfo *NodeInfo) {
-					nodeInfo.usage = api.ReferencedResourceList{
-						v1.ResourceCPU:    resource.NewMilliQuantity(1220, resource.DecimalSI),
-						v1.ResourceMemory: resource.NewQuantity(3038982964, resource.BinarySI),
-						v1.ResourcePods:   resource.NewQuantity(11, resource.BinarySI),
-					}
-				}),
-				*BuildTestNodeInfo("node3", func(nodeInfo *NodeInfo) {
-					nodeInfo.usage = api.ReferencedResourceList{
-						v1.ResourceCPU:    resource.NewMilliQuantity(1530, resource.DecimalSI),
-						v1.ResourceMemory: resource.NewQuantity(5038982964, resource.BinarySI),
-						v1.ResourcePods:   resource.NewQuantity(20, resource.BinarySI),
-					}
-				}),
+				*BuildTestNodeInfo("node1", withUsage(frameworktesting.BuildNodeUsage().WithCPU("1730m").WithMemory("3038982964").WithPods(25))),
+				*BuildTestNodeInfo("node2", withUsage(frameworktesting.BuildNodeUsage().WithCPU("1220m").WithMemory("3038982964").WithPods(11))),
+				*BuildTestNodeInfo("node3", withUsage(frameworktesting.BuildNodeUsage().WithCPU("1530m").WithMemory("5038982964").WithPods(20))),
 			},
 			expectedNodeInfoNames: []string{"node3", "node1", "node2"},
 		},
 		{
 			name: "memory",
 			nodeInfoList: []NodeInfo{
-				*BuildTestNodeInfo("node1", func(nodeInfo *NodeInfo) {
-					nodeInfo.usage = api.ReferencedResourceList{
-						v1.ResourceMemory: resource.NewQuantity(3038982964, resource.BinarySI),
-					}
-				}),
-				*BuildTestNodeInfo("node2", func(nodeInfo *NodeInfo) {
-					nodeInfo.usage = api.ReferencedResourceList{
-						v1.ResourceMemory: resource.NewQuantity(2038982964, resource.BinarySI),
-					}
-				}),
-				*BuildTestNodeInfo("node3", func(nodeInfo *NodeInfo) {
-					nodeInfo.usage = api.ReferencedResourceList{
-						v1.ResourceMemory: resource.NewQuantity(5038982964, resource.BinarySI),
-					}
-				}),
+				*BuildTestNodeInfo("node1", withUsage(frameworktesting.BuildNodeUsage().WithMemory("3038982964"))),
+				*BuildTestNodeInfo("node2", withUsage(frameworktesting.BuildNodeUsage().WithMemory("2038982964"))),
+				*BuildTestNodeInfo("node3", withUsage(frameworktesting.BuildNodeUsage().WithMemory("5038982964"))),
 			},
 			expectedNodeInfoNames: []string{"node3", "node1", "node2"},
 		},
+		{
+			name: "weighted cpu memory",
+			nodeInfoList: []NodeInfo{
+				*BuildTestNodeInfo("node1", withUsage(frameworktesting.BuildNodeUsage().WithCPU("1730m").WithMemory("1000"))),
+				*BuildTestNodeInfo("node2", withUsage(frameworktesting.BuildNodeUsage().WithCPU("1220m").WithMemory("2000"))),
+				*BuildTestNodeInfo("node3", withUsage(frameworktesting.BuildNodeUsage().WithCPU("1530m").WithMemory("3000"))),
+			},
+			weights: map[v1.ResourceName]float64{
+				v1.ResourceCPU:    0,
+				v1.ResourceMemory: 2,
+			},
+			expectedNodeInfoNames: []string{"node3", "node2", "node1"},
+		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name+" descending", func(t *testing.T) {
-			sortNodesByUsage(tc.nodeInfoList, false) // ascending=false, sort nodes in descending order
+			sortNodesByUsage(tc.nodeInfoList, false, tc.weights) // ascending=false, sort nodes in descending order
 
 			for i := 0; i < len(tc.nodeInfoList); i++ {
 				if tc.nodeInfoList[i].NodeUsage.node.Name != tc.expectedNodeInfoNames[i] {
@@ -127,7 +121,7 @@ func TestSortNodesByUsage(t *testing.T) {
 			}
 		})
 		t.Run(tc.name+" ascending", func(t *testing.T) {
-			sortNodesByUsage(tc.nodeInfoList, true) // ascending=true, sort nodes in ascending order
+			sortNodesByUsage(tc.nodeInfoList, true, tc.weights) // ascending=true, sort nodes in ascending order
 
 			size := len(tc.nodeInfoList)
 			for i := 0; i < size; i++ {
@@ -139,6 +133,149 @@ func TestSortNodesByUsage(t *testing.T) {
 	}
 }
 
+func TestSortNodesByUsageTies(t *testing.T) {
+	usage := withUsage(frameworktesting.BuildNodeUsage().WithCPU("1"))
+
+	for _, ascending := range []bool{true, false} {
+		nodeInfoList := []NodeInfo{
+			*BuildTestNodeInfo("node3", usage),
+			*BuildTestNodeInfo("node1", usage),
+			*BuildTestNodeInfo("node2", usage),
+		}
+		sortNodesByUsage(nodeInfoList, ascending, nil)
+
+		names := []string{}
+		for _, nodeInfo := range nodeInfoList {
+			names = append(names, nodeInfo.node.Name)
+		}
+		expected := []string{"node1", "node2", "node3"}
+		if !reflect.DeepEqual(names, expected) {
+			t.Errorf("ascending=%v: expected %v, got %v", ascending, expected, names)
+		}
+	}
+}
+
+func TestSortNodesByWeightedUsage(t *testing.T) {
+	// node1 uses more memory bytes than node2 uses cpu millis but its
+	// usage is lower once both are taken relative to the capacity.
+	nodeInfoList := []NodeInfo{
+		*BuildTestNodeInfo("node1", withUsage(frameworktesting.BuildNodeUsage().WithCPU("1").WithMemory("3000"))),
+		*BuildTestNodeInfo("node2", withUsage(frameworktesting.BuildNodeUsage().WithCPU("3").WithMemory("1000"))),
+	}
+	usage := map[string]api.ResourceThresholds{
+		"node1": {v1.ResourceCPU: 25, v1.ResourceMemory: 75},
+		"node2": {v1.ResourceCPU: 75, v1.ResourceMemory: 25},
+	}
+
+	for _, tc := range []struct {
+		name     string
+		weights  map[v1.ResourceName]float64
+		expected []string
+	}{
+		{
+			name:     "no weights",
+			expected: []string{"node1", "node2"},
+		},
+		{
+			name:     "cpu weighs more",
+			weights:  map[v1.ResourceName]float64{v1.ResourceCPU: 2},
+			expected: []string{"node2", "node1"},
+		},
+		{
+			name:     "memory weighs more",
+			weights:  map[v1.ResourceName]float64{v1.ResourceMemory: 2},
+			expected: []string{"node1", "node2"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			nodes := slices.Clone(nodeInfoList)
+			sortNodesByWeightedUsage(nodes, false, usage, tc.weights)
+
+			names := []string{}
+			for _, nodeInfo := range nodes {
+				names = append(names, nodeInfo.node.Name)
+			}
+			if !reflect.DeepEqual(names, tc.expected) {
+				t.Errorf("Expected %v, got %v", tc.expected, names)
+			}
+		})
+	}
+}
+
+func TestWeightedThresholds(t *testing.T) {
+	threshold := api.ResourceThresholds{v1.ResourceCPU: 50, v1.ResourceMemory: 50}
+	for _, tc := range []struct {
+		name    string
+		usage   api.ResourceThresholds
+		weights map[v1.ResourceName]float64
+		above   bool
+		below   bool
+	}{
+		{
+			name:  "any resource above without weights",
+			usage: api.ResourceThresholds{v1.ResourceCPU: 20, v1.ResourceMemory: 60},
+			above: true,
+		},
+		{
+			name:    "cpu pressure matters more",
+			usage:   api.ResourceThresholds{v1.ResourceCPU: 20, v1.ResourceMemory: 60},
+			weights: map[v1.ResourceName]float64{v1.ResourceCPU: 2, v1.ResourceMemory: 1},
+			below:   true,
+		},
+		{
+			name:    "cpu pressure above",
+			usage:   api.ResourceThresholds{v1.ResourceCPU: 60, v1.ResourceMemory: 40},
+			weights: map[v1.ResourceName]float64{v1.ResourceCPU: 2, v1.ResourceMemory: 1},
+			above:   true,
+		},
+		{
+			name:    "memory ignored",
+			usage:   api.ResourceThresholds{v1.ResourceCPU: 50, v1.ResourceMemory: 90},
+			weights: map[v1.ResourceName]float64{v1.ResourceMemory: 0},
+			below:   true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if above := isNodeAboveWeightedThreshold(tc.usage, threshold, tc.weights); above != tc.above {
+				t.Errorf("Expected above to be %v, got %v", tc.above, above)
+			}
+			if below := isNodeBelowWeightedThreshold(tc.usage, threshold, tc.weights); below != tc.below {
+				t.Errorf("Expected below to be %v, got %v", tc.below, below)
+			}
+		})
+	}
+}
+
+func TestPreferNodesMarkedForDeletion(t *testing.T) {
+	markForDeletion := func(nodeInfo *NodeInfo) {
+		nodeInfo.node.Spec.Taints = []v1.Taint{
+			{
+				Key:    nodeutil.DeletionCandidateOfClusterAutoscalerTaint,
+				Effect: v1.TaintEffectPreferNoSchedule,
+			},
+		}
+	}
+
+	keep := func(*NodeInfo) {}
+
+	nodeInfoList := []NodeInfo{
+		*BuildTestNodeInfo("node1", keep),
+		*BuildTestNodeInfo("node2", markForDeletion),
+		*BuildTestNodeInfo("node3", keep),
+		*BuildTestNodeInfo("node4", markForDeletion),
+	}
+	preferNodesMarkedForDeletion(nodeInfoList)
+
+	names := []string{}
+	for _, nodeInfo := range nodeInfoList {
+		names = append(names, nodeInfo.node.Name)
+	}
+	expected := []string{"node2", "node4", "node1", "node3"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected %v, got %v", expected, names)
+	}
+}
+
 func TestResourceUsageToResourceThreshold(t *testing.T) {
 	for _, tt := range []struct {
 		name     string
@@ -246,8 +383,8 @@ func TestClassificationUsingDeviationThresholds(t *testing.T) {
 	// mean that our low limit will be 5 pct points below the average and
 	// the high limit will be 5 pct points above the average.
 	userDefinedThresholds := map[string]api.ResourceThresholds{
-		"low":  {v1.ResourceCPU: 5, v1.ResourceMemory: 5},
-		"high": {v1.ResourceCPU: 5, v1.ResourceMemory: 5},
+		"low":  frameworktesting.BuildNodeThresholds().WithCPU(5).WithMemory(5).Build(),
+		"high": frameworktesting.BuildNodeThresholds().WithCPU(5).WithMemory(5).Build(),
 	}
 
 	// Create a fake total amount of resources for all nodes. We define
@@ -339,10 +476,147 @@ func TestClassificationUsingDeviationThresholds(t *testing.T) {
 // This is almost a copy of TestUsingDeviationThresholds but we are using
 // pointers here. This is for making sure our generic types are in check. To
 // understand this code better read comments on TestUsingDeviationThresholds.
+func TestAssessNodesUsagesAndStdDeviationThresholds(t *testing.T) {
+	usages := map[string]api.ReferencedResourceList{}
+	capacities := map[string]api.ReferencedResourceList{}
+	for name, cpu := range map[string]string{"node1": "200m", "node2": "400m", "node3": "600m", "node4": "800m"} {
+		usages[name] = frameworktesting.BuildNodeUsage().WithCPU(cpu).WithMemory("500").Build()
+		capacities[name] = frameworktesting.BuildNodeUsage().WithCPU("1").WithMemory("1000").Build()
+	}
+
+	// cpu usage averages 50% with a standard deviation of ~22.36%, memory
+	// usage is the same on all nodes.
+	usage, thresholds := assessNodesUsagesAndStdDeviationThresholds(
+		usages,
+		capacities,
+		api.ResourceThresholds{v1.ResourceCPU: 1, v1.ResourceMemory: 1},
+		api.ResourceThresholds{v1.ResourceCPU: 0.5, v1.ResourceMemory: 1},
+	)
+
+	if len(usage) != 4 || len(thresholds) != 4 {
+		t.Fatalf("Expected the usage and thresholds of 4 nodes, got %v and %v", usage, thresholds)
+	}
+	stdDeviation := math.Sqrt(500)
+	expected := []api.ResourceThresholds{
+		{v1.ResourceCPU: api.Percentage(50 - stdDeviation), v1.ResourceMemory: 50},
+		{v1.ResourceCPU: api.Percentage(50 + stdDeviation/2), v1.ResourceMemory: 50},
+	}
+	for node := range thresholds {
+		for i := range expected {
+			for name, value := range expected[i] {
+				if math.Abs(float64(thresholds[node][i][name]-value)) > 0.001 {
+					t.Errorf("Expected %v threshold %v of %v to be %v, got %v", name, i, node, value, thresholds[node][i][name])
+				}
+			}
+		}
+	}
+
+	for node, expected := range map[string]string{"node1": "underutilized", "node4": "overutilized"} {
+		below := isNodeBelowThreshold(usage[node], thresholds[node][0])
+		above := isNodeAboveThreshold(usage[node], thresholds[node][1])
+		if below != (expected == "underutilized") || above != (expected == "overutilized") {
+			t.Errorf("Expected %v to be %v, got below=%v above=%v", node, expected, below, above)
+		}
+	}
+	for _, node := range []string{"node2", "node3"} {
+		if isNodeBelowThreshold(usage[node], thresholds[node][0]) || isNodeAboveThreshold(usage[node], thresholds[node][1]) {
+			t.Errorf("Expected %v to be appropriately utilized", node)
+		}
+	}
+}
+
+func TestRelativeThresholdsAssessorCenters(t *testing.T) {
+	usages := map[string]api.ReferencedResourceList{}
+	capacities := map[string]api.ReferencedResourceList{}
+	// node5 is an outlier pulling the average cpu usage up to 38%.
+	for name, cpu := range map[string]string{
+		"node1": "200m", "node2": "250m", "node3": "300m", "node4": "150m", "node5": "1",
+	} {
+		usages[name] = frameworktesting.BuildNodeUsage().WithCPU(cpu).Build()
+		capacities[name] = frameworktesting.BuildNodeUsage().WithCPU("1").Build()
+	}
+
+	for _, tc := range []struct {
+		center   DeviationCenter
+		pct      api.Percentage
+		expected api.Percentage
+	}{
+		{center: "", expected: 38},
+		{center: DeviationCenterAverage, expected: 38},
+		{center: DeviationCenterMedian, expected: 25},
+		{center: DeviationCenterTrimmedMean, pct: 20, expected: 25},
+		// the default trims 10% of 5 nodes, i.e. none of them.
+		{center: DeviationCenterTrimmedMean, expected: 38},
+	} {
+		t.Run(string(tc.center), func(t *testing.T) {
+			_, thresholds := relativeThresholdsAssessor(tc.center, tc.pct)(
+				usages,
+				capacities,
+				api.ResourceThresholds{v1.ResourceCPU: 10},
+				api.ResourceThresholds{v1.ResourceCPU: 10},
+			)
+			for node := range thresholds {
+				low, high := thresholds[node][0][v1.ResourceCPU], thresholds[node][1][v1.ResourceCPU]
+				if math.Abs(float64(low-(tc.expected-10))) > 0.001 || math.Abs(float64(high-(tc.expected+10))) > 0.001 {
+					t.Errorf("Expected %v thresholds around %v, got %v and %v", node, tc.expected, low, high)
+				}
+			}
+		})
+	}
+}
+
+func TestAssessNodesUsagesAndPercentileThresholds(t *testing.T) {
+	usages := map[string]api.ReferencedResourceList{}
+	capacities := map[string]api.ReferencedResourceList{}
+	// nodes of different sizes, the usage of node5 is the highest in
+	// absolute terms but its share of the node is the lowest.
+	for name, cpu := range map[string][]string{
+		"node1": {"200m", "1"},
+		"node2": {"400m", "1"},
+		"node3": {"600m", "1"},
+		"node4": {"800m", "1"},
+		"node5": {"1", "10"},
+	} {
+		usages[name] = frameworktesting.BuildNodeUsage().WithCPU(cpu[0]).WithMemory("500").Build()
+		capacities[name] = frameworktesting.BuildNodeUsage().WithCPU(cpu[1]).WithMemory("1000").Build()
+	}
+
+	usage, thresholds := assessNodesUsagesAndPercentileThresholds(
+		usages,
+		capacities,
+		api.ResourceThresholds{v1.ResourceCPU: 25},
+		api.ResourceThresholds{v1.ResourceCPU: 75},
+	)
+
+	// cpu usages are 10%, 20%, 40%, 60% and 80%.
+	expected := []api.ResourceThresholds{{v1.ResourceCPU: 20}, {v1.ResourceCPU: 60}}
+	for node := range usage {
+		if !reflect.DeepEqual(thresholds[node], expected) {
+			t.Errorf("Expected thresholds %v for %v, got %v", expected, node, thresholds[node])
+		}
+	}
+
+	var below, above []string
+	for _, node := range sortedNodeNames(usage) {
+		if isNodeBelowThreshold(usage[node], thresholds[node][0]) {
+			below = append(below, node)
+		}
+		if isNodeAboveThreshold(usage[node], thresholds[node][1]) {
+			above = append(above, node)
+		}
+	}
+	if !reflect.DeepEqual(below, []string{"node1", "node5"}) {
+		t.Errorf("Expected node1 and node5 to be underutilized, got %v", below)
+	}
+	if !reflect.DeepEqual(above, []string{"node4"}) {
+		t.Errorf("Expected node4 to be overutilized, got %v", above)
+	}
+}
+
 func TestUsingDeviationThresholdsWithPointers(t *testing.T) {
 	userDefinedThresholds := map[string]api.ResourceThresholds{
-		"low":  {v1.ResourceCPU: 5, v1.ResourceMemory: 5},
-		"high": {v1.ResourceCPU: 5, v1.ResourceMemory: 5},
+		"low":  frameworktesting.BuildNodeThresholds().WithCPU(5).WithMemory(5).Build(),
+		"high": frameworktesting.BuildNodeThresholds().WithCPU(5).WithMemory(5).Build(),
 	}
 
 	nodesTotal := normalizer.Replicate(
@@ -577,3 +851,164 @@ func TestNormalizeAndClassify(t *testing.T) {
 		})
 	}
 }
+
+func TestBalanceSummaryKeysAndValues(t *testing.T) {
+	summary := newBalanceSummary(LowNodeUtilizationPluginName)
+	summary.underutilized = 2
+	summary.overutilized = 3
+	summary.evicted = 4
+	summary.skipped = 1
+	summary.blockedByBudget = 1
+	summary.resized = 2
+
+	keysAndValues := summary.keysAndValues()
+	if len(keysAndValues)%2 != 0 {
+		t.Fatalf("expected an even number of keys and values, got %d", len(keysAndValues))
+	}
+
+	// the last pair is the duration, it can't be compared.
+	expected := []any{
+		"plugin", LowNodeUtilizationPluginName,
+		"underutilizedNodes", 2,
+		"overutilizedNodes", 3,
+		"evictedPods", 4,
+		"skippedPods", 1,
+		"blockedByDisruptionBudget", 1,
+		"resizedPods", 2,
+	}
+	if !reflect.DeepEqual(keysAndValues[:len(expected)], expected) {
+		t.Errorf("expected %v, got %v", expected, keysAndValues[:len(expected)])
+	}
+	if keysAndValues[len(expected)] != "duration" {
+		t.Errorf("expected duration to be reported, got %v", keysAndValues[len(expected)])
+	}
+}
+
+func TestNoNodeAboveThresholds(t *testing.T) {
+	thresholds := map[string][]api.ResourceThresholds{
+		"node1": {{v1.ResourceCPU: 20}, {v1.ResourceCPU: 70, v1.ResourcePods: 50}},
+		"node2": {{v1.ResourceCPU: 20}, {v1.ResourceCPU: 60}},
+	}
+
+	for _, tc := range []struct {
+		name     string
+		usage    map[string]api.ResourceThresholds
+		expected bool
+	}{
+		{
+			name: "all nodes below the lowest threshold",
+			usage: map[string]api.ResourceThresholds{
+				"node1": {v1.ResourceCPU: 60, v1.ResourcePods: 50},
+				"node2": {v1.ResourceCPU: 10, v1.ResourcePods: 10},
+			},
+			expected: true,
+		},
+		{
+			name: "one node above the lowest threshold",
+			usage: map[string]api.ResourceThresholds{
+				"node1": {v1.ResourceCPU: 65, v1.ResourcePods: 10},
+				"node2": {v1.ResourceCPU: 10, v1.ResourcePods: 10},
+			},
+			expected: false,
+		},
+		{
+			name: "resource only thresholded on one node",
+			usage: map[string]api.ResourceThresholds{
+				"node1": {v1.ResourceCPU: 10, v1.ResourcePods: 10},
+				"node2": {v1.ResourceCPU: 10, v1.ResourcePods: 80},
+			},
+			expected: false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if result := noNodeAboveThresholds(tc.usage, thresholds, 1); result != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestCapNodeCapacitiesToThreshold(t *testing.T) {
+	resourceNames := []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory, v1.ResourcePods}
+	thresholds := api.ResourceThresholds{v1.ResourceCPU: 50, v1.ResourcePods: 25}
+
+	n1 := referencedResourceListForNodeCapacity(BuildTestNodeInfo("n1", func(*NodeInfo) {}).node)
+	n2 := referencedResourceListForNodeCapacity(BuildTestNodeInfo("n2", func(nodeInfo *NodeInfo) {
+		nodeInfo.node.Status.Allocatable[v1.ResourceCPU] = *resource.NewMilliQuantity(4000, resource.DecimalSI)
+	}).node)
+
+	// the returned list is modified during evictions, this must not
+	// affect the node capacities.
+	first := capNodeCapacitiesToThreshold(n1, thresholds, resourceNames)
+	first[v1.ResourceCPU].Sub(resource.MustParse("500m"))
+
+	second := capNodeCapacitiesToThreshold(n1, thresholds, resourceNames)
+	if cpu := second[v1.ResourceCPU].MilliValue(); cpu != 965 {
+		t.Errorf("expected capped cpu to be 965m, got %vm", cpu)
+	}
+	if pods := second[v1.ResourcePods].Value(); pods != 7 {
+		t.Errorf("expected capped pods to be 7, got %v", pods)
+	}
+	if memory := second[v1.ResourceMemory].Value(); memory != 3287692*1024 {
+		t.Errorf("expected memory not to be capped, got %v", memory)
+	}
+
+	third := capNodeCapacitiesToThreshold(n2, thresholds, resourceNames)
+	if cpu := third[v1.ResourceCPU].MilliValue(); cpu != 2000 {
+		t.Errorf("expected capped cpu to be 2000m, got %vm", cpu)
+	}
+}
+
+func TestKeysAndValuesUseStringKeys(t *testing.T) {
+	hugepages := v1.ResourceName(v1.ResourceHugePagesPrefix + "2Mi")
+
+	thresholds := thresholdsToKeysAndValues(api.ResourceThresholds{
+		v1.ResourceCPU:              20,
+		v1.ResourceEphemeralStorage: 30,
+		hugepages:                   40,
+	})
+	expected := []any{
+		"cpu", "20.00%",
+		"ephemeral-storage", "30.00%",
+		"hugepages-2Mi", "40.00%",
+	}
+	if !reflect.DeepEqual(thresholds, expected) {
+		t.Errorf("expected %v, got %v", expected, thresholds)
+	}
+
+	usage := usageToKeysAndValues(api.ReferencedResourceList{
+		v1.ResourceCPU:              resource.NewMilliQuantity(1500, resource.DecimalSI),
+		v1.ResourceEphemeralStorage: resource.NewQuantity(2048, resource.BinarySI),
+		hugepages:                   resource.NewQuantity(4096, resource.BinarySI),
+	})
+	expected = []any{
+		"CPU", int64(1500),
+		"ephemeral-storage", int64(2048),
+		"hugepages-2Mi", int64(4096),
+	}
+	if !reflect.DeepEqual(usage, expected) {
+		t.Errorf("expected %v, got %v", expected, usage)
+	}
+}
+
+func TestCapNodeCapacityToThresholdFormat(t *testing.T) {
+	hugepages := v1.ResourceName(v1.ResourceHugePagesPrefix + "2Mi")
+	capacities := api.ReferencedResourceList{
+		v1.ResourceEphemeralStorage: resource.NewQuantity(8*1024*1024*1024, resource.BinarySI),
+		hugepages:                   resource.NewQuantity(16*1024*1024, resource.BinarySI),
+	}
+	thresholds := api.ResourceThresholds{
+		v1.ResourceEphemeralStorage: 50,
+		hugepages:                   25,
+	}
+
+	for name, expected := range map[v1.ResourceName]string{
+		v1.ResourceEphemeralStorage: "4Gi",
+		hugepages:                   "4Mi",
+	} {
+		capped := capNodeCapacityToThreshold(capacities, thresholds, name)
+		if capped.Format != resource.BinarySI || capped.String() != expected {
+			t.Errorf("expected %v to be capped to %v, got %v (%v)", name, expected, capped.String(), capped.Format)
+		}
+	}
+}
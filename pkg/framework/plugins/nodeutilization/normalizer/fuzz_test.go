@@ -0,0 +1,147 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package normalizer
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/descheduler/pkg/api"
+)
+
+var (
+	fuzzNodes     = []string{"node1", "node2", "node3", "node4"}
+	fuzzResources = []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory, v1.ResourcePods}
+)
+
+// fuzzThresholds decodes the fuzzer input into percentages indexed by node
+// and resource. every 10 bytes pick a node, a resource and the bits of the
+// percentage, so NaN, infinities and mismatched resource sets are reached.
+func fuzzThresholds(data []byte) map[string]api.ResourceThresholds {
+	result := map[string]api.ResourceThresholds{}
+	for ; len(data) >= 10; data = data[10:] {
+		node := fuzzNodes[int(data[0])%len(fuzzNodes)]
+		resource := fuzzResources[int(data[1])%len(fuzzResources)]
+		if result[node] == nil {
+			result[node] = api.ResourceThresholds{}
+		}
+		result[node][resource] = api.Percentage(math.Float64frombits(binary.LittleEndian.Uint64(data[2:10])))
+	}
+	return result
+}
+
+// fuzzSeed encodes a percentage for a node and resource as fuzzThresholds
+// expects it.
+func fuzzSeed(node, resource byte, value float64) []byte {
+	seed := []byte{node, resource, 0, 0, 0, 0, 0, 0, 0, 0}
+	binary.LittleEndian.PutUint64(seed[2:], math.Float64bits(value))
+	return seed
+}
+
+func addFuzzSeeds(f *testing.F) {
+	f.Add([]byte{})
+	f.Add(append(fuzzSeed(0, 0, 50), fuzzSeed(1, 0, 20)...))
+	f.Add(append(fuzzSeed(0, 0, math.NaN()), fuzzSeed(1, 1, 20)...))
+	f.Add(append(fuzzSeed(0, 0, math.Inf(1)), fuzzSeed(1, 0, math.Inf(-1))...))
+	f.Add(append(fuzzSeed(0, 2, -10), fuzzSeed(0, 1, 250)...))
+}
+
+func FuzzNormalize(f *testing.F) {
+	addFuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		half := len(data) / 2
+		usages, totals := fuzzThresholds(data[:half]), fuzzThresholds(data[half:])
+
+		result := Normalize(usages, totals, func(usage, total api.ResourceThresholds) api.ResourceThresholds {
+			normalized := api.ResourceThresholds{}
+			for name, value := range usage {
+				normalized[name] = value / total[name] * 100
+			}
+			return normalized
+		})
+
+		for node := range result {
+			if _, ok := usages[node]; !ok {
+				t.Errorf("node %v normalized without usage", node)
+			}
+			if _, ok := totals[node]; !ok {
+				t.Errorf("node %v normalized without totals", node)
+			}
+		}
+		for node := range usages {
+			if _, ok := totals[node]; ok {
+				if _, ok := result[node]; !ok {
+					t.Errorf("node %v with usage and totals was not normalized", node)
+				}
+			}
+		}
+	})
+}
+
+func FuzzClamp(f *testing.F) {
+	addFuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for node, values := range fuzzThresholds(data) {
+			result := Clamp(values, 0, 100)
+			if len(result) != len(values) {
+				t.Fatalf("node %v: expected %v clamped values, got %v", node, len(values), len(result))
+			}
+			for name, value := range result {
+				if !(value >= 0 && value <= 100) {
+					t.Errorf("node %v: %v clamped to %v, outside of [0, 100]", node, values[name], value)
+				}
+			}
+		}
+	})
+}
+
+func FuzzAverage(f *testing.F) {
+	addFuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		values := fuzzThresholds(data)
+		result := Average(values)
+
+		bounded := map[v1.ResourceName]bool{}
+		for _, thresholds := range values {
+			for name, value := range thresholds {
+				if _, ok := bounded[name]; !ok {
+					bounded[name] = true
+				}
+				if math.IsNaN(float64(value)) || math.IsInf(float64(value), 0) {
+					continue
+				}
+				if value < 0 || value > 100 {
+					bounded[name] = false
+				}
+				if _, ok := result[name]; !ok {
+					t.Errorf("resource %v missing from the average", name)
+				}
+			}
+		}
+
+		for name, value := range result {
+			if math.IsNaN(float64(value)) {
+				t.Errorf("average of %v is NaN", name)
+			}
+			// the average of percentages is a percentage.
+			if bounded[name] && (value < 0 || value > 100) {
+				t.Errorf("average of %v is %v, outside of [0, 100]", name, value)
+			}
+		}
+	})
+}
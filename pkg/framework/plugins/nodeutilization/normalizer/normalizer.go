@@ -16,7 +16,10 @@ limitations under the License.
 package normalizer
 
 import (
+	"fmt"
 	"math"
+	"slices"
+	"strings"
 
 	"golang.org/x/exp/constraints"
 )
@@ -54,6 +57,48 @@ func Normalize[K comparable, V, N any](usages, totals Values[K, V], fn Normalize
 	return result
 }
 
+// MissingTotalsError is returned by NormalizeStrict when some of the usages
+// have no total to be normalized against.
+type MissingTotalsError[K comparable] struct {
+	// Keys of the usages without a total, sorted by their string
+	// representation.
+	Keys []K
+}
+
+func (e *MissingTotalsError[K]) Error() string {
+	keys := make([]string, 0, len(e.Keys))
+	for _, key := range e.Keys {
+		keys = append(keys, fmt.Sprint(key))
+	}
+	return fmt.Sprintf("missing totals for %d key(s): %s", len(keys), strings.Join(keys, ", "))
+}
+
+// NormalizeStrict works as Normalize but, rather than silently skipping
+// them, reports the usages whose key is absent in the totals through a
+// MissingTotalsError. The values that could be normalized are returned
+// regardless so callers can decide whether to carry on without the
+// missing ones.
+func NormalizeStrict[K comparable, V, N any](usages, totals Values[K, V], fn Normalizer[V, N]) (map[K]N, error) {
+	result := Values[K, N]{}
+	var missing []K
+	for key, value := range usages {
+		total, ok := totals[key]
+		if !ok {
+			missing = append(missing, key)
+			continue
+		}
+		result[key] = fn(value, total)
+	}
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	slices.SortFunc(missing, func(a, b K) int {
+		return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+	})
+	return result, &MissingTotalsError[K]{Keys: missing}
+}
+
 // Replicate replicates the provide value for each key in the provided slice.
 // Returns a map with the keys and the provided value.
 func Replicate[K comparable, V any](keys []K, value V) map[K]V {
@@ -68,11 +113,16 @@ func Replicate[K comparable, V any](keys []K, value V) map[K]V {
 // will return a set of values where each value is between the minimum and
 // maximum values (included). Values below minimum are rounded up to the
 // minimum value, and values above maximum are rounded down to the maximum
-// value.
+// value. NaN values, usually coming from malformed usage data, are set to
+// the minimum value.
 func Clamp[K comparable, N Number, V ~map[K]N](values V, minimum, maximum N) V {
 	result := V{}
 	for key := range values {
 		value := values[key]
+		if math.IsNaN(float64(value)) {
+			result[key] = minimum
+			continue
+		}
 		value = N(math.Max(float64(value), float64(minimum)))
 		value = N(math.Min(float64(value), float64(maximum)))
 		result[key] = value
@@ -123,12 +173,16 @@ func Sum[K comparable, N Number, V ~map[K]N](mapA, mapB V) V {
 // Average calculates the average of a set of values. This function receives
 // a map of values and returns the average of all the values. Average expects
 // the values to represent the same unit of measure. You can use this function
-// after Normalizing the values.
+// after Normalizing the values. NaN and infinite values are ignored so a
+// single malformed value does not spoil the average.
 func Average[J, K comparable, N Number, V ~map[J]N](values map[K]V) V {
 	counter := map[J]int{}
 	result := V{}
 	for _, imap := range values {
 		for name, value := range imap {
+			if math.IsNaN(float64(value)) || math.IsInf(float64(value), 0) {
+				continue
+			}
 			result[name] += value
 			counter[name]++
 		}
@@ -140,3 +194,125 @@ func Average[J, K comparable, N Number, V ~map[J]N](values map[K]V) V {
 
 	return result
 }
+
+// Median calculates, for each key, the median of a set of values. Unlike
+// Average, a few values far from the others do not move the median. As for
+// Average, NaN and infinite values are ignored.
+func Median[J, K comparable, N Number, V ~map[J]N](values map[K]V) V {
+	percentiles := V{}
+	for _, imap := range values {
+		for name := range imap {
+			percentiles[name] = 50
+		}
+	}
+	return Percentile(values, percentiles)
+}
+
+// TrimmedMean calculates, for each key, the average of a set of values once
+// the pct percent lowest and the pct percent highest values, pct being in
+// the [0; 50] interval, are left out. A few values far from the others are
+// then not taken into account. As for Average, NaN and infinite values are
+// ignored.
+func TrimmedMean[J, K comparable, N Number, V ~map[J]N](values map[K]V, pct float64) V {
+	samples := map[J][]float64{}
+	for _, imap := range values {
+		for name, value := range imap {
+			if math.IsNaN(float64(value)) || math.IsInf(float64(value), 0) {
+				continue
+			}
+			samples[name] = append(samples[name], float64(value))
+		}
+	}
+
+	pct = math.Max(0, math.Min(pct, 50))
+	result := V{}
+	for name, sorted := range samples {
+		slices.Sort(sorted)
+		trim := int(math.Floor(float64(len(sorted)) * pct / 100))
+		if 2*trim >= len(sorted) {
+			// nothing would be left, keep the middle values.
+			trim = (len(sorted) - 1) / 2
+		}
+		sorted = sorted[trim : len(sorted)-trim]
+
+		var sum float64
+		for _, value := range sorted {
+			sum += value
+		}
+		result[name] = N(sum / float64(len(sorted)))
+	}
+
+	return result
+}
+
+// Multiply multiplies the values of two maps. Values are expected to be of
+// Number type. Original values are preserved. If a key is present in one map
+// but not in the other, the key is ignored.
+func Multiply[K comparable, N Number, V ~map[K]N](mapA, mapB V) V {
+	result := V{}
+	for name, value := range mapA {
+		result[name] = value * mapB[name]
+	}
+	return result
+}
+
+// StdDeviation calculates the population standard deviation of a set of
+// values around their average, as returned by Average. As for Average, NaN
+// and infinite values are ignored.
+func StdDeviation[J, K comparable, N Number, V ~map[J]N](values map[K]V, average V) V {
+	counter := map[J]int{}
+	variance := map[J]float64{}
+	for _, imap := range values {
+		for name, value := range imap {
+			if math.IsNaN(float64(value)) || math.IsInf(float64(value), 0) {
+				continue
+			}
+			delta := float64(value) - float64(average[name])
+			variance[name] += delta * delta
+			counter[name]++
+		}
+	}
+
+	result := V{}
+	for name := range variance {
+		result[name] = N(math.Sqrt(variance[name] / float64(counter[name])))
+	}
+
+	return result
+}
+
+// Percentile calculates, for each key of the percentiles map, the value at
+// the given percentile, in the [0; 100] interval, of the values of the key.
+// Values between the closest ranks are linearly interpolated. As for
+// Average, NaN and infinite values are ignored.
+func Percentile[J, K comparable, N Number, V ~map[J]N](values map[K]V, percentiles V) V {
+	samples := map[J][]float64{}
+	for _, imap := range values {
+		for name, value := range imap {
+			if _, ok := percentiles[name]; !ok {
+				continue
+			}
+			if math.IsNaN(float64(value)) || math.IsInf(float64(value), 0) {
+				continue
+			}
+			samples[name] = append(samples[name], float64(value))
+		}
+	}
+
+	result := V{}
+	for name, percentile := range percentiles {
+		sorted := samples[name]
+		if len(sorted) == 0 {
+			continue
+		}
+		slices.Sort(sorted)
+
+		rank := float64(percentile) / 100 * float64(len(sorted)-1)
+		rank = math.Max(0, math.Min(rank, float64(len(sorted)-1)))
+		lower, upper := int(math.Floor(rank)), int(math.Ceil(rank))
+		value := sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+		result[name] = N(value)
+	}
+
+	return result
+}
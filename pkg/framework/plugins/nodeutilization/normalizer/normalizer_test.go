@@ -106,6 +106,43 @@ func TestNormalizeSimple(t *testing.T) {
 	}
 }
 
+func TestNormalizeStrict(t *testing.T) {
+	normalizer := func(usage, total float64) float64 {
+		return usage / total
+	}
+
+	result, err := NormalizeStrict(
+		map[string]float64{"cpu": 1, "mem": 6},
+		map[string]float64{"cpu": 2, "mem": 10},
+		normalizer,
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := map[string]float64{"cpu": 0.5, "mem": 0.6}; !reflect.DeepEqual(result, expected) {
+		t.Fatalf("unexpected result: %v", result)
+	}
+
+	result, err = NormalizeStrict(
+		map[string]float64{"cpu": 1, "mem": 6, "gpu": 1},
+		map[string]float64{"cpu": 2},
+		normalizer,
+	)
+	if expected := map[string]float64{"cpu": 0.5}; !reflect.DeepEqual(result, expected) {
+		t.Fatalf("unexpected result: %v", result)
+	}
+	missing, ok := err.(*MissingTotalsError[string])
+	if !ok {
+		t.Fatalf("expected a missing totals error, got %v", err)
+	}
+	if expected := []string{"gpu", "mem"}; !reflect.DeepEqual(missing.Keys, expected) {
+		t.Fatalf("expected missing keys %v, got %v", expected, missing.Keys)
+	}
+	if expected := "missing totals for 2 key(s): gpu, mem"; err.Error() != expected {
+		t.Fatalf("expected error %q, got %q", expected, err.Error())
+	}
+}
+
 func TestNormalize(t *testing.T) {
 	for _, tt := range []struct {
 		name       string
@@ -647,3 +684,158 @@ func TestClamp(t *testing.T) {
 		})
 	}
 }
+
+func TestMultiply(t *testing.T) {
+	result := Multiply(
+		api.ResourceThresholds{v1.ResourceCPU: 10, v1.ResourceMemory: 20},
+		api.ResourceThresholds{v1.ResourceCPU: 1.5, v1.ResourcePods: 2},
+	)
+	expected := api.ResourceThresholds{v1.ResourceCPU: 15, v1.ResourceMemory: 0}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("unexpected result: %v", result)
+	}
+}
+
+func TestStdDeviation(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		values   map[string]api.ResourceThresholds
+		expected api.ResourceThresholds
+	}{
+		{
+			name:     "no values",
+			values:   map[string]api.ResourceThresholds{},
+			expected: api.ResourceThresholds{},
+		},
+		{
+			name: "same values",
+			values: map[string]api.ResourceThresholds{
+				"node1": {v1.ResourceCPU: 50},
+				"node2": {v1.ResourceCPU: 50},
+			},
+			expected: api.ResourceThresholds{v1.ResourceCPU: 0},
+		},
+		{
+			name: "spread values",
+			values: map[string]api.ResourceThresholds{
+				"node1": {v1.ResourceCPU: 20, v1.ResourceMemory: 40},
+				"node2": {v1.ResourceCPU: 40, v1.ResourceMemory: 40},
+				"node3": {v1.ResourceCPU: 60, v1.ResourceMemory: 40},
+				"node4": {v1.ResourceCPU: 80, v1.ResourceMemory: 40},
+			},
+			expected: api.ResourceThresholds{v1.ResourceCPU: api.Percentage(math.Sqrt(500)), v1.ResourceMemory: 0},
+		},
+		{
+			name: "malformed values",
+			values: map[string]api.ResourceThresholds{
+				"node1": {v1.ResourceCPU: 40},
+				"node2": {v1.ResourceCPU: 60},
+				"node3": {v1.ResourceCPU: api.Percentage(math.NaN())},
+			},
+			expected: api.ResourceThresholds{v1.ResourceCPU: 10},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			result := StdDeviation(tt.values, Average(tt.values))
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Fatalf("unexpected result: %v", result)
+			}
+		})
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	values := map[string]api.ResourceThresholds{
+		"node1": {v1.ResourceCPU: 10, v1.ResourceMemory: 40},
+		"node2": {v1.ResourceCPU: 40, v1.ResourceMemory: 40},
+		"node3": {v1.ResourceCPU: 20, v1.ResourceMemory: api.Percentage(math.NaN())},
+		"node4": {v1.ResourceCPU: 30},
+		"node5": {v1.ResourceCPU: 50},
+	}
+	for _, tt := range []struct {
+		name        string
+		percentiles api.ResourceThresholds
+		expected    api.ResourceThresholds
+	}{
+		{
+			name:        "exact ranks",
+			percentiles: api.ResourceThresholds{v1.ResourceCPU: 25, v1.ResourceMemory: 100},
+			expected:    api.ResourceThresholds{v1.ResourceCPU: 20, v1.ResourceMemory: 40},
+		},
+		{
+			name:        "interpolated ranks",
+			percentiles: api.ResourceThresholds{v1.ResourceCPU: 90},
+			expected:    api.ResourceThresholds{v1.ResourceCPU: 46},
+		},
+		{
+			name:        "bounds",
+			percentiles: api.ResourceThresholds{v1.ResourceCPU: 0, v1.ResourceMemory: 0},
+			expected:    api.ResourceThresholds{v1.ResourceCPU: 10, v1.ResourceMemory: 40},
+		},
+		{
+			name:        "no values",
+			percentiles: api.ResourceThresholds{v1.ResourcePods: 50},
+			expected:    api.ResourceThresholds{},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Percentile(values, tt.percentiles)
+			if len(result) != len(tt.expected) {
+				t.Fatalf("unexpected result: %v", result)
+			}
+			for name, value := range tt.expected {
+				if math.Abs(float64(result[name]-value)) > 1e-9 {
+					t.Fatalf("unexpected result: %v", result)
+				}
+			}
+		})
+	}
+}
+
+func TestMedianAndTrimmedMean(t *testing.T) {
+	values := map[string]api.ResourceThresholds{
+		"node1": {v1.ResourceCPU: 10, v1.ResourceMemory: 40},
+		"node2": {v1.ResourceCPU: 20, v1.ResourceMemory: 50},
+		"node3": {v1.ResourceCPU: 30, v1.ResourceMemory: api.Percentage(math.NaN())},
+		"node4": {v1.ResourceCPU: 40},
+		"node5": {v1.ResourceCPU: 100},
+	}
+	for _, tt := range []struct {
+		name     string
+		fn       func() api.ResourceThresholds
+		expected api.ResourceThresholds
+	}{
+		{
+			name:     "median",
+			fn:       func() api.ResourceThresholds { return Median(values) },
+			expected: api.ResourceThresholds{v1.ResourceCPU: 30, v1.ResourceMemory: 45},
+		},
+		{
+			name:     "trimmed mean without trimming",
+			fn:       func() api.ResourceThresholds { return TrimmedMean(values, 0) },
+			expected: api.ResourceThresholds{v1.ResourceCPU: 40, v1.ResourceMemory: 45},
+		},
+		{
+			name:     "trimmed mean",
+			fn:       func() api.ResourceThresholds { return TrimmedMean(values, 20) },
+			expected: api.ResourceThresholds{v1.ResourceCPU: 30, v1.ResourceMemory: 45},
+		},
+		{
+			name:     "trimmed mean trimming everything",
+			fn:       func() api.ResourceThresholds { return TrimmedMean(values, 50) },
+			expected: api.ResourceThresholds{v1.ResourceCPU: 30, v1.ResourceMemory: 45},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.fn()
+			if len(result) != len(tt.expected) {
+				t.Fatalf("unexpected result: %v", result)
+			}
+			for name, value := range tt.expected {
+				if math.Abs(float64(result[name]-value)) > 1e-9 {
+					t.Fatalf("unexpected result: %v", result)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/descheduler/pkg/api"
+)
+
+// overcommitRule is an OvercommitRule with its node selector parsed.
+type overcommitRule struct {
+	selector labels.Selector
+	factors  map[v1.ResourceName]float64
+}
+
+// parseOvercommitRules parses the node selectors of the overcommit rules.
+func parseOvercommitRules(rules []OvercommitRule) ([]overcommitRule, error) {
+	parsed := make([]overcommitRule, 0, len(rules))
+	for _, rule := range rules {
+		selector, err := metav1.LabelSelectorAsSelector(rule.NodeSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid overcommit node selector: %v", err)
+		}
+		parsed = append(parsed, overcommitRule{selector: selector, factors: rule.Factors})
+	}
+	return parsed, nil
+}
+
+// overcommitCapacities multiplies the capacities of the nodes matching an
+// overcommit rule by the factors of the first rule they match. capacities
+// are modified in place.
+func overcommitCapacities(
+	capacities map[string]api.ReferencedResourceList,
+	nodes []*v1.Node,
+	rules []overcommitRule,
+) {
+	if len(rules) == 0 {
+		return
+	}
+	for _, node := range nodes {
+		for _, rule := range rules {
+			if !rule.selector.Matches(labels.Set(node.Labels)) {
+				continue
+			}
+			capacity := capacities[node.Name]
+			for name, factor := range rule.factors {
+				if quantity, ok := capacity[name]; ok && quantity != nil {
+					capacity[name] = overcommitQuantity(name, quantity, factor)
+				}
+			}
+			keysAndValues := []any{"node", klog.KObj(node)}
+			keysAndValues = append(keysAndValues, usageToKeysAndValues(capacity)...)
+			klog.V(3).InfoS("Node capacity overcommitted", keysAndValues...)
+			break
+		}
+	}
+}
+
+// overcommitQuantity returns the quantity multiplied by the factor. cpu is
+// handled in milli units so fractions of a core are not lost.
+func overcommitQuantity(name v1.ResourceName, quantity *resource.Quantity, factor float64) *resource.Quantity {
+	if name == v1.ResourceCPU {
+		return resource.NewMilliQuantity(
+			int64(float64(quantity.MilliValue())*factor), quantity.Format,
+		)
+	}
+	return resource.NewQuantity(
+		int64(float64(quantity.Value())*factor), quantity.Format,
+	)
+}
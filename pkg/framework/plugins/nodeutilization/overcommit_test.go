@@ -0,0 +1,84 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/descheduler/test"
+)
+
+func TestOvercommitCapacities(t *testing.T) {
+	withPool := func(pool string) func(*v1.Node) {
+		return func(node *v1.Node) {
+			node.Labels = map[string]string{"pool": pool}
+		}
+	}
+	nodes := []*v1.Node{
+		test.BuildTestNode("batch", 4000, 2*1024*1024*1024, 10, withPool("batch")),
+		test.BuildTestNode("memory", 4000, 2*1024*1024*1024, 10, withPool("memory")),
+		test.BuildTestNode("general", 4000, 2*1024*1024*1024, 10, withPool("general")),
+	}
+
+	rules, err := parseOvercommitRules([]OvercommitRule{
+		{
+			NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "batch"}},
+			Factors:      map[v1.ResourceName]float64{v1.ResourceCPU: 1.5},
+		},
+		{
+			NodeSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "pool", Operator: metav1.LabelSelectorOpIn, Values: []string{"batch", "memory"}},
+				},
+			},
+			Factors: map[v1.ResourceName]float64{v1.ResourceMemory: 2, v1.ResourceCPU: 3},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unable to parse overcommit rules: %v", err)
+	}
+
+	capacities := referencedResourceListForNodesCapacity(nodes)
+	overcommitCapacities(capacities, nodes, rules)
+
+	for _, tc := range []struct {
+		node   string
+		cpu    int64
+		memory int64
+	}{
+		// the first matching rule wins.
+		{node: "batch", cpu: 6000, memory: 2 * 1024 * 1024 * 1024},
+		{node: "memory", cpu: 12000, memory: 4 * 1024 * 1024 * 1024},
+		{node: "general", cpu: 4000, memory: 2 * 1024 * 1024 * 1024},
+	} {
+		t.Run(tc.node, func(t *testing.T) {
+			capacity := capacities[tc.node]
+			if cpu := capacity[v1.ResourceCPU].MilliValue(); cpu != tc.cpu {
+				t.Errorf("expected cpu capacity %vm, got %vm", tc.cpu, cpu)
+			}
+			if memory := capacity[v1.ResourceMemory].Value(); memory != tc.memory {
+				t.Errorf("expected memory capacity %v, got %v", tc.memory, memory)
+			}
+			if pods := capacity[v1.ResourcePods].Value(); pods != 10 {
+				t.Errorf("expected pods capacity not to be overcommitted, got %v", pods)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/descheduler/pkg/api"
+)
+
+// PodUsageFunc returns the usage of a pod, as reported by the usage client
+// of the plugin.
+type PodUsageFunc func(pod *v1.Pod) (api.ReferencedResourceList, error)
+
+// PodSorter sorts the eviction candidates of a source node, the pods first
+// in the list are evicted first. the candidates are provided sorted by
+// priority and QoS class, sorters are expected to sort them in a stable way
+// so the priority and the QoS class break the ties. nodeUsage is the usage
+// of the node prior to any eviction.
+type PodSorter interface {
+	Sort(pods []*v1.Pod, nodeUsage api.ReferencedResourceList, podUsage PodUsageFunc)
+}
+
+var (
+	podSortersLock sync.RWMutex
+	// podSorters are the sorters of the known pod eviction orders, the
+	// default priority order has none.
+	podSorters = map[PodEvictionOrder]PodSorter{
+		PodEvictionOrderLargestConsumerFirst:  consumerPodSorter{largestFirst: true},
+		PodEvictionOrderSmallestConsumerFirst: consumerPodSorter{},
+		PodEvictionOrderOldestFirst:           agePodSorter{oldestFirst: true},
+		PodEvictionOrderNewestFirst:           agePodSorter{},
+	}
+)
+
+// RegisterPodSorter makes the sorter available as the provided pod eviction
+// order, replacing the sorter previously registered under it, if any.
+func RegisterPodSorter(order PodEvictionOrder, sorter PodSorter) {
+	podSortersLock.Lock()
+	defer podSortersLock.Unlock()
+	podSorters[order] = sorter
+}
+
+// podSorterFor returns the sorter of the pod eviction order. nil is returned
+// for the default order, the candidates are already sorted by priority.
+func podSorterFor(order PodEvictionOrder) (PodSorter, error) {
+	if order == "" || order == PodEvictionOrderPriority {
+		return nil, nil
+	}
+	podSortersLock.RLock()
+	defer podSortersLock.RUnlock()
+	sorter, ok := podSorters[order]
+	if !ok {
+		return nil, fmt.Errorf("unknown pod eviction order %q", order)
+	}
+	return sorter, nil
+}
+
+// consumerPodSorter sorts the pods by their share of the node usage. the
+// share of a pod is the sum, across the resources, of the pod usage
+// relative to the node usage. pods whose usage is unknown have no share.
+type consumerPodSorter struct {
+	largestFirst bool
+}
+
+func (s consumerPodSorter) Sort(pods []*v1.Pod, nodeUsage api.ReferencedResourceList, podUsage PodUsageFunc) {
+	shares := make(map[*v1.Pod]float64, len(pods))
+	for _, pod := range pods {
+		usage, err := podUsage(pod)
+		if err != nil {
+			klog.V(4).InfoS("Unable to get the pod usage, pod is considered as not consuming anything", "pod", klog.KObj(pod), "err", err)
+			continue
+		}
+		shares[pod] = podUsageShare(usage, nodeUsage)
+	}
+	sort.SliceStable(pods, func(i, j int) bool {
+		if s.largestFirst {
+			return shares[pods[i]] > shares[pods[j]]
+		}
+		return shares[pods[i]] < shares[pods[j]]
+	})
+}
+
+// podUsageShare sums up the usage of the pod relative to the usage of its
+// node across all the resources but the number of pods, which is the same
+// for all the pods.
+func podUsageShare(podUsage, nodeUsage api.ReferencedResourceList) float64 {
+	var share float64
+	for name, quantity := range podUsage {
+		if name == v1.ResourcePods || quantity == nil {
+			continue
+		}
+		total, ok := nodeUsage[name]
+		if !ok || total == nil || total.Sign() <= 0 {
+			continue
+		}
+		share += quantity.AsApproximateFloat64() / total.AsApproximateFloat64()
+	}
+	return share
+}
+
+// agePodSorter sorts the pods by their creation time.
+type agePodSorter struct {
+	oldestFirst bool
+}
+
+func (s agePodSorter) Sort(pods []*v1.Pod, _ api.ReferencedResourceList, _ PodUsageFunc) {
+	sort.SliceStable(pods, func(i, j int) bool {
+		ti, tj := pods[i].CreationTimestamp, pods[j].CreationTimestamp
+		if s.oldestFirst {
+			return ti.Before(&tj)
+		}
+		return tj.Before(&ti)
+	})
+}
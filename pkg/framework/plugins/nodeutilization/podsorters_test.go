@@ -0,0 +1,172 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/descheduler/pkg/api"
+	podutil "sigs.k8s.io/descheduler/pkg/descheduler/pod"
+	"sigs.k8s.io/descheduler/pkg/framework/plugins/defaultevictor"
+	frameworktesting "sigs.k8s.io/descheduler/pkg/framework/testing"
+	frameworktypes "sigs.k8s.io/descheduler/pkg/framework/types"
+	"sigs.k8s.io/descheduler/test"
+)
+
+func TestPodSorters(t *testing.T) {
+	now := time.Now()
+	buildPod := func(name string, age time.Duration, priority int32) *v1.Pod {
+		return test.BuildTestPod(name, 100, 100, "n1", func(pod *v1.Pod) {
+			pod.CreationTimestamp = metav1.NewTime(now.Add(-age))
+			pod.Spec.Priority = &priority
+		})
+	}
+
+	pods := []*v1.Pod{
+		buildPod("p1", time.Hour, 0),
+		buildPod("p2", 3*time.Hour, 0),
+		buildPod("p3", 2*time.Hour, 10),
+		buildPod("p4", 4*time.Hour, 0),
+	}
+	// p1 and p3 consume the same share of the node, the usage of p4 is
+	// unknown.
+	usages := map[string]api.ReferencedResourceList{
+		"p1": frameworktesting.BuildPodUsage().WithCPU("400m").WithMemory("200").Build(),
+		"p2": frameworktesting.BuildPodUsage().WithCPU("1600m").WithMemory("100").Build(),
+		"p3": frameworktesting.BuildPodUsage().WithCPU("800m").Build(),
+	}
+	nodeUsage := frameworktesting.BuildNodeUsage().WithCPU("4").WithMemory("2000").WithPods(4).Build()
+	podUsage := func(pod *v1.Pod) (api.ReferencedResourceList, error) {
+		usage, ok := usages[pod.Name]
+		if !ok {
+			return nil, fmt.Errorf("no usage for %v", pod.Name)
+		}
+		return usage, nil
+	}
+
+	for _, tc := range []struct {
+		order    PodEvictionOrder
+		expected []string
+	}{
+		{order: PodEvictionOrderLargestConsumerFirst, expected: []string{"p2", "p1", "p3", "p4"}},
+		{order: PodEvictionOrderSmallestConsumerFirst, expected: []string{"p4", "p1", "p3", "p2"}},
+		{order: PodEvictionOrderOldestFirst, expected: []string{"p4", "p2", "p3", "p1"}},
+		{order: PodEvictionOrderNewestFirst, expected: []string{"p1", "p3", "p2", "p4"}},
+	} {
+		t.Run(string(tc.order), func(t *testing.T) {
+			sorter, err := podSorterFor(tc.order)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			sorted := slices.Clone(pods)
+			podutil.SortPodsBasedOnPriorityLowToHigh(sorted)
+			sorter.Sort(sorted, nodeUsage, podUsage)
+
+			var names []string
+			for _, pod := range sorted {
+				names = append(names, pod.Name)
+			}
+			if !slices.Equal(names, tc.expected) {
+				t.Errorf("Expected %v, got %v", tc.expected, names)
+			}
+		})
+	}
+}
+
+func TestPodSorterFor(t *testing.T) {
+	for _, order := range []PodEvictionOrder{"", PodEvictionOrderPriority} {
+		if sorter, err := podSorterFor(order); err != nil || sorter != nil {
+			t.Errorf("Expected no sorter for %q, got %v, %v", order, sorter, err)
+		}
+	}
+	if _, err := podSorterFor("Random"); err == nil {
+		t.Errorf("Expected an error for an unknown order")
+	}
+
+	RegisterPodSorter("Random", agePodSorter{})
+	defer func() {
+		podSortersLock.Lock()
+		defer podSortersLock.Unlock()
+		delete(podSorters, "Random")
+	}()
+	if sorter, err := podSorterFor("Random"); err != nil || sorter == nil {
+		t.Errorf("Expected the registered sorter, got %v, %v", sorter, err)
+	}
+}
+
+func TestLargestConsumerFirstEvictsFewerPods(t *testing.T) {
+	for _, tc := range []struct {
+		order    PodEvictionOrder
+		expected uint
+	}{
+		{order: PodEvictionOrderPriority, expected: 3},
+		{order: PodEvictionOrderLargestConsumerFirst, expected: 1},
+	} {
+		t.Run(string(tc.order), func(t *testing.T) {
+			ctx := context.Background()
+
+			n1 := test.BuildTestNode("n1", 4000, 3000, 10, nil)
+			n2 := test.BuildTestNode("n2", 4000, 3000, 10, nil)
+			objs := []runtime.Object{
+				n1, n2,
+				test.BuildTestPod("p1", 400, 0, n1.Name, test.SetRSOwnerRef),
+				test.BuildTestPod("p2", 400, 0, n1.Name, test.SetRSOwnerRef),
+				test.BuildTestPod("p3", 2400, 0, n1.Name, test.SetRSOwnerRef),
+				test.BuildTestPod("p4", 400, 0, n2.Name, test.SetRSOwnerRef),
+			}
+
+			handle, podEvictor, err := frameworktesting.InitFrameworkHandle(
+				ctx,
+				fake.NewSimpleClientset(objs...),
+				nil,
+				defaultevictor.DefaultEvictorArgs{},
+				func(pods []*v1.Pod) {
+					sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+				},
+			)
+			if err != nil {
+				t.Fatalf("Unable to initialize a framework handle: %v", err)
+			}
+
+			plugin, err := NewLowNodeUtilization(&LowNodeUtilizationArgs{
+				Thresholds:       api.ResourceThresholds{v1.ResourceCPU: 30},
+				TargetThresholds: api.ResourceThresholds{v1.ResourceCPU: 50},
+				PodEvictionOrder: tc.order,
+			}, handle)
+			if err != nil {
+				t.Fatalf("Unable to initialize the plugin: %v", err)
+			}
+
+			if status := plugin.(frameworktypes.BalancePlugin).Balance(ctx, []*v1.Node{n1, n2}); status != nil && status.Err != nil {
+				t.Fatalf("Unexpected error: %v", status.Err)
+			}
+			if evicted := podEvictor.TotalEvicted(); evicted != tc.expected {
+				t.Errorf("Expected %v pods to be evicted, got %v", tc.expected, evicted)
+			}
+		})
+	}
+}
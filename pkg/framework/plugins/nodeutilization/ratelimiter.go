@@ -0,0 +1,86 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/klog/v2"
+
+	frameworktypes "sigs.k8s.io/descheduler/pkg/framework/types"
+)
+
+// evictionRateLimiterTTL is how long a rate limiter is kept in the shared
+// cache after the plugin was last built. The bucket refills by itself, the
+// ttl only drops the limiters of the plugins no longer configured.
+const evictionRateLimiterTTL = 24 * time.Hour
+
+// rateLimitError is returned when no eviction token is left, the pods are
+// then evicted on the next descheduling cycles.
+type rateLimitError struct{}
+
+func (e *rateLimitError) Error() string {
+	return "eviction rate limit reached"
+}
+
+// sharedRateLimiter is the rate limiter of a plugin kept across cycles,
+// along with the limit it was created for.
+type sharedRateLimiter struct {
+	limit   EvictionRateLimit
+	limiter flowcontrol.RateLimiter
+}
+
+// evictionRateLimiterFor returns the token bucket rate limiter for the
+// provided limit. Plugins are built again on every descheduling cycle, the
+// limiter is kept in the shared cache, per profile and plugin, so its bucket
+// is not refilled at once every cycle. A new limiter is created when the
+// limit changes. nil is returned if no limit is configured.
+func evictionRateLimiterFor(handle frameworktypes.Handle, pluginName string, limit *EvictionRateLimit) flowcontrol.RateLimiter {
+	if limit == nil {
+		return nil
+	}
+	cache := frameworktypes.SharedCacheFor(handle)
+	key := fmt.Sprintf("nodeutilization/ratelimiter/%s/%s", frameworktypes.ProfileNameFor(handle), pluginName)
+	if obj, ok := cache.Get(key); ok {
+		if shared := obj.(*sharedRateLimiter); shared.limit == *limit {
+			cache.Set(key, shared, evictionRateLimiterTTL)
+			return shared.limiter
+		}
+	}
+
+	shared := &sharedRateLimiter{
+		limit: *limit,
+		limiter: flowcontrol.NewTokenBucketRateLimiter(
+			float32(limit.EvictionsPerMinute)/60, int(max(limit.Burst, 1)),
+		),
+	}
+	cache.Set(key, shared, evictionRateLimiterTTL)
+	return shared.limiter
+}
+
+// takeEvictionToken takes a token from the rate limiter without waiting for
+// one, so the cycle is not held up. an error is returned when the bucket is
+// empty.
+func takeEvictionToken(limiter flowcontrol.RateLimiter) error {
+	if !limiter.TryAccept() {
+		klog.V(1).InfoS("Eviction rate limit reached, no more pods evicted during this cycle")
+		return &rateLimitError{}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/descheduler/pkg/api"
+	"sigs.k8s.io/descheduler/pkg/framework/plugins/defaultevictor"
+	frameworktesting "sigs.k8s.io/descheduler/pkg/framework/testing"
+	frameworktypes "sigs.k8s.io/descheduler/pkg/framework/types"
+	"sigs.k8s.io/descheduler/test"
+)
+
+func TestLowNodeUtilizationEvictionRateLimit(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		rateLimit *EvictionRateLimit
+		cycles    int
+		expected  uint
+	}{
+		{
+			name:     "no rate limit",
+			expected: 2,
+		},
+		{
+			name:      "evictions do not wait for the next token",
+			rateLimit: &EvictionRateLimit{EvictionsPerMinute: 600},
+			expected:  1,
+		},
+		{
+			name:      "burst",
+			rateLimit: &EvictionRateLimit{EvictionsPerMinute: 1, Burst: 2},
+			expected:  2,
+		},
+		{
+			name:      "bucket is kept across cycles",
+			rateLimit: &EvictionRateLimit{EvictionsPerMinute: 1, Burst: 2},
+			cycles:    2,
+			expected:  2,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			nodes := []*v1.Node{
+				test.BuildTestNode("n1", 4000, 3000, 10, nil),
+				test.BuildTestNode("n2", 4000, 3000, 10, nil),
+			}
+			objs := []runtime.Object{nodes[0], nodes[1]}
+			for i := 0; i < 4; i++ {
+				objs = append(objs, test.BuildTestPod(fmt.Sprintf("n1-p%d", i), 800, 0, "n1", test.SetRSOwnerRef))
+			}
+			objs = append(objs, test.BuildTestPod("n2-p0", 400, 0, "n2", test.SetRSOwnerRef))
+
+			handle, podEvictor, err := frameworktesting.InitFrameworkHandle(
+				ctx,
+				fake.NewSimpleClientset(objs...),
+				nil,
+				defaultevictor.DefaultEvictorArgs{},
+				func(pods []*v1.Pod) {
+					sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+				},
+			)
+			if err != nil {
+				t.Fatalf("Unable to initialize a framework handle: %v", err)
+			}
+
+			// the plugin is built again on every cycle, as the
+			// descheduler does. evicted pods are not removed by
+			// the fake client, they are picked again.
+			for i := 0; i < max(tc.cycles, 1); i++ {
+				plugin, err := NewLowNodeUtilization(&LowNodeUtilizationArgs{
+					Thresholds:        api.ResourceThresholds{v1.ResourceCPU: 30},
+					TargetThresholds:  api.ResourceThresholds{v1.ResourceCPU: 50},
+					EvictionRateLimit: tc.rateLimit,
+				}, handle)
+				if err != nil {
+					t.Fatalf("Unable to initialize the plugin: %v", err)
+				}
+
+				if status := plugin.(frameworktypes.BalancePlugin).Balance(ctx, nodes); status != nil && status.Err != nil {
+					t.Fatalf("Unexpected error: %v", status.Err)
+				}
+			}
+			if evicted := podEvictor.TotalEvicted(); evicted != tc.expected {
+				t.Errorf("Expected %v pods to be evicted, got %v", tc.expected, evicted)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/descheduler/pkg/api"
+	podutil "sigs.k8s.io/descheduler/pkg/descheduler/pod"
+)
+
+// PodResizer resizes the containers of a running pod in place. the provided
+// pod holds the new requests of the containers.
+type PodResizer interface {
+	Resize(ctx context.Context, pod *v1.Pod) error
+}
+
+// clientPodResizer resizes the pods through the resize subresource, served
+// by clusters with the InPlacePodVerticalScaling feature enabled.
+type clientPodResizer struct {
+	client clientset.Interface
+}
+
+var _ PodResizer = &clientPodResizer{}
+
+// Resize updates the resize subresource of the pod.
+func (r *clientPodResizer) Resize(ctx context.Context, pod *v1.Pod) error {
+	_, err := r.client.CoreV1().Pods(pod.Namespace).UpdateResize(ctx, pod.Name, pod, metav1.UpdateOptions{})
+	return err
+}
+
+// inPlaceResizeResources returns the resources whose requests are shrunk,
+// cpu when none is configured.
+func inPlaceResizeResources(config *InPlaceResize) []v1.ResourceName {
+	if len(config.Resources) == 0 {
+		return []v1.ResourceName{v1.ResourceCPU}
+	}
+	return config.Resources
+}
+
+// shrunkPod returns a copy of the pod with the requests of its containers
+// shrunk as much as the configuration allows, along with by how much the
+// requests of the pod were shrunk. nil is returned if the pod can not be
+// shrunk: guaranteed pods would change their qos class and containers whose
+// resize policy restarts them are left alone.
+func shrunkPod(pod *v1.Pod, config *InPlaceResize) (*v1.Pod, v1.ResourceList) {
+	if pod.Status.QOSClass == v1.PodQOSGuaranteed {
+		return nil, nil
+	}
+
+	shrunk := pod.DeepCopy()
+	reduction := v1.ResourceList{}
+	for i := range shrunk.Spec.Containers {
+		container := &shrunk.Spec.Containers[i]
+		for _, name := range inPlaceResizeResources(config) {
+			request, ok := container.Resources.Requests[name]
+			if !ok || request.Sign() <= 0 || restartsOnResize(container, name) {
+				continue
+			}
+
+			value := int64(float64(request.MilliValue()) * (1 - float64(config.MaxReduction)/100))
+			if minimum, ok := config.MinRequests[name]; ok && value < minimum.MilliValue() {
+				value = minimum.MilliValue()
+			}
+			// requests are never dropped altogether, that would change
+			// the qos class of the pod.
+			if value <= 0 || value >= request.MilliValue() {
+				continue
+			}
+
+			delta := resource.NewMilliQuantity(request.MilliValue()-value, request.Format)
+			container.Resources.Requests[name] = *resource.NewMilliQuantity(value, request.Format)
+			total := reduction[name]
+			total.Add(*delta)
+			reduction[name] = total
+		}
+	}
+	if len(reduction) == 0 {
+		return nil, nil
+	}
+	return shrunk, reduction
+}
+
+// restartsOnResize tells if the container is restarted when the resource is
+// resized.
+func restartsOnResize(container *v1.Container, name v1.ResourceName) bool {
+	for _, policy := range container.ResizePolicy {
+		if policy.ResourceName == name {
+			return policy.RestartPolicy == v1.RestartContainer
+		}
+	}
+	return false
+}
+
+// shrinkPods shrinks in place the requests of the evictable pods of the
+// source nodes, before any pod is evicted, until the nodes are no longer
+// above their target thresholds. the usage of the nodes is updated as pods
+// are shrunk. in dry run mode the pods are not resized but the usage is
+// still updated. it returns the pods that were shrunk, they are not to be
+// evicted during the same invocation as their requests, as known to the
+// usage client, are outdated.
+func shrinkPods(
+	ctx context.Context,
+	resizer PodResizer,
+	config *InPlaceResize,
+	sourceNodes []NodeInfo,
+	podFilter func(pod *v1.Pod) bool,
+	dryRun bool,
+	summary *balanceSummary,
+) sets.Set[types.UID] {
+	resized := sets.New[types.UID]()
+	for _, nodeInfo := range sourceNodes {
+		_, removablePods := classifyPods(nodeInfo.allPods, podFilter)
+		podutil.SortPodsBasedOnPriorityLowToHigh(removablePods)
+		for _, pod := range removablePods {
+			if !isNodeAboveTargetUtilization(nodeInfo.NodeUsage, nodeInfo.available) {
+				break
+			}
+
+			shrunk, reduction := shrunkPod(pod, config)
+			if shrunk == nil {
+				continue
+			}
+			if !dryRun {
+				if err := resizer.Resize(ctx, shrunk); err != nil {
+					klog.ErrorS(err, "Unable to resize the pod in place", "pod", klog.KObj(pod))
+					continue
+				}
+			}
+
+			klog.V(1).InfoS(
+				"Pod resized in place",
+				append([]any{"pod", klog.KObj(pod), "node", klog.KObj(nodeInfo.node), "dryRun", dryRun}, usageToKeysAndValues(referencedResourceList(reduction))...)...,
+			)
+			for name, quantity := range reduction {
+				if usage, ok := nodeInfo.usage[name]; ok && usage != nil {
+					usage.Sub(quantity)
+				}
+			}
+			resized.Insert(pod.UID)
+			summary.resized++
+		}
+	}
+	return resized
+}
+
+// referencedResourceList converts a resource list into a referenced one.
+func referencedResourceList(list v1.ResourceList) api.ReferencedResourceList {
+	result := api.ReferencedResourceList{}
+	for name, quantity := range list {
+		result[name] = &quantity
+	}
+	return result
+}
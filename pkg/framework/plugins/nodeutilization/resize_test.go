@@ -0,0 +1,194 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/descheduler/pkg/api"
+	"sigs.k8s.io/descheduler/pkg/framework/plugins/defaultevictor"
+	frameworktesting "sigs.k8s.io/descheduler/pkg/framework/testing"
+	frameworktypes "sigs.k8s.io/descheduler/pkg/framework/types"
+	"sigs.k8s.io/descheduler/test"
+)
+
+type fakePodResizer struct {
+	resized []*v1.Pod
+}
+
+func (r *fakePodResizer) Resize(_ context.Context, pod *v1.Pod) error {
+	r.resized = append(r.resized, pod)
+	return nil
+}
+
+func TestShrunkPod(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		apply     func(*v1.Pod)
+		config    *InPlaceResize
+		cpu       int64
+		reduction int64
+	}{
+		{
+			name:      "cpu shrunk by the max reduction",
+			config:    &InPlaceResize{MaxReduction: 25},
+			cpu:       600,
+			reduction: 200,
+		},
+		{
+			name: "cpu shrunk down to the min requests",
+			config: &InPlaceResize{
+				MaxReduction: 75,
+				MinRequests:  v1.ResourceList{v1.ResourceCPU: resource.MustParse("500m")},
+			},
+			cpu:       500,
+			reduction: 300,
+		},
+		{
+			name: "min requests above the request",
+			config: &InPlaceResize{
+				MaxReduction: 50,
+				MinRequests:  v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")},
+			},
+		},
+		{
+			name: "guaranteed pod",
+			apply: func(pod *v1.Pod) {
+				pod.Status.QOSClass = v1.PodQOSGuaranteed
+			},
+			config: &InPlaceResize{MaxReduction: 50},
+		},
+		{
+			name: "container restarted on resize",
+			apply: func(pod *v1.Pod) {
+				pod.Spec.Containers[0].ResizePolicy = []v1.ContainerResizePolicy{
+					{ResourceName: v1.ResourceCPU, RestartPolicy: v1.RestartContainer},
+				}
+			},
+			config: &InPlaceResize{MaxReduction: 50},
+		},
+		{
+			name:   "resource not requested",
+			config: &InPlaceResize{MaxReduction: 50, Resources: []v1.ResourceName{v1.ResourceMemory}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			pod := test.BuildTestPod("p1", 800, 0, "n1", tc.apply)
+			shrunk, reduction := shrunkPod(pod, tc.config)
+			if tc.reduction == 0 {
+				if shrunk != nil {
+					t.Fatalf("expected the pod not to be shrunk, got requests %v instead", shrunk.Spec.Containers[0].Resources.Requests)
+				}
+				return
+			}
+			if shrunk == nil {
+				t.Fatalf("expected the pod to be shrunk")
+			}
+			if cpu := shrunk.Spec.Containers[0].Resources.Requests.Cpu().MilliValue(); cpu != tc.cpu {
+				t.Errorf("expected a cpu request of %vm, got %vm instead", tc.cpu, cpu)
+			}
+			if cpu := reduction.Cpu().MilliValue(); cpu != tc.reduction {
+				t.Errorf("expected a cpu reduction of %vm, got %vm instead", tc.reduction, cpu)
+			}
+			if cpu := pod.Spec.Containers[0].Resources.Requests.Cpu().MilliValue(); cpu != 800 {
+				t.Errorf("expected the original pod to be left untouched, got a cpu request of %vm", cpu)
+			}
+		})
+	}
+}
+
+func TestLowNodeUtilizationInPlaceResize(t *testing.T) {
+	ctx := context.Background()
+
+	nodes := []*v1.Node{
+		test.BuildTestNode("n1", 4000, 3000, 10, nil),
+		test.BuildTestNode("n2", 4000, 3000, 10, nil),
+	}
+	objs := []runtime.Object{nodes[0], nodes[1]}
+	for i := 0; i < 3; i++ {
+		objs = append(objs, test.BuildTestPod(fmt.Sprintf("n1-p%d", i), 800, 0, "n1", test.SetRSOwnerRef))
+	}
+	// guaranteed pods are never shrunk, only evicted.
+	objs = append(objs, test.BuildTestPod("n1-p3", 800, 0, "n1", func(pod *v1.Pod) {
+		test.SetRSOwnerRef(pod)
+		pod.Status.QOSClass = v1.PodQOSGuaranteed
+	}))
+	objs = append(objs, test.BuildTestPod("n2-p0", 400, 0, "n2", test.SetRSOwnerRef))
+
+	for _, tc := range []struct {
+		name         string
+		maxReduction api.Percentage
+		resized      int
+		evicted      uint
+	}{
+		{
+			name:         "shrinking is enough",
+			maxReduction: 50,
+			resized:      3,
+		},
+		{
+			name:         "shrinking is not enough",
+			maxReduction: 10,
+			resized:      3,
+			evicted:      1,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			handle, podEvictor, err := frameworktesting.InitFrameworkHandle(
+				ctx,
+				fake.NewSimpleClientset(objs...),
+				nil,
+				defaultevictor.DefaultEvictorArgs{},
+				func(pods []*v1.Pod) {
+					sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+				},
+			)
+			if err != nil {
+				t.Fatalf("Unable to initialize a framework handle: %v", err)
+			}
+
+			plugin, err := NewLowNodeUtilization(&LowNodeUtilizationArgs{
+				Thresholds:       api.ResourceThresholds{v1.ResourceCPU: 30},
+				TargetThresholds: api.ResourceThresholds{v1.ResourceCPU: 50},
+				InPlaceResize:    &InPlaceResize{MaxReduction: tc.maxReduction},
+			}, handle)
+			if err != nil {
+				t.Fatalf("Unable to initialize the plugin: %v", err)
+			}
+			resizer := &fakePodResizer{}
+			plugin.(*LowNodeUtilization).podResizer = resizer
+
+			if status := plugin.(frameworktypes.BalancePlugin).Balance(ctx, nodes); status != nil && status.Err != nil {
+				t.Fatalf("Unexpected error: %v", status.Err)
+			}
+			if len(resizer.resized) != tc.resized {
+				t.Errorf("Expected %v pods to be resized, got %v", tc.resized, len(resizer.resized))
+			}
+			if evicted := podEvictor.TotalEvicted(); evicted != tc.evicted {
+				t.Errorf("Expected %v pods to be evicted, got %v", tc.evicted, evicted)
+			}
+		})
+	}
+}
@@ -0,0 +1,346 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
+
+	promapi "github.com/prometheus/client_golang/api"
+	"github.com/prometheus/common/model"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/workqueue"
+	utilptr "k8s.io/utils/ptr"
+)
+
+// ResourceUsageCollector collects the actual usage of a resource the metrics
+// server does not report, e.g. the utilization of the nvidia.com/gpu devices
+// exposed by the NVIDIA DCGM exporter. Usages are expressed in units of the
+// resource, e.g. 1.5 for one and a half fully used GPUs.
+type ResourceUsageCollector interface {
+	// NodesUsage returns the usage of the resource on the provided
+	// nodes, indexed by node name.
+	NodesUsage(ctx context.Context, nodes []*v1.Node) (map[string]*resource.Quantity, error)
+	// PodUsage returns the usage of the resource by the pod.
+	PodUsage(ctx context.Context, pod *v1.Pod) (*resource.Quantity, error)
+}
+
+var (
+	resourceUsageCollectorsLock sync.RWMutex
+	// resourceUsageCollectors are the collectors registered per resource.
+	resourceUsageCollectors = map[v1.ResourceName]ResourceUsageCollector{}
+)
+
+// RegisterResourceUsageCollector makes the collector report the actual usage
+// of the resource, replacing the collector previously registered for it, if
+// any. Collectors configured in the plugin arguments take precedence.
+func RegisterResourceUsageCollector(name v1.ResourceName, collector ResourceUsageCollector) {
+	resourceUsageCollectorsLock.Lock()
+	defer resourceUsageCollectorsLock.Unlock()
+	resourceUsageCollectors[name] = collector
+}
+
+// resourceUsageCollectorsFor returns the collectors of the provided resources
+// among the ones configured in the plugin arguments and the registered ones.
+// cpu, memory and pods are left out, their usage is known otherwise. unless
+// another collector is provided, the ephemeral storage usage is read from the
+// kubelet stats summary through the provided rest client.
+func resourceUsageCollectorsFor(
+	resourceNames []v1.ResourceName,
+	configured []ExtendedResourceMetrics,
+	promClient promapi.Client,
+	restClient rest.Interface,
+) (map[v1.ResourceName]ResourceUsageCollector, error) {
+	resourceUsageCollectorsLock.RLock()
+	defer resourceUsageCollectorsLock.RUnlock()
+
+	collectors := map[v1.ResourceName]ResourceUsageCollector{}
+	for _, name := range resourceNames {
+		switch name {
+		case v1.ResourceCPU, v1.ResourceMemory, v1.ResourcePods:
+			continue
+		}
+		if collector, ok := resourceUsageCollectors[name]; ok {
+			collectors[name] = collector
+		}
+	}
+
+	if slices.Contains(resourceNames, v1.ResourceEphemeralStorage) && collectors[v1.ResourceEphemeralStorage] == nil {
+		collectors[v1.ResourceEphemeralStorage] = newStatsSummaryCollector(restClient)
+	}
+
+	for _, metrics := range configured {
+		if !slices.Contains(resourceNames, metrics.Name) {
+			continue
+		}
+		if promClient == nil {
+			return nil, fmt.Errorf("prometheus client not initialized")
+		}
+		collectors[metrics.Name] = &prometheusResourceCollector{
+			promClient: promClient,
+			query:      metrics.Query,
+			nodeLabel:  metrics.NodeLabel,
+			podQuery:   metrics.PodQuery,
+		}
+	}
+	return collectors, nil
+}
+
+// prometheusResourceCollector collects the usage of a resource through
+// prometheus queries.
+type prometheusResourceCollector struct {
+	promClient promapi.Client
+	query      string
+	nodeLabel  string
+	podQuery   string
+}
+
+var _ ResourceUsageCollector = &prometheusResourceCollector{}
+
+// NodesUsage runs the query, each sample holds the usage of a node.
+func (c *prometheusResourceCollector) NodesUsage(ctx context.Context, _ []*v1.Node) (map[string]*resource.Quantity, error) {
+	nodeLabel := c.nodeLabel
+	if nodeLabel == "" {
+		nodeLabel = defaultPrometheusNodeLabel
+	}
+
+	samples, err := queryPrometheusVector(ctx, c.promClient, c.query)
+	if err != nil {
+		return nil, err
+	}
+
+	usages := map[string]*resource.Quantity{}
+	for _, sample := range samples {
+		node, ok := sample.Metric[model.LabelName(nodeLabel)]
+		if !ok {
+			return nil, fmt.Errorf("The collected metrics sample is missing '%v' key", nodeLabel)
+		}
+		usage, err := resourceQuantity(float64(sample.Value))
+		if err != nil {
+			return nil, fmt.Errorf("The collected metrics sample for %q is not valid: %v", node, err)
+		}
+		usages[string(node)] = usage
+	}
+	return usages, nil
+}
+
+// PodUsage runs the pod query, the samples are summed up so queries reporting
+// the usage of every container of the pod can be used as is. without a pod
+// query the pod usage is not supported.
+func (c *prometheusResourceCollector) PodUsage(ctx context.Context, pod *v1.Pod) (*resource.Quantity, error) {
+	if c.podQuery == "" {
+		return nil, newNotSupportedError(actualUsageClientType)
+	}
+
+	tmpl, err := template.New("podQuery").Parse(c.podQuery)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse prometheus pod query template: %v", err)
+	}
+	var query strings.Builder
+	if err := tmpl.Execute(&query, prometheusPodQueryData{Namespace: pod.Namespace, Pod: pod.Name}); err != nil {
+		return nil, fmt.Errorf("unable to render prometheus pod query template: %v", err)
+	}
+
+	samples, err := queryPrometheusVector(ctx, c.promClient, query.String())
+	if err != nil {
+		return nil, err
+	}
+	if len(samples) == 0 {
+		return nil, fmt.Errorf("unable to find metric entry for %v/%v", pod.Namespace, pod.Name)
+	}
+
+	var value float64
+	for _, sample := range samples {
+		value += float64(sample.Value)
+	}
+	return resourceQuantity(value)
+}
+
+// resourceQuantity converts a sample value into a quantity, in milli units
+// so fractions of the resource are not lost.
+func resourceQuantity(value float64) (*resource.Quantity, error) {
+	// written as a negated check so NaN values are rejected too.
+	if !(value >= 0) || math.IsInf(value, 1) {
+		return nil, fmt.Errorf("value %v must be a non negative number", value)
+	}
+	return resource.NewMilliQuantity(int64(math.Round(value*1000)), resource.DecimalSI), nil
+}
+
+// coreRESTClient returns the rest client of the core api group, nil when
+// the clientset does not provide one, e.g. the fake clientsets.
+func coreRESTClient(client clientset.Interface) rest.Interface {
+	if client == nil {
+		return nil
+	}
+	restClient, ok := client.CoreV1().RESTClient().(*rest.RESTClient)
+	if !ok || restClient == nil {
+		return nil
+	}
+	return restClient
+}
+
+// statsSummaryParallelism is the number of nodes whose kubelet stats summary
+// is fetched concurrently.
+const statsSummaryParallelism = 16
+
+// statsSummary holds the part of the kubelet stats summary the descheduler
+// relies on, see k8s.io/kubelet/pkg/apis/stats/v1alpha1.
+type statsSummary struct {
+	Node struct {
+		Fs *fsStats `json:"fs,omitempty"`
+	} `json:"node"`
+	Pods []struct {
+		PodRef struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"podRef"`
+		EphemeralStorage *fsStats `json:"ephemeral-storage,omitempty"`
+	} `json:"pods"`
+}
+
+type fsStats struct {
+	UsedBytes *uint64 `json:"usedBytes,omitempty"`
+}
+
+// statsSummaryCollector collects the ephemeral storage usage of the nodes
+// and their pods from the kubelet stats summary, reached through the node
+// proxy of the api server. the usage of a node is the one of its root file
+// system, the same the kubelet relies on when evicting on disk pressure.
+type statsSummaryCollector struct {
+	client rest.Interface
+
+	mu sync.Mutex
+	// _podUsage holds the usage of the pods found in the summaries
+	// fetched on the last NodesUsage call.
+	_podUsage map[types.NamespacedName]*resource.Quantity
+}
+
+var _ ResourceUsageCollector = &statsSummaryCollector{}
+
+func newStatsSummaryCollector(client rest.Interface) *statsSummaryCollector {
+	return &statsSummaryCollector{client: client}
+}
+
+// NodesUsage fetches the stats summary of every node. nodes whose summary
+// can not be fetched are left out.
+func (c *statsSummaryCollector) NodesUsage(ctx context.Context, nodes []*v1.Node) (map[string]*resource.Quantity, error) {
+	usages := map[string]*resource.Quantity{}
+	podUsage := map[types.NamespacedName]*resource.Quantity{}
+	errs := make([]error, len(nodes))
+
+	var mu sync.Mutex
+	workqueue.ParallelizeUntil(ctx, statsSummaryParallelism, len(nodes), func(i int) {
+		summary, err := c.summary(ctx, nodes[i].Name)
+		if err != nil {
+			errs[i] = err
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if summary.Node.Fs != nil && summary.Node.Fs.UsedBytes != nil {
+			usages[nodes[i].Name] = bytesQuantity(*summary.Node.Fs.UsedBytes)
+		}
+		for _, pod := range summary.Pods {
+			if pod.EphemeralStorage == nil || pod.EphemeralStorage.UsedBytes == nil {
+				continue
+			}
+			key := types.NamespacedName{Namespace: pod.PodRef.Namespace, Name: pod.PodRef.Name}
+			podUsage[key] = bytesQuantity(*pod.EphemeralStorage.UsedBytes)
+		}
+	})
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c._podUsage = podUsage
+	return usages, nil
+}
+
+// PodUsage returns the usage found in the summary of the pod node, the
+// summary is fetched again if the pod was not part of the last one.
+func (c *statsSummaryCollector) PodUsage(ctx context.Context, pod *v1.Pod) (*resource.Quantity, error) {
+	key := types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}
+
+	c.mu.Lock()
+	usage, ok := c._podUsage[key]
+	c.mu.Unlock()
+	if ok {
+		return utilptr.To(usage.DeepCopy()), nil
+	}
+
+	summary, err := c.summary(ctx, pod.Spec.NodeName)
+	if err != nil {
+		return nil, err
+	}
+	for _, stats := range summary.Pods {
+		if stats.PodRef.Namespace != pod.Namespace || stats.PodRef.Name != pod.Name {
+			continue
+		}
+		if stats.EphemeralStorage == nil || stats.EphemeralStorage.UsedBytes == nil {
+			break
+		}
+		return bytesQuantity(*stats.EphemeralStorage.UsedBytes), nil
+	}
+	return nil, fmt.Errorf("unable to find stats summary entry for %v/%v", pod.Namespace, pod.Name)
+}
+
+// summary fetches the stats summary of the node.
+func (c *statsSummaryCollector) summary(ctx context.Context, nodeName string) (*statsSummary, error) {
+	if c.client == nil {
+		return nil, fmt.Errorf("kubelet stats summary client not initialized")
+	}
+	raw, err := c.client.Get().
+		Resource("nodes").
+		Name(nodeName).
+		SubResource("proxy").
+		Suffix("stats", "summary").
+		Do(ctx).
+		Raw()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the stats summary of node %q: %v", nodeName, err)
+	}
+
+	summary := &statsSummary{}
+	if err := json.Unmarshal(raw, summary); err != nil {
+		return nil, fmt.Errorf("unable to decode the stats summary of node %q: %v", nodeName, err)
+	}
+	return summary, nil
+}
+
+// bytesQuantity converts a number of bytes into a quantity.
+func bytesQuantity(value uint64) *resource.Quantity {
+	if value > math.MaxInt64 {
+		value = math.MaxInt64
+	}
+	return resource.NewQuantity(int64(value), resource.BinarySI)
+}
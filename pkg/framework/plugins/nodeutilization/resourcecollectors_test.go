@@ -0,0 +1,292 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/prometheus/common/model"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	fakeclientset "k8s.io/client-go/kubernetes/fake"
+	fakemetricsclient "k8s.io/metrics/pkg/client/clientset/versioned/fake"
+
+	"sigs.k8s.io/descheduler/pkg/descheduler/metricscollector"
+	podutil "sigs.k8s.io/descheduler/pkg/descheduler/pod"
+	frameworktesting "sigs.k8s.io/descheduler/pkg/framework/testing"
+	"sigs.k8s.io/descheduler/test"
+)
+
+const testGPU = v1.ResourceName("nvidia.com/gpu")
+
+// staticResourceCollector reports the same usage for every node and pod.
+type staticResourceCollector struct {
+	usage string
+}
+
+func (c staticResourceCollector) NodesUsage(_ context.Context, nodes []*v1.Node) (map[string]*resource.Quantity, error) {
+	usages := map[string]*resource.Quantity{}
+	for _, node := range nodes {
+		usage := resource.MustParse(c.usage)
+		usages[node.Name] = &usage
+	}
+	return usages, nil
+}
+
+func (c staticResourceCollector) PodUsage(context.Context, *v1.Pod) (*resource.Quantity, error) {
+	usage := resource.MustParse(c.usage)
+	return &usage, nil
+}
+
+func TestResourceUsageCollectorsFor(t *testing.T) {
+	RegisterResourceUsageCollector(testGPU, staticResourceCollector{usage: "1"})
+	RegisterResourceUsageCollector(v1.ResourceCPU, staticResourceCollector{usage: "1"})
+	defer func() {
+		resourceUsageCollectorsLock.Lock()
+		defer resourceUsageCollectorsLock.Unlock()
+		delete(resourceUsageCollectors, testGPU)
+		delete(resourceUsageCollectors, v1.ResourceCPU)
+	}()
+
+	configured := []ExtendedResourceMetrics{
+		{Name: testGPU, Query: "gpu"},
+		{Name: "example.com/foo", Query: "foo"},
+	}
+	promClient := &frameworktesting.FakePrometheusClient{}
+
+	for _, tc := range []struct {
+		name       string
+		configured []ExtendedResourceMetrics
+		expected   map[v1.ResourceName]ResourceUsageCollector
+	}{
+		{
+			name:     "registered collectors",
+			expected: map[v1.ResourceName]ResourceUsageCollector{testGPU: staticResourceCollector{usage: "1"}},
+		},
+		{
+			name:       "configured collectors take precedence",
+			configured: configured,
+			expected: map[v1.ResourceName]ResourceUsageCollector{
+				testGPU: &prometheusResourceCollector{promClient: promClient, query: "gpu"},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			collectors, err := resourceUsageCollectorsFor(
+				[]v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory, testGPU}, tc.configured, promClient, nil,
+			)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(collectors) != len(tc.expected) {
+				t.Fatalf("Expected %v, got %v", tc.expected, collectors)
+			}
+			for name, expected := range tc.expected {
+				if got, ok := collectors[name]; !ok || !equalCollectors(got, expected) {
+					t.Errorf("Expected %v collector to be %v, got %v", name, expected, got)
+				}
+			}
+		})
+	}
+
+	if _, err := resourceUsageCollectorsFor([]v1.ResourceName{testGPU}, configured, nil, nil); err == nil {
+		t.Errorf("Expected an error without prometheus client")
+	}
+
+	restClient := fakeMetricsRESTClient(nil, nil)
+	collectors, err := resourceUsageCollectorsFor([]v1.ResourceName{v1.ResourceEphemeralStorage}, nil, nil, restClient)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := collectors[v1.ResourceEphemeralStorage].(*statsSummaryCollector); !ok {
+		t.Errorf("Expected the stats summary collector by default, got %v", collectors)
+	}
+	collectors, err = resourceUsageCollectorsFor([]v1.ResourceName{v1.ResourceEphemeralStorage}, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	nodes := []*v1.Node{test.BuildTestNode("n1", 1000, 1000, 10, nil)}
+	if _, err := collectors[v1.ResourceEphemeralStorage].NodesUsage(context.TODO(), nodes); err == nil {
+		t.Errorf("Expected an error without kubelet stats summary client")
+	}
+}
+
+func equalCollectors(a, b ResourceUsageCollector) bool {
+	pa, aok := a.(*prometheusResourceCollector)
+	pb, bok := b.(*prometheusResourceCollector)
+	if aok && bok {
+		return *pa == *pb
+	}
+	return a == b
+}
+
+func TestActualUsageClientExtendedResources(t *testing.T) {
+	ctx := context.TODO()
+	n1 := test.BuildTestNode("n1", 2000, 3000, 10, nil)
+	p1 := test.BuildTestPod("p1", 400, 0, n1.Name, nil)
+	p1.Namespace = "default"
+
+	clientset := fakeclientset.NewSimpleClientset(n1, p1)
+	metricsClientset := fakemetricsclient.NewSimpleClientset()
+	metricsClientset.Tracker().Create(nodesgvr, test.BuildNodeMetrics(n1.Name, 400, 1714978816), "")
+	metricsClientset.Tracker().Create(podsgvr, test.BuildPodMetrics(p1.Name, 300, 1024), "default")
+
+	sharedInformerFactory := informers.NewSharedInformerFactory(clientset, 0)
+	podInformer := sharedInformerFactory.Core().V1().Pods().Informer()
+	nodeLister := sharedInformerFactory.Core().V1().Nodes().Lister()
+	podsAssignedToNode, err := podutil.BuildGetPodsAssignedToNodeFunc(podInformer)
+	if err != nil {
+		t.Fatalf("Build get pods assigned to node function error: %v", err)
+	}
+	sharedInformerFactory.Start(ctx.Done())
+	sharedInformerFactory.WaitForCacheSync(ctx.Done())
+
+	collector := metricscollector.NewMetricsCollector(nodeLister, metricsClientset, labels.Everything())
+	if err := collector.Collect(ctx); err != nil {
+		t.Fatalf("failed to capture metrics: %v", err)
+	}
+
+	query := "sum by (instance) (DCGM_FI_DEV_GPU_UTIL) / 100"
+	podQuery := `sum(DCGM_FI_DEV_GPU_UTIL{namespace="{{.Namespace}}",pod="{{.Pod}}"}) / 100`
+	renderedPodQuery := `sum(DCGM_FI_DEV_GPU_UTIL{namespace="default",pod="p1"}) / 100`
+	promClient := &frameworktesting.FakePrometheusClient{
+		Responses: map[string]frameworktesting.PrometheusResponse{
+			query:            {Result: model.Vector{frameworktesting.PrometheusSample("gpu", n1.Name, 1.5)}},
+			renderedPodQuery: {Result: model.Vector{frameworktesting.PrometheusSample("gpu", n1.Name, 0.25)}},
+		},
+	}
+
+	resourceNames := []v1.ResourceName{v1.ResourceCPU, testGPU}
+	collectors, err := resourceUsageCollectorsFor(
+		resourceNames,
+		[]ExtendedResourceMetrics{{Name: testGPU, Query: query, PodQuery: podQuery}},
+		promClient,
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	usageClient := newActualUsageClient(resourceNames, podsAssignedToNode, collector, 0, 0, collectors)
+	if err := usageClient.Sync(ctx, []*v1.Node{n1}); err != nil {
+		t.Fatalf("failed to sync a snapshot: %v", err)
+	}
+
+	nodeUsage := usageClient.NodeUtilization(n1.Name)
+	if cpu := nodeUsage[v1.ResourceCPU].MilliValue(); cpu != 400 {
+		t.Errorf("expected node cpu usage to be 400m, got %vm", cpu)
+	}
+	if gpu := nodeUsage[testGPU].MilliValue(); gpu != 1500 {
+		t.Errorf("expected node gpu usage to be 1500m, got %vm", gpu)
+	}
+
+	podUsage, err := usageClient.PodUsage(p1)
+	if err != nil {
+		t.Fatalf("unexpected error getting pod usage: %v", err)
+	}
+	if cpu := podUsage[v1.ResourceCPU].MilliValue(); cpu != 300 {
+		t.Errorf("expected pod cpu usage to be 300m, got %vm", cpu)
+	}
+	if gpu := podUsage[testGPU].MilliValue(); gpu != 250 {
+		t.Errorf("expected pod gpu usage to be 250m, got %vm", gpu)
+	}
+
+	expectedQueries := []string{query, renderedPodQuery}
+	if !slices.Equal(promClient.Queries(), expectedQueries) {
+		t.Errorf("expected queries %v, got %v instead", expectedQueries, promClient.Queries())
+	}
+}
+
+func TestActualUsageClientEphemeralStorage(t *testing.T) {
+	ctx := context.TODO()
+	n1 := test.BuildTestNode("n1", 2000, 3000, 10, nil)
+	p1 := test.BuildTestPod("p1", 400, 0, n1.Name, nil)
+	p1.Namespace = "default"
+	p2 := test.BuildTestPod("p2", 400, 0, n1.Name, nil)
+	p2.Namespace = "default"
+
+	clientset := fakeclientset.NewSimpleClientset(n1, p1, p2)
+	metricsClientset := fakemetricsclient.NewSimpleClientset()
+	metricsClientset.Tracker().Create(nodesgvr, test.BuildNodeMetrics(n1.Name, 400, 1714978816), "")
+	metricsClientset.Tracker().Create(podsgvr, test.BuildPodMetrics(p1.Name, 300, 1024), "default")
+	metricsClientset.Tracker().Create(podsgvr, test.BuildPodMetrics(p2.Name, 100, 1024), "default")
+
+	sharedInformerFactory := informers.NewSharedInformerFactory(clientset, 0)
+	podInformer := sharedInformerFactory.Core().V1().Pods().Informer()
+	nodeLister := sharedInformerFactory.Core().V1().Nodes().Lister()
+	podsAssignedToNode, err := podutil.BuildGetPodsAssignedToNodeFunc(podInformer)
+	if err != nil {
+		t.Fatalf("Build get pods assigned to node function error: %v", err)
+	}
+	sharedInformerFactory.Start(ctx.Done())
+	sharedInformerFactory.WaitForCacheSync(ctx.Done())
+
+	collector := metricscollector.NewMetricsCollector(nodeLister, metricsClientset, labels.Everything())
+	if err := collector.Collect(ctx); err != nil {
+		t.Fatalf("failed to capture metrics: %v", err)
+	}
+
+	summaryPath := "/nodes/n1/proxy/stats/summary"
+	responses := map[string]string{
+		summaryPath: `{
+			"node": {"nodeName": "n1", "fs": {"usedBytes": 4096}},
+			"pods": [
+				{"podRef": {"name": "p1", "namespace": "default"}, "ephemeral-storage": {"usedBytes": 1024}},
+				{"podRef": {"name": "p3", "namespace": "default"}, "ephemeral-storage": {"usedBytes": 512}}
+			]
+		}`,
+	}
+	requests := map[string]string{}
+	resourceNames := []v1.ResourceName{v1.ResourceCPU, v1.ResourceEphemeralStorage}
+	collectors, err := resourceUsageCollectorsFor(resourceNames, nil, nil, fakeMetricsRESTClient(responses, requests))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	usageClient := newActualUsageClient(resourceNames, podsAssignedToNode, collector, 0, 0, collectors)
+	if err := usageClient.Sync(ctx, []*v1.Node{n1}); err != nil {
+		t.Fatalf("failed to sync a snapshot: %v", err)
+	}
+	if _, ok := requests[summaryPath]; !ok {
+		t.Errorf("expected the stats summary of n1 to be fetched, got %v", requests)
+	}
+
+	nodeUsage := usageClient.NodeUtilization(n1.Name)
+	if storage := nodeUsage[v1.ResourceEphemeralStorage].Value(); storage != 4096 {
+		t.Errorf("expected node ephemeral storage usage to be 4096, got %v", storage)
+	}
+
+	podUsage, err := usageClient.PodUsage(p1)
+	if err != nil {
+		t.Fatalf("unexpected error getting pod usage: %v", err)
+	}
+	if cpu := podUsage[v1.ResourceCPU].MilliValue(); cpu != 300 {
+		t.Errorf("expected pod cpu usage to be 300m, got %vm", cpu)
+	}
+	if storage := podUsage[v1.ResourceEphemeralStorage].Value(); storage != 1024 {
+		t.Errorf("expected pod ephemeral storage usage to be 1024, got %v", storage)
+	}
+
+	// p2 is not part of the stats summary, fetching it again does not help.
+	if _, err := usageClient.PodUsage(p2); err == nil {
+		t.Errorf("expected an error getting the usage of a pod missing from the stats summary")
+	}
+}
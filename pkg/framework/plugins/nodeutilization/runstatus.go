@@ -0,0 +1,124 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+// maxRunStatusErrors is the maximum number of errors kept in the run status,
+// the errors past it are only logged.
+const maxRunStatusErrors = 10
+
+// runStatusReport is the outcome of a Balance invocation, as written to the
+// run status ConfigMap.
+type runStatusReport struct {
+	StartTime            metav1.Time    `json:"startTime"`
+	Duration             string         `json:"duration"`
+	DryRun               bool           `json:"dryRun,omitempty"`
+	ClassifiedNodes      map[string]int `json:"classifiedNodes"`
+	ConsideredPods       int            `json:"consideredPods"`
+	EvictedPods          int            `json:"evictedPods"`
+	SkippedPods          int            `json:"skippedPods"`
+	SkippedPodsPerReason map[string]int `json:"skippedPodsPerReason,omitempty"`
+	Errors               []string       `json:"errors,omitempty"`
+}
+
+// failed accounts for an error hit during the invocation.
+func (s *balanceSummary) failed(err error) {
+	if len(s.errors) < maxRunStatusErrors {
+		s.errors = append(s.errors, err.Error())
+	}
+}
+
+// runStatusReport converts the summary into its run status report.
+func (s *balanceSummary) runStatusReport() runStatusReport {
+	return runStatusReport{
+		StartTime: metav1.NewTime(s.start),
+		Duration:  time.Since(s.start).String(),
+		DryRun:    s.dryRun != nil,
+		ClassifiedNodes: map[string]int{
+			classificationUnderutilized: s.underutilized,
+			classificationOverutilized:  s.overutilized,
+			classificationAppropriate:   max(len(s.usage)-s.underutilized-s.overutilized, 0),
+		},
+		ConsideredPods:       s.considered,
+		EvictedPods:          s.evicted,
+		SkippedPods:          s.skipped,
+		SkippedPodsPerReason: s.skippedPerReason,
+		Errors:               s.errors,
+	}
+}
+
+// writeRunStatus writes the outcome of the invocation to the run status
+// ConfigMap, under a key named after the plugin, creating the ConfigMap if
+// needed. writing the status is best effort, failures are only logged.
+func (s *balanceSummary) writeRunStatus(ctx context.Context, client clientset.Interface, config *RunStatus) {
+	if config == nil {
+		return
+	}
+
+	report, err := json.Marshal(s.runStatusReport())
+	if err != nil {
+		klog.ErrorS(err, "Unable to encode the run status", "plugin", s.plugin)
+		return
+	}
+
+	if err := patchRunStatus(ctx, client, config, s.plugin, string(report)); err != nil {
+		klog.ErrorS(
+			err, "Unable to write the run status",
+			"plugin", s.plugin,
+			"configMap", klog.KRef(config.Namespace, config.Name),
+		)
+	}
+}
+
+// patchRunStatus sets the key of the ConfigMap to the report. the ConfigMap
+// is patched so the keys of other plugins are left untouched.
+func patchRunStatus(ctx context.Context, client clientset.Interface, config *RunStatus, key, report string) error {
+	patch, err := json.Marshal(map[string]any{
+		"data": map[string]string{key: report},
+	})
+	if err != nil {
+		return err
+	}
+
+	configMaps := client.CoreV1().ConfigMaps(config.Namespace)
+	_, err = configMaps.Patch(ctx, config.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	_, err = configMaps.Create(ctx, &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: config.Namespace, Name: config.Name},
+		Data:       map[string]string{key: report},
+	}, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		// created in the meantime, by another descheduler instance.
+		_, err = configMaps.Patch(ctx, config.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	}
+	return err
+}
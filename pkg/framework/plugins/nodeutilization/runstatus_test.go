@@ -0,0 +1,83 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/descheduler/pkg/api"
+)
+
+func TestWriteRunStatus(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset()
+	config := &RunStatus{Namespace: "kube-system", Name: "descheduler-status"}
+
+	low := newBalanceSummary(LowNodeUtilizationPluginName)
+	low.assessed(map[string]api.ResourceThresholds{"n1": nil, "n2": nil, "n3": nil, "n4": nil}, nil)
+	low.underutilized, low.overutilized = 1, 2
+	low.considered = 3
+	low.podEvicted("n2")
+	low.podSkipped(skipReasonNoFit)
+	low.failed(errors.New("eviction failed"))
+	low.writeRunStatus(ctx, client, config)
+
+	// the status of a second plugin is added next to the first one.
+	high := newBalanceSummary(HighNodeUtilizationPluginName)
+	high.writeRunStatus(ctx, client, config)
+
+	configMap, err := client.CoreV1().ConfigMaps(config.Namespace).Get(ctx, config.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Unable to get the run status: %v", err)
+	}
+	if _, ok := configMap.Data[HighNodeUtilizationPluginName]; !ok {
+		t.Errorf("Expected the run status of %v, got %v", HighNodeUtilizationPluginName, configMap.Data)
+	}
+
+	var report runStatusReport
+	if err := json.Unmarshal([]byte(configMap.Data[LowNodeUtilizationPluginName]), &report); err != nil {
+		t.Fatalf("Unable to decode the run status of %v: %v", LowNodeUtilizationPluginName, err)
+	}
+	expected := map[string]int{
+		classificationUnderutilized: 1,
+		classificationOverutilized:  2,
+		classificationAppropriate:   1,
+	}
+	for classification, count := range expected {
+		if report.ClassifiedNodes[classification] != count {
+			t.Errorf("Expected %v %v nodes, got %v", count, classification, report.ClassifiedNodes[classification])
+		}
+	}
+	if report.ConsideredPods != 3 || report.EvictedPods != 1 || report.SkippedPods != 1 {
+		t.Errorf("Expected 3 considered, 1 evicted and 1 skipped pods, got %+v", report)
+	}
+	if report.SkippedPodsPerReason[skipReasonNoFit] != 1 {
+		t.Errorf("Expected a pod skipped for %v, got %v", skipReasonNoFit, report.SkippedPodsPerReason)
+	}
+	if len(report.Errors) != 1 || report.Errors[0] != "eviction failed" {
+		t.Errorf("Expected the eviction failure to be reported, got %v", report.Errors)
+	}
+	if report.StartTime.IsZero() || report.Duration == "" {
+		t.Errorf("Expected the start time and duration to be reported, got %+v", report)
+	}
+}
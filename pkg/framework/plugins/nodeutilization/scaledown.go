@@ -0,0 +1,148 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"context"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	nodeutil "sigs.k8s.io/descheduler/pkg/descheduler/node"
+	podutil "sigs.k8s.io/descheduler/pkg/descheduler/pod"
+)
+
+// defaultScaleDownHintsTTL is how long the nodes stay marked for node
+// autoscalers when no ttl is configured.
+const defaultScaleDownHintsTTL = time.Hour
+
+// scaleDownMarks converts the hints into the marks set on the nodes.
+func scaleDownMarks(hints []ScaleDownHint) nodeutil.ScaleDownMarks {
+	var marks nodeutil.ScaleDownMarks
+	for _, hint := range hints {
+		switch hint {
+		case ScaleDownHintAnnotate:
+			marks.Annotate = true
+		case ScaleDownHintTaint:
+			marks.Taint = true
+		case ScaleDownHintCordon:
+			marks.Cordon = true
+		}
+	}
+	return marks
+}
+
+// drainedNodes returns the source nodes all the evictable pods were evicted
+// from, according to the summary. nodes no pod was evicted from are not
+// returned, they were not emptied by the plugin.
+func drainedNodes(sourceNodes []NodeInfo, podFilter func(pod *v1.Pod) bool, summary *balanceSummary) []*v1.Node {
+	var drained []*v1.Node
+	for _, node := range sourceNodes {
+		evicted := summary.evictedPerNode[node.node.Name]
+		if evicted == 0 {
+			continue
+		}
+		if _, removable := classifyPods(node.allPods, podFilter); evicted < len(removable) {
+			continue
+		}
+		drained = append(drained, node.node)
+	}
+	return drained
+}
+
+// markDrainedNodes marks the source nodes all the evictable pods were evicted
+// from as candidates for scale down, as requested by the hints. marks are
+// best effort, failures are logged and do not interrupt the process.
+func markDrainedNodes(
+	ctx context.Context,
+	client clientset.Interface,
+	plugin string,
+	hints []ScaleDownHint,
+	sourceNodes []NodeInfo,
+	podFilter func(pod *v1.Pod) bool,
+	summary *balanceSummary,
+) {
+	if len(hints) == 0 {
+		return
+	}
+
+	marks := scaleDownMarks(hints)
+	for _, node := range drainedNodes(sourceNodes, podFilter, summary) {
+		if err := nodeutil.MarkNodeForScaleDown(ctx, client, node.Name, plugin, marks); err != nil {
+			klog.ErrorS(
+				err, "unable to mark the node as a candidate for scale down",
+				"node", klog.KObj(node),
+			)
+			continue
+		}
+		klog.V(1).InfoS("Node marked as a candidate for scale down", "node", klog.KObj(node), "hints", hints)
+	}
+}
+
+// clearScaleDownMarks removes the marks the plugin set on the nodes that
+// were not scaled down: the nodes marked for longer than the ttl and the
+// nodes hosting evictable pods created since they were marked. it returns
+// the nodes as updated, nodes whose marks could not be removed are returned
+// as provided. failures are logged and do not interrupt the process.
+func clearScaleDownMarks(
+	ctx context.Context,
+	client clientset.Interface,
+	plugin string,
+	ttl metav1.Duration,
+	nodes []*v1.Node,
+	nodeIndexer podutil.GetPodsAssignedToNodeFunc,
+	podFilter func(pod *v1.Pod) bool,
+) []*v1.Node {
+	expiration := ttl.Duration
+	if expiration == 0 {
+		expiration = defaultScaleDownHintsTTL
+	}
+
+	result := make([]*v1.Node, 0, len(nodes))
+	for _, node := range nodes {
+		record := nodeutil.ScaleDownRecordOf(node)
+		if record == nil || record.Value != plugin {
+			result = append(result, node)
+			continue
+		}
+
+		reason := "expired"
+		if time.Since(record.MarkedAt.Time) < expiration {
+			pods, err := podutil.ListPodsOnANode(node.Name, nodeIndexer, func(pod *v1.Pod) bool {
+				return record.MarkedAt.Before(&pod.CreationTimestamp) && podFilter(pod)
+			})
+			if err != nil || len(pods) == 0 {
+				result = append(result, node)
+				continue
+			}
+			reason = "hosts evictable pods again"
+		}
+
+		updated, err := nodeutil.UnmarkNodeForScaleDown(ctx, client, node)
+		if err != nil {
+			klog.ErrorS(err, "unable to remove the scale down marks of the node", "node", klog.KObj(node))
+			result = append(result, node)
+			continue
+		}
+		klog.V(1).InfoS("Scale down marks removed from the node", "node", klog.KObj(node), "reason", reason)
+		result = append(result, updated)
+	}
+	return result
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/descheduler/pkg/api"
+	nodeutil "sigs.k8s.io/descheduler/pkg/descheduler/node"
+	podutil "sigs.k8s.io/descheduler/pkg/descheduler/pod"
+	"sigs.k8s.io/descheduler/pkg/framework/plugins/defaultevictor"
+	frameworktesting "sigs.k8s.io/descheduler/pkg/framework/testing"
+	frameworktypes "sigs.k8s.io/descheduler/pkg/framework/types"
+	"sigs.k8s.io/descheduler/test"
+)
+
+func TestDrainedNodes(t *testing.T) {
+	n1 := test.BuildTestNode("n1", 4000, 3000, 10, nil)
+	n2 := test.BuildTestNode("n2", 4000, 3000, 10, nil)
+	n3 := test.BuildTestNode("n3", 4000, 3000, 10, nil)
+
+	evictable := func(pod *v1.Pod) bool { return len(pod.OwnerReferences) > 0 }
+	sourceNodes := []NodeInfo{
+		{NodeUsage: NodeUsage{node: n1, allPods: []*v1.Pod{
+			test.BuildTestPod("p1", 100, 0, n1.Name, test.SetRSOwnerRef),
+			test.BuildTestPod("p2", 100, 0, n1.Name, nil),
+		}}},
+		{NodeUsage: NodeUsage{node: n2, allPods: []*v1.Pod{
+			test.BuildTestPod("p3", 100, 0, n2.Name, test.SetRSOwnerRef),
+			test.BuildTestPod("p4", 100, 0, n2.Name, test.SetRSOwnerRef),
+		}}},
+		{NodeUsage: NodeUsage{node: n3, allPods: []*v1.Pod{
+			test.BuildTestPod("p5", 100, 0, n3.Name, test.SetRSOwnerRef),
+		}}},
+	}
+
+	// all the evictable pods of n1 were evicted, n2 kept one of them and
+	// no pod was evicted from n3.
+	summary := newBalanceSummary("test")
+	summary.podEvicted(n1.Name)
+	summary.podEvicted(n2.Name)
+
+	drained := drainedNodes(sourceNodes, evictable, summary)
+	if len(drained) != 1 || drained[0].Name != n1.Name {
+		t.Errorf("Expected only n1 to be drained, got %v", drained)
+	}
+}
+
+func TestNodeConsolidationScaleDownHints(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	n1 := test.BuildTestNode("n1", 4000, 3000, 10, nil)
+	n2 := test.BuildTestNode("n2", 4000, 3000, 10, nil)
+	nodes := []*v1.Node{n1, n2}
+
+	objs := []runtime.Object{
+		n1,
+		n2,
+		test.BuildTestPod("p1", 1000, 0, n1.Name, test.SetRSOwnerRef),
+		test.BuildTestPod("p2", 500, 0, n2.Name, test.SetRSOwnerRef),
+	}
+	client := fake.NewSimpleClientset(objs...)
+
+	handle, podEvictor, err := frameworktesting.InitFrameworkHandle(ctx, client, nil, defaultevictor.DefaultEvictorArgs{}, nil)
+	if err != nil {
+		t.Fatalf("Unable to initialize a framework handle: %v", err)
+	}
+
+	plugin, err := NewNodeConsolidation(&NodeConsolidationArgs{
+		TargetThresholds: api.ResourceThresholds{v1.ResourceCPU: 80},
+		MaxNodesToDrain:  1,
+		ScaleDownHints:   []ScaleDownHint{ScaleDownHintAnnotate, ScaleDownHintTaint},
+	}, handle)
+	if err != nil {
+		t.Fatalf("Unable to initialize the plugin: %v", err)
+	}
+
+	status := plugin.(frameworktypes.BalancePlugin).Balance(ctx, nodes)
+	if status != nil && status.Err != nil {
+		t.Fatalf("Unexpected error: %v", status.Err)
+	}
+	if evicted := podEvictor.TotalEvicted(); evicted != 1 {
+		t.Fatalf("Expected a single pod to be evicted, got %v", evicted)
+	}
+
+	for _, tc := range []struct {
+		node   string
+		marked bool
+	}{
+		{node: n1.Name, marked: false},
+		{node: n2.Name, marked: true},
+	} {
+		node, err := client.CoreV1().Nodes().Get(ctx, tc.node, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Unable to get node %v: %v", tc.node, err)
+		}
+		annotated := node.Annotations[nodeutil.CandidateForScaleDownKey] == NodeConsolidationPluginName
+		tainted := nodeutil.HasTaint(node, nodeutil.CandidateForScaleDownKey)
+		if annotated != tc.marked || tainted != tc.marked {
+			t.Errorf("Expected node %v to be marked to be %v, got annotated %v and tainted %v", tc.node, tc.marked, annotated, tainted)
+		}
+		if node.Spec.Unschedulable {
+			t.Errorf("Expected node %v not to be cordoned", tc.node)
+		}
+	}
+}
+
+func TestClearScaleDownMarks(t *testing.T) {
+	ctx := context.Background()
+
+	now := time.Now()
+	marked := func(name, plugin string, at time.Time) *v1.Node {
+		return test.BuildTestNode(name, 4000, 3000, 10, func(node *v1.Node) {
+			record, _ := json.Marshal(nodeutil.ScaleDownRecord{
+				ScaleDownMarks: nodeutil.ScaleDownMarks{Cordon: true},
+				Value:          plugin,
+				MarkedAt:       metav1.NewTime(at),
+			})
+			node.Annotations = map[string]string{nodeutil.ScaleDownMarksKey: string(record)}
+			node.Spec.Unschedulable = true
+		})
+	}
+	nodes := []*v1.Node{
+		marked("recent", NodeConsolidationPluginName, now.Add(-time.Minute)),
+		marked("repopulated", NodeConsolidationPluginName, now.Add(-time.Minute)),
+		marked("expired", NodeConsolidationPluginName, now.Add(-2*time.Hour)),
+		marked("other-plugin", HighNodeUtilizationPluginName, now.Add(-2*time.Hour)),
+	}
+	objs := []runtime.Object{}
+	for _, node := range nodes {
+		objs = append(objs, node)
+	}
+
+	// pods created before the mark were evicted and are still terminating.
+	pods := []*v1.Pod{
+		test.BuildTestPod("terminating", 100, 0, "recent", func(pod *v1.Pod) {
+			test.SetRSOwnerRef(pod)
+			pod.CreationTimestamp = metav1.NewTime(now.Add(-time.Hour))
+		}),
+		test.BuildTestPod("new", 100, 0, "repopulated", func(pod *v1.Pod) {
+			test.SetRSOwnerRef(pod)
+			pod.CreationTimestamp = metav1.NewTime(now)
+		}),
+	}
+	nodeIndexer := func(node string, filter podutil.FilterFunc) ([]*v1.Pod, error) {
+		var result []*v1.Pod
+		for _, pod := range pods {
+			if pod.Spec.NodeName == node && (filter == nil || filter(pod)) {
+				result = append(result, pod)
+			}
+		}
+		return result, nil
+	}
+	evictable := func(pod *v1.Pod) bool { return len(pod.OwnerReferences) > 0 }
+
+	client := fake.NewSimpleClientset(objs...)
+	updated := clearScaleDownMarks(ctx, client, NodeConsolidationPluginName, metav1.Duration{}, nodes, nodeIndexer, evictable)
+
+	for i, expected := range []bool{true, false, false, true} {
+		node, err := client.CoreV1().Nodes().Get(ctx, nodes[i].Name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if stillMarked := nodeutil.ScaleDownRecordOf(node) != nil; stillMarked != expected {
+			t.Errorf("Expected node %v to be marked to be %v, got %v", node.Name, expected, stillMarked)
+		}
+		if node.Spec.Unschedulable != expected || updated[i].Spec.Unschedulable != expected {
+			t.Errorf("Expected node %v to be cordoned to be %v, got %v", node.Name, expected, node.Spec.Unschedulable)
+		}
+	}
+}
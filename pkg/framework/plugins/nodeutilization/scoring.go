@@ -0,0 +1,330 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/descheduler/pkg/api"
+	nodeutil "sigs.k8s.io/descheduler/pkg/descheduler/node"
+	"sigs.k8s.io/descheduler/pkg/utils"
+)
+
+// maxNodeScore is the highest score a node can get, it matches the maximum
+// score used by the scheduler.
+const maxNodeScore = 100
+
+// defaultScoringResources are the resources, and their weights, used when
+// the scoring strategy does not specify any. same as the scheduler.
+var defaultScoringResources = []ResourceSpec{
+	{Name: string(v1.ResourceCPU), Weight: 1},
+	{Name: string(v1.ResourceMemory), Weight: 1},
+}
+
+// resourceScorer scores a resource of a node given the amount requested
+// and the node capacity for the resource.
+type resourceScorer func(requested, capacity int64) int64
+
+// leastAllocatedScore favors nodes with fewer requested resources.
+func leastAllocatedScore(requested, capacity int64) int64 {
+	if capacity == 0 || requested > capacity {
+		return 0
+	}
+	return (capacity - requested) * maxNodeScore / capacity
+}
+
+// mostAllocatedScore favors nodes with more requested resources.
+func mostAllocatedScore(requested, capacity int64) int64 {
+	if capacity == 0 {
+		return 0
+	}
+	if requested > capacity {
+		requested = capacity
+	}
+	return requested * maxNodeScore / capacity
+}
+
+// zoneWorkload identifies the pods of a workload running in a zone.
+type zoneWorkload struct {
+	zone  string
+	owner types.UID
+}
+
+// destinationRanker keeps track of where the evicted pods are expected to
+// be scheduled. for every pod it picks the destination node the scheduler
+// would score the highest, among the ones the pod fits on, and accounts for
+// the pod usage in that node.
+type destinationRanker struct {
+	scorer        resourceScorer
+	weights       []ResourceSpec
+	resourceNames []v1.ResourceName
+	destinations  []NodeInfo
+	images        *nodeutil.ImagePlatforms
+	placements    []podPlacement
+	// zonePods counts the pods of every workload in every zone of the
+	// destination nodes, evicted pods included. it is only kept when
+	// spreading the pods by zone.
+	zonePods map[zoneWorkload]int
+}
+
+// podPlacement is an evicted pod and the node it is expected to be
+// scheduled on.
+type podPlacement struct {
+	pod  *v1.Pod
+	node string
+}
+
+// newDestinationRanker returns a ranker for the provided destination nodes.
+// nil is returned if no scoring strategy has been configured. the usage of
+// the destination nodes is copied so the provided nodes are not modified.
+// images tell what destination nodes the pods can run on.
+func newDestinationRanker(
+	strategy *ScoringStrategy,
+	destinations []NodeInfo,
+	resourceNames []v1.ResourceName,
+	images *nodeutil.ImagePlatforms,
+) *destinationRanker {
+	if strategy == nil {
+		return nil
+	}
+
+	scorer := leastAllocatedScore
+	if strategy.Type == MostAllocated {
+		scorer = mostAllocatedScore
+	}
+
+	var zonePods map[zoneWorkload]int
+	if strategy.Type == SpreadByZone {
+		zonePods = map[zoneWorkload]int{}
+		for _, destination := range destinations {
+			for _, pod := range destination.allPods {
+				if key, ok := podZoneWorkload(pod, destination.node); ok {
+					zonePods[key]++
+				}
+			}
+		}
+	}
+
+	weights := strategy.Resources
+	if len(weights) == 0 {
+		weights = defaultScoringResources
+	}
+
+	copies := make([]NodeInfo, len(destinations))
+	for i, destination := range destinations {
+		copies[i] = destination
+		copies[i].usage = copyUsage(destination.usage)
+	}
+
+	return &destinationRanker{
+		scorer:        scorer,
+		weights:       weights,
+		resourceNames: resourceNames,
+		destinations:  copies,
+		images:        images,
+		zonePods:      zonePods,
+	}
+}
+
+// podZoneWorkload returns the key counting the pod among the pods of its
+// workload in the zone of the node. pods without a controller do not belong
+// to any workload.
+func podZoneWorkload(pod *v1.Pod, node *v1.Node) (zoneWorkload, bool) {
+	owner := metav1.GetControllerOfNoCopy(pod)
+	if owner == nil {
+		return zoneWorkload{}, false
+	}
+	return zoneWorkload{zone: node.Labels[v1.LabelTopologyZone], owner: owner.UID}, true
+}
+
+// zoneSkew returns the number of pods of the workload of the pod already in
+// the zone of the destination node, zero unless spreading the pods by zone.
+func (r *destinationRanker) zoneSkew(pod *v1.Pod, destination *NodeInfo) int {
+	if r.zonePods == nil {
+		return 0
+	}
+	key, ok := podZoneWorkload(pod, destination.node)
+	if !ok {
+		return 0
+	}
+	return r.zonePods[key]
+}
+
+// pick returns the destination node the pod, running on a node of the source
+// platform, is expected to land on. nil is returned if the pod does not fit
+// in any of the destination nodes. when spreading the pods by zone the nodes
+// in the zones running the fewest pods of the workload of the pod come first,
+// their score only breaks the ties. nodes scoring the same are told apart by
+// their cost, the cheapest is filled first.
+func (r *destinationRanker) pick(pod *v1.Pod, podUsage api.ReferencedResourceList, source nodeutil.Platform) *NodeInfo {
+	var best *NodeInfo
+	var bestScore int64
+	var bestSkew int
+	for i := range r.destinations {
+		destination := &r.destinations[i]
+		if !r.images.PodRunsOn(pod, source, nodeutil.NodePlatform(destination.node)) {
+			continue
+		}
+		if !r.fits(pod, podUsage, destination) {
+			continue
+		}
+		score := r.score(podUsage, destination)
+		skew := r.zoneSkew(pod, destination)
+		if best == nil || skew < bestSkew || (skew == bestSkew && score > bestScore) ||
+			(skew == bestSkew && score == bestScore && destination.cost < best.cost) {
+			best, bestScore, bestSkew = destination, score, skew
+		}
+	}
+	return best
+}
+
+// assign accounts for the pod usage in the provided destination node.
+func (r *destinationRanker) assign(pod *v1.Pod, destination *NodeInfo, podUsage api.ReferencedResourceList) {
+	for _, name := range r.resourceNames {
+		if podUsage[name] == nil || destination.usage[name] == nil {
+			continue
+		}
+		destination.usage[name].Add(*podUsage[name])
+	}
+	r.placements = append(r.placements, podPlacement{pod: pod, node: destination.node.Name})
+	if r.zonePods != nil {
+		if key, ok := podZoneWorkload(pod, destination.node); ok {
+			r.zonePods[key]++
+		}
+	}
+	klog.V(3).InfoS(
+		"Pod expected to be scheduled on node",
+		"pod", klog.KObj(pod),
+		"node", klog.KObj(destination.node),
+	)
+}
+
+// fits tells if the pod can be scheduled on the destination node without
+// taking the node above its threshold.
+func (r *destinationRanker) fits(pod *v1.Pod, podUsage api.ReferencedResourceList, destination *NodeInfo) bool {
+	if !utils.TolerationsTolerateTaintsWithFilter(pod.Spec.Tolerations, destination.node.Spec.Taints, nil) {
+		return false
+	}
+	if ok, err := utils.PodMatchNodeSelector(pod, destination.node); err != nil || !ok {
+		return false
+	}
+	for _, name := range r.resourceNames {
+		if podUsage[name] == nil {
+			continue
+		}
+		if destination.usage[name] == nil || destination.available[name] == nil {
+			return false
+		}
+		requested := destination.usage[name].DeepCopy()
+		requested.Add(*podUsage[name])
+		if requested.Cmp(*destination.available[name]) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// score returns the score of the destination node once the pod is placed on
+// it. the score is the weighted average of the score of each resource, the
+// node allocatable is used as the capacity. resources the node does not
+// have are ignored, as done by the scheduler.
+func (r *destinationRanker) score(podUsage api.ReferencedResourceList, destination *NodeInfo) int64 {
+	var score, weightSum int64
+	for _, spec := range r.weights {
+		name := v1.ResourceName(spec.Name)
+		allocatable, ok := destination.node.Status.Allocatable[name]
+		if !ok {
+			continue
+		}
+
+		requested := resource.Quantity{}
+		if destination.usage[name] != nil {
+			requested.Add(*destination.usage[name])
+		}
+		if podUsage[name] != nil {
+			requested.Add(*podUsage[name])
+		}
+
+		capacity := allocatable.Value()
+		value := requested.Value()
+		if name == v1.ResourceCPU {
+			capacity = allocatable.MilliValue()
+			value = requested.MilliValue()
+		}
+		if capacity == 0 {
+			continue
+		}
+
+		score += r.scorer(value, capacity) * spec.Weight
+		weightSum += spec.Weight
+	}
+	if weightSum == 0 {
+		return 0
+	}
+	return score / weightSum
+}
+
+// evictedPlacements returns the evicted pods and the nodes they are expected
+// to be scheduled on. it is safe to call on a nil ranker.
+func (r *destinationRanker) evictedPlacements() []podPlacement {
+	if r == nil {
+		return nil
+	}
+	return r.placements
+}
+
+// headroom returns, for every destination node, the resources it can still
+// accept before reaching its threshold once the evicted pods are placed on
+// it, and the number of evicted pods expected on it. it is safe to call on a
+// nil ranker.
+func (r *destinationRanker) headroom() map[string]destinationHeadroom {
+	if r == nil {
+		return nil
+	}
+	placed := map[string]int{}
+	for _, placement := range r.placements {
+		placed[placement.node]++
+	}
+	result := make(map[string]destinationHeadroom, len(r.destinations))
+	for _, destination := range r.destinations {
+		available := api.ReferencedResourceList{}
+		for _, name := range r.resourceNames {
+			if destination.available[name] == nil || destination.usage[name] == nil {
+				continue
+			}
+			quantity := destination.available[name].DeepCopy()
+			quantity.Sub(*destination.usage[name])
+			if quantity.Sign() < 0 {
+				quantity.Set(0)
+			}
+			available[name] = &quantity
+		}
+		result[destination.node.Name] = destinationHeadroom{available: available, pods: placed[destination.node.Name]}
+	}
+	return result
+}
+
+// destinationHeadroom is what is left of a destination node once the evicted
+// pods expected on it are placed.
+type destinationHeadroom struct {
+	available api.ReferencedResourceList
+	pods      int
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"fmt"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/descheduler/pkg/api"
+	nodeutil "sigs.k8s.io/descheduler/pkg/descheduler/node"
+	"sigs.k8s.io/descheduler/test"
+)
+
+func TestResourceScorers(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		scorer    resourceScorer
+		requested int64
+		capacity  int64
+		expected  int64
+	}{
+		{"least allocated empty node", leastAllocatedScore, 0, 1000, 100},
+		{"least allocated half used node", leastAllocatedScore, 500, 1000, 50},
+		{"least allocated overcommitted node", leastAllocatedScore, 1500, 1000, 0},
+		{"least allocated without capacity", leastAllocatedScore, 0, 0, 0},
+		{"most allocated empty node", mostAllocatedScore, 0, 1000, 0},
+		{"most allocated half used node", mostAllocatedScore, 500, 1000, 50},
+		{"most allocated overcommitted node", mostAllocatedScore, 1500, 1000, 100},
+		{"most allocated without capacity", mostAllocatedScore, 0, 0, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if score := tc.scorer(tc.requested, tc.capacity); score != tc.expected {
+				t.Errorf("expected score %d, got %d", tc.expected, score)
+			}
+		})
+	}
+}
+
+func TestDestinationRanker(t *testing.T) {
+	resourceNames := []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory}
+	destination := func(name string, cpuUsage int64) NodeInfo {
+		return NodeInfo{
+			NodeUsage: NodeUsage{
+				node: test.BuildTestNode(name, 2000, 2000, 10, nil),
+				usage: api.ReferencedResourceList{
+					v1.ResourceCPU:    resource.NewMilliQuantity(cpuUsage, resource.DecimalSI),
+					v1.ResourceMemory: resource.NewQuantity(0, resource.BinarySI),
+				},
+			},
+			available: api.ReferencedResourceList{
+				v1.ResourceCPU:    resource.NewMilliQuantity(1600, resource.DecimalSI),
+				v1.ResourceMemory: resource.NewQuantity(1600, resource.BinarySI),
+			},
+		}
+	}
+	podUsage := api.ReferencedResourceList{
+		v1.ResourceCPU:    resource.NewMilliQuantity(400, resource.DecimalSI),
+		v1.ResourceMemory: resource.NewQuantity(0, resource.BinarySI),
+	}
+	pod := test.BuildTestPod("p1", 400, 0, "source", nil)
+
+	for _, tc := range []struct {
+		name     string
+		strategy *ScoringStrategy
+		expected []string
+	}{
+		{
+			name:     "least allocated spreads the pods",
+			strategy: &ScoringStrategy{Type: LeastAllocated},
+			expected: []string{"n2", "n2", "n2", "n1", "n2", ""},
+		},
+		{
+			name:     "most allocated packs the pods",
+			strategy: &ScoringStrategy{Type: MostAllocated},
+			expected: []string{"n1", "n2", "n2", "n2", "n2", ""},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			destinations := []NodeInfo{destination("n1", 1200), destination("n2", 0)}
+			ranker := newDestinationRanker(tc.strategy, destinations, resourceNames, nil)
+
+			for i, expected := range tc.expected {
+				picked := ranker.pick(pod, podUsage, nodeutil.Platform{})
+				if picked == nil {
+					if expected != "" {
+						t.Fatalf("pod %d: expected node %s, got none", i, expected)
+					}
+					continue
+				}
+				if picked.node.Name != expected {
+					t.Fatalf("pod %d: expected node %q, got %q", i, expected, picked.node.Name)
+				}
+				ranker.assign(pod, picked, podUsage)
+			}
+
+			// the usage of the provided nodes must not be modified.
+			if destinations[1].usage[v1.ResourceCPU].MilliValue() != 0 {
+				t.Errorf("expected the destination usage not to be modified")
+			}
+		})
+	}
+
+	if newDestinationRanker(nil, nil, resourceNames, nil) != nil {
+		t.Errorf("expected no ranker without a scoring strategy")
+	}
+}
+
+func TestDestinationRankerSpreadByZone(t *testing.T) {
+	resourceNames := []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory}
+	owner := []metav1.OwnerReference{{Kind: "ReplicaSet", APIVersion: "apps/v1", Name: "rs", UID: "rs", Controller: ptr.To(true)}}
+	running := test.BuildTestPod("running", 400, 0, "n1", nil)
+	running.OwnerReferences = owner
+	destination := func(name, zone string, cpuUsage int64, pods ...*v1.Pod) NodeInfo {
+		node := test.BuildTestNode(name, 2000, 2000, 10, nil)
+		node.Labels[v1.LabelTopologyZone] = zone
+		return NodeInfo{
+			NodeUsage: NodeUsage{
+				node: node,
+				usage: api.ReferencedResourceList{
+					v1.ResourceCPU:    resource.NewMilliQuantity(cpuUsage, resource.DecimalSI),
+					v1.ResourceMemory: resource.NewQuantity(0, resource.BinarySI),
+				},
+				allPods: pods,
+			},
+			available: api.ReferencedResourceList{
+				v1.ResourceCPU:    resource.NewMilliQuantity(1600, resource.DecimalSI),
+				v1.ResourceMemory: resource.NewQuantity(1600, resource.BinarySI),
+			},
+		}
+	}
+	podUsage := api.ReferencedResourceList{
+		v1.ResourceCPU:    resource.NewMilliQuantity(400, resource.DecimalSI),
+		v1.ResourceMemory: resource.NewQuantity(0, resource.BinarySI),
+	}
+
+	destinations := []NodeInfo{
+		destination("n1", "zone-a", 400, running),
+		destination("n2", "zone-a", 0),
+		destination("n3", "zone-b", 800),
+	}
+	ranker := newDestinationRanker(&ScoringStrategy{Type: SpreadByZone}, destinations, resourceNames, nil)
+
+	// zone-a already runs a pod of the workload, the first pod goes to
+	// zone-b even though its node is the most allocated one. the last pod
+	// no longer fits in zone-b.
+	for i, expected := range []string{"n3", "n2", "n3", "n1"} {
+		pod := test.BuildTestPod(fmt.Sprintf("p%d", i), 400, 0, "source", nil)
+		pod.OwnerReferences = owner
+		picked := ranker.pick(pod, podUsage, nodeutil.Platform{})
+		if picked == nil {
+			t.Fatalf("pod %d: expected node %s, got none", i, expected)
+		}
+		if picked.node.Name != expected {
+			t.Fatalf("pod %d: expected node %q, got %q", i, expected, picked.node.Name)
+		}
+		ranker.assign(pod, picked, podUsage)
+	}
+
+	headroom := ranker.headroom()
+	for name, expected := range map[string]struct {
+		cpu  int64
+		pods int
+	}{"n1": {800, 1}, "n2": {1200, 1}, "n3": {0, 2}} {
+		if cpu := headroom[name].available[v1.ResourceCPU].MilliValue(); cpu != expected.cpu {
+			t.Errorf("expected %q cpu headroom to be %vm, got %vm instead", name, expected.cpu, cpu)
+		}
+		if pods := headroom[name].pods; pods != expected.pods {
+			t.Errorf("expected %v pods placed on %q, got %v instead", expected.pods, name, pods)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"maps"
+	"slices"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"sigs.k8s.io/descheduler/pkg/api"
+)
+
+// thresholdsWithQuantities returns the thresholds completed with the
+// resources whose threshold is a quantity. their percentage is unresolved
+// until it is computed from the capacity of every node, the one provided is
+// used meanwhile.
+func thresholdsWithQuantities(
+	thresholds api.ResourceThresholds, quantities v1.ResourceList, unresolved api.Percentage,
+) api.ResourceThresholds {
+	if len(quantities) == 0 {
+		return thresholds
+	}
+	result := api.ResourceThresholds{}
+	maps.Copy(result, thresholds)
+	for name := range quantities {
+		if _, ok := result[name]; !ok {
+			result[name] = unresolved
+		}
+	}
+	return result
+}
+
+// resourceThreshold converts a threshold expressed as the quantity of the
+// resource left free on the node into a percentage of the node capacity.
+// false is returned if the capacity of the node is unknown.
+func resourceThreshold(capacity *resource.Quantity, free resource.Quantity) (api.Percentage, bool) {
+	if capacity == nil || capacity.Sign() <= 0 {
+		return 0, false
+	}
+	pct := 100 * (1 - free.AsApproximateFloat64()/capacity.AsApproximateFloat64())
+	return api.Percentage(min(max(pct, MinResourcePercentage), MaxResourcePercentage)), true
+}
+
+// resolveThresholdQuantities sets, for every node, the thresholds of the
+// resources whose thresholds are quantities to the percentage of the node
+// capacity they amount to. nodes whose capacity is unknown keep the
+// unresolved thresholds, they are never underutilized nor overutilized for
+// the resource.
+func resolveThresholdQuantities(
+	thresholds map[string][]api.ResourceThresholds,
+	capacities map[string]api.ReferencedResourceList,
+	low, high v1.ResourceList,
+) {
+	if len(low) == 0 && len(high) == 0 {
+		return
+	}
+	for name, nodeThresholds := range thresholds {
+		// thresholds may be shared among nodes, they are copied
+		// before being resolved for this node.
+		resolved := make([]api.ResourceThresholds, len(nodeThresholds))
+		for i := range nodeThresholds {
+			resolved[i] = maps.Clone(nodeThresholds[i])
+		}
+		for i, quantities := range []v1.ResourceList{low, high} {
+			for resourceName, free := range quantities {
+				if pct, ok := resourceThreshold(capacities[name][resourceName], free); ok {
+					resolved[i][resourceName] = pct
+				}
+			}
+		}
+		thresholds[name] = resolved
+	}
+}
+
+// quantitiesToKeysAndValues converts the threshold quantities into a list of
+// keys and values, the keys are suffixed to tell them from percentages.
+func quantitiesToKeysAndValues(quantities v1.ResourceList) []any {
+	result := []any{}
+	for _, name := range slices.Sorted(maps.Keys(quantities)) {
+		quantity := quantities[name]
+		result = append(result, string(name)+"Free", quantity.String())
+	}
+	return result
+}
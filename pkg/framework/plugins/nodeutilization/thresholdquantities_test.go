@@ -0,0 +1,154 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sort"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/descheduler/pkg/api"
+	"sigs.k8s.io/descheduler/pkg/framework/plugins/defaultevictor"
+	"sigs.k8s.io/descheduler/pkg/framework/plugins/nodeutilization/normalizer"
+	frameworktesting "sigs.k8s.io/descheduler/pkg/framework/testing"
+	frameworktypes "sigs.k8s.io/descheduler/pkg/framework/types"
+	"sigs.k8s.io/descheduler/test"
+)
+
+func TestResolveThresholdQuantities(t *testing.T) {
+	low := api.ResourceThresholds{v1.ResourceCPU: 20, v1.ResourceMemory: MinResourcePercentage}
+	high := api.ResourceThresholds{v1.ResourceCPU: 50, v1.ResourceMemory: MaxResourcePercentage}
+	thresholds := normalizer.Replicate([]string{"small", "large", "unknown"}, []api.ResourceThresholds{low, high})
+	capacities := map[string]api.ReferencedResourceList{
+		"small": {v1.ResourceMemory: ptr.To(resource.MustParse("8Gi"))},
+		"large": {v1.ResourceMemory: ptr.To(resource.MustParse("64Gi"))},
+	}
+
+	resolveThresholdQuantities(
+		thresholds,
+		capacities,
+		v1.ResourceList{v1.ResourceMemory: resource.MustParse("4Gi")},
+		v1.ResourceList{v1.ResourceMemory: resource.MustParse("2Gi")},
+	)
+
+	expected := map[string][]api.ResourceThresholds{
+		"small": {
+			{v1.ResourceCPU: 20, v1.ResourceMemory: 50},
+			{v1.ResourceCPU: 50, v1.ResourceMemory: 75},
+		},
+		"large": {
+			{v1.ResourceCPU: 20, v1.ResourceMemory: 93.75},
+			{v1.ResourceCPU: 50, v1.ResourceMemory: 96.875},
+		},
+		"unknown": {low, high},
+	}
+	for name, nodeThresholds := range expected {
+		for i := range nodeThresholds {
+			if !maps.Equal(thresholds[name][i], nodeThresholds[i]) {
+				t.Errorf("Expected thresholds %v for node %v, got %v", nodeThresholds[i], name, thresholds[name][i])
+			}
+		}
+	}
+}
+
+func TestLowNodeUtilizationThresholdQuantities(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		args     *LowNodeUtilizationArgs
+		expected map[string]int
+	}{
+		{
+			name: "percentages",
+			args: &LowNodeUtilizationArgs{
+				Thresholds:       api.ResourceThresholds{v1.ResourceMemory: 30},
+				TargetThresholds: api.ResourceThresholds{v1.ResourceMemory: 50},
+			},
+			expected: map[string]int{"n1": 2, "n2": 1},
+		},
+		{
+			name: "quantities",
+			args: &LowNodeUtilizationArgs{
+				ThresholdQuantities:       v1.ResourceList{v1.ResourceMemory: resource.MustParse("32Gi")},
+				TargetThresholdQuantities: v1.ResourceList{v1.ResourceMemory: resource.MustParse("3Gi")},
+			},
+			expected: map[string]int{"n1": 1},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			// n1 has 2Gi of its 8Gi free, n2 24Gi of its 64Gi and
+			// n3 is empty.
+			nodes := []*v1.Node{
+				test.BuildTestNode("n1", 4000, 8<<30, 10, nil),
+				test.BuildTestNode("n2", 4000, 64<<30, 10, nil),
+				test.BuildTestNode("n3", 4000, 64<<30, 10, nil),
+			}
+			objs := []runtime.Object{nodes[0], nodes[1], nodes[2]}
+			for i := 0; i < 4; i++ {
+				objs = append(
+					objs,
+					test.BuildTestPod(fmt.Sprintf("n1-p%d", i), 100, 3<<29, "n1", test.SetRSOwnerRef),
+					test.BuildTestPod(fmt.Sprintf("n2-p%d", i), 100, 10<<30, "n2", test.SetRSOwnerRef),
+				)
+			}
+
+			handle, _, err := frameworktesting.InitFrameworkHandle(
+				ctx,
+				fake.NewSimpleClientset(objs...),
+				nil,
+				defaultevictor.DefaultEvictorArgs{},
+				func(pods []*v1.Pod) {
+					sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+				},
+			)
+			if err != nil {
+				t.Fatalf("Unable to initialize a framework handle: %v", err)
+			}
+
+			if err := ValidateLowNodeUtilizationArgs(tc.args); err != nil {
+				t.Fatalf("Unexpected validation error: %v", err)
+			}
+			plugin, err := NewLowNodeUtilization(tc.args, handle)
+			if err != nil {
+				t.Fatalf("Unable to initialize the plugin: %v", err)
+			}
+
+			var summary *balanceSummary
+			summaryObserver = func(s *balanceSummary) { summary = s }
+			defer func() { summaryObserver = nil }()
+
+			if status := plugin.(frameworktypes.BalancePlugin).Balance(ctx, nodes); status != nil && status.Err != nil {
+				t.Fatalf("Unexpected error: %v", status.Err)
+			}
+			if summary == nil {
+				t.Fatalf("No balance summary observed")
+			}
+			if !maps.Equal(summary.evictedPerNode, tc.expected) {
+				t.Errorf("Expected pods to be evicted from %v, got %v", tc.expected, summary.evictedPerNode)
+			}
+		})
+	}
+}
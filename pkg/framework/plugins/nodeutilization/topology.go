@@ -0,0 +1,51 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// nodesInTopologyDomains returns the nodes labeled with the topology key.
+// nodes without the label belong to no domain and are left alone, there is
+// no telling which nodes their pods could be moved to.
+func nodesInTopologyDomains(nodes []*v1.Node, topologyKey string) []*v1.Node {
+	result := make([]*v1.Node, 0, len(nodes))
+	for _, node := range nodes {
+		if _, ok := node.Labels[topologyKey]; !ok {
+			klog.V(2).InfoS(
+				"Node has no topology domain, thus not balanced",
+				"node", klog.KObj(node),
+				"topologyKey", topologyKey,
+			)
+			continue
+		}
+		result = append(result, node)
+	}
+	return result
+}
+
+// nodeInfosByTopologyDomain groups the nodes by their topology domain.
+func nodeInfosByTopologyDomain(nodes []NodeInfo, topologyKey string) map[string][]NodeInfo {
+	domains := map[string][]NodeInfo{}
+	for _, node := range nodes {
+		domain := node.node.Labels[topologyKey]
+		domains[domain] = append(domains[domain], node)
+	}
+	return domains
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/descheduler/pkg/api"
+	"sigs.k8s.io/descheduler/pkg/framework/plugins/defaultevictor"
+	frameworktesting "sigs.k8s.io/descheduler/pkg/framework/testing"
+	frameworktypes "sigs.k8s.io/descheduler/pkg/framework/types"
+	"sigs.k8s.io/descheduler/test"
+)
+
+const testTopologyKey = "topology.kubernetes.io/zone"
+
+func withZone(zone string) func(*v1.Node) {
+	return func(node *v1.Node) {
+		node.Labels[testTopologyKey] = zone
+	}
+}
+
+func TestLowNodeUtilizationTopologyKey(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		topologyKey string
+		expected    map[string]uint
+	}{
+		{
+			name:     "pods move across zones",
+			expected: map[string]uint{"n1": 2, "n3": 2},
+		},
+		{
+			name:        "pods move within their zone",
+			topologyKey: testTopologyKey,
+			expected:    map[string]uint{"n1": 2},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			// n1 is overutilized and n2 and n4 are underutilized in
+			// zone a, n3 is the only node of zone b. n5 belongs to
+			// no zone.
+			nodes := []*v1.Node{
+				test.BuildTestNode("n1", 4000, 3000, 10, withZone("a")),
+				test.BuildTestNode("n2", 4000, 3000, 10, withZone("a")),
+				test.BuildTestNode("n3", 4000, 3000, 10, withZone("b")),
+				test.BuildTestNode("n4", 4000, 3000, 10, withZone("a")),
+				test.BuildTestNode("n5", 4000, 3000, 10, nil),
+			}
+			objs := []runtime.Object{}
+			for _, node := range nodes {
+				objs = append(objs, node)
+			}
+			for _, node := range []string{"n1", "n3", "n5"} {
+				for i := 0; i < 4; i++ {
+					objs = append(objs, test.BuildTestPod(fmt.Sprintf("%s-p%d", node, i), 800, 0, node, test.SetRSOwnerRef))
+				}
+			}
+			for _, node := range []string{"n2", "n4"} {
+				objs = append(objs, test.BuildTestPod(node+"-p0", 400, 0, node, test.SetRSOwnerRef))
+			}
+
+			handle, podEvictor, err := frameworktesting.InitFrameworkHandle(
+				ctx,
+				fake.NewSimpleClientset(objs...),
+				nil,
+				defaultevictor.DefaultEvictorArgs{},
+				func(pods []*v1.Pod) {
+					sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+				},
+			)
+			if err != nil {
+				t.Fatalf("Unable to initialize a framework handle: %v", err)
+			}
+
+			plugin, err := NewLowNodeUtilization(&LowNodeUtilizationArgs{
+				Thresholds:       api.ResourceThresholds{v1.ResourceCPU: 30},
+				TargetThresholds: api.ResourceThresholds{v1.ResourceCPU: 50},
+				TopologyKey:      tc.topologyKey,
+			}, handle)
+			if err != nil {
+				t.Fatalf("Unable to initialize the plugin: %v", err)
+			}
+
+			if status := plugin.(frameworktypes.BalancePlugin).Balance(ctx, nodes); status != nil && status.Err != nil {
+				t.Fatalf("Unexpected error: %v", status.Err)
+			}
+
+			var total uint
+			for _, node := range nodes {
+				evicted := podEvictor.NodeEvicted(node)
+				if evicted != tc.expected[node.Name] {
+					t.Errorf("Expected %v pods to be evicted from %v, got %v", tc.expected[node.Name], node.Name, evicted)
+				}
+				total += evicted
+			}
+			if total != podEvictor.TotalEvicted() {
+				t.Errorf("Expected %v pods to be evicted, got %v", total, podEvictor.TotalEvicted())
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"sigs.k8s.io/descheduler/pkg/api"
+	"sigs.k8s.io/descheduler/pkg/framework/plugins/nodeutilization/classifier"
+	"sigs.k8s.io/descheduler/pkg/tracing"
+)
+
+// classifyNodes classifies the nodes within a span so the time spent on the
+// classification shows in the traces of the Balance invocation. the span
+// records how many nodes ended up in every group.
+func classifyNodes(
+	ctx context.Context,
+	plugin string,
+	usage map[string]api.ResourceThresholds,
+	thresholds map[string][]api.ResourceThresholds,
+	classifiers ...classifier.Classifier[string, api.ResourceThresholds],
+) []map[string]api.ResourceThresholds {
+	_, span := tracing.Tracer().Start(ctx, "Classify", trace.WithAttributes(
+		attribute.String("plugin", plugin),
+		attribute.Int("nodes", len(usage)),
+	))
+	defer span.End()
+
+	groups := classifier.Classify(usage, thresholds, classifiers...)
+	sizes := make([]int, 0, len(groups))
+	for _, group := range groups {
+		sizes = append(sizes, len(group))
+	}
+	span.SetAttributes(attribute.IntSlice("classifiedNodes", sizes))
+	return groups
+}
+
+// annotateSpan records the outcome of the Balance invocation in the span of
+// the invocation, the one the framework starts for every plugin.
+func (s *balanceSummary) annotateSpan(ctx context.Context) {
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.Int("underutilizedNodes", s.underutilized),
+		attribute.Int("overutilizedNodes", s.overutilized),
+		attribute.Int("evictedPods", s.evicted),
+		attribute.Int("skippedPods", s.skipped),
+	)
+}
+
+// endSpan ends the span, recording the error if any.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -14,6 +14,7 @@ limitations under the License.
 package nodeutilization
 
 import (
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/descheduler/pkg/api"
 )
@@ -42,6 +43,39 @@ type LowNodeUtilizationArgs struct {
 	NumberOfNodes          int                    `json:"numberOfNodes,omitempty"`
 	MetricsUtilization     *MetricsUtilization    `json:"metricsUtilization,omitempty"`
 
+	// ThresholdQuantities and TargetThresholdQuantities express, per
+	// resource, the thresholds as the quantity of the resource left free
+	// on the nodes instead of a percentage of their capacity, e.g. a
+	// node with less than the target quantity of memory free is
+	// overutilized whatever its size. They are converted into a
+	// percentage of the capacity of every node. A resource can not have
+	// both a percentage and a quantity in the same thresholds, and the
+	// quantities can not be combined with deviation nor percentile
+	// thresholds.
+	ThresholdQuantities       v1.ResourceList `json:"thresholdQuantities,omitempty"`
+	TargetThresholdQuantities v1.ResourceList `json:"targetThresholdQuantities,omitempty"`
+
+	// UseStdDeviationThresholds makes the thresholds and the target
+	// thresholds the number of standard deviations of the nodes usage
+	// below and above the average usage, per resource, instead of fixed
+	// percentages.
+	UseStdDeviationThresholds bool `json:"useStdDeviationThresholds,omitempty"`
+
+	// UsePercentileThresholds makes the thresholds and the target
+	// thresholds percentiles of the nodes usage, per resource. Nodes at
+	// or below the first are underutilized, nodes above the second are
+	// overutilized.
+	UsePercentileThresholds bool `json:"usePercentileThresholds,omitempty"`
+
+	// DeviationCenter is the aggregate of the nodes usage the deviation
+	// thresholds are applied around. See DeviationCenter.
+	DeviationCenter DeviationCenter `json:"deviationCenter,omitempty"`
+
+	// DeviationTrimPercentage is the percentage of the nodes with the
+	// lowest usage, and of the ones with the highest usage, left out of
+	// the TrimmedMean deviation center. Defaults to 10.
+	DeviationTrimPercentage api.Percentage `json:"deviationTrimPercentage,omitempty"`
+
 	// Naming this one differently since namespaces are still
 	// considered while considering resources used by pods
 	// but then filtered out before eviction
@@ -49,6 +83,117 @@ type LowNodeUtilizationArgs struct {
 
 	// evictionLimits limits the number of evictions per domain. E.g. node, namespace, total.
 	EvictionLimits *api.EvictionLimits `json:"evictionLimits,omitempty"`
+
+	// ScoringStrategy ranks the destination nodes the same way the
+	// scheduler NodeResourcesFit plugin does. See ScoringStrategy.
+	ScoringStrategy *ScoringStrategy `json:"scoringStrategy,omitempty"`
+
+	// SchedulingHints annotates the owners of the evicted pods with the
+	// nodes they are expected to be scheduled on. Requires ScoringStrategy.
+	SchedulingHints bool `json:"schedulingHints,omitempty"`
+
+	// NodeConditions maps node conditions, e.g. the ones reported by the
+	// Node Problem Detector, to actions. See NodeConditionRule.
+	NodeConditions []NodeConditionRule `json:"nodeConditions,omitempty"`
+
+	// Overcommit scales the capacity of the nodes matching a selector
+	// before their usage is assessed. See OvercommitRule.
+	Overcommit []OvercommitRule `json:"overcommit,omitempty"`
+	// EvictionOrder sequences the evictions across the source nodes,
+	// PerNode (default) or PriorityBands. See EvictionOrder.
+	EvictionOrder EvictionOrder `json:"evictionOrder,omitempty"`
+
+	// DryRun runs the classification and the selection of the pods to
+	// evict without evicting any of them. A report of the classified
+	// nodes, the pods that would be evicted and the projected usage of
+	// their nodes is logged instead.
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// Mode selects what is done with the pods selected for eviction,
+	// Evict (default) or Annotate. See BalanceMode.
+	Mode BalanceMode `json:"mode,omitempty"`
+
+	// DecisionLog records why every pod was evicted. See DecisionLog.
+	DecisionLog *DecisionLog `json:"decisionLog,omitempty"`
+
+	// PodEvictionOrder sorts the pods of every source node, Priority
+	// (default), LargestConsumerFirst, SmallestConsumerFirst, OldestFirst
+	// or NewestFirst. See PodEvictionOrder.
+	PodEvictionOrder PodEvictionOrder `json:"podEvictionOrder,omitempty"`
+
+	// AffinityAwareness skips, or evicts last, the pods whose affinity is
+	// better satisfied on their node than on any destination node. See
+	// AffinityAwareness.
+	AffinityAwareness AffinityAwareness `json:"affinityAwareness,omitempty"`
+
+	// FairnessPolicy spreads the evictions of every source node across
+	// the namespaces of its pods, None (default) or Proportional. See
+	// FairnessPolicy.
+	FairnessPolicy FairnessPolicy `json:"fairnessPolicy,omitempty"`
+
+	// NodeCost tells where the cost of the nodes is read from, nodes are
+	// then drained from the most expensive and filled from the cheapest
+	// when their usage ties. See NodeCost.
+	NodeCost *NodeCost `json:"nodeCost,omitempty"`
+
+	// TopologyKey is the label of the nodes telling their topology domain,
+	// e.g. topology.kubernetes.io/zone. When set the nodes are classified
+	// within their domain, the average used by the deviation thresholds
+	// is computed per domain, and pods are only moved between nodes of
+	// the same domain. Nodes without the label are not balanced.
+	TopologyKey string `json:"topologyKey,omitempty"`
+
+	// ResourceWeights weighs the resources when nodes are classified and
+	// sorted, nodes are then compared through the weighted average of
+	// their usage percentages instead of resource by resource. e.g. cpu: 2
+	// and memory: 1 make the cpu usage count twice as much as the memory
+	// usage. Resources without a weight have a weight of one.
+	ResourceWeights map[v1.ResourceName]float64 `json:"resourceWeights,omitempty"`
+
+	// NodePools overrides the thresholds and the target thresholds of
+	// the nodes matching a node selector. See NodePoolThresholds.
+	NodePools []NodePoolThresholds `json:"nodePools,omitempty"`
+
+	// Cooldown leaves the nodes pods were evicted from alone for a while.
+	// See Cooldown.
+	Cooldown *Cooldown `json:"cooldown,omitempty"`
+
+	// DoNotDisruptHintTTL annotates the nodes pods are moved onto with
+	// karpenter.sh/do-not-disrupt for that long, so karpenter does not
+	// consolidate them right away. Nodes are not annotated when not set.
+	DoNotDisruptHintTTL metav1.Duration `json:"doNotDisruptHintTTL,omitempty"`
+
+	// EvictionRateLimit spreads the evictions over time. See
+	// EvictionRateLimit.
+	EvictionRateLimit *EvictionRateLimit `json:"evictionRateLimit,omitempty"`
+
+	// Hysteresis keeps the nodes classified as underutilized or as
+	// overutilized on the previous cycle in their class until their usage
+	// crosses the thresholds by more than the hysteresis, e.g. with the
+	// cpu target threshold set to 50 and an hysteresis of 10 an
+	// overutilized node stays overutilized until its cpu usage drops to
+	// 40%. Nodes hovering around a threshold then do not flap between
+	// classes across cycles.
+	Hysteresis api.Percentage `json:"hysteresis,omitempty"`
+
+	// ContinueEvictionStrategy selects until when pods are evicted from
+	// the overutilized nodes. See ContinueEvictionStrategy.
+	ContinueEvictionStrategy *ContinueEvictionStrategy `json:"continueEvictionStrategy,omitempty"`
+
+	// InPlaceResize shrinks the requests of the pods of the overutilized
+	// nodes in place before evicting any pod. See InPlaceResize.
+	InPlaceResize *InPlaceResize `json:"inPlaceResize,omitempty"`
+
+	// UsageCacheTTL keeps the node usage collected by the plugin for the
+	// provided duration. Plugins of any profile collecting the same usage
+	// for the same nodes reuse it instead of listing the pods or querying
+	// the metrics again, until pods are evicted. The usage is collected
+	// on every run when unset.
+	UsageCacheTTL metav1.Duration `json:"usageCacheTTL,omitempty"`
+
+	// RunStatus writes the outcome of every Balance invocation to a
+	// ConfigMap. See RunStatus.
+	RunStatus *RunStatus `json:"runStatus,omitempty"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -71,6 +216,505 @@ type HighNodeUtilizationArgs struct {
 	// considered while considering resources used by pods
 	// but then filtered out before eviction
 	EvictableNamespaces *api.Namespaces `json:"evictableNamespaces,omitempty"`
+
+	// evictionLimits limits the number of evictions per domain. E.g. node, namespace, total.
+	EvictionLimits *api.EvictionLimits `json:"evictionLimits,omitempty"`
+
+	// ScoringStrategy ranks the destination nodes the same way the
+	// scheduler NodeResourcesFit plugin does. See ScoringStrategy.
+	ScoringStrategy *ScoringStrategy `json:"scoringStrategy,omitempty"`
+
+	// SchedulingHints annotates the owners of the evicted pods with the
+	// nodes they are expected to be scheduled on. Requires ScoringStrategy.
+	SchedulingHints bool `json:"schedulingHints,omitempty"`
+
+	// NodeConditions maps node conditions, e.g. the ones reported by the
+	// Node Problem Detector, to actions. See NodeConditionRule.
+	NodeConditions []NodeConditionRule `json:"nodeConditions,omitempty"`
+
+	// Overcommit scales the capacity of the nodes matching a selector
+	// before their usage is assessed. See OvercommitRule.
+	Overcommit []OvercommitRule `json:"overcommit,omitempty"`
+	// EvictionOrder sequences the evictions across the source nodes,
+	// PerNode (default) or PriorityBands. See EvictionOrder.
+	EvictionOrder EvictionOrder `json:"evictionOrder,omitempty"`
+
+	// DryRun runs the classification and the selection of the pods to
+	// evict without evicting any of them. A report of the classified
+	// nodes, the pods that would be evicted and the projected usage of
+	// their nodes is logged instead.
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// Mode selects what is done with the pods selected for eviction,
+	// Evict (default) or Annotate. See BalanceMode.
+	Mode BalanceMode `json:"mode,omitempty"`
+
+	// DecisionLog records why every pod was evicted. See DecisionLog.
+	DecisionLog *DecisionLog `json:"decisionLog,omitempty"`
+
+	// PodEvictionOrder sorts the pods of every source node, Priority
+	// (default), LargestConsumerFirst, SmallestConsumerFirst, OldestFirst
+	// or NewestFirst. See PodEvictionOrder.
+	PodEvictionOrder PodEvictionOrder `json:"podEvictionOrder,omitempty"`
+
+	// AffinityAwareness skips, or evicts last, the pods whose affinity is
+	// better satisfied on their node than on any destination node. See
+	// AffinityAwareness.
+	AffinityAwareness AffinityAwareness `json:"affinityAwareness,omitempty"`
+
+	// FairnessPolicy spreads the evictions of every source node across
+	// the namespaces of its pods, None (default) or Proportional. See
+	// FairnessPolicy.
+	FairnessPolicy FairnessPolicy `json:"fairnessPolicy,omitempty"`
+
+	// NodeCost tells where the cost of the nodes is read from, nodes are
+	// then drained from the most expensive and filled from the cheapest
+	// when their usage ties. See NodeCost.
+	NodeCost *NodeCost `json:"nodeCost,omitempty"`
+
+	// ScaleDownHints marks the nodes whose evictable pods were all evicted
+	// for node autoscalers. See ScaleDownHint.
+	ScaleDownHints []ScaleDownHint `json:"scaleDownHints,omitempty"`
+
+	// ScaleDownHintsTTL is how long the nodes stay marked for node
+	// autoscalers if they are not scaled down, one hour when not set.
+	// See ScaleDownHint.
+	ScaleDownHintsTTL metav1.Duration `json:"scaleDownHintsTTL,omitempty"`
+
+	// ResourceWeights weighs the resources when nodes are classified and
+	// sorted, nodes are then compared through the weighted average of
+	// their usage percentages instead of resource by resource. e.g. cpu: 2
+	// and memory: 1 make the cpu usage count twice as much as the memory
+	// usage. Resources without a weight have a weight of one.
+	ResourceWeights map[v1.ResourceName]float64 `json:"resourceWeights,omitempty"`
+
+	// Cooldown leaves the nodes pods were evicted from alone for a while.
+	// See Cooldown.
+	Cooldown *Cooldown `json:"cooldown,omitempty"`
+
+	// EvictionRateLimit spreads the evictions over time. See
+	// EvictionRateLimit.
+	EvictionRateLimit *EvictionRateLimit `json:"evictionRateLimit,omitempty"`
+
+	// Hysteresis keeps the nodes classified as underutilized on the
+	// previous cycle underutilized until their usage goes above the
+	// thresholds by more than the hysteresis. Nodes hovering around the
+	// thresholds then do not flap between classes across cycles.
+	Hysteresis api.Percentage `json:"hysteresis,omitempty"`
+
+	// UsageCacheTTL keeps the node usage collected by the plugin for the
+	// provided duration. Plugins of any profile collecting the same usage
+	// for the same nodes reuse it instead of listing the pods or querying
+	// the metrics again, until pods are evicted. The usage is collected
+	// on every run when unset.
+	UsageCacheTTL metav1.Duration `json:"usageCacheTTL,omitempty"`
+
+	// RunStatus writes the outcome of every Balance invocation to a
+	// ConfigMap. See RunStatus.
+	RunStatus *RunStatus `json:"runStatus,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type NodeConsolidationArgs struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// TargetThresholds is how full, as a percentage of their capacity,
+	// the nodes left are allowed to get once the pods of the drained
+	// nodes are packed onto them. Resources without a target threshold
+	// can be packed up to the full capacity. cpu, memory and pods are
+	// always considered.
+	TargetThresholds api.ResourceThresholds `json:"targetThresholds,omitempty"`
+
+	// MaxNodesToDrain is the number of nodes drained at most on every
+	// cycle. Defaults to one.
+	MaxNodesToDrain int `json:"maxNodesToDrain,omitempty"`
+
+	// Naming this one differently since namespaces are still
+	// considered while considering resources used by pods
+	// but then filtered out before eviction
+	EvictableNamespaces *api.Namespaces `json:"evictableNamespaces,omitempty"`
+
+	// EvictionLimits limits the evictions the plugin does per cycle.
+	EvictionLimits *api.EvictionLimits `json:"evictionLimits,omitempty"`
+
+	// MetricsUtilization packs the pods by their actual usage instead of
+	// their requests. Only the KubernetesMetrics, VPARecommendations and
+	// Static sources, reporting the usage of the pods in resource units,
+	// are supported.
+	MetricsUtilization *MetricsUtilization `json:"metricsUtilization,omitempty"`
+
+	// DryRun runs the packing simulation and logs the nodes that would
+	// be drained without evicting any pod.
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// Mode selects what is done with the pods selected for eviction,
+	// Evict (default) or Annotate. See BalanceMode.
+	Mode BalanceMode `json:"mode,omitempty"`
+
+	// ScaleDownHints marks the nodes whose evictable pods were all evicted
+	// for node autoscalers. See ScaleDownHint.
+	ScaleDownHints []ScaleDownHint `json:"scaleDownHints,omitempty"`
+
+	// ScaleDownHintsTTL is how long the nodes stay marked for node
+	// autoscalers if they are not scaled down, one hour when not set.
+	// See ScaleDownHint.
+	ScaleDownHintsTTL metav1.Duration `json:"scaleDownHintsTTL,omitempty"`
+
+	// UsageCacheTTL keeps the node usage collected by the plugin for the
+	// provided duration. See LowNodeUtilizationArgs.
+	UsageCacheTTL metav1.Duration `json:"usageCacheTTL,omitempty"`
+
+	// RunStatus writes the outcome of every Balance invocation to a
+	// ConfigMap. See RunStatus.
+	RunStatus *RunStatus `json:"runStatus,omitempty"`
+}
+
+// DecisionLog configures where the decision records of the evicted pods
+// are written to, on top of the descheduler log.
+type DecisionLog struct {
+	// Path of the file the records are appended to, one JSON document per
+	// line.
+	Path string `json:"path,omitempty"`
+}
+
+// RunStatus configures the ConfigMap the outcome of the last Balance
+// invocation is written to, so it can be read programmatically. The number of
+// nodes in every classification, of pods evicted and skipped, the errors hit
+// and the duration of the invocation are written as a JSON document under a
+// key named after the plugin. Plugins of different profiles should write to
+// different ConfigMaps.
+type RunStatus struct {
+	// Namespace of the ConfigMap.
+	Namespace string `json:"namespace"`
+
+	// Name of the ConfigMap, created when it does not exist.
+	Name string `json:"name"`
+}
+
+// InPlaceResize configures how the requests of the evictable pods of the
+// overutilized nodes are shrunk, through the pod resize subresource served
+// by the clusters with the InPlacePodVerticalScaling feature enabled, before
+// resorting to evictions. The pods are shrunk until their node is no longer
+// above its target thresholds, the pods of the nodes still above them once
+// all pods were shrunk are evicted. Guaranteed pods and containers whose
+// resize policy restarts them are not shrunk. Only supported with the usage
+// computed from the pod requests.
+// +k8s:deepcopy-gen=true
+type InPlaceResize struct {
+	// Resources whose requests are shrunk, cpu and memory. Defaults to
+	// cpu.
+	Resources []v1.ResourceName `json:"resources,omitempty"`
+
+	// MaxReduction is the largest share of their requests, in
+	// percentage, the containers are shrunk by.
+	MaxReduction api.Percentage `json:"maxReduction"`
+
+	// MinRequests are the requests the containers are never shrunk
+	// below.
+	MinRequests v1.ResourceList `json:"minRequests,omitempty"`
+}
+
+// ContinueEvictionStrategyType is the condition pods are evicted from an
+// overutilized node under.
+type ContinueEvictionStrategyType string
+
+const (
+	// UntilBelowTarget evicts pods until the node usage drops below the
+	// target thresholds.
+	UntilBelowTarget ContinueEvictionStrategyType = "UntilBelowTarget"
+	// UntilBelowLow evicts pods until the node usage drops below the low
+	// thresholds, the ones underutilized nodes are classified with.
+	UntilBelowLow ContinueEvictionStrategyType = "UntilBelowLow"
+	// FixedPodCount evicts the same number of pods from every node.
+	FixedPodCount ContinueEvictionStrategyType = "FixedPodCount"
+	// FixedResourceAmount evicts pods until the pods evicted from the
+	// node use the provided amount of any of the resources.
+	FixedResourceAmount ContinueEvictionStrategyType = "FixedResourceAmount"
+)
+
+// ContinueEvictionStrategy configures until when pods are evicted from an
+// overutilized node. Regardless of the strategy no pod is evicted once the
+// underutilized nodes can not accept any more pods and the nodes reporting a
+// condition to drain are drained.
+// +k8s:deepcopy-gen=true
+type ContinueEvictionStrategy struct {
+	// Type selects the strategy, UntilBelowTarget (default),
+	// UntilBelowLow, FixedPodCount or FixedResourceAmount.
+	Type ContinueEvictionStrategyType `json:"type,omitempty"`
+
+	// PodCount is the number of pods evicted from every node with the
+	// FixedPodCount strategy.
+	PodCount uint `json:"podCount,omitempty"`
+
+	// ResourceAmount is the amount of resources evicted from every node
+	// with the FixedResourceAmount strategy.
+	ResourceAmount v1.ResourceList `json:"resourceAmount,omitempty"`
+}
+
+// Cooldown configures for how long the nodes pods were evicted from are
+// not used as source nodes again. Consecutive descheduling cycles would
+// otherwise keep evicting pods from the same nodes before the scheduler
+// had the chance to rebalance the cluster.
+type Cooldown struct {
+	// Duration for which a node is skipped after pods were evicted from
+	// it.
+	Duration metav1.Duration `json:"duration"`
+
+	// Annotate records the time of the last eviction in the
+	// descheduler.alpha.kubernetes.io/last-eviction annotation of the
+	// nodes so the cooldown survives restarts of the descheduler.
+	Annotate bool `json:"annotate,omitempty"`
+}
+
+// EvictionRateLimit limits the rate pods are evicted at with a token bucket.
+// The bucket holds up to Burst tokens and is refilled at EvictionsPerMinute,
+// every eviction takes a token and no more pods are evicted during the cycle
+// once the bucket is empty. The bucket is kept across cycles. Large
+// imbalances are then corrected gradually, without overwhelming the
+// scheduler with pods to place.
+type EvictionRateLimit struct {
+	// EvictionsPerMinute is the rate the bucket is refilled at.
+	EvictionsPerMinute uint `json:"evictionsPerMinute"`
+
+	// Burst is the size of the bucket, i.e. the number of pods that can
+	// be evicted at once. Defaults to one.
+	Burst uint `json:"burst,omitempty"`
+}
+
+// DeviationCenter is the aggregate of the nodes usage, per resource, the
+// deviation thresholds are applied around.
+type DeviationCenter string
+
+const (
+	// DeviationCenterAverage applies the deviation thresholds around the
+	// average usage of the nodes. This is the default.
+	DeviationCenterAverage DeviationCenter = "Average"
+	// DeviationCenterMedian applies the deviation thresholds around the
+	// median usage of the nodes. A few nodes far more, or far less, used
+	// than the others skew the average but not the median.
+	DeviationCenterMedian DeviationCenter = "Median"
+	// DeviationCenterTrimmedMean applies the deviation thresholds around
+	// the average usage of the nodes once the most and the least used
+	// ones are left out, see DeviationTrimPercentage.
+	DeviationCenterTrimmedMean DeviationCenter = "TrimmedMean"
+)
+
+// EvictionOrder is the order in which the pods of the source nodes are
+// evicted.
+type EvictionOrder string
+
+const (
+	// EvictionOrderPerNode evicts the pods node after node, the pods of
+	// every node are evicted from the lowest to the highest priority.
+	EvictionOrderPerNode EvictionOrder = "PerNode"
+	// EvictionOrderPriorityBands evicts the pods priority after priority
+	// across all the source nodes: the pods of a priority are evicted from
+	// all the source nodes before any pod of a higher priority is. This
+	// keeps the disruption on the least important pods of the cluster.
+	EvictionOrderPriorityBands EvictionOrder = "PriorityBands"
+)
+
+// PodEvictionOrder is the order in which the pods of a source node are
+// evicted. Pods sharing the same position, e.g. consuming the same share of
+// the node, are evicted from the lowest to the highest priority. Orders
+// other than the built-in ones can be registered through RegisterPodSorter.
+type PodEvictionOrder string
+
+const (
+	// PodEvictionOrderPriority evicts the pods from the lowest to the
+	// highest priority, and then by QoS class.
+	PodEvictionOrderPriority PodEvictionOrder = "Priority"
+	// PodEvictionOrderLargestConsumerFirst evicts the pods consuming the
+	// largest share of the node usage first, so fewer evictions bring the
+	// node under its target.
+	PodEvictionOrderLargestConsumerFirst PodEvictionOrder = "LargestConsumerFirst"
+	// PodEvictionOrderSmallestConsumerFirst evicts the pods consuming the
+	// smallest share of the node usage first, so the pods evicted are the
+	// easiest to place.
+	PodEvictionOrderSmallestConsumerFirst PodEvictionOrder = "SmallestConsumerFirst"
+	// PodEvictionOrderOldestFirst evicts the pods created first.
+	PodEvictionOrderOldestFirst PodEvictionOrder = "OldestFirst"
+	// PodEvictionOrderNewestFirst evicts the pods created last.
+	PodEvictionOrderNewestFirst PodEvictionOrder = "NewestFirst"
+)
+
+// AffinityAwareness is what is done with the pods whose node affinity and
+// pod (anti-)affinity preferences are better satisfied on their node than on
+// any destination node. Once evicted such pods are expected to be scheduled
+// back on their node. Affinity is ignored when not set.
+type AffinityAwareness string
+
+const (
+	// AffinityAwarenessSkip does not evict such pods.
+	AffinityAwarenessSkip AffinityAwareness = "Skip"
+	// AffinityAwarenessDeprioritize evicts such pods after all the other
+	// pods of their node.
+	AffinityAwarenessDeprioritize AffinityAwareness = "Deprioritize"
+)
+
+// FairnessPolicy is how the evictions of a source node are spread across the
+// namespaces of its pods.
+type FairnessPolicy string
+
+const (
+	// FairnessPolicyNone evicts the pods in the order they are sorted in,
+	// whatever their namespace.
+	FairnessPolicyNone FairnessPolicy = "None"
+	// FairnessPolicyProportional interleaves the pods of the namespaces so
+	// every namespace is evicted a number of pods proportional to its share
+	// of the usage of the node.
+	FairnessPolicyProportional FairnessPolicy = "Proportional"
+)
+
+// BalanceMode is what is done with the pods selected for eviction.
+type BalanceMode string
+
+const (
+	// BalanceModeEvict evicts the pods.
+	BalanceModeEvict BalanceMode = "Evict"
+	// BalanceModeAnnotate annotates the pods instead of evicting them,
+	// see EvictionRequestedAnnotationKey. Downstream automation is
+	// expected to act upon the annotations, the descheduler then only
+	// needs to patch pods. The eviction limits of the descheduler are not
+	// enforced, only the ones of the plugin are.
+	BalanceModeAnnotate BalanceMode = "Annotate"
+)
+
+// ScaleDownHint is how the nodes whose evictable pods were all evicted are
+// marked for node autoscalers, e.g. the cluster autoscaler or karpenter, to
+// pick them up. The marks are removed from the nodes that are not scaled
+// down once they host evictable pods again or once the ScaleDownHintsTTL
+// elapsed.
+type ScaleDownHint string
+
+const (
+	// ScaleDownHintAnnotate sets the descheduler.io/candidate-for-scale-down
+	// annotation on the nodes, its value is the name of the plugin.
+	ScaleDownHintAnnotate ScaleDownHint = "Annotate"
+	// ScaleDownHintTaint sets the descheduler.io/candidate-for-scale-down
+	// taint, with the PreferNoSchedule effect, on the nodes.
+	ScaleDownHintTaint ScaleDownHint = "Taint"
+	// ScaleDownHintCordon marks the nodes unschedulable.
+	ScaleDownHintCordon ScaleDownHint = "Cordon"
+)
+
+// NodeCost tells where the cost of running every node, e.g. its hourly
+// price, is read from. Exactly one of label, annotation and provider is
+// expected. Nodes whose cost is not known are considered free.
+// +k8s:deepcopy-gen=true
+type NodeCost struct {
+	// label is the node label holding the cost of the node, e.g.
+	// `node.example.com/hourly-price`, as a decimal number.
+	Label string `json:"label,omitempty"`
+
+	// annotation is the node annotation holding the cost of the node, as
+	// a decimal number.
+	Annotation string `json:"annotation,omitempty"`
+
+	// provider is the name of a NodeCostProvider registered through
+	// RegisterNodeCostProvider.
+	Provider string `json:"provider,omitempty"`
+}
+
+// ScoringStrategyType is the type of scoring strategy used to rank the
+// destination nodes. The types match the ones of the scheduler
+// NodeResourcesFit plugin.
+type ScoringStrategyType string
+
+const (
+	// LeastAllocated ranks first the nodes with the most available
+	// resources.
+	LeastAllocated ScoringStrategyType = "LeastAllocated"
+	// MostAllocated ranks first the nodes with the least available
+	// resources.
+	MostAllocated ScoringStrategyType = "MostAllocated"
+	// SpreadByZone ranks first the nodes in the zones running the fewest
+	// pods of the workload of the evicted pod, as the scheduler spreading
+	// does, then the nodes with the most available resources.
+	SpreadByZone ScoringStrategyType = "SpreadByZone"
+)
+
+// ScoringStrategy mirrors the scoring strategy of the scheduler
+// NodeResourcesFit plugin. When set, every evicted pod is expected to land
+// on the destination node the scheduler would score the highest among the
+// ones it fits on, and pods that do not fit on any destination node are
+// not evicted. It should match the configuration of the scheduler profile
+// the evicted pods are scheduled with.
+// +k8s:deepcopy-gen=true
+type ScoringStrategy struct {
+	// Type selects the scoring strategy, LeastAllocated, MostAllocated or
+	// SpreadByZone.
+	Type ScoringStrategyType `json:"type,omitempty"`
+
+	// Resources to consider when scoring, with their weights. Defaults
+	// to cpu and memory, both with a weight of one.
+	Resources []ResourceSpec `json:"resources,omitempty"`
+}
+
+// ResourceSpec is a resource and its weight when scoring nodes.
+type ResourceSpec struct {
+	// Name of the resource.
+	Name string `json:"name"`
+	// Weight of the resource, in the [1, 100] range.
+	Weight int64 `json:"weight,omitempty"`
+}
+
+// NodeConditionAction is what the plugins do with a node reporting a
+// given condition.
+type NodeConditionAction string
+
+const (
+	// NodeConditionActionAvoid prevents the node from being used as a
+	// destination for the evicted pods.
+	NodeConditionActionAvoid NodeConditionAction = "Avoid"
+	// NodeConditionActionDrain makes the node a source of evictions
+	// regardless of its usage, and prevents it from being used as a
+	// destination for the evicted pods.
+	NodeConditionActionDrain NodeConditionAction = "Drain"
+)
+
+// NodeConditionRule maps a node condition to an action. A node matches the
+// rule when it reports a condition of the given type with the given status.
+type NodeConditionRule struct {
+	// Type of the node condition, e.g. KernelDeadlock.
+	Type v1.NodeConditionType `json:"type"`
+	// Status of the node condition. Defaults to True.
+	Status v1.ConditionStatus `json:"status,omitempty"`
+	// Action taken on the nodes matching the rule, Avoid or Drain.
+	Action NodeConditionAction `json:"action"`
+}
+
+// OvercommitRule multiplies the capacity of the nodes matching a selector,
+// e.g. to let a pool of batch nodes intentionally run at 1.5 times its cpu
+// without being considered overutilized by thresholds tuned for the rest
+// of the nodes. The usage of the matching nodes is compared to, and the
+// pods are moved within, their capacity multiplied by the factors. Nodes
+// matching multiple rules are scaled by the first one.
+// +k8s:deepcopy-gen=true
+type OvercommitRule struct {
+	// NodeSelector selects the nodes the rule applies to.
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector"`
+	// Factors multiply the capacity of the nodes, by resource, e.g. 1.5
+	// for cpu. Resources without a factor keep their capacity.
+	Factors map[v1.ResourceName]float64 `json:"factors"`
+}
+
+// NodePoolThresholds are the thresholds and the target thresholds of the
+// nodes matching a node selector, e.g. GPU nodes next to general purpose
+// nodes. They are interpreted as the plugin thresholds are, with deviation
+// or percentile thresholds the average and the percentiles are computed
+// among the nodes of the pool. Nodes matching multiple pools use the first
+// one, nodes matching none use the plugin thresholds.
+// +k8s:deepcopy-gen=true
+type NodePoolThresholds struct {
+	// NodeSelector selects the nodes of the pool.
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector"`
+	// Thresholds of the nodes of the pool.
+	Thresholds api.ResourceThresholds `json:"thresholds"`
+	// TargetThresholds of the nodes of the pool.
+	TargetThresholds api.ResourceThresholds `json:"targetThresholds"`
 }
 
 // MetricsUtilization allow to consume actual resource utilization from metrics
@@ -81,17 +725,282 @@ type MetricsUtilization struct {
 	// Deprecated. Use Source instead.
 	MetricsServer bool `json:"metricsServer,omitempty"`
 
-	// source enables the plugin to consume metrics from a metrics source.
-	// Currently only KubernetesMetrics available.
+	// source enables the plugin to consume metrics from a metrics source,
+	// either KubernetesMetrics, Prometheus, CustomMetrics,
+	// VPARecommendations, OpenTelemetry or Static.
 	Source api.MetricsSource `json:"source,omitempty"`
 
 	// prometheus enables metrics collection through a prometheus query.
 	Prometheus *Prometheus `json:"prometheus,omitempty"`
+
+	// customMetrics enables metrics collection through the custom or the
+	// external metrics APIs.
+	CustomMetrics *CustomMetrics `json:"customMetrics,omitempty"`
+
+	// openTelemetry enables metrics collection from an OpenTelemetry
+	// collector.
+	OpenTelemetry *OpenTelemetryMetrics `json:"openTelemetry,omitempty"`
+
+	// static reads the utilization from a static document.
+	Static *StaticMetrics `json:"static,omitempty"`
+
+	// weights blends the actual utilization reported by the metrics
+	// source with the utilization computed from the pod requests. Only
+	// supported with the KubernetesMetrics source.
+	Weights *UsageWeights `json:"weights,omitempty"`
+
+	// smoothingFactor is the weight of the latest sample in the
+	// exponentially weighted moving average of the node usage collected
+	// from the metrics server, in (0; 1] interval. Lower values smooth out
+	// usage spikes further, 1 disables the smoothing. Defaults to 0.1.
+	// Only supported with the KubernetesMetrics source.
+	SmoothingFactor float64 `json:"smoothingFactor,omitempty"`
+
+	// minSamples is the number of samples to collect for a node before
+	// its usage is taken into account, the plugin does nothing until all
+	// the processed nodes have enough of them. Only supported with the
+	// KubernetesMetrics source.
+	MinSamples int `json:"minSamples,omitempty"`
+
+	// extendedResources collects the actual usage of the resources the
+	// metrics server does not report, e.g. nvidia.com/gpu, through
+	// prometheus queries. Only supported with the KubernetesMetrics
+	// source.
+	ExtendedResources []ExtendedResourceMetrics `json:"extendedResources,omitempty"`
+}
+
+// ExtendedResourceMetrics configures how the actual usage of a resource is
+// collected from prometheus, e.g. out of the metrics of the NVIDIA DCGM
+// exporter.
+type ExtendedResourceMetrics struct {
+	// name of the resource, e.g. nvidia.com/gpu.
+	Name v1.ResourceName `json:"name"`
+
+	// query returning a vector of samples, each sample labeled with the
+	// nodeLabel label corresponding to a node name with the usage of the
+	// resource on the node as value, in units of the resource. E.g. 1.5
+	// for one and a half fully used GPUs.
+	Query string `json:"query"`
+
+	// nodeLabel is the label of the samples holding the node name, e.g.
+	// `Hostname` for the DCGM exporter. Defaults to `instance`.
+	NodeLabel string `json:"nodeLabel,omitempty"`
+
+	// podQuery returning a vector of samples whose values sum up to the
+	// usage of the resource by a pod, in units of the resource. The query
+	// refers to the pod through the {{.Namespace}} and {{.Pod}} template
+	// placeholders. Without it only a single pod is evicted from each
+	// overutilized node.
+	PodQuery string `json:"podQuery,omitempty"`
+}
+
+// CustomMetrics configures a metric served through the Kubernetes custom
+// metrics API (custom.metrics.k8s.io) or external metrics API
+// (external.metrics.k8s.io), e.g. by Prometheus Adapter or the Datadog
+// Cluster Agent.
+// +k8s:deepcopy-gen=true
+type CustomMetrics struct {
+	// metricName is the name of the metric describing the nodes, each
+	// node value is expected to be a real number in <0; 1> interval.
+	MetricName string `json:"metricName,omitempty"`
+
+	// podMetricName is the name of the metric describing the pods, each
+	// pod value being the share of its node the pod uses. Without it only
+	// a single pod is evicted from each overutilized node. Not supported
+	// with external metrics.
+	PodMetricName string `json:"podMetricName,omitempty"`
+
+	// selector restricts the series of the metrics, e.g. to a single
+	// mode of the cpu usage.
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// external reads metricName through the external metrics API instead
+	// of the custom metrics one.
+	External *ExternalMetric `json:"external,omitempty"`
+}
+
+// OpenTelemetryMetrics configures how the utilization of the nodes is read
+// from an OpenTelemetry collector exposing the metrics it receives, e.g. over
+// OTLP, through its prometheus exporter. Metric and attribute names are given
+// in their OpenTelemetry form, dots are translated to underscores the way the
+// exporter does.
+// +k8s:deepcopy-gen=true
+type OpenTelemetryMetrics struct {
+	// endpoint is the url the prometheus exporter of the collector serves
+	// the metrics at, e.g. http://otel-collector.monitoring:8889/metrics.
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// metricName is the name of the gauge describing the nodes, e.g.
+	// system.memory.utilization, each node value is expected to be a real
+	// number in <0; 1> interval. The `_ratio` suffix the exporter appends
+	// to the names of ratio gauges is not part of the name.
+	MetricName string `json:"metricName,omitempty"`
+
+	// nodeAttribute is the attribute of the metric holding the node name.
+	// Defaults to `k8s.node.name`. Resource attributes, as k8s.node.name
+	// usually is, are only exported as labels with the
+	// resource_to_telemetry_conversion setting of the exporter enabled.
+	NodeAttribute string `json:"nodeAttribute,omitempty"`
+
+	// attributes the series of the node metric are restricted to, e.g.
+	// `state: used` for the system.memory.utilization metric.
+	Attributes map[string]string `json:"attributes,omitempty"`
+
+	// aggregation combines the series of the node metric describing the
+	// same node into a single value. Defaults to `Average`, the series of
+	// a node being e.g. one per cpu.
+	Aggregation OpenTelemetryAggregation `json:"aggregation,omitempty"`
+
+	// podMetricName is the name of the gauge describing the pods, each
+	// pod value being the share of its node the pod uses, e.g.
+	// k8s.pod.cpu.node.utilization. The pods are told apart by the
+	// k8s.namespace.name and k8s.pod.name attributes. Without it only a
+	// single pod is evicted from each overutilized node.
+	PodMetricName string `json:"podMetricName,omitempty"`
+}
+
+// OpenTelemetryAggregation combines the series describing the same node.
+type OpenTelemetryAggregation string
+
+const (
+	// OpenTelemetryAggregationAverage keeps the average of the series,
+	// for series each describing a part of the node, e.g. a cpu.
+	OpenTelemetryAggregationAverage OpenTelemetryAggregation = "Average"
+	// OpenTelemetryAggregationSum adds the series up, for series each
+	// holding a share of the whole node.
+	OpenTelemetryAggregationSum OpenTelemetryAggregation = "Sum"
+)
+
+// StaticMetrics configures where the static usage document is read from,
+// either a file or a ConfigMap. The document is read again on every cycle.
+// +k8s:deepcopy-gen=true
+type StaticMetrics struct {
+	// file is the path of the document.
+	File string `json:"file,omitempty"`
+
+	// configMap holding the document.
+	ConfigMap *StaticUsageConfigMap `json:"configMap,omitempty"`
+}
+
+// StaticUsageConfigMap references the ConfigMap key holding a static usage
+// document.
+type StaticUsageConfigMap struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+
+	// key of the document in the ConfigMap data. Defaults to `usage`.
+	Key string `json:"key,omitempty"`
 }
 
+// StaticUsage is the document a static usage client reads the utilization
+// from, in YAML or JSON. Resources a node lists are taken as they are, the
+// other ones are summed up from the usage of the pods on the node. Resources
+// a pod does not list are accounted through its requests.
+// +k8s:deepcopy-gen=true
+type StaticUsage struct {
+	// nodes maps the node names to their usage.
+	Nodes map[string]v1.ResourceList `json:"nodes,omitempty"`
+
+	// pods maps the pods, referred to as namespace/name, to their usage.
+	Pods map[string]v1.ResourceList `json:"pods,omitempty"`
+}
+
+// ExternalMetric configures how the nodes are read from an external metric.
+// External metrics do not describe Kubernetes objects, the nodes are told
+// apart by a label of the metric.
+type ExternalMetric struct {
+	// namespace the external metric is read from.
+	Namespace string `json:"namespace,omitempty"`
+
+	// nodeLabel is the label of the metric holding the node name.
+	// Defaults to `node`.
+	NodeLabel string `json:"nodeLabel,omitempty"`
+}
+
+// UsageWeights sets how much each source of utilization weighs when the
+// actual utilization is blended with the utilization computed from the pod
+// requests. The utilization of a resource is the weighted average of both.
+type UsageWeights struct {
+	// requests is the weight of the utilization computed from the pod
+	// requests.
+	Requests float64 `json:"requests,omitempty"`
+
+	// metrics is the weight of the actual utilization reported by the
+	// metrics source.
+	Metrics float64 `json:"metrics,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
 type Prometheus struct {
-	// query returning a vector of samples, each sample labeled with `instance`
-	// corresponding to a node name with each sample value as a real number
-	// in <0; 1> interval.
+	// query returning a vector of samples, each sample labeled with the
+	// nodeLabel label corresponding to a node name with each sample value
+	// as a real number in <0; 1> interval. The query may refer to the nodes being processed
+	// through the {{.Nodes}} template placeholder, it is replaced with a
+	// regular expression matching the node names, escaped to be used within
+	// a double-quoted matcher, e.g. instance=~"{{.Nodes}}". In this case the nodes
+	// are queried in groups of nodesPerQuery.
 	Query string `json:"query,omitempty"`
+
+	// nodesPerQuery is the maximum number of nodes a query referring to
+	// the {{.Nodes}} placeholder is issued for. Defaults to 100.
+	NodesPerQuery int `json:"nodesPerQuery,omitempty"`
+
+	// nodeLabel is the label of the samples holding the node name, e.g.
+	// `node` or `kubernetes_node`. Defaults to `instance`.
+	NodeLabel string `json:"nodeLabel,omitempty"`
+
+	// podQuery returning a vector of samples whose values sum up to the
+	// share of the node used by a pod, a real number in <0; 1> interval.
+	// The query refers to the pod through the {{.Namespace}} and {{.Pod}}
+	// template placeholders. Without it only a single pod is evicted from
+	// each overutilized node.
+	PodQuery string `json:"podQuery,omitempty"`
+
+	// timeout bounds every attempt of a query. Defaults to 10s.
+	Timeout metav1.Duration `json:"timeout,omitempty"`
+
+	// retries is the number of times a failed query is attempted again
+	// before the cycle is given up on. Queries prometheus rejects as
+	// invalid are not retried. Defaults to 0.
+	Retries int `json:"retries,omitempty"`
+
+	// backoff is the delay before the first retry, it doubles on every
+	// following one. Defaults to 1s.
+	Backoff metav1.Duration `json:"backoff,omitempty"`
+
+	// range evaluates the queries over a window of time instead of at
+	// the current time, the samples of every series are then aggregated
+	// into a single value. See PrometheusRange.
+	Range *PrometheusRange `json:"range,omitempty"`
 }
+
+// PrometheusRange evaluates the prometheus queries as range queries, e.g.
+// over the last 10 minutes, so a single spiky sample does not trigger a
+// round of evictions. The samples of every series returned are aggregated
+// into a single value, the queries are otherwise expected to return the
+// same samples as instant queries would.
+// +k8s:deepcopy-gen=true
+type PrometheusRange struct {
+	// window is how far back the queries are evaluated from.
+	Window metav1.Duration `json:"window"`
+
+	// step is the resolution of the queries, the interval between two
+	// samples of a series. Defaults to a minute.
+	Step metav1.Duration `json:"step,omitempty"`
+
+	// aggregation turns the samples of a series into a single value.
+	// Defaults to Average.
+	Aggregation PrometheusAggregation `json:"aggregation,omitempty"`
+}
+
+// PrometheusAggregation turns the samples of a series into a single value.
+type PrometheusAggregation string
+
+const (
+	// PrometheusAggregationAverage keeps the average of the samples.
+	PrometheusAggregationAverage PrometheusAggregation = "Average"
+	// PrometheusAggregationMax keeps the highest sample.
+	PrometheusAggregationMax PrometheusAggregation = "Max"
+	// PrometheusAggregationP95 keeps the 95th percentile of the samples.
+	PrometheusAggregationP95 PrometheusAggregation = "P95"
+)
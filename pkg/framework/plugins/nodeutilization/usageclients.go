@@ -18,22 +18,43 @@ package nodeutilization
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
+	"math"
+	"net/http"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
 	"time"
 
 	promapi "github.com/prometheus/client_golang/api"
 	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
 	"github.com/prometheus/common/model"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
 	utilptr "k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
 
 	"sigs.k8s.io/descheduler/pkg/api"
 	"sigs.k8s.io/descheduler/pkg/descheduler/metricscollector"
 	nodeutil "sigs.k8s.io/descheduler/pkg/descheduler/node"
 	podutil "sigs.k8s.io/descheduler/pkg/descheduler/pod"
+	"sigs.k8s.io/descheduler/pkg/descheduler/vpa"
+	frameworktypes "sigs.k8s.io/descheduler/pkg/framework/types"
 	"sigs.k8s.io/descheduler/pkg/utils"
 )
 
@@ -43,6 +64,11 @@ const (
 	requestedUsageClientType UsageClientType = iota
 	actualUsageClientType
 	prometheusUsageClientType
+	compositeUsageClientType
+	vpaRecommendationUsageClientType
+	customMetricsUsageClientType
+	openTelemetryUsageClientType
+	staticUsageClientType
 )
 
 type notSupportedError struct {
@@ -59,56 +85,415 @@ func newNotSupportedError(usageClientType UsageClientType) *notSupportedError {
 	}
 }
 
-type usageClient interface {
-	// Both low/high node utilization plugins are expected to invoke sync right
+// absentUsage marks a resource a compactUsage holds no value for.
+const absentUsage = math.MinInt64
+
+// compactUsage is a memory efficient representation of a node usage. Values
+// are kept in the order of the resource names the usage client was created
+// for, cpu and extended resources in milli units, so fractions reported by
+// metrics are kept, and all the other resources in their base units.
+// Usage clients keep the usage of all nodes in this form and only convert it
+// into an api.ReferencedResourceList when it is requested.
+type compactUsage []int64
+
+// newCompactUsage converts the usage of the provided resources into its
+// compact form.
+func newCompactUsage(resourceNames []v1.ResourceName, usage api.ReferencedResourceList) compactUsage {
+	compact := make(compactUsage, len(resourceNames))
+	for i, resourceName := range resourceNames {
+		quantity, ok := usage[resourceName]
+		switch {
+		case !ok || quantity == nil:
+			compact[i] = absentUsage
+		case isMilliResource(resourceName):
+			compact[i] = quantity.MilliValue()
+		default:
+			compact[i] = quantity.Value()
+		}
+	}
+	return compact
+}
+
+// resourceList converts the compact usage back into an
+// api.ReferencedResourceList. resourceNames must be the same the compact
+// usage was created with.
+func (c compactUsage) resourceList(resourceNames []v1.ResourceName) api.ReferencedResourceList {
+	if c == nil {
+		return nil
+	}
+
+	usage := make(api.ReferencedResourceList, len(resourceNames))
+	for i, resourceName := range resourceNames {
+		switch {
+		case c[i] == absentUsage:
+			continue
+		case isMilliResource(resourceName):
+			usage[resourceName] = resource.NewMilliQuantity(c[i], resource.DecimalSI)
+		default:
+			usage[resourceName] = resource.NewQuantity(c[i], utils.ResourceQuantityFormat(resourceName))
+		}
+	}
+	return usage
+}
+
+// isMilliResource tells if the compact usage of the resource is kept in milli
+// units, i.e. cpu and the extended resources.
+func isMilliResource(resourceName v1.ResourceName) bool {
+	return resourceName != v1.ResourcePods &&
+		utils.ResourceQuantityFormat(resourceName) == resource.DecimalSI
+}
+
+// UsageClient provides the utilization of the nodes and the usage of their
+// pods. Plugins assessing node utilization can rely on it, unit tests can
+// use the FakeUsageClient of the framework testing package.
+type UsageClient interface {
+	// Both low/high node utilization plugins are expected to invoke Sync right
 	// after Balance method is invoked. There's no cache invalidation so each
-	// Balance is expected to get the latest data by invoking sync.
-	sync(ctx context.Context, nodes []*v1.Node) error
-	nodeUtilization(node string) api.ReferencedResourceList
-	pods(node string) []*v1.Pod
-	podUsage(pod *v1.Pod) (api.ReferencedResourceList, error)
+	// Balance is expected to get the latest data by invoking Sync.
+	Sync(ctx context.Context, nodes []*v1.Node) error
+	NodeUtilization(node string) api.ReferencedResourceList
+	Pods(node string) []*v1.Pod
+	PodUsage(pod *v1.Pod) (api.ReferencedResourceList, error)
+}
+
+// nodeUsageSnapshot holds the utilization computed for a node together with
+// the resource versions of the node and of the pods it was computed from. It
+// allows to reuse the utilization for as long as neither the node nor its pod
+// set changes.
+type nodeUsageSnapshot struct {
+	nodeVersion string
+	podVersions map[types.UID]string
+	pods        []*v1.Pod
+	usage       compactUsage
+}
+
+// nodeUsageSnapshots holds the latest utilization snapshot of every node.
+type nodeUsageSnapshots map[string]*nodeUsageSnapshot
+
+// podVersion returns the version of the pod its requests are computed from,
+// including the versions of the resource claims devices are allocated to
+// the pod through.
+func podVersion(pod *v1.Pod, devices *nodeutil.DeviceAccounting) string {
+	if claims := devices.PodClaimsVersion(pod); claims != "" {
+		return pod.ResourceVersion + "/" + claims
+	}
+	return pod.ResourceVersion
+}
+
+// newNodeUsageSnapshot returns a snapshot for the provided node, pods and
+// usage. Returns nil if any of the objects misses its resource version as
+// changes to it could not be detected.
+func newNodeUsageSnapshot(node *v1.Node, pods []*v1.Pod, usage compactUsage, devices *nodeutil.DeviceAccounting) *nodeUsageSnapshot {
+	if node.ResourceVersion == "" {
+		return nil
+	}
+	podVersions := make(map[types.UID]string, len(pods))
+	for _, pod := range pods {
+		if pod.ResourceVersion == "" {
+			return nil
+		}
+		podVersions[pod.UID] = podVersion(pod, devices)
+	}
+	return &nodeUsageSnapshot{
+		nodeVersion: node.ResourceVersion,
+		podVersions: podVersions,
+		pods:        pods,
+		usage:       usage,
+	}
+}
+
+// matches returns true if the snapshot was computed from the same versions
+// of the node, its pods and their resource claims.
+func (s *nodeUsageSnapshot) matches(node *v1.Node, pods []*v1.Pod, devices *nodeutil.DeviceAccounting) bool {
+	if s.nodeVersion != node.ResourceVersion || len(s.podVersions) != len(pods) {
+		return false
+	}
+	for _, pod := range pods {
+		if version, ok := s.podVersions[pod.UID]; !ok || version != podVersion(pod, devices) {
+			return false
+		}
+	}
+	return true
+}
+
+// sharedUsageClient wraps a usage client shared among the plugins of a
+// profile during a descheduling cycle. Syncing the same set of nodes more
+// than once is skipped unless the client has been invalidated in between,
+// e.g. because pods have been evicted.
+type sharedUsageClient struct {
+	UsageClient
+
+	mu     sync.Mutex
+	synced []string
+	stale  bool
+}
+
+var _ UsageClient = &sharedUsageClient{}
+
+func (c *sharedUsageClient) Sync(ctx context.Context, nodes []*v1.Node) error {
+	names := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		names = append(names, node.Name)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.stale && c.synced != nil && slices.Equal(c.synced, names) {
+		klog.V(3).InfoS("Reusing node usage synced by another plugin")
+		return nil
+	}
+
+	if err := syncUsageClient(ctx, c.UsageClient, nodes); err != nil {
+		c.synced = nil
+		return err
+	}
+	c.synced, c.stale = names, false
+	return nil
+}
+
+// invalidate makes the next sync to reach the wrapped usage client.
+func (c *sharedUsageClient) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stale = true
+}
+
+// cachedUsage is the usage a usage client synced for a set of nodes, it is
+// kept in the cache shared by all profiles.
+type cachedUsage struct {
+	client UsageClient
+	nodes  []string
+}
+
+// cachedUsageClient reuses the usage synced by any plugin of any profile
+// using the same configuration for as long as it is cached. The usage is
+// cached for ttl once synced and dropped when pods are evicted. Usage
+// synced for a different set of nodes is never reused.
+type cachedUsageClient struct {
+	cache  *frameworktypes.SharedCache
+	key    string
+	ttl    time.Duration
+	create func() (UsageClient, error)
+	// getPodsAssignedToNode lists the pods of the current cycle, pods
+	// evicted since the usage was cached are not returned.
+	getPodsAssignedToNode podutil.GetPodsAssignedToNodeFunc
+
+	current UsageClient
+}
+
+var _ UsageClient = &cachedUsageClient{}
+
+func (c *cachedUsageClient) Sync(ctx context.Context, nodes []*v1.Node) error {
+	names := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		names = append(names, node.Name)
+	}
+
+	if obj, ok := c.cache.Get(c.key); ok {
+		if cached := obj.(*cachedUsage); slices.Equal(cached.nodes, names) {
+			klog.V(3).InfoS("Reusing cached node usage")
+			c.current = cached.client
+			return nil
+		}
+	}
+
+	// a new client is created every time the usage is synced again, the
+	// clients capture the pods of the cycle they were created in.
+	client, err := c.create()
+	if err != nil {
+		return err
+	}
+	if err := syncUsageClient(ctx, client, nodes); err != nil {
+		return err
+	}
+	c.cache.Set(c.key, &cachedUsage{client: client, nodes: names}, c.ttl)
+	c.current = client
+	return nil
+}
+
+func (c *cachedUsageClient) NodeUtilization(node string) api.ReferencedResourceList {
+	return c.current.NodeUtilization(node)
+}
+
+func (c *cachedUsageClient) Pods(node string) []*v1.Pod {
+	pods := c.current.Pods(node)
+	current, err := podutil.ListPodsOnANode(node, c.getPodsAssignedToNode, nil)
+	if err != nil {
+		return pods
+	}
+	uids := sets.New[types.UID]()
+	for _, pod := range current {
+		uids.Insert(pod.UID)
+	}
+	return slices.DeleteFunc(slices.Clone(pods), func(pod *v1.Pod) bool {
+		return !uids.Has(pod.UID)
+	})
+}
+
+func (c *cachedUsageClient) PodUsage(pod *v1.Pod) (api.ReferencedResourceList, error) {
+	return c.current.PodUsage(pod)
+}
+
+// invalidate drops the cached usage so the next sync collects it again.
+func (c *cachedUsageClient) invalidate() {
+	c.cache.Delete(c.key)
+}
+
+// sharedUsageClientFor returns the usage client shared through the handle
+// under the provided configuration key. The client is created by create if
+// no plugin of the profile has created it yet during the cycle. With a
+// positive ttl the synced usage is also shared with the plugins of other
+// profiles, and across cycles, through the handle shared cache.
+func sharedUsageClientFor(
+	handle frameworktypes.Handle, key string, ttl time.Duration, create func() (UsageClient, error),
+) (UsageClient, error) {
+	if ttl > 0 {
+		return &cachedUsageClient{
+			cache:  frameworktypes.SharedCacheFor(handle),
+			key:    "nodeutilization/usagecache/" + key,
+			ttl:    ttl,
+			create: create,
+
+			getPodsAssignedToNode: handle.GetPodsAssignedToNodeFunc(),
+		}, nil
+	}
+
+	obj, err := frameworktypes.SharedObjectsFor(handle).GetOrCreate(
+		"nodeutilization/usageclient/"+key,
+		func() (any, error) {
+			client, err := create()
+			if err != nil {
+				return nil, err
+			}
+			return &sharedUsageClient{UsageClient: client}, nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*sharedUsageClient), nil
+}
+
+// usageClientKey returns the key identifying a usage client configuration.
+// Plugins using the same configuration share the same usage client.
+func usageClientKey(clientType UsageClientType, resourceNames []v1.ResourceName, extra ...string) string {
+	names := make([]string, 0, len(resourceNames))
+	for _, resourceName := range resourceNames {
+		names = append(names, string(resourceName))
+	}
+	slices.Sort(names)
+	return fmt.Sprintf("%d/%s/%s", clientType, strings.Join(names, ","), strings.Join(extra, "/"))
+}
+
+// invalidateUsageClient invalidates the usage client if it is shared, this
+// must be called after evicting pods so other plugins sharing the client do
+// not rely on usage from before the evictions.
+func invalidateUsageClient(client UsageClient) {
+	switch c := client.(type) {
+	case *sharedUsageClient:
+		c.invalidate()
+	case *cachedUsageClient:
+		c.invalidate()
+	case *compositeUsageClient:
+		for _, wrapped := range c.clients {
+			invalidateUsageClient(wrapped)
+		}
+	}
 }
 
 type requestedUsageClient struct {
 	resourceNames         []v1.ResourceName
 	getPodsAssignedToNode podutil.GetPodsAssignedToNodeFunc
+	// devices, when set, accounts the devices allocated to the pods
+	// through Dynamic Resource Allocation as part of their requests.
+	devices *nodeutil.DeviceAccounting
+
+	// snapshots keeps the per node utilization from previous syncs, of
+	// this client or of the clients of previous cycles, so it is only
+	// recomputed for nodes whose pod set has changed since. nil disables
+	// the reuse.
+	snapshots nodeUsageSnapshots
 
 	_pods            map[string][]*v1.Pod
-	_nodeUtilization map[string]api.ReferencedResourceList
+	_nodeUtilization map[string]compactUsage
 }
 
-var _ usageClient = &requestedUsageClient{}
+var _ UsageClient = &requestedUsageClient{}
 
 func newRequestedUsageClient(
 	resourceNames []v1.ResourceName,
 	getPodsAssignedToNode podutil.GetPodsAssignedToNodeFunc,
+	devices *nodeutil.DeviceAccounting,
+	snapshots nodeUsageSnapshots,
 ) *requestedUsageClient {
 	return &requestedUsageClient{
 		resourceNames:         resourceNames,
 		getPodsAssignedToNode: getPodsAssignedToNode,
+		devices:               devices,
+		snapshots:             snapshots,
+	}
+}
+
+// nodeUsageSnapshotsFor returns the snapshots of the node utilization the
+// requested usage clients of the profile of the handle computed for the
+// provided resources. The clients are created again on every cycle, the
+// snapshots are kept in the shared cache so the clients of the next cycles
+// reuse them.
+func nodeUsageSnapshotsFor(handle frameworktypes.Handle, resourceNames []v1.ResourceName) nodeUsageSnapshots {
+	return sharedObject(
+		handle,
+		fmt.Sprintf("nodeutilization/usagesnapshots/%s/%s", frameworktypes.ProfileNameFor(handle), usageClientKey(requestedUsageClientType, resourceNames)),
+		func() nodeUsageSnapshots { return nodeUsageSnapshots{} },
+	)
+}
+
+// snapshot returns the snapshot of the node utilization if it was computed
+// from the same versions of the node and its pods.
+func (s *requestedUsageClient) snapshot(node *v1.Node, pods []*v1.Pod) (*nodeUsageSnapshot, bool) {
+	snapshot, ok := s.snapshots[node.Name]
+	if !ok {
+		return nil, false
 	}
+	return snapshot, snapshot.matches(node, pods, s.devices)
 }
 
-func (s *requestedUsageClient) nodeUtilization(node string) api.ReferencedResourceList {
-	return s._nodeUtilization[node]
+func (s *requestedUsageClient) NodeUtilization(node string) api.ReferencedResourceList {
+	return s._nodeUtilization[node].resourceList(s.resourceNames)
 }
 
-func (s *requestedUsageClient) pods(node string) []*v1.Pod {
+func (s *requestedUsageClient) Pods(node string) []*v1.Pod {
 	return s._pods[node]
 }
 
-func (s *requestedUsageClient) podUsage(pod *v1.Pod) (api.ReferencedResourceList, error) {
+func (s *requestedUsageClient) PodUsage(pod *v1.Pod) (api.ReferencedResourceList, error) {
 	usage := make(api.ReferencedResourceList)
 	for _, resourceName := range s.resourceNames {
 		usage[resourceName] = utilptr.To[resource.Quantity](utils.GetResourceRequestQuantity(pod, resourceName).DeepCopy())
 	}
+	for name, quantity := range s.devices.PodRequests(pod) {
+		if _, ok := usage[name]; ok {
+			usage[name] = utilptr.To[resource.Quantity](quantity.DeepCopy())
+		}
+	}
 	return usage, nil
 }
 
-func (s *requestedUsageClient) sync(ctx context.Context, nodes []*v1.Node) error {
-	s._nodeUtilization = make(map[string]api.ReferencedResourceList)
+func (s *requestedUsageClient) Sync(ctx context.Context, nodes []*v1.Node) error {
+	s._nodeUtilization = make(map[string]compactUsage)
 	s._pods = make(map[string][]*v1.Pod)
 
+	// the snapshots of the nodes no longer processed are dropped, the
+	// snapshots are only kept for the current nodes.
+	if s.snapshots != nil {
+		current := make(map[string]bool, len(nodes))
+		for _, node := range nodes {
+			current[node.Name] = true
+		}
+		maps.DeleteFunc(s.snapshots, func(name string, _ *nodeUsageSnapshot) bool {
+			return !current[name]
+		})
+	}
+
 	for _, node := range nodes {
 		pods, err := podutil.ListPodsOnANode(node.Name, s.getPodsAssignedToNode, nil)
 		if err != nil {
@@ -116,8 +501,20 @@ func (s *requestedUsageClient) sync(ctx context.Context, nodes []*v1.Node) error
 			return fmt.Errorf("error accessing %q node's pods: %v", node.Name, err)
 		}
 
+		// if neither the node nor its pods changed since the last sync
+		// the previously computed utilization is still accurate.
+		if snapshot, ok := s.snapshot(node, pods); ok {
+			klog.V(4).InfoS("Reusing node utilization from previous sync", "node", klog.KObj(node))
+			s._pods[node.Name] = snapshot.pods
+			s._nodeUtilization[node.Name] = snapshot.usage
+			continue
+		}
+
 		nodeUsage, err := nodeutil.NodeUtilization(pods, s.resourceNames, func(pod *v1.Pod) (v1.ResourceList, error) {
 			req, _ := utils.PodRequestsAndLimits(pod)
+			for name, quantity := range s.devices.PodRequests(pod) {
+				req[name] = quantity
+			}
 			return req, nil
 		})
 		if err != nil {
@@ -125,10 +522,17 @@ func (s *requestedUsageClient) sync(ctx context.Context, nodes []*v1.Node) error
 		}
 
 		// store the snapshot of pods from the same (or the closest) node utilization computation
+		compact := newCompactUsage(s.resourceNames, nodeUsage)
 		s._pods[node.Name] = pods
-		s._nodeUtilization[node.Name] = nodeUsage
+		s._nodeUtilization[node.Name] = compact
+		if s.snapshots != nil {
+			if snapshot := newNodeUsageSnapshot(node, pods, compact, s.devices); snapshot != nil {
+				s.snapshots[node.Name] = snapshot
+			} else {
+				delete(s.snapshots, node.Name)
+			}
+		}
 	}
-
 	return nil
 }
 
@@ -136,34 +540,57 @@ type actualUsageClient struct {
 	resourceNames         []v1.ResourceName
 	getPodsAssignedToNode podutil.GetPodsAssignedToNodeFunc
 	metricsCollector      *metricscollector.MetricsCollector
+	// smoothingFactor is the weight of the latest sample in the node
+	// usage, the collector default is used when zero.
+	smoothingFactor float64
+	// minSamples is the number of samples a node needs before its usage
+	// is trusted.
+	minSamples int
+	// collectors report the usage of the resources the metrics server
+	// does not, indexed by resource name.
+	collectors map[v1.ResourceName]ResourceUsageCollector
 
 	_pods            map[string][]*v1.Pod
-	_nodeUtilization map[string]api.ReferencedResourceList
+	_nodeUtilization map[string]compactUsage
+	// _podUsage caches the pod usage fetched since the last sync so the
+	// metrics api is queried at most once per pod during a cycle.
+	_podUsage map[types.NamespacedName]api.ReferencedResourceList
 }
 
-var _ usageClient = &actualUsageClient{}
+var _ UsageClient = &actualUsageClient{}
 
 func newActualUsageClient(
 	resourceNames []v1.ResourceName,
 	getPodsAssignedToNode podutil.GetPodsAssignedToNodeFunc,
 	metricsCollector *metricscollector.MetricsCollector,
+	smoothingFactor float64,
+	minSamples int,
+	collectors map[v1.ResourceName]ResourceUsageCollector,
 ) *actualUsageClient {
 	return &actualUsageClient{
 		resourceNames:         resourceNames,
 		getPodsAssignedToNode: getPodsAssignedToNode,
 		metricsCollector:      metricsCollector,
+		smoothingFactor:       smoothingFactor,
+		minSamples:            minSamples,
+		collectors:            collectors,
 	}
 }
 
-func (client *actualUsageClient) nodeUtilization(node string) api.ReferencedResourceList {
-	return client._nodeUtilization[node]
+func (client *actualUsageClient) NodeUtilization(node string) api.ReferencedResourceList {
+	return client._nodeUtilization[node].resourceList(client.resourceNames)
 }
 
-func (client *actualUsageClient) pods(node string) []*v1.Pod {
+func (client *actualUsageClient) Pods(node string) []*v1.Pod {
 	return client._pods[node]
 }
 
-func (client *actualUsageClient) podUsage(pod *v1.Pod) (api.ReferencedResourceList, error) {
+func (client *actualUsageClient) PodUsage(pod *v1.Pod) (api.ReferencedResourceList, error) {
+	key := types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}
+	if usage, ok := client._podUsage[key]; ok {
+		return copyUsage(usage), nil
+	}
+
 	// It's not efficient to keep track of all pods in a cluster when only their fractions is evicted.
 	// Thus, take the current pod metrics without computing any softening (like e.g. EWMA).
 	podMetrics, err := client.metricsCollector.MetricsClient().MetricsV1beta1().PodMetricses(pod.Namespace).Get(context.TODO(), pod.Name, metav1.GetOptions{})
@@ -174,7 +601,7 @@ func (client *actualUsageClient) podUsage(pod *v1.Pod) (api.ReferencedResourceLi
 	totalUsage := make(api.ReferencedResourceList)
 	for _, container := range podMetrics.Containers {
 		for _, resourceName := range client.resourceNames {
-			if resourceName == v1.ResourcePods {
+			if resourceName == v1.ResourcePods || client.collectors[resourceName] != nil {
 				continue
 			}
 			if _, exists := container.Usage[resourceName]; !exists {
@@ -188,18 +615,43 @@ func (client *actualUsageClient) podUsage(pod *v1.Pod) (api.ReferencedResourceLi
 		}
 	}
 
+	for resourceName, collector := range client.collectors {
+		usage, err := collector.PodUsage(context.TODO(), pod)
+		if err != nil {
+			if _, ok := err.(*notSupportedError); ok {
+				return nil, err
+			}
+			return nil, fmt.Errorf("unable to get %q usage of pod %v/%v: %v", resourceName, pod.Namespace, pod.Name, err)
+		}
+		totalUsage[resourceName] = usage
+	}
+
+	if client._podUsage == nil {
+		client._podUsage = make(map[types.NamespacedName]api.ReferencedResourceList)
+	}
+	client._podUsage[key] = copyUsage(totalUsage)
 	return totalUsage, nil
 }
 
-func (client *actualUsageClient) sync(ctx context.Context, nodes []*v1.Node) error {
-	client._nodeUtilization = make(map[string]api.ReferencedResourceList)
+func (client *actualUsageClient) Sync(ctx context.Context, nodes []*v1.Node) error {
+	client._nodeUtilization = make(map[string]compactUsage)
 	client._pods = make(map[string][]*v1.Pod)
+	client._podUsage = make(map[types.NamespacedName]api.ReferencedResourceList)
 
-	nodesUsage, err := client.metricsCollector.AllNodesUsage()
+	nodesUsage, err := client.nodesUsage()
 	if err != nil {
 		return err
 	}
 
+	collectedUsages := map[v1.ResourceName]map[string]*resource.Quantity{}
+	for resourceName, collector := range client.collectors {
+		usages, err := collector.NodesUsage(ctx, nodes)
+		if err != nil {
+			return fmt.Errorf("unable to collect %q usage: %v", resourceName, err)
+		}
+		collectedUsages[resourceName] = usages
+	}
+
 	for _, node := range nodes {
 		pods, err := podutil.ListPodsOnANode(node.Name, client.getPodsAssignedToNode, nil)
 		if err != nil {
@@ -207,11 +659,20 @@ func (client *actualUsageClient) sync(ctx context.Context, nodes []*v1.Node) err
 			return fmt.Errorf("error accessing %q node's pods: %v", node.Name, err)
 		}
 
+		if samples := client.metricsCollector.NodeSamples(node.Name); samples < client.minSamples {
+			return fmt.Errorf("node %q has %v metrics samples collected, %v are required", node.Name, samples, client.minSamples)
+		}
+
 		collectedNodeUsage, ok := nodesUsage[node.Name]
 		if !ok {
 			return fmt.Errorf("unable to find node %q in the collected metrics", node.Name)
 		}
 		collectedNodeUsage[v1.ResourcePods] = resource.NewQuantity(int64(len(pods)), resource.DecimalSI)
+		for resourceName, usages := range collectedUsages {
+			if usage, ok := usages[node.Name]; ok {
+				collectedNodeUsage[resourceName] = usage
+			}
+		}
 
 		nodeUsage := api.ReferencedResourceList{}
 		for _, resourceName := range client.resourceNames {
@@ -222,51 +683,281 @@ func (client *actualUsageClient) sync(ctx context.Context, nodes []*v1.Node) err
 		}
 		// store the snapshot of pods from the same (or the closest) node utilization computation
 		client._pods[node.Name] = pods
-		client._nodeUtilization[node.Name] = nodeUsage
+		client._nodeUtilization[node.Name] = newCompactUsage(client.resourceNames, nodeUsage)
 	}
 
 	return nil
 }
 
+// nodesUsage returns the node usage collected by the metrics collector,
+// smoothed with the configured factor if any.
+func (client *actualUsageClient) nodesUsage() (map[string]api.ReferencedResourceList, error) {
+	if client.smoothingFactor == 0 {
+		return client.metricsCollector.AllNodesUsage()
+	}
+	return client.metricsCollector.SmoothedNodesUsage(client.smoothingFactor), nil
+}
+
+// defaultPrometheusNodesPerQuery is the number of nodes queried at once when
+// the prometheus query refers to the nodes being processed.
+const defaultPrometheusNodesPerQuery = 100
+
+// defaultPrometheusQueryTimeout bounds every attempt of a prometheus query
+// when no other timeout is configured.
+const defaultPrometheusQueryTimeout = 10 * time.Second
+
+// defaultPrometheusRetryBackoff is the delay before the first retry of a
+// failed prometheus query when no other backoff is configured.
+const defaultPrometheusRetryBackoff = time.Second
+
+// defaultPrometheusRangeStep is the resolution of the prometheus range
+// queries when no other step is configured.
+const defaultPrometheusRangeStep = time.Minute
+
+// defaultPrometheusNodeLabel is the label of the prometheus samples holding
+// the name of the node when no other label is configured.
+const defaultPrometheusNodeLabel = "instance"
+
+// PrometheusNodeNameFunc maps the value of the node label of a prometheus
+// sample to the name of the node the sample is about, e.g. to strip a port
+// or a domain suffix from the label value.
+type PrometheusNodeNameFunc func(labelValue string) string
+
+// prometheusResourceNames are the resources the prometheus usage client
+// reports usage for.
+var prometheusResourceNames = []v1.ResourceName{MetricResource}
+
+// prometheusQueryData is the data available to prometheus query templates.
+type prometheusQueryData struct {
+	// Nodes is a regular expression matching the names of the nodes
+	// being queried, e.g. `node1|node2|node3`. It is escaped to be used
+	// within a double-quoted matcher, e.g. `instance=~"{{.Nodes}}"`.
+	Nodes string
+}
+
+// prometheusPodQueryData is the data available to prometheus pod query
+// templates.
+type prometheusPodQueryData struct {
+	// Namespace is the namespace of the pod being queried.
+	Namespace string
+	// Pod is the name of the pod being queried.
+	Pod string
+}
+
 type prometheusUsageClient struct {
 	getPodsAssignedToNode podutil.GetPodsAssignedToNodeFunc
 	promClient            promapi.Client
 	promQuery             string
+	nodesPerQuery         int
+	nodeLabel             string
+	nodeName              PrometheusNodeNameFunc
+	podQuery              string
+	timeout               time.Duration
+	retries               int
+	backoff               time.Duration
+	// queryRange, when set, makes the queries range queries whose series
+	// are aggregated into single samples.
+	queryRange *PrometheusRange
 
 	_pods            map[string][]*v1.Pod
-	_nodeUtilization map[string]map[v1.ResourceName]*resource.Quantity
+	_nodeUtilization map[string]compactUsage
+	// _podUsage caches the pod usage queried since the last sync so
+	// prometheus is queried at most once per pod during a cycle.
+	_podUsage map[types.NamespacedName]api.ReferencedResourceList
 }
 
-var _ usageClient = &actualUsageClient{}
+var _ UsageClient = &actualUsageClient{}
 
 func newPrometheusUsageClient(
 	getPodsAssignedToNode podutil.GetPodsAssignedToNodeFunc,
 	promClient promapi.Client,
 	promQuery string,
+	nodesPerQuery int,
+	nodeLabel string,
+	nodeName PrometheusNodeNameFunc,
+	podQuery string,
+	opts ...prometheusUsageClientOption,
 ) *prometheusUsageClient {
-	return &prometheusUsageClient{
+	if nodesPerQuery <= 0 {
+		nodesPerQuery = defaultPrometheusNodesPerQuery
+	}
+	client := &prometheusUsageClient{
 		getPodsAssignedToNode: getPodsAssignedToNode,
 		promClient:            promClient,
 		promQuery:             promQuery,
+		nodesPerQuery:         nodesPerQuery,
+		nodeLabel:             nodeLabel,
+		nodeName:              nodeName,
+		podQuery:              podQuery,
+		timeout:               defaultPrometheusQueryTimeout,
+		backoff:               defaultPrometheusRetryBackoff,
+	}
+	for _, opt := range opts {
+		opt(client)
+	}
+	return client
+}
+
+// prometheusUsageClientOption configures optional behavior of the prometheus
+// usage client.
+type prometheusUsageClientOption func(*prometheusUsageClient)
+
+// withPrometheusQueryTimeout bounds every attempt of a query. Non positive
+// timeouts keep the default one.
+func withPrometheusQueryTimeout(timeout time.Duration) prometheusUsageClientOption {
+	return func(client *prometheusUsageClient) {
+		if timeout > 0 {
+			client.timeout = timeout
+		}
+	}
+}
+
+// withPrometheusRange makes the queries range queries over the window of the
+// provided range. The step and the aggregation of the range default to a
+// minute and to the average.
+func withPrometheusRange(r *PrometheusRange) prometheusUsageClientOption {
+	return func(client *prometheusUsageClient) {
+		if r == nil {
+			return
+		}
+		client.queryRange = r.DeepCopy()
+		if client.queryRange.Step.Duration <= 0 {
+			client.queryRange.Step.Duration = defaultPrometheusRangeStep
+		}
+		if client.queryRange.Aggregation == "" {
+			client.queryRange.Aggregation = PrometheusAggregationAverage
+		}
+	}
+}
+
+// withPrometheusRetries makes failed queries to be attempted again up to
+// retries times, waiting for backoff before the first retry and twice as
+// long before every following one. Non positive backoffs keep the default
+// one.
+func withPrometheusRetries(retries int, backoff time.Duration) prometheusUsageClientOption {
+	return func(client *prometheusUsageClient) {
+		client.retries = max(retries, 0)
+		if backoff > 0 {
+			client.backoff = backoff
+		}
 	}
 }
 
-func (client *prometheusUsageClient) nodeUtilization(node string) map[v1.ResourceName]*resource.Quantity {
-	return client._nodeUtilization[node]
+func (client *prometheusUsageClient) NodeUtilization(node string) map[v1.ResourceName]*resource.Quantity {
+	return client._nodeUtilization[node].resourceList(prometheusResourceNames)
 }
 
-func (client *prometheusUsageClient) pods(node string) []*v1.Pod {
+func (client *prometheusUsageClient) Pods(node string) []*v1.Pod {
 	return client._pods[node]
 }
 
-func (client *prometheusUsageClient) podUsage(pod *v1.Pod) (map[v1.ResourceName]*resource.Quantity, error) {
-	return nil, newNotSupportedError(prometheusUsageClientType)
+// PodUsage returns the share of the node the pod uses, as reported by the pod
+// query. The samples of the query are summed up so queries reporting the
+// usage of every container of the pod can be used as is. Without a pod query
+// the pod usage is not supported.
+func (client *prometheusUsageClient) PodUsage(pod *v1.Pod) (map[v1.ResourceName]*resource.Quantity, error) {
+	if client.podQuery == "" {
+		return nil, newNotSupportedError(prometheusUsageClientType)
+	}
+
+	key := types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}
+	if usage, ok := client._podUsage[key]; ok {
+		return copyUsage(usage), nil
+	}
+
+	tmpl, err := template.New("podQuery").Parse(client.podQuery)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse prometheus pod query template: %v", err)
+	}
+	var query strings.Builder
+	if err := tmpl.Execute(&query, prometheusPodQueryData{Namespace: pod.Namespace, Pod: pod.Name}); err != nil {
+		return nil, fmt.Errorf("unable to render prometheus pod query template: %v", err)
+	}
+
+	samples, err := client.query(context.TODO(), query.String())
+	if err != nil {
+		return nil, err
+	}
+	if len(samples) == 0 {
+		return nil, fmt.Errorf("unable to find metric entry for %v/%v", pod.Namespace, pod.Name)
+	}
+
+	var value float64
+	for _, sample := range samples {
+		value += float64(sample.Value)
+	}
+	// written as a negated range check so NaN samples are rejected too.
+	if !(value >= 0 && value <= 1) {
+		return nil, fmt.Errorf("The collected metrics samples for %v/%v sum up to %v outside of <0; 1> interval", pod.Namespace, pod.Name, value)
+	}
+
+	// the usage is kept in milli units, pods using less than a percent of
+	// the node would otherwise be accounted as using nothing.
+	usage := api.ReferencedResourceList{
+		MetricResource: resource.NewMilliQuantity(int64(math.Round(value*100*1000)), resource.DecimalSI),
+	}
+	if client._podUsage == nil {
+		client._podUsage = make(map[types.NamespacedName]api.ReferencedResourceList)
+	}
+	client._podUsage[key] = copyUsage(usage)
+	return usage, nil
+}
+
+// NodeUsageFromPrometheusMetrics runs the query and returns the usage of the
+// nodes it reports. The nodes are identified by the value of the nodeLabel
+// label of the samples, "instance" when empty, translated into node names by
+// nodeName when provided.
+func NodeUsageFromPrometheusMetrics(
+	ctx context.Context,
+	promClient promapi.Client,
+	promQuery string,
+	nodeLabel string,
+	nodeName PrometheusNodeNameFunc,
+) (map[string]map[v1.ResourceName]*resource.Quantity, error) {
+	samples, err := queryPrometheusVector(ctx, promClient, promQuery)
+	if err != nil {
+		return nil, err
+	}
+	return nodeUsagesFromSamples(samples, nodeLabel, nodeName)
+}
+
+// nodeUsagesFromSamples returns the usage of the nodes the samples report,
+// see NodeUsageFromPrometheusMetrics.
+func nodeUsagesFromSamples(
+	samples model.Vector, nodeLabel string, nodeName PrometheusNodeNameFunc,
+) (map[string]map[v1.ResourceName]*resource.Quantity, error) {
+	if nodeLabel == "" {
+		nodeLabel = defaultPrometheusNodeLabel
+	}
+
+	nodeUsages := make(map[string]map[v1.ResourceName]*resource.Quantity)
+	for _, sample := range samples {
+		labelValue, exists := sample.Metric[model.LabelName(nodeLabel)]
+		if !exists {
+			return nil, fmt.Errorf("The collected metrics sample is missing '%v' key", nodeLabel)
+		}
+		node := string(labelValue)
+		if nodeName != nil {
+			node = nodeName(node)
+		}
+		// written as a negated range check so NaN samples are rejected too.
+		if !(sample.Value >= 0 && sample.Value <= 1) {
+			return nil, fmt.Errorf("The collected metrics sample for %q has value %v outside of <0; 1> interval", node, sample.Value)
+		}
+		nodeUsages[node] = map[v1.ResourceName]*resource.Quantity{
+			MetricResource: resource.NewQuantity(int64(sample.Value*100), resource.DecimalSI),
+		}
+	}
+
+	return nodeUsages, nil
 }
 
-func NodeUsageFromPrometheusMetrics(ctx context.Context, promClient promapi.Client, promQuery string) (map[string]map[v1.ResourceName]*resource.Quantity, error) {
+// queryPrometheusVector runs the instant query and returns the samples of
+// the resulting vector.
+func queryPrometheusVector(ctx context.Context, promClient promapi.Client, promQuery string) (model.Vector, error) {
 	results, warnings, err := promv1.NewAPI(promClient).Query(ctx, promQuery, time.Now())
 	if err != nil {
-		return nil, fmt.Errorf("unable to capture prometheus metrics: %v", err)
+		return nil, fmt.Errorf("unable to capture prometheus metrics: %w", err)
 	}
 	if len(warnings) > 0 {
 		klog.Infof("prometheus metrics warnings: %v", warnings)
@@ -275,29 +966,157 @@ func NodeUsageFromPrometheusMetrics(ctx context.Context, promClient promapi.Clie
 	if results.Type() != model.ValVector {
 		return nil, fmt.Errorf("expected query results to be of type %q, got %q instead", model.ValVector, results.Type())
 	}
+	return results.(model.Vector), nil
+}
 
-	nodeUsages := make(map[string]map[v1.ResourceName]*resource.Quantity)
-	for _, sample := range results.(model.Vector) {
-		nodeName, exists := sample.Metric["instance"]
-		if !exists {
-			return nil, fmt.Errorf("The collected metrics sample is missing 'instance' key")
+// query runs the instant query. Failed attempts are retried with an
+// exponential backoff, except for the queries prometheus rejects as invalid,
+// and every attempt is bounded by the client timeout.
+func (client *prometheusUsageClient) query(ctx context.Context, promQuery string) (model.Vector, error) {
+	var samples model.Vector
+	var queryErr error
+	attempt := 0
+	backoff := wait.Backoff{Duration: client.backoff, Factor: 2, Steps: client.retries + 1}
+	err := wait.ExponentialBackoffWithContext(ctx, backoff, func(ctx context.Context) (bool, error) {
+		attempt++
+		attemptCtx, cancel := context.WithTimeout(ctx, client.timeout)
+		defer cancel()
+		if client.queryRange != nil {
+			samples, queryErr = queryPrometheusRange(attemptCtx, client.promClient, promQuery, client.queryRange)
+		} else {
+			samples, queryErr = queryPrometheusVector(attemptCtx, client.promClient, promQuery)
 		}
-		if sample.Value < 0 || sample.Value > 1 {
-			return nil, fmt.Errorf("The collected metrics sample for %q has value %v outside of <0; 1> interval", string(nodeName), sample.Value)
+		if queryErr == nil {
+			return true, nil
 		}
-		nodeUsages[string(nodeName)] = map[v1.ResourceName]*resource.Quantity{
-			MetricResource: resource.NewQuantity(int64(sample.Value*100), resource.DecimalSI),
+		var promErr *promv1.Error
+		if errors.As(queryErr, &promErr) && promErr.Type == promv1.ErrBadData {
+			return false, queryErr
+		}
+		klog.V(2).InfoS("Prometheus query failed", "attempt", attempt, "err", queryErr)
+		return false, nil
+	})
+	if err != nil {
+		// out of attempts, the error of the last one is more telling.
+		if wait.Interrupted(err) && queryErr != nil {
+			return nil, queryErr
+		}
+		return nil, err
+	}
+	return samples, nil
+}
+
+// queryPrometheusRange runs the query over the window of the range, ending
+// now, and aggregates the samples of every resulting series into a single
+// sample. Series without samples are left out.
+func queryPrometheusRange(ctx context.Context, promClient promapi.Client, promQuery string, r *PrometheusRange) (model.Vector, error) {
+	now := time.Now()
+	results, warnings, err := promv1.NewAPI(promClient).QueryRange(ctx, promQuery, promv1.Range{
+		Start: now.Add(-r.Window.Duration),
+		End:   now,
+		Step:  r.Step.Duration,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to capture prometheus metrics: %w", err)
+	}
+	if len(warnings) > 0 {
+		klog.Infof("prometheus metrics warnings: %v", warnings)
+	}
+
+	if results.Type() != model.ValMatrix {
+		return nil, fmt.Errorf("expected query results to be of type %q, got %q instead", model.ValMatrix, results.Type())
+	}
+	var samples model.Vector
+	for _, series := range results.(model.Matrix) {
+		if len(series.Values) == 0 {
+			continue
+		}
+		samples = append(samples, &model.Sample{
+			Metric:    series.Metric,
+			Value:     aggregateSamples(series.Values, r.Aggregation),
+			Timestamp: series.Values[len(series.Values)-1].Timestamp,
+		})
+	}
+	return samples, nil
+}
+
+// aggregateSamples turns the samples of a series into a single value. The
+// 95th percentile is the nearest rank one.
+func aggregateSamples(values []model.SamplePair, aggregation PrometheusAggregation) model.SampleValue {
+	switch aggregation {
+	case PrometheusAggregationMax:
+		highest := values[0].Value
+		for _, value := range values[1:] {
+			highest = max(highest, value.Value)
+		}
+		return highest
+	case PrometheusAggregationP95:
+		sorted := make([]model.SampleValue, 0, len(values))
+		for _, value := range values {
+			sorted = append(sorted, value.Value)
+		}
+		slices.Sort(sorted)
+		return sorted[int(math.Ceil(0.95*float64(len(sorted))))-1]
+	default:
+		var sum model.SampleValue
+		for _, value := range values {
+			sum += value.Value
+		}
+		return sum / model.SampleValue(len(values))
+	}
+}
+
+// nodeUsages collects the usage of the provided nodes. If the query refers to
+// the nodes through a template placeholder they are queried in groups, this
+// bounds the number of round trips while keeping each query reasonably
+// sized. Otherwise the query is issued once for all the nodes.
+func (client *prometheusUsageClient) nodeUsages(ctx context.Context, nodes []*v1.Node) (map[string]map[v1.ResourceName]*resource.Quantity, error) {
+	if !strings.Contains(client.promQuery, "{{") {
+		samples, err := client.query(ctx, client.promQuery)
+		if err != nil {
+			return nil, err
 		}
+		return nodeUsagesFromSamples(samples, client.nodeLabel, client.nodeName)
+	}
+
+	tmpl, err := template.New("query").Parse(client.promQuery)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse prometheus query template: %v", err)
 	}
 
+	nodeUsages := make(map[string]map[v1.ResourceName]*resource.Quantity)
+	for group := range slices.Chunk(nodes, client.nodesPerQuery) {
+		names := make([]string, 0, len(group))
+		for _, node := range group {
+			// the backslashes escaping the regular expression are
+			// escaped once more for the double-quoted string literal.
+			names = append(names, strings.ReplaceAll(regexp.QuoteMeta(node.Name), `\`, `\\`))
+		}
+
+		var query strings.Builder
+		if err := tmpl.Execute(&query, prometheusQueryData{Nodes: strings.Join(names, "|")}); err != nil {
+			return nil, fmt.Errorf("unable to render prometheus query template: %v", err)
+		}
+
+		samples, err := client.query(ctx, query.String())
+		if err != nil {
+			return nil, err
+		}
+		groupUsages, err := nodeUsagesFromSamples(samples, client.nodeLabel, client.nodeName)
+		if err != nil {
+			return nil, err
+		}
+		maps.Copy(nodeUsages, groupUsages)
+	}
 	return nodeUsages, nil
 }
 
-func (client *prometheusUsageClient) sync(ctx context.Context, nodes []*v1.Node) error {
-	client._nodeUtilization = make(map[string]map[v1.ResourceName]*resource.Quantity)
+func (client *prometheusUsageClient) Sync(ctx context.Context, nodes []*v1.Node) error {
+	client._nodeUtilization = make(map[string]compactUsage)
 	client._pods = make(map[string][]*v1.Pod)
+	client._podUsage = make(map[types.NamespacedName]api.ReferencedResourceList)
 
-	nodeUsages, err := NodeUsageFromPrometheusMetrics(ctx, client.promClient, client.promQuery)
+	nodeUsages, err := client.nodeUsages(ctx, nodes)
 	if err != nil {
 		return err
 	}
@@ -314,8 +1133,847 @@ func (client *prometheusUsageClient) sync(ctx context.Context, nodes []*v1.Node)
 
 		// store the snapshot of pods from the same (or the closest) node utilization computation
 		client._pods[node.Name] = pods
-		client._nodeUtilization[node.Name] = nodeUsages[node.Name]
+		client._nodeUtilization[node.Name] = newCompactUsage(prometheusResourceNames, nodeUsages[node.Name])
 	}
 
 	return nil
 }
+
+// defaultExternalMetricNodeLabel is the label of the external metrics holding
+// the name of the node when no other label is configured.
+const defaultExternalMetricNodeLabel = "node"
+
+// customMetricsResourceNames are the resources the custom metrics usage
+// client reports usage for.
+var customMetricsResourceNames = []v1.ResourceName{MetricResource}
+
+// metricValueList is a list of values of a metric as served by the custom
+// metrics API, where values describe objects, or by the external metrics
+// API, where values are labeled. The metrics API types are not vendored,
+// lists are decoded from the custom.metrics.k8s.io/v1beta2 and the
+// external.metrics.k8s.io/v1beta1 APIs.
+type metricValueList struct {
+	Items []metricValue `json:"items"`
+}
+
+type metricValue struct {
+	DescribedObject v1.ObjectReference `json:"describedObject"`
+	MetricLabels    map[string]string  `json:"metricLabels,omitempty"`
+	Value           resource.Quantity  `json:"value"`
+}
+
+// customMetricsUsageClient reads the utilization of the nodes from a metric
+// served through the custom metrics API or the external metrics API, e.g. by
+// Prometheus Adapter, so the descheduler needs no direct access to the
+// monitoring system. Like with prometheus, values are expected to be the
+// share of the node in use.
+type customMetricsUsageClient struct {
+	getPodsAssignedToNode podutil.GetPodsAssignedToNodeFunc
+	client                rest.Interface
+	metricName            string
+	podMetricName         string
+	selector              string
+	external              *ExternalMetric
+
+	_pods            map[string][]*v1.Pod
+	_nodeUtilization map[string]compactUsage
+	// _podUsage caches the pod usage read since the last sync so the
+	// metrics api is queried at most once per pod during a cycle.
+	_podUsage map[types.NamespacedName]api.ReferencedResourceList
+}
+
+var _ UsageClient = &customMetricsUsageClient{}
+
+func newCustomMetricsUsageClient(
+	getPodsAssignedToNode podutil.GetPodsAssignedToNodeFunc,
+	client rest.Interface,
+	metrics *CustomMetrics,
+) (*customMetricsUsageClient, error) {
+	selector := ""
+	if metrics.Selector != nil {
+		s, err := metav1.LabelSelectorAsSelector(metrics.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid custom metrics selector: %v", err)
+		}
+		selector = s.String()
+	}
+	return &customMetricsUsageClient{
+		getPodsAssignedToNode: getPodsAssignedToNode,
+		client:                client,
+		metricName:            metrics.MetricName,
+		podMetricName:         metrics.PodMetricName,
+		selector:              selector,
+		external:              metrics.External,
+	}, nil
+}
+
+func (client *customMetricsUsageClient) NodeUtilization(node string) api.ReferencedResourceList {
+	return client._nodeUtilization[node].resourceList(customMetricsResourceNames)
+}
+
+func (client *customMetricsUsageClient) Pods(node string) []*v1.Pod {
+	return client._pods[node]
+}
+
+// PodUsage returns the share of the node the pod uses, as reported by the
+// pod metric. Without a pod metric the pod usage is not supported.
+func (client *customMetricsUsageClient) PodUsage(pod *v1.Pod) (api.ReferencedResourceList, error) {
+	if client.podMetricName == "" || client.external != nil {
+		return nil, newNotSupportedError(customMetricsUsageClientType)
+	}
+
+	key := types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}
+	if usage, ok := client._podUsage[key]; ok {
+		return copyUsage(usage), nil
+	}
+
+	values, err := client.list(
+		context.TODO(),
+		"/apis/custom.metrics.k8s.io/v1beta2/namespaces/"+pod.Namespace+"/pods/"+pod.Name,
+		client.podMetricName,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("unable to find metric entry for %v/%v", pod.Namespace, pod.Name)
+	}
+
+	var value float64
+	for _, metric := range values {
+		value += metric.Value.AsApproximateFloat64()
+	}
+	// written as a negated range check so NaN values are rejected too.
+	if !(value >= 0 && value <= 1) {
+		return nil, fmt.Errorf("The collected metrics values for %v/%v sum up to %v outside of <0; 1> interval", pod.Namespace, pod.Name, value)
+	}
+
+	// the usage is kept in milli units, pods using less than a percent of
+	// the node would otherwise be accounted as using nothing.
+	usage := api.ReferencedResourceList{
+		MetricResource: resource.NewMilliQuantity(int64(math.Round(value*100*1000)), resource.DecimalSI),
+	}
+	if client._podUsage == nil {
+		client._podUsage = make(map[types.NamespacedName]api.ReferencedResourceList)
+	}
+	client._podUsage[key] = copyUsage(usage)
+	return usage, nil
+}
+
+func (client *customMetricsUsageClient) Sync(ctx context.Context, nodes []*v1.Node) error {
+	client._nodeUtilization = make(map[string]compactUsage)
+	client._pods = make(map[string][]*v1.Pod)
+	client._podUsage = make(map[types.NamespacedName]api.ReferencedResourceList)
+
+	nodeUsages, err := client.nodeUsages(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, node := range nodes {
+		if _, exists := nodeUsages[node.Name]; !exists {
+			return fmt.Errorf("unable to find metric entry for %v", node.Name)
+		}
+		pods, err := podutil.ListPodsOnANode(node.Name, client.getPodsAssignedToNode, nil)
+		if err != nil {
+			klog.V(2).InfoS("Node will not be processed, error accessing its pods", "node", klog.KObj(node), "err", err)
+			return fmt.Errorf("error accessing %q node's pods: %v", node.Name, err)
+		}
+
+		// store the snapshot of pods from the same (or the closest) node utilization computation
+		client._pods[node.Name] = pods
+		client._nodeUtilization[node.Name] = newCompactUsage(customMetricsResourceNames, nodeUsages[node.Name])
+	}
+
+	return nil
+}
+
+// nodeUsages reads the usage of all the nodes the metric describes, or is
+// labeled with for external metrics.
+func (client *customMetricsUsageClient) nodeUsages(ctx context.Context) (map[string]api.ReferencedResourceList, error) {
+	path := "/apis/custom.metrics.k8s.io/v1beta2/nodes/*"
+	nodeLabel := ""
+	if client.external != nil {
+		path = "/apis/external.metrics.k8s.io/v1beta1/namespaces/" + client.external.Namespace
+		nodeLabel = client.external.NodeLabel
+		if nodeLabel == "" {
+			nodeLabel = defaultExternalMetricNodeLabel
+		}
+	}
+
+	values, err := client.list(ctx, path, client.metricName)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeUsages := make(map[string]api.ReferencedResourceList)
+	for _, metric := range values {
+		nodeName := metric.DescribedObject.Name
+		if client.external != nil {
+			var exists bool
+			if nodeName, exists = metric.MetricLabels[nodeLabel]; !exists {
+				return nil, fmt.Errorf("The collected metrics value is missing '%v' label", nodeLabel)
+			}
+		}
+		value := metric.Value.AsApproximateFloat64()
+		if !(value >= 0 && value <= 1) {
+			return nil, fmt.Errorf("The collected metrics value for %q has value %v outside of <0; 1> interval", nodeName, value)
+		}
+		nodeUsages[nodeName] = api.ReferencedResourceList{
+			MetricResource: resource.NewQuantity(int64(value*100), resource.DecimalSI),
+		}
+	}
+	return nodeUsages, nil
+}
+
+// list returns the values of the metric served under the provided path.
+func (client *customMetricsUsageClient) list(ctx context.Context, path, metricName string) ([]metricValue, error) {
+	request := client.client.Get().AbsPath(path, metricName)
+	if client.selector != "" {
+		request = request.Param("labelSelector", client.selector)
+	}
+	raw, err := request.Do(ctx).Raw()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get %q metric: %v", metricName, err)
+	}
+
+	var list metricValueList
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return nil, fmt.Errorf("unable to decode %q metric: %v", metricName, err)
+	}
+	return list.Items, nil
+}
+
+const (
+	// defaultOpenTelemetryNodeAttribute is the attribute of the
+	// OpenTelemetry metrics holding the name of the node when no other
+	// attribute is configured.
+	defaultOpenTelemetryNodeAttribute = "k8s.node.name"
+	// openTelemetryNamespaceAttribute and openTelemetryPodAttribute are
+	// the attributes the pod metric tells the pods apart by.
+	openTelemetryNamespaceAttribute = "k8s.namespace.name"
+	openTelemetryPodAttribute       = "k8s.pod.name"
+	// defaultOpenTelemetryScrapeTimeout bounds a single scrape of the
+	// collector.
+	defaultOpenTelemetryScrapeTimeout = 10 * time.Second
+)
+
+// openTelemetryResourceNames are the resources the OpenTelemetry usage
+// client reports usage for.
+var openTelemetryResourceNames = []v1.ResourceName{MetricResource}
+
+// openTelemetryRatioSuffix is the suffix the prometheus exporter of the
+// collector appends to the names of the gauges whose unit is a ratio.
+const openTelemetryRatioSuffix = "_ratio"
+
+// invalidPrometheusNameChars matches the characters the prometheus exporter
+// of the collector replaces with underscores in metric and label names.
+var invalidPrometheusNameChars = regexp.MustCompile(`[^a-zA-Z0-9_:]`)
+
+// prometheusName translates an OpenTelemetry metric or attribute name into
+// the name the prometheus exporter of the collector exposes it under.
+func prometheusName(name string) string {
+	return invalidPrometheusNameChars.ReplaceAllString(name, "_")
+}
+
+// metricFamily returns the family of the metric, exposed with or without the
+// unit suffix depending on the add_metric_suffixes setting of the exporter.
+func metricFamily(families map[string]*dto.MetricFamily, name string) *dto.MetricFamily {
+	if family, ok := families[name+openTelemetryRatioSuffix]; ok {
+		return family
+	}
+	return families[name]
+}
+
+// openTelemetryUsageClient reads the utilization of the nodes from an
+// OpenTelemetry collector, so clusters instrumented with OpenTelemetry need
+// no prometheus server in between. The collector is scraped once per sync,
+// both the node and the pod metrics are read out of the same scrape. Like
+// with prometheus, values are expected to be the share of the node in use.
+type openTelemetryUsageClient struct {
+	getPodsAssignedToNode podutil.GetPodsAssignedToNodeFunc
+	httpClient            *http.Client
+	endpoint              string
+	metricName            string
+	nodeLabel             string
+	labels                map[string]string
+	aggregation           OpenTelemetryAggregation
+	podMetricName         string
+
+	_pods            map[string][]*v1.Pod
+	_nodeUtilization map[string]compactUsage
+	_podUsage        map[types.NamespacedName]float64
+}
+
+var _ UsageClient = &openTelemetryUsageClient{}
+
+func newOpenTelemetryUsageClient(
+	getPodsAssignedToNode podutil.GetPodsAssignedToNodeFunc,
+	httpClient *http.Client,
+	metrics *OpenTelemetryMetrics,
+) *openTelemetryUsageClient {
+	nodeAttribute := metrics.NodeAttribute
+	if nodeAttribute == "" {
+		nodeAttribute = defaultOpenTelemetryNodeAttribute
+	}
+	podMetricName := ""
+	if metrics.PodMetricName != "" {
+		podMetricName = prometheusName(metrics.PodMetricName)
+	}
+	labels := make(map[string]string, len(metrics.Attributes))
+	for attribute, value := range metrics.Attributes {
+		labels[prometheusName(attribute)] = value
+	}
+	aggregation := metrics.Aggregation
+	if aggregation == "" {
+		aggregation = OpenTelemetryAggregationAverage
+	}
+	return &openTelemetryUsageClient{
+		getPodsAssignedToNode: getPodsAssignedToNode,
+		httpClient:            httpClient,
+		endpoint:              metrics.Endpoint,
+		metricName:            prometheusName(metrics.MetricName),
+		nodeLabel:             prometheusName(nodeAttribute),
+		labels:                labels,
+		aggregation:           aggregation,
+		podMetricName:         podMetricName,
+	}
+}
+
+func (client *openTelemetryUsageClient) NodeUtilization(node string) api.ReferencedResourceList {
+	return client._nodeUtilization[node].resourceList(openTelemetryResourceNames)
+}
+
+func (client *openTelemetryUsageClient) Pods(node string) []*v1.Pod {
+	return client._pods[node]
+}
+
+// PodUsage returns the share of the node the pod uses, as reported by the
+// pod metric in the last scrape. Without a pod metric the pod usage is not
+// supported.
+func (client *openTelemetryUsageClient) PodUsage(pod *v1.Pod) (api.ReferencedResourceList, error) {
+	if client.podMetricName == "" {
+		return nil, newNotSupportedError(openTelemetryUsageClientType)
+	}
+	value, ok := client._podUsage[types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}]
+	if !ok {
+		return nil, fmt.Errorf("unable to find metric entry for %v/%v", pod.Namespace, pod.Name)
+	}
+	if !(value >= 0 && value <= 1) {
+		return nil, fmt.Errorf("The collected metrics values for %v/%v sum up to %v outside of <0; 1> interval", pod.Namespace, pod.Name, value)
+	}
+	// the usage is kept in milli units, pods using less than a percent of
+	// the node would otherwise be accounted as using nothing.
+	return api.ReferencedResourceList{
+		MetricResource: resource.NewMilliQuantity(int64(math.Round(value*100*1000)), resource.DecimalSI),
+	}, nil
+}
+
+func (client *openTelemetryUsageClient) Sync(ctx context.Context, nodes []*v1.Node) error {
+	client._nodeUtilization = make(map[string]compactUsage)
+	client._pods = make(map[string][]*v1.Pod)
+	client._podUsage = make(map[types.NamespacedName]float64)
+
+	families, err := client.scrape(ctx)
+	if err != nil {
+		return err
+	}
+
+	nodeUsages := make(map[string]api.ReferencedResourceList)
+	nodeValues, err := aggregateMetricValues(metricFamily(families, client.metricName), client.nodeLabel, client.labels, client.aggregation)
+	if err != nil {
+		return err
+	}
+	for nodeName, value := range nodeValues {
+		if !(value >= 0 && value <= 1) {
+			return fmt.Errorf("The collected metrics value for %q has value %v outside of <0; 1> interval", nodeName, value)
+		}
+		nodeUsages[nodeName] = api.ReferencedResourceList{
+			MetricResource: resource.NewQuantity(int64(value*100), resource.DecimalSI),
+		}
+	}
+
+	if client.podMetricName != "" {
+		namespaceLabel, podLabel := prometheusName(openTelemetryNamespaceAttribute), prometheusName(openTelemetryPodAttribute)
+		for _, metric := range metricFamily(families, client.podMetricName).GetMetric() {
+			labels := seriesLabels(metric)
+			if labels[namespaceLabel] == "" || labels[podLabel] == "" {
+				continue
+			}
+			value, ok := seriesValue(metric)
+			if !ok {
+				continue
+			}
+			client._podUsage[types.NamespacedName{Namespace: labels[namespaceLabel], Name: labels[podLabel]}] += value
+		}
+	}
+
+	for _, node := range nodes {
+		if _, exists := nodeUsages[node.Name]; !exists {
+			return fmt.Errorf("unable to find metric entry for %v", node.Name)
+		}
+		pods, err := podutil.ListPodsOnANode(node.Name, client.getPodsAssignedToNode, nil)
+		if err != nil {
+			klog.V(2).InfoS("Node will not be processed, error accessing its pods", "node", klog.KObj(node), "err", err)
+			return fmt.Errorf("error accessing %q node's pods: %v", node.Name, err)
+		}
+
+		// store the snapshot of pods from the same (or the closest) node utilization computation
+		client._pods[node.Name] = pods
+		client._nodeUtilization[node.Name] = newCompactUsage(openTelemetryResourceNames, nodeUsages[node.Name])
+	}
+
+	return nil
+}
+
+// scrape reads all the metric families the collector exposes.
+func (client *openTelemetryUsageClient) scrape(ctx context.Context) (map[string]*dto.MetricFamily, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, client.endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create the opentelemetry collector request: %v", err)
+	}
+	request.Header.Set("Accept", string(expfmt.NewFormat(expfmt.TypeTextPlain)))
+	response, err := client.httpClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("unable to scrape the opentelemetry collector: %v", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to scrape the opentelemetry collector: unexpected status %v", response.Status)
+	}
+
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the opentelemetry collector metrics: %v", err)
+	}
+	return families, nil
+}
+
+// aggregateMetricValues combines the values of the series of the family
+// carrying the provided labels by the value of the node label. Series
+// describing the same node, e.g. one per cpu, are averaged or added up.
+func aggregateMetricValues(
+	family *dto.MetricFamily, nodeLabel string, labels map[string]string, aggregation OpenTelemetryAggregation,
+) (map[string]float64, error) {
+	values := make(map[string]float64)
+	counts := make(map[string]int)
+	for _, metric := range family.GetMetric() {
+		value, ok := seriesValue(metric)
+		if !ok {
+			continue
+		}
+		series := seriesLabels(metric)
+		if !matchesLabels(series, labels) {
+			continue
+		}
+		key, exists := series[nodeLabel]
+		if !exists {
+			return nil, fmt.Errorf("The collected metrics value is missing '%v' label, resource attributes are only exported as labels with resource_to_telemetry_conversion enabled", nodeLabel)
+		}
+		values[key] += value
+		counts[key]++
+	}
+	if aggregation == OpenTelemetryAggregationAverage {
+		for key, count := range counts {
+			values[key] /= float64(count)
+		}
+	}
+	return values, nil
+}
+
+// matchesLabels tells if the series carries all the provided labels.
+func matchesLabels(series, labels map[string]string) bool {
+	for name, value := range labels {
+		if series[name] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// seriesLabels returns the labels of the series as a map.
+func seriesLabels(metric *dto.Metric) map[string]string {
+	labels := make(map[string]string, len(metric.GetLabel()))
+	for _, pair := range metric.GetLabel() {
+		labels[pair.GetName()] = pair.GetValue()
+	}
+	return labels
+}
+
+// seriesValue returns the value of a gauge or untyped series, the only kinds
+// describing a share of a node.
+func seriesValue(metric *dto.Metric) (float64, bool) {
+	switch {
+	case metric.Gauge != nil:
+		return metric.Gauge.GetValue(), true
+	case metric.Untyped != nil:
+		return metric.Untyped.GetValue(), true
+	}
+	return 0, false
+}
+
+// defaultStaticUsageConfigMapKey is the key of the ConfigMap data holding
+// the static usage document when no other key is configured.
+const defaultStaticUsageConfigMapKey = "usage"
+
+// StaticUsageLoader returns the raw static usage document, see StaticUsage.
+type StaticUsageLoader func(ctx context.Context) ([]byte, error)
+
+// StaticUsageFromFile returns a loader reading the static usage document
+// from the provided file.
+func StaticUsageFromFile(path string) StaticUsageLoader {
+	return func(context.Context) ([]byte, error) {
+		return os.ReadFile(path)
+	}
+}
+
+// StaticUsageFromConfigMap returns a loader reading the static usage
+// document from the provided key of a ConfigMap.
+func StaticUsageFromConfigMap(client clientset.Interface, namespace, name, key string) StaticUsageLoader {
+	if key == "" {
+		key = defaultStaticUsageConfigMapKey
+	}
+	return func(ctx context.Context) ([]byte, error) {
+		configMap, err := client.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		document, ok := configMap.Data[key]
+		if !ok {
+			return nil, fmt.Errorf("%v/%v ConfigMap has no %q key", namespace, name, key)
+		}
+		return []byte(document), nil
+	}
+}
+
+// staticUsageClient reports the utilization read from a static document
+// rather than from a metrics provider. It makes the descheduler decisions
+// reproducible, e.g. in integration tests or when exercising what the
+// descheduler would do with a synthetic cluster state.
+type staticUsageClient struct {
+	resourceNames         []v1.ResourceName
+	getPodsAssignedToNode podutil.GetPodsAssignedToNodeFunc
+	load                  StaticUsageLoader
+
+	_pods            map[string][]*v1.Pod
+	_nodeUtilization map[string]compactUsage
+	_usage           *StaticUsage
+}
+
+var _ UsageClient = &staticUsageClient{}
+
+// NewStaticUsageClient returns a usage client reporting the utilization of
+// the provided resources out of the document returned by load. The document
+// is loaded again on every sync.
+func NewStaticUsageClient(
+	resourceNames []v1.ResourceName,
+	getPodsAssignedToNode podutil.GetPodsAssignedToNodeFunc,
+	load StaticUsageLoader,
+) UsageClient {
+	return &staticUsageClient{
+		resourceNames:         resourceNames,
+		getPodsAssignedToNode: getPodsAssignedToNode,
+		load:                  load,
+	}
+}
+
+func (client *staticUsageClient) NodeUtilization(node string) api.ReferencedResourceList {
+	return client._nodeUtilization[node].resourceList(client.resourceNames)
+}
+
+func (client *staticUsageClient) Pods(node string) []*v1.Pod {
+	return client._pods[node]
+}
+
+func (client *staticUsageClient) PodUsage(pod *v1.Pod) (api.ReferencedResourceList, error) {
+	podUsage := client.podUsage(pod)
+	usage := make(api.ReferencedResourceList)
+	for _, resourceName := range client.resourceNames {
+		if quantity, ok := podUsage[resourceName]; ok {
+			usage[resourceName] = utilptr.To[resource.Quantity](quantity.DeepCopy())
+		}
+	}
+	return usage, nil
+}
+
+func (client *staticUsageClient) Sync(ctx context.Context, nodes []*v1.Node) error {
+	client._nodeUtilization = make(map[string]compactUsage)
+	client._pods = make(map[string][]*v1.Pod)
+
+	raw, err := client.load(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to load the static usage: %v", err)
+	}
+	usage := &StaticUsage{}
+	if err := yaml.UnmarshalStrict(raw, usage); err != nil {
+		return fmt.Errorf("unable to decode the static usage: %v", err)
+	}
+	client._usage = usage
+
+	for _, node := range nodes {
+		pods, err := podutil.ListPodsOnANode(node.Name, client.getPodsAssignedToNode, nil)
+		if err != nil {
+			klog.V(2).InfoS("Node will not be processed, error accessing its pods", "node", klog.KObj(node), "err", err)
+			return fmt.Errorf("error accessing %q node's pods: %v", node.Name, err)
+		}
+
+		nodeUsage, err := nodeutil.NodeUtilization(pods, client.resourceNames, func(pod *v1.Pod) (v1.ResourceList, error) {
+			return client.podUsage(pod), nil
+		})
+		if err != nil {
+			return err
+		}
+		for name, quantity := range usage.Nodes[node.Name] {
+			if _, ok := nodeUsage[name]; ok {
+				nodeUsage[name] = utilptr.To[resource.Quantity](quantity.DeepCopy())
+			}
+		}
+
+		// store the snapshot of pods from the same (or the closest) node utilization computation
+		client._pods[node.Name] = pods
+		client._nodeUtilization[node.Name] = newCompactUsage(client.resourceNames, nodeUsage)
+	}
+
+	return nil
+}
+
+// podUsage returns the usage the document lists for the pod, completed with
+// the pod requests for the resources it does not list.
+func (client *staticUsageClient) podUsage(pod *v1.Pod) v1.ResourceList {
+	usage, _ := utils.PodRequestsAndLimits(pod)
+	if client._usage == nil {
+		return usage
+	}
+	for name, quantity := range client._usage.Pods[pod.Namespace+"/"+pod.Name] {
+		usage[name] = quantity
+	}
+	return usage
+}
+
+// vpaRecommendationUsageClient computes the utilization of the nodes from
+// the targets the VerticalPodAutoscalers recommend for the containers of the
+// pods rather than from their requests. Recommendations follow the actual
+// usage over time, they give a steadier signal than the instantaneous usage
+// where the requests are badly tuned. Pods, containers and resources without
+// a recommendation are accounted through their requests.
+type vpaRecommendationUsageClient struct {
+	resourceNames         []v1.ResourceName
+	getPodsAssignedToNode podutil.GetPodsAssignedToNodeFunc
+	vpaLister             vpa.Lister
+	// devices, when set, accounts the devices allocated to the pods
+	// through Dynamic Resource Allocation as part of their requests.
+	devices *nodeutil.DeviceAccounting
+
+	_pods            map[string][]*v1.Pod
+	_nodeUtilization map[string]compactUsage
+}
+
+var _ UsageClient = &vpaRecommendationUsageClient{}
+
+func newVPARecommendationUsageClient(
+	resourceNames []v1.ResourceName,
+	getPodsAssignedToNode podutil.GetPodsAssignedToNodeFunc,
+	vpaLister vpa.Lister,
+	devices *nodeutil.DeviceAccounting,
+) *vpaRecommendationUsageClient {
+	return &vpaRecommendationUsageClient{
+		resourceNames:         resourceNames,
+		getPodsAssignedToNode: getPodsAssignedToNode,
+		vpaLister:             vpaLister,
+		devices:               devices,
+	}
+}
+
+func (client *vpaRecommendationUsageClient) NodeUtilization(node string) api.ReferencedResourceList {
+	return client._nodeUtilization[node].resourceList(client.resourceNames)
+}
+
+func (client *vpaRecommendationUsageClient) Pods(node string) []*v1.Pod {
+	return client._pods[node]
+}
+
+func (client *vpaRecommendationUsageClient) PodUsage(pod *v1.Pod) (api.ReferencedResourceList, error) {
+	recommended, err := client.recommendedPod(context.TODO(), pod)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := make(api.ReferencedResourceList)
+	for _, resourceName := range client.resourceNames {
+		usage[resourceName] = utilptr.To[resource.Quantity](utils.GetResourceRequestQuantity(recommended, resourceName).DeepCopy())
+	}
+	for name, quantity := range client.devices.PodRequests(pod) {
+		if _, ok := usage[name]; ok {
+			usage[name] = utilptr.To[resource.Quantity](quantity.DeepCopy())
+		}
+	}
+	return usage, nil
+}
+
+func (client *vpaRecommendationUsageClient) Sync(ctx context.Context, nodes []*v1.Node) error {
+	client._nodeUtilization = make(map[string]compactUsage)
+	client._pods = make(map[string][]*v1.Pod)
+
+	for _, node := range nodes {
+		pods, err := podutil.ListPodsOnANode(node.Name, client.getPodsAssignedToNode, nil)
+		if err != nil {
+			klog.V(2).InfoS("Node will not be processed, error accessing its pods", "node", klog.KObj(node), "err", err)
+			return fmt.Errorf("error accessing %q node's pods: %v", node.Name, err)
+		}
+
+		nodeUsage, err := nodeutil.NodeUtilization(pods, client.resourceNames, func(pod *v1.Pod) (v1.ResourceList, error) {
+			recommended, err := client.recommendedPod(ctx, pod)
+			if err != nil {
+				return nil, err
+			}
+			req, _ := utils.PodRequestsAndLimits(recommended)
+			for name, quantity := range client.devices.PodRequests(pod) {
+				req[name] = quantity
+			}
+			return req, nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// store the snapshot of pods from the same (or the closest) node utilization computation
+		client._pods[node.Name] = pods
+		client._nodeUtilization[node.Name] = newCompactUsage(client.resourceNames, nodeUsage)
+	}
+
+	return nil
+}
+
+// recommendedPod returns a copy of the pod whose container requests are
+// replaced by the targets recommended by the first VerticalPodAutoscaler
+// targeting the pod workload. The pod itself is returned if there is no
+// recommendation for any of its containers.
+func (client *vpaRecommendationUsageClient) recommendedPod(ctx context.Context, pod *v1.Pod) (*v1.Pod, error) {
+	vpas, err := client.vpaLister.List(ctx, pod.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list VerticalPodAutoscalers: %v", err)
+	}
+
+	for _, autoscaler := range vpas {
+		if !autoscaler.Targets(pod) {
+			continue
+		}
+
+		recommended := pod
+		for i, container := range pod.Spec.Containers {
+			recommendation := autoscaler.ContainerRecommendation(container.Name)
+			if recommendation == nil || len(recommendation.Target) == 0 {
+				continue
+			}
+			if recommended == pod {
+				recommended = pod.DeepCopy()
+			}
+			resources := &recommended.Spec.Containers[i].Resources
+			if resources.Requests == nil {
+				resources.Requests = v1.ResourceList{}
+			}
+			for name, target := range recommendation.Target {
+				resources.Requests[name] = target
+			}
+		}
+		return recommended, nil
+	}
+	return pod, nil
+}
+
+// compositeUsageClient wraps several usage clients and blends the usage they
+// report with per client weights, e.g. to balance nodes based on a mix of the
+// pod requests and of the actual utilization. The usage of a resource is the
+// weighted average of the usage reported for it by the wrapped clients.
+type compositeUsageClient struct {
+	clients []UsageClient
+	weights []float64
+}
+
+var _ UsageClient = &compositeUsageClient{}
+
+// newCompositeUsageClient returns a usage client blending the usage of the
+// provided clients, the i-th weight applies to the i-th client.
+func newCompositeUsageClient(clients []UsageClient, weights []float64) *compositeUsageClient {
+	return &compositeUsageClient{
+		clients: clients,
+		weights: weights,
+	}
+}
+
+func (c *compositeUsageClient) Sync(ctx context.Context, nodes []*v1.Node) error {
+	for _, client := range c.clients {
+		if err := client.Sync(ctx, nodes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *compositeUsageClient) NodeUtilization(node string) api.ReferencedResourceList {
+	usages := make([]api.ReferencedResourceList, len(c.clients))
+	for i, client := range c.clients {
+		usages[i] = client.NodeUtilization(node)
+	}
+	return c.blend(usages)
+}
+
+// Pods returns the pods of the node as seen by the first client. All the
+// clients list the pods of a node the same way.
+func (c *compositeUsageClient) Pods(node string) []*v1.Pod {
+	return c.clients[0].Pods(node)
+}
+
+// PodUsage blends the usage of the pod reported by the clients supporting
+// it. If none of the clients does the usage is not supported either.
+func (c *compositeUsageClient) PodUsage(pod *v1.Pod) (api.ReferencedResourceList, error) {
+	usages := make([]api.ReferencedResourceList, len(c.clients))
+	supported := false
+	for i, client := range c.clients {
+		usage, err := client.PodUsage(pod)
+		if err != nil {
+			if _, ok := err.(*notSupportedError); ok {
+				continue
+			}
+			return nil, err
+		}
+		usages[i], supported = usage, true
+	}
+	if !supported {
+		return nil, newNotSupportedError(compositeUsageClientType)
+	}
+	return c.blend(usages), nil
+}
+
+// blend returns the weighted average of the provided usages, the i-th usage
+// being reported by the i-th client. A resource missing from some of the
+// usages is averaged among the usages reporting it.
+func (c *compositeUsageClient) blend(usages []api.ReferencedResourceList) api.ReferencedResourceList {
+	sums := map[v1.ResourceName]float64{}
+	weights := map[v1.ResourceName]float64{}
+	for i, usage := range usages {
+		for name, quantity := range usage {
+			if quantity == nil || c.weights[i] == 0 {
+				continue
+			}
+			value := quantity.Value()
+			if name == v1.ResourceCPU {
+				value = quantity.MilliValue()
+			}
+			sums[name] += c.weights[i] * float64(value)
+			weights[name] += c.weights[i]
+		}
+	}
+
+	blended := api.ReferencedResourceList{}
+	for name, sum := range sums {
+		value := int64(math.Round(sum / weights[name]))
+		if name == v1.ResourceCPU {
+			blended[name] = resource.NewMilliQuantity(value, resource.DecimalSI)
+			continue
+		}
+		blended[name] = resource.NewQuantity(value, utils.ResourceQuantityFormat(name))
+	}
+	return blended
+}
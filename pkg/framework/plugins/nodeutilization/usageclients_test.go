@@ -18,25 +18,50 @@ package nodeutilization
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"io"
+	"math"
 	"net/http"
-	"net/url"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/prometheus/common/model"
 
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	v1 "k8s.io/api/core/v1"
+	resourcev1beta1 "k8s.io/api/resource/v1beta1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/informers"
 	fakeclientset "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	restfake "k8s.io/client-go/rest/fake"
+	core "k8s.io/client-go/testing"
+	"k8s.io/component-base/metrics/testutil"
 	"k8s.io/metrics/pkg/apis/metrics/v1beta1"
 	fakemetricsclient "k8s.io/metrics/pkg/client/clientset/versioned/fake"
+	"k8s.io/utils/ptr"
 
+	"sigs.k8s.io/descheduler/metrics"
+	"sigs.k8s.io/descheduler/pkg/api"
 	"sigs.k8s.io/descheduler/pkg/descheduler/metricscollector"
+	nodeutil "sigs.k8s.io/descheduler/pkg/descheduler/node"
 	podutil "sigs.k8s.io/descheduler/pkg/descheduler/pod"
+	"sigs.k8s.io/descheduler/pkg/descheduler/vpa"
+	frameworkfake "sigs.k8s.io/descheduler/pkg/framework/fake"
+	frameworktesting "sigs.k8s.io/descheduler/pkg/framework/testing"
+	frameworktypes "sigs.k8s.io/descheduler/pkg/framework/types"
 	"sigs.k8s.io/descheduler/test"
 )
 
@@ -51,7 +76,7 @@ func updateMetricsAndCheckNodeUtilization(
 	newValue, expectedValue int64,
 	metricsClientset *fakemetricsclient.Clientset,
 	collector *metricscollector.MetricsCollector,
-	usageClient usageClient,
+	usageClient UsageClient,
 	nodes []*v1.Node,
 	nodeName string,
 	nodemetrics *v1beta1.NodeMetrics,
@@ -63,16 +88,16 @@ func updateMetricsAndCheckNodeUtilization(
 	if err != nil {
 		t.Fatalf("failed to capture metrics: %v", err)
 	}
-	err = usageClient.sync(ctx, nodes)
+	err = usageClient.Sync(ctx, nodes)
 	if err != nil {
 		t.Fatalf("failed to sync a snapshot: %v", err)
 	}
-	nodeUtilization := usageClient.nodeUtilization(nodeName)
+	nodeUtilization := usageClient.NodeUtilization(nodeName)
 	t.Logf("current node cpu usage: %v\n", nodeUtilization[v1.ResourceCPU].MilliValue())
 	if nodeUtilization[v1.ResourceCPU].MilliValue() != expectedValue {
 		t.Fatalf("cpu node usage expected to be %v, got %v instead", expectedValue, nodeUtilization[v1.ResourceCPU].MilliValue())
 	}
-	pods := usageClient.pods(nodeName)
+	pods := usageClient.Pods(nodeName)
 	fmt.Printf("pods: %#v\n", pods)
 	if len(pods) != 2 {
 		t.Fatalf("expected 2 pods for node %v, got %v instead", nodeName, len(pods))
@@ -125,6 +150,9 @@ func TestActualUsageClient(t *testing.T) {
 		resourceNames,
 		podsAssignedToNode,
 		collector,
+		0,
+		0,
+		nil,
 	)
 
 	updateMetricsAndCheckNodeUtilization(t, ctx,
@@ -143,46 +171,69 @@ func TestActualUsageClient(t *testing.T) {
 	)
 }
 
-type fakePromClient struct {
-	result   interface{}
-	dataType model.ValueType
-}
+func TestActualUsageClientSmoothing(t *testing.T) {
+	n1 := test.BuildTestNode("n1", 2000, 3000, 10, nil)
+	n2 := test.BuildTestNode("n2", 2000, 3000, 10, nil)
 
-type fakePayload struct {
-	Status string      `json:"status"`
-	Data   queryResult `json:"data"`
-}
+	p1 := test.BuildTestPod("p1", 400, 0, n1.Name, nil)
+	p21 := test.BuildTestPod("p21", 400, 0, n2.Name, nil)
+	p22 := test.BuildTestPod("p22", 400, 0, n2.Name, nil)
 
-type queryResult struct {
-	Type   model.ValueType `json:"resultType"`
-	Result interface{}     `json:"result"`
-}
+	nodes := []*v1.Node{n1, n2}
 
-func (client *fakePromClient) URL(ep string, args map[string]string) *url.URL {
-	return &url.URL{}
-}
+	n1metrics := test.BuildNodeMetrics("n1", 400, 1714978816)
+	n2metrics := test.BuildNodeMetrics("n2", 1400, 1714978816)
 
-func (client *fakePromClient) Do(ctx context.Context, request *http.Request) (*http.Response, []byte, error) {
-	jsonData, err := json.Marshal(fakePayload{
-		Status: "success",
-		Data: queryResult{
-			Type:   client.dataType,
-			Result: client.result,
-		},
-	})
+	clientset := fakeclientset.NewSimpleClientset(n1, n2, p1, p21, p22)
+	metricsClientset := fakemetricsclient.NewSimpleClientset()
+	metricsClientset.Tracker().Create(nodesgvr, n1metrics, "")
+	metricsClientset.Tracker().Create(nodesgvr, n2metrics, "")
 
-	return &http.Response{StatusCode: 200}, jsonData, err
-}
+	ctx := context.TODO()
 
-func sample(metricName, nodeName string, value float64) *model.Sample {
-	return &model.Sample{
-		Metric: model.Metric{
-			"__name__": model.LabelValue(metricName),
-			"instance": model.LabelValue(nodeName),
-		},
-		Value:     model.SampleValue(value),
-		Timestamp: 1728991761711,
+	sharedInformerFactory := informers.NewSharedInformerFactory(clientset, 0)
+	podInformer := sharedInformerFactory.Core().V1().Pods().Informer()
+	nodeLister := sharedInformerFactory.Core().V1().Nodes().Lister()
+	podsAssignedToNode, err := podutil.BuildGetPodsAssignedToNodeFunc(podInformer)
+	if err != nil {
+		t.Fatalf("Build get pods assigned to node function error: %v", err)
+	}
+
+	sharedInformerFactory.Start(ctx.Done())
+	sharedInformerFactory.WaitForCacheSync(ctx.Done())
+
+	collector := metricscollector.NewMetricsCollector(nodeLister, metricsClientset, labels.Everything())
+
+	usageClient := newActualUsageClient(
+		[]v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory},
+		podsAssignedToNode,
+		collector,
+		0.5,
+		2,
+		nil,
+	)
+
+	if err := collector.Collect(ctx); err != nil {
+		t.Fatalf("failed to capture metrics: %v", err)
 	}
+	if err := usageClient.Sync(ctx, nodes); err == nil {
+		t.Fatalf("expected the sync to fail with a single metrics sample collected")
+	}
+
+	updateMetricsAndCheckNodeUtilization(t, ctx,
+		600, 1000,
+		metricsClientset, collector, usageClient, nodes, n2.Name, n2metrics,
+	)
+
+	updateMetricsAndCheckNodeUtilization(t, ctx,
+		1000, 1000,
+		metricsClientset, collector, usageClient, nodes, n2.Name, n2metrics,
+	)
+
+	updateMetricsAndCheckNodeUtilization(t, ctx,
+		200, 600,
+		metricsClientset, collector, usageClient, nodes, n2.Name, n2metrics,
+	)
 }
 
 func TestPrometheusUsageClient(t *testing.T) {
@@ -199,18 +250,16 @@ func TestPrometheusUsageClient(t *testing.T) {
 
 	tests := []struct {
 		name      string
-		result    interface{}
-		dataType  model.ValueType
+		result    model.Value
 		nodeUsage map[string]int64
 		err       error
 	}{
 		{
-			name:     "valid data",
-			dataType: model.ValVector,
+			name: "valid data",
 			result: model.Vector{
-				sample("instance:node_cpu:rate:sum", "ip-10-0-51-101.ec2.internal", 0.20381818181818104),
-				sample("instance:node_cpu:rate:sum", "ip-10-0-17-165.ec2.internal", 0.4245454545454522),
-				sample("instance:node_cpu:rate:sum", "ip-10-0-94-25.ec2.internal", 0.5695757575757561),
+				frameworktesting.PrometheusSample("instance:node_cpu:rate:sum", "ip-10-0-51-101.ec2.internal", 0.20381818181818104),
+				frameworktesting.PrometheusSample("instance:node_cpu:rate:sum", "ip-10-0-17-165.ec2.internal", 0.4245454545454522),
+				frameworktesting.PrometheusSample("instance:node_cpu:rate:sum", "ip-10-0-94-25.ec2.internal", 0.5695757575757561),
 			},
 			nodeUsage: map[string]int64{
 				"ip-10-0-51-101.ec2.internal": 20,
@@ -219,8 +268,7 @@ func TestPrometheusUsageClient(t *testing.T) {
 			},
 		},
 		{
-			name:     "invalid data missing instance label",
-			dataType: model.ValVector,
+			name: "invalid data missing instance label",
 			result: model.Vector{
 				&model.Sample{
 					Metric: model.Metric{
@@ -233,17 +281,22 @@ func TestPrometheusUsageClient(t *testing.T) {
 			err: fmt.Errorf("The collected metrics sample is missing 'instance' key"),
 		},
 		{
-			name:     "invalid data value out of range",
-			dataType: model.ValVector,
+			name: "invalid data value out of range",
 			result: model.Vector{
-				sample("instance:node_cpu:rate:sum", "ip-10-0-51-101.ec2.internal", 1.20381818181818104),
+				frameworktesting.PrometheusSample("instance:node_cpu:rate:sum", "ip-10-0-51-101.ec2.internal", 1.20381818181818104),
 			},
 			err: fmt.Errorf("The collected metrics sample for \"ip-10-0-51-101.ec2.internal\" has value 1.203818181818181 outside of <0; 1> interval"),
 		},
 		{
-			name:     "invalid data not a vector",
-			dataType: model.ValScalar,
-			result: model.Scalar{
+			name: "invalid data value not a number",
+			result: model.Vector{
+				frameworktesting.PrometheusSample("instance:node_cpu:rate:sum", "ip-10-0-51-101.ec2.internal", math.NaN()),
+			},
+			err: fmt.Errorf("The collected metrics sample for \"ip-10-0-51-101.ec2.internal\" has value NaN outside of <0; 1> interval"),
+		},
+		{
+			name: "invalid data not a vector",
+			result: &model.Scalar{
 				Value:     model.SampleValue(0.20381818181818104),
 				Timestamp: 1728991761711,
 			},
@@ -253,9 +306,10 @@ func TestPrometheusUsageClient(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			pClient := &fakePromClient{
-				result:   tc.result,
-				dataType: tc.dataType,
+			pClient := &frameworktesting.FakePrometheusClient{
+				Handler: func(string) frameworktesting.PrometheusResponse {
+					return frameworktesting.PrometheusResponse{Result: tc.result}
+				},
 			}
 
 			clientset := fakeclientset.NewSimpleClientset(n1, n2, n3, p1, p21, p22, p3)
@@ -271,8 +325,8 @@ func TestPrometheusUsageClient(t *testing.T) {
 			sharedInformerFactory.Start(ctx.Done())
 			sharedInformerFactory.WaitForCacheSync(ctx.Done())
 
-			prometheusUsageClient := newPrometheusUsageClient(podsAssignedToNode, pClient, "instance:node_cpu:rate:sum")
-			err = prometheusUsageClient.sync(ctx, nodes)
+			prometheusUsageClient := newPrometheusUsageClient(podsAssignedToNode, pClient, "instance:node_cpu:rate:sum", 0, "", nil, "")
+			err = prometheusUsageClient.Sync(ctx, nodes)
 			if tc.err == nil {
 				if err != nil {
 					t.Fatalf("unexpected error: %v", err)
@@ -287,7 +341,7 @@ func TestPrometheusUsageClient(t *testing.T) {
 			}
 
 			for _, node := range nodes {
-				nodeUtil := prometheusUsageClient.nodeUtilization(node.Name)
+				nodeUtil := prometheusUsageClient.NodeUtilization(node.Name)
 				if nodeUtil[MetricResource].Value() != tc.nodeUsage[node.Name] {
 					t.Fatalf("expected %q node utilization to be %v, got %v instead", node.Name, tc.nodeUsage[node.Name], nodeUtil[MetricResource])
 				} else {
@@ -297,3 +351,1691 @@ func TestPrometheusUsageClient(t *testing.T) {
 		})
 	}
 }
+
+func TestRecordUtilizationDeltas(t *testing.T) {
+	metrics.Register()
+
+	ctx := context.TODO()
+	n1 := test.BuildTestNode("n1", 2000, 3000, 10, nil)
+	p1 := test.BuildTestPod("p1", 400, 0, n1.Name, nil)
+	p2 := test.BuildTestPod("p2", 400, 0, n1.Name, nil)
+	p3 := test.BuildTestPod("p3", 400, 0, n1.Name, nil)
+
+	pods := []*v1.Pod{p1, p2, p3}
+	getPodsAssignedToNode := func(string, podutil.FilterFunc) ([]*v1.Pod, error) {
+		return pods, nil
+	}
+
+	usageClient := newRequestedUsageClient([]v1.ResourceName{v1.ResourceCPU}, getPodsAssignedToNode, nil, nil)
+	if err := usageClient.Sync(ctx, []*v1.Node{n1}); err != nil {
+		t.Fatalf("failed to sync a snapshot: %v", err)
+	}
+
+	nodeInfo := NodeInfo{
+		NodeUsage: NodeUsage{
+			node:  n1,
+			usage: usageClient.NodeUtilization(n1.Name),
+		},
+	}
+	preEvictionUsage := copyNodesUsage([]NodeInfo{nodeInfo})
+
+	capacities := referencedResourceListForNodesCapacity([]*v1.Node{n1})
+
+	// two pods are evicted, the model predicts a 40% drop in cpu usage
+	// but only one of them is gone by the time the next cycle starts.
+	deltas := &pendingUtilizationDeltas{}
+	summary := newBalanceSummary("test")
+	for _, pod := range []*v1.Pod{p1, p2} {
+		nodeInfo.usage[v1.ResourceCPU].Sub(*pod.Spec.Containers[0].Resources.Requests.Cpu())
+		summary.podEvicted(n1.Name)
+	}
+	recordUtilizationDeltas(deltas, []NodeInfo{nodeInfo}, preEvictionUsage, capacities, summary)
+	if len(summary.deltas) != 0 {
+		t.Fatalf("expected the deltas to be measured on the next cycle, got %v", summary.deltas)
+	}
+
+	pods = []*v1.Pod{p2, p3}
+	if err := usageClient.Sync(ctx, []*v1.Node{n1}); err != nil {
+		t.Fatalf("failed to sync a snapshot: %v", err)
+	}
+	usage := map[string]api.ReferencedResourceList{n1.Name: usageClient.NodeUtilization(n1.Name)}
+	summary = newBalanceSummary("test")
+	measureUtilizationDeltas(deltas, usage, capacities, summary)
+
+	delta, ok := summary.deltas[n1.Name]
+	if !ok {
+		t.Fatalf("expected a utilization delta to be recorded for %v", n1.Name)
+	}
+	if delta.predicted[v1.ResourceCPU] != 40 {
+		t.Errorf("expected predicted cpu delta to be 40, got %v", delta.predicted[v1.ResourceCPU])
+	}
+	if delta.achieved[v1.ResourceCPU] != 20 {
+		t.Errorf("expected achieved cpu delta to be 20, got %v", delta.achieved[v1.ResourceCPU])
+	}
+	if len(deltas.series) != 1 {
+		t.Errorf("expected the gauge series to be kept for deletion, got %v", deltas.series)
+	}
+	labels := map[string]string{"strategy": "test", "resource": string(v1.ResourceCPU)}
+	if value, err := testutil.GetGaugeMetricValue(metrics.BalanceAchievedUtilizationDelta.With(labels)); err != nil || value != 20 {
+		t.Errorf("expected the achieved cpu delta gauge to be 20, got %v (%v)", value, err)
+	}
+
+	// no pod was evicted since, nothing is measured and the series of
+	// the previous measurement are deleted.
+	summary = newBalanceSummary("test")
+	measureUtilizationDeltas(deltas, usage, capacities, summary)
+	if len(summary.deltas) != 0 || len(deltas.series) != 0 {
+		t.Errorf("expected no delta to be measured, got %v", summary.deltas)
+	}
+}
+
+func TestRequestedUsageClientSidecars(t *testing.T) {
+	ctx := context.TODO()
+	n1 := test.BuildTestNode("n1", 2000, 3000, 10, nil)
+	p1 := test.BuildTestPod("p1", 400, 0, n1.Name, func(pod *v1.Pod) {
+		pod.Spec.InitContainers = []v1.Container{
+			{
+				Name:          "sidecar",
+				RestartPolicy: ptr.To(v1.ContainerRestartPolicyAlways),
+				Resources: v1.ResourceRequirements{
+					Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("100m")},
+				},
+			},
+		}
+	})
+	p2 := test.BuildTestPod("p2", 400, 0, n1.Name, nil)
+
+	getPodsAssignedToNode := func(string, podutil.FilterFunc) ([]*v1.Pod, error) {
+		return []*v1.Pod{p1, p2}, nil
+	}
+
+	usageClient := newRequestedUsageClient([]v1.ResourceName{v1.ResourceCPU}, getPodsAssignedToNode, nil, nil)
+	if err := usageClient.Sync(ctx, []*v1.Node{n1}); err != nil {
+		t.Fatalf("failed to sync a snapshot: %v", err)
+	}
+
+	// the sidecar runs for the whole lifetime of the pod, its request
+	// is part of both the pod and the node usage.
+	if cpu := usageClient.NodeUtilization(n1.Name)[v1.ResourceCPU].MilliValue(); cpu != 900 {
+		t.Errorf("expected node cpu usage to be 900m, got %vm", cpu)
+	}
+	usage, err := usageClient.PodUsage(p1)
+	if err != nil {
+		t.Fatalf("failed to get the pod usage: %v", err)
+	}
+	if cpu := usage[v1.ResourceCPU].MilliValue(); cpu != 500 {
+		t.Errorf("expected pod cpu usage to be 500m, got %vm", cpu)
+	}
+}
+
+func TestRequestedUsageClientIncrementalSync(t *testing.T) {
+	ctx := context.TODO()
+	n1 := test.BuildTestNode("n1", 2000, 3000, 10, nil)
+	n1.ResourceVersion = "1"
+	p1 := test.BuildTestPod("p1", 400, 0, n1.Name, nil)
+	p1.ResourceVersion = "1"
+	p2 := test.BuildTestPod("p2", 400, 0, n1.Name, nil)
+	p2.ResourceVersion = "1"
+
+	pods := []*v1.Pod{p1, p2}
+	getPodsAssignedToNode := func(string, podutil.FilterFunc) ([]*v1.Pod, error) {
+		return pods, nil
+	}
+
+	snapshots := nodeUsageSnapshots{}
+	stored := func() *nodeUsageSnapshot {
+		return snapshots[n1.Name]
+	}
+
+	usageClient := newRequestedUsageClient([]v1.ResourceName{v1.ResourceCPU}, getPodsAssignedToNode, nil, snapshots)
+	if err := usageClient.Sync(ctx, []*v1.Node{n1}); err != nil {
+		t.Fatalf("failed to sync a snapshot: %v", err)
+	}
+	snapshot := stored()
+	if snapshot == nil {
+		t.Fatalf("expected a snapshot to be stored for %v", n1.Name)
+	}
+
+	// the usage handed out by the client is modified during evictions,
+	// this must not leak into the stored snapshot. the clients are
+	// created again on every cycle, the snapshots outlive them.
+	usageClient.NodeUtilization(n1.Name)[v1.ResourceCPU].Sub(resource.MustParse("400m"))
+	usageClient = newRequestedUsageClient([]v1.ResourceName{v1.ResourceCPU}, getPodsAssignedToNode, nil, snapshots)
+	if err := usageClient.Sync(ctx, []*v1.Node{n1}); err != nil {
+		t.Fatalf("failed to sync a snapshot: %v", err)
+	}
+	if stored() != snapshot {
+		t.Errorf("expected the snapshot to be reused when nothing changed")
+	}
+	if cpu := usageClient.NodeUtilization(n1.Name)[v1.ResourceCPU].MilliValue(); cpu != 800 {
+		t.Errorf("expected cpu usage to be 800m, got %vm", cpu)
+	}
+
+	p2 = p2.DeepCopy()
+	p2.ResourceVersion = "2"
+	p2.Spec.Containers[0].Resources.Requests[v1.ResourceCPU] = resource.MustParse("600m")
+	pods = []*v1.Pod{p1, p2}
+	if err := usageClient.Sync(ctx, []*v1.Node{n1}); err != nil {
+		t.Fatalf("failed to sync a snapshot: %v", err)
+	}
+	if stored() == snapshot {
+		t.Errorf("expected the snapshot to be recomputed after a pod changed")
+	}
+	if cpu := usageClient.NodeUtilization(n1.Name)[v1.ResourceCPU].MilliValue(); cpu != 1000 {
+		t.Errorf("expected cpu usage to be 1000m, got %vm", cpu)
+	}
+
+	pods = []*v1.Pod{p1}
+	if err := usageClient.Sync(ctx, []*v1.Node{n1}); err != nil {
+		t.Fatalf("failed to sync a snapshot: %v", err)
+	}
+	if cpu := usageClient.NodeUtilization(n1.Name)[v1.ResourceCPU].MilliValue(); cpu != 400 {
+		t.Errorf("expected cpu usage to be 400m, got %vm", cpu)
+	}
+
+	// the snapshots are only kept for the nodes being processed.
+	n2 := test.BuildTestNode("n2", 2000, 3000, 10, nil)
+	n2.ResourceVersion = "1"
+	if err := usageClient.Sync(ctx, []*v1.Node{n2}); err != nil {
+		t.Fatalf("failed to sync a snapshot: %v", err)
+	}
+	if _, ok := snapshots[n1.Name]; ok || len(snapshots) != 1 {
+		t.Errorf("expected only the snapshot of n2 to be kept, got %v", snapshots)
+	}
+}
+
+func TestRequestedUsageClientIncrementalSyncDevices(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const driver = "gpu.example.com"
+	n1 := test.BuildTestNode("n1", 2000, 3000, 10, nil)
+	n1.ResourceVersion = "1"
+	claim := &resourcev1beta1.ResourceClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "p1-gpu", Namespace: "default", ResourceVersion: "1"},
+	}
+	p1 := test.BuildTestPod("p1", 400, 0, n1.Name, func(pod *v1.Pod) {
+		pod.ResourceVersion = "1"
+		pod.Spec.ResourceClaims = []v1.PodResourceClaim{{Name: "gpu", ResourceClaimName: ptr.To(claim.Name)}}
+	})
+	getPodsAssignedToNode := func(string, podutil.FilterFunc) ([]*v1.Pod, error) {
+		return []*v1.Pod{p1}, nil
+	}
+
+	client := fakeclientset.NewSimpleClientset(claim)
+	sharedInformerFactory := informers.NewSharedInformerFactory(client, 0)
+	devices, err := nodeutil.NewDeviceAccounting(sharedInformerFactory)
+	if err != nil {
+		t.Fatalf("Build device accounting error: %v", err)
+	}
+	claimLister := sharedInformerFactory.Resource().V1beta1().ResourceClaims().Lister()
+	sharedInformerFactory.Start(ctx.Done())
+	sharedInformerFactory.WaitForCacheSync(ctx.Done())
+
+	snapshots := nodeUsageSnapshots{}
+	resourceNames := []v1.ResourceName{v1.ResourceCPU, driver}
+	usageClient := newRequestedUsageClient(resourceNames, getPodsAssignedToNode, devices, snapshots)
+	if err := usageClient.Sync(ctx, []*v1.Node{n1}); err != nil {
+		t.Fatalf("failed to sync a snapshot: %v", err)
+	}
+	if gpus := usageClient.NodeUtilization(n1.Name)[driver].Value(); gpus != 0 {
+		t.Errorf("expected no device to be used, got %v", gpus)
+	}
+
+	// the claim is allocated, neither the node nor the pod change.
+	claim = claim.DeepCopy()
+	claim.ResourceVersion = "2"
+	claim.Status.Allocation = &resourcev1beta1.AllocationResult{
+		Devices: resourcev1beta1.DeviceAllocationResult{
+			Results: []resourcev1beta1.DeviceRequestAllocationResult{
+				{Request: "gpu", Driver: driver, Pool: n1.Name, Device: "gpu-0"},
+			},
+		},
+	}
+	if _, err := client.ResourceV1beta1().ResourceClaims(claim.Namespace).Update(ctx, claim, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("unable to update the resource claim: %v", err)
+	}
+	if err := wait.PollUntilContextTimeout(ctx, 10*time.Millisecond, 5*time.Second, true, func(context.Context) (bool, error) {
+		cached, err := claimLister.ResourceClaims(claim.Namespace).Get(claim.Name)
+		return err == nil && cached.ResourceVersion == "2", nil
+	}); err != nil {
+		t.Fatalf("resource claim update not observed: %v", err)
+	}
+
+	usageClient = newRequestedUsageClient(resourceNames, getPodsAssignedToNode, devices, snapshots)
+	if err := usageClient.Sync(ctx, []*v1.Node{n1}); err != nil {
+		t.Fatalf("failed to sync a snapshot: %v", err)
+	}
+	if gpus := usageClient.NodeUtilization(n1.Name)[driver].Value(); gpus != 1 {
+		t.Errorf("expected the allocated device to be used, got %v", gpus)
+	}
+}
+
+func TestPrometheusUsageClientTemplatedQuery(t *testing.T) {
+	n1 := test.BuildTestNode("ip-10-0-17-165.ec2.internal", 2000, 3000, 10, nil)
+	n2 := test.BuildTestNode("ip-10-0-51-101.ec2.internal", 2000, 3000, 10, nil)
+	n3 := test.BuildTestNode("ip-10-0-94-25.ec2.internal", 2000, 3000, 10, nil)
+	nodes := []*v1.Node{n1, n2, n3}
+
+	usages := map[string]float64{
+		n1.Name: 0.42,
+		n2.Name: 0.20,
+		n3.Name: 0.56,
+	}
+	// answer queries with samples for the nodes the instance matcher of
+	// the query matches, the same way prometheus evaluates it.
+	var matchErrs []error
+	pClient := &frameworktesting.FakePrometheusClient{
+		Handler: func(query string) frameworktesting.PrometheusResponse {
+			matcher, err := parseRegexpMatcher(query, "instance")
+			if err != nil {
+				matchErrs = append(matchErrs, err)
+				return frameworktesting.PrometheusResponse{Err: err}
+			}
+			result := model.Vector{}
+			for node, usage := range usages {
+				if matcher.MatchString(node) {
+					result = append(result, frameworktesting.PrometheusSample("instance:node_cpu:rate:sum", node, usage))
+				}
+			}
+			return frameworktesting.PrometheusResponse{Result: result}
+		},
+	}
+	getPodsAssignedToNode := func(string, podutil.FilterFunc) ([]*v1.Pod, error) {
+		return nil, nil
+	}
+
+	usageClient := newPrometheusUsageClient(
+		getPodsAssignedToNode,
+		pClient,
+		`instance:node_cpu:rate:sum{instance=~"{{.Nodes}}"}`,
+		2,
+		"",
+		nil,
+		"",
+	)
+	if err := usageClient.Sync(context.TODO(), nodes); err != nil {
+		t.Fatalf("unexpected error: %v (query errors: %v)", err, matchErrs)
+	}
+
+	expectedQueries := []string{
+		`instance:node_cpu:rate:sum{instance=~"ip-10-0-17-165\\.ec2\\.internal|ip-10-0-51-101\\.ec2\\.internal"}`,
+		`instance:node_cpu:rate:sum{instance=~"ip-10-0-94-25\\.ec2\\.internal"}`,
+	}
+	if !reflect.DeepEqual(pClient.Queries(), expectedQueries) {
+		t.Fatalf("expected queries %v, got %v instead", expectedQueries, pClient.Queries())
+	}
+
+	expectedUsage := map[string]int64{n1.Name: 42, n2.Name: 20, n3.Name: 56}
+	for _, node := range nodes {
+		if usage := usageClient.NodeUtilization(node.Name)[MetricResource].Value(); usage != expectedUsage[node.Name] {
+			t.Errorf("expected %q node utilization to be %v, got %v instead", node.Name, expectedUsage[node.Name], usage)
+		}
+	}
+}
+
+// parseRegexpMatcher extracts the regular expression matcher of the label
+// from the query the way the promql parser does: the double-quoted string
+// literal is unquoted following the Go escaping rules and the expression is
+// anchored on both ends.
+func parseRegexpMatcher(query, label string) (*regexp.Regexp, error) {
+	start := strings.Index(query, label+`=~"`)
+	if start == -1 {
+		return nil, fmt.Errorf("no %q regexp matcher found in %q", label, query)
+	}
+	literal := query[start+len(label)+2:]
+	end := 1
+	for ; end < len(literal); end++ {
+		if literal[end] == '\\' {
+			end++
+			continue
+		}
+		if literal[end] == '"' {
+			break
+		}
+	}
+	if end >= len(literal) {
+		return nil, fmt.Errorf("unterminated string literal in %q", query)
+	}
+	value, err := strconv.Unquote(literal[:end+1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid string literal %s in %q: %v", literal[:end+1], query, err)
+	}
+	return regexp.Compile("^(?:" + value + ")$")
+}
+
+func TestParseRegexpMatcher(t *testing.T) {
+	// the single escaped form is what regexp.QuoteMeta produces, it is
+	// not a valid double-quoted string literal.
+	if _, err := parseRegexpMatcher(`up{instance=~"ip-10-0-17-165\.ec2\.internal"}`, "instance"); err == nil {
+		t.Errorf("expected single escaped dots to fail to parse")
+	}
+	matcher, err := parseRegexpMatcher(`up{instance=~"ip-10-0-17-165\\.ec2\\.internal"}`, "instance")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !matcher.MatchString("ip-10-0-17-165.ec2.internal") || matcher.MatchString("ip-10-0-17-165xec2.internal") {
+		t.Errorf("unexpected matcher %v", matcher)
+	}
+}
+
+func TestPrometheusUsageClientScriptedResponses(t *testing.T) {
+	n1 := test.BuildTestNode("n1", 2000, 3000, 10, nil)
+	n2 := test.BuildTestNode("n2", 2000, 3000, 10, nil)
+	nodes := []*v1.Node{n1, n2}
+	getPodsAssignedToNode := func(string, podutil.FilterFunc) ([]*v1.Pod, error) {
+		return nil, nil
+	}
+	query := "instance:node_cpu:rate:sum"
+	samples := model.Vector{
+		frameworktesting.PrometheusSample(query, n1.Name, 0.42),
+		frameworktesting.PrometheusSample(query, n2.Name, 0.20),
+	}
+
+	tests := []struct {
+		name     string
+		response frameworktesting.PrometheusResponse
+		timeout  time.Duration
+		err      bool
+	}{
+		{
+			name:     "results with warnings",
+			response: frameworktesting.PrometheusResponse{Result: samples, Warnings: []string{"partial response"}},
+		},
+		{
+			name:     "slow response within the deadline",
+			response: frameworktesting.PrometheusResponse{Result: samples, Latency: 10 * time.Millisecond},
+			timeout:  time.Minute,
+		},
+		{
+			name:     "slow response past the deadline",
+			response: frameworktesting.PrometheusResponse{Result: samples, Latency: time.Minute},
+			timeout:  10 * time.Millisecond,
+			err:      true,
+		},
+		{
+			name:     "transport error",
+			response: frameworktesting.PrometheusResponse{Err: fmt.Errorf("connection refused")},
+			err:      true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tc.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tc.timeout)
+				defer cancel()
+			}
+
+			pClient := &frameworktesting.FakePrometheusClient{
+				Responses: map[string]frameworktesting.PrometheusResponse{query: tc.response},
+			}
+			usageClient := newPrometheusUsageClient(getPodsAssignedToNode, pClient, query, 0, "", nil, "")
+			err := usageClient.Sync(ctx, nodes)
+			if tc.err {
+				if err == nil {
+					t.Fatalf("expected an error, got nil instead")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(pClient.Queries(), []string{query}) {
+				t.Errorf("expected queries %v, got %v instead", []string{query}, pClient.Queries())
+			}
+			expectedUsage := map[string]int64{n1.Name: 42, n2.Name: 20}
+			for _, node := range nodes {
+				if usage := usageClient.NodeUtilization(node.Name)[MetricResource].Value(); usage != expectedUsage[node.Name] {
+					t.Errorf("expected %q node utilization to be %v, got %v instead", node.Name, expectedUsage[node.Name], usage)
+				}
+			}
+		})
+	}
+}
+
+func TestPrometheusUsageClientRetries(t *testing.T) {
+	n1 := test.BuildTestNode("n1", 2000, 3000, 10, nil)
+	nodes := []*v1.Node{n1}
+	getPodsAssignedToNode := func(string, podutil.FilterFunc) ([]*v1.Pod, error) {
+		return nil, nil
+	}
+	query := "instance:node_cpu:rate:sum"
+	samples := model.Vector{frameworktesting.PrometheusSample(query, n1.Name, 0.42)}
+
+	tests := []struct {
+		name     string
+		failures int
+		latency  time.Duration
+		retries  int
+		timeout  time.Duration
+		queries  int
+		err      bool
+	}{
+		{
+			name:     "no retries",
+			failures: 1,
+			queries:  1,
+			err:      true,
+		},
+		{
+			name:     "transient failures",
+			failures: 2,
+			retries:  2,
+			queries:  3,
+		},
+		{
+			name:     "out of retries",
+			failures: 3,
+			retries:  2,
+			queries:  3,
+			err:      true,
+		},
+		{
+			name:    "attempts time out",
+			latency: time.Minute,
+			retries: 1,
+			timeout: 10 * time.Millisecond,
+			queries: 2,
+			err:     true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			attempts := 0
+			pClient := &frameworktesting.FakePrometheusClient{
+				Handler: func(string) frameworktesting.PrometheusResponse {
+					attempts++
+					if attempts <= tc.failures {
+						return frameworktesting.PrometheusResponse{Err: fmt.Errorf("connection refused")}
+					}
+					return frameworktesting.PrometheusResponse{Result: samples, Latency: tc.latency}
+				},
+			}
+			usageClient := newPrometheusUsageClient(
+				getPodsAssignedToNode, pClient, query, 0, "", nil, "",
+				withPrometheusQueryTimeout(tc.timeout),
+				withPrometheusRetries(tc.retries, time.Millisecond),
+			)
+			err := usageClient.Sync(context.Background(), nodes)
+			if tc.err != (err != nil) {
+				t.Fatalf("expected error: %v, got %v", tc.err, err)
+			}
+			if queries := len(pClient.Queries()); queries != tc.queries {
+				t.Errorf("expected %v queries, got %v", tc.queries, queries)
+			}
+		})
+	}
+}
+
+func TestPrometheusUsageClientRange(t *testing.T) {
+	n1 := test.BuildTestNode("n1", 2000, 3000, 10, nil)
+	nodes := []*v1.Node{n1}
+	getPodsAssignedToNode := func(string, podutil.FilterFunc) ([]*v1.Pod, error) {
+		return nil, nil
+	}
+	query := "instance:node_cpu:rate:sum"
+
+	// a single spike among steady samples.
+	var values []model.SamplePair
+	for i := 0; i < 20; i++ {
+		value := model.SampleValue(0.25)
+		if i == 10 {
+			value = 0.75
+		}
+		values = append(values, model.SamplePair{Timestamp: model.Time(i * 60000), Value: value})
+	}
+	series := model.Matrix{
+		{Metric: frameworktesting.PrometheusSample(query, n1.Name, 0).Metric, Values: values},
+	}
+
+	tests := []struct {
+		name        string
+		aggregation PrometheusAggregation
+		result      model.Value
+		expected    int64
+		err         bool
+	}{
+		{name: "default aggregation", result: series, expected: 27},
+		{name: "max", aggregation: PrometheusAggregationMax, result: series, expected: 75},
+		{name: "p95", aggregation: PrometheusAggregationP95, result: series, expected: 25},
+		{
+			name:   "vector result",
+			result: model.Vector{frameworktesting.PrometheusSample(query, n1.Name, 0.3)},
+			err:    true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pClient := &frameworktesting.FakePrometheusClient{
+				Responses: map[string]frameworktesting.PrometheusResponse{query: {Result: tc.result}},
+			}
+			usageClient := newPrometheusUsageClient(
+				getPodsAssignedToNode, pClient, query, 0, "", nil, "",
+				withPrometheusRange(&PrometheusRange{
+					Window:      metav1.Duration{Duration: 20 * time.Minute},
+					Aggregation: tc.aggregation,
+				}),
+			)
+			err := usageClient.Sync(context.Background(), nodes)
+			if tc.err {
+				if err == nil {
+					t.Fatalf("expected an error, got nil instead")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if usage := usageClient.NodeUtilization(n1.Name)[MetricResource].Value(); usage != tc.expected {
+				t.Errorf("expected node utilization to be %v, got %v instead", tc.expected, usage)
+			}
+		})
+	}
+}
+
+func TestPrometheusUsageClientNodeLabel(t *testing.T) {
+	n1 := test.BuildTestNode("n1", 2000, 3000, 10, nil)
+	n2 := test.BuildTestNode("n2", 2000, 3000, 10, nil)
+	nodes := []*v1.Node{n1, n2}
+	getPodsAssignedToNode := func(string, podutil.FilterFunc) ([]*v1.Pod, error) {
+		return nil, nil
+	}
+	query := "node:node_cpu:rate:sum"
+	sample := func(labels model.Metric, value float64) *model.Sample {
+		return &model.Sample{Metric: labels, Value: model.SampleValue(value), Timestamp: 1728991761711}
+	}
+	stripPort := func(value string) string {
+		return strings.Split(value, ":")[0]
+	}
+
+	tests := []struct {
+		name      string
+		nodeLabel string
+		nodeName  PrometheusNodeNameFunc
+		result    model.Vector
+		err       error
+	}{
+		{
+			name:      "node label",
+			nodeLabel: "node",
+			result: model.Vector{
+				sample(model.Metric{"node": "n1", "instance": "10.0.0.1:9100"}, 0.42),
+				sample(model.Metric{"node": "n2", "instance": "10.0.0.2:9100"}, 0.20),
+			},
+		},
+		{
+			name:      "kubernetes_node label",
+			nodeLabel: "kubernetes_node",
+			result: model.Vector{
+				sample(model.Metric{"kubernetes_node": "n1"}, 0.42),
+				sample(model.Metric{"kubernetes_node": "n2"}, 0.20),
+			},
+		},
+		{
+			name:     "default label mapped to the node name",
+			nodeName: stripPort,
+			result: model.Vector{
+				sample(model.Metric{"instance": "n1:9100"}, 0.42),
+				sample(model.Metric{"instance": "n2:9100"}, 0.20),
+			},
+		},
+		{
+			name:      "samples missing the node label",
+			nodeLabel: "node",
+			result: model.Vector{
+				sample(model.Metric{"instance": "n1"}, 0.42),
+			},
+			err: fmt.Errorf("The collected metrics sample is missing 'node' key"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pClient := &frameworktesting.FakePrometheusClient{
+				Responses: map[string]frameworktesting.PrometheusResponse{query: {Result: tc.result}},
+			}
+			usageClient := newPrometheusUsageClient(getPodsAssignedToNode, pClient, query, 0, tc.nodeLabel, tc.nodeName, "")
+			err := usageClient.Sync(context.TODO(), nodes)
+			if tc.err != nil {
+				if err == nil || err.Error() != tc.err.Error() {
+					t.Fatalf("expected %q error, got %v instead", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			expectedUsage := map[string]int64{n1.Name: 42, n2.Name: 20}
+			for _, node := range nodes {
+				if usage := usageClient.NodeUtilization(node.Name)[MetricResource].Value(); usage != expectedUsage[node.Name] {
+					t.Errorf("expected %q node utilization to be %v, got %v instead", node.Name, expectedUsage[node.Name], usage)
+				}
+			}
+		})
+	}
+}
+
+func TestPrometheusUsageClientPodUsage(t *testing.T) {
+	n1 := test.BuildTestNode("n1", 2000, 3000, 10, nil)
+	p1 := test.BuildTestPod("p1", 400, 0, n1.Name, nil)
+	getPodsAssignedToNode := func(string, podutil.FilterFunc) ([]*v1.Pod, error) {
+		return []*v1.Pod{p1}, nil
+	}
+	query := "instance:node_cpu:rate:sum"
+	podQuery := `pod:node_cpu:share{namespace="{{.Namespace}}",pod="{{.Pod}}"}`
+	renderedPodQuery := `pod:node_cpu:share{namespace="default",pod="p1"}`
+
+	tests := []struct {
+		name     string
+		podQuery string
+		result   model.Vector
+		usage    int64
+		err      error
+	}{
+		{
+			name: "pod query not configured",
+			err:  newNotSupportedError(prometheusUsageClientType),
+		},
+		{
+			name:     "single sample",
+			podQuery: podQuery,
+			result: model.Vector{
+				frameworktesting.PrometheusSample("pod:node_cpu:share", n1.Name, 0.04),
+			},
+			usage: 4000,
+		},
+		{
+			name:     "samples of every container are summed up",
+			podQuery: podQuery,
+			result: model.Vector{
+				frameworktesting.PrometheusSample("pod:node_cpu:share", n1.Name, 0.025),
+				frameworktesting.PrometheusSample("pod:node_cpu:share", n1.Name, 0.0005),
+			},
+			usage: 2550,
+		},
+		{
+			name:     "no sample",
+			podQuery: podQuery,
+			result:   model.Vector{},
+			err:      fmt.Errorf("unable to find metric entry for default/p1"),
+		},
+		{
+			name:     "samples outside of the interval",
+			podQuery: podQuery,
+			result: model.Vector{
+				frameworktesting.PrometheusSample("pod:node_cpu:share", n1.Name, 0.75),
+				frameworktesting.PrometheusSample("pod:node_cpu:share", n1.Name, 0.5),
+			},
+			err: fmt.Errorf("The collected metrics samples for default/p1 sum up to 1.25 outside of <0; 1> interval"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pClient := &frameworktesting.FakePrometheusClient{
+				Responses: map[string]frameworktesting.PrometheusResponse{
+					query:            {Result: model.Vector{frameworktesting.PrometheusSample(query, n1.Name, 0.5)}},
+					renderedPodQuery: {Result: tc.result},
+				},
+			}
+			usageClient := newPrometheusUsageClient(getPodsAssignedToNode, pClient, query, 0, "", nil, tc.podQuery)
+			if err := usageClient.Sync(context.TODO(), []*v1.Node{n1}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// the usage is queried once, the second call is served from
+			// the cache.
+			for i := 0; i < 2; i++ {
+				usage, err := usageClient.PodUsage(p1)
+				if tc.err != nil {
+					if err == nil || err.Error() != tc.err.Error() {
+						t.Fatalf("expected %q error, got %v instead", tc.err, err)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if value := usage[MetricResource].MilliValue(); value != tc.usage {
+					t.Errorf("expected pod usage to be %vm, got %vm instead", tc.usage, value)
+				}
+			}
+			expectedQueries := []string{query, renderedPodQuery}
+			if !reflect.DeepEqual(pClient.Queries(), expectedQueries) {
+				t.Errorf("expected queries %v, got %v instead", expectedQueries, pClient.Queries())
+			}
+		})
+	}
+}
+
+// staticVPALister returns the same VerticalPodAutoscalers, or error, for
+// every namespace.
+type staticVPALister struct {
+	vpas []vpa.VerticalPodAutoscaler
+	err  error
+}
+
+func (l staticVPALister) List(context.Context, string) ([]vpa.VerticalPodAutoscaler, error) {
+	return l.vpas, l.err
+}
+
+func TestVPARecommendationUsageClient(t *testing.T) {
+	ctx := context.TODO()
+	n1 := test.BuildTestNode("n1", 2000, 3000, 10, nil)
+	deploymentPod := func(pod *v1.Pod) {
+		pod.Labels = map[string]string{"pod-template-hash": "5d8f7c"}
+		pod.OwnerReferences = []metav1.OwnerReference{
+			{Kind: "ReplicaSet", Name: "web-5d8f7c", Controller: ptr.To(true)},
+		}
+		pod.Spec.Containers[0].Name = "app"
+	}
+	p1 := test.BuildTestPod("p1", 400, 0, n1.Name, deploymentPod)
+	p2 := test.BuildTestPod("p2", 300, 0, n1.Name, test.SetSSOwnerRef)
+	p3 := test.BuildTestPod("p3", 400, 0, n1.Name, func(pod *v1.Pod) {
+		deploymentPod(pod)
+		pod.Spec.InitContainers = []v1.Container{
+			{
+				Name:          "proxy",
+				RestartPolicy: ptr.To(v1.ContainerRestartPolicyAlways),
+				Resources: v1.ResourceRequirements{
+					Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("50m")},
+				},
+			},
+		}
+	})
+	getPodsAssignedToNode := func(string, podutil.FilterFunc) ([]*v1.Pod, error) {
+		return []*v1.Pod{p1, p2, p3}, nil
+	}
+
+	web := vpa.VerticalPodAutoscaler{}
+	web.Spec.TargetRef = &autoscalingv1.CrossVersionObjectReference{Kind: "Deployment", Name: "web", APIVersion: "apps/v1"}
+	web.Status.Recommendation = &vpa.Recommendation{
+		ContainerRecommendations: []vpa.ContainerRecommendation{
+			{
+				ContainerName: "app",
+				Target: v1.ResourceList{
+					v1.ResourceCPU:    resource.MustParse("100m"),
+					v1.ResourceMemory: resource.MustParse("200Mi"),
+				},
+			},
+		},
+	}
+
+	resourceNames := []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory, v1.ResourcePods}
+	usageClient := newVPARecommendationUsageClient(resourceNames, getPodsAssignedToNode, staticVPALister{vpas: []vpa.VerticalPodAutoscaler{web}}, nil)
+	if err := usageClient.Sync(ctx, []*v1.Node{n1}); err != nil {
+		t.Fatalf("failed to sync a snapshot: %v", err)
+	}
+
+	// the deployment pods are accounted through the recommendation, the
+	// sidecar without one and the statefulset pod through their requests.
+	expected := api.ReferencedResourceList{
+		v1.ResourceCPU:    resource.NewMilliQuantity(550, resource.DecimalSI),
+		v1.ResourceMemory: resource.NewQuantity(400*1024*1024, resource.BinarySI),
+		v1.ResourcePods:   resource.NewQuantity(3, resource.DecimalSI),
+	}
+	usage := usageClient.NodeUtilization(n1.Name)
+	for name, quantity := range expected {
+		if usage[name].Cmp(*quantity) != 0 {
+			t.Errorf("expected node %v usage to be %v, got %v", name, quantity, usage[name])
+		}
+	}
+
+	for pod, cpu := range map[*v1.Pod]int64{p1: 100, p2: 300, p3: 150} {
+		usage, err := usageClient.PodUsage(pod)
+		if err != nil {
+			t.Fatalf("failed to get the pod usage: %v", err)
+		}
+		if value := usage[v1.ResourceCPU].MilliValue(); value != cpu {
+			t.Errorf("expected %v cpu usage to be %vm, got %vm", pod.Name, cpu, value)
+		}
+	}
+	// the recommendation does not change the pod itself.
+	if cpu := p1.Spec.Containers[0].Resources.Requests.Cpu().MilliValue(); cpu != 400 {
+		t.Errorf("expected p1 cpu request to remain 400m, got %vm", cpu)
+	}
+
+	failing := newVPARecommendationUsageClient(resourceNames, getPodsAssignedToNode, staticVPALister{err: fmt.Errorf("forbidden")}, nil)
+	if err := failing.Sync(ctx, []*v1.Node{n1}); err == nil {
+		t.Errorf("expected the sync to fail when the VerticalPodAutoscalers can not be listed")
+	}
+}
+
+// fakeMetricsRESTClient returns a rest client answering requests to the
+// provided paths with the provided bodies, and with not found otherwise.
+// The query of the requests is recorded by path.
+func fakeMetricsRESTClient(responses map[string]string, queries map[string]string) *restfake.RESTClient {
+	return &restfake.RESTClient{
+		NegotiatedSerializer: scheme.Codecs.WithoutConversion(),
+		Client: restfake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+			if queries != nil {
+				queries[req.URL.Path] = req.URL.RawQuery
+			}
+			header := http.Header{"Content-Type": []string{runtime.ContentTypeJSON}}
+			body, ok := responses[req.URL.Path]
+			if !ok {
+				return &http.Response{StatusCode: http.StatusNotFound, Header: header, Body: io.NopCloser(strings.NewReader("{}"))}, nil
+			}
+			return &http.Response{StatusCode: http.StatusOK, Header: header, Body: io.NopCloser(strings.NewReader(body))}, nil
+		}),
+	}
+}
+
+func TestCustomMetricsUsageClient(t *testing.T) {
+	n1 := test.BuildTestNode("n1", 2000, 3000, 10, nil)
+	n2 := test.BuildTestNode("n2", 2000, 3000, 10, nil)
+	nodes := []*v1.Node{n1, n2}
+	getPodsAssignedToNode := func(string, podutil.FilterFunc) ([]*v1.Pod, error) {
+		return nil, nil
+	}
+
+	tests := []struct {
+		name      string
+		metrics   *CustomMetrics
+		responses map[string]string
+		query     string
+		err       error
+	}{
+		{
+			name: "custom metric describing the nodes",
+			metrics: &CustomMetrics{
+				MetricName: "node_cpu_utilisation",
+				Selector:   &metav1.LabelSelector{MatchLabels: map[string]string{"mode": "busy"}},
+			},
+			responses: map[string]string{
+				"/apis/custom.metrics.k8s.io/v1beta2/nodes/*/node_cpu_utilisation": `{"items": [
+					{"describedObject": {"kind": "Node", "name": "n1"}, "value": "420m"},
+					{"describedObject": {"kind": "Node", "name": "n2"}, "value": "200m"}
+				]}`,
+			},
+			query: "labelSelector=mode%3Dbusy",
+		},
+		{
+			name: "external metric labeled with the nodes",
+			metrics: &CustomMetrics{
+				MetricName: "node_cpu_utilisation",
+				External:   &ExternalMetric{Namespace: "monitoring"},
+			},
+			responses: map[string]string{
+				"/apis/external.metrics.k8s.io/v1beta1/namespaces/monitoring/node_cpu_utilisation": `{"items": [
+					{"metricName": "node_cpu_utilisation", "metricLabels": {"node": "n1"}, "value": "420m"},
+					{"metricName": "node_cpu_utilisation", "metricLabels": {"node": "n2"}, "value": "200m"}
+				]}`,
+			},
+		},
+		{
+			name: "external metric labeled with a custom node label",
+			metrics: &CustomMetrics{
+				MetricName: "node_cpu_utilisation",
+				External:   &ExternalMetric{Namespace: "monitoring", NodeLabel: "kubernetes_node"},
+			},
+			responses: map[string]string{
+				"/apis/external.metrics.k8s.io/v1beta1/namespaces/monitoring/node_cpu_utilisation": `{"items": [
+					{"metricName": "node_cpu_utilisation", "metricLabels": {"kubernetes_node": "n1"}, "value": "420m"},
+					{"metricName": "node_cpu_utilisation", "metricLabels": {"kubernetes_node": "n2"}, "value": "200m"}
+				]}`,
+			},
+		},
+		{
+			name: "external metric missing the node label",
+			metrics: &CustomMetrics{
+				MetricName: "node_cpu_utilisation",
+				External:   &ExternalMetric{Namespace: "monitoring"},
+			},
+			responses: map[string]string{
+				"/apis/external.metrics.k8s.io/v1beta1/namespaces/monitoring/node_cpu_utilisation": `{"items": [
+					{"metricName": "node_cpu_utilisation", "metricLabels": {"instance": "n1"}, "value": "420m"}
+				]}`,
+			},
+			err: fmt.Errorf("The collected metrics value is missing 'node' label"),
+		},
+		{
+			name:    "node without a value",
+			metrics: &CustomMetrics{MetricName: "node_cpu_utilisation"},
+			responses: map[string]string{
+				"/apis/custom.metrics.k8s.io/v1beta2/nodes/*/node_cpu_utilisation": `{"items": [
+					{"describedObject": {"kind": "Node", "name": "n1"}, "value": "420m"}
+				]}`,
+			},
+			err: fmt.Errorf("unable to find metric entry for n2"),
+		},
+		{
+			name:    "value outside of the interval",
+			metrics: &CustomMetrics{MetricName: "node_cpu_utilisation"},
+			responses: map[string]string{
+				"/apis/custom.metrics.k8s.io/v1beta2/nodes/*/node_cpu_utilisation": `{"items": [
+					{"describedObject": {"kind": "Node", "name": "n1"}, "value": "2"}
+				]}`,
+			},
+			err: fmt.Errorf("The collected metrics value for \"n1\" has value 2 outside of <0; 1> interval"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			queries := map[string]string{}
+			usageClient, err := newCustomMetricsUsageClient(getPodsAssignedToNode, fakeMetricsRESTClient(tc.responses, queries), tc.metrics)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			err = usageClient.Sync(context.TODO(), nodes)
+			if tc.err != nil {
+				if err == nil || err.Error() != tc.err.Error() {
+					t.Fatalf("expected %q error, got %v instead", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for path := range tc.responses {
+				if queries[path] != tc.query {
+					t.Errorf("expected %q query, got %q instead", tc.query, queries[path])
+				}
+			}
+			expectedUsage := map[string]int64{n1.Name: 42, n2.Name: 20}
+			for _, node := range nodes {
+				if usage := usageClient.NodeUtilization(node.Name)[MetricResource].Value(); usage != expectedUsage[node.Name] {
+					t.Errorf("expected %q node utilization to be %v, got %v instead", node.Name, expectedUsage[node.Name], usage)
+				}
+			}
+		})
+	}
+}
+
+func TestCustomMetricsUsageClientPodUsage(t *testing.T) {
+	n1 := test.BuildTestNode("n1", 2000, 3000, 10, nil)
+	p1 := test.BuildTestPod("p1", 400, 0, n1.Name, nil)
+	p2 := test.BuildTestPod("p2", 400, 0, n1.Name, nil)
+	getPodsAssignedToNode := func(string, podutil.FilterFunc) ([]*v1.Pod, error) {
+		return []*v1.Pod{p1, p2}, nil
+	}
+	responses := map[string]string{
+		"/apis/custom.metrics.k8s.io/v1beta2/nodes/*/node_cpu_utilisation": `{"items": [
+			{"describedObject": {"kind": "Node", "name": "n1"}, "value": "500m"}
+		]}`,
+		"/apis/custom.metrics.k8s.io/v1beta2/namespaces/default/pods/p1/pod_cpu_share": `{"items": [
+			{"describedObject": {"kind": "Pod", "namespace": "default", "name": "p1"}, "value": "40m"}
+		]}`,
+	}
+
+	tests := []struct {
+		name    string
+		metrics *CustomMetrics
+		pod     *v1.Pod
+		usage   int64
+		err     error
+	}{
+		{
+			name:    "pod metric",
+			metrics: &CustomMetrics{MetricName: "node_cpu_utilisation", PodMetricName: "pod_cpu_share"},
+			pod:     p1,
+			usage:   4000,
+		},
+		{
+			name:    "pod without a value",
+			metrics: &CustomMetrics{MetricName: "node_cpu_utilisation", PodMetricName: "pod_cpu_share"},
+			pod:     p2,
+			err:     fmt.Errorf("unable to get \"pod_cpu_share\" metric: the server could not find the requested resource"),
+		},
+		{
+			name:    "pod metric not configured",
+			metrics: &CustomMetrics{MetricName: "node_cpu_utilisation"},
+			pod:     p1,
+			err:     newNotSupportedError(customMetricsUsageClientType),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			usageClient, err := newCustomMetricsUsageClient(getPodsAssignedToNode, fakeMetricsRESTClient(responses, nil), tc.metrics)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err := usageClient.Sync(context.TODO(), []*v1.Node{n1}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			usage, err := usageClient.PodUsage(tc.pod)
+			if tc.err != nil {
+				if err == nil || err.Error() != tc.err.Error() {
+					t.Fatalf("expected %q error, got %v instead", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if value := usage[MetricResource].MilliValue(); value != tc.usage {
+				t.Errorf("expected pod usage to be %vm, got %vm instead", tc.usage, value)
+			}
+		})
+	}
+}
+
+func TestOpenTelemetryUsageClient(t *testing.T) {
+	n1 := test.BuildTestNode("n1", 2000, 3000, 10, nil)
+	n2 := test.BuildTestNode("n2", 2000, 3000, 10, nil)
+	p1 := test.BuildTestPod("p1", 400, 0, n1.Name, nil)
+	p2 := test.BuildTestPod("p2", 400, 0, n1.Name, nil)
+	nodes := []*v1.Node{n1, n2}
+	getPodsAssignedToNode := func(string, podutil.FilterFunc) ([]*v1.Pod, error) {
+		return []*v1.Pod{p1, p2}, nil
+	}
+	exposition := `# TYPE k8s_node_cpu_utilization gauge
+k8s_node_cpu_utilization{k8s_node_name="n1"} 0.42
+k8s_node_cpu_utilization{k8s_node_name="n2"} 0.2
+# TYPE node_cpu_busy gauge
+node_cpu_busy{host_name="n1",cpu="0"} 0.2
+node_cpu_busy{host_name="n1",cpu="1"} 0.22
+node_cpu_busy{host_name="n2",cpu="0"} 0.2
+# TYPE k8s_pod_cpu_node_share gauge
+k8s_pod_cpu_node_share{k8s_namespace_name="default",k8s_pod_name="p1",k8s_container_name="a"} 0.03
+k8s_pod_cpu_node_share{k8s_namespace_name="default",k8s_pod_name="p1",k8s_container_name="b"} 0.01
+# TYPE k8s_node_memory_utilization gauge
+k8s_node_memory_utilization{k8s_node_name="n1"} 1.5
+k8s_node_memory_utilization{k8s_node_name="n2"} 0.2
+`
+	// metrics of the hostmetrics and kubeletstats receivers as exposed by
+	// the prometheus exporter with the resource attributes converted to
+	// labels.
+	exporterExposition := `# HELP target_info Target metadata
+# TYPE target_info gauge
+target_info{instance="otel-collector:8889",job="otel-collector"} 1
+# HELP system_cpu_utilization_ratio Difference in system.cpu.time since the last measurement per logical CPU, divided by the elapsed time (value in interval [0,1]).
+# TYPE system_cpu_utilization_ratio gauge
+system_cpu_utilization_ratio{cpu="cpu0",instance="otel-collector:8889",job="otel-collector",k8s_node_name="n1",state="idle"} 0.58
+system_cpu_utilization_ratio{cpu="cpu0",instance="otel-collector:8889",job="otel-collector",k8s_node_name="n1",state="user"} 0.4
+system_cpu_utilization_ratio{cpu="cpu1",instance="otel-collector:8889",job="otel-collector",k8s_node_name="n1",state="idle"} 0.54
+system_cpu_utilization_ratio{cpu="cpu1",instance="otel-collector:8889",job="otel-collector",k8s_node_name="n1",state="user"} 0.44
+system_cpu_utilization_ratio{cpu="cpu0",instance="otel-collector:8889",job="otel-collector",k8s_node_name="n2",state="idle"} 0.28
+system_cpu_utilization_ratio{cpu="cpu0",instance="otel-collector:8889",job="otel-collector",k8s_node_name="n2",state="user"} 0.2
+system_cpu_utilization_ratio{cpu="cpu1",instance="otel-collector:8889",job="otel-collector",k8s_node_name="n2",state="idle"} 0.28
+system_cpu_utilization_ratio{cpu="cpu1",instance="otel-collector:8889",job="otel-collector",k8s_node_name="n2",state="user"} 0.2
+# HELP system_memory_utilization_ratio Percentage of memory bytes in use.
+# TYPE system_memory_utilization_ratio gauge
+system_memory_utilization_ratio{instance="otel-collector:8889",job="otel-collector",k8s_node_name="n1",state="free"} 0.5
+system_memory_utilization_ratio{instance="otel-collector:8889",job="otel-collector",k8s_node_name="n1",state="used"} 0.42
+system_memory_utilization_ratio{instance="otel-collector:8889",job="otel-collector",k8s_node_name="n2",state="free"} 0.7
+system_memory_utilization_ratio{instance="otel-collector:8889",job="otel-collector",k8s_node_name="n2",state="used"} 0.2
+# HELP k8s_pod_cpu_node_utilization_ratio Pod cpu utilization as a ratio of the node's capacity
+# TYPE k8s_pod_cpu_node_utilization_ratio gauge
+k8s_pod_cpu_node_utilization_ratio{instance="otel-collector:8889",job="otel-collector",k8s_namespace_name="default",k8s_node_name="n1",k8s_pod_name="p1"} 0.04
+`
+
+	tests := []struct {
+		name       string
+		metrics    *OpenTelemetryMetrics
+		exposition string
+		status     int
+		podUsage   map[string]int64
+		err        error
+	}{
+		{
+			name:    "node metric with the default node attribute",
+			metrics: &OpenTelemetryMetrics{MetricName: "k8s.node.cpu.utilization"},
+		},
+		{
+			name:    "series of a node summed up",
+			metrics: &OpenTelemetryMetrics{MetricName: "node_cpu_busy", NodeAttribute: "host.name", Aggregation: OpenTelemetryAggregationSum},
+		},
+		{
+			name: "per cpu series of a node averaged",
+			metrics: &OpenTelemetryMetrics{
+				MetricName: "system.cpu.utilization",
+				Attributes: map[string]string{"state": "user"},
+			},
+			exposition: exporterExposition,
+		},
+		{
+			name: "series restricted to the attributes",
+			metrics: &OpenTelemetryMetrics{
+				MetricName:    "system.memory.utilization",
+				Attributes:    map[string]string{"state": "used"},
+				PodMetricName: "k8s.pod.cpu.node.utilization",
+			},
+			exposition: exporterExposition,
+			podUsage:   map[string]int64{p1.Name: 4000},
+		},
+		{
+			name:       "per cpu series of a node summed up",
+			metrics:    &OpenTelemetryMetrics{MetricName: "system.cpu.utilization", Aggregation: OpenTelemetryAggregationSum},
+			exposition: exporterExposition,
+			err:        fmt.Errorf("The collected metrics value for \"n1\" has value 1.96 outside of <0; 1> interval"),
+		},
+		{
+			name:     "pod metric",
+			metrics:  &OpenTelemetryMetrics{MetricName: "k8s.node.cpu.utilization", PodMetricName: "k8s.pod.cpu.node_share"},
+			podUsage: map[string]int64{p1.Name: 4000},
+		},
+		{
+			name:    "node metric missing the node attribute",
+			metrics: &OpenTelemetryMetrics{MetricName: "node_cpu_busy"},
+			err:     fmt.Errorf("The collected metrics value is missing 'k8s_node_name' label, resource attributes are only exported as labels with resource_to_telemetry_conversion enabled"),
+		},
+		{
+			name:    "node without a value",
+			metrics: &OpenTelemetryMetrics{MetricName: "k8s.node.memory.available"},
+			err:     fmt.Errorf("unable to find metric entry for n1"),
+		},
+		{
+			name:    "value outside of the interval",
+			metrics: &OpenTelemetryMetrics{MetricName: "k8s.node.memory.utilization"},
+			err:     fmt.Errorf("The collected metrics value for \"n1\" has value 1.5 outside of <0; 1> interval"),
+		},
+		{
+			name:    "collector failing",
+			metrics: &OpenTelemetryMetrics{MetricName: "k8s.node.cpu.utilization"},
+			status:  http.StatusServiceUnavailable,
+			err:     fmt.Errorf("unable to scrape the opentelemetry collector: unexpected status 503 Service Unavailable"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tc.status != 0 {
+					w.WriteHeader(tc.status)
+					return
+				}
+				if tc.exposition != "" {
+					_, _ = w.Write([]byte(tc.exposition))
+					return
+				}
+				_, _ = w.Write([]byte(exposition))
+			}))
+			defer server.Close()
+
+			tc.metrics.Endpoint = server.URL + "/metrics"
+			usageClient := newOpenTelemetryUsageClient(getPodsAssignedToNode, server.Client(), tc.metrics)
+			err := usageClient.Sync(context.TODO(), nodes)
+			if tc.err != nil {
+				if err == nil || err.Error() != tc.err.Error() {
+					t.Fatalf("expected %q error, got %v instead", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			expectedUsage := map[string]int64{n1.Name: 42, n2.Name: 20}
+			for _, node := range nodes {
+				if usage := usageClient.NodeUtilization(node.Name)[MetricResource].Value(); usage != expectedUsage[node.Name] {
+					t.Errorf("expected %q node utilization to be %v, got %v instead", node.Name, expectedUsage[node.Name], usage)
+				}
+			}
+
+			for _, pod := range []*v1.Pod{p1, p2} {
+				usage, err := usageClient.PodUsage(pod)
+				expected, exists := tc.podUsage[pod.Name]
+				switch {
+				case tc.podUsage == nil:
+					if err == nil || err.Error() != newNotSupportedError(openTelemetryUsageClientType).Error() {
+						t.Errorf("expected pod usage not to be supported, got %v instead", err)
+					}
+				case !exists:
+					if err == nil {
+						t.Errorf("expected %q pod usage to be missing, got %v instead", pod.Name, usage)
+					}
+				case err != nil:
+					t.Errorf("unexpected error: %v", err)
+				case usage[MetricResource].MilliValue() != expected:
+					t.Errorf("expected %q pod usage to be %vm, got %vm instead", pod.Name, expected, usage[MetricResource].MilliValue())
+				}
+			}
+		})
+	}
+}
+
+func TestStaticUsageClient(t *testing.T) {
+	n1 := test.BuildTestNode("n1", 2000, 3000, 10, nil)
+	n2 := test.BuildTestNode("n2", 2000, 3000, 10, nil)
+	p1 := test.BuildTestPod("p1", 400, 0, n1.Name, nil)
+	p2 := test.BuildTestPod("p2", 400, 0, n1.Name, nil)
+	p3 := test.BuildTestPod("p3", 400, 0, n2.Name, nil)
+	nodes := []*v1.Node{n1, n2}
+	getPodsAssignedToNode := func(node string, _ podutil.FilterFunc) ([]*v1.Pod, error) {
+		if node == n1.Name {
+			return []*v1.Pod{p1, p2}, nil
+		}
+		return []*v1.Pod{p3}, nil
+	}
+	document := `
+nodes:
+  n1:
+    cpu: 1500m
+pods:
+  default/p1:
+    cpu: 100m
+`
+	file := filepath.Join(t.TempDir(), "usage.yaml")
+	if err := os.WriteFile(file, []byte(document), 0o600); err != nil {
+		t.Fatalf("unable to write %v: %v", file, err)
+	}
+	configMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "usage"},
+		Data:       map[string]string{"usage": document, "invalid": "nodes: {n1: {cpu: 1}}\nnode: {}"},
+	}
+	client := fakeclientset.NewSimpleClientset(configMap)
+
+	tests := []struct {
+		name string
+		load StaticUsageLoader
+		err  error
+	}{
+		{
+			name: "document read from a file",
+			load: StaticUsageFromFile(file),
+		},
+		{
+			name: "document read from a configmap",
+			load: StaticUsageFromConfigMap(client, "kube-system", "usage", ""),
+		},
+		{
+			name: "configmap without the key",
+			load: StaticUsageFromConfigMap(client, "kube-system", "usage", "missing"),
+			err:  fmt.Errorf("unable to load the static usage: kube-system/usage ConfigMap has no \"missing\" key"),
+		},
+		{
+			name: "document with an unknown field",
+			load: StaticUsageFromConfigMap(client, "kube-system", "usage", "invalid"),
+			err:  fmt.Errorf("unable to decode the static usage: error unmarshaling JSON: while decoding JSON: json: unknown field \"node\""),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			usageClient := NewStaticUsageClient([]v1.ResourceName{v1.ResourceCPU, v1.ResourcePods}, getPodsAssignedToNode, tc.load)
+			err := usageClient.Sync(context.TODO(), nodes)
+			if tc.err != nil {
+				if err == nil || err.Error() != tc.err.Error() {
+					t.Fatalf("expected %q error, got %v instead", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// n1 lists its cpu usage, n2 cpu usage is the request of p3.
+			expectedCPU := map[string]int64{n1.Name: 1500, n2.Name: 400}
+			expectedPods := map[string]int64{n1.Name: 2, n2.Name: 1}
+			for _, node := range nodes {
+				usage := usageClient.NodeUtilization(node.Name)
+				if cpu := usage[v1.ResourceCPU].MilliValue(); cpu != expectedCPU[node.Name] {
+					t.Errorf("expected %q node cpu usage to be %vm, got %vm instead", node.Name, expectedCPU[node.Name], cpu)
+				}
+				if pods := usage[v1.ResourcePods].Value(); pods != expectedPods[node.Name] {
+					t.Errorf("expected %q node to run %v pods, got %v instead", node.Name, expectedPods[node.Name], pods)
+				}
+			}
+
+			// p1 lists its cpu usage, p2 is accounted through its request.
+			for pod, expected := range map[*v1.Pod]int64{p1: 100, p2: 400} {
+				usage, err := usageClient.PodUsage(pod)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if cpu := usage[v1.ResourceCPU].MilliValue(); cpu != expected {
+					t.Errorf("expected %q pod cpu usage to be %vm, got %vm instead", pod.Name, expected, cpu)
+				}
+			}
+		})
+	}
+}
+
+func TestCompactUsage(t *testing.T) {
+	resourceNames := []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory, v1.ResourcePods, extendedResource}
+	usage := api.ReferencedResourceList{
+		v1.ResourceCPU:    resource.NewMilliQuantity(1500, resource.DecimalSI),
+		v1.ResourceMemory: resource.NewQuantity(3*1024*1024*1024, resource.BinarySI),
+		v1.ResourcePods:   resource.NewQuantity(12, resource.DecimalSI),
+	}
+
+	compact := newCompactUsage(resourceNames, usage)
+	if len(compact) != len(resourceNames) {
+		t.Fatalf("expected %v values, got %v", len(resourceNames), len(compact))
+	}
+
+	result := compact.resourceList(resourceNames)
+	if _, ok := result[extendedResource]; ok {
+		t.Errorf("expected absent resource not to be present in the result")
+	}
+	for _, resourceName := range []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory, v1.ResourcePods} {
+		if result[resourceName].Cmp(*usage[resourceName]) != 0 {
+			t.Errorf("expected %v usage to be %v, got %v", resourceName, usage[resourceName], result[resourceName])
+		}
+	}
+	if result[v1.ResourceMemory].Format != resource.BinarySI {
+		t.Errorf("expected memory to be in %v format, got %v", resource.BinarySI, result[v1.ResourceMemory].Format)
+	}
+
+	// fractions of extended resources, e.g. reported by a resource
+	// usage collector, are kept.
+	usage[extendedResource] = resource.NewMilliQuantity(1500, resource.DecimalSI)
+	result = newCompactUsage(resourceNames, usage).resourceList(resourceNames)
+	if result[extendedResource].Cmp(*usage[extendedResource]) != 0 {
+		t.Errorf("expected %v usage to be %v, got %v", extendedResource, usage[extendedResource], result[extendedResource])
+	}
+
+	var missing compactUsage
+	if missing.resourceList(resourceNames) != nil {
+		t.Errorf("expected nil usage for a missing node")
+	}
+}
+
+func TestSharedUsageClient(t *testing.T) {
+	ctx := context.TODO()
+	n1 := test.BuildTestNode("n1", 2000, 3000, 10, nil)
+	n2 := test.BuildTestNode("n2", 2000, 3000, 10, nil)
+	p1 := test.BuildTestPod("p1", 400, 0, n1.Name, nil)
+
+	listed := 0
+	handle := &frameworkfake.HandleImpl{
+		GetPodsAssignedToNodeFuncImpl: func(nodeName string, filter podutil.FilterFunc) ([]*v1.Pod, error) {
+			listed++
+			if nodeName == n1.Name {
+				return []*v1.Pod{p1}, nil
+			}
+			return nil, nil
+		},
+	}
+
+	low, err := NewLowNodeUtilization(
+		&LowNodeUtilizationArgs{
+			Thresholds:       api.ResourceThresholds{v1.ResourceCPU: 20},
+			TargetThresholds: api.ResourceThresholds{v1.ResourceCPU: 70},
+		},
+		handle,
+	)
+	if err != nil {
+		t.Fatalf("unable to initialize the plugin: %v", err)
+	}
+	high, err := NewHighNodeUtilization(
+		&HighNodeUtilizationArgs{
+			Thresholds: api.ResourceThresholds{v1.ResourcePods: 20},
+		},
+		handle,
+	)
+	if err != nil {
+		t.Fatalf("unable to initialize the plugin: %v", err)
+	}
+
+	client := low.(*LowNodeUtilization).usageClient
+	if client != high.(*HighNodeUtilization).usageClient {
+		t.Fatalf("expected plugins with the same usage configuration to share the usage client")
+	}
+
+	nodes := []*v1.Node{n1, n2}
+	for i := 0; i < 2; i++ {
+		if err := client.Sync(ctx, nodes); err != nil {
+			t.Fatalf("failed to sync: %v", err)
+		}
+	}
+	if listed != len(nodes) {
+		t.Errorf("expected the usage to be synced once, pods were listed %v times", listed)
+	}
+
+	invalidateUsageClient(client)
+	if err := client.Sync(ctx, nodes); err != nil {
+		t.Fatalf("failed to sync: %v", err)
+	}
+	if listed != 2*len(nodes) {
+		t.Errorf("expected the usage to be synced again after invalidation, pods were listed %v times", listed)
+	}
+
+	if err := client.Sync(ctx, []*v1.Node{n1}); err != nil {
+		t.Fatalf("failed to sync: %v", err)
+	}
+	if listed != 2*len(nodes)+1 {
+		t.Errorf("expected the usage to be synced for a different set of nodes, pods were listed %v times", listed)
+	}
+	if cpu := client.NodeUtilization(n1.Name)[v1.ResourceCPU].MilliValue(); cpu != 400 {
+		t.Errorf("expected cpu usage to be 400m, got %vm", cpu)
+	}
+}
+
+func TestCachedUsageClient(t *testing.T) {
+	ctx := context.TODO()
+	n1 := test.BuildTestNode("n1", 2000, 3000, 10, nil)
+	n2 := test.BuildTestNode("n2", 2000, 3000, 10, nil)
+	p1 := test.BuildTestPod("p1", 400, 0, n1.Name, nil)
+
+	// both handles belong to different profiles sharing the same cache,
+	// p1 has been evicted by the time the second profile runs.
+	cache := frameworktypes.NewSharedCache()
+	listed := map[string]int{}
+	handleFor := func(profile string, pods ...*v1.Pod) *frameworkfake.HandleImpl {
+		return &frameworkfake.HandleImpl{
+			SharedCacheImpl: cache,
+			GetPodsAssignedToNodeFuncImpl: func(nodeName string, filter podutil.FilterFunc) ([]*v1.Pod, error) {
+				listed[profile]++
+				if nodeName == n1.Name {
+					return pods, nil
+				}
+				return nil, nil
+			},
+		}
+	}
+
+	ttl := metav1.Duration{Duration: time.Hour}
+	low, err := NewLowNodeUtilization(
+		&LowNodeUtilizationArgs{
+			Thresholds:       api.ResourceThresholds{v1.ResourceCPU: 20},
+			TargetThresholds: api.ResourceThresholds{v1.ResourceCPU: 70},
+			UsageCacheTTL:    ttl,
+		},
+		handleFor("first", p1),
+	)
+	if err != nil {
+		t.Fatalf("unable to initialize the plugin: %v", err)
+	}
+	high, err := NewHighNodeUtilization(
+		&HighNodeUtilizationArgs{
+			Thresholds:    api.ResourceThresholds{v1.ResourcePods: 20},
+			UsageCacheTTL: ttl,
+		},
+		handleFor("second"),
+	)
+	if err != nil {
+		t.Fatalf("unable to initialize the plugin: %v", err)
+	}
+
+	nodes := []*v1.Node{n1, n2}
+	first, second := low.(*LowNodeUtilization).usageClient, high.(*HighNodeUtilization).usageClient
+	for _, client := range []UsageClient{first, second} {
+		if err := client.Sync(ctx, nodes); err != nil {
+			t.Fatalf("failed to sync: %v", err)
+		}
+	}
+	if listed["first"] != len(nodes) || listed["second"] != 0 {
+		t.Errorf("expected the usage to be synced once, pods were listed %v times", listed)
+	}
+	if cpu := second.NodeUtilization(n1.Name)[v1.ResourceCPU].MilliValue(); cpu != 400 {
+		t.Errorf("expected the cached cpu usage to be 400m, got %vm", cpu)
+	}
+	if pods := second.Pods(n1.Name); len(pods) != 0 {
+		t.Errorf("expected evicted pods to be left out, got %v", len(pods))
+	}
+
+	invalidateUsageClient(second)
+	listed["second"] = 0
+	if err := second.Sync(ctx, nodes); err != nil {
+		t.Fatalf("failed to sync: %v", err)
+	}
+	if listed["second"] != len(nodes) {
+		t.Errorf("expected the usage to be synced again after invalidation, pods were listed %v times", listed["second"])
+	}
+	if cpu := second.NodeUtilization(n1.Name)[v1.ResourceCPU].MilliValue(); cpu != 0 {
+		t.Errorf("expected cpu usage to be 0m, got %vm", cpu)
+	}
+}
+
+func TestActualUsageClientCachesPodUsage(t *testing.T) {
+	ctx := context.TODO()
+	n1 := test.BuildTestNode("n1", 2000, 3000, 10, nil)
+	p1 := test.BuildTestPod("p1", 400, 0, n1.Name, nil)
+	p1.Namespace = "default"
+
+	clientset := fakeclientset.NewSimpleClientset(n1, p1)
+	metricsClientset := fakemetricsclient.NewSimpleClientset()
+	metricsClientset.Tracker().Create(nodesgvr, test.BuildNodeMetrics(n1.Name, 400, 1714978816), "")
+	metricsClientset.Tracker().Create(podsgvr, test.BuildPodMetrics(p1.Name, 300, 1024), "default")
+
+	gets := 0
+	metricsClientset.PrependReactor("get", "pods", func(action core.Action) (bool, runtime.Object, error) {
+		gets++
+		return false, nil, nil
+	})
+
+	sharedInformerFactory := informers.NewSharedInformerFactory(clientset, 0)
+	podInformer := sharedInformerFactory.Core().V1().Pods().Informer()
+	nodeLister := sharedInformerFactory.Core().V1().Nodes().Lister()
+	podsAssignedToNode, err := podutil.BuildGetPodsAssignedToNodeFunc(podInformer)
+	if err != nil {
+		t.Fatalf("Build get pods assigned to node function error: %v", err)
+	}
+	sharedInformerFactory.Start(ctx.Done())
+	sharedInformerFactory.WaitForCacheSync(ctx.Done())
+
+	collector := metricscollector.NewMetricsCollector(nodeLister, metricsClientset, labels.Everything())
+	if err := collector.Collect(ctx); err != nil {
+		t.Fatalf("failed to capture metrics: %v", err)
+	}
+
+	usageClient := newActualUsageClient([]v1.ResourceName{v1.ResourceCPU}, podsAssignedToNode, collector, 0, 0, nil)
+	if err := usageClient.Sync(ctx, []*v1.Node{n1}); err != nil {
+		t.Fatalf("failed to sync a snapshot: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		usage, err := usageClient.PodUsage(p1)
+		if err != nil {
+			t.Fatalf("unexpected error getting pod usage: %v", err)
+		}
+		if cpu := usage[v1.ResourceCPU].MilliValue(); cpu != 300 {
+			t.Errorf("expected pod cpu usage to be 300m, got %vm", cpu)
+		}
+		// callers must not be able to alter the cached usage.
+		usage[v1.ResourceCPU].Sub(resource.MustParse("100m"))
+	}
+	if gets != 1 {
+		t.Errorf("expected pod metrics to be fetched once, got %v", gets)
+	}
+
+	if err := usageClient.Sync(ctx, []*v1.Node{n1}); err != nil {
+		t.Fatalf("failed to sync a snapshot: %v", err)
+	}
+	if _, err := usageClient.PodUsage(p1); err != nil {
+		t.Fatalf("unexpected error getting pod usage: %v", err)
+	}
+	if gets != 2 {
+		t.Errorf("expected pod metrics to be fetched again after a sync, got %v", gets)
+	}
+}
+
+func TestCompositeUsageClient(t *testing.T) {
+	ctx := context.TODO()
+	n1 := test.BuildTestNode("n1", 2000, 3000, 10, nil)
+	p1 := test.BuildTestPod("p1", 400, 0, n1.Name, nil)
+	p2 := test.BuildTestPod("p2", 200, 0, n1.Name, nil)
+
+	requested := frameworktesting.BuildUsageClient().
+		WithNode(n1.Name, frameworktesting.BuildNodeUsage().WithCPU("600m").WithMemory("1Gi").WithPods(2), p1, p2).
+		WithPodUsage(p1, frameworktesting.BuildNodeUsage().WithCPU("400m")).
+		WithPodUsage(p2, frameworktesting.BuildNodeUsage().WithCPU("200m")).
+		Build()
+	actual := frameworktesting.BuildUsageClient().
+		WithNode(n1.Name, frameworktesting.BuildNodeUsage().WithCPU("1500m").WithPods(2), p1, p2).
+		WithPodUsage(p1, frameworktesting.BuildNodeUsage().WithCPU("1000m")).
+		WithPodUsageError(p2, newNotSupportedError(actualUsageClientType)).
+		Build()
+
+	client := newCompositeUsageClient([]UsageClient{requested, actual}, []float64{1, 2})
+	if err := client.Sync(ctx, []*v1.Node{n1}); err != nil {
+		t.Fatalf("failed to sync: %v", err)
+	}
+	if requested.SyncCalls() != 1 || actual.SyncCalls() != 1 {
+		t.Errorf("expected every client to be synced once, got %v and %v", requested.SyncCalls(), actual.SyncCalls())
+	}
+
+	usage := client.NodeUtilization(n1.Name)
+	// (600m * 1 + 1500m * 2) / 3
+	if cpu := usage[v1.ResourceCPU].MilliValue(); cpu != 1200 {
+		t.Errorf("expected blended cpu usage to be 1200m, got %vm", cpu)
+	}
+	// memory is only reported by the requested client.
+	if memory := usage[v1.ResourceMemory]; memory.Cmp(resource.MustParse("1Gi")) != 0 || memory.Format != resource.BinarySI {
+		t.Errorf("expected memory usage to be 1Gi, got %v", memory)
+	}
+	if pods := usage[v1.ResourcePods].Value(); pods != 2 {
+		t.Errorf("expected pods usage to be 2, got %v", pods)
+	}
+	if pods := client.Pods(n1.Name); len(pods) != 2 {
+		t.Errorf("expected 2 pods, got %v", len(pods))
+	}
+
+	// (400m * 1 + 1000m * 2) / 3
+	if podUsage, err := client.PodUsage(p1); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	} else if cpu := podUsage[v1.ResourceCPU].MilliValue(); cpu != 800 {
+		t.Errorf("expected blended p1 cpu usage to be 800m, got %vm", cpu)
+	}
+	// the actual usage of p2 is not supported, only its requests count.
+	if podUsage, err := client.PodUsage(p2); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	} else if cpu := podUsage[v1.ResourceCPU].MilliValue(); cpu != 200 {
+		t.Errorf("expected p2 cpu usage to be 200m, got %vm", cpu)
+	}
+
+	unsupported := newCompositeUsageClient([]UsageClient{actual}, []float64{1})
+	if _, err := unsupported.PodUsage(p2); err == nil {
+		t.Errorf("expected the pod usage not to be supported")
+	} else if _, ok := err.(*notSupportedError); !ok {
+		t.Errorf("expected a not supported error, got %v", err)
+	}
+}
+
+func TestLowNodeUtilizationUsageWeights(t *testing.T) {
+	handle := &frameworkfake.HandleImpl{
+		MetricsCollectorImpl: metricscollector.NewMetricsCollector(nil, fakemetricsclient.NewSimpleClientset(), labels.Everything()),
+		GetPodsAssignedToNodeFuncImpl: func(string, podutil.FilterFunc) ([]*v1.Pod, error) {
+			return nil, nil
+		},
+	}
+
+	plugin, err := NewLowNodeUtilization(
+		&LowNodeUtilizationArgs{
+			Thresholds:       api.ResourceThresholds{v1.ResourceCPU: 20},
+			TargetThresholds: api.ResourceThresholds{v1.ResourceCPU: 70},
+			MetricsUtilization: &MetricsUtilization{
+				Source:  api.KubernetesMetrics,
+				Weights: &UsageWeights{Requests: 1, Metrics: 3},
+			},
+		},
+		handle,
+	)
+	if err != nil {
+		t.Fatalf("unable to initialize the plugin: %v", err)
+	}
+
+	composite, ok := plugin.(*LowNodeUtilization).usageClient.(*compositeUsageClient)
+	if !ok {
+		t.Fatalf("expected a composite usage client, got %T", plugin.(*LowNodeUtilization).usageClient)
+	}
+	if !reflect.DeepEqual(composite.weights, []float64{1, 3}) {
+		t.Errorf("expected weights [1 3], got %v", composite.weights)
+	}
+	requested, ok := composite.clients[0].(*sharedUsageClient)
+	if !ok || reflect.TypeOf(requested.UsageClient) != reflect.TypeOf(&requestedUsageClient{}) {
+		t.Errorf("expected the first client to be the shared requested usage client, got %T", composite.clients[0])
+	}
+	actual, ok := composite.clients[1].(*sharedUsageClient)
+	if !ok || reflect.TypeOf(actual.UsageClient) != reflect.TypeOf(&actualUsageClient{}) {
+		t.Errorf("expected the second client to be the shared actual usage client, got %T", composite.clients[1])
+	}
+}
@@ -15,9 +15,17 @@ package nodeutilization
 
 import (
 	"fmt"
+	"net/url"
+	"strings"
+	"text/template"
 
+	"github.com/prometheus/common/model"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"sigs.k8s.io/descheduler/pkg/api"
+	"sigs.k8s.io/descheduler/pkg/descheduler/metricscollector"
 )
 
 func ValidateHighNodeUtilizationArgs(obj runtime.Object) error {
@@ -30,10 +38,328 @@ func ValidateHighNodeUtilizationArgs(obj runtime.Object) error {
 	if err != nil {
 		return err
 	}
+	if err := validateScoringStrategy(args.ScoringStrategy); err != nil {
+		return err
+	}
+	if args.SchedulingHints && args.ScoringStrategy == nil {
+		return fmt.Errorf("schedulingHints requires a scoringStrategy")
+	}
+	if err := validateNodeConditions(args.NodeConditions); err != nil {
+		return err
+	}
+	if err := validateOvercommit(args.Overcommit); err != nil {
+		return err
+	}
+	if err := validateEvictionOrder(args.EvictionOrder); err != nil {
+		return err
+	}
+	if args.DecisionLog != nil && args.DecisionLog.Path == "" {
+		return fmt.Errorf("decisionLog path is required")
+	}
+	if err := validateCooldown(args.Cooldown); err != nil {
+		return err
+	}
+	if args.EvictionRateLimit != nil && args.EvictionRateLimit.EvictionsPerMinute == 0 {
+		return fmt.Errorf("evictionRateLimit evictionsPerMinute must be positive")
+	}
+	if args.Hysteresis < MinResourcePercentage || args.Hysteresis > MaxResourcePercentage {
+		return fmt.Errorf("hysteresis not in [%v, %v] range", MinResourcePercentage, MaxResourcePercentage)
+	}
+	if args.UsageCacheTTL.Duration < 0 {
+		return fmt.Errorf("usageCacheTTL can not be negative, got %v", args.UsageCacheTTL.Duration)
+	}
+	if err := validateRunStatus(args.RunStatus); err != nil {
+		return err
+	}
+	if err := validateBalanceMode(args.Mode); err != nil {
+		return err
+	}
+	if _, err := podSorterFor(args.PodEvictionOrder); err != nil {
+		return err
+	}
+	if err := validateAffinityAwareness(args.AffinityAwareness); err != nil {
+		return err
+	}
+	if err := validateFairnessPolicy(args.FairnessPolicy); err != nil {
+		return err
+	}
+	if _, err := nodeCostProviderFor(args.NodeCost); err != nil {
+		return err
+	}
+	if err := validateScaleDownHints(args.ScaleDownHints); err != nil {
+		return err
+	}
+	if args.ScaleDownHintsTTL.Duration < 0 {
+		return fmt.Errorf("scaleDownHintsTTL can not be negative, got %v", args.ScaleDownHintsTTL.Duration)
+	}
+	if err := validateResourceWeights(args.ResourceWeights, args.Thresholds); err != nil {
+		return err
+	}
 	// make sure we know about the eviction modes defined by the user.
 	return validateEvictionModes(args.EvictionModes)
 }
 
+// validateDeviationCenter checks the deviation center is known and only
+// set along with the deviation thresholds.
+func validateDeviationCenter(args *LowNodeUtilizationArgs) error {
+	switch args.DeviationCenter {
+	case "", DeviationCenterAverage, DeviationCenterMedian, DeviationCenterTrimmedMean:
+	default:
+		return fmt.Errorf("unknown deviation center %q", args.DeviationCenter)
+	}
+	if args.DeviationCenter != "" && !args.UseDeviationThresholds {
+		return fmt.Errorf("deviationCenter requires useDeviationThresholds")
+	}
+	if args.DeviationTrimPercentage != 0 && args.DeviationCenter != DeviationCenterTrimmedMean {
+		return fmt.Errorf("deviationTrimPercentage requires the %q deviation center", DeviationCenterTrimmedMean)
+	}
+	if args.DeviationTrimPercentage < 0 || args.DeviationTrimPercentage >= 50 {
+		return fmt.Errorf("deviationTrimPercentage not in [0, 50) range")
+	}
+	return nil
+}
+
+// validatePrometheusRange checks the range window is positive and covers
+// at least one step, and that the aggregation is known.
+func validatePrometheusRange(r *PrometheusRange) error {
+	if r == nil {
+		return nil
+	}
+	if r.Window.Duration <= 0 {
+		return fmt.Errorf("prometheus range window must be positive, got %v", r.Window.Duration)
+	}
+	if r.Step.Duration < 0 || r.Step.Duration > r.Window.Duration {
+		return fmt.Errorf("prometheus range step not in [0, %v] range", r.Window.Duration)
+	}
+	switch r.Aggregation {
+	case "", PrometheusAggregationAverage, PrometheusAggregationMax, PrometheusAggregationP95:
+	default:
+		return fmt.Errorf("unknown prometheus range aggregation %q", r.Aggregation)
+	}
+	return nil
+}
+
+// validateCooldown checks the cooldown duration is positive.
+func validateCooldown(cooldown *Cooldown) error {
+	if cooldown != nil && cooldown.Duration.Duration <= 0 {
+		return fmt.Errorf("cooldown duration must be positive, got %v", cooldown.Duration.Duration)
+	}
+	return nil
+}
+
+// validateInPlaceResize checks the pods are shrunk by a valid share of
+// their requests, only of their cpu and memory, and that the usage is
+// computed from the pod requests, shrinking them does not lower the usage
+// reported by the metrics.
+func validateInPlaceResize(args *LowNodeUtilizationArgs) error {
+	config := args.InPlaceResize
+	if config == nil {
+		return nil
+	}
+	if args.MetricsUtilization != nil {
+		return fmt.Errorf("inPlaceResize can not be combined with metricsUtilization")
+	}
+	if config.MaxReduction <= MinResourcePercentage || config.MaxReduction > MaxResourcePercentage {
+		return fmt.Errorf("inPlaceResize maxReduction not in (%v, %v] range", MinResourcePercentage, MaxResourcePercentage)
+	}
+	for _, name := range config.Resources {
+		if name != v1.ResourceCPU && name != v1.ResourceMemory {
+			return fmt.Errorf("inPlaceResize resource %q is not supported, must be %q or %q", name, v1.ResourceCPU, v1.ResourceMemory)
+		}
+	}
+	for name, quantity := range config.MinRequests {
+		if quantity.Sign() < 0 {
+			return fmt.Errorf("inPlaceResize minRequests of %v can not be negative", name)
+		}
+	}
+	return nil
+}
+
+// validateContinueEvictionStrategy checks the strategy type is known and
+// the amount it evicts is set when it evicts a fixed amount.
+func validateContinueEvictionStrategy(strategy *ContinueEvictionStrategy) error {
+	if strategy == nil {
+		return nil
+	}
+	switch strategy.Type {
+	case "", UntilBelowTarget, UntilBelowLow:
+	case FixedPodCount:
+		if strategy.PodCount == 0 {
+			return fmt.Errorf("continueEvictionStrategy podCount must be positive with %q", FixedPodCount)
+		}
+	case FixedResourceAmount:
+		if len(strategy.ResourceAmount) == 0 {
+			return fmt.Errorf("continueEvictionStrategy resourceAmount is required with %q", FixedResourceAmount)
+		}
+		for name, quantity := range strategy.ResourceAmount {
+			if quantity.Sign() <= 0 {
+				return fmt.Errorf("continueEvictionStrategy resourceAmount of %v must be positive", name)
+			}
+		}
+	default:
+		return fmt.Errorf(
+			"invalid continueEvictionStrategy type %q, must be %q, %q, %q or %q",
+			strategy.Type, UntilBelowTarget, UntilBelowLow, FixedPodCount, FixedResourceAmount,
+		)
+	}
+	return nil
+}
+
+// validateRunStatus checks the run status ConfigMap namespace and name are
+// valid.
+func validateRunStatus(status *RunStatus) error {
+	if status == nil {
+		return nil
+	}
+	if errs := validation.IsDNS1123Label(status.Namespace); len(errs) > 0 {
+		return fmt.Errorf("invalid runStatus namespace %q: %s", status.Namespace, strings.Join(errs, ", "))
+	}
+	if errs := validation.IsDNS1123Subdomain(status.Name); len(errs) > 0 {
+		return fmt.Errorf("invalid runStatus name %q: %s", status.Name, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// validateScoringStrategy checks if the scoring strategy type is known and
+// if the resource weights are in the range accepted by the scheduler.
+func validateScoringStrategy(strategy *ScoringStrategy) error {
+	if strategy == nil {
+		return nil
+	}
+	if strategy.Type != LeastAllocated && strategy.Type != MostAllocated && strategy.Type != SpreadByZone {
+		return fmt.Errorf("invalid scoring strategy type %q, must be %q, %q or %q", strategy.Type, LeastAllocated, MostAllocated, SpreadByZone)
+	}
+	for _, spec := range strategy.Resources {
+		if spec.Name == "" {
+			return fmt.Errorf("scoring strategy resource name can not be empty")
+		}
+		if spec.Weight < 1 || spec.Weight > 100 {
+			return fmt.Errorf("scoring strategy weight of resource %s not in [1, 100] range", spec.Name)
+		}
+	}
+	return nil
+}
+
+// validateAffinityAwareness checks the affinity awareness policy is known.
+func validateAffinityAwareness(policy AffinityAwareness) error {
+	if policy != "" && policy != AffinityAwarenessSkip && policy != AffinityAwarenessDeprioritize {
+		return fmt.Errorf("invalid affinityAwareness %q, must be %q or %q", policy, AffinityAwarenessSkip, AffinityAwarenessDeprioritize)
+	}
+	return nil
+}
+
+// validateResourceWeights checks that the weights are not negative and
+// that at least one of the thresholds resources has a positive weight.
+func validateResourceWeights(weights map[v1.ResourceName]float64, thresholds api.ResourceThresholds) error {
+	if len(weights) == 0 {
+		return nil
+	}
+	for name, weight := range weights {
+		if name == "" {
+			return fmt.Errorf("resource weight name can not be empty")
+		}
+		if weight < 0 {
+			return fmt.Errorf("resource weight of %s can not be negative", name)
+		}
+	}
+	for name := range thresholds {
+		if resourceWeight(weights, name) > 0 {
+			return nil
+		}
+	}
+	return fmt.Errorf("resourceWeights can not be zero for all the thresholds resources")
+}
+
+// validateNodeConditions checks that every rule has a type and a known
+// status and action.
+func validateNodeConditions(rules []NodeConditionRule) error {
+	for _, rule := range rules {
+		if rule.Type == "" {
+			return fmt.Errorf("node condition type must not be empty")
+		}
+		switch rule.Status {
+		case "", v1.ConditionTrue, v1.ConditionFalse, v1.ConditionUnknown:
+		default:
+			return fmt.Errorf("invalid status %q for node condition %s", rule.Status, rule.Type)
+		}
+		if rule.Action != NodeConditionActionAvoid && rule.Action != NodeConditionActionDrain {
+			return fmt.Errorf(
+				"invalid action %q for node condition %s, must be %q or %q",
+				rule.Action, rule.Type, NodeConditionActionAvoid, NodeConditionActionDrain,
+			)
+		}
+	}
+	return nil
+}
+
+// validateOvercommit checks that every rule has a valid node selector and
+// positive factors.
+func validateOvercommit(rules []OvercommitRule) error {
+	for _, rule := range rules {
+		if rule.NodeSelector == nil {
+			return fmt.Errorf("overcommit node selector must be set")
+		}
+		if _, err := metav1.LabelSelectorAsSelector(rule.NodeSelector); err != nil {
+			return fmt.Errorf("invalid overcommit node selector: %v", err)
+		}
+		if len(rule.Factors) == 0 {
+			return fmt.Errorf("overcommit factors must not be empty")
+		}
+		for name, factor := range rule.Factors {
+			if name == "" {
+				return fmt.Errorf("overcommit resource name can not be empty")
+			}
+			if factor <= 0 {
+				return fmt.Errorf("overcommit factor of resource %s must be positive, got %v", name, factor)
+			}
+		}
+	}
+	return nil
+}
+
+// validateBalanceMode checks the mode is known.
+func validateBalanceMode(mode BalanceMode) error {
+	switch mode {
+	case "", BalanceModeEvict, BalanceModeAnnotate:
+		return nil
+	default:
+		return fmt.Errorf("invalid mode %q, must be %q or %q", mode, BalanceModeEvict, BalanceModeAnnotate)
+	}
+}
+
+// validateScaleDownHints checks the scale down hints are known.
+func validateScaleDownHints(hints []ScaleDownHint) error {
+	for _, hint := range hints {
+		switch hint {
+		case ScaleDownHintAnnotate, ScaleDownHintTaint, ScaleDownHintCordon:
+		default:
+			return fmt.Errorf(
+				"invalid scaleDownHint %q, must be %q, %q or %q",
+				hint, ScaleDownHintAnnotate, ScaleDownHintTaint, ScaleDownHintCordon,
+			)
+		}
+	}
+	return nil
+}
+
+// validateFairnessPolicy checks the fairness policy is known.
+func validateFairnessPolicy(policy FairnessPolicy) error {
+	if policy != "" && policy != FairnessPolicyNone && policy != FairnessPolicyProportional {
+		return fmt.Errorf("invalid fairnessPolicy %q, must be %q or %q", policy, FairnessPolicyNone, FairnessPolicyProportional)
+	}
+	return nil
+}
+
+// validateEvictionOrder checks if the eviction order is known.
+func validateEvictionOrder(order EvictionOrder) error {
+	switch order {
+	case "", EvictionOrderPerNode, EvictionOrderPriorityBands:
+		return nil
+	default:
+		return fmt.Errorf("invalid eviction order %q, must be %q or %q", order, EvictionOrderPerNode, EvictionOrderPriorityBands)
+	}
+}
+
 // validateEvictionModes checks if the eviction modes are valid/known
 // to the descheduler.
 func validateEvictionModes(modes []EvictionMode) error {
@@ -58,10 +384,108 @@ func ValidateLowNodeUtilizationArgs(obj runtime.Object) error {
 	if args.EvictableNamespaces != nil && len(args.EvictableNamespaces.Include) > 0 {
 		return fmt.Errorf("only Exclude namespaces can be set, inclusion is not supported")
 	}
-	err := validateLowNodeUtilizationThresholds(args.Thresholds, args.TargetThresholds, args.UseDeviationThresholds)
+	modes := 0
+	for _, enabled := range []bool{args.UseDeviationThresholds, args.UseStdDeviationThresholds, args.UsePercentileThresholds} {
+		if enabled {
+			modes++
+		}
+	}
+	if modes > 1 {
+		return fmt.Errorf("only one of useDeviationThresholds, useStdDeviationThresholds and usePercentileThresholds can be set")
+	}
+	if err := validateDeviationCenter(args); err != nil {
+		return err
+	}
+	if err := validateThresholdQuantities(args); err != nil {
+		return err
+	}
+	thresholds := thresholdsWithQuantities(args.Thresholds, args.ThresholdQuantities, MinResourcePercentage)
+	targetThresholds := thresholdsWithQuantities(args.TargetThresholds, args.TargetThresholdQuantities, MaxResourcePercentage)
+	err := validateLowNodeUtilizationThresholds(
+		thresholds, targetThresholds, args.UseDeviationThresholds || args.UseStdDeviationThresholds,
+	)
 	if err != nil {
 		return err
 	}
+	for _, pool := range args.NodePools {
+		if pool.NodeSelector == nil {
+			return fmt.Errorf("node pool node selector must be set")
+		}
+		if _, err := metav1.LabelSelectorAsSelector(pool.NodeSelector); err != nil {
+			return fmt.Errorf("invalid node pool node selector: %v", err)
+		}
+		err := validateLowNodeUtilizationThresholds(
+			pool.Thresholds, pool.TargetThresholds, args.UseDeviationThresholds || args.UseStdDeviationThresholds,
+		)
+		if err != nil {
+			return fmt.Errorf("node pool thresholds are not valid: %v", err)
+		}
+	}
+	if err := validateScoringStrategy(args.ScoringStrategy); err != nil {
+		return err
+	}
+	if args.SchedulingHints && args.ScoringStrategy == nil {
+		return fmt.Errorf("schedulingHints requires a scoringStrategy")
+	}
+	if err := validateNodeConditions(args.NodeConditions); err != nil {
+		return err
+	}
+	if err := validateOvercommit(args.Overcommit); err != nil {
+		return err
+	}
+	if err := validateEvictionOrder(args.EvictionOrder); err != nil {
+		return err
+	}
+	if args.DecisionLog != nil && args.DecisionLog.Path == "" {
+		return fmt.Errorf("decisionLog path is required")
+	}
+	if err := validateCooldown(args.Cooldown); err != nil {
+		return err
+	}
+	if args.DoNotDisruptHintTTL.Duration < 0 {
+		return fmt.Errorf("doNotDisruptHintTTL can not be negative, got %v", args.DoNotDisruptHintTTL.Duration)
+	}
+	if args.EvictionRateLimit != nil && args.EvictionRateLimit.EvictionsPerMinute == 0 {
+		return fmt.Errorf("evictionRateLimit evictionsPerMinute must be positive")
+	}
+	if args.Hysteresis < MinResourcePercentage || args.Hysteresis > MaxResourcePercentage {
+		return fmt.Errorf("hysteresis not in [%v, %v] range", MinResourcePercentage, MaxResourcePercentage)
+	}
+	if args.UsageCacheTTL.Duration < 0 {
+		return fmt.Errorf("usageCacheTTL can not be negative, got %v", args.UsageCacheTTL.Duration)
+	}
+	if err := validateContinueEvictionStrategy(args.ContinueEvictionStrategy); err != nil {
+		return err
+	}
+	if err := validateInPlaceResize(args); err != nil {
+		return err
+	}
+	if err := validateRunStatus(args.RunStatus); err != nil {
+		return err
+	}
+	if err := validateBalanceMode(args.Mode); err != nil {
+		return err
+	}
+	if _, err := podSorterFor(args.PodEvictionOrder); err != nil {
+		return err
+	}
+	if err := validateAffinityAwareness(args.AffinityAwareness); err != nil {
+		return err
+	}
+	if err := validateFairnessPolicy(args.FairnessPolicy); err != nil {
+		return err
+	}
+	if _, err := nodeCostProviderFor(args.NodeCost); err != nil {
+		return err
+	}
+	if err := validateResourceWeights(args.ResourceWeights, thresholds); err != nil {
+		return err
+	}
+	if args.TopologyKey != "" {
+		if errs := validation.IsQualifiedName(args.TopologyKey); len(errs) > 0 {
+			return fmt.Errorf("topologyKey %q is not valid: %s", args.TopologyKey, strings.Join(errs, ", "))
+		}
+	}
 	if args.MetricsUtilization != nil {
 		if args.MetricsUtilization.Source == api.KubernetesMetrics && args.MetricsUtilization.MetricsServer {
 			return fmt.Errorf("it is not allowed to set both %q source and metricsServer", api.KubernetesMetrics)
@@ -69,9 +493,283 @@ func ValidateLowNodeUtilizationArgs(obj runtime.Object) error {
 		if args.MetricsUtilization.Source == api.KubernetesMetrics && args.MetricsUtilization.Prometheus != nil {
 			return fmt.Errorf("prometheus configuration is not allowed to set when source is set to %q", api.KubernetesMetrics)
 		}
+		if source := args.MetricsUtilization.Source; (source == api.VPARecommendations || source == api.CustomMetrics || source == api.OpenTelemetryMetrics || source == api.StaticMetrics) && (args.MetricsUtilization.MetricsServer || args.MetricsUtilization.Prometheus != nil) {
+			return fmt.Errorf("neither metricsServer nor prometheus configuration are allowed to set when source is set to %q", source)
+		}
+		if err := validateCustomMetrics(args.MetricsUtilization); err != nil {
+			return err
+		}
+		if err := validateOpenTelemetryMetrics(args.MetricsUtilization); err != nil {
+			return err
+		}
+		if err := validateStaticMetrics(args.MetricsUtilization); err != nil {
+			return err
+		}
 		if args.MetricsUtilization.Source == api.PrometheusMetrics && (args.MetricsUtilization.Prometheus == nil || args.MetricsUtilization.Prometheus.Query == "") {
 			return fmt.Errorf("prometheus query is required when metrics source is set to %q", api.PrometheusMetrics)
 		}
+		if err := validateUsageWeights(args.MetricsUtilization); err != nil {
+			return err
+		}
+		if err := validateSmoothing(args.MetricsUtilization); err != nil {
+			return err
+		}
+		if err := validateExtendedResources(args.MetricsUtilization); err != nil {
+			return err
+		}
+		if prometheus := args.MetricsUtilization.Prometheus; prometheus != nil {
+			if prometheus.NodesPerQuery < 0 {
+				return fmt.Errorf("prometheus nodesPerQuery can not be negative")
+			}
+			if prometheus.Timeout.Duration < 0 || prometheus.Backoff.Duration < 0 {
+				return fmt.Errorf("prometheus timeout and backoff can not be negative")
+			}
+			if prometheus.Retries < 0 {
+				return fmt.Errorf("prometheus retries can not be negative")
+			}
+			if err := validatePrometheusRange(prometheus.Range); err != nil {
+				return err
+			}
+			if prometheus.NodeLabel != "" && !model.LabelName(prometheus.NodeLabel).IsValid() {
+				return fmt.Errorf("prometheus nodeLabel %q is not a valid label name", prometheus.NodeLabel)
+			}
+			if _, err := template.New("query").Parse(prometheus.Query); err != nil {
+				return fmt.Errorf("prometheus query is not a valid template: %v", err)
+			}
+			if _, err := template.New("podQuery").Parse(prometheus.PodQuery); err != nil {
+				return fmt.Errorf("prometheus podQuery is not a valid template: %v", err)
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateNodeConsolidationArgs validates the NodeConsolidation plugin
+// arguments.
+func ValidateNodeConsolidationArgs(obj runtime.Object) error {
+	args := obj.(*NodeConsolidationArgs)
+	// only exclude can be set, or not at all
+	if args.EvictableNamespaces != nil && len(args.EvictableNamespaces.Include) > 0 {
+		return fmt.Errorf("only Exclude namespaces can be set, inclusion is not supported")
+	}
+	for name, percent := range args.TargetThresholds {
+		if percent < MinResourcePercentage || percent > MaxResourcePercentage {
+			return fmt.Errorf("%v target threshold not in [%v, %v] range", name, MinResourcePercentage, MaxResourcePercentage)
+		}
+	}
+	if err := validateScaleDownHints(args.ScaleDownHints); err != nil {
+		return err
+	}
+	if args.ScaleDownHintsTTL.Duration < 0 {
+		return fmt.Errorf("scaleDownHintsTTL can not be negative, got %v", args.ScaleDownHintsTTL.Duration)
+	}
+	if args.MaxNodesToDrain < 0 {
+		return fmt.Errorf("maxNodesToDrain can not be negative, got %d", args.MaxNodesToDrain)
+	}
+	if args.UsageCacheTTL.Duration < 0 {
+		return fmt.Errorf("usageCacheTTL can not be negative, got %v", args.UsageCacheTTL.Duration)
+	}
+	if err := validateRunStatus(args.RunStatus); err != nil {
+		return err
+	}
+	if err := validateBalanceMode(args.Mode); err != nil {
+		return err
+	}
+	metrics := args.MetricsUtilization
+	if metrics == nil {
+		return nil
+	}
+	switch metrics.Source {
+	case api.KubernetesMetrics, api.VPARecommendations, api.StaticMetrics:
+	default:
+		return fmt.Errorf(
+			"metrics source must be %q, %q or %q, pods are packed by their usage in resource units",
+			api.KubernetesMetrics, api.VPARecommendations, api.StaticMetrics,
+		)
+	}
+	return validateStaticMetrics(metrics)
+}
+
+// validateCustomMetrics checks the custom metrics configuration is only set,
+// and complete, with the CustomMetrics source.
+func validateCustomMetrics(metrics *MetricsUtilization) error {
+	custom := metrics.CustomMetrics
+	if metrics.Source != api.CustomMetrics {
+		if custom != nil {
+			return fmt.Errorf("custom metrics configuration is only allowed when source is set to %q", api.CustomMetrics)
+		}
+		return nil
+	}
+	if custom == nil || custom.MetricName == "" {
+		return fmt.Errorf("custom metrics metricName is required when metrics source is set to %q", api.CustomMetrics)
+	}
+	if _, err := metav1.LabelSelectorAsSelector(custom.Selector); err != nil {
+		return fmt.Errorf("invalid custom metrics selector: %v", err)
+	}
+	if custom.External != nil {
+		if custom.External.Namespace == "" {
+			return fmt.Errorf("external metrics namespace is required")
+		}
+		if custom.PodMetricName != "" {
+			return fmt.Errorf("custom metrics podMetricName is not supported with external metrics")
+		}
+	}
+	return nil
+}
+
+// validateOpenTelemetryMetrics checks the OpenTelemetry configuration is only
+// set, and complete, with the OpenTelemetry source.
+func validateOpenTelemetryMetrics(metrics *MetricsUtilization) error {
+	otel := metrics.OpenTelemetry
+	if metrics.Source != api.OpenTelemetryMetrics {
+		if otel != nil {
+			return fmt.Errorf("openTelemetry configuration is only allowed when source is set to %q", api.OpenTelemetryMetrics)
+		}
+		return nil
+	}
+	if otel == nil || otel.Endpoint == "" || otel.MetricName == "" {
+		return fmt.Errorf("openTelemetry endpoint and metricName are required when metrics source is set to %q", api.OpenTelemetryMetrics)
+	}
+	endpoint, err := url.Parse(otel.Endpoint)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return fmt.Errorf("openTelemetry endpoint %q is not a valid http(s) url", otel.Endpoint)
+	}
+	switch otel.Aggregation {
+	case "", OpenTelemetryAggregationAverage, OpenTelemetryAggregationSum:
+	default:
+		return fmt.Errorf("unknown openTelemetry aggregation %q", otel.Aggregation)
+	}
+	return nil
+}
+
+// validateStaticMetrics checks the static usage configuration is only set,
+// and complete, with the Static source.
+func validateStaticMetrics(metrics *MetricsUtilization) error {
+	static := metrics.Static
+	if metrics.Source != api.StaticMetrics {
+		if static != nil {
+			return fmt.Errorf("static configuration is only allowed when source is set to %q", api.StaticMetrics)
+		}
+		return nil
+	}
+	if static == nil || (static.File == "") == (static.ConfigMap == nil) {
+		return fmt.Errorf("exactly one of static file and configMap is required when metrics source is set to %q", api.StaticMetrics)
+	}
+	if static.ConfigMap != nil && (static.ConfigMap.Namespace == "" || static.ConfigMap.Name == "") {
+		return fmt.Errorf("static configMap namespace and name are required")
+	}
+	return nil
+}
+
+// validateUsageWeights checks the weights blending the actual utilization
+// with the utilization computed from the pod requests, if any.
+func validateUsageWeights(metrics *MetricsUtilization) error {
+	weights := metrics.Weights
+	if weights == nil {
+		return nil
+	}
+	if metrics.Source != api.KubernetesMetrics && !metrics.MetricsServer {
+		return fmt.Errorf("usage weights are only supported with the %q metrics source", api.KubernetesMetrics)
+	}
+	if weights.Requests < 0 || weights.Metrics < 0 {
+		return fmt.Errorf("usage weights can not be negative")
+	}
+	if weights.Requests+weights.Metrics == 0 {
+		return fmt.Errorf("at least one of the usage weights must be positive")
+	}
+	return nil
+}
+
+// validateSmoothing checks the smoothing of the node usage collected from
+// the metrics server, if any.
+func validateSmoothing(metrics *MetricsUtilization) error {
+	if metrics.SmoothingFactor == 0 && metrics.MinSamples == 0 {
+		return nil
+	}
+	if metrics.Source != api.KubernetesMetrics && !metrics.MetricsServer {
+		return fmt.Errorf("smoothingFactor and minSamples are only supported with the %q metrics source", api.KubernetesMetrics)
+	}
+	if metrics.SmoothingFactor < 0 || metrics.SmoothingFactor > 1 {
+		return fmt.Errorf("smoothingFactor is expected to be in (0; 1] interval, got %v", metrics.SmoothingFactor)
+	}
+	if metrics.MinSamples < 0 || metrics.MinSamples > metricscollector.MaxSamples {
+		return fmt.Errorf("minSamples is expected to be between 0 and %v, got %v", metricscollector.MaxSamples, metrics.MinSamples)
+	}
+	return nil
+}
+
+// validateExtendedResources checks the prometheus queries collecting the
+// usage of the resources the metrics server does not report, if any.
+func validateExtendedResources(metrics *MetricsUtilization) error {
+	if len(metrics.ExtendedResources) == 0 {
+		return nil
+	}
+	if metrics.Source != api.KubernetesMetrics && !metrics.MetricsServer {
+		return fmt.Errorf("extendedResources are only supported with the %q metrics source", api.KubernetesMetrics)
+	}
+	seen := map[v1.ResourceName]bool{}
+	for _, extended := range metrics.ExtendedResources {
+		switch extended.Name {
+		case "":
+			return fmt.Errorf("extended resource name can not be empty")
+		case v1.ResourceCPU, v1.ResourceMemory, v1.ResourcePods:
+			return fmt.Errorf("the usage of %s is not collected through extendedResources", extended.Name)
+		}
+		if seen[extended.Name] {
+			return fmt.Errorf("extended resource %s is configured more than once", extended.Name)
+		}
+		seen[extended.Name] = true
+		if extended.Query == "" {
+			return fmt.Errorf("extended resource %s query is required", extended.Name)
+		}
+		if extended.NodeLabel != "" && !model.LabelName(extended.NodeLabel).IsValid() {
+			return fmt.Errorf("extended resource %s nodeLabel %q is not a valid label name", extended.Name, extended.NodeLabel)
+		}
+		if _, err := template.New("podQuery").Parse(extended.PodQuery); err != nil {
+			return fmt.Errorf("extended resource %s podQuery is not a valid template: %v", extended.Name, err)
+		}
+	}
+	return nil
+}
+
+// validateThresholdQuantities checks the threshold quantities are not
+// negative, do not overlap with the percentages and are only used with
+// static thresholds and with usage reported in quantities.
+func validateThresholdQuantities(args *LowNodeUtilizationArgs) error {
+	if len(args.ThresholdQuantities) == 0 && len(args.TargetThresholdQuantities) == 0 {
+		return nil
+	}
+	if args.UseDeviationThresholds || args.UseStdDeviationThresholds || args.UsePercentileThresholds {
+		return fmt.Errorf("threshold quantities can not be combined with deviation or percentile thresholds")
+	}
+	if metrics := args.MetricsUtilization; metrics != nil {
+		switch metrics.Source {
+		case api.PrometheusMetrics, api.CustomMetrics, api.OpenTelemetryMetrics:
+			return fmt.Errorf("threshold quantities are not supported with the %v metrics source", metrics.Source)
+		}
+	}
+	for _, pair := range []struct {
+		field       string
+		percentages api.ResourceThresholds
+		quantities  v1.ResourceList
+	}{
+		{"thresholdQuantities", args.Thresholds, args.ThresholdQuantities},
+		{"targetThresholdQuantities", args.TargetThresholds, args.TargetThresholdQuantities},
+	} {
+		for name, quantity := range pair.quantities {
+			if quantity.Sign() < 0 {
+				return fmt.Errorf("%s' %v can not be negative", pair.field, name)
+			}
+			if _, ok := pair.percentages[name]; ok {
+				return fmt.Errorf("%s' %v is configured as a percentage as well", pair.field, name)
+			}
+		}
+	}
+	// the low threshold leaves free more than the target threshold does.
+	for name, low := range args.ThresholdQuantities {
+		if high, ok := args.TargetThresholdQuantities[name]; ok && low.Cmp(high) < 0 {
+			return fmt.Errorf("thresholdQuantities' %v is lower than targetThresholdQuantities'", name)
+		}
 	}
 	return nil
 }
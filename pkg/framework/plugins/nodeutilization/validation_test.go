@@ -19,8 +19,11 @@ package nodeutilization
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
 	"sigs.k8s.io/descheduler/pkg/api"
@@ -223,7 +226,7 @@ func TestValidateLowNodeUtilizationPluginConfig(t *testing.T) {
 			errInfo: fmt.Errorf("prometheus query is required when metrics source is set to \"Prometheus\""),
 		},
 		{
-			name: "prometheus set when source set to kubernetes metrics",
+			name: "negative prometheus nodes per query",
 			args: &LowNodeUtilizationArgs{
 				Thresholds: api.ResourceThresholds{
 					v1.ResourceCPU:    20,
@@ -236,17 +239,1449 @@ func TestValidateLowNodeUtilizationPluginConfig(t *testing.T) {
 					extendedResource:  80,
 				},
 				MetricsUtilization: &MetricsUtilization{
-					Source:     api.KubernetesMetrics,
-					Prometheus: &Prometheus{},
+					Source: api.PrometheusMetrics,
+					Prometheus: &Prometheus{
+						Query:         `instance:node_cpu:rate:sum{instance=~"{{.Nodes}}"}`,
+						NodesPerQuery: -1,
+					},
 				},
 			},
-			errInfo: fmt.Errorf("prometheus configuration is not allowed to set when source is set to \"KubernetesMetrics\""),
+			errInfo: fmt.Errorf("prometheus nodesPerQuery can not be negative"),
 		},
-	}
-
-	for _, testCase := range tests {
-		t.Run(testCase.name, func(t *testing.T) {
-			validateErr := ValidateLowNodeUtilizationArgs(runtime.Object(testCase.args))
+		{
+			name: "negative prometheus retries",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					MetricResource: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					MetricResource: 80,
+				},
+				MetricsUtilization: &MetricsUtilization{
+					Source: api.PrometheusMetrics,
+					Prometheus: &Prometheus{
+						Query:   "instance:node_cpu:rate:sum",
+						Retries: -1,
+					},
+				},
+			},
+			errInfo: fmt.Errorf("prometheus retries can not be negative"),
+		},
+		{
+			name: "prometheus range step past the window",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					MetricResource: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					MetricResource: 80,
+				},
+				MetricsUtilization: &MetricsUtilization{
+					Source: api.PrometheusMetrics,
+					Prometheus: &Prometheus{
+						Query: "instance:node_cpu:rate:sum",
+						Range: &PrometheusRange{
+							Window: metav1.Duration{Duration: time.Minute},
+							Step:   metav1.Duration{Duration: time.Hour},
+						},
+					},
+				},
+			},
+			errInfo: fmt.Errorf("prometheus range step not in [0, 1m0s] range"),
+		},
+		{
+			name: "unknown prometheus range aggregation",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					MetricResource: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					MetricResource: 80,
+				},
+				MetricsUtilization: &MetricsUtilization{
+					Source: api.PrometheusMetrics,
+					Prometheus: &Prometheus{
+						Query: "instance:node_cpu:rate:sum",
+						Range: &PrometheusRange{
+							Window:      metav1.Duration{Duration: 10 * time.Minute},
+							Aggregation: "Min",
+						},
+					},
+				},
+			},
+			errInfo: fmt.Errorf("unknown prometheus range aggregation \"Min\""),
+		},
+		{
+			name: "custom metrics without a metric name",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					MetricResource: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					MetricResource: 80,
+				},
+				MetricsUtilization: &MetricsUtilization{
+					Source:        api.CustomMetrics,
+					CustomMetrics: &CustomMetrics{},
+				},
+			},
+			errInfo: fmt.Errorf("custom metrics metricName is required when metrics source is set to \"CustomMetrics\""),
+		},
+		{
+			name: "custom metrics configuration with another source",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					MetricResource: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					MetricResource: 80,
+				},
+				MetricsUtilization: &MetricsUtilization{
+					Source:        api.KubernetesMetrics,
+					CustomMetrics: &CustomMetrics{MetricName: "node_cpu_utilisation"},
+				},
+			},
+			errInfo: fmt.Errorf("custom metrics configuration is only allowed when source is set to \"CustomMetrics\""),
+		},
+		{
+			name: "external metrics without a namespace",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					MetricResource: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					MetricResource: 80,
+				},
+				MetricsUtilization: &MetricsUtilization{
+					Source: api.CustomMetrics,
+					CustomMetrics: &CustomMetrics{
+						MetricName: "node_cpu_utilisation",
+						External:   &ExternalMetric{},
+					},
+				},
+			},
+			errInfo: fmt.Errorf("external metrics namespace is required"),
+		},
+		{
+			name: "external metrics with a pod metric",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					MetricResource: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					MetricResource: 80,
+				},
+				MetricsUtilization: &MetricsUtilization{
+					Source: api.CustomMetrics,
+					CustomMetrics: &CustomMetrics{
+						MetricName:    "node_cpu_utilisation",
+						PodMetricName: "pod_cpu_share",
+						External:      &ExternalMetric{Namespace: "monitoring"},
+					},
+				},
+			},
+			errInfo: fmt.Errorf("custom metrics podMetricName is not supported with external metrics"),
+		},
+		{
+			name: "valid custom metrics",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					MetricResource: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					MetricResource: 80,
+				},
+				MetricsUtilization: &MetricsUtilization{
+					Source: api.CustomMetrics,
+					CustomMetrics: &CustomMetrics{
+						MetricName:    "node_cpu_utilisation",
+						PodMetricName: "pod_cpu_share",
+						Selector:      &metav1.LabelSelector{MatchLabels: map[string]string{"mode": "busy"}},
+					},
+				},
+			},
+			errInfo: nil,
+		},
+		{
+			name: "opentelemetry without an endpoint",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					MetricResource: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					MetricResource: 80,
+				},
+				MetricsUtilization: &MetricsUtilization{
+					Source:        api.OpenTelemetryMetrics,
+					OpenTelemetry: &OpenTelemetryMetrics{MetricName: "k8s.node.cpu.utilization"},
+				},
+			},
+			errInfo: fmt.Errorf("openTelemetry endpoint and metricName are required when metrics source is set to \"OpenTelemetry\""),
+		},
+		{
+			name: "opentelemetry with an invalid endpoint",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					MetricResource: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					MetricResource: 80,
+				},
+				MetricsUtilization: &MetricsUtilization{
+					Source: api.OpenTelemetryMetrics,
+					OpenTelemetry: &OpenTelemetryMetrics{
+						Endpoint:   "otel-collector:8889/metrics",
+						MetricName: "k8s.node.cpu.utilization",
+					},
+				},
+			},
+			errInfo: fmt.Errorf("openTelemetry endpoint \"otel-collector:8889/metrics\" is not a valid http(s) url"),
+		},
+		{
+			name: "opentelemetry configuration with another source",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					MetricResource: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					MetricResource: 80,
+				},
+				MetricsUtilization: &MetricsUtilization{
+					Source:        api.CustomMetrics,
+					CustomMetrics: &CustomMetrics{MetricName: "node_cpu_utilisation"},
+					OpenTelemetry: &OpenTelemetryMetrics{MetricName: "k8s.node.cpu.utilization"},
+				},
+			},
+			errInfo: fmt.Errorf("openTelemetry configuration is only allowed when source is set to \"OpenTelemetry\""),
+		},
+		{
+			name: "valid opentelemetry",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					MetricResource: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					MetricResource: 80,
+				},
+				MetricsUtilization: &MetricsUtilization{
+					Source: api.OpenTelemetryMetrics,
+					OpenTelemetry: &OpenTelemetryMetrics{
+						Endpoint:      "http://otel-collector.monitoring:8889/metrics",
+						MetricName:    "system.memory.utilization",
+						Attributes:    map[string]string{"state": "used"},
+						PodMetricName: "k8s.pod.memory.node.utilization",
+					},
+				},
+			},
+			errInfo: nil,
+		},
+		{
+			name: "opentelemetry with an unknown aggregation",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					MetricResource: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					MetricResource: 80,
+				},
+				MetricsUtilization: &MetricsUtilization{
+					Source: api.OpenTelemetryMetrics,
+					OpenTelemetry: &OpenTelemetryMetrics{
+						Endpoint:    "http://otel-collector.monitoring:8889/metrics",
+						MetricName:  "system.cpu.utilization",
+						Aggregation: "Max",
+					},
+				},
+			},
+			errInfo: fmt.Errorf("unknown openTelemetry aggregation \"Max\""),
+		},
+		{
+			name: "static without a document",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				MetricsUtilization: &MetricsUtilization{
+					Source: api.StaticMetrics,
+					Static: &StaticMetrics{},
+				},
+			},
+			errInfo: fmt.Errorf("exactly one of static file and configMap is required when metrics source is set to \"Static\""),
+		},
+		{
+			name: "static configmap without a name",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				MetricsUtilization: &MetricsUtilization{
+					Source: api.StaticMetrics,
+					Static: &StaticMetrics{ConfigMap: &StaticUsageConfigMap{Namespace: "kube-system"}},
+				},
+			},
+			errInfo: fmt.Errorf("static configMap namespace and name are required"),
+		},
+		{
+			name: "static configuration with another source",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				MetricsUtilization: &MetricsUtilization{
+					Source: api.KubernetesMetrics,
+					Static: &StaticMetrics{File: "/etc/descheduler/usage.yaml"},
+				},
+			},
+			errInfo: fmt.Errorf("static configuration is only allowed when source is set to \"Static\""),
+		},
+		{
+			name: "valid static",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				MetricsUtilization: &MetricsUtilization{
+					Source: api.StaticMetrics,
+					Static: &StaticMetrics{ConfigMap: &StaticUsageConfigMap{Namespace: "kube-system", Name: "usage"}},
+				},
+			},
+			errInfo: nil,
+		},
+		{
+			name: "node cost with both a label and an annotation",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				NodeCost: &NodeCost{Label: "price", Annotation: "price"},
+			},
+			errInfo: fmt.Errorf("nodeCost requires exactly one of label, annotation or provider"),
+		},
+		{
+			name: "node cost with an unknown provider",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				NodeCost: &NodeCost{Provider: "unknown"},
+			},
+			errInfo: fmt.Errorf("unknown nodeCost provider \"unknown\""),
+		},
+		{
+			name: "prometheus configuration with vpa recommendations",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				MetricsUtilization: &MetricsUtilization{
+					Source: api.VPARecommendations,
+					Prometheus: &Prometheus{
+						Query: "instance:node_cpu:rate:sum",
+					},
+				},
+			},
+			errInfo: fmt.Errorf("neither metricsServer nor prometheus configuration are allowed to set when source is set to \"VPARecommendations\""),
+		},
+		{
+			name: "invalid prometheus pod query template",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					MetricResource: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					MetricResource: 80,
+				},
+				MetricsUtilization: &MetricsUtilization{
+					Source: api.PrometheusMetrics,
+					Prometheus: &Prometheus{
+						Query:    "instance:node_cpu:rate:sum",
+						PodQuery: `pod:node_cpu:share{pod="{{.Pod}"}`,
+					},
+				},
+			},
+			errInfo: fmt.Errorf(`prometheus podQuery is not a valid template: template: podQuery:1: bad character U+007D '}'`),
+		},
+		{
+			name: "invalid prometheus node label",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					MetricResource: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					MetricResource: 80,
+				},
+				MetricsUtilization: &MetricsUtilization{
+					Source: api.PrometheusMetrics,
+					Prometheus: &Prometheus{
+						Query:     "node:node_cpu:rate:sum",
+						NodeLabel: "node\xff",
+					},
+				},
+			},
+			errInfo: fmt.Errorf("prometheus nodeLabel \"node\\xff\" is not a valid label name"),
+		},
+		{
+			name: "usage weights with kubernetes metrics",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU:    20,
+					v1.ResourceMemory: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU:    80,
+					v1.ResourceMemory: 80,
+				},
+				MetricsUtilization: &MetricsUtilization{
+					Source:  api.KubernetesMetrics,
+					Weights: &UsageWeights{Requests: 1, Metrics: 1},
+				},
+			},
+			errInfo: nil,
+		},
+		{
+			name: "usage weights with prometheus",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU:    20,
+					v1.ResourceMemory: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU:    80,
+					v1.ResourceMemory: 80,
+				},
+				MetricsUtilization: &MetricsUtilization{
+					Source:     api.PrometheusMetrics,
+					Prometheus: &Prometheus{Query: "instance:node_cpu:rate:sum"},
+					Weights:    &UsageWeights{Requests: 1, Metrics: 1},
+				},
+			},
+			errInfo: fmt.Errorf("usage weights are only supported with the \"KubernetesMetrics\" metrics source"),
+		},
+		{
+			name: "smoothing with kubernetes metrics",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU:    20,
+					v1.ResourceMemory: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU:    80,
+					v1.ResourceMemory: 80,
+				},
+				MetricsUtilization: &MetricsUtilization{
+					Source:          api.KubernetesMetrics,
+					SmoothingFactor: 0.3,
+					MinSamples:      6,
+				},
+			},
+			errInfo: nil,
+		},
+		{
+			name: "smoothing with prometheus",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU:    20,
+					v1.ResourceMemory: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU:    80,
+					v1.ResourceMemory: 80,
+				},
+				MetricsUtilization: &MetricsUtilization{
+					Source:          api.PrometheusMetrics,
+					Prometheus:      &Prometheus{Query: "instance:node_cpu:rate:sum"},
+					SmoothingFactor: 0.3,
+				},
+			},
+			errInfo: fmt.Errorf("smoothingFactor and minSamples are only supported with the \"KubernetesMetrics\" metrics source"),
+		},
+		{
+			name: "smoothing factor out of range",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU:    20,
+					v1.ResourceMemory: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU:    80,
+					v1.ResourceMemory: 80,
+				},
+				MetricsUtilization: &MetricsUtilization{
+					Source:          api.KubernetesMetrics,
+					SmoothingFactor: 1.5,
+				},
+			},
+			errInfo: fmt.Errorf("smoothingFactor is expected to be in (0; 1] interval, got 1.5"),
+		},
+		{
+			name: "extended resources",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU:   20,
+					"nvidia.com/gpu": 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU:   80,
+					"nvidia.com/gpu": 80,
+				},
+				MetricsUtilization: &MetricsUtilization{
+					Source: api.KubernetesMetrics,
+					ExtendedResources: []ExtendedResourceMetrics{
+						{Name: "nvidia.com/gpu", Query: "gpu", NodeLabel: "Hostname", PodQuery: `gpu{pod="{{.Pod}}"}`},
+					},
+				},
+			},
+		},
+		{
+			name: "extended resources with prometheus",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU:   20,
+					"nvidia.com/gpu": 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU:   80,
+					"nvidia.com/gpu": 80,
+				},
+				MetricsUtilization: &MetricsUtilization{
+					Source:     api.PrometheusMetrics,
+					Prometheus: &Prometheus{Query: "instance:node_cpu:rate:sum"},
+					ExtendedResources: []ExtendedResourceMetrics{
+						{Name: "nvidia.com/gpu", Query: "gpu"},
+					},
+				},
+			},
+			errInfo: fmt.Errorf("extendedResources are only supported with the \"KubernetesMetrics\" metrics source"),
+		},
+		{
+			name: "extended resource without query",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU:   20,
+					"nvidia.com/gpu": 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU:   80,
+					"nvidia.com/gpu": 80,
+				},
+				MetricsUtilization: &MetricsUtilization{
+					Source: api.KubernetesMetrics,
+					ExtendedResources: []ExtendedResourceMetrics{
+						{Name: "nvidia.com/gpu"},
+					},
+				},
+			},
+			errInfo: fmt.Errorf("extended resource nvidia.com/gpu query is required"),
+		},
+		{
+			name: "extended resource collecting cpu",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU:   20,
+					"nvidia.com/gpu": 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU:   80,
+					"nvidia.com/gpu": 80,
+				},
+				MetricsUtilization: &MetricsUtilization{
+					Source: api.KubernetesMetrics,
+					ExtendedResources: []ExtendedResourceMetrics{
+						{Name: v1.ResourceCPU, Query: "cpu"},
+					},
+				},
+			},
+			errInfo: fmt.Errorf("the usage of cpu is not collected through extendedResources"),
+		},
+		{
+			name: "extended resource configured twice",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU:   20,
+					"nvidia.com/gpu": 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU:   80,
+					"nvidia.com/gpu": 80,
+				},
+				MetricsUtilization: &MetricsUtilization{
+					Source: api.KubernetesMetrics,
+					ExtendedResources: []ExtendedResourceMetrics{
+						{Name: "nvidia.com/gpu", Query: "gpu"},
+						{Name: "nvidia.com/gpu", Query: "gpu"},
+					},
+				},
+			},
+			errInfo: fmt.Errorf("extended resource nvidia.com/gpu is configured more than once"),
+		},
+		{
+			name: "too many min samples",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU:    20,
+					v1.ResourceMemory: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU:    80,
+					v1.ResourceMemory: 80,
+				},
+				MetricsUtilization: &MetricsUtilization{
+					MetricsServer: true,
+					MinSamples:    100,
+				},
+			},
+			errInfo: fmt.Errorf("minSamples is expected to be between 0 and 60, got 100"),
+		},
+		{
+			name: "negative usage weights",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU:    20,
+					v1.ResourceMemory: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU:    80,
+					v1.ResourceMemory: 80,
+				},
+				MetricsUtilization: &MetricsUtilization{
+					Source:  api.KubernetesMetrics,
+					Weights: &UsageWeights{Requests: -1, Metrics: 1},
+				},
+			},
+			errInfo: fmt.Errorf("usage weights can not be negative"),
+		},
+		{
+			name: "zero usage weights",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU:    20,
+					v1.ResourceMemory: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU:    80,
+					v1.ResourceMemory: 80,
+				},
+				MetricsUtilization: &MetricsUtilization{
+					Source:  api.KubernetesMetrics,
+					Weights: &UsageWeights{},
+				},
+			},
+			errInfo: fmt.Errorf("at least one of the usage weights must be positive"),
+		},
+		{
+			name: "invalid prometheus query template",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU:    20,
+					v1.ResourceMemory: 20,
+					extendedResource:  20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU:    80,
+					v1.ResourceMemory: 80,
+					extendedResource:  80,
+				},
+				MetricsUtilization: &MetricsUtilization{
+					Source: api.PrometheusMetrics,
+					Prometheus: &Prometheus{
+						Query: `instance:node_cpu:rate:sum{instance=~"{{.Nodes`,
+					},
+				},
+			},
+			errInfo: fmt.Errorf("prometheus query is not a valid template: template: query:1: unclosed action"),
+		},
+		{
+			name: "prometheus set when source set to kubernetes metrics",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU:    20,
+					v1.ResourceMemory: 20,
+					extendedResource:  20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU:    80,
+					v1.ResourceMemory: 80,
+					extendedResource:  80,
+				},
+				MetricsUtilization: &MetricsUtilization{
+					Source:     api.KubernetesMetrics,
+					Prometheus: &Prometheus{},
+				},
+			},
+			errInfo: fmt.Errorf("prometheus configuration is not allowed to set when source is set to \"KubernetesMetrics\""),
+		},
+		{
+			name: "valid scoring strategy",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				ScoringStrategy: &ScoringStrategy{
+					Type:      MostAllocated,
+					Resources: []ResourceSpec{{Name: "cpu", Weight: 3}},
+				},
+			},
+			errInfo: nil,
+		},
+		{
+			name: "unknown scoring strategy type",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				ScoringStrategy: &ScoringStrategy{Type: "RequestedToCapacityRatio"},
+			},
+			errInfo: fmt.Errorf("invalid scoring strategy type \"RequestedToCapacityRatio\", must be \"LeastAllocated\", \"MostAllocated\" or \"SpreadByZone\""),
+		},
+		{
+			name: "scoring strategy weight out of range",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				ScoringStrategy: &ScoringStrategy{
+					Type:      LeastAllocated,
+					Resources: []ResourceSpec{{Name: "cpu", Weight: 101}},
+				},
+			},
+			errInfo: fmt.Errorf("scoring strategy weight of resource cpu not in [1, 100] range"),
+		},
+		{
+			name: "scheduling hints without scoring strategy",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				SchedulingHints: true,
+			},
+			errInfo: fmt.Errorf("schedulingHints requires a scoringStrategy"),
+		},
+		{
+			name: "node condition with unknown action",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				NodeConditions: []NodeConditionRule{
+					{Type: "KernelDeadlock", Action: "Cordon"},
+				},
+			},
+			errInfo: fmt.Errorf("invalid action \"Cordon\" for node condition KernelDeadlock, must be \"Avoid\" or \"Drain\""),
+		},
+		{
+			name: "priority bands eviction order",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				EvictionOrder: EvictionOrderPriorityBands,
+			},
+			errInfo: nil,
+		},
+		{
+			name: "unknown eviction order",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				EvictionOrder: "Random",
+			},
+			errInfo: fmt.Errorf("invalid eviction order \"Random\", must be \"PerNode\" or \"PriorityBands\""),
+		},
+		{
+			name: "decision log without path",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				DecisionLog: &DecisionLog{},
+			},
+			errInfo: fmt.Errorf("decisionLog path is required"),
+		},
+		{
+			name: "cooldown without duration",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				Cooldown: &Cooldown{Annotate: true},
+			},
+			errInfo: fmt.Errorf("cooldown duration must be positive, got 0s"),
+		},
+		{
+			name: "cooldown",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				Cooldown: &Cooldown{Duration: metav1.Duration{Duration: time.Hour}},
+			},
+		},
+		{
+			name: "negative do not disrupt hint ttl",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				DoNotDisruptHintTTL: metav1.Duration{Duration: -time.Minute},
+			},
+			errInfo: fmt.Errorf("doNotDisruptHintTTL can not be negative, got -1m0s"),
+		},
+		{
+			name: "eviction rate limit without rate",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				EvictionRateLimit: &EvictionRateLimit{Burst: 5},
+			},
+			errInfo: fmt.Errorf("evictionRateLimit evictionsPerMinute must be positive"),
+		},
+		{
+			name: "deviation center without deviation thresholds",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				DeviationCenter: DeviationCenterMedian,
+			},
+			errInfo: fmt.Errorf("deviationCenter requires useDeviationThresholds"),
+		},
+		{
+			name: "unknown deviation center",
+			args: &LowNodeUtilizationArgs{
+				UseDeviationThresholds: true,
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				DeviationCenter: "Mode",
+			},
+			errInfo: fmt.Errorf("unknown deviation center \"Mode\""),
+		},
+		{
+			name: "deviation trim percentage out of range",
+			args: &LowNodeUtilizationArgs{
+				UseDeviationThresholds: true,
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				DeviationCenter:         DeviationCenterTrimmedMean,
+				DeviationTrimPercentage: 50,
+			},
+			errInfo: fmt.Errorf("deviationTrimPercentage not in [0, 50) range"),
+		},
+		{
+			name: "deviation trim percentage without trimmed mean",
+			args: &LowNodeUtilizationArgs{
+				UseDeviationThresholds: true,
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				DeviationCenter:         DeviationCenterMedian,
+				DeviationTrimPercentage: 10,
+			},
+			errInfo: fmt.Errorf("deviationTrimPercentage requires the \"TrimmedMean\" deviation center"),
+		},
+		{
+			name: "trimmed mean deviation center",
+			args: &LowNodeUtilizationArgs{
+				UseDeviationThresholds: true,
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				DeviationCenter:         DeviationCenterTrimmedMean,
+				DeviationTrimPercentage: 10,
+			},
+		},
+		{
+			name: "hysteresis out of range",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				Hysteresis: -5,
+			},
+			errInfo: fmt.Errorf("hysteresis not in [0, 100] range"),
+		},
+		{
+			name: "hysteresis",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				Hysteresis: 10,
+			},
+		},
+		{
+			name: "negative usage cache ttl",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				UsageCacheTTL: metav1.Duration{Duration: -time.Minute},
+			},
+			errInfo: fmt.Errorf("usageCacheTTL can not be negative, got -1m0s"),
+		},
+		{
+			name: "threshold quantities",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				ThresholdQuantities:       v1.ResourceList{v1.ResourceMemory: resource.MustParse("8Gi")},
+				TargetThresholdQuantities: v1.ResourceList{v1.ResourceMemory: resource.MustParse("2Gi")},
+			},
+			errInfo: nil,
+		},
+		{
+			name: "threshold quantity configured as a percentage as well",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceMemory: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceMemory: 80,
+				},
+				TargetThresholdQuantities: v1.ResourceList{v1.ResourceMemory: resource.MustParse("2Gi")},
+			},
+			errInfo: fmt.Errorf("targetThresholdQuantities' memory is configured as a percentage as well"),
+		},
+		{
+			name: "threshold quantity leaving less free than the target one",
+			args: &LowNodeUtilizationArgs{
+				ThresholdQuantities:       v1.ResourceList{v1.ResourceMemory: resource.MustParse("1Gi")},
+				TargetThresholdQuantities: v1.ResourceList{v1.ResourceMemory: resource.MustParse("2Gi")},
+			},
+			errInfo: fmt.Errorf("thresholdQuantities' memory is lower than targetThresholdQuantities'"),
+		},
+		{
+			name: "threshold quantities without their target",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				ThresholdQuantities: v1.ResourceList{v1.ResourceMemory: resource.MustParse("8Gi")},
+			},
+			errInfo: fmt.Errorf("thresholds and targetThresholds configured different resources"),
+		},
+		{
+			name: "threshold quantities with deviation thresholds",
+			args: &LowNodeUtilizationArgs{
+				UseDeviationThresholds:    true,
+				ThresholdQuantities:       v1.ResourceList{v1.ResourceMemory: resource.MustParse("8Gi")},
+				TargetThresholdQuantities: v1.ResourceList{v1.ResourceMemory: resource.MustParse("2Gi")},
+			},
+			errInfo: fmt.Errorf("threshold quantities can not be combined with deviation or percentile thresholds"),
+		},
+		{
+			name: "unknown mode",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				Mode: "Taint",
+			},
+			errInfo: fmt.Errorf(`invalid mode "Taint", must be "Evict" or "Annotate"`),
+		},
+		{
+			name: "in place resize without max reduction",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				InPlaceResize: &InPlaceResize{},
+			},
+			errInfo: fmt.Errorf("inPlaceResize maxReduction not in (0, 100] range"),
+		},
+		{
+			name: "in place resize with metrics utilization",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				MetricsUtilization: &MetricsUtilization{Source: api.KubernetesMetrics},
+				InPlaceResize:      &InPlaceResize{MaxReduction: 50},
+			},
+			errInfo: fmt.Errorf("inPlaceResize can not be combined with metricsUtilization"),
+		},
+		{
+			name: "fixed pod count without a pod count",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				ContinueEvictionStrategy: &ContinueEvictionStrategy{Type: FixedPodCount},
+			},
+			errInfo: fmt.Errorf(`continueEvictionStrategy podCount must be positive with "FixedPodCount"`),
+		},
+		{
+			name: "unknown continue eviction strategy",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				ContinueEvictionStrategy: &ContinueEvictionStrategy{Type: "UntilEmpty"},
+			},
+			errInfo: fmt.Errorf(`invalid continueEvictionStrategy type "UntilEmpty", must be "UntilBelowTarget", "UntilBelowLow", "FixedPodCount" or "FixedResourceAmount"`),
+		},
+		{
+			name: "invalid run status name",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				RunStatus: &RunStatus{Namespace: "kube-system", Name: "Run_Status"},
+			},
+			errInfo: fmt.Errorf(`invalid runStatus name "Run_Status": a lowercase RFC 1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')`),
+		},
+		{
+			name: "unknown pod eviction order",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				PodEvictionOrder: "Random",
+			},
+			errInfo: fmt.Errorf("unknown pod eviction order \"Random\""),
+		},
+		{
+			name: "unknown affinity awareness",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				AffinityAwareness: "Ignore",
+			},
+			errInfo: fmt.Errorf("invalid affinityAwareness \"Ignore\", must be \"Skip\" or \"Deprioritize\""),
+		},
+		{
+			name: "unknown fairness policy",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				FairnessPolicy: "Equal",
+			},
+			errInfo: fmt.Errorf("invalid fairnessPolicy \"Equal\", must be \"None\" or \"Proportional\""),
+		},
+		{
+			name: "node pool without node selector",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				NodePools: []NodePoolThresholds{
+					{
+						Thresholds:       api.ResourceThresholds{v1.ResourceCPU: 20},
+						TargetThresholds: api.ResourceThresholds{v1.ResourceCPU: 80},
+					},
+				},
+			},
+			errInfo: fmt.Errorf("node pool node selector must be set"),
+		},
+		{
+			name: "node pool with invalid thresholds",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				NodePools: []NodePoolThresholds{
+					{
+						NodeSelector:     &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "gpu"}},
+						Thresholds:       api.ResourceThresholds{v1.ResourceCPU: 90},
+						TargetThresholds: api.ResourceThresholds{v1.ResourceCPU: 80},
+					},
+				},
+			},
+			errInfo: fmt.Errorf("node pool thresholds are not valid: thresholds' cpu percentage is greater than targetThresholds'"),
+		},
+		{
+			name: "both deviation thresholds modes",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 1,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 1,
+				},
+				UseDeviationThresholds:    true,
+				UseStdDeviationThresholds: true,
+			},
+			errInfo: fmt.Errorf("only one of useDeviationThresholds, useStdDeviationThresholds and usePercentileThresholds can be set"),
+		},
+		{
+			name: "percentile thresholds and deviation thresholds",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				UseDeviationThresholds:  true,
+				UsePercentileThresholds: true,
+			},
+			errInfo: fmt.Errorf("only one of useDeviationThresholds, useStdDeviationThresholds and usePercentileThresholds can be set"),
+		},
+		{
+			name: "low percentile above high percentile",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				UsePercentileThresholds: true,
+			},
+			errInfo: fmt.Errorf("thresholds' cpu percentage is greater than targetThresholds'"),
+		},
+		{
+			name: "std deviation thresholds",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 2,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 1,
+				},
+				UseStdDeviationThresholds: true,
+			},
+		},
+		{
+			name: "negative resource weight",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				ResourceWeights: map[v1.ResourceName]float64{
+					v1.ResourceCPU: -1,
+				},
+			},
+			errInfo: fmt.Errorf("resource weight of cpu can not be negative"),
+		},
+		{
+			name: "zero weights for all thresholds resources",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				ResourceWeights: map[v1.ResourceName]float64{
+					v1.ResourceCPU:    0,
+					v1.ResourceMemory: 1,
+				},
+			},
+			errInfo: fmt.Errorf("resourceWeights can not be zero for all the thresholds resources"),
+		},
+		{
+			name: "invalid topology key",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				TopologyKey: "topology zone",
+			},
+			errInfo: fmt.Errorf("topologyKey \"topology zone\" is not valid: name part must consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyName',  or 'my.name',  or '123-abc', regex used for validation is '([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]')"),
+		},
+		{
+			name: "node condition without type",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				NodeConditions: []NodeConditionRule{
+					{Action: NodeConditionActionDrain},
+				},
+			},
+			errInfo: fmt.Errorf("node condition type must not be empty"),
+		},
+		{
+			name: "overcommit without node selector",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				Overcommit: []OvercommitRule{
+					{Factors: map[v1.ResourceName]float64{v1.ResourceCPU: 1.5}},
+				},
+			},
+			errInfo: fmt.Errorf("overcommit node selector must be set"),
+		},
+		{
+			name: "overcommit with invalid node selector",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				Overcommit: []OvercommitRule{
+					{
+						NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "not valid"}},
+						Factors:      map[v1.ResourceName]float64{v1.ResourceCPU: 1.5},
+					},
+				},
+			},
+			errInfo: fmt.Errorf("invalid overcommit node selector: values[0][pool]: Invalid value: \"not valid\": a valid label must be an empty string or consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyValue',  or 'my_value',  or '12345', regex used for validation is '(([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9])?')"),
+		},
+		{
+			name: "overcommit with a non positive factor",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				Overcommit: []OvercommitRule{
+					{
+						NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "batch"}},
+						Factors:      map[v1.ResourceName]float64{v1.ResourceCPU: 0},
+					},
+				},
+			},
+			errInfo: fmt.Errorf("overcommit factor of resource cpu must be positive, got 0"),
+		},
+		{
+			name: "valid overcommit",
+			args: &LowNodeUtilizationArgs{
+				Thresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 20,
+				},
+				TargetThresholds: api.ResourceThresholds{
+					v1.ResourceCPU: 80,
+				},
+				Overcommit: []OvercommitRule{
+					{
+						NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "batch"}},
+						Factors:      map[v1.ResourceName]float64{v1.ResourceCPU: 1.5},
+					},
+				},
+			},
+			errInfo: nil,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			validateErr := ValidateLowNodeUtilizationArgs(runtime.Object(testCase.args))
+			if validateErr == nil || testCase.errInfo == nil {
+				if validateErr != testCase.errInfo {
+					t.Errorf("expected validity of plugin config: %q but got %q instead", testCase.errInfo, validateErr)
+				}
+			} else if validateErr.Error() != testCase.errInfo.Error() {
+				t.Errorf("expected validity of plugin config: %q but got %q instead", testCase.errInfo, validateErr)
+			}
+		})
+	}
+}
+
+func TestValidateNodeConsolidationPluginConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    *NodeConsolidationArgs
+		errInfo error
+	}{
+		{
+			name: "valid configuration",
+			args: &NodeConsolidationArgs{
+				TargetThresholds: api.ResourceThresholds{v1.ResourceCPU: 80},
+				MaxNodesToDrain:  2,
+			},
+		},
+		{
+			name: "included namespaces",
+			args: &NodeConsolidationArgs{
+				EvictableNamespaces: &api.Namespaces{Include: []string{"default"}},
+			},
+			errInfo: fmt.Errorf("only Exclude namespaces can be set, inclusion is not supported"),
+		},
+		{
+			name: "target threshold out of range",
+			args: &NodeConsolidationArgs{
+				TargetThresholds: api.ResourceThresholds{v1.ResourceCPU: 120},
+			},
+			errInfo: fmt.Errorf("cpu target threshold not in [0, 100] range"),
+		},
+		{
+			name: "negative maxNodesToDrain",
+			args: &NodeConsolidationArgs{
+				MaxNodesToDrain: -1,
+			},
+			errInfo: fmt.Errorf("maxNodesToDrain can not be negative, got -1"),
+		},
+		{
+			name: "prometheus metrics",
+			args: &NodeConsolidationArgs{
+				MetricsUtilization: &MetricsUtilization{
+					Source:     api.PrometheusMetrics,
+					Prometheus: &Prometheus{Query: "instance:node_cpu:rate:sum"},
+				},
+			},
+			errInfo: fmt.Errorf("metrics source must be \"KubernetesMetrics\", \"VPARecommendations\" or \"Static\", pods are packed by their usage in resource units"),
+		},
+		{
+			name: "unknown scale down hint",
+			args: &NodeConsolidationArgs{
+				ScaleDownHints: []ScaleDownHint{ScaleDownHintTaint, "Drain"},
+			},
+			errInfo: fmt.Errorf("invalid scaleDownHint \"Drain\", must be \"Annotate\", \"Taint\" or \"Cordon\""),
+		},
+		{
+			name: "negative scale down hints ttl",
+			args: &NodeConsolidationArgs{
+				ScaleDownHints:    []ScaleDownHint{ScaleDownHintTaint},
+				ScaleDownHintsTTL: metav1.Duration{Duration: -time.Minute},
+			},
+			errInfo: fmt.Errorf("scaleDownHintsTTL can not be negative, got -1m0s"),
+		},
+		{
+			name: "kubernetes metrics",
+			args: &NodeConsolidationArgs{
+				MetricsUtilization: &MetricsUtilization{Source: api.KubernetesMetrics},
+			},
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			validateErr := ValidateNodeConsolidationArgs(runtime.Object(testCase.args))
 			if validateErr == nil || testCase.errInfo == nil {
 				if validateErr != testCase.errInfo {
 					t.Errorf("expected validity of plugin config: %q but got %q instead", testCase.errInfo, validateErr)
@@ -0,0 +1,71 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// podWorkload returns the workload the pod is a replica of, i.e. its
+// controller. pods of a ReplicaSet created by a Deployment, told apart by the
+// pod-template-hash suffix of the ReplicaSet name, belong to the Deployment
+// so its replicas count together during rollouts. pods without a controller
+// are not replicas of any workload.
+func podWorkload(pod *v1.Pod) (ownerKey, bool) {
+	owner := metav1.GetControllerOfNoCopy(pod)
+	if owner == nil {
+		return ownerKey{}, false
+	}
+	key := ownerKey{namespace: pod.Namespace, kind: owner.Kind, name: owner.Name}
+	if owner.Kind != "ReplicaSet" {
+		return key, true
+	}
+	hash, ok := pod.Labels[appsv1.DefaultDeploymentUniqueLabelKey]
+	if !ok || hash == "" {
+		return key, true
+	}
+	if name, ok := strings.CutSuffix(owner.Name, "-"+hash); ok && name != "" {
+		key.kind, key.name = "Deployment", name
+	}
+	return key, true
+}
+
+// workloadLimitReached tells if as many replicas of the workload of the pod
+// as the limit allows were evicted already during the cycle.
+func (s *balanceSummary) workloadLimitReached(pod *v1.Pod, limit *uint) bool {
+	if limit == nil {
+		return false
+	}
+	workload, ok := podWorkload(pod)
+	if !ok {
+		return false
+	}
+	return s.evictedPerWorkload[workload] >= *limit
+}
+
+// workloadEvicted accounts for a replica of the workload of the pod evicted.
+func (s *balanceSummary) workloadEvicted(pod *v1.Pod) {
+	workload, ok := podWorkload(pod)
+	if !ok {
+		return
+	}
+	s.evictedPerWorkload[workload]++
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutilization
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/descheduler/test"
+)
+
+// controlledBy makes the pod a replica of the provided controller, a non
+// empty hash is set as the pod-template-hash label of the pod.
+func controlledBy(kind, name, hash string) func(*v1.Pod) {
+	return func(pod *v1.Pod) {
+		pod.ObjectMeta.OwnerReferences = []metav1.OwnerReference{
+			{Kind: kind, APIVersion: "apps/v1", Name: name, Controller: ptr.To(true)},
+		}
+		if hash != "" {
+			pod.Labels = map[string]string{appsv1.DefaultDeploymentUniqueLabelKey: hash}
+		}
+	}
+}
+
+func TestPodWorkload(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		pod      *v1.Pod
+		expected ownerKey
+		found    bool
+	}{
+		{
+			name: "pod without owner",
+			pod:  test.BuildTestPod("p1", 100, 0, "n1", nil),
+		},
+		{
+			name: "pod without controller",
+			pod:  test.BuildTestPod("p1", 100, 0, "n1", test.SetRSOwnerRef),
+		},
+		{
+			name:     "statefulset replica",
+			pod:      test.BuildTestPod("p1", 100, 0, "n1", controlledBy("StatefulSet", "db", "")),
+			expected: ownerKey{namespace: "default", kind: "StatefulSet", name: "db"},
+			found:    true,
+		},
+		{
+			name:     "deployment replica",
+			pod:      test.BuildTestPod("p1", 100, 0, "n1", controlledBy("ReplicaSet", "web-5d8f7c", "5d8f7c")),
+			expected: ownerKey{namespace: "default", kind: "Deployment", name: "web"},
+			found:    true,
+		},
+		{
+			name:     "replicaset replica",
+			pod:      test.BuildTestPod("p1", 100, 0, "n1", controlledBy("ReplicaSet", "web", "")),
+			expected: ownerKey{namespace: "default", kind: "ReplicaSet", name: "web"},
+			found:    true,
+		},
+		{
+			name:     "replicaset named after another hash",
+			pod:      test.BuildTestPod("p1", 100, 0, "n1", controlledBy("ReplicaSet", "web-5d8f7c", "9b7d4f")),
+			expected: ownerKey{namespace: "default", kind: "ReplicaSet", name: "web-5d8f7c"},
+			found:    true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			workload, found := podWorkload(tc.pod)
+			if found != tc.found {
+				t.Fatalf("expected workload found to be %v, got %v instead", tc.found, found)
+			}
+			if workload != tc.expected {
+				t.Errorf("expected workload %+v, got %+v instead", tc.expected, workload)
+			}
+		})
+	}
+}
@@ -22,10 +22,62 @@ limitations under the License.
 package nodeutilization
 
 import (
+	v1 "k8s.io/api/core/v1"
+	resource "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	api "sigs.k8s.io/descheduler/pkg/api"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContinueEvictionStrategy) DeepCopyInto(out *ContinueEvictionStrategy) {
+	*out = *in
+	if in.ResourceAmount != nil {
+		in, out := &in.ResourceAmount, &out.ResourceAmount
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContinueEvictionStrategy.
+func (in *ContinueEvictionStrategy) DeepCopy() *ContinueEvictionStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(ContinueEvictionStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CustomMetrics) DeepCopyInto(out *CustomMetrics) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.External != nil {
+		in, out := &in.External, &out.External
+		*out = new(ExternalMetric)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomMetrics.
+func (in *CustomMetrics) DeepCopy() *CustomMetrics {
+	if in == nil {
+		return nil
+	}
+	out := new(CustomMetrics)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HighNodeUtilizationArgs) DeepCopyInto(out *HighNodeUtilizationArgs) {
 	*out = *in
@@ -47,6 +99,67 @@ func (in *HighNodeUtilizationArgs) DeepCopyInto(out *HighNodeUtilizationArgs) {
 		*out = new(api.Namespaces)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.EvictionLimits != nil {
+		in, out := &in.EvictionLimits, &out.EvictionLimits
+		*out = new(api.EvictionLimits)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScoringStrategy != nil {
+		in, out := &in.ScoringStrategy, &out.ScoringStrategy
+		*out = new(ScoringStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeConditions != nil {
+		in, out := &in.NodeConditions, &out.NodeConditions
+		*out = make([]NodeConditionRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.Overcommit != nil {
+		in, out := &in.Overcommit, &out.Overcommit
+		*out = make([]OvercommitRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DecisionLog != nil {
+		in, out := &in.DecisionLog, &out.DecisionLog
+		*out = new(DecisionLog)
+		**out = **in
+	}
+	if in.NodeCost != nil {
+		in, out := &in.NodeCost, &out.NodeCost
+		*out = new(NodeCost)
+		**out = **in
+	}
+	if in.ScaleDownHints != nil {
+		in, out := &in.ScaleDownHints, &out.ScaleDownHints
+		*out = make([]ScaleDownHint, len(*in))
+		copy(*out, *in)
+	}
+	out.ScaleDownHintsTTL = in.ScaleDownHintsTTL
+	if in.ResourceWeights != nil {
+		in, out := &in.ResourceWeights, &out.ResourceWeights
+		*out = make(map[v1.ResourceName]float64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Cooldown != nil {
+		in, out := &in.Cooldown, &out.Cooldown
+		*out = new(Cooldown)
+		**out = **in
+	}
+	if in.EvictionRateLimit != nil {
+		in, out := &in.EvictionRateLimit, &out.EvictionRateLimit
+		*out = new(EvictionRateLimit)
+		**out = **in
+	}
+	out.UsageCacheTTL = in.UsageCacheTTL
+	if in.RunStatus != nil {
+		in, out := &in.RunStatus, &out.RunStatus
+		*out = new(RunStatus)
+		**out = **in
+	}
 	return
 }
 
@@ -68,6 +181,34 @@ func (in *HighNodeUtilizationArgs) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InPlaceResize) DeepCopyInto(out *InPlaceResize) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]v1.ResourceName, len(*in))
+		copy(*out, *in)
+	}
+	if in.MinRequests != nil {
+		in, out := &in.MinRequests, &out.MinRequests
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InPlaceResize.
+func (in *InPlaceResize) DeepCopy() *InPlaceResize {
+	if in == nil {
+		return nil
+	}
+	out := new(InPlaceResize)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LowNodeUtilizationArgs) DeepCopyInto(out *LowNodeUtilizationArgs) {
 	*out = *in
@@ -91,6 +232,20 @@ func (in *LowNodeUtilizationArgs) DeepCopyInto(out *LowNodeUtilizationArgs) {
 		*out = new(MetricsUtilization)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ThresholdQuantities != nil {
+		in, out := &in.ThresholdQuantities, &out.ThresholdQuantities
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.TargetThresholdQuantities != nil {
+		in, out := &in.TargetThresholdQuantities, &out.TargetThresholdQuantities
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
 	if in.EvictableNamespaces != nil {
 		in, out := &in.EvictableNamespaces, &out.EvictableNamespaces
 		*out = new(api.Namespaces)
@@ -101,6 +256,74 @@ func (in *LowNodeUtilizationArgs) DeepCopyInto(out *LowNodeUtilizationArgs) {
 		*out = new(api.EvictionLimits)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ScoringStrategy != nil {
+		in, out := &in.ScoringStrategy, &out.ScoringStrategy
+		*out = new(ScoringStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeConditions != nil {
+		in, out := &in.NodeConditions, &out.NodeConditions
+		*out = make([]NodeConditionRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.Overcommit != nil {
+		in, out := &in.Overcommit, &out.Overcommit
+		*out = make([]OvercommitRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DecisionLog != nil {
+		in, out := &in.DecisionLog, &out.DecisionLog
+		*out = new(DecisionLog)
+		**out = **in
+	}
+	if in.NodeCost != nil {
+		in, out := &in.NodeCost, &out.NodeCost
+		*out = new(NodeCost)
+		**out = **in
+	}
+	if in.ResourceWeights != nil {
+		in, out := &in.ResourceWeights, &out.ResourceWeights
+		*out = make(map[v1.ResourceName]float64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NodePools != nil {
+		in, out := &in.NodePools, &out.NodePools
+		*out = make([]NodePoolThresholds, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Cooldown != nil {
+		in, out := &in.Cooldown, &out.Cooldown
+		*out = new(Cooldown)
+		**out = **in
+	}
+	out.DoNotDisruptHintTTL = in.DoNotDisruptHintTTL
+	if in.EvictionRateLimit != nil {
+		in, out := &in.EvictionRateLimit, &out.EvictionRateLimit
+		*out = new(EvictionRateLimit)
+		**out = **in
+	}
+	if in.ContinueEvictionStrategy != nil {
+		in, out := &in.ContinueEvictionStrategy, &out.ContinueEvictionStrategy
+		*out = new(ContinueEvictionStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InPlaceResize != nil {
+		in, out := &in.InPlaceResize, &out.InPlaceResize
+		*out = new(InPlaceResize)
+		(*in).DeepCopyInto(*out)
+	}
+	out.UsageCacheTTL = in.UsageCacheTTL
+	if in.RunStatus != nil {
+		in, out := &in.RunStatus, &out.RunStatus
+		*out = new(RunStatus)
+		**out = **in
+	}
 	return
 }
 
@@ -128,8 +351,33 @@ func (in *MetricsUtilization) DeepCopyInto(out *MetricsUtilization) {
 	if in.Prometheus != nil {
 		in, out := &in.Prometheus, &out.Prometheus
 		*out = new(Prometheus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CustomMetrics != nil {
+		in, out := &in.CustomMetrics, &out.CustomMetrics
+		*out = new(CustomMetrics)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OpenTelemetry != nil {
+		in, out := &in.OpenTelemetry, &out.OpenTelemetry
+		*out = new(OpenTelemetryMetrics)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Static != nil {
+		in, out := &in.Static, &out.Static
+		*out = new(StaticMetrics)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Weights != nil {
+		in, out := &in.Weights, &out.Weights
+		*out = new(UsageWeights)
 		**out = **in
 	}
+	if in.ExtendedResources != nil {
+		in, out := &in.ExtendedResources, &out.ExtendedResources
+		*out = make([]ExtendedResourceMetrics, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -142,3 +390,297 @@ func (in *MetricsUtilization) DeepCopy() *MetricsUtilization {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeConsolidationArgs) DeepCopyInto(out *NodeConsolidationArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.TargetThresholds != nil {
+		in, out := &in.TargetThresholds, &out.TargetThresholds
+		*out = make(api.ResourceThresholds, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.EvictableNamespaces != nil {
+		in, out := &in.EvictableNamespaces, &out.EvictableNamespaces
+		*out = new(api.Namespaces)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EvictionLimits != nil {
+		in, out := &in.EvictionLimits, &out.EvictionLimits
+		*out = new(api.EvictionLimits)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MetricsUtilization != nil {
+		in, out := &in.MetricsUtilization, &out.MetricsUtilization
+		*out = new(MetricsUtilization)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScaleDownHints != nil {
+		in, out := &in.ScaleDownHints, &out.ScaleDownHints
+		*out = make([]ScaleDownHint, len(*in))
+		copy(*out, *in)
+	}
+	out.ScaleDownHintsTTL = in.ScaleDownHintsTTL
+	out.UsageCacheTTL = in.UsageCacheTTL
+	if in.RunStatus != nil {
+		in, out := &in.RunStatus, &out.RunStatus
+		*out = new(RunStatus)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeConsolidationArgs.
+func (in *NodeConsolidationArgs) DeepCopy() *NodeConsolidationArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeConsolidationArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NodeConsolidationArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeCost) DeepCopyInto(out *NodeCost) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeCost.
+func (in *NodeCost) DeepCopy() *NodeCost {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeCost)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePoolThresholds) DeepCopyInto(out *NodePoolThresholds) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Thresholds != nil {
+		in, out := &in.Thresholds, &out.Thresholds
+		*out = make(api.ResourceThresholds, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TargetThresholds != nil {
+		in, out := &in.TargetThresholds, &out.TargetThresholds
+		*out = make(api.ResourceThresholds, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolThresholds.
+func (in *NodePoolThresholds) DeepCopy() *NodePoolThresholds {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePoolThresholds)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenTelemetryMetrics) DeepCopyInto(out *OpenTelemetryMetrics) {
+	*out = *in
+	if in.Attributes != nil {
+		in, out := &in.Attributes, &out.Attributes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenTelemetryMetrics.
+func (in *OpenTelemetryMetrics) DeepCopy() *OpenTelemetryMetrics {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenTelemetryMetrics)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OvercommitRule) DeepCopyInto(out *OvercommitRule) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Factors != nil {
+		in, out := &in.Factors, &out.Factors
+		*out = make(map[v1.ResourceName]float64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OvercommitRule.
+func (in *OvercommitRule) DeepCopy() *OvercommitRule {
+	if in == nil {
+		return nil
+	}
+	out := new(OvercommitRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Prometheus) DeepCopyInto(out *Prometheus) {
+	*out = *in
+	out.Timeout = in.Timeout
+	out.Backoff = in.Backoff
+	if in.Range != nil {
+		in, out := &in.Range, &out.Range
+		*out = new(PrometheusRange)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Prometheus.
+func (in *Prometheus) DeepCopy() *Prometheus {
+	if in == nil {
+		return nil
+	}
+	out := new(Prometheus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusRange) DeepCopyInto(out *PrometheusRange) {
+	*out = *in
+	out.Window = in.Window
+	out.Step = in.Step
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusRange.
+func (in *PrometheusRange) DeepCopy() *PrometheusRange {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusRange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScoringStrategy) DeepCopyInto(out *ScoringStrategy) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]ResourceSpec, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScoringStrategy.
+func (in *ScoringStrategy) DeepCopy() *ScoringStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(ScoringStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StaticMetrics) DeepCopyInto(out *StaticMetrics) {
+	*out = *in
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(StaticUsageConfigMap)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StaticMetrics.
+func (in *StaticMetrics) DeepCopy() *StaticMetrics {
+	if in == nil {
+		return nil
+	}
+	out := new(StaticMetrics)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StaticUsage) DeepCopyInto(out *StaticUsage) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make(map[string]v1.ResourceList, len(*in))
+		for key, val := range *in {
+			var outVal map[v1.ResourceName]resource.Quantity
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make(v1.ResourceList, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val.DeepCopy()
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.Pods != nil {
+		in, out := &in.Pods, &out.Pods
+		*out = make(map[string]v1.ResourceList, len(*in))
+		for key, val := range *in {
+			var outVal map[v1.ResourceName]resource.Quantity
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make(v1.ResourceList, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val.DeepCopy()
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StaticUsage.
+func (in *StaticUsage) DeepCopy() *StaticUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(StaticUsage)
+	in.DeepCopyInto(out)
+	return out
+}
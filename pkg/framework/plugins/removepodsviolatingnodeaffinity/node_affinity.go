@@ -91,7 +91,7 @@ func (d *RemovePodsViolatingNodeAffinity) Deschedule(ctx context.Context, nodes
 			filterFunc := func(pod *v1.Pod, node *v1.Node, nodes []*v1.Node) bool {
 				return utils.PodHasNodeAffinity(pod, utils.RequiredDuringSchedulingIgnoredDuringExecution) &&
 					d.handle.Evictor().Filter(pod) &&
-					nodeutil.PodFitsAnyNode(d.handle.GetPodsAssignedToNodeFunc(), pod, nodes) &&
+					nodeutil.PodFitsAnyNodeWithDevices(d.handle.GetPodsAssignedToNodeFunc(), frameworktypes.DeviceAccountingFor(d.handle), pod, nodes) &&
 					!nodeutil.PodMatchNodeSelector(pod, node)
 			}
 			err = d.processNodes(ctx, nodes, filterFunc)
@@ -101,7 +101,7 @@ func (d *RemovePodsViolatingNodeAffinity) Deschedule(ctx context.Context, nodes
 			filterFunc := func(pod *v1.Pod, node *v1.Node, nodes []*v1.Node) bool {
 				return utils.PodHasNodeAffinity(pod, utils.PreferredDuringSchedulingIgnoredDuringExecution) &&
 					d.handle.Evictor().Filter(pod) &&
-					nodeutil.PodFitsAnyNode(d.handle.GetPodsAssignedToNodeFunc(), pod, nodes) &&
+					nodeutil.PodFitsAnyNodeWithDevices(d.handle.GetPodsAssignedToNodeFunc(), frameworktypes.DeviceAccountingFor(d.handle), pod, nodes) &&
 					(nodeutil.GetBestNodeWeightGivenPodPreferredAffinity(pod, nodes) > nodeutil.GetNodeWeightGivenPodPreferredAffinity(pod, node))
 			}
 			err = d.processNodes(ctx, nodes, filterFunc)
@@ -369,7 +369,7 @@ func (d *RemovePodsViolatingTopologySpreadConstraint) balanceDomains(
 			// This is because the chosen pods aren't sorted, but immovable pods still count as "evicted" toward the PTS algorithm.
 			// So, a better selection heuristic could improve performance.
 
-			if topologyBalanceNodeFit && !node.PodFitsAnyOtherNode(getPodsAssignedToNode, aboveToEvict[k], nodesBelowIdealAvg) {
+			if topologyBalanceNodeFit && !node.PodFitsAnyOtherNodeWithDevices(getPodsAssignedToNode, frameworktypes.DeviceAccountingFor(d.handle), aboveToEvict[k], nodesBelowIdealAvg) {
 				klog.V(2).InfoS("ignoring pod for eviction as it does not fit on any other node", "pod", klog.KObj(aboveToEvict[k]))
 				continue
 			}
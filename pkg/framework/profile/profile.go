@@ -16,6 +16,8 @@ package profile
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"slices"
 	"time"
 
 	promapi "github.com/prometheus/client_golang/api"
@@ -31,8 +33,10 @@ import (
 
 	"sigs.k8s.io/descheduler/metrics"
 	"sigs.k8s.io/descheduler/pkg/api"
+	"sigs.k8s.io/descheduler/pkg/descheduler/client"
 	"sigs.k8s.io/descheduler/pkg/descheduler/evictions"
 	"sigs.k8s.io/descheduler/pkg/descheduler/metricscollector"
+	nodeutil "sigs.k8s.io/descheduler/pkg/descheduler/node"
 	podutil "sigs.k8s.io/descheduler/pkg/descheduler/pod"
 	"sigs.k8s.io/descheduler/pkg/framework/pluginregistry"
 	frameworktypes "sigs.k8s.io/descheduler/pkg/framework/types"
@@ -49,6 +53,7 @@ type evictorImpl struct {
 }
 
 var _ frameworktypes.Evictor = &evictorImpl{}
+var _ frameworktypes.DryRunEvictor = &evictorImpl{}
 
 // Filter checks if a pod can be evicted
 func (ei *evictorImpl) Filter(pod *v1.Pod) bool {
@@ -66,17 +71,32 @@ func (ei *evictorImpl) Evict(ctx context.Context, pod *v1.Pod, opts evictions.Ev
 	return ei.podEvictor.EvictPod(ctx, pod, opts)
 }
 
+// DryRun returns true if evictions are only simulated
+func (ei *evictorImpl) DryRun() bool {
+	return ei.podEvictor.DryRun()
+}
+
 // handleImpl implements the framework handle which gets passed to plugins
 type handleImpl struct {
 	clientSet                 clientset.Interface
 	prometheusClient          promapi.Client
 	metricsCollector          *metricscollector.MetricsCollector
+	deviceAccounting          *nodeutil.DeviceAccounting
 	getPodsAssignedToNodeFunc podutil.GetPodsAssignedToNodeFunc
 	sharedInformerFactory     informers.SharedInformerFactory
+	sharedObjects             *frameworktypes.SharedObjects
+	sharedCache               *frameworktypes.SharedCache
+	rand                      *frameworktypes.Rand
 	evictor                   *evictorImpl
+	profileName               string
 }
 
 var _ frameworktypes.Handle = &handleImpl{}
+var _ frameworktypes.DeviceAccountingHandle = &handleImpl{}
+var _ frameworktypes.SharedObjectsHandle = &handleImpl{}
+var _ frameworktypes.SharedCacheHandle = &handleImpl{}
+var _ frameworktypes.RandHandle = &handleImpl{}
+var _ frameworktypes.ProfileNameHandle = &handleImpl{}
 
 // ClientSet retrieves kube client set
 func (hi *handleImpl) ClientSet() clientset.Interface {
@@ -84,13 +104,22 @@ func (hi *handleImpl) ClientSet() clientset.Interface {
 }
 
 func (hi *handleImpl) PrometheusClient() promapi.Client {
-	return hi.prometheusClient
+	if hi.prometheusClient == nil {
+		return nil
+	}
+	return &countingPrometheusClient{Client: hi.prometheusClient}
 }
 
 func (hi *handleImpl) MetricsCollector() *metricscollector.MetricsCollector {
 	return hi.metricsCollector
 }
 
+// DeviceAccounting retrieves the accounting of the devices allocated through
+// Dynamic Resource Allocation
+func (hi *handleImpl) DeviceAccounting() *nodeutil.DeviceAccounting {
+	return hi.deviceAccounting
+}
+
 // GetPodsAssignedToNodeFunc retrieves GetPodsAssignedToNodeFunc implementation
 func (hi *handleImpl) GetPodsAssignedToNodeFunc() podutil.GetPodsAssignedToNodeFunc {
 	return hi.getPodsAssignedToNodeFunc
@@ -101,11 +130,43 @@ func (hi *handleImpl) SharedInformerFactory() informers.SharedInformerFactory {
 	return hi.sharedInformerFactory
 }
 
+// SharedObjects retrieves the objects shared among the profile plugins
+func (hi *handleImpl) SharedObjects() *frameworktypes.SharedObjects {
+	return hi.sharedObjects
+}
+
+// SharedCache retrieves the objects shared among the plugins of all profiles
+func (hi *handleImpl) SharedCache() *frameworktypes.SharedCache {
+	return hi.sharedCache
+}
+
+// Rand retrieves the source of the randomized choices of the plugins
+func (hi *handleImpl) Rand() *frameworktypes.Rand {
+	return hi.rand
+}
+
+// ProfileName retrieves the name of the profile
+func (hi *handleImpl) ProfileName() string {
+	return hi.profileName
+}
+
 // Evictor retrieves evictor so plugins can filter and evict pods
 func (hi *handleImpl) Evictor() frameworktypes.Evictor {
 	return hi.evictor
 }
 
+// countingPrometheusClient accounts every query sent to Prometheus to the
+// plugin the query context was created for (see client.WithPlugin).
+type countingPrometheusClient struct {
+	promapi.Client
+}
+
+// Do sends the request through the wrapped client.
+func (cc *countingPrometheusClient) Do(ctx context.Context, req *http.Request) (*http.Response, []byte, error) {
+	client.CountPluginCall(ctx, client.PluginCallMetrics)
+	return cc.Client.Do(ctx, req)
+}
+
 type filterPlugin interface {
 	frameworktypes.Plugin
 	Filter(pod *v1.Pod) bool
@@ -130,6 +191,11 @@ type profileImpl struct {
 	balance           sets.Set[string]
 	filter            sets.Set[string]
 	preEvictionFilter sets.Set[string]
+
+	// excludedOperatingSystems and excludedTaints hold, by plugin, the
+	// operating systems and the taints of the nodes the plugin is not given.
+	excludedOperatingSystems map[string]sets.Set[string]
+	excludedTaints           map[string][]string
 }
 
 // Option for the handleImpl.
@@ -142,6 +208,9 @@ type handleImplOpts struct {
 	getPodsAssignedToNodeFunc podutil.GetPodsAssignedToNodeFunc
 	podEvictor                *evictions.PodEvictor
 	metricsCollector          *metricscollector.MetricsCollector
+	deviceAccounting          *nodeutil.DeviceAccounting
+	sharedCache               *frameworktypes.SharedCache
+	rand                      *frameworktypes.Rand
 }
 
 // WithClientSet sets clientSet for the scheduling frameworkImpl.
@@ -182,6 +251,31 @@ func WithMetricsCollector(metricsCollector *metricscollector.MetricsCollector) O
 	}
 }
 
+// WithDeviceAccounting sets the accounting of the devices allocated through
+// Dynamic Resource Allocation.
+func WithDeviceAccounting(deviceAccounting *nodeutil.DeviceAccounting) Option {
+	return func(o *handleImplOpts) {
+		o.deviceAccounting = deviceAccounting
+	}
+}
+
+// WithSharedCache sets the cache shared among the plugins of all profiles.
+// An empty cache only shared within the profile is used when none is
+// provided.
+func WithSharedCache(sharedCache *frameworktypes.SharedCache) Option {
+	return func(o *handleImplOpts) {
+		o.sharedCache = sharedCache
+	}
+}
+
+// WithRand sets the source of the randomized choices of the plugins. A
+// source seeded from the current time is used when none is provided.
+func WithRand(rand *frameworktypes.Rand) Option {
+	return func(o *handleImplOpts) {
+		o.rand = rand
+	}
+}
+
 func getPluginConfig(pluginName string, pluginConfigs []api.PluginConfig) (*api.PluginConfig, int) {
 	for idx, pluginConfig := range pluginConfigs {
 		if pluginConfig.Name == pluginName {
@@ -249,6 +343,13 @@ func NewProfile(config api.DeschedulerProfile, reg pluginregistry.Registry, opts
 		return nil, fmt.Errorf("podEvictor missing")
 	}
 
+	if hOpts.rand == nil {
+		hOpts.rand = frameworktypes.NewRand(time.Now().UnixNano())
+	}
+	if hOpts.sharedCache == nil {
+		hOpts.sharedCache = frameworktypes.NewSharedCache()
+	}
+
 	pi := &profileImpl{
 		profileName:              config.Name,
 		podEvictor:               hOpts.podEvictor,
@@ -256,9 +357,20 @@ func NewProfile(config api.DeschedulerProfile, reg pluginregistry.Registry, opts
 		balancePlugins:           []frameworktypes.BalancePlugin{},
 		filterPlugins:            []filterPlugin{},
 		preEvictionFilterPlugins: []preEvictionFilterPlugin{},
+		excludedOperatingSystems: map[string]sets.Set[string]{},
+		excludedTaints:           map[string][]string{},
 	}
 	pi.registryToExtensionPoints(reg)
 
+	for _, pluginConfig := range config.PluginConfigs {
+		if len(pluginConfig.ExcludedOperatingSystems) > 0 {
+			pi.excludedOperatingSystems[pluginConfig.Name] = sets.New(pluginConfig.ExcludedOperatingSystems...)
+		}
+		if len(pluginConfig.ExcludedTaints) > 0 {
+			pi.excludedTaints[pluginConfig.Name] = pluginConfig.ExcludedTaints
+		}
+	}
+
 	if !pi.deschedule.HasAll(config.Plugins.Deschedule.Enabled...) {
 		return nil, fmt.Errorf("profile %q configures deschedule extension point of non-existing plugins: %v", config.Name, sets.New(config.Plugins.Deschedule.Enabled...).Difference(pi.deschedule))
 	}
@@ -276,12 +388,17 @@ func NewProfile(config api.DeschedulerProfile, reg pluginregistry.Registry, opts
 		clientSet:                 hOpts.clientSet,
 		getPodsAssignedToNodeFunc: hOpts.getPodsAssignedToNodeFunc,
 		sharedInformerFactory:     hOpts.sharedInformerFactory,
+		sharedObjects:             frameworktypes.NewSharedObjects(),
+		sharedCache:               hOpts.sharedCache,
+		rand:                      hOpts.rand,
 		evictor: &evictorImpl{
 			profileName: config.Name,
 			podEvictor:  hOpts.podEvictor,
 		},
 		metricsCollector: hOpts.metricsCollector,
+		deviceAccounting: hOpts.deviceAccounting,
 		prometheusClient: hOpts.prometheusClient,
+		profileName:      config.Name,
 	}
 
 	pluginNames := append(config.Plugins.Deschedule.Enabled, config.Plugins.Balance.Enabled...)
@@ -328,6 +445,32 @@ func NewProfile(config api.DeschedulerProfile, reg pluginregistry.Registry, opts
 	return pi, nil
 }
 
+// pluginNodes returns the nodes the plugin processes, i.e. the nodes neither
+// running one of the operating systems nor carrying one of the taints
+// excluded for the plugin.
+func (d profileImpl) pluginNodes(pluginName string, nodes []*v1.Node) []*v1.Node {
+	excludedOperatingSystems, excludedTaints := d.excludedOperatingSystems[pluginName], d.excludedTaints[pluginName]
+	if excludedOperatingSystems.Len() == 0 && len(excludedTaints) == 0 {
+		return nodes
+	}
+	result := make([]*v1.Node, 0, len(nodes))
+	for _, node := range nodes {
+		if excludedOperatingSystems.Has(nodeutil.OperatingSystem(node)) {
+			klog.V(3).InfoS("Skipping node running an excluded operating system", "plugin", pluginName, "profile", d.profileName, "node", klog.KObj(node))
+			continue
+		}
+		if slices.ContainsFunc(excludedTaints, func(taint string) bool { return nodeutil.HasTaint(node, taint) }) {
+			klog.V(3).InfoS("Skipping node carrying an excluded taint", "plugin", pluginName, "profile", d.profileName, "node", klog.KObj(node))
+			continue
+		}
+		result = append(result, node)
+	}
+	if skipped := len(nodes) - len(result); skipped > 0 {
+		klog.V(2).InfoS("Skipping excluded nodes", "plugin", pluginName, "profile", d.profileName, "skippedNodes", skipped)
+	}
+	return result
+}
+
 func (d profileImpl) RunDeschedulePlugins(ctx context.Context, nodes []*v1.Node) *frameworktypes.Status {
 	errs := []error{}
 	for _, pl := range d.deschedulePlugins {
@@ -337,8 +480,9 @@ func (d profileImpl) RunDeschedulePlugins(ctx context.Context, nodes []*v1.Node)
 		evictedBeforeDeschedule := d.podEvictor.TotalEvicted()
 		evictionRequestsBeforeDeschedule := d.podEvictor.TotalEvictionRequests()
 		strategyStart := time.Now()
-		status := pl.Deschedule(ctx, nodes)
+		status := pl.Deschedule(client.WithPlugin(ctx, d.profileName, pl.Name()), d.pluginNodes(pl.Name(), nodes))
 		metrics.DeschedulerStrategyDuration.With(map[string]string{"strategy": pl.Name(), "profile": d.profileName}).Observe(time.Since(strategyStart).Seconds())
+		metrics.PluginExecutionDuration.With(map[string]string{"plugin": pl.Name(), "profile": d.profileName, "extension_point": string(frameworktypes.DescheduleExtensionPoint)}).Observe(time.Since(strategyStart).Seconds())
 
 		if status != nil && status.Err != nil {
 			span.AddEvent("Plugin Execution Failed", trace.WithAttributes(attribute.String("err", status.Err.Error())))
@@ -366,8 +510,9 @@ func (d profileImpl) RunBalancePlugins(ctx context.Context, nodes []*v1.Node) *f
 		evictedBeforeBalance := d.podEvictor.TotalEvicted()
 		evictionRequestsBeforeBalance := d.podEvictor.TotalEvictionRequests()
 		strategyStart := time.Now()
-		status := pl.Balance(ctx, nodes)
+		status := pl.Balance(client.WithPlugin(ctx, d.profileName, pl.Name()), d.pluginNodes(pl.Name(), nodes))
 		metrics.DeschedulerStrategyDuration.With(map[string]string{"strategy": pl.Name(), "profile": d.profileName}).Observe(time.Since(strategyStart).Seconds())
+		metrics.PluginExecutionDuration.With(map[string]string{"plugin": pl.Name(), "profile": d.profileName, "extension_point": string(frameworktypes.BalanceExtensionPoint)}).Observe(time.Since(strategyStart).Seconds())
 
 		if status != nil && status.Err != nil {
 			span.AddEvent("Plugin Execution Failed", trace.WithAttributes(attribute.String("err", status.Err.Error())))
@@ -3,6 +3,8 @@ package profile
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/url"
 	"sort"
 	"testing"
 
@@ -15,8 +17,11 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	fakeclientset "k8s.io/client-go/kubernetes/fake"
 	core "k8s.io/client-go/testing"
+	"k8s.io/component-base/metrics/testutil"
 
+	"sigs.k8s.io/descheduler/metrics"
 	"sigs.k8s.io/descheduler/pkg/api"
+	"sigs.k8s.io/descheduler/pkg/descheduler/client"
 	"sigs.k8s.io/descheduler/pkg/descheduler/evictions"
 	fakeplugin "sigs.k8s.io/descheduler/pkg/framework/fake/plugin"
 	"sigs.k8s.io/descheduler/pkg/framework/pluginregistry"
@@ -677,3 +682,191 @@ func TestProfileExtensionPointOrdering(t *testing.T) {
 		t.Errorf("check for balance invocation order failed. Results are not deep equal. mismatch (-want +got):\n%s", diff)
 	}
 }
+
+func TestProfileExcludedNodes(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	linuxNode := testutils.BuildTestNode("linux", 2000, 3000, 10, func(node *v1.Node) {
+		node.Labels = map[string]string{v1.LabelOSStable: "linux"}
+	})
+	windowsNode := testutils.BuildTestNode("windows", 2000, 3000, 10, func(node *v1.Node) {
+		node.Labels = map[string]string{v1.LabelOSStable: "windows"}
+	})
+	// nodes without the os label are assumed to run linux
+	unlabeledNode := testutils.BuildTestNode("unlabeled", 2000, 3000, 10, nil)
+	gpuNode := testutils.BuildTestNode("gpu", 2000, 3000, 10, func(node *v1.Node) {
+		node.Spec.Taints = []v1.Taint{{Key: "dedicated", Value: "gpu", Effect: v1.TaintEffectNoSchedule}}
+	})
+	infraNode := testutils.BuildTestNode("infra", 2000, 3000, 10, func(node *v1.Node) {
+		node.Spec.Taints = []v1.Taint{{Key: "dedicated", Value: "infra", Effect: v1.TaintEffectNoSchedule}}
+	})
+	nodes := []*v1.Node{linuxNode, windowsNode, unlabeledNode, gpuNode, infraNode}
+
+	pluginregistry.PluginRegistry = pluginregistry.NewRegistry()
+
+	descheduleNodes := map[string][]string{}
+	balanceNodes := map[string][]string{}
+	nodeNames := func(nodes []*v1.Node) []string {
+		names := []string{}
+		for _, node := range nodes {
+			names = append(names, node.Name)
+		}
+		return names
+	}
+
+	for _, pluginName := range []string{"Excluding", "AllNodes"} {
+		fakePlugin := fakeplugin.FakePlugin{PluginName: pluginName}
+		fakePlugin.AddReactor(string(frameworktypes.DescheduleExtensionPoint), func(action fakeplugin.Action) (handled, filter bool, err error) {
+			if dAction, ok := action.(fakeplugin.DescheduleAction); ok {
+				descheduleNodes[pluginName] = nodeNames(dAction.Nodes())
+				return true, false, nil
+			}
+			return false, false, nil
+		})
+		fakePlugin.AddReactor(string(frameworktypes.BalanceExtensionPoint), func(action fakeplugin.Action) (handled, filter bool, err error) {
+			if bAction, ok := action.(fakeplugin.BalanceAction); ok {
+				balanceNodes[pluginName] = nodeNames(bAction.Nodes())
+				return true, false, nil
+			}
+			return false, false, nil
+		})
+		pluginregistry.Register(
+			pluginName,
+			fakeplugin.NewPluginFncFromFake(&fakePlugin),
+			&fakeplugin.FakePlugin{},
+			&fakeplugin.FakePluginArgs{},
+			fakeplugin.ValidateFakePluginArgs,
+			fakeplugin.SetDefaults_FakePluginArgs,
+			pluginregistry.PluginRegistry,
+		)
+	}
+
+	pluginregistry.Register(
+		defaultevictor.PluginName,
+		defaultevictor.New,
+		&defaultevictor.DefaultEvictor{},
+		&defaultevictor.DefaultEvictorArgs{},
+		defaultevictor.ValidateDefaultEvictorArgs,
+		defaultevictor.SetDefaults_DefaultEvictorArgs,
+		pluginregistry.PluginRegistry,
+	)
+
+	client := fakeclientset.NewSimpleClientset(linuxNode, windowsNode, unlabeledNode, gpuNode, infraNode)
+	handle, podEvictor, err := frameworktesting.InitFrameworkHandle(
+		ctx,
+		client,
+		nil,
+		defaultevictor.DefaultEvictorArgs{},
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("Unable to initialize a framework handle: %v", err)
+	}
+
+	prfl, err := NewProfile(
+		api.DeschedulerProfile{
+			Name: "strategy-test-profile",
+			PluginConfigs: []api.PluginConfig{
+				{
+					Name: defaultevictor.PluginName,
+					Args: &defaultevictor.DefaultEvictorArgs{},
+				},
+				{
+					Name:                     "Excluding",
+					Args:                     &fakeplugin.FakePluginArgs{},
+					ExcludedOperatingSystems: []string{"windows"},
+					ExcludedTaints:           []string{"dedicated=gpu"},
+				},
+				{
+					Name: "AllNodes",
+					Args: &fakeplugin.FakePluginArgs{},
+				},
+			},
+			Plugins: api.Plugins{
+				Deschedule: api.PluginSet{
+					Enabled: []string{"Excluding", "AllNodes"},
+				},
+				Balance: api.PluginSet{
+					Enabled: []string{"Excluding", "AllNodes"},
+				},
+				Filter: api.PluginSet{
+					Enabled: []string{defaultevictor.PluginName},
+				},
+				PreEvictionFilter: api.PluginSet{
+					Enabled: []string{defaultevictor.PluginName},
+				},
+			},
+		},
+		pluginregistry.PluginRegistry,
+		WithClientSet(client),
+		WithSharedInformerFactory(handle.SharedInformerFactoryImpl),
+		WithPodEvictor(podEvictor),
+		WithGetPodsAssignedToNodeFnc(handle.GetPodsAssignedToNodeFuncImpl),
+	)
+	if err != nil {
+		t.Fatalf("unable to create profile: %v", err)
+	}
+
+	prfl.RunDeschedulePlugins(ctx, nodes)
+	prfl.RunBalancePlugins(ctx, nodes)
+
+	expected := map[string][]string{
+		"Excluding": {"linux", "unlabeled", "infra"},
+		"AllNodes":  {"linux", "windows", "unlabeled", "gpu", "infra"},
+	}
+	if diff := cmp.Diff(expected, descheduleNodes); diff != "" {
+		t.Errorf("unexpected nodes given to deschedule plugins (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(expected, balanceNodes); diff != "" {
+		t.Errorf("unexpected nodes given to balance plugins (-want +got):\n%s", diff)
+	}
+}
+
+// fakePrometheusClient answers every query with an empty response.
+type fakePrometheusClient struct{}
+
+func (fakePrometheusClient) URL(string, map[string]string) *url.URL {
+	return &url.URL{}
+}
+
+func (fakePrometheusClient) Do(context.Context, *http.Request) (*http.Response, []byte, error) {
+	return &http.Response{StatusCode: http.StatusOK}, nil, nil
+}
+
+func TestHandleAccountsPrometheusQueries(t *testing.T) {
+	metrics.Register()
+
+	handle := &handleImpl{profileName: "test-profile", prometheusClient: fakePrometheusClient{}}
+
+	labels := map[string]string{"plugin": "FakePlugin", "profile": "test-profile", "call": client.PluginCallMetrics}
+	before, err := testutil.GetCounterMetricValue(metrics.PluginAPICalls.With(labels))
+	if err != nil {
+		t.Fatalf("unable to read counter: %v", err)
+	}
+
+	ctx := context.Background()
+	pluginCtx := client.WithPlugin(ctx, "test-profile", "FakePlugin")
+	for i := 0; i < 3; i++ {
+		if _, _, err := handle.PrometheusClient().Do(pluginCtx, &http.Request{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// queries issued outside of a plugin are not accounted.
+	if _, _, err := handle.PrometheusClient().Do(ctx, &http.Request{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	after, err := testutil.GetCounterMetricValue(metrics.PluginAPICalls.With(labels))
+	if err != nil {
+		t.Fatalf("unable to read counter: %v", err)
+	}
+	if after-before != 3 {
+		t.Errorf("expected 3 metrics calls to be accounted, got %v", after-before)
+	}
+
+	if (&handleImpl{}).PrometheusClient() != nil {
+		t.Errorf("expected nil prometheus client when none is configured")
+	}
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/descheduler/pkg/api"
+)
+
+// UsageBuilder builds the usage of a node or of a pod, e.g.:
+//
+//	BuildNodeUsage().WithCPU("2").WithMemory("4Gi").WithPods(10).Build()
+//
+// Quantities are parsed with resource.MustParse, so invalid quantities
+// panic. Every call to Build returns a new copy of the usage.
+type UsageBuilder struct {
+	usage api.ReferencedResourceList
+}
+
+// BuildNodeUsage starts building the usage of a node.
+func BuildNodeUsage() *UsageBuilder {
+	return &UsageBuilder{usage: api.ReferencedResourceList{}}
+}
+
+// BuildPodUsage starts building the usage of a pod.
+func BuildPodUsage() *UsageBuilder {
+	return &UsageBuilder{usage: api.ReferencedResourceList{}}
+}
+
+// WithCPU sets the cpu usage, e.g. "2" or "500m".
+func (b *UsageBuilder) WithCPU(quantity string) *UsageBuilder {
+	return b.WithResource(v1.ResourceCPU, quantity)
+}
+
+// WithMemory sets the memory usage, e.g. "4Gi".
+func (b *UsageBuilder) WithMemory(quantity string) *UsageBuilder {
+	return b.WithResource(v1.ResourceMemory, quantity)
+}
+
+// WithPods sets the number of pods.
+func (b *UsageBuilder) WithPods(count int64) *UsageBuilder {
+	b.usage[v1.ResourcePods] = resource.NewQuantity(count, resource.DecimalSI)
+	return b
+}
+
+// WithResource sets the usage of any resource, e.g. an extended resource.
+func (b *UsageBuilder) WithResource(name v1.ResourceName, quantity string) *UsageBuilder {
+	b.usage[name] = ptr.To(resource.MustParse(quantity))
+	return b
+}
+
+// Build returns the usage.
+func (b *UsageBuilder) Build() api.ReferencedResourceList {
+	return copyResourceList(b.usage)
+}
+
+// ThresholdsBuilder builds the thresholds, or the normalized usage, of a
+// node, e.g.:
+//
+//	BuildNodeThresholds().WithCPU(20).WithMemory(30).Build()
+type ThresholdsBuilder struct {
+	thresholds api.ResourceThresholds
+}
+
+// BuildNodeThresholds starts building thresholds.
+func BuildNodeThresholds() *ThresholdsBuilder {
+	return &ThresholdsBuilder{thresholds: api.ResourceThresholds{}}
+}
+
+// WithCPU sets the cpu percentage.
+func (b *ThresholdsBuilder) WithCPU(percentage api.Percentage) *ThresholdsBuilder {
+	return b.WithResource(v1.ResourceCPU, percentage)
+}
+
+// WithMemory sets the memory percentage.
+func (b *ThresholdsBuilder) WithMemory(percentage api.Percentage) *ThresholdsBuilder {
+	return b.WithResource(v1.ResourceMemory, percentage)
+}
+
+// WithPods sets the pods percentage.
+func (b *ThresholdsBuilder) WithPods(percentage api.Percentage) *ThresholdsBuilder {
+	return b.WithResource(v1.ResourcePods, percentage)
+}
+
+// WithResource sets the percentage of any resource.
+func (b *ThresholdsBuilder) WithResource(name v1.ResourceName, percentage api.Percentage) *ThresholdsBuilder {
+	b.thresholds[name] = percentage
+	return b
+}
+
+// Build returns the thresholds.
+func (b *ThresholdsBuilder) Build() api.ResourceThresholds {
+	result := make(api.ResourceThresholds, len(b.thresholds))
+	for name, percentage := range b.thresholds {
+		result[name] = percentage
+	}
+	return result
+}
+
+// UsageClientBuilder builds the state of a FakeUsageClient, e.g.:
+//
+//	BuildUsageClient().
+//		WithNode("n1", BuildNodeUsage().WithCPU("800m").WithPods(2), p1, p2).
+//		WithNode("n2", BuildNodeUsage().WithCPU("0").WithPods(0)).
+//		Build()
+type UsageClientBuilder struct {
+	client *FakeUsageClient
+}
+
+// BuildUsageClient starts building a usage client reporting no nodes.
+func BuildUsageClient() *UsageClientBuilder {
+	return &UsageClientBuilder{
+		client: &FakeUsageClient{
+			NodeUtilizations: map[string]api.ReferencedResourceList{},
+			NodePods:         map[string][]*v1.Pod{},
+			PodUsages:        map[types.UID]api.ReferencedResourceList{},
+			PodUsageErrors:   map[types.UID]error{},
+		},
+	}
+}
+
+// WithNode reports the usage and the pods of a node.
+func (b *UsageClientBuilder) WithNode(node string, usage *UsageBuilder, pods ...*v1.Pod) *UsageClientBuilder {
+	b.client.NodeUtilizations[node] = usage.Build()
+	b.client.NodePods[node] = pods
+	return b
+}
+
+// WithNodeUsage reports the usage of a node, keeping the pods already
+// reported for it.
+func (b *UsageClientBuilder) WithNodeUsage(node string, usage *UsageBuilder) *UsageClientBuilder {
+	b.client.NodeUtilizations[node] = usage.Build()
+	return b
+}
+
+// WithPodUsage reports the usage of a pod. Pods without a reported usage
+// report their requests.
+func (b *UsageClientBuilder) WithPodUsage(pod *v1.Pod, usage *UsageBuilder) *UsageClientBuilder {
+	b.client.PodUsages[pod.UID] = usage.Build()
+	return b
+}
+
+// WithPodUsageError fails the requests for the usage of a pod.
+func (b *UsageClientBuilder) WithPodUsageError(pod *v1.Pod, err error) *UsageClientBuilder {
+	b.client.PodUsageErrors[pod.UID] = err
+	return b
+}
+
+// WithSyncErrors fails the successive calls to Sync, see
+// FakeUsageClient.SyncErrors.
+func (b *UsageClientBuilder) WithSyncErrors(errs ...error) *UsageClientBuilder {
+	b.client.SyncErrors = append(b.client.SyncErrors, errs...)
+	return b
+}
+
+// WithSyncError fails one more call to Sync, see WithSyncErrors.
+func (b *UsageClientBuilder) WithSyncError(err error) *UsageClientBuilder {
+	return b.WithSyncErrors(err)
+}
+
+// Build returns the usage client. The builder must not be used afterwards.
+func (b *UsageClientBuilder) Build() *FakeUsageClient {
+	return b.client
+}
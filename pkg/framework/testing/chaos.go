@@ -0,0 +1,176 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"math/rand"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	core "k8s.io/client-go/testing"
+
+	"sigs.k8s.io/descheduler/pkg/descheduler/evictions"
+	frameworktypes "sigs.k8s.io/descheduler/pkg/framework/types"
+)
+
+// ChaosFailure is a kind of API server misbehavior injected by Chaos.
+type ChaosFailure string
+
+const (
+	// ChaosTooManyRequests fails the request with a 429, as a pod
+	// disruption budget or the API server rate limiting would.
+	ChaosTooManyRequests ChaosFailure = "TooManyRequests"
+	// ChaosInternalError fails the request with a 500.
+	ChaosInternalError ChaosFailure = "InternalError"
+	// ChaosTimeout fails the request with a 504, as an API server giving
+	// up on a request would.
+	ChaosTimeout ChaosFailure = "Timeout"
+)
+
+// Chaos injects random eviction failures at a configurable rate. Failures
+// are drawn from a seeded source so a failing run can be reproduced, and
+// are counted so tests can verify the accounting of the code under test.
+// It is safe for concurrent use.
+//
+// Failures can be injected either in front of an evictor, see Evictor, or
+// in front of the eviction API of a fake clientset, see EvictionReactor.
+type Chaos struct {
+	rate     float64
+	failures []ChaosFailure
+
+	mu       sync.Mutex
+	rand     *rand.Rand
+	injected map[ChaosFailure]int
+	passed   int
+}
+
+// NewChaos returns a Chaos failing the given fraction, between 0 and 1, of
+// the evictions. Every failure is picked at random among the provided
+// failures, all of them are used when none is provided.
+func NewChaos(seed int64, rate float64, failures ...ChaosFailure) *Chaos {
+	if len(failures) == 0 {
+		failures = []ChaosFailure{ChaosTooManyRequests, ChaosInternalError, ChaosTimeout}
+	}
+	return &Chaos{
+		rate:     rate,
+		failures: failures,
+		rand:     rand.New(rand.NewSource(seed)),
+		injected: map[ChaosFailure]int{},
+	}
+}
+
+// next decides the fate of an eviction, a nil error lets it through.
+func (c *Chaos) next(namespace, name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.rand.Float64() >= c.rate {
+		c.passed++
+		return nil
+	}
+
+	failure := c.failures[c.rand.Intn(len(c.failures))]
+	c.injected[failure]++
+	switch failure {
+	case ChaosTooManyRequests:
+		return apierrors.NewTooManyRequests(fmt.Sprintf("chaos: too many requests evicting %s/%s", namespace, name), 1)
+	case ChaosInternalError:
+		return apierrors.NewInternalError(fmt.Errorf("chaos: internal error evicting %s/%s", namespace, name))
+	case ChaosTimeout:
+		return apierrors.NewTimeoutError(fmt.Sprintf("chaos: timed out evicting %s/%s", namespace, name), 1)
+	default:
+		return fmt.Errorf("chaos: unknown failure %q", failure)
+	}
+}
+
+// Injected returns the number of failures injected, by kind.
+func (c *Chaos) Injected() map[ChaosFailure]int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return maps.Clone(c.injected)
+}
+
+// TotalInjected returns the number of failures injected.
+func (c *Chaos) TotalInjected() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	total := 0
+	for _, count := range c.injected {
+		total += count
+	}
+	return total
+}
+
+// Passed returns the number of evictions let through.
+func (c *Chaos) Passed() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.passed
+}
+
+// Evictor decorates the evictor so evictions fail before reaching it. The
+// evictor limits and counters only see the evictions let through.
+func (c *Chaos) Evictor(evictor frameworktypes.Evictor) frameworktypes.Evictor {
+	return &chaosEvictor{Evictor: evictor, chaos: c}
+}
+
+// EvictionReactor returns a reaction failing the eviction requests sent to
+// a fake clientset, evictions let through are handed to the next reactor.
+// It is meant to be registered with:
+//
+//	client.PrependReactor("create", "pods/eviction", chaos.EvictionReactor())
+//
+// Contrary to Evictor this exercises the error handling of the evictions
+// package.
+func (c *Chaos) EvictionReactor() core.ReactionFunc {
+	return func(action core.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		var name string
+		if create, ok := action.(core.CreateAction); ok {
+			if accessor, ok := create.GetObject().(interface{ GetName() string }); ok {
+				name = accessor.GetName()
+			}
+		}
+		if err := c.next(action.GetNamespace(), name); err != nil {
+			return true, nil, err
+		}
+		return false, nil, nil
+	}
+}
+
+// chaosEvictor is the evictor returned by Chaos.Evictor.
+type chaosEvictor struct {
+	frameworktypes.Evictor
+	chaos *Chaos
+}
+
+func (e *chaosEvictor) Evict(ctx context.Context, pod *v1.Pod, opts evictions.EvictOptions) error {
+	if err := e.chaos.next(pod.Namespace, pod.Name); err != nil {
+		return err
+	}
+	return e.Evictor.Evict(ctx, pod, opts)
+}
+
+func (e *chaosEvictor) DryRun() bool {
+	return frameworktypes.IsDryRun(e.Evictor)
+}
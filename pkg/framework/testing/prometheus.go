@@ -0,0 +1,184 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	promapi "github.com/prometheus/client_golang/api"
+	"github.com/prometheus/common/model"
+)
+
+// PrometheusResponse is the scripted answer of a FakePrometheusClient to a
+// query.
+type PrometheusResponse struct {
+	// Result is the result of the query, e.g. a model.Vector or a
+	// *model.Scalar.
+	Result model.Value
+	// Warnings are sent along with the result, as Prometheus does when a
+	// query hits a partial failure.
+	Warnings []string
+	// Latency delays the response. The request fails if its context is
+	// done before the response is sent.
+	Latency time.Duration
+	// Err fails the request as a transport error would, Result and
+	// Warnings are ignored.
+	Err error
+}
+
+// FakePrometheusClient is a Prometheus client answering queries with
+// scripted responses, e.g.:
+//
+//	client := &FakePrometheusClient{
+//		Responses: map[string]PrometheusResponse{
+//			"instance:node_cpu:rate:sum": {
+//				Result:   model.Vector{PrometheusSample("instance:node_cpu:rate:sum", "n1", 0.5)},
+//				Warnings: []string{"partial response"},
+//			},
+//		},
+//	}
+//
+// Queries without a scripted response are answered by Handler, or fail when
+// no Handler is set. Every query received is recorded. It is safe for
+// concurrent use.
+type FakePrometheusClient struct {
+	// Responses holds the responses, indexed by query.
+	Responses map[string]PrometheusResponse
+	// Handler answers the queries without a scripted response.
+	Handler func(query string) PrometheusResponse
+	// Latency delays every response, on top of the latency of the
+	// response itself.
+	Latency time.Duration
+
+	mu      sync.Mutex
+	queries []string
+}
+
+var _ promapi.Client = &FakePrometheusClient{}
+
+// prometheusPayload is the envelope of the responses of the Prometheus
+// HTTP API.
+type prometheusPayload struct {
+	Status   string                `json:"status"`
+	Data     prometheusQueryResult `json:"data"`
+	Warnings []string              `json:"warnings,omitempty"`
+}
+
+type prometheusQueryResult struct {
+	Type   model.ValueType `json:"resultType"`
+	Result model.Value     `json:"result"`
+}
+
+// PrometheusSample returns a sample of the metric for the instance.
+func PrometheusSample(metricName, instance string, value float64) *model.Sample {
+	return &model.Sample{
+		Metric: model.Metric{
+			"__name__": model.LabelValue(metricName),
+			"instance": model.LabelValue(instance),
+		},
+		Value:     model.SampleValue(value),
+		Timestamp: 1728991761711,
+	}
+}
+
+// Queries returns the queries received, in order.
+func (c *FakePrometheusClient) Queries() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.queries)
+}
+
+func (c *FakePrometheusClient) URL(ep string, args map[string]string) *url.URL {
+	return &url.URL{Path: ep}
+}
+
+func (c *FakePrometheusClient) Do(ctx context.Context, request *http.Request) (*http.Response, []byte, error) {
+	query, err := prometheusQuery(request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c.mu.Lock()
+	c.queries = append(c.queries, query)
+	response, ok := c.Responses[query]
+	handler := c.Handler
+	latency := c.Latency
+	c.mu.Unlock()
+
+	if !ok {
+		if handler == nil {
+			return nil, nil, fmt.Errorf("no response scripted for query %q", query)
+		}
+		response = handler(query)
+	}
+
+	if delay := latency + response.Latency; delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	if response.Err != nil {
+		return nil, nil, response.Err
+	}
+	if response.Result == nil {
+		response.Result = model.Vector{}
+	}
+	body, err := json.Marshal(prometheusPayload{
+		Status: "success",
+		Data: prometheusQueryResult{
+			Type:   response.Result.Type(),
+			Result: response.Result,
+		},
+		Warnings: response.Warnings,
+	})
+	return &http.Response{StatusCode: http.StatusOK}, body, err
+}
+
+// prometheusQuery returns the query of a request, sent either in the body
+// or in the url.
+func prometheusQuery(request *http.Request) (string, error) {
+	if request.Body != nil {
+		body, err := io.ReadAll(request.Body)
+		if err != nil {
+			return "", err
+		}
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return "", err
+		}
+		if values.Has("query") {
+			return values.Get("query"), nil
+		}
+	}
+	if request.URL != nil {
+		return request.URL.Query().Get("query"), nil
+	}
+	return "", nil
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"context"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/descheduler/pkg/api"
+)
+
+// FakeUsageClient implements the nodeutilization UsageClient interface with
+// scripted node utilizations, pods, pod usages and errors. It is safe for
+// concurrent use. The zero value is a client reporting no nodes and no pods.
+type FakeUsageClient struct {
+	// NodeUtilizations holds the utilization returned for every node.
+	NodeUtilizations map[string]api.ReferencedResourceList
+	// NodePods holds the pods returned for every node.
+	NodePods map[string][]*v1.Pod
+	// PodUsages holds the usage returned for every pod, indexed by pod UID.
+	PodUsages map[types.UID]api.ReferencedResourceList
+	// PodUsageErrors holds the error returned when the usage of a pod is
+	// requested, indexed by pod UID. It takes precedence over PodUsages.
+	PodUsageErrors map[types.UID]error
+	// SyncErrors are returned by the successive calls to Sync, the first
+	// call returns the first error and so on. Calls past the end of the
+	// list succeed.
+	SyncErrors []error
+
+	mu        sync.Mutex
+	syncCalls int
+	synced    [][]*v1.Node
+}
+
+// Sync records the call and returns the next scripted error.
+func (c *FakeUsageClient) Sync(ctx context.Context, nodes []*v1.Node) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	call := c.syncCalls
+	c.syncCalls++
+	c.synced = append(c.synced, nodes)
+	if call < len(c.SyncErrors) {
+		return c.SyncErrors[call]
+	}
+	return nil
+}
+
+// NodeUtilization returns a copy of the utilization scripted for the node.
+func (c *FakeUsageClient) NodeUtilization(node string) api.ReferencedResourceList {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return copyResourceList(c.NodeUtilizations[node])
+}
+
+// Pods returns the pods scripted for the node.
+func (c *FakeUsageClient) Pods(node string) []*v1.Pod {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.NodePods[node]
+}
+
+// PodUsage returns the error scripted for the pod, if any, or a copy of its
+// scripted usage. Pods without a scripted usage report their requests.
+func (c *FakeUsageClient) PodUsage(pod *v1.Pod) (api.ReferencedResourceList, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err, ok := c.PodUsageErrors[pod.UID]; ok {
+		return nil, err
+	}
+	if usage, ok := c.PodUsages[pod.UID]; ok {
+		return copyResourceList(usage), nil
+	}
+
+	usage := api.ReferencedResourceList{}
+	for _, container := range pod.Spec.Containers {
+		for name, quantity := range container.Resources.Requests {
+			if usage[name] == nil {
+				usage[name] = ptr.To(quantity.DeepCopy())
+				continue
+			}
+			usage[name].Add(quantity)
+		}
+	}
+	return usage, nil
+}
+
+// SyncCalls returns the number of times Sync was called.
+func (c *FakeUsageClient) SyncCalls() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.syncCalls
+}
+
+// SyncedNodes returns the nodes passed to every call to Sync, in order.
+func (c *FakeUsageClient) SyncedNodes() [][]*v1.Node {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.synced
+}
+
+// copyResourceList returns a deep copy of the list, the plugins modify the
+// usage they are handed while simulating evictions.
+func copyResourceList(list api.ReferencedResourceList) api.ReferencedResourceList {
+	if list == nil {
+		return nil
+	}
+	out := make(api.ReferencedResourceList, len(list))
+	for name, quantity := range list {
+		if quantity != nil {
+			out[name] = ptr.To(quantity.DeepCopy())
+		}
+	}
+	return out
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package types
+
+import (
+	"sync"
+	"time"
+)
+
+// SharedCache holds objects the plugins of all profiles share for a limited
+// time, possibly across descheduling cycles, e.g. expensive to collect node
+// usage. Every object expires after the ttl it was stored with. It is safe
+// for concurrent use.
+type SharedCache struct {
+	mu      sync.Mutex
+	entries map[string]sharedCacheEntry
+}
+
+type sharedCacheEntry struct {
+	obj     any
+	expires time.Time
+}
+
+// NewSharedCache returns an empty shared cache.
+func NewSharedCache() *SharedCache {
+	return &SharedCache{entries: map[string]sharedCacheEntry{}}
+}
+
+// Get returns the object stored under the provided key if it has not
+// expired yet.
+func (sc *SharedCache) Get(key string) (any, bool) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	entry, ok := sc.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !time.Now().Before(entry.expires) {
+		delete(sc.entries, key)
+		return nil, false
+	}
+	return entry.obj, true
+}
+
+// Set stores the object under the provided key for ttl, replacing any object
+// stored under the same key. Expired objects are dropped along the way.
+func (sc *SharedCache) Set(key string, obj any, ttl time.Duration) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	now := time.Now()
+	for k, entry := range sc.entries {
+		if !now.Before(entry.expires) {
+			delete(sc.entries, k)
+		}
+	}
+	sc.entries[key] = sharedCacheEntry{obj: obj, expires: now.Add(ttl)}
+}
+
+// Delete drops the object stored under the provided key, if any.
+func (sc *SharedCache) Delete(key string) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	delete(sc.entries, key)
+}
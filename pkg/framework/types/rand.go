@@ -0,0 +1,80 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package types
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math/rand"
+	"sync"
+)
+
+// Rand is the source of the randomized choices made while descheduling,
+// e.g. breaking ties between equivalent pods or sampling. Choices made
+// through a Rand built with the same seed are the same from one run to
+// the next. It is safe for concurrent use.
+type Rand struct {
+	seed int64
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// NewRand returns a Rand seeded with the provided seed.
+func NewRand(seed int64) *Rand {
+	return &Rand{seed: seed, rand: rand.New(rand.NewSource(seed))}
+}
+
+// Seed returns the seed the Rand was built with.
+func (r *Rand) Seed() int64 {
+	return r.seed
+}
+
+// Intn returns a number in [0, n). It panics if n <= 0.
+func (r *Rand) Intn(n int) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rand.Intn(n)
+}
+
+// Float64 returns a number in [0.0, 1.0).
+func (r *Rand) Float64() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rand.Float64()
+}
+
+// Shuffle randomizes the order of n elements, swap swaps the elements
+// with indexes i and j.
+func (r *Rand) Shuffle(n int, swap func(i, j int)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rand.Shuffle(n, swap)
+}
+
+// Derive returns a new source seeded from the seed of the Rand and the
+// provided key. The choices made through the returned source only depend
+// on the seed and the key, not on how many choices were made before nor
+// on the order sources are derived in, so concurrent callers keep making
+// reproducible choices. The returned source is not safe for concurrent
+// use.
+func (r *Rand) Derive(key string) *rand.Rand {
+	hash := fnv.New64a()
+	_ = binary.Write(hash, binary.LittleEndian, r.seed)
+	_, _ = hash.Write([]byte(key))
+	return rand.New(rand.NewSource(int64(hash.Sum64())))
+}
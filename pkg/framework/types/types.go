@@ -18,6 +18,8 @@ package types
 
 import (
 	"context"
+	"sync"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/informers"
@@ -25,6 +27,7 @@ import (
 
 	"sigs.k8s.io/descheduler/pkg/descheduler/evictions"
 	"sigs.k8s.io/descheduler/pkg/descheduler/metricscollector"
+	nodeutil "sigs.k8s.io/descheduler/pkg/descheduler/node"
 	podutil "sigs.k8s.io/descheduler/pkg/descheduler/pod"
 
 	promapi "github.com/prometheus/client_golang/api"
@@ -43,6 +46,133 @@ type Handle interface {
 	MetricsCollector() *metricscollector.MetricsCollector
 }
 
+// The interfaces below are optionally implemented by handles, they are kept
+// out of Handle so implementations living out of the tree keep building.
+// Plugins retrieve the instruments through the functions of the same name
+// followed by For, which fall back to a default when the handle does not
+// implement the interface.
+
+// DeviceAccountingHandle is implemented by handles providing the accounting
+// of the devices allocated through Dynamic Resource Allocation.
+type DeviceAccountingHandle interface {
+	// DeviceAccounting returns the accounting of the devices allocated
+	// through Dynamic Resource Allocation, it is nil unless the
+	// DynamicResourceAllocation feature gate is enabled.
+	DeviceAccounting() *nodeutil.DeviceAccounting
+}
+
+// SharedObjectsHandle is implemented by handles providing a store for
+// objects shared among the plugins of a profile.
+type SharedObjectsHandle interface {
+	// SharedObjects returns a store for objects shared among the plugins
+	// of a profile during a single descheduling cycle.
+	SharedObjects() *SharedObjects
+}
+
+// SharedCacheHandle is implemented by handles providing a store for objects
+// shared among the plugins of all profiles.
+type SharedCacheHandle interface {
+	// SharedCache returns a store for objects shared among the plugins
+	// of all profiles for a limited time, across descheduling cycles.
+	SharedCache() *SharedCache
+}
+
+// RandHandle is implemented by handles providing the source of the
+// randomized choices of the plugins.
+type RandHandle interface {
+	// Rand returns the source plugins make their randomized choices with,
+	// it is seeded with --seed when provided.
+	Rand() *Rand
+}
+
+// ProfileNameHandle is implemented by handles aware of the profile the
+// plugin belongs to.
+type ProfileNameHandle interface {
+	// ProfileName returns the name of the profile the plugin belongs to,
+	// plugins keep their state in the SharedCache under keys including it.
+	ProfileName() string
+}
+
+var (
+	defaultSharedCache = NewSharedCache()
+	defaultRand        = NewRand(time.Now().UnixNano())
+)
+
+// DeviceAccountingFor returns the device accounting of the handle, nil if
+// the handle does not provide one.
+func DeviceAccountingFor(handle Handle) *nodeutil.DeviceAccounting {
+	if h, ok := handle.(DeviceAccountingHandle); ok {
+		return h.DeviceAccounting()
+	}
+	return nil
+}
+
+// SharedObjectsFor returns the shared objects store of the handle. Handles
+// not providing one get an empty store, objects are then not shared.
+func SharedObjectsFor(handle Handle) *SharedObjects {
+	if h, ok := handle.(SharedObjectsHandle); ok {
+		return h.SharedObjects()
+	}
+	return NewSharedObjects()
+}
+
+// SharedCacheFor returns the shared cache of the handle. Handles not
+// providing one share a package wide cache.
+func SharedCacheFor(handle Handle) *SharedCache {
+	if h, ok := handle.(SharedCacheHandle); ok {
+		return h.SharedCache()
+	}
+	return defaultSharedCache
+}
+
+// RandFor returns the source of randomized choices of the handle. Handles
+// not providing one share a package wide source seeded with the time.
+func RandFor(handle Handle) *Rand {
+	if h, ok := handle.(RandHandle); ok {
+		return h.Rand()
+	}
+	return defaultRand
+}
+
+// ProfileNameFor returns the name of the profile of the handle, empty if the
+// handle is not aware of it.
+func ProfileNameFor(handle Handle) string {
+	if h, ok := handle.(ProfileNameHandle); ok {
+		return h.ProfileName()
+	}
+	return ""
+}
+
+// SharedObjects holds objects plugins of the same profile share during a
+// descheduling cycle, e.g. expensive to build clients. Objects are stored
+// under keys chosen by the plugins. It is safe for concurrent use.
+type SharedObjects struct {
+	mu      sync.Mutex
+	objects map[string]any
+}
+
+// NewSharedObjects returns an empty shared objects store.
+func NewSharedObjects() *SharedObjects {
+	return &SharedObjects{objects: map[string]any{}}
+}
+
+// GetOrCreate returns the object stored under the provided key. If no object
+// is stored yet it is created through create and stored, errors returned by
+// create are returned as is and nothing is stored.
+func (so *SharedObjects) GetOrCreate(key string, create func() (any, error)) (any, error) {
+	so.mu.Lock()
+	defer so.mu.Unlock()
+	if obj, ok := so.objects[key]; ok {
+		return obj, nil
+	}
+	obj, err := create()
+	if err != nil {
+		return nil, err
+	}
+	so.objects[key] = obj
+	return obj, nil
+}
+
 // Evictor defines an interface for filtering and evicting pods
 // while abstracting away the specific pod evictor/evictor filter.
 type Evictor interface {
@@ -54,6 +184,22 @@ type Evictor interface {
 	Evict(context.Context, *v1.Pod, evictions.EvictOptions) error
 }
 
+// DryRunEvictor is optionally implemented by evictors able to tell whether
+// evictions are only simulated.
+type DryRunEvictor interface {
+	// DryRun returns true if evictions are only simulated
+	DryRun() bool
+}
+
+// IsDryRun returns true if the evictor only simulates evictions. Evictors
+// not implementing DryRunEvictor are assumed to evict pods.
+func IsDryRun(evictor Evictor) bool {
+	if e, ok := evictor.(DryRunEvictor); ok {
+		return e.DryRun()
+	}
+	return false
+}
+
 // Status describes result of an extension point invocation
 type Status struct {
 	Err error
@@ -2,11 +2,16 @@ package utils
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
 	policy "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/labels"
 
+	autoscalingv2 "k8s.io/client-go/listers/autoscaling/v2"
 	policyv1 "k8s.io/client-go/listers/policy/v1"
+	"k8s.io/client-go/tools/cache"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -29,31 +34,33 @@ func GetResourceRequest(pod *v1.Pod, resource v1.ResourceName) int64 {
 	return requestQuantity.Value()
 }
 
-// GetResourceRequestQuantity finds and returns the request quantity for a specific resource.
-func GetResourceRequestQuantity(pod *v1.Pod, resourceName v1.ResourceName) resource.Quantity {
-	requestQuantity := resource.Quantity{}
+// IsHugePageResourceName returns true if the resource name is a huge page
+// resource, e.g. hugepages-2Mi.
+func IsHugePageResourceName(name v1.ResourceName) bool {
+	return strings.HasPrefix(string(name), v1.ResourceHugePagesPrefix)
+}
 
-	switch resourceName {
-	case v1.ResourceCPU:
-		requestQuantity = resource.Quantity{Format: resource.DecimalSI}
-	case v1.ResourceMemory, v1.ResourceStorage, v1.ResourceEphemeralStorage:
-		requestQuantity = resource.Quantity{Format: resource.BinarySI}
+// ResourceQuantityFormat returns the format quantities of the resource are
+// expressed in. Memory, storage and huge pages are measured in bytes and use
+// the binary format, all the other resources use the decimal one.
+func ResourceQuantityFormat(name v1.ResourceName) resource.Format {
+	switch {
+	case name == v1.ResourceMemory, name == v1.ResourceStorage, name == v1.ResourceEphemeralStorage, IsHugePageResourceName(name):
+		return resource.BinarySI
 	default:
-		requestQuantity = resource.Quantity{Format: resource.DecimalSI}
+		return resource.DecimalSI
 	}
+}
 
-	for _, container := range pod.Spec.Containers {
-		if rQuantity, ok := container.Resources.Requests[resourceName]; ok {
-			requestQuantity.Add(rQuantity)
-		}
-	}
+// GetResourceRequestQuantity finds and returns the request quantity for a specific resource.
+func GetResourceRequestQuantity(pod *v1.Pod, resourceName v1.ResourceName) resource.Quantity {
+	requestQuantity := resource.Quantity{Format: ResourceQuantityFormat(resourceName)}
 
-	for _, container := range pod.Spec.InitContainers {
-		if rQuantity, ok := container.Resources.Requests[resourceName]; ok {
-			if requestQuantity.Cmp(rQuantity) < 0 {
-				requestQuantity = rQuantity.DeepCopy()
-			}
-		}
+	requests := podContainersResources(pod, func(container v1.Container) v1.ResourceList {
+		return container.Resources.Requests
+	})
+	if rQuantity, ok := requests[resourceName]; ok {
+		requestQuantity.Add(rQuantity)
 	}
 
 	// We assume pod overhead feature gate is enabled.
@@ -152,6 +159,174 @@ func IsPodCoveredByPDB(pod *v1.Pod, lister policyv1.PodDisruptionBudgetLister) (
 	return len(pdbList) > 0, nil
 }
 
+const (
+	// ReplicaSetControllerUIDIndex is the name of the ReplicaSet informer
+	// index mapping the UID of a controller to the ReplicaSets it owns.
+	ReplicaSetControllerUIDIndex = "replicaset.controllerUID"
+	// ReplicaSetControllerNameIndex is the name of the ReplicaSet informer
+	// index mapping the namespace, kind and name of a controller to the
+	// ReplicaSets it owns.
+	ReplicaSetControllerNameIndex = "replicaset.controllerName"
+
+	// flaggerPrimarySuffix is appended by Flagger to the name of the
+	// Deployment it targets to name the primary Deployment it creates.
+	flaggerPrimarySuffix = "-primary"
+)
+
+// ReplicaSetIndexers returns the indexers IsPodOwnerRollingOut expects the
+// ReplicaSet informer to have.
+func ReplicaSetIndexers() cache.Indexers {
+	return cache.Indexers{
+		ReplicaSetControllerUIDIndex: func(obj interface{}) ([]string, error) {
+			rs, ok := obj.(*appsv1.ReplicaSet)
+			if !ok {
+				return []string{}, nil
+			}
+			owner := metav1.GetControllerOf(rs)
+			if owner == nil {
+				return []string{}, nil
+			}
+			return []string{string(owner.UID)}, nil
+		},
+		ReplicaSetControllerNameIndex: func(obj interface{}) ([]string, error) {
+			rs, ok := obj.(*appsv1.ReplicaSet)
+			if !ok {
+				return []string{}, nil
+			}
+			owner := metav1.GetControllerOf(rs)
+			if owner == nil {
+				return []string{}, nil
+			}
+			return []string{controllerNameKey(rs.Namespace, owner.Kind, owner.Name)}, nil
+		},
+	}
+}
+
+func controllerNameKey(namespace, kind, name string) string {
+	return namespace + "/" + kind + "/" + name
+}
+
+// IsPodOwnerRollingOut checks if the pod belongs to a ReplicaSet whose owner,
+// a Deployment or an Argo Rollout for example, is in the middle of a rollout
+// or a canary. That is the case when more than one of the ReplicaSets of the
+// owner have replicas. Flagger canaries are covered as well: Flagger keeps the
+// canary Deployment scaled to zero next to a "-primary" Deployment and only
+// scales it up during an analysis, so the ReplicaSets of both Deployments of
+// the pair are considered. The indexer is the ReplicaSet informer indexer, it
+// must have the indexers returned by ReplicaSetIndexers.
+func IsPodOwnerRollingOut(pod *v1.Pod, indexer cache.Indexer) (bool, error) {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil || owner.Kind != "ReplicaSet" {
+		return false, nil
+	}
+
+	obj, exists, err := indexer.GetByKey(pod.Namespace + "/" + owner.Name)
+	if err != nil || !exists {
+		return false, err
+	}
+	rs, ok := obj.(*appsv1.ReplicaSet)
+	if !ok || rs.UID != owner.UID {
+		return false, nil
+	}
+	rolloutOwner := metav1.GetControllerOf(rs)
+	if rolloutOwner == nil {
+		return false, nil
+	}
+
+	objs, err := indexer.ByIndex(ReplicaSetControllerUIDIndex, string(rolloutOwner.UID))
+	if err != nil {
+		return false, err
+	}
+
+	if rolloutOwner.Kind == "Deployment" {
+		counterpart := rolloutOwner.Name + flaggerPrimarySuffix
+		if name, found := strings.CutSuffix(rolloutOwner.Name, flaggerPrimarySuffix); found {
+			counterpart = name
+		}
+		pair, err := indexer.ByIndex(ReplicaSetControllerNameIndex, controllerNameKey(pod.Namespace, "Deployment", counterpart))
+		if err != nil {
+			return false, err
+		}
+		objs = append(objs, pair...)
+	}
+
+	active := 0
+	for _, obj := range objs {
+		rs, ok := obj.(*appsv1.ReplicaSet)
+		if !ok {
+			continue
+		}
+		if rs.Status.Replicas > 0 || (rs.Spec.Replicas != nil && *rs.Spec.Replicas > 0) {
+			active++
+		}
+	}
+	return active > 1, nil
+}
+
+// IsPodOwnerScaling checks if the workload owning the pod is being scaled by
+// a HorizontalPodAutoscaler. A workload is considered to be scaling when its
+// autoscaler desired and current replicas differ or when the autoscaler has
+// scaled it within the cooldown. KEDA ScaledObjects are covered as well since
+// KEDA drives the scaling through an autoscaler it manages.
+func IsPodOwnerScaling(pod *v1.Pod, lister autoscalingv2.HorizontalPodAutoscalerLister, cooldown time.Duration) (bool, error) {
+	targets := GetPodWorkloads(pod)
+	if len(targets) == 0 {
+		return false, nil
+	}
+
+	list, err := lister.HorizontalPodAutoscalers(pod.Namespace).List(labels.Everything())
+	if err != nil {
+		return false, err
+	}
+
+	for _, hpa := range list {
+		if name, ok := targets[hpa.Spec.ScaleTargetRef.Kind]; !ok || name != hpa.Spec.ScaleTargetRef.Name {
+			continue
+		}
+		if hpa.Status.DesiredReplicas != hpa.Status.CurrentReplicas {
+			return true, nil
+		}
+		if hpa.Status.LastScaleTime != nil && time.Since(hpa.Status.LastScaleTime.Time) < cooldown {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// GetPodWorkloads returns the names of the workloads controlling the pod,
+// indexed by kind. Besides the pod controller, the Deployment is returned
+// for pods owned by a ReplicaSet, the ReplicaSet is named after the
+// Deployment and the pod template hash. Workloads are what autoscalers
+// target.
+func GetPodWorkloads(pod *v1.Pod) map[string]string {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return nil
+	}
+
+	workloads := map[string]string{owner.Kind: owner.Name}
+	if hash, ok := pod.Labels["pod-template-hash"]; ok && owner.Kind == "ReplicaSet" {
+		if name, found := strings.CutSuffix(owner.Name, "-"+hash); found {
+			workloads["Deployment"] = name
+		}
+	}
+	return workloads
+}
+
+// IsPodResizing checks if an in-place resize of the pod is pending or in
+// progress.
+func IsPodResizing(pod *v1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type != v1.PodResizePending && condition.Type != v1.PodResizeInProgress {
+			continue
+		}
+		if condition.Status == v1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
 // GetPodSource returns the source of the pod based on the annotation.
 func GetPodSource(pod *v1.Pod) (string, error) {
 	if pod.Annotations != nil {
@@ -167,16 +342,12 @@ func GetPodSource(pod *v1.Pod) (string, error) {
 // total container resource requests and to the total container limits which have a
 // non-zero quantity.
 func PodRequestsAndLimits(pod *v1.Pod) (reqs, limits v1.ResourceList) {
-	reqs, limits = v1.ResourceList{}, v1.ResourceList{}
-	for _, container := range pod.Spec.Containers {
-		addResourceList(reqs, container.Resources.Requests)
-		addResourceList(limits, container.Resources.Limits)
-	}
-	// init containers define the minimum of any resource
-	for _, container := range pod.Spec.InitContainers {
-		maxResourceList(reqs, container.Resources.Requests)
-		maxResourceList(limits, container.Resources.Limits)
-	}
+	reqs = podContainersResources(pod, func(container v1.Container) v1.ResourceList {
+		return container.Resources.Requests
+	})
+	limits = podContainersResources(pod, func(container v1.Container) v1.ResourceList {
+		return container.Resources.Limits
+	})
 
 	// We assume pod overhead feature gate is enabled.
 	// We can't import the scheduler settings so we will inherit the default.
@@ -194,6 +365,41 @@ func PodRequestsAndLimits(pod *v1.Pod) (reqs, limits v1.ResourceList) {
 	return
 }
 
+// podContainersResources returns the resources of the containers of the pod
+// summed up the way the scheduler does. Restartable init containers, a.k.a.
+// sidecars, run for the whole lifetime of the pod so they add up to the
+// regular containers. Other init containers run one at a time, next to the
+// sidecars started before them, and define the minimum of any resource.
+func podContainersResources(pod *v1.Pod, resources func(v1.Container) v1.ResourceList) v1.ResourceList {
+	list := v1.ResourceList{}
+	for _, container := range pod.Spec.Containers {
+		addResourceList(list, resources(container))
+	}
+
+	sidecars, initContainers := v1.ResourceList{}, v1.ResourceList{}
+	for _, container := range pod.Spec.InitContainers {
+		if IsRestartableInitContainer(container) {
+			addResourceList(sidecars, resources(container))
+			maxResourceList(initContainers, sidecars)
+			continue
+		}
+		running := v1.ResourceList{}
+		addResourceList(running, resources(container))
+		addResourceList(running, sidecars)
+		maxResourceList(initContainers, running)
+	}
+
+	addResourceList(list, sidecars)
+	maxResourceList(list, initContainers)
+	return list
+}
+
+// IsRestartableInitContainer returns true if the init container is a
+// sidecar, i.e. it keeps running next to the regular containers.
+func IsRestartableInitContainer(container v1.Container) bool {
+	return container.RestartPolicy != nil && *container.RestartPolicy == v1.ContainerRestartPolicyAlways
+}
+
 // addResourceList adds the resources in newList to list
 func addResourceList(list, newList v1.ResourceList) {
 	for name, quantity := range newList {
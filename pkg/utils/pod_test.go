@@ -0,0 +1,149 @@
+package utils
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	utilptr "k8s.io/utils/ptr"
+)
+
+func buildContainer(cpu, memory string, restartPolicy *v1.ContainerRestartPolicy) v1.Container {
+	return v1.Container{
+		RestartPolicy: restartPolicy,
+		Resources: v1.ResourceRequirements{
+			Requests: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse(cpu),
+				v1.ResourceMemory: resource.MustParse(memory),
+			},
+			Limits: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse(cpu),
+				v1.ResourceMemory: resource.MustParse(memory),
+			},
+		},
+	}
+}
+
+func TestPodRequestsAndLimits(t *testing.T) {
+	sidecar := utilptr.To(v1.ContainerRestartPolicyAlways)
+
+	tests := []struct {
+		name           string
+		containers     []v1.Container
+		initContainers []v1.Container
+		overhead       v1.ResourceList
+		expected       v1.ResourceList
+	}{
+		{
+			name: "regular containers are summed up",
+			containers: []v1.Container{
+				buildContainer("100m", "100Mi", nil),
+				buildContainer("200m", "50Mi", nil),
+			},
+			expected: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("300m"),
+				v1.ResourceMemory: resource.MustParse("150Mi"),
+			},
+		},
+		{
+			name: "init containers define the minimum",
+			containers: []v1.Container{
+				buildContainer("100m", "100Mi", nil),
+			},
+			initContainers: []v1.Container{
+				buildContainer("500m", "10Mi", nil),
+			},
+			expected: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("500m"),
+				v1.ResourceMemory: resource.MustParse("100Mi"),
+			},
+		},
+		{
+			name: "sidecars add up to the regular containers",
+			containers: []v1.Container{
+				buildContainer("100m", "100Mi", nil),
+			},
+			initContainers: []v1.Container{
+				buildContainer("50m", "20Mi", sidecar),
+				buildContainer("50m", "20Mi", sidecar),
+			},
+			expected: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("200m"),
+				v1.ResourceMemory: resource.MustParse("140Mi"),
+			},
+		},
+		{
+			name: "init containers run next to the sidecars started before them",
+			containers: []v1.Container{
+				buildContainer("100m", "100Mi", nil),
+			},
+			initContainers: []v1.Container{
+				buildContainer("50m", "20Mi", sidecar),
+				buildContainer("500m", "10Mi", nil),
+				buildContainer("50m", "20Mi", sidecar),
+			},
+			expected: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("550m"),
+				v1.ResourceMemory: resource.MustParse("140Mi"),
+			},
+		},
+		{
+			name: "overhead is added",
+			containers: []v1.Container{
+				buildContainer("100m", "100Mi", nil),
+			},
+			initContainers: []v1.Container{
+				buildContainer("50m", "20Mi", sidecar),
+			},
+			overhead: v1.ResourceList{
+				v1.ResourceCPU: resource.MustParse("10m"),
+			},
+			expected: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("160m"),
+				v1.ResourceMemory: resource.MustParse("120Mi"),
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pod := &v1.Pod{
+				Spec: v1.PodSpec{
+					Containers:     tc.containers,
+					InitContainers: tc.initContainers,
+					Overhead:       tc.overhead,
+				},
+			}
+
+			reqs, limits := PodRequestsAndLimits(pod)
+			if !equality.Semantic.DeepEqual(reqs, tc.expected) {
+				t.Errorf("expected requests %v, got %v", tc.expected, reqs)
+			}
+			if !equality.Semantic.DeepEqual(limits, tc.expected) {
+				t.Errorf("expected limits %v, got %v", tc.expected, limits)
+			}
+			for name, expected := range tc.expected {
+				if quantity := GetResourceRequestQuantity(pod, name); quantity.Cmp(expected) != 0 {
+					t.Errorf("expected %v request %v, got %v", name, expected.String(), quantity.String())
+				}
+			}
+		})
+	}
+}
+
+func TestResourceQuantityFormat(t *testing.T) {
+	for name, expected := range map[v1.ResourceName]resource.Format{
+		v1.ResourceCPU:              resource.DecimalSI,
+		v1.ResourceMemory:           resource.BinarySI,
+		v1.ResourcePods:             resource.DecimalSI,
+		v1.ResourceEphemeralStorage: resource.BinarySI,
+		"hugepages-2Mi":             resource.BinarySI,
+		"hugepages-1Gi":             resource.BinarySI,
+		"example.com/gpu":           resource.DecimalSI,
+	} {
+		if format := ResourceQuantityFormat(name); format != expected {
+			t.Errorf("expected %v format to be %v, got %v", name, expected, format)
+		}
+	}
+}
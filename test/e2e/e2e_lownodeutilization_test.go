@@ -18,6 +18,7 @@ package e2e
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"strings"
 	"testing"
@@ -37,8 +38,13 @@ import (
 	"sigs.k8s.io/descheduler/pkg/api"
 	apiv1alpha2 "sigs.k8s.io/descheduler/pkg/api/v1alpha2"
 	"sigs.k8s.io/descheduler/pkg/descheduler/client"
+	"sigs.k8s.io/descheduler/pkg/descheduler/evictions"
+	eutils "sigs.k8s.io/descheduler/pkg/descheduler/evictions/utils"
+	podutil "sigs.k8s.io/descheduler/pkg/descheduler/pod"
 	"sigs.k8s.io/descheduler/pkg/framework/plugins/defaultevictor"
 	"sigs.k8s.io/descheduler/pkg/framework/plugins/nodeutilization"
+	frameworktesting "sigs.k8s.io/descheduler/pkg/framework/testing"
+	frameworktypes "sigs.k8s.io/descheduler/pkg/framework/types"
 )
 
 func lowNodeUtilizationPolicy(lowNodeUtilizationArgs *nodeutilization.LowNodeUtilizationArgs, evictorArgs *defaultevictor.DefaultEvictorArgs, metricsCollectorEnabled bool) *apiv1alpha2.DeschedulerPolicy {
@@ -302,3 +308,141 @@ func TestLowNodeUtilizationKubernetesMetrics(t *testing.T) {
 		})
 	}
 }
+
+func TestLowNodeUtilizationEphemeralStorage(t *testing.T) {
+	testLowNodeUtilizationResource(t, v1.ResourceEphemeralStorage)
+}
+
+func TestLowNodeUtilizationHugepages(t *testing.T) {
+	testLowNodeUtilizationResource(t, v1.ResourceName(v1.ResourceHugePagesPrefix+"2Mi"))
+}
+
+// testLowNodeUtilizationResource binds pods each requesting 10% of the given
+// resource to a single worker node and expects the LowNodeUtilization plugin
+// to evict some of them when the resource is the only one thresholded.
+func testLowNodeUtilizationResource(t *testing.T, resourceName v1.ResourceName) {
+	ctx := context.Background()
+
+	clientSet, _, _, getPodsAssignedToNode := initializeClient(ctx, t)
+
+	nodeList, err := clientSet.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Errorf("Error listing node with %v", err)
+	}
+
+	_, workerNodes := splitNodesAndWorkerNodes(nodeList.Items)
+	if len(workerNodes) < 2 {
+		t.Skipf("At least 2 worker nodes are required, got %v", len(workerNodes))
+	}
+
+	allocatable := workerNodes[0].Status.Allocatable[resourceName]
+	if allocatable.IsZero() {
+		t.Skipf("Node %v has no %v allocatable", workerNodes[0].Name, resourceName)
+	}
+	tenth := resource.NewQuantity(int64(float64(allocatable.Value())*0.1), allocatable.Format)
+
+	testNamespace := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "e2e-" + strings.ToLower(t.Name())}}
+	t.Logf("Creating testing namespace %q", testNamespace.Name)
+	if _, err := clientSet.CoreV1().Namespaces().Create(ctx, testNamespace, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Unable to create ns %v", testNamespace.Name)
+	}
+	defer clientSet.CoreV1().Namespaces().Delete(ctx, testNamespace.Name, metav1.DeleteOptions{})
+
+	// huge pages can't be overcommitted, their limits must match their
+	// requests. they also require cpu or memory to be requested.
+	resources := v1.ResourceRequirements{
+		Requests: v1.ResourceList{
+			v1.ResourceCPU: resource.MustParse("10m"),
+			resourceName:   *tenth,
+		},
+		Limits: v1.ResourceList{
+			resourceName: *tenth,
+		},
+	}
+
+	podLabels := map[string]string{"test": "node-utilization-resource", "name": "test-rc-node-utilization-resource"}
+	t.Logf("Creating pods each requesting %v of %v, all bound to node %v", tenth.String(), resourceName, workerNodes[0].Name)
+	for i := 0; i < 4; i++ {
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("lnu-resource-pod-%v", i),
+				Namespace: testNamespace.Name,
+				Labels:    podLabels,
+			},
+			Spec: makePodSpec("", nil),
+		}
+		pod.Spec.NodeName = workerNodes[0].Name
+		pod.Spec.Containers[0].Resources = resources
+
+		if _, err := clientSet.CoreV1().Pods(pod.Namespace).Create(ctx, pod, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("Error creating pod %v: %v", pod.Name, err)
+		}
+	}
+
+	t.Log("Creating RC with 4 replicas owning the created pods")
+	rc := RcByNameContainer("test-rc-node-utilization-resource", testNamespace.Name, int32(4), map[string]string{"test": "node-utilization-resource"}, nil, "")
+	if _, err := clientSet.CoreV1().ReplicationControllers(rc.Namespace).Create(ctx, rc, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating RC %v", err)
+	}
+	defer deleteRC(ctx, t, clientSet, rc)
+	waitForRCPodsRunning(ctx, t, clientSet, rc)
+
+	evictionPolicyGroupVersion, err := eutils.SupportEviction(clientSet)
+	if err != nil || len(evictionPolicyGroupVersion) == 0 {
+		t.Fatalf("Error detecting eviction policy group: %v", err)
+	}
+
+	handle, _, err := frameworktesting.InitFrameworkHandle(
+		ctx,
+		clientSet,
+		evictions.NewOptions().
+			WithPolicyGroupVersion(evictionPolicyGroupVersion),
+		defaultevictor.DefaultEvictorArgs{
+			EvictLocalStoragePods: true,
+		},
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("Unable to initialize a framework handle: %v", err)
+	}
+
+	podFilter, err := podutil.NewOptions().WithFilter(handle.EvictorFilterImpl.Filter).BuildFilterFunc()
+	if err != nil {
+		t.Errorf("Error initializing pod filter function, %v", err)
+	}
+
+	podsOnMostUtilizedNode, err := podutil.ListPodsOnANode(workerNodes[0].Name, getPodsAssignedToNode, podFilter)
+	if err != nil {
+		t.Errorf("Error listing pods on a node %v", err)
+	}
+	podsBefore := len(podsOnMostUtilizedNode)
+
+	// the node holding the pods is at 40% of the resource at least, all
+	// the other worker nodes are expected to be close to 0%.
+	t.Log("Running LowNodeUtilization plugin")
+	plugin, err := nodeutilization.NewLowNodeUtilization(&nodeutilization.LowNodeUtilizationArgs{
+		Thresholds: api.ResourceThresholds{
+			resourceName: 5,
+		},
+		TargetThresholds: api.ResourceThresholds{
+			resourceName: 20,
+		},
+	}, handle)
+	if err != nil {
+		t.Fatalf("Unable to initialize the plugin: %v", err)
+	}
+	plugin.(frameworktypes.BalancePlugin).Balance(ctx, workerNodes)
+
+	waitForTerminatingPodsToDisappear(ctx, t, clientSet, rc.Namespace)
+
+	podsOnMostUtilizedNode, err = podutil.ListPodsOnANode(workerNodes[0].Name, getPodsAssignedToNode, podFilter)
+	if err != nil {
+		t.Errorf("Error listing pods on a node %v", err)
+	}
+	podsAfter := len(podsOnMostUtilizedNode)
+
+	if podsAfter >= podsBefore {
+		t.Fatalf("No pod has been evicted from %v node", workerNodes[0].Name)
+	}
+	t.Logf("Number of pods on node %v changed from %v to %v", workerNodes[0].Name, podsBefore, podsAfter)
+}
@@ -0,0 +1,441 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	clientset "k8s.io/client-go/kubernetes"
+	utilptr "k8s.io/utils/ptr"
+
+	"sigs.k8s.io/descheduler/pkg/api"
+	"sigs.k8s.io/descheduler/pkg/descheduler/evictions"
+	eutils "sigs.k8s.io/descheduler/pkg/descheduler/evictions/utils"
+	"sigs.k8s.io/descheduler/pkg/framework/plugins/defaultevictor"
+	"sigs.k8s.io/descheduler/pkg/framework/plugins/nodeutilization"
+	frameworktesting "sigs.k8s.io/descheduler/pkg/framework/testing"
+	frameworktypes "sigs.k8s.io/descheduler/pkg/framework/types"
+)
+
+// usageShape distributes the pods of a synthetic load over a number of
+// nodes. it returns the number of pods to place on every node, the first
+// node being the most loaded one.
+type usageShape func(pods, nodes int) []int
+
+// singleNodeShape places all the pods on the first node.
+func singleNodeShape(pods, nodes int) []int {
+	weights := make([]float64, nodes)
+	weights[0] = 1
+	return distributePods(pods, weights)
+}
+
+// linearShape places pods proportionally to nodes, nodes-1, ..., 1.
+func linearShape(pods, nodes int) []int {
+	weights := make([]float64, nodes)
+	for i := range weights {
+		weights[i] = float64(nodes - i)
+	}
+	return distributePods(pods, weights)
+}
+
+// exponentialShape halves the number of pods from one node to the next.
+func exponentialShape(pods, nodes int) []int {
+	weights := make([]float64, nodes)
+	for i := range weights {
+		weights[i] = math.Pow(2, float64(nodes-i-1))
+	}
+	return distributePods(pods, weights)
+}
+
+// distributePods splits the pods proportionally to the weights, the pods
+// left by rounding down go to the nodes with the largest remainders.
+func distributePods(pods int, weights []float64) []int {
+	var total float64
+	for _, weight := range weights {
+		total += weight
+	}
+
+	counts := make([]int, len(weights))
+	remainders := make([]float64, len(weights))
+	assigned := 0
+	for i, weight := range weights {
+		share := float64(pods) * weight / total
+		counts[i] = int(share)
+		remainders[i] = share - float64(counts[i])
+		assigned += counts[i]
+	}
+
+	order := make([]int, len(weights))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return remainders[order[i]] > remainders[order[j]]
+	})
+	for i := 0; assigned < pods; i++ {
+		counts[order[i%len(order)]]++
+		assigned++
+	}
+	return counts
+}
+
+// syntheticLoad describes a skewed workload created before running a
+// balancing policy.
+type syntheticLoad struct {
+	// nodes is the number of worker nodes the load is spread over.
+	nodes int
+	// pods is the number of pods of the load.
+	pods int
+	// podCPUFraction is the fraction of the node allocatable cpu requested
+	// by every pod.
+	podCPUFraction float64
+	// shape distributes the pods over the nodes.
+	shape usageShape
+}
+
+// withOverrides returns the load with the node and pod counts overridden
+// by the E2E_SYNTHETIC_LOAD_NODES and E2E_SYNTHETIC_LOAD_PODS env variables,
+// to run the same shapes against larger clusters.
+func (l syntheticLoad) withOverrides(t *testing.T) syntheticLoad {
+	for env, value := range map[string]*int{"E2E_SYNTHETIC_LOAD_NODES": &l.nodes, "E2E_SYNTHETIC_LOAD_PODS": &l.pods} {
+		raw := os.Getenv(env)
+		if raw == "" {
+			continue
+		}
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			t.Fatalf("Invalid %v value %q", env, raw)
+		}
+		*value = parsed
+	}
+	return l
+}
+
+// convergenceMetrics describes the state the cluster converged to after
+// running a balancing policy against a synthetic load.
+type convergenceMetrics struct {
+	// cycles is the number of descheduling cycles run, the last one being
+	// the first cycle with no eviction unless the policy did not converge.
+	cycles int
+	// converged is true if the last cycle evicted no pod.
+	converged bool
+	// evicted is the number of pods evicted over all the cycles.
+	evicted uint
+	// utilization is the requested cpu fraction of every node.
+	utilization map[string]float64
+	// spread is the difference between the most and the least utilized
+	// nodes.
+	spread float64
+}
+
+// convergenceExpectations are the bounds the convergence metrics of a run
+// are asserted against. the eviction bounds are fractions of the load pods
+// so the expectations hold when the load is scaled up.
+type convergenceExpectations struct {
+	maxCycles  int
+	minEvicted float64
+	maxEvicted float64
+	maxSpread  float64
+}
+
+// assert fails the test if the metrics are not within the expectations.
+func (e convergenceExpectations) assert(t *testing.T, load syntheticLoad, metrics convergenceMetrics) {
+	t.Logf("Converged: %v, cycles: %v, evicted: %v, spread: %.3f, utilization: %v", metrics.converged, metrics.cycles, metrics.evicted, metrics.spread, metrics.utilization)
+	if !metrics.converged {
+		t.Errorf("Expected the policy to converge within %v cycles", e.maxCycles)
+	}
+	minEvictions := uint(math.Ceil(e.minEvicted * float64(load.pods)))
+	maxEvictions := uint(math.Floor(e.maxEvicted * float64(load.pods)))
+	if metrics.evicted < minEvictions || metrics.evicted > maxEvictions {
+		t.Errorf("Expected between %v and %v evictions, got %v", minEvictions, maxEvictions, metrics.evicted)
+	}
+	if metrics.spread > e.maxSpread {
+		t.Errorf("Expected the utilization spread to be at most %.3f, got %.3f", e.maxSpread, metrics.spread)
+	}
+}
+
+// runSyntheticLoad creates the synthetic load, runs the balance plugin built
+// by newPlugin until a cycle evicts no pod or maxCycles cycles are run, and
+// returns the metrics of the state the cluster converged to. pods are
+// created bound to their nodes and then adopted by a replication controller,
+// evicted pods are recreated and placed by the scheduler.
+func runSyntheticLoad(
+	ctx context.Context,
+	t *testing.T,
+	clientSet clientset.Interface,
+	load syntheticLoad,
+	maxCycles int,
+	newPlugin func(handle frameworktypes.Handle) (frameworktypes.BalancePlugin, error),
+) convergenceMetrics {
+	nodeList, err := clientSet.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("Error listing node with %v", err)
+	}
+	_, workerNodes := splitNodesAndWorkerNodes(nodeList.Items)
+	if len(workerNodes) < load.nodes {
+		t.Skipf("Synthetic load requires %v worker nodes, the cluster has %v", load.nodes, len(workerNodes))
+	}
+	nodes := workerNodes[:load.nodes]
+
+	testNamespace := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "e2e-synthetic-load-" + utilrand.String(5)}}
+	t.Logf("Creating testing namespace %q", testNamespace.Name)
+	if _, err := clientSet.CoreV1().Namespaces().Create(ctx, testNamespace, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Unable to create ns %v: %v", testNamespace.Name, err)
+	}
+	defer clientSet.CoreV1().Namespaces().Delete(ctx, testNamespace.Name, metav1.DeleteOptions{})
+
+	// start from nodes with the same utilization so the skew comes from
+	// the synthetic load only
+	cleanUp, err := createBalancedPodForNodes(t, ctx, clientSet, testNamespace.Name, nodes, 0)
+	if err != nil {
+		t.Fatalf("Unable to create load balancing pods: %v", err)
+	}
+	defer cleanUp()
+
+	nodeCPU := nodes[0].Status.Allocatable[v1.ResourceCPU]
+	podCPU := resource.NewMilliQuantity(int64(float64(nodeCPU.MilliValue())*load.podCPUFraction), resource.DecimalSI)
+	podLabels := map[string]string{"test": "synthetic-load", "name": "synthetic-load"}
+
+	counts := load.shape(load.pods, len(nodes))
+	t.Logf("Creating %v pods requesting %v cpu each, distributed as %v", load.pods, podCPU, counts)
+	idx := 0
+	for i, node := range nodes {
+		for j := 0; j < counts[i]; j++ {
+			pod := syntheticLoadPod(fmt.Sprintf("synthetic-load-%v", idx), testNamespace.Name, podLabels, node.Name, *podCPU)
+			if _, err := clientSet.CoreV1().Pods(pod.Namespace).Create(ctx, pod, metav1.CreateOptions{}); err != nil {
+				t.Fatalf("Error creating synthetic load pod: %v", err)
+			}
+			idx++
+		}
+	}
+
+	rc := RcByNameContainer("synthetic-load", testNamespace.Name, int32(load.pods), podLabels, nil, "")
+	rc.Spec.Template.Spec.Containers[0].Resources = v1.ResourceRequirements{
+		Limits:   v1.ResourceList{v1.ResourceCPU: *podCPU},
+		Requests: v1.ResourceList{v1.ResourceCPU: *podCPU},
+	}
+	if _, err := clientSet.CoreV1().ReplicationControllers(rc.Namespace).Create(ctx, rc, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating RC %v", err)
+	}
+	defer deleteRC(ctx, t, clientSet, rc)
+	waitForRCPodsRunning(ctx, t, clientSet, rc)
+
+	evictionPolicyGroupVersion, err := eutils.SupportEviction(clientSet)
+	if err != nil || len(evictionPolicyGroupVersion) == 0 {
+		t.Fatalf("Error detecting eviction policy group: %v", err)
+	}
+
+	metrics := convergenceMetrics{}
+	for metrics.cycles < maxCycles {
+		metrics.cycles++
+		evicted := runSyntheticLoadCycle(ctx, t, clientSet, evictionPolicyGroupVersion, nodes, newPlugin)
+		t.Logf("Cycle %v evicted %v pods", metrics.cycles, evicted)
+		metrics.evicted += evicted
+		if evicted == 0 {
+			metrics.converged = true
+			break
+		}
+		waitForTerminatingPodsToDisappear(ctx, t, clientSet, rc.Namespace)
+		waitForRCPodsRunning(ctx, t, clientSet, rc)
+	}
+
+	metrics.utilization = map[string]float64{}
+	minUtilization, maxUtilization := math.MaxFloat64, 0.0
+	for _, node := range nodes {
+		cpuFraction, _, _, _ := computeCPUMemFraction(t, clientSet, node, &v1.ResourceRequirements{})
+		metrics.utilization[node.Name] = cpuFraction
+		minUtilization = math.Min(minUtilization, cpuFraction)
+		maxUtilization = math.Max(maxUtilization, cpuFraction)
+	}
+	metrics.spread = maxUtilization - minUtilization
+	return metrics
+}
+
+// runSyntheticLoadCycle runs a single descheduling cycle with a fresh
+// framework handle, as the descheduler does, and returns the number of pods
+// evicted.
+func runSyntheticLoadCycle(
+	ctx context.Context,
+	t *testing.T,
+	clientSet clientset.Interface,
+	evictionPolicyGroupVersion string,
+	nodes []*v1.Node,
+	newPlugin func(handle frameworktypes.Handle) (frameworktypes.BalancePlugin, error),
+) uint {
+	cycleCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	handle, podEvictor, err := frameworktesting.InitFrameworkHandle(
+		cycleCtx,
+		clientSet,
+		evictions.NewOptions().
+			WithPolicyGroupVersion(evictionPolicyGroupVersion),
+		defaultevictor.DefaultEvictorArgs{
+			NodeFit: true,
+		},
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("Unable to initialize a framework handle: %v", err)
+	}
+
+	plugin, err := newPlugin(handle)
+	if err != nil {
+		t.Fatalf("Unable to initialize the plugin: %v", err)
+	}
+	if status := plugin.Balance(cycleCtx, nodes); status != nil && status.Err != nil {
+		t.Fatalf("Balance failed: %v", status.Err)
+	}
+	return podEvictor.TotalEvicted()
+}
+
+// syntheticLoadPod returns a pause pod requesting the provided cpu, bound
+// to the node through a required node affinity.
+func syntheticLoadPod(name, namespace string, labels map[string]string, nodeName string, cpu resource.Quantity) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: v1.PodSpec{
+			SecurityContext: &v1.PodSecurityContext{
+				RunAsNonRoot: utilptr.To(true),
+				RunAsUser:    utilptr.To[int64](1000),
+				RunAsGroup:   utilptr.To[int64](1000),
+				SeccompProfile: &v1.SeccompProfile{
+					Type: v1.SeccompProfileTypeRuntimeDefault,
+				},
+			},
+			TerminationGracePeriodSeconds: utilptr.To[int64](0),
+			Containers: []v1.Container{{
+				Name:            "pause",
+				ImagePullPolicy: "Never",
+				Image:           "registry.k8s.io/pause",
+				Ports:           []v1.ContainerPort{{ContainerPort: 80}},
+				Resources: v1.ResourceRequirements{
+					Limits:   v1.ResourceList{v1.ResourceCPU: cpu},
+					Requests: v1.ResourceList{v1.ResourceCPU: cpu},
+				},
+			}},
+			Affinity: &v1.Affinity{
+				NodeAffinity: &v1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{
+						NodeSelectorTerms: []v1.NodeSelectorTerm{
+							{
+								MatchFields: []v1.NodeSelectorRequirement{
+									{Key: "metadata.name", Operator: v1.NodeSelectorOpIn, Values: []string{nodeName}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestSyntheticLoadLowNodeUtilization(t *testing.T) {
+	ctx := context.Background()
+
+	clientSet, _, _, _ := initializeClient(ctx, t)
+
+	lowNodeUtilization := func(handle frameworktypes.Handle) (frameworktypes.BalancePlugin, error) {
+		plugin, err := nodeutilization.NewLowNodeUtilization(&nodeutilization.LowNodeUtilizationArgs{
+			UseDeviationThresholds: true,
+			Thresholds: api.ResourceThresholds{
+				v1.ResourceCPU: 10,
+			},
+			TargetThresholds: api.ResourceThresholds{
+				v1.ResourceCPU: 10,
+			},
+		}, handle)
+		if err != nil {
+			return nil, err
+		}
+		return plugin.(frameworktypes.BalancePlugin), nil
+	}
+
+	tests := []struct {
+		name         string
+		load         syntheticLoad
+		expectations convergenceExpectations
+	}{
+		{
+			name: "all pods on a single node",
+			load: syntheticLoad{
+				nodes:          2,
+				pods:           8,
+				podCPUFraction: 0.05,
+				shape:          singleNodeShape,
+			},
+			expectations: convergenceExpectations{
+				maxCycles:  5,
+				minEvicted: 0.25,
+				maxEvicted: 0.75,
+				maxSpread:  0.25,
+			},
+		},
+		{
+			name: "linearly skewed pods",
+			load: syntheticLoad{
+				nodes:          2,
+				pods:           12,
+				podCPUFraction: 0.04,
+				shape:          linearShape,
+			},
+			expectations: convergenceExpectations{
+				maxCycles:  5,
+				minEvicted: 0.05,
+				maxEvicted: 0.35,
+				maxSpread:  0.25,
+			},
+		},
+		{
+			name: "exponentially skewed pods",
+			load: syntheticLoad{
+				nodes:          2,
+				pods:           12,
+				podCPUFraction: 0.04,
+				shape:          exponentialShape,
+			},
+			expectations: convergenceExpectations{
+				maxCycles:  5,
+				minEvicted: 0.05,
+				maxEvicted: 0.35,
+				maxSpread:  0.25,
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			load := tc.load.withOverrides(t)
+			metrics := runSyntheticLoad(ctx, t, clientSet, load, tc.expectations.maxCycles, lowNodeUtilization)
+			tc.expectations.assert(t, load, metrics)
+		})
+	}
+}
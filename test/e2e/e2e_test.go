@@ -73,7 +73,8 @@ func isClientRateLimiterError(err error) bool {
 func initFeatureGates() featuregate.FeatureGate {
 	featureGates := featuregate.NewFeatureGate()
 	featureGates.Add(map[featuregate.Feature]featuregate.FeatureSpec{
-		features.EvictionsInBackground: {Default: false, PreRelease: featuregate.Alpha},
+		features.EvictionsInBackground:     {Default: false, PreRelease: featuregate.Alpha},
+		features.DynamicResourceAllocation: {Default: false, PreRelease: featuregate.Alpha},
 	})
 	return featureGates
 }
@@ -27,6 +27,8 @@ SKIP_KUBECTL_INSTALL=${SKIP_KUBECTL_INSTALL:-}
 SKIP_KIND_INSTALL=${SKIP_KIND_INSTALL:-}
 SKIP_KUBEVIRT_INSTALL=${SKIP_KUBEVIRT_INSTALL:-}
 KUBEVIRT_VERSION=${KUBEVIRT_VERSION:-v1.3.0-rc.1}
+KIND_CONFIG=${KIND_CONFIG:-./hack/kind_config.yaml}
+E2E_TEST_RUN=${E2E_TEST_RUN:-}
 
 # Build a descheduler image
 IMAGE_TAG=v$(date +%Y%m%d)-$(git describe --tags)
@@ -51,7 +53,7 @@ if [ -n "$KIND_E2E" ]; then
 
     # If we did not set SKIP_INSTALL
     if [ -z "$SKIP_INSTALL" ]; then
-        ${KIND_SUDO} kind create cluster --image kindest/node:${K8S_VERSION} --config=./hack/kind_config.yaml
+        ${KIND_SUDO} kind create cluster --image kindest/node:${K8S_VERSION} --config=${KIND_CONFIG}
     fi
     ${CONTAINER_ENGINE} pull registry.k8s.io/pause
     if [ "${CONTAINER_ENGINE}" == "podman" ]; then
@@ -105,4 +107,4 @@ kubectl patch -n kube-system deployment metrics-server --type=json \
   -p '[{"op":"add","path":"/spec/template/spec/containers/0/args/-","value":"--kubelet-insecure-tls"}]'
 
 PRJ_PREFIX="sigs.k8s.io/descheduler"
-go test ${PRJ_PREFIX}/test/e2e/ -v -timeout 0
+go test ${PRJ_PREFIX}/test/e2e/ -v -timeout 0 ${E2E_TEST_RUN:+-run "${E2E_TEST_RUN}"}